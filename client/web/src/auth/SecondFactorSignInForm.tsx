@@ -0,0 +1,280 @@
+import React, { useCallback, useState } from 'react'
+
+import { asError, logger } from '@sourcegraph/common'
+import { Alert, Button, Code, Form, Input, Link, LoadingSpinner, Text } from '@sourcegraph/wildcard'
+
+import type { SourcegraphContext } from '../jscontext'
+
+/**
+ * The response of the sign-in endpoint when the password was correct but a
+ * second factor is still needed to finish signing in.
+ */
+export interface SecondFactorStatus {
+    secondFactorRequired: boolean
+    /** Whether the user must enroll a second factor before they can sign in. */
+    enrollmentRequired: boolean
+    methods: ('totp' | 'webauthn' | 'recovery')[]
+}
+
+interface TOTPEnrollment {
+    secret: string
+    keyURI: string
+}
+
+interface Props {
+    status: SecondFactorStatus
+    context: Pick<SourcegraphContext, 'xhrHeaders'>
+    onAuthError: (error: Error | null) => void
+    onSignedIn: () => void
+}
+
+const base64URLToBuffer = (value: string): ArrayBuffer => {
+    const base64 = value.replace(/-/g, '+').replace(/_/g, '/')
+    const binary = atob(base64.padEnd(base64.length + ((4 - (base64.length % 4)) % 4), '='))
+    return Uint8Array.from(binary, character => character.charCodeAt(0)).buffer
+}
+
+const bufferToBase64URL = (buffer: ArrayBuffer): string =>
+    btoa(String.fromCharCode(...new Uint8Array(buffer)))
+        .replace(/\+/g, '-')
+        .replace(/\//g, '_')
+        .replace(/=+$/, '')
+
+/**
+ * The second step of signing in with a username and password, for users who
+ * use (or are required to use) two-factor authentication.
+ */
+export const SecondFactorSignInForm: React.FunctionComponent<React.PropsWithChildren<Props>> = ({
+    status,
+    context,
+    onAuthError,
+    onSignedIn,
+}) => {
+    const [code, setCode] = useState('')
+    const [loading, setLoading] = useState(false)
+    const [enrollment, setEnrollment] = useState<TOTPEnrollment | null>(null)
+    const [recoveryCodes, setRecoveryCodes] = useState<string[] | null>(null)
+
+    const post = useCallback(
+        async <T,>(path: string, body: object = {}): Promise<T> => {
+            const response = await fetch(`/-/second-factor/${path}`, {
+                credentials: 'same-origin',
+                method: 'POST',
+                headers: {
+                    ...context.xhrHeaders,
+                    Accept: 'application/json',
+                    'Content-Type': 'application/json',
+                },
+                body: JSON.stringify(body),
+            })
+            if (response.status === 401) {
+                throw new Error('The code was incorrect')
+            }
+            if (response.status === 422) {
+                throw new Error('The account has been locked out')
+            }
+            if (!response.ok) {
+                throw new Error((await response.text()) || 'Unknown Error')
+            }
+            return response.json() as Promise<T>
+        },
+        [context]
+    )
+
+    const run = useCallback(
+        (action: () => Promise<void>): void => {
+            if (loading) {
+                return
+            }
+            setLoading(true)
+            onAuthError(null)
+            action()
+                .catch(error => {
+                    logger.error('Auth error:', error)
+                    onAuthError(asError(error))
+                })
+                .finally(() => setLoading(false))
+        },
+        [loading, onAuthError]
+    )
+
+    const onCodeChange = useCallback((event: React.ChangeEvent<HTMLInputElement>): void => {
+        setCode(event.target.value)
+    }, [])
+
+    const onVerifyCode = useCallback(
+        (event: React.FormEvent<HTMLFormElement>): void => {
+            event.preventDefault()
+            run(async () => {
+                // TOTP codes are 6 digits, anything else can only be a recovery code.
+                const method = /^\s*\d{6}\s*$/.test(code) ? 'totp' : 'recovery'
+                await post('verify', { method, code })
+                onSignedIn()
+            })
+        },
+        [code, run, post, onSignedIn]
+    )
+
+    const onUseSecurityKey = useCallback((): void => {
+        run(async () => {
+            const options = await post<{
+                challenge: string
+                rpId: string
+                timeout: number
+                allowCredentials: { type: 'public-key'; id: string }[]
+                userVerification: UserVerificationRequirement
+            }>('webauthn/challenge')
+            const credential = (await navigator.credentials.get({
+                publicKey: {
+                    ...options,
+                    challenge: base64URLToBuffer(options.challenge),
+                    allowCredentials: options.allowCredentials.map(allowed => ({
+                        ...allowed,
+                        id: base64URLToBuffer(allowed.id),
+                    })),
+                },
+            })) as PublicKeyCredential | null
+            if (!credential) {
+                throw new Error('No security key was used')
+            }
+            const response = credential.response as AuthenticatorAssertionResponse
+            await post('verify', {
+                method: 'webauthn',
+                credential: {
+                    id: credential.id,
+                    rawId: bufferToBase64URL(credential.rawId),
+                    type: credential.type,
+                    response: {
+                        clientDataJSON: bufferToBase64URL(response.clientDataJSON),
+                        authenticatorData: bufferToBase64URL(response.authenticatorData),
+                        signature: bufferToBase64URL(response.signature),
+                    },
+                },
+            })
+            onSignedIn()
+        })
+    }, [run, post, onSignedIn])
+
+    const onStartEnrollment = useCallback((): void => {
+        run(async () => setEnrollment(await post<TOTPEnrollment>('totp/enroll')))
+    }, [run, post])
+
+    const onConfirmEnrollment = useCallback(
+        (event: React.FormEvent<HTMLFormElement>): void => {
+            event.preventDefault()
+            run(async () => {
+                const result = await post<{ recoveryCodes?: string[] }>('totp/confirm', { code })
+                if (result.recoveryCodes) {
+                    setRecoveryCodes(result.recoveryCodes)
+                } else {
+                    onSignedIn()
+                }
+            })
+        },
+        [code, run, post, onSignedIn]
+    )
+
+    if (recoveryCodes) {
+        return (
+            <div>
+                <Text alignment="left">
+                    Two-factor authentication is now enabled. Store these recovery codes in a safe place. Each of them
+                    can be used once to sign in if you lose access to your authenticator app. They will not be shown
+                    again.
+                </Text>
+                <ul className="list-unstyled text-left">
+                    {recoveryCodes.map(recoveryCode => (
+                        <li key={recoveryCode}>
+                            <Code>{recoveryCode}</Code>
+                        </li>
+                    ))}
+                </ul>
+                <Button display="block" variant="primary" onClick={onSignedIn}>
+                    Continue
+                </Button>
+            </div>
+        )
+    }
+
+    if (status.enrollmentRequired) {
+        if (!enrollment) {
+            return (
+                <div>
+                    <Alert variant="info">
+                        Your account must use two-factor authentication. Set up an authenticator app to continue.
+                    </Alert>
+                    <Button display="block" variant="primary" disabled={loading} onClick={onStartEnrollment}>
+                        {loading ? <LoadingSpinner /> : 'Set up authenticator app'}
+                    </Button>
+                </div>
+            )
+        }
+        return (
+            <Form onSubmit={onConfirmEnrollment}>
+                <Text alignment="left">
+                    Add this secret to your authenticator app, or <Link to={enrollment.keyURI}>open it</Link> on a
+                    device with an authenticator app installed:
+                </Text>
+                <Text alignment="left">
+                    <Code>{enrollment.secret}</Code>
+                </Text>
+                <Input
+                    id="second-factor-code"
+                    label={<Text alignment="left">Code from your authenticator app</Text>}
+                    onChange={onCodeChange}
+                    required={true}
+                    value={code}
+                    disabled={loading}
+                    autoFocus={true}
+                    autoComplete="one-time-code"
+                    inputMode="numeric"
+                    className="form-group"
+                />
+                <Button display="block" type="submit" disabled={loading} variant="primary">
+                    {loading ? <LoadingSpinner /> : 'Enable two-factor authentication'}
+                </Button>
+            </Form>
+        )
+    }
+
+    return (
+        <div>
+            {status.methods.includes('webauthn') && (
+                <Button
+                    display="block"
+                    className="mb-3"
+                    variant="primary"
+                    disabled={loading}
+                    onClick={onUseSecurityKey}
+                >
+                    Use security key
+                </Button>
+            )}
+            {(status.methods.includes('totp') || status.methods.includes('recovery')) && (
+                <Form onSubmit={onVerifyCode}>
+                    <Input
+                        id="second-factor-code"
+                        label={
+                            <Text alignment="left">
+                                {status.methods.includes('totp')
+                                    ? 'Code from your authenticator app, or a recovery code'
+                                    : 'Recovery code'}
+                            </Text>
+                        }
+                        onChange={onCodeChange}
+                        required={true}
+                        value={code}
+                        disabled={loading}
+                        autoFocus={!status.methods.includes('webauthn')}
+                        autoCapitalize="off"
+                        autoComplete="one-time-code"
+                        className="form-group"
+                    />
+                    <Button display="block" type="submit" disabled={loading} variant="secondary">
+                        {loading ? <LoadingSpinner /> : 'Verify'}
+                    </Button>
+                </Form>
+            )}
+        </div>
+    )
+}
@@ -9,6 +9,7 @@ import { Label, Button, LoadingSpinner, Link, Text, Input, Form } from '@sourceg
 import type { SourcegraphContext } from '../jscontext'
 import { eventLogger } from '../tracking/eventLogger'
 
+import { SecondFactorSignInForm, type SecondFactorStatus } from './SecondFactorSignInForm'
 import { getReturnTo, PasswordInput } from './SignInSignUpCommon'
 
 interface Props {
@@ -32,6 +33,16 @@ export const UsernamePasswordSignInForm: React.FunctionComponent<React.PropsWith
     const [usernameOrEmail, setUsernameOrEmail] = useState('')
     const [password, setPassword] = useState('')
     const [loading, setLoading] = useState(false)
+    const [secondFactorStatus, setSecondFactorStatus] = useState<SecondFactorStatus | null>(null)
+
+    const onSignedIn = useCallback((): void => {
+        if (new URLSearchParams(location.search).get('close') === 'true') {
+            window.close()
+        } else {
+            const returnTo = getReturnTo(location)
+            window.location.replace(returnTo)
+        }
+    }, [location])
 
     const onUsernameOrEmailFieldChange = useCallback((event: React.ChangeEvent<HTMLInputElement>): void => {
         setUsernameOrEmail(event.target.value)
@@ -63,13 +74,15 @@ export const UsernamePasswordSignInForm: React.FunctionComponent<React.PropsWith
                     password,
                 }),
             })
-                .then(response => {
+                .then(async response => {
                     if (response.status === 200) {
-                        if (new URLSearchParams(location.search).get('close') === 'true') {
-                            window.close()
+                        const body = await response.text()
+                        const status = body ? (JSON.parse(body) as SecondFactorStatus) : null
+                        if (status?.secondFactorRequired) {
+                            setLoading(false)
+                            setSecondFactorStatus(status)
                         } else {
-                            const returnTo = getReturnTo(location)
-                            window.location.replace(returnTo)
+                            onSignedIn()
                         }
                     } else if (response.status === 401) {
                         throw new Error('User or password was incorrect')
@@ -85,9 +98,22 @@ export const UsernamePasswordSignInForm: React.FunctionComponent<React.PropsWith
                     onAuthError(asError(error))
                 })
         },
-        [usernameOrEmail, loading, location, password, onAuthError, context]
+        [usernameOrEmail, loading, password, onAuthError, onSignedIn, context]
     )
 
+    if (secondFactorStatus) {
+        return (
+            <div className={className}>
+                <SecondFactorSignInForm
+                    status={secondFactorStatus}
+                    context={context}
+                    onAuthError={onAuthError}
+                    onSignedIn={onSignedIn}
+                />
+            </div>
+        )
+    }
+
     return (
         <>
             <Form onSubmit={handleSubmit} className={className}>
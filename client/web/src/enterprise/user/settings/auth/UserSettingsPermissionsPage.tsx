@@ -225,6 +225,10 @@ const PermissionReasonBadgeProps: { [reason: string]: BadgeProps } = {
         variant: 'success',
         tooltip: 'The permission was granted through explicit permissions API.',
     },
+    'Group Mapping': {
+        variant: 'success',
+        tooltip: 'The permission was granted to a group of the user asserted by the authentication provider.',
+    },
 }
 
 interface ScheduleUserPermissionsSyncActionContainerProps {
@@ -259,6 +263,7 @@ const permsSourceMap = {
     USER_SYNC: 'user-centric permission sync',
     REPO_SYNC: 'repo-centric permission sync',
     API: 'explicit permissions API',
+    GROUP_SYNC: 'group mappings',
 }
 
 interface PermsSourceProps {
@@ -272,6 +277,8 @@ const PermsSource: React.FunctionComponent<PermsSourceProps> = ({ source }) => {
     let href = '/help/admin/permissions/syncing#permission-syncing'
     if (source === PermissionSource.API) {
         href = '/help/admin/permissions/api'
+    } else if (source === PermissionSource.GROUP_SYNC) {
+        href = '/help/admin/permissions/group_mappings'
     }
 
     return (
@@ -68,6 +68,7 @@ var (
 		router.RequestAccess:      {},
 		router.SiteInit:           {},
 		router.SignIn:             {},
+		router.SecondFactor:       {},
 		router.SignOut:            {},
 		router.UnlockAccount:      {},
 		router.ResetPasswordInit:  {},
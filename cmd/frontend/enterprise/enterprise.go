@@ -40,6 +40,11 @@ type Services struct {
 	// Handler for exporting code insights data.
 	CodeInsightsDataExportHandler http.Handler
 
+	// Handlers for exporting software bills of materials generated from code intel data, for
+	// a single repository and for many repositories at once.
+	CodeIntelSBOMHandler  http.Handler
+	CodeIntelSBOMsHandler http.Handler
+
 	// Handler for exporting search jobs data.
 	SearchJobsDataExportHandler http.Handler
 	SearchJobsLogsHandler       http.Handler
@@ -117,6 +122,8 @@ func DefaultServices() Services {
 		NewGitHubAppSetupHandler:        func() http.Handler { return makeNotFoundHandler("Sourcegraph GitHub App setup") },
 		NewComputeStreamHandler:         func() http.Handler { return makeNotFoundHandler("compute streaming endpoint") },
 		CodeInsightsDataExportHandler:   makeNotFoundHandler("code insights data export handler"),
+		CodeIntelSBOMHandler:            makeNotFoundHandler("code intel SBOM handler"),
+		CodeIntelSBOMsHandler:           makeNotFoundHandler("code intel bulk SBOM handler"),
 		NewDotcomLicenseCheckHandler:    func() http.Handler { return makeNotFoundHandler("dotcom license check handler") },
 		NewChatCompletionsStreamHandler: func() http.Handler { return makeNotFoundHandler("chat completions streaming endpoint") },
 		NewCodeCompletionsHandler:       func() http.Handler { return makeNotFoundHandler("code completions streaming endpoint") },
@@ -0,0 +1,168 @@
+package graphqlbackend
+
+import (
+	"context"
+	"strings"
+
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/language/parser"
+
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/authz"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// rootFieldAccessTokenScopes maps the root fields of each operation type to the fine-grained
+// access token scope that permits selecting them. Root fields that are not listed here may only
+// be selected by actors that are not restricted to fine-grained scopes.
+var rootFieldAccessTokenScopes = map[string]map[string]string{
+	ast.OperationTypeQuery: {
+		"search": authz.ScopeSearchRead,
+
+		"repository":         authz.ScopeRepoRead,
+		"repositoryRedirect": authz.ScopeRepoRead,
+		"repositories":       authz.ScopeRepoRead,
+
+		"batchChanges":                  authz.ScopeBatchesWrite,
+		"batchChange":                   authz.ScopeBatchesWrite,
+		"globalChangesetsStats":         authz.ScopeBatchesWrite,
+		"batchChangesCodeHosts":         authz.ScopeBatchesWrite,
+		"availableBulkOperations":       authz.ScopeBatchesWrite,
+		"batchSpecs":                    authz.ScopeBatchesWrite,
+		"checkBatchChangesCredential":   authz.ScopeBatchesWrite,
+		"resolveWorkspacesForBatchSpec": authz.ScopeBatchesWrite,
+		"maxUnlicensedChangesets":       authz.ScopeBatchesWrite,
+	},
+	ast.OperationTypeMutation: {
+		"createChangesetSpec":                authz.ScopeBatchesWrite,
+		"createChangesetSpecs":               authz.ScopeBatchesWrite,
+		"syncChangeset":                      authz.ScopeBatchesWrite,
+		"reenqueueChangeset":                 authz.ScopeBatchesWrite,
+		"createBatchChange":                  authz.ScopeBatchesWrite,
+		"createBatchSpec":                    authz.ScopeBatchesWrite,
+		"createEmptyBatchChange":             authz.ScopeBatchesWrite,
+		"upsertEmptyBatchChange":             authz.ScopeBatchesWrite,
+		"createBatchSpecFromRaw":             authz.ScopeBatchesWrite,
+		"replaceBatchSpecInput":              authz.ScopeBatchesWrite,
+		"upsertBatchSpecInput":               authz.ScopeBatchesWrite,
+		"deleteBatchSpec":                    authz.ScopeBatchesWrite,
+		"executeBatchSpec":                   authz.ScopeBatchesWrite,
+		"applyBatchChange":                   authz.ScopeBatchesWrite,
+		"closeBatchChange":                   authz.ScopeBatchesWrite,
+		"moveBatchChange":                    authz.ScopeBatchesWrite,
+		"deleteBatchChange":                  authz.ScopeBatchesWrite,
+		"detachChangesets":                   authz.ScopeBatchesWrite,
+		"createChangesetComments":            authz.ScopeBatchesWrite,
+		"reenqueueChangesets":                authz.ScopeBatchesWrite,
+		"mergeChangesets":                    authz.ScopeBatchesWrite,
+		"closeChangesets":                    authz.ScopeBatchesWrite,
+		"publishChangesets":                  authz.ScopeBatchesWrite,
+		"cancelBatchSpecExecution":           authz.ScopeBatchesWrite,
+		"cancelBatchSpecWorkspaceExecution":  authz.ScopeBatchesWrite,
+		"retryBatchSpecWorkspaceExecution":   authz.ScopeBatchesWrite,
+		"retryBatchSpecExecution":            authz.ScopeBatchesWrite,
+		"enqueueBatchSpecWorkspaceExecution": authz.ScopeBatchesWrite,
+		"toggleBatchSpecAutoApply":           authz.ScopeBatchesWrite,
+	},
+}
+
+// CheckAccessTokenScopes returns an error if the actor in the given context is restricted to a set
+// of fine-grained access token scopes that does not permit every root field selected by the given
+// GraphQL request.
+//
+// 🚨 SECURITY: The request is parsed with a different GraphQL parser than the one that executes
+// it, so any request that this parser rejects, or whose operation cannot be determined, is
+// rejected rather than executed unchecked.
+func CheckAccessTokenScopes(ctx context.Context, query, operationName string) error {
+	a := actor.FromContext(ctx)
+	if !a.IsScopeRestricted() {
+		return nil
+	}
+
+	doc, err := parser.Parse(parser.ParseParams{Source: query})
+	if err != nil {
+		return errors.Wrap(err, "the GraphQL request of a scoped access token could not be parsed")
+	}
+
+	fragments := map[string]*ast.FragmentDefinition{}
+	for _, def := range doc.Definitions {
+		if fragment, ok := def.(*ast.FragmentDefinition); ok && fragment.Name != nil {
+			fragments[fragment.Name.Value] = fragment
+		}
+	}
+
+	checked := 0
+	for _, def := range doc.Definitions {
+		operation, ok := def.(*ast.OperationDefinition)
+		if !ok {
+			continue
+		}
+		if operationName != "" && (operation.Name == nil || operation.Name.Value != operationName) {
+			continue
+		}
+
+		checked++
+		for _, fieldName := range rootFieldNames(operation.SelectionSet, fragments, map[string]struct{}{}) {
+			if err := checkRootFieldAccessTokenScope(a, operation.Operation, fieldName); err != nil {
+				return err
+			}
+		}
+	}
+
+	if checked == 0 {
+		if operationName != "" {
+			return errors.Errorf("no operation with name %q", operationName)
+		}
+		return errors.New("no operations in query document")
+	}
+	return nil
+}
+
+func checkRootFieldAccessTokenScope(a *actor.Actor, operationType, fieldName string) error {
+	if strings.HasPrefix(fieldName, "__") {
+		// Introspection fields are always permitted.
+		return nil
+	}
+
+	if scope, ok := rootFieldAccessTokenScopes[operationType][fieldName]; ok && a.HasAccessTokenScope(scope) {
+		return nil
+	}
+
+	return errors.Errorf("the scopes of the access token used to authenticate this request do not permit the %s field %q", operationType, fieldName)
+}
+
+// rootFieldNames returns the names of the fields selected by the given selection set, including
+// those selected via inline fragments and fragment spreads.
+func rootFieldNames(selectionSet *ast.SelectionSet, fragments map[string]*ast.FragmentDefinition, visited map[string]struct{}) []string {
+	if selectionSet == nil {
+		return nil
+	}
+
+	var names []string
+	for _, selection := range selectionSet.Selections {
+		switch s := selection.(type) {
+		case *ast.Field:
+			if s.Name != nil {
+				names = append(names, s.Name.Value)
+			}
+
+		case *ast.InlineFragment:
+			names = append(names, rootFieldNames(s.SelectionSet, fragments, visited)...)
+
+		case *ast.FragmentSpread:
+			if s.Name == nil {
+				continue
+			}
+			if _, ok := visited[s.Name.Value]; ok {
+				continue
+			}
+			visited[s.Name.Value] = struct{}{}
+
+			if fragment, ok := fragments[s.Name.Value]; ok {
+				names = append(names, rootFieldNames(fragment.SelectionSet, fragments, visited)...)
+			}
+		}
+	}
+
+	return names
+}
@@ -0,0 +1,110 @@
+package graphqlbackend
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/authz"
+)
+
+func TestCheckAccessTokenScopes(t *testing.T) {
+	restricted := func(scopes ...string) context.Context {
+		return actor.WithActor(context.Background(), &actor.Actor{UID: 1, AccessTokenScopes: scopes})
+	}
+
+	for _, tc := range []struct {
+		name          string
+		ctx           context.Context
+		query         string
+		operationName string
+		wantErr       bool
+	}{
+		{
+			name:  "unrestricted actor",
+			ctx:   actor.WithActor(context.Background(), &actor.Actor{UID: 1}),
+			query: `mutation { deleteUser(user: "VXNlcjox") { alwaysNil } }`,
+		},
+		{
+			name:  "permitted query",
+			ctx:   restricted(authz.ScopeSearchRead),
+			query: `query { search(query: "foo") { results { matchCount } } }`,
+		},
+		{
+			name:    "unpermitted query",
+			ctx:     restricted(authz.ScopeSearchRead),
+			query:   `query { currentUser { username } }`,
+			wantErr: true,
+		},
+		{
+			name:    "unpermitted query with permitted query",
+			ctx:     restricted(authz.ScopeSearchRead),
+			query:   `query { search(query: "foo") { results { matchCount } } repository(name: "foo") { id } }`,
+			wantErr: true,
+		},
+		{
+			name:  "introspection",
+			ctx:   restricted(authz.ScopeRepoRead),
+			query: `query { __typename __schema { types { name } } }`,
+		},
+		{
+			name:  "permitted mutation",
+			ctx:   restricted(authz.ScopeBatchesWrite),
+			query: `mutation { applyBatchChange(batchSpec: "abc") { id } }`,
+		},
+		{
+			name:    "query field selected as mutation",
+			ctx:     restricted(authz.ScopeRepoRead),
+			query:   `mutation { repository { id } }`,
+			wantErr: true,
+		},
+		{
+			name:    "unpermitted field in fragment spread",
+			ctx:     restricted(authz.ScopeRepoRead),
+			query:   `query { ...F } fragment F on Query { repository(name: "foo") { id } currentUser { username } }`,
+			wantErr: true,
+		},
+		{
+			name:    "unpermitted field in inline fragment",
+			ctx:     restricted(authz.ScopeRepoRead),
+			query:   `query { ... on Query { site { id } } }`,
+			wantErr: true,
+		},
+		{
+			name:          "unselected operation",
+			ctx:           restricted(authz.ScopeRepoRead),
+			query:         `query A { repository(name: "foo") { id } } query B { currentUser { username } }`,
+			operationName: "A",
+		},
+		{
+			name:          "unknown operation",
+			ctx:           restricted(authz.ScopeRepoRead),
+			query:         `query A { repository(name: "foo") { id } }`,
+			operationName: "B",
+			wantErr:       true,
+		},
+		{
+			name:    "no operations",
+			ctx:     restricted(authz.ScopeRepoRead),
+			query:   `fragment F on Query { currentUser { username } }`,
+			wantErr: true,
+		},
+		{
+			name:    "unparseable query",
+			ctx:     restricted(authz.ScopeRepoRead),
+			query:   `query { repository(name: "foo") { id } currentUser { username }`,
+			wantErr: true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			err := CheckAccessTokenScopes(tc.ctx, tc.query, tc.operationName)
+			if tc.wantErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"sort"
 	"sync"
+	"time"
 
 	"github.com/graph-gophers/graphql-go"
 
@@ -17,13 +18,15 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/authz"
 	"github.com/sourcegraph/sourcegraph/internal/conf"
 	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/gqlutil"
 	"github.com/sourcegraph/sourcegraph/lib/errors"
 )
 
 type createAccessTokenInput struct {
-	User   graphql.ID
-	Scopes []string
-	Note   string
+	User      graphql.ID
+	Scopes    []string
+	Note      string
+	ExpiresAt *gqlutil.DateTime
 }
 
 func (r *schemaResolver) CreateAccessToken(ctx context.Context, args *createAccessTokenInput) (*createAccessTokenResult, error) {
@@ -60,20 +63,23 @@ func (r *schemaResolver) CreateAccessToken(ctx context.Context, args *createAcce
 	}
 
 	// Validate scopes.
-	var hasUserAllScope bool
+	var hasUserAllScope, hasSudoScope, hasFineGrainedScope bool
 	seenScope := map[string]struct{}{}
 	sort.Strings(args.Scopes)
 	for _, scope := range args.Scopes {
-		switch scope {
-		case authz.ScopeUserAll:
+		switch {
+		case scope == authz.ScopeUserAll:
 			hasUserAllScope = true
-		case authz.ScopeSiteAdminSudo:
+		case scope == authz.ScopeSiteAdminSudo:
 			// 🚨 SECURITY: Only site admins may create a token with the "site-admin:sudo" scope.
 			if err := auth.CheckCurrentUserIsSiteAdmin(ctx, r.db); err != nil {
 				return nil, err
 			} else if envvar.SourcegraphDotComMode() {
 				return nil, errors.Errorf("creation of access tokens with scope %q is disabled on Sourcegraph.com", authz.ScopeSiteAdminSudo)
 			}
+			hasSudoScope = true
+		case authz.IsFineGrainedScope(scope):
+			hasFineGrainedScope = true
 		default:
 			return nil, errors.Errorf("unknown access token scope %q (valid scopes: %q)", scope, authz.AllScopes)
 		}
@@ -83,12 +89,24 @@ func (r *schemaResolver) CreateAccessToken(ctx context.Context, args *createAcce
 		}
 		seenScope[scope] = struct{}{}
 	}
-	if !hasUserAllScope {
-		return nil, errors.Errorf("all access tokens must have scope %q", authz.ScopeUserAll)
+	if hasUserAllScope && hasFineGrainedScope {
+		return nil, errors.Errorf("access token scope %q may not be combined with fine-grained scopes", authz.ScopeUserAll)
+	}
+	if !hasUserAllScope && (hasSudoScope || !hasFineGrainedScope) {
+		return nil, errors.Errorf("all access tokens must have scope %q or at least one of the scopes %q", authz.ScopeUserAll, authz.FineGrainedScopes)
 	}
 
 	uid := actor.FromContext(ctx).UID
-	id, token, err := r.db.AccessTokens().Create(ctx, userID, args.Scopes, args.Note, uid)
+	var id int64
+	var token string
+	if args.ExpiresAt != nil {
+		if !args.ExpiresAt.After(time.Now()) {
+			return nil, errors.New("access token expiration time must be in the future")
+		}
+		id, token, err = r.db.AccessTokens().CreateWithExpiration(ctx, userID, args.Scopes, args.Note, uid, args.ExpiresAt.Time)
+	} else {
+		id, token, err = r.db.AccessTokens().Create(ctx, userID, args.Scopes, args.Note, uid)
+	}
 	logger := r.logger.Scoped("CreateAccessToken", "access token creation").
 		With(log.Int32("userID", uid))
 
@@ -5,7 +5,9 @@ import (
 	"fmt"
 	"reflect"
 	"testing"
+	"time"
 
+	mockrequire "github.com/derision-test/go-mockgen/testutil/require"
 	"github.com/graph-gophers/graphql-go"
 	gqlerrors "github.com/graph-gophers/graphql-go/errors"
 	"github.com/hexops/autogold/v2"
@@ -21,6 +23,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/database/dbmocks"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc"
 	"github.com/sourcegraph/sourcegraph/internal/gitserver"
+	"github.com/sourcegraph/sourcegraph/internal/gqlutil"
 	"github.com/sourcegraph/sourcegraph/internal/types"
 	"github.com/sourcegraph/sourcegraph/schema"
 )
@@ -94,6 +97,63 @@ func TestMutation_CreateAccessToken(t *testing.T) {
 		}
 	})
 
+	t.Run("authenticated as user, using fine-grained scopes with expiration", func(t *testing.T) {
+		expiresAt := time.Now().Add(24 * time.Hour).Truncate(time.Second)
+
+		accessTokens := dbmocks.NewMockAccessTokenStore()
+		accessTokens.CreateWithExpirationFunc.SetDefaultHook(func(_ context.Context, subjectUserID int32, scopes []string, note string, creatorUserID int32, gotExpiresAt time.Time) (int64, string, error) {
+			if want := []string{authz.ScopeRepoRead, authz.ScopeSearchRead}; !reflect.DeepEqual(scopes, want) {
+				t.Errorf("got %q, want %q", scopes, want)
+			}
+			if !gotExpiresAt.Equal(expiresAt) {
+				t.Errorf("got %v, want %v", gotExpiresAt, expiresAt)
+			}
+			return 1, "t", nil
+		})
+
+		db := dbmocks.NewMockDB()
+		db.AccessTokensFunc.SetDefaultReturn(accessTokens)
+
+		ctx := actor.WithActor(context.Background(), &actor.Actor{UID: 1})
+		result, err := newSchemaResolver(db, gitserver.NewClient()).CreateAccessToken(ctx, &createAccessTokenInput{
+			User:      uid1GQLID,
+			Scopes:    []string{authz.ScopeSearchRead, authz.ScopeRepoRead},
+			Note:      "n",
+			ExpiresAt: &gqlutil.DateTime{Time: expiresAt},
+		})
+		require.NoError(t, err)
+		require.Equal(t, "t", result.Token())
+		mockrequire.Called(t, accessTokens.CreateWithExpirationFunc)
+	})
+
+	for _, tc := range []struct {
+		name      string
+		scopes    []string
+		expiresAt *gqlutil.DateTime
+	}{
+		{"combining user:all with fine-grained scopes", []string{authz.ScopeUserAll, authz.ScopeSearchRead}, nil},
+		{"unknown scope", []string{"repo:write"}, nil},
+		{"expiration in the past", []string{authz.ScopeUserAll}, &gqlutil.DateTime{Time: time.Now().Add(-time.Hour)}},
+	} {
+		t.Run("authenticated as user, "+tc.name, func(t *testing.T) {
+			accessTokens := dbmocks.NewMockAccessTokenStore()
+			db := dbmocks.NewMockDB()
+			db.AccessTokensFunc.SetDefaultReturn(accessTokens)
+
+			ctx := actor.WithActor(context.Background(), &actor.Actor{UID: 1})
+			result, err := newSchemaResolver(db, gitserver.NewClient()).CreateAccessToken(ctx, &createAccessTokenInput{
+				User:      uid1GQLID,
+				Scopes:    tc.scopes,
+				Note:      "n",
+				ExpiresAt: tc.expiresAt,
+			})
+			require.Error(t, err)
+			require.Nil(t, result)
+			mockrequire.NotCalled(t, accessTokens.CreateFunc)
+			mockrequire.NotCalled(t, accessTokens.CreateWithExpirationFunc)
+		})
+	}
+
 	t.Run("authenticated as user, using site-admin-only scopes", func(t *testing.T) {
 		users := dbmocks.NewMockUserStore()
 		users.GetByCurrentAuthUserFunc.SetDefaultReturn(&types.User{ID: 1, SiteAdmin: false}, nil)
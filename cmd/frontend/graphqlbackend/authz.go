@@ -29,6 +29,8 @@ type AuthzResolver interface {
 	AuthzProviderTypes(ctx context.Context) ([]string, error)
 	PermissionsSyncJobs(ctx context.Context, args ListPermissionsSyncJobsArgs) (*graphqlutil.ConnectionResolver[PermissionsSyncJobResolver], error)
 	PermissionsSyncingStats(ctx context.Context) (PermissionsSyncingStatsResolver, error)
+	ExplainUserPermissions(ctx context.Context, args *UserIDArgs) (PermissionsExplanationResolver, error)
+	ExplainRepositoryPermissions(ctx context.Context, args *RepositoryIDArgs) (PermissionsExplanationResolver, error)
 
 	// RepositoryPermissionsInfo and UserPermissionsInfo are helpers functions.
 	RepositoryPermissionsInfo(ctx context.Context, repoID graphql.ID) (PermissionsInfoResolver, error)
@@ -39,6 +41,10 @@ type RepositoryIDArgs struct {
 	Repository graphql.ID
 }
 
+type UserIDArgs struct {
+	User graphql.ID
+}
+
 type UserPermissionsSyncArgs struct {
 	User    graphql.ID
 	Options *struct {
@@ -172,3 +178,17 @@ type PermissionsSyncingStatsResolver interface {
 	UsersWithStalePermissions(ctx context.Context) (int32, error)
 	ReposWithStalePermissions(ctx context.Context) (int32, error)
 }
+
+type PermissionsExplanationResolver interface {
+	CodeHostStates() []CodeHostStateResolver
+	SiteAdmin() bool
+	Unrestricted() bool
+	Permissions() []PermissionsExplanationEntryResolver
+}
+
+type PermissionsExplanationEntryResolver interface {
+	Repository(ctx context.Context) (*RepositoryResolver, error)
+	User(ctx context.Context) (*UserResolver, error)
+	Source() string
+	Change() string
+}
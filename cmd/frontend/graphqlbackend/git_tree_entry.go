@@ -323,7 +323,10 @@ func (r *GitTreeEntryResolver) IsSingleChild(ctx context.Context, args *gitTreeE
 	return len(entries) == 1, nil
 }
 
-func (r *GitTreeEntryResolver) LSIF(ctx context.Context, args *struct{ ToolName *string }) (resolverstubs.GitBlobLSIFDataResolver, error) {
+func (r *GitTreeEntryResolver) LSIF(ctx context.Context, args *struct {
+	ToolName            *string
+	SearchBasedFallback bool
+}) (resolverstubs.GitBlobLSIFDataResolver, error) {
 	var toolName string
 	if args.ToolName != nil {
 		toolName = *args.ToolName
@@ -335,11 +338,12 @@ func (r *GitTreeEntryResolver) LSIF(ctx context.Context, args *struct{ ToolName
 	}
 
 	return EnterpriseResolvers.codeIntelResolver.GitBlobLSIFData(ctx, &resolverstubs.GitBlobLSIFDataArgs{
-		Repo:      repo,
-		Commit:    api.CommitID(r.Commit().OID()),
-		Path:      r.Path(),
-		ExactPath: !r.stat.IsDir(),
-		ToolName:  toolName,
+		Repo:                repo,
+		Commit:              api.CommitID(r.Commit().OID()),
+		Path:                r.Path(),
+		ExactPath:           !r.stat.IsDir(),
+		ToolName:            toolName,
+		SearchBasedFallback: args.SearchBasedFallback,
 	})
 }
 
@@ -31,6 +31,8 @@ type RBACResolver interface {
 	CreateRole(ctx context.Context, args *CreateRoleArgs) (RoleResolver, error)
 	SetPermissions(ctx context.Context, args SetPermissionsArgs) (*EmptyResponse, error)
 	SetRoles(ctx context.Context, args *SetRolesArgs) (*EmptyResponse, error)
+	AssignScopedRole(ctx context.Context, args *ScopedRoleArgs) (*EmptyResponse, error)
+	RevokeScopedRole(ctx context.Context, args *ScopedRoleArgs) (*EmptyResponse, error)
 }
 
 type DeleteRoleArgs struct {
@@ -66,6 +68,18 @@ type SetRolesArgs struct {
 	Roles []graphql.ID
 }
 
+type ScopedRoleArgs struct {
+	User  graphql.ID
+	Role  graphql.ID
+	Scope RoleScopeInput
+}
+
+type RoleScopeInput struct {
+	Organization  *graphql.ID
+	SearchContext *graphql.ID
+	Repository    *graphql.ID
+}
+
 type ErrIDIsZero struct{}
 
 func (e ErrIDIsZero) Error() string {
@@ -23,6 +23,7 @@ const (
 	Logout = "logout"
 
 	SignIn             = "sign-in"
+	SecondFactor       = "second-factor"
 	SignOut            = "sign-out"
 	SignUp             = "sign-up"
 	RequestAccess      = "request-access"
@@ -82,6 +83,7 @@ func newRouter() *mux.Router {
 	base.Path("/-/site-init").Methods("POST").Name(SiteInit)
 	base.Path("/-/verify-email").Methods("GET").Name(VerifyEmail)
 	base.Path("/-/sign-in").Methods("POST").Name(SignIn)
+	base.PathPrefix("/-/second-factor").Name(SecondFactor)
 	base.Path("/-/sign-out").Methods("GET").Name(SignOut)
 	base.Path("/-/unlock-account").Methods("POST").Name(UnlockAccount)
 	base.Path("/-/unlock-user-account").Methods("POST").Name(UnlockUserAccount)
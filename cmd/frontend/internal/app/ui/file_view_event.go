@@ -0,0 +1,61 @@
+package ui
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/audit"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+)
+
+// fileViewEventArgument is the argument of the security event recorded when a
+// file is viewed through the blob or raw endpoints.
+type fileViewEventArgument struct {
+	Repo     string `json:"repo"`
+	Rev      string `json:"rev,omitempty"`
+	CommitID string `json:"commitID,omitempty"`
+	Path     string `json:"path"`
+	// Endpoint is either "blob" or "raw".
+	Endpoint string `json:"endpoint"`
+	// ContentType is the negotiated content type of raw requests, which tells
+	// plain file views apart from archive downloads.
+	ContentType string `json:"contentType,omitempty"`
+}
+
+// logFileViewEvent records a security event for the viewed file if
+// log.auditLog.fileViews is enabled. The repository, revision and path are
+// redacted according to log.auditLog.redactionPatterns.
+func logFileViewEvent(r *http.Request, db database.DB, common *Common, endpoint, filePath, contentType string) {
+	cfg := conf.SiteConfig()
+	if !audit.IsEnabled(cfg, audit.FileViews) || !audit.SampleDataAccess(cfg) {
+		return
+	}
+
+	argument, err := json.Marshal(fileViewEventArgument{
+		Repo:        audit.RedactDataAccess(cfg, string(common.Repo.Name)),
+		Rev:         audit.RedactDataAccess(cfg, common.Rev),
+		CommitID:    string(common.CommitID),
+		Path:        audit.RedactDataAccess(cfg, filePath),
+		Endpoint:    endpoint,
+		ContentType: contentType,
+	})
+	if err != nil {
+		return
+	}
+
+	ctx := r.Context()
+	a := actor.FromContext(ctx)
+	db.SecurityEventLogs().LogEvent(ctx, &database.SecurityEvent{
+		Name: database.SecurityEventNameFileViewed,
+		// The query string is left out, since it may contain a search query.
+		URL:             audit.RedactDataAccess(cfg, r.URL.Path),
+		UserID:          uint32(a.UID),
+		AnonymousUserID: a.AnonymousUID,
+		Argument:        argument,
+		Source:          "BACKEND",
+		Timestamp:       time.Now(),
+	})
+}
@@ -525,6 +525,9 @@ func serveRepoOrBlob(db database.DB, routeName string, title func(c *Common, r *
 			http.Redirect(w, r, r.URL.String(), http.StatusPermanentRedirect)
 			return nil
 		}
+		if routeName == routeBlob {
+			logFileViewEvent(r, db, common, "blob", mux.Vars(r)["Path"], "")
+		}
 		return renderTemplate(w, "app.html", common)
 	}
 }
@@ -124,6 +124,8 @@ func serveRaw(logger log.Logger, db database.DB, gitserverClient gitserver.Clien
 			contentType = applicationXTar
 		}
 
+		logFileViewEvent(r, db, common, "raw", requestedPath, contentType)
+
 		// Instrument to understand duration and errors
 		var (
 			start       = time.Now()
@@ -13,15 +13,19 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/gorilla/mux"
 	"github.com/stretchr/testify/assert"
 
 	"github.com/sourcegraph/log/logtest"
+	"github.com/sourcegraph/sourcegraph/internal/actor"
 	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
 	"github.com/sourcegraph/sourcegraph/internal/database"
 	"github.com/sourcegraph/sourcegraph/internal/database/dbmocks"
 	"github.com/sourcegraph/sourcegraph/internal/fileutil"
 	"github.com/sourcegraph/sourcegraph/internal/gitserver"
 	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/schema"
 )
 
 // initHTTPTestGitServer instantiates an httptest.Server to make it return an HTTP response as set
@@ -414,3 +418,50 @@ func Test_serveRawRepoCloning(t *testing.T) {
 	assert.Equal(t, http.StatusNotFound, w.Code, "http response status")
 	assert.Equal(t, "Repository unavailable while cloning.", string(w.Body.Bytes()), "http response body")
 }
+
+func Test_serveRawFileViewEvent(t *testing.T) {
+	mockNewCommon = func(w http.ResponseWriter, r *http.Request, title string, serveError serveErrorHandler) (*Common, error) {
+		return &Common{
+			Repo: &types.Repo{
+				Name: "github.com/acme/secret",
+			},
+			Rev:      "secret-branch",
+			CommitID: api.CommitID("12345"),
+		}, nil
+	}
+	defer func() {
+		mockNewCommon = nil
+	}()
+
+	conf.Mock(&conf.Unified{SiteConfiguration: schema.SiteConfiguration{Log: &schema.Log{AuditLog: &schema.AuditLog{
+		FileViews:         true,
+		RedactionPatterns: []string{`secret`},
+	}}}})
+	defer conf.Mock(nil)
+
+	gsClient := gitserver.NewMockClient()
+	gsClient.StatFunc.SetDefaultReturn(&fileutil.FileInfo{}, os.ErrNotExist)
+
+	securityEventLogs := dbmocks.NewMockSecurityEventLogsStore()
+	db := dbmocks.NewMockDB()
+	db.SecurityEventLogsFunc.SetDefaultReturn(securityEventLogs)
+
+	req := httptest.NewRequest("GET", "/test/-/raw/secret/notes.md?q=token", nil)
+	req = mux.SetURLVars(req, map[string]string{"Path": "secret/notes.md"})
+	req = req.WithContext(actor.WithActor(req.Context(), &actor.Actor{UID: 1}))
+	w := httptest.NewRecorder()
+
+	if err := serveRaw(logtest.Scoped(t), db, gsClient)(w, req); err != nil {
+		t.Fatalf("Failed to invoke serveRaw: %v", err)
+	}
+
+	history := securityEventLogs.LogEventFunc.History()
+	if len(history) != 1 {
+		t.Fatalf("Want 1 security event but got %d", len(history))
+	}
+	event := history[0].Arg1
+	assert.Equal(t, database.SecurityEventNameFileViewed, event.Name)
+	assert.Equal(t, "/test/-/raw/[REDACTED]/notes.md", event.URL)
+	assert.Equal(t, uint32(1), event.UserID)
+	assert.JSONEq(t, `{"repo":"github.com/acme/[REDACTED]","rev":"[REDACTED]-branch","commitID":"12345","path":"/[REDACTED]/notes.md","endpoint":"raw","contentType":"text/plain"}`, string(event.Argument))
+}
@@ -21,6 +21,9 @@ type ExternalAccountData struct {
 	IDToken    oidc.IDToken  `json:"idToken"`
 	UserInfo   oidc.UserInfo `json:"userInfo"`
 	UserClaims userClaims    `json:"userClaims"`
+	// Groups are the groups asserted in the groups claim of the ID token or userinfo
+	// response, used to grant repository permissions by group.
+	Groups []string `json:"groups,omitempty"`
 }
 
 // getOrCreateUser gets or creates a user account based on the OpenID Connect token. It returns the
@@ -69,6 +72,7 @@ func getOrCreateUser(ctx context.Context, db database.DB, p *Provider, idToken *
 		IDToken:    *idToken,
 		UserInfo:   *userInfo,
 		UserClaims: *claims,
+		Groups:     getGroups(p.config.GroupsClaimName, idToken, userInfo),
 	})
 	if err != nil {
 		return false, nil, "", err
@@ -105,6 +109,42 @@ func getOrCreateUser(ctx context.Context, db database.DB, p *Provider, idToken *
 	return newUserCreated, actor.FromUser(userID), "", nil
 }
 
+// getGroups returns the groups in the claim with the given name (default "groups") of
+// the ID token, or of the userinfo response if the ID token has no such claim.
+func getGroups(claimName string, idToken *oidc.IDToken, userInfo *oidc.UserInfo) []string {
+	if claimName == "" {
+		claimName = "groups"
+	}
+
+	var claims map[string]json.RawMessage
+	if err := idToken.Claims(&claims); err == nil {
+		if raw, ok := claims[claimName]; ok {
+			return parseGroupsClaim(raw)
+		}
+	}
+	claims = nil
+	if err := userInfo.Claims(&claims); err == nil {
+		if raw, ok := claims[claimName]; ok {
+			return parseGroupsClaim(raw)
+		}
+	}
+	return nil
+}
+
+// parseGroupsClaim parses a groups claim, which is either an array of strings or a
+// single string.
+func parseGroupsClaim(raw json.RawMessage) []string {
+	var groups []string
+	if err := json.Unmarshal(raw, &groups); err == nil {
+		return groups
+	}
+	var group string
+	if err := json.Unmarshal(raw, &group); err == nil && group != "" {
+		return []string{group}
+	}
+	return nil
+}
+
 // GetExternalAccountData returns the deserialized JSON blob from user external accounts table
 func GetExternalAccountData(ctx context.Context, data *extsvc.AccountData) (val *ExternalAccountData, err error) {
 	if data.Data != nil {
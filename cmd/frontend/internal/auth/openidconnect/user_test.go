@@ -2,6 +2,7 @@ package openidconnect
 
 import (
 	"context"
+	"encoding/json"
 	"strings"
 	"testing"
 
@@ -98,3 +99,20 @@ func TestGetPublicExternalAccountData(t *testing.T) {
 		require.Equal(t, want, *got)
 	})
 }
+
+func TestParseGroupsClaim(t *testing.T) {
+	tests := map[string]struct {
+		raw  string
+		want []string
+	}{
+		"array":        {raw: `["engineering","security"]`, want: []string{"engineering", "security"}},
+		"single value": {raw: `"engineering"`, want: []string{"engineering"}},
+		"empty string": {raw: `""`, want: nil},
+		"invalid":      {raw: `{"name":"engineering"}`, want: nil},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			require.Equal(t, test.want, parseGroupsClaim(json.RawMessage(test.raw)))
+		})
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
+	"sort"
 	"strings"
 
 	saml2 "github.com/russellhaering/gosaml2"
@@ -22,7 +23,7 @@ type authnResponseInfo struct {
 	email, displayName   string
 	unnormalizedUsername string
 	groups               map[string]bool
-	accountData          any
+	accountData          *saml2.AssertionInfo
 }
 
 func readAuthnResponse(p *provider, encodedResp string) (*authnResponseInfo, error) {
@@ -223,10 +224,27 @@ func GetPublicExternalAccountData(ctx context.Context, accountData *extsvc.Accou
 	}, nil
 }
 
+// externalAccountData is the external account data blob of a SAML user. The groups
+// of the user are stored alongside the assertions so that repository permissions can
+// be granted by group without parsing the assertions again.
+type externalAccountData struct {
+	*saml2.AssertionInfo
+	Groups []string `json:"groups,omitempty"`
+}
+
 // SetExternalAccountData sets the user and token into the external account data blob.
 func SetExternalAccountData(data *extsvc.AccountData, info *authnResponseInfo) error {
+	groups := make([]string, 0, len(info.groups))
+	for group := range info.groups {
+		groups = append(groups, group)
+	}
+	sort.Strings(groups)
+
 	// TODO: leverage the whole info object instead of just storing JSON blob without any structure
-	serializedData, err := json.Marshal(info.accountData)
+	serializedData, err := json.Marshal(externalAccountData{
+		AssertionInfo: info.accountData,
+		Groups:        groups,
+	})
 	if err != nil {
 		return err
 	}
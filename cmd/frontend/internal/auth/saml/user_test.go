@@ -10,10 +10,12 @@ import (
 	"time"
 
 	saml2 "github.com/russellhaering/gosaml2"
+	saml2types "github.com/russellhaering/gosaml2/types"
 	dsig "github.com/russellhaering/goxmldsig"
 	"github.com/stretchr/testify/require"
 	"gotest.tools/assert"
 
+	"github.com/sourcegraph/sourcegraph/internal/encryption"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc"
 )
 
@@ -131,3 +133,28 @@ func TestGetPublicExternalAccountData(t *testing.T) {
 		})
 	}
 }
+
+func TestSetExternalAccountData(t *testing.T) {
+	info := &authnResponseInfo{
+		groups: map[string]bool{"security": true, "engineering": true},
+		accountData: &saml2.AssertionInfo{
+			NameID: "alice",
+			Values: saml2.Values{"email": {Name: "email", Values: []saml2types.AttributeValue{{Value: "alice@acme.com"}}}},
+		},
+	}
+
+	var data extsvc.AccountData
+	require.NoError(t, SetExternalAccountData(&data, info))
+
+	got, err := encryption.DecryptJSON[struct {
+		NameID string
+		Groups []string `json:"groups"`
+	}](context.Background(), data.Data)
+	require.NoError(t, err)
+	require.Equal(t, "alice", got.NameID)
+	require.Equal(t, []string{"engineering", "security"}, got.Groups)
+
+	values, err := GetExternalAccountData(context.Background(), &data)
+	require.NoError(t, err)
+	require.Equal(t, "alice@acme.com", values.Values["email"].Values[0].Value)
+}
@@ -0,0 +1,121 @@
+package resolvers
+
+import (
+	"context"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend"
+	"github.com/sourcegraph/sourcegraph/internal/auth"
+	"github.com/sourcegraph/sourcegraph/internal/authz/permssync"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/errcode"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver"
+	"github.com/sourcegraph/sourcegraph/internal/licensing"
+)
+
+func (r *Resolver) ExplainUserPermissions(ctx context.Context, args *graphqlbackend.UserIDArgs) (graphqlbackend.PermissionsExplanationResolver, error) {
+	if err := r.checkLicense(licensing.FeatureACLs); err != nil {
+		return nil, err
+	}
+
+	// 🚨 SECURITY: Only site admins can explain user permissions.
+	if err := auth.CheckCurrentUserIsSiteAdmin(ctx, r.db); err != nil {
+		return nil, err
+	}
+
+	userID, err := graphqlbackend.UnmarshalUserID(args.User)
+	if err != nil {
+		return nil, err
+	}
+
+	explanation, err := permssync.ExplainUserPerms(ctx, r.db, userID)
+	if err != nil {
+		return nil, err
+	}
+	return &permissionsExplanationResolver{db: r.db, explanation: explanation}, nil
+}
+
+func (r *Resolver) ExplainRepositoryPermissions(ctx context.Context, args *graphqlbackend.RepositoryIDArgs) (graphqlbackend.PermissionsExplanationResolver, error) {
+	if err := r.checkLicense(licensing.FeatureACLs); err != nil {
+		return nil, err
+	}
+
+	// 🚨 SECURITY: Only site admins can explain repository permissions.
+	if err := auth.CheckCurrentUserIsSiteAdmin(ctx, r.db); err != nil {
+		return nil, err
+	}
+
+	repoID, err := graphqlbackend.UnmarshalRepositoryID(args.Repository)
+	if err != nil {
+		return nil, err
+	}
+
+	explanation, err := permssync.ExplainRepoPerms(ctx, r.db, repoID)
+	if err != nil {
+		return nil, err
+	}
+	return &permissionsExplanationResolver{db: r.db, explanation: explanation}, nil
+}
+
+type permissionsExplanationResolver struct {
+	db          database.DB
+	explanation *permssync.Explanation
+}
+
+func (r *permissionsExplanationResolver) CodeHostStates() []graphqlbackend.CodeHostStateResolver {
+	resolvers := make([]graphqlbackend.CodeHostStateResolver, 0, len(r.explanation.ProviderStates))
+	for _, state := range r.explanation.ProviderStates {
+		resolvers = append(resolvers, codeHostStateResolver{state: state})
+	}
+	return resolvers
+}
+
+func (r *permissionsExplanationResolver) SiteAdmin() bool {
+	return r.explanation.SiteAdmin
+}
+
+func (r *permissionsExplanationResolver) Unrestricted() bool {
+	return r.explanation.Unrestricted
+}
+
+func (r *permissionsExplanationResolver) Permissions() []graphqlbackend.PermissionsExplanationEntryResolver {
+	resolvers := make([]graphqlbackend.PermissionsExplanationEntryResolver, 0, len(r.explanation.Permissions))
+	for _, perm := range r.explanation.Permissions {
+		resolvers = append(resolvers, permissionsExplanationEntryResolver{db: r.db, perm: perm})
+	}
+	return resolvers
+}
+
+type permissionsExplanationEntryResolver struct {
+	db   database.DB
+	perm permssync.ExplainedPermission
+}
+
+func (r permissionsExplanationEntryResolver) Repository(ctx context.Context) (*graphqlbackend.RepositoryResolver, error) {
+	repo, err := r.db.Repos().Get(ctx, r.perm.RepoID)
+	if err != nil {
+		if errcode.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return graphqlbackend.NewRepositoryResolver(r.db, gitserver.NewClient(), repo), nil
+}
+
+func (r permissionsExplanationEntryResolver) User(ctx context.Context) (*graphqlbackend.UserResolver, error) {
+	user, err := graphqlbackend.UserByIDInt32(ctx, r.db, r.perm.UserID)
+	if err != nil {
+		if errcode.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return user, nil
+}
+
+func (r permissionsExplanationEntryResolver) Source() string {
+	return r.perm.Source.ToGraphQL()
+}
+
+func (r permissionsExplanationEntryResolver) Change() string {
+	return string(r.perm.Change)
+}
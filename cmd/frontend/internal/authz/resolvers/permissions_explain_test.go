@@ -0,0 +1,68 @@
+package resolvers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend"
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/auth"
+	"github.com/sourcegraph/sourcegraph/internal/authz"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbmocks"
+	"github.com/sourcegraph/sourcegraph/internal/licensing"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+)
+
+func TestResolver_ExplainPermissions(t *testing.T) {
+	t.Cleanup(licensing.TestingSkipFeatureChecks())
+	ctx := actor.WithActor(context.Background(), &actor.Actor{UID: 1})
+
+	t.Run("authenticated as non-admin", func(t *testing.T) {
+		users := dbmocks.NewStrictMockUserStore()
+		users.GetByCurrentAuthUserFunc.SetDefaultReturn(&types.User{}, nil)
+
+		db := dbmocks.NewStrictMockDB()
+		db.UsersFunc.SetDefaultReturn(users)
+
+		r := &Resolver{db: db}
+		_, err := r.ExplainUserPermissions(ctx, &graphqlbackend.UserIDArgs{User: graphqlbackend.MarshalUserID(2)})
+		require.Equal(t, auth.ErrMustBeSiteAdmin, err)
+
+		_, err = r.ExplainRepositoryPermissions(ctx, &graphqlbackend.RepositoryIDArgs{Repository: graphqlbackend.MarshalRepositoryID(1)})
+		require.Equal(t, auth.ErrMustBeSiteAdmin, err)
+	})
+
+	t.Run("explain repository permissions", func(t *testing.T) {
+		users := dbmocks.NewStrictMockUserStore()
+		users.GetByCurrentAuthUserFunc.SetDefaultReturn(&types.User{ID: 1, SiteAdmin: true}, nil)
+
+		repos := dbmocks.NewStrictMockRepoStore()
+		repos.GetFunc.SetDefaultReturn(&types.Repo{ID: 1}, nil)
+
+		perms := dbmocks.NewStrictMockPermsStore()
+		perms.LoadRepoPermissionsFunc.SetDefaultReturn([]authz.Permission{
+			{UserID: 2, RepoID: 1, Source: authz.SourceAPI},
+		}, nil)
+
+		db := dbmocks.NewStrictMockDB()
+		db.UsersFunc.SetDefaultReturn(users)
+		db.ReposFunc.SetDefaultReturn(repos)
+		db.PermsFunc.SetDefaultReturn(perms)
+
+		explanation, err := (&Resolver{db: db}).ExplainRepositoryPermissions(ctx, &graphqlbackend.RepositoryIDArgs{
+			Repository: graphqlbackend.MarshalRepositoryID(api.RepoID(1)),
+		})
+		require.NoError(t, err)
+		require.True(t, explanation.Unrestricted())
+		require.False(t, explanation.SiteAdmin())
+		require.Empty(t, explanation.CodeHostStates())
+
+		entries := explanation.Permissions()
+		require.Len(t, entries, 1)
+		require.Equal(t, "API", entries[0].Source())
+		require.Equal(t, "UNCHANGED", entries[0].Change())
+	})
+}
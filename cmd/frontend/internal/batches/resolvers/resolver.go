@@ -679,10 +679,6 @@ func (r *Resolver) MoveBatchChange(ctx context.Context, args *graphqlbackend.Mov
 		return nil, err
 	}
 
-	if err := rbac.CheckCurrentUserHasPermission(ctx, r.store.DatabaseDB(), rbac.BatchChangesWritePermission); err != nil {
-		return nil, err
-	}
-
 	batchChangeID, err := unmarshalBatchChangeID(args.BatchChange)
 	if err != nil {
 		return nil, err
@@ -692,6 +688,10 @@ func (r *Resolver) MoveBatchChange(ctx context.Context, args *graphqlbackend.Mov
 		return nil, ErrIDIsZero{}
 	}
 
+	if err := r.checkBatchChangesWritePermissionForBatchChange(ctx, batchChangeID); err != nil {
+		return nil, err
+	}
+
 	opts := service.MoveBatchChangeOpts{
 		BatchChangeID: batchChangeID,
 	}
@@ -705,6 +705,12 @@ func (r *Resolver) MoveBatchChange(ctx context.Context, args *graphqlbackend.Mov
 		if err != nil {
 			return nil, err
 		}
+
+		// The user also needs to be allowed to write batch changes in the namespace
+		// the batch change is moved to.
+		if err := checkBatchChangesWritePermissionForNamespace(ctx, r.store.DatabaseDB(), opts.NewNamespaceOrgID); err != nil {
+			return nil, err
+		}
 	}
 
 	svc := service.New(r.store)
@@ -724,10 +730,6 @@ func (r *Resolver) DeleteBatchChange(ctx context.Context, args *graphqlbackend.D
 		return nil, err
 	}
 
-	if err := rbac.CheckCurrentUserHasPermission(ctx, r.store.DatabaseDB(), rbac.BatchChangesWritePermission); err != nil {
-		return nil, err
-	}
-
 	batchChangeID, err := unmarshalBatchChangeID(args.BatchChange)
 	if err != nil {
 		return nil, err
@@ -737,6 +739,10 @@ func (r *Resolver) DeleteBatchChange(ctx context.Context, args *graphqlbackend.D
 		return nil, ErrIDIsZero{}
 	}
 
+	if err := r.checkBatchChangesWritePermissionForBatchChange(ctx, batchChangeID); err != nil {
+		return nil, err
+	}
+
 	svc := service.New(r.store)
 	// 🚨 SECURITY: DeleteBatchChange checks whether current user is authorized.
 	err = svc.DeleteBatchChange(ctx, batchChangeID)
@@ -1001,10 +1007,6 @@ func (r *Resolver) CloseBatchChange(ctx context.Context, args *graphqlbackend.Cl
 		return nil, err
 	}
 
-	if err := rbac.CheckCurrentUserHasPermission(ctx, r.store.DatabaseDB(), rbac.BatchChangesWritePermission); err != nil {
-		return nil, err
-	}
-
 	batchChangeID, err := unmarshalBatchChangeID(args.BatchChange)
 	if err != nil {
 		return nil, errors.Wrap(err, "unmarshaling batch change id")
@@ -1014,6 +1016,10 @@ func (r *Resolver) CloseBatchChange(ctx context.Context, args *graphqlbackend.Cl
 		return nil, ErrIDIsZero{}
 	}
 
+	if err := r.checkBatchChangesWritePermissionForBatchChange(ctx, batchChangeID); err != nil {
+		return nil, err
+	}
+
 	svc := service.New(r.store)
 	// 🚨 SECURITY: CloseBatchChange checks whether current user is authorized.
 	batchChange, err := svc.CloseBatchChange(ctx, batchChangeID, args.CloseChangesets)
@@ -1323,12 +1329,12 @@ func (r *Resolver) DetachChangesets(ctx context.Context, args *graphqlbackend.De
 		return nil, err
 	}
 
-	if err := rbac.CheckCurrentUserHasPermission(ctx, r.store.DatabaseDB(), rbac.BatchChangesWritePermission); err != nil {
+	batchChangeID, changesetIDs, err := unmarshalBulkOperationBaseArgs(args.BulkOperationBaseArgs)
+	if err != nil {
 		return nil, err
 	}
 
-	batchChangeID, changesetIDs, err := unmarshalBulkOperationBaseArgs(args.BulkOperationBaseArgs)
-	if err != nil {
+	if err := r.checkBatchChangesWritePermissionForBatchChange(ctx, batchChangeID); err != nil {
 		return nil, err
 	}
 
@@ -1361,10 +1367,6 @@ func (r *Resolver) CreateChangesetComments(ctx context.Context, args *graphqlbac
 		return nil, err
 	}
 
-	if err := rbac.CheckCurrentUserHasPermission(ctx, r.store.DatabaseDB(), rbac.BatchChangesWritePermission); err != nil {
-		return nil, err
-	}
-
 	if args.Body == "" {
 		return nil, errors.New("empty comment body is not allowed")
 	}
@@ -1374,6 +1376,10 @@ func (r *Resolver) CreateChangesetComments(ctx context.Context, args *graphqlbac
 		return nil, err
 	}
 
+	if err := r.checkBatchChangesWritePermissionForBatchChange(ctx, batchChangeID); err != nil {
+		return nil, err
+	}
+
 	// 🚨 SECURITY: CreateChangesetJobs checks whether current user is authorized.
 	svc := service.New(r.store)
 	published := btypes.ChangesetPublicationStatePublished
@@ -1408,12 +1414,12 @@ func (r *Resolver) ReenqueueChangesets(ctx context.Context, args *graphqlbackend
 		return nil, err
 	}
 
-	if err := rbac.CheckCurrentUserHasPermission(ctx, r.store.DatabaseDB(), rbac.BatchChangesWritePermission); err != nil {
+	batchChangeID, changesetIDs, err := unmarshalBulkOperationBaseArgs(args.BulkOperationBaseArgs)
+	if err != nil {
 		return nil, err
 	}
 
-	batchChangeID, changesetIDs, err := unmarshalBulkOperationBaseArgs(args.BulkOperationBaseArgs)
-	if err != nil {
+	if err := r.checkBatchChangesWritePermissionForBatchChange(ctx, batchChangeID); err != nil {
 		return nil, err
 	}
 
@@ -1446,12 +1452,12 @@ func (r *Resolver) MergeChangesets(ctx context.Context, args *graphqlbackend.Mer
 		return nil, err
 	}
 
-	if err := rbac.CheckCurrentUserHasPermission(ctx, r.store.DatabaseDB(), rbac.BatchChangesWritePermission); err != nil {
+	batchChangeID, changesetIDs, err := unmarshalBulkOperationBaseArgs(args.BulkOperationBaseArgs)
+	if err != nil {
 		return nil, err
 	}
 
-	batchChangeID, changesetIDs, err := unmarshalBulkOperationBaseArgs(args.BulkOperationBaseArgs)
-	if err != nil {
+	if err := r.checkBatchChangesWritePermissionForBatchChange(ctx, batchChangeID); err != nil {
 		return nil, err
 	}
 
@@ -1487,12 +1493,12 @@ func (r *Resolver) CloseChangesets(ctx context.Context, args *graphqlbackend.Clo
 		return nil, err
 	}
 
-	if err := rbac.CheckCurrentUserHasPermission(ctx, r.store.DatabaseDB(), rbac.BatchChangesWritePermission); err != nil {
+	batchChangeID, changesetIDs, err := unmarshalBulkOperationBaseArgs(args.BulkOperationBaseArgs)
+	if err != nil {
 		return nil, err
 	}
 
-	batchChangeID, changesetIDs, err := unmarshalBulkOperationBaseArgs(args.BulkOperationBaseArgs)
-	if err != nil {
+	if err := r.checkBatchChangesWritePermissionForBatchChange(ctx, batchChangeID); err != nil {
 		return nil, err
 	}
 
@@ -1527,12 +1533,12 @@ func (r *Resolver) PublishChangesets(ctx context.Context, args *graphqlbackend.P
 		return nil, err
 	}
 
-	if err := rbac.CheckCurrentUserHasPermission(ctx, r.store.DatabaseDB(), rbac.BatchChangesWritePermission); err != nil {
+	batchChangeID, changesetIDs, err := unmarshalBulkOperationBaseArgs(args.BulkOperationBaseArgs)
+	if err != nil {
 		return nil, err
 	}
 
-	batchChangeID, changesetIDs, err := unmarshalBulkOperationBaseArgs(args.BulkOperationBaseArgs)
-	if err != nil {
+	if err := r.checkBatchChangesWritePermissionForBatchChange(ctx, batchChangeID); err != nil {
 		return nil, err
 	}
 
@@ -1608,17 +1614,17 @@ func (r *Resolver) CreateEmptyBatchChange(ctx context.Context, args *graphqlback
 		return nil, err
 	}
 
-	if err := rbac.CheckCurrentUserHasPermission(ctx, r.store.DatabaseDB(), rbac.BatchChangesWritePermission); err != nil {
+	var uid, oid int32
+	if err := graphqlbackend.UnmarshalNamespaceID(args.Namespace, &uid, &oid); err != nil {
 		return nil, err
 	}
 
-	svc := service.New(r.store)
-
-	var uid, oid int32
-	if err := graphqlbackend.UnmarshalNamespaceID(args.Namespace, &uid, &oid); err != nil {
+	if err := checkBatchChangesWritePermissionForNamespace(ctx, r.store.DatabaseDB(), oid); err != nil {
 		return nil, err
 	}
 
+	svc := service.New(r.store)
+
 	batchChange, err := svc.CreateEmptyBatchChange(ctx, service.CreateEmptyBatchChangeOpts{
 		NamespaceUserID: uid,
 		NamespaceOrgID:  oid,
@@ -1644,17 +1650,17 @@ func (r *Resolver) UpsertEmptyBatchChange(ctx context.Context, args *graphqlback
 		return nil, err
 	}
 
-	if err := rbac.CheckCurrentUserHasPermission(ctx, r.store.DatabaseDB(), rbac.BatchChangesWritePermission); err != nil {
+	var uid, oid int32
+	if err := graphqlbackend.UnmarshalNamespaceID(args.Namespace, &uid, &oid); err != nil {
 		return nil, err
 	}
 
-	svc := service.New(r.store)
-
-	var uid, oid int32
-	if err := graphqlbackend.UnmarshalNamespaceID(args.Namespace, &uid, &oid); err != nil {
+	if err := checkBatchChangesWritePermissionForNamespace(ctx, r.store.DatabaseDB(), oid); err != nil {
 		return nil, err
 	}
 
+	svc := service.New(r.store)
+
 	batchChange, err := svc.UpsertEmptyBatchChange(ctx, service.UpsertEmptyBatchChangeOpts{
 		NamespaceUserID: uid,
 		NamespaceOrgID:  oid,
@@ -1677,17 +1683,17 @@ func (r *Resolver) CreateBatchSpecFromRaw(ctx context.Context, args *graphqlback
 		return nil, err
 	}
 
-	if err := rbac.CheckCurrentUserHasPermission(ctx, r.store.DatabaseDB(), rbac.BatchChangesWritePermission); err != nil {
+	var uid, oid int32
+	if err := graphqlbackend.UnmarshalNamespaceID(args.Namespace, &uid, &oid); err != nil {
 		return nil, err
 	}
 
-	svc := service.New(r.store)
-
-	var uid, oid int32
-	if err := graphqlbackend.UnmarshalNamespaceID(args.Namespace, &uid, &oid); err != nil {
+	if err := checkBatchChangesWritePermissionForNamespace(ctx, r.store.DatabaseDB(), oid); err != nil {
 		return nil, err
 	}
 
+	svc := service.New(r.store)
+
 	bid, err := unmarshalBatchChangeID(args.BatchChange)
 	if err != nil {
 		return nil, err
@@ -1868,17 +1874,17 @@ func (r *Resolver) UpsertBatchSpecInput(ctx context.Context, args *graphqlbacken
 		return nil, err
 	}
 
-	if err := rbac.CheckCurrentUserHasPermission(ctx, r.store.DatabaseDB(), rbac.BatchChangesWritePermission); err != nil {
+	var uid, oid int32
+	if err := graphqlbackend.UnmarshalNamespaceID(args.Namespace, &uid, &oid); err != nil {
 		return nil, err
 	}
 
-	svc := service.New(r.store)
-
-	var uid, oid int32
-	if err := graphqlbackend.UnmarshalNamespaceID(args.Namespace, &uid, &oid); err != nil {
+	if err := checkBatchChangesWritePermissionForNamespace(ctx, r.store.DatabaseDB(), oid); err != nil {
 		return nil, err
 	}
 
+	svc := service.New(r.store)
+
 	// 🚨 SECURITY: UpsertBatchSpecInput checks whether current user is
 	// authorised and has access to the namespace.
 	//
@@ -2137,6 +2143,29 @@ func validateFirstParamDefaults(first int32) error {
 	return validateFirstParam(first, defaultMaxFirstParam)
 }
 
+// checkBatchChangesWritePermissionForBatchChange returns an error if the current user is
+// not allowed to write the batch change with the given ID, either through a role assigned
+// to them globally or a role assigned to them for the organization owning the batch change.
+func (r *Resolver) checkBatchChangesWritePermissionForBatchChange(ctx context.Context, batchChangeID int64) error {
+	batchChange, err := r.store.GetBatchChange(ctx, store.GetBatchChangeOpts{ID: batchChangeID})
+	if err != nil {
+		if err == store.ErrNoResults {
+			// Only global roles apply. The service returns a not found error to
+			// users that are allowed to write batch changes.
+			return rbac.CheckCurrentUserHasPermission(ctx, r.store.DatabaseDB(), rbac.BatchChangesWritePermission)
+		}
+		return err
+	}
+	return checkBatchChangesWritePermissionForNamespace(ctx, r.store.DatabaseDB(), batchChange.NamespaceOrgID)
+}
+
+// checkBatchChangesWritePermissionForNamespace returns an error if the current user is not
+// allowed to write batch changes in the namespace of the given organization. An orgID of 0
+// denotes a user namespace, in which case only globally assigned roles apply.
+func checkBatchChangesWritePermissionForNamespace(ctx context.Context, db database.DB, orgID int32) error {
+	return rbac.CheckCurrentUserHasPermissionForResource(ctx, db, rbac.BatchChangesWritePermission, rbac.Resource{OrgID: orgID})
+}
+
 func unmarshalBulkOperationBaseArgs(args graphqlbackend.BulkOperationBaseArgs) (batchChangeID int64, changesetIDs []int64, err error) {
 	batchChangeID, err = unmarshalBatchChangeID(args.BatchChange)
 	if err != nil {
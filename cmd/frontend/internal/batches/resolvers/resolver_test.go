@@ -877,7 +877,7 @@ func TestCreateEmptyBatchChange(t *testing.T) {
 	userID := bt.CreateTestUser(t, db, true).ID
 	// We give this user the `BATCH_CHANGES#WRITE` permission so they're authorized
 	// to create Batch Changes.
-	assignBatchChangesWritePermissionToUser(ctx, t, db, userID)
+	_, perm := assignBatchChangesWritePermissionToUser(ctx, t, db, userID)
 	namespaceID := relay.MarshalID("User", userID)
 
 	unauthorizedUser := bt.CreateTestUser(t, db, false)
@@ -955,6 +955,41 @@ func TestCreateEmptyBatchChange(t *testing.T) {
 
 	})
 
+	t.Run("user with role scoped to organization", func(t *testing.T) {
+		scopedUser := bt.CreateTestUser(t, db, false)
+		org := bt.CreateTestOrg(t, db, "scoped-org", scopedUser.ID)
+
+		role := bt.CreateTestRole(ctx, t, db, "TEST-ROLE-SCOPED")
+		bt.AssignPermissionToRole(ctx, t, db, perm.ID, role.ID)
+		err := db.ScopedUserRoles().Assign(ctx, database.AssignScopedUserRoleOpts{
+			UserID: scopedUser.ID,
+			RoleID: role.ID,
+			Scope:  types.RoleScope{OrgID: org.ID},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		actorCtx := actor.WithActor(ctx, actor.FromUser(scopedUser.ID))
+
+		var response struct{ CreateEmptyBatchChange apitest.BatchChange }
+		apitest.MustExec(actorCtx, t, s, map[string]any{
+			"namespace": relay.MarshalID("Org", org.ID),
+			"name":      "my-batch-change",
+		}, &response, mutationCreateEmptyBatchChange)
+		if response.CreateEmptyBatchChange.ID == "" {
+			t.Fatalf("expected batch change to be created, but was not")
+		}
+
+		// The role doesn't apply outside of the organization.
+		errs := apitest.Exec(actorCtx, t, s, map[string]any{
+			"namespace": relay.MarshalID("User", scopedUser.ID),
+			"name":      "my-batch-change",
+		}, &response, mutationCreateEmptyBatchChange)
+		if len(errs) == 0 || !strings.Contains(errs[0].Error(), fmt.Sprintf("user is missing permission %s", rbac.BatchChangesWritePermission)) {
+			t.Fatalf("expected unauthorized error, got %+v", errs)
+		}
+	})
 }
 
 const mutationCreateEmptyBatchChange = `
@@ -6,6 +6,7 @@ import (
 
 	"github.com/sourcegraph/log"
 
+	"github.com/sourcegraph/sourcegraph/internal/audit"
 	"github.com/sourcegraph/sourcegraph/internal/conf"
 	"github.com/sourcegraph/sourcegraph/internal/database"
 )
@@ -34,22 +35,20 @@ func DeleteOldSecurityEventLogsInPostgres(ctx context.Context, logger log.Logger
 		time.Sleep(time.Hour)
 
 		// Only clean up if security event logs are being stored in the database.
-		c := conf.Get()
-		if c.Log == nil || c.Log.SecurityEventLog == nil {
-			continue
-		}
-		if c.Log.SecurityEventLog.Location != "database" && c.Log.SecurityEventLog.Location != "all" {
+		c := conf.SiteConfig()
+		if loc := audit.SecurityEventLocation(c); loc != audit.Database && loc != audit.All {
 			continue
 		}
 
-		// We choose 30 days as the interval to ensure that we have at least the last month's worth of
-		// logs at all times.
-		_, err := db.ExecContext(
-			ctx,
-			`DELETE FROM security_event_logs WHERE "timestamp" < now() - interval '30' day`,
-		)
+		// Events are retained for log.securityEventLog.retentionDays, which defaults
+		// to 30 days.
+		deleted, err := db.SecurityEventLogs().DeleteOlderThan(ctx, time.Now().Add(-audit.SecurityEventRetention(c)))
 		if err != nil {
 			logger.Error("deleting expired rows from security_event_logs table", log.Error(err))
+			continue
+		}
+		if deleted > 0 {
+			logger.Debug("deleted expired rows from security_event_logs table", log.Int64("count", deleted))
 		}
 	}
 }
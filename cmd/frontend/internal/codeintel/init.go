@@ -14,6 +14,7 @@ import (
 	policiesgraphql "github.com/sourcegraph/sourcegraph/internal/codeintel/policies/transport/graphql"
 	rankinggraphql "github.com/sourcegraph/sourcegraph/internal/codeintel/ranking/transport/graphql"
 	"github.com/sourcegraph/sourcegraph/internal/codeintel/resolvers"
+	"github.com/sourcegraph/sourcegraph/internal/codeintel/sbom"
 	sentinelgraphql "github.com/sourcegraph/sourcegraph/internal/codeintel/sentinel/transport/graphql"
 	"github.com/sourcegraph/sourcegraph/internal/codeintel/shared/lsifuploadstore"
 	sharedresolvers "github.com/sourcegraph/sourcegraph/internal/codeintel/shared/resolvers"
@@ -133,6 +134,10 @@ func Init(
 		rankingRootResolver,
 	))
 	enterpriseServices.NewCodeIntelUploadHandler = newUploadHandler
+	sbomLogger := observationCtx.Logger.Scoped("sbom", "codeintel SBOM export")
+	sbomGenerator := sbom.NewGenerator(codeIntelServices.UploadsService)
+	enterpriseServices.CodeIntelSBOMHandler = newSBOMHandler(sbomLogger, db, sbomGenerator)
+	enterpriseServices.CodeIntelSBOMsHandler = newBulkSBOMHandler(sbomLogger, db, sbomGenerator)
 	enterpriseServices.RankingService = codeIntelServices.RankingService
 	return nil
 }
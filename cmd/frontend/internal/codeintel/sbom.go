@@ -0,0 +1,167 @@
+package codeintel
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"strings"
+
+	"github.com/gorilla/mux"
+	"github.com/sourcegraph/log"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/handlerutil"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/routevar"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/codeintel/sbom"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/errcode"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver/gitdomain"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// newSBOMHandler returns an HTTP handler that serves a software bill of materials for the repository
+// and revision named in the request path, built from the package references of the precise indexes
+// visible at that revision. The `format` query parameter selects CycloneDX (the default) or SPDX.
+func newSBOMHandler(logger log.Logger, db database.DB, generator *sbom.Generator) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		format, err := sbom.ParseFormat(r.URL.Query().Get("format"))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		// 🚨 SECURITY: The repository is resolved through the repository store, which enforces
+		// the repository permissions of the current actor.
+		repo, commitID, err := handlerutil.GetRepoAndRev(r.Context(), logger, db, mux.Vars(r))
+		if err != nil {
+			if isRepoRevNotFound(err) {
+				http.Error(w, "repository or revision not found", http.StatusNotFound)
+			} else if errcode.IsUnauthorized(err) {
+				w.WriteHeader(http.StatusUnauthorized)
+			} else {
+				logger.Error("failed to resolve repository", log.Error(err))
+				w.WriteHeader(http.StatusInternalServerError)
+			}
+			return
+		}
+
+		doc, err := generator.Generate(r.Context(), int(repo.ID), string(repo.Name), string(commitID))
+		if err != nil {
+			logger.Error("failed to generate SBOM", log.String("repo", string(repo.Name)), log.String("commit", string(commitID)), log.Error(err))
+			http.Error(w, "failed to generate SBOM", http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", format.ContentType())
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", fmt.Sprintf("%s-%s.%s.json", path.Base(string(repo.Name)), commitID, format)))
+		if err := sbom.Write(w, format, doc); err != nil {
+			logger.Error("failed to write SBOM", log.Error(err))
+		}
+	})
+}
+
+// maxBulkSBOMRepositories is the maximum number of repositories in a single bulk SBOM request.
+const maxBulkSBOMRepositories = 100
+
+// bulkSBOMRequest is the body of a bulk SBOM request. Each repository is named as in the path of
+// the single repository endpoint: `<repository>` or `<repository>@<revision>`.
+type bulkSBOMRequest struct {
+	Repositories []string `json:"repositories"`
+}
+
+// bulkSBOMResult is written as a line of the response of a bulk SBOM request for each requested
+// repository, in request order. Exactly one of SBOM and Error is set.
+type bulkSBOMResult struct {
+	Repository string          `json:"repository"`
+	Commit     string          `json:"commit,omitempty"`
+	SBOM       json.RawMessage `json:"sbom,omitempty"`
+	Error      string          `json:"error,omitempty"`
+}
+
+// newBulkSBOMHandler returns an HTTP handler that serves software bills of materials for each of
+// the repositories listed in the request body as newline-delimited JSON. Repositories that cannot
+// be exported are reported in their line of the response instead of failing the whole request.
+// The `format` query parameter selects CycloneDX (the default) or SPDX.
+func newBulkSBOMHandler(logger log.Logger, db database.DB, generator *sbom.Generator) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		format, err := sbom.ParseFormat(r.URL.Query().Get("format"))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		var request bulkSBOMRequest
+		if err := json.NewDecoder(io.LimitReader(r.Body, 1<<20)).Decode(&request); err != nil {
+			http.Error(w, fmt.Sprintf("invalid request body: %s", err), http.StatusBadRequest)
+			return
+		}
+		if len(request.Repositories) == 0 || len(request.Repositories) > maxBulkSBOMRepositories {
+			http.Error(w, fmt.Sprintf("between 1 and %d repositories must be requested", maxBulkSBOMRepositories), http.StatusBadRequest)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		flusher, _ := w.(http.Flusher)
+		encoder := json.NewEncoder(w)
+		for _, repository := range request.Repositories {
+			if err := encoder.Encode(generateBulkSBOM(r.Context(), logger, db, generator, format, repository)); err != nil {
+				logger.Error("failed to write SBOM", log.Error(err))
+				return
+			}
+			if flusher != nil {
+				flusher.Flush()
+			}
+		}
+	})
+}
+
+// generateBulkSBOM returns the line of a bulk SBOM response for the given repository.
+func generateBulkSBOM(ctx context.Context, logger log.Logger, db database.DB, generator *sbom.Generator, format sbom.Format, repository string) bulkSBOMResult {
+	result := bulkSBOMResult{Repository: repository}
+
+	name, rev, _ := strings.Cut(repository, "@")
+	vars := routevar.RepoRevRouteVars(routevar.RepoRev{Repo: api.RepoName(name), Rev: rev})
+
+	// 🚨 SECURITY: The repository is resolved through the repository store, which enforces
+	// the repository permissions of the current actor.
+	repo, commitID, err := handlerutil.GetRepoAndRev(ctx, logger, db, vars)
+	if err != nil {
+		if isRepoRevNotFound(err) {
+			result.Error = "repository or revision not found"
+		} else if errcode.IsUnauthorized(err) {
+			result.Error = "unauthorized"
+		} else {
+			logger.Error("failed to resolve repository", log.String("repo", repository), log.Error(err))
+			result.Error = "failed to resolve repository"
+		}
+		return result
+	}
+	result.Commit = string(commitID)
+
+	doc, err := generator.Generate(ctx, int(repo.ID), string(repo.Name), string(commitID))
+	if err != nil {
+		logger.Error("failed to generate SBOM", log.String("repo", string(repo.Name)), log.String("commit", string(commitID)), log.Error(err))
+		result.Error = "failed to generate SBOM"
+		return result
+	}
+
+	var buf bytes.Buffer
+	if err := sbom.Write(&buf, format, doc); err != nil {
+		logger.Error("failed to write SBOM", log.Error(err))
+		result.Error = "failed to write SBOM"
+		return result
+	}
+	result.SBOM = buf.Bytes()
+
+	return result
+}
+
+// isRepoRevNotFound returns true if the given error resolving a repository and revision should be
+// reported as the repository or revision not existing.
+func isRepoRevNotFound(err error) bool {
+	return errors.HasType(err, &gitdomain.RevisionNotFoundError{}) || errcode.IsNotFound(err) || errcode.IsBlocked(err)
+}
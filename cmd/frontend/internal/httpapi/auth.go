@@ -12,6 +12,7 @@ import (
 	"github.com/sourcegraph/log"
 
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/envvar"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/routevar"
 	"github.com/sourcegraph/sourcegraph/internal/actor"
 	"github.com/sourcegraph/sourcegraph/internal/audit"
 	"github.com/sourcegraph/sourcegraph/internal/auth"
@@ -20,6 +21,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/cookie"
 	"github.com/sourcegraph/sourcegraph/internal/database"
 	"github.com/sourcegraph/sourcegraph/internal/errcode"
+	"github.com/sourcegraph/sourcegraph/internal/lazyregexp"
 	"github.com/sourcegraph/sourcegraph/internal/trace"
 	"github.com/sourcegraph/sourcegraph/lib/errors"
 )
@@ -112,13 +114,18 @@ func AccessTokenAuthMiddleware(db database.DB, baseLogger log.Logger, next http.
 			//
 			// 🚨 SECURITY: It's important we check for the correct scopes to know what this token
 			// is allowed to do.
-			var requiredScope string
+			var subjectUserID int32
+			var accessTokenScopes []string
+			var err error
 			if sudoUser == "" {
-				requiredScope = authz.ScopeUserAll
+				var scopes []string
+				subjectUserID, scopes, err = db.AccessTokens().LookupScopes(r.Context(), token)
+				if err == nil {
+					accessTokenScopes, err = restrictedAccessTokenScopes(scopes)
+				}
 			} else {
-				requiredScope = authz.ScopeSiteAdminSudo
+				subjectUserID, err = db.AccessTokens().Lookup(r.Context(), token, authz.ScopeSiteAdminSudo)
 			}
-			subjectUserID, err := db.AccessTokens().Lookup(r.Context(), token, requiredScope)
 			if err != nil {
 				if err == database.ErrAccessTokenNotFound || errors.HasType(err, database.InvalidTokenError{}) {
 					anonymousId, anonCookieSet := cookie.AnonymousUID(r)
@@ -149,6 +156,23 @@ func AccessTokenAuthMiddleware(db database.DB, baseLogger log.Logger, next http.
 				return
 			}
 
+			// 🚨 SECURITY: Tokens restricted to fine-grained scopes may only be used for requests
+			// that one of their scopes permits.
+			if accessTokenScopes != nil && !accessTokenScopesPermitRequest(accessTokenScopes, r) {
+				audit.Log(r.Context(), logger, audit.Record{
+					Entity: authAuditEntity,
+					Action: "check_access_token_scopes",
+					Fields: []log.Field{
+						log.String("problem", "access token scopes do not permit request"),
+						log.Int32("subjectUserID", subjectUserID),
+						log.Strings("scopes", accessTokenScopes),
+						log.String("path", r.URL.Path),
+					},
+				})
+				http.Error(w, "The scopes of the access token do not permit this request.", http.StatusForbidden)
+				return
+			}
+
 			// FIXME: Can we find a way to do this only for SOAP users?
 			soapCount, err := db.UserExternalAccounts().Count(
 				r.Context(),
@@ -285,6 +309,7 @@ func AccessTokenAuthMiddleware(db database.DB, baseLogger log.Logger, next http.
 					&actor.Actor{
 						UID:                 actorUserID,
 						SourcegraphOperator: sourcegraphOperator,
+						AccessTokenScopes:   accessTokenScopes,
 					},
 				),
 			)
@@ -293,3 +318,67 @@ func AccessTokenAuthMiddleware(db database.DB, baseLogger log.Logger, next http.
 		next.ServeHTTP(w, r)
 	})
 }
+
+// restrictedAccessTokenScopes returns the fine-grained scopes of an access token with the given
+// scopes. If the token grants full control of the user account, nil is returned. If the token
+// grants neither full control nor any fine-grained scope, ErrAccessTokenNotFound is returned.
+func restrictedAccessTokenScopes(scopes []string) ([]string, error) {
+	restricted := []string{}
+	for _, scope := range scopes {
+		if scope == authz.ScopeUserAll {
+			return nil, nil
+		}
+		if authz.IsFineGrainedScope(scope) {
+			restricted = append(restricted, scope)
+		}
+	}
+	if len(restricted) == 0 {
+		return nil, database.ErrAccessTokenNotFound
+	}
+
+	return restricted, nil
+}
+
+// fineGrainedScopesByPathPrefix maps the path prefixes of the requests that tokens restricted to
+// fine-grained scopes may perform to the scopes that permit them. GraphQL requests are further
+// restricted by the GraphQL handler based on the selected root fields.
+var fineGrainedScopesByPathPrefix = []struct {
+	prefix string
+	scopes []string
+}{
+	{"/.api/graphql", []string{authz.ScopeSearchRead, authz.ScopeRepoRead, authz.ScopeBatchesWrite}},
+	{"/.api/search/stream", []string{authz.ScopeSearchRead}},
+	{"/.api/blame/", []string{authz.ScopeRepoRead}},
+	{"/.api/files/batch-changes/", []string{authz.ScopeBatchesWrite}},
+	{"/.api/lsif/upload", []string{authz.ScopeCodeIntelUpload}},
+	{"/.api/scip/upload", []string{authz.ScopeCodeIntelUpload}},
+}
+
+// rawRoutePattern matches the paths of the raw route of the UI router, which serves the contents of
+// repositories.
+var rawRoutePattern = lazyregexp.New("^/" + routevar.RepoPattern + `(?:@` + routevar.RevPattern + `)?/` + routevar.RepoPathDelim + `/raw(?:/|$)`)
+
+// accessTokenScopesPermitRequest returns true if one of the given fine-grained access token
+// scopes permits the given request.
+func accessTokenScopesPermitRequest(scopes []string, r *http.Request) bool {
+	var permittingScopes []string
+	if (r.Method == http.MethodGet || r.Method == http.MethodHead) && !strings.HasPrefix(r.URL.Path, "/.api/") && rawRoutePattern.MatchString(r.URL.Path) {
+		permittingScopes = []string{authz.ScopeRepoRead}
+	} else {
+		for _, p := range fineGrainedScopesByPathPrefix {
+			if strings.HasPrefix(r.URL.Path, p.prefix) {
+				permittingScopes = p.scopes
+				break
+			}
+		}
+	}
+
+	for _, permittingScope := range permittingScopes {
+		for _, scope := range scopes {
+			if scope == permittingScope {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -82,7 +82,7 @@ func TestAccessTokenAuthMiddleware(t *testing.T) {
 		req.Header.Set("Authorization", "token badbad")
 
 		accessTokens := dbmocks.NewMockAccessTokenStore()
-		accessTokens.LookupFunc.SetDefaultReturn(0, database.InvalidTokenError{})
+		accessTokens.LookupScopesFunc.SetDefaultReturn(0, nil, database.InvalidTokenError{})
 		db.AccessTokensFunc.SetDefaultReturn(accessTokens)
 
 		securityEventLogs := dbmocks.NewMockSecurityEventLogsStore()
@@ -94,29 +94,89 @@ func TestAccessTokenAuthMiddleware(t *testing.T) {
 		db.SecurityEventLogsFunc.SetDefaultReturn(securityEventLogs)
 
 		checkHTTPResponse(t, db, req, http.StatusUnauthorized, "Invalid access token.\n")
-		mockrequire.Called(t, accessTokens.LookupFunc)
+		mockrequire.Called(t, accessTokens.LookupScopesFunc)
 		mockrequire.Called(t, securityEventLogs.LogEventFunc)
 	})
 
+	t.Run("valid header with token without usable scopes", func(t *testing.T) {
+		req, _ := http.NewRequest("GET", "/", nil)
+		req.Header.Set("Authorization", "token abcdef")
+
+		accessTokens := dbmocks.NewMockAccessTokenStore()
+		accessTokens.LookupScopesFunc.SetDefaultReturn(123, []string{authz.ScopeSiteAdminSudo}, nil)
+		db.AccessTokensFunc.SetDefaultReturn(accessTokens)
+		db.SecurityEventLogsFunc.SetDefaultReturn(dbmocks.NewMockSecurityEventLogsStore())
+
+		checkHTTPResponse(t, db, req, http.StatusUnauthorized, "Invalid access token.\n")
+	})
+
+	for _, tc := range []struct {
+		name           string
+		path           string
+		scopes         []string
+		wantStatusCode int
+		wantBody       string
+	}{
+		{"search token, streaming search", "/.api/search/stream", []string{authz.ScopeSearchRead}, http.StatusOK, "user 123"},
+		{"search token, GraphQL", "/.api/graphql", []string{authz.ScopeSearchRead}, http.StatusOK, "user 123"},
+		{"upload token, SCIP upload", "/.api/scip/upload", []string{authz.ScopeCodeIntelUpload}, http.StatusOK, "user 123"},
+		{"repo token, raw file", "/github.com/foo/bar/-/raw/README.md", []string{authz.ScopeRepoRead}, http.StatusOK, "user 123"},
+		{"repo token, raw file at revision", "/github.com/foo/bar@main/-/raw/README.md", []string{authz.ScopeRepoRead}, http.StatusOK, "user 123"},
+		{"repo token, raw path in other route", "/github.com/foo/bar/-/settings/-/raw/", []string{authz.ScopeRepoRead}, http.StatusForbidden, "The scopes of the access token do not permit this request.\n"},
+		{"repo token, raw path in API route", "/.api/src-cli/-/raw/", []string{authz.ScopeRepoRead}, http.StatusForbidden, "The scopes of the access token do not permit this request.\n"},
+		{"repo token, raw path in query", "/site-admin?p=/-/raw/", []string{authz.ScopeRepoRead}, http.StatusForbidden, "The scopes of the access token do not permit this request.\n"},
+		{"upload token, GraphQL", "/.api/graphql", []string{authz.ScopeCodeIntelUpload}, http.StatusForbidden, "The scopes of the access token do not permit this request.\n"},
+		{"search token, SCIP upload", "/.api/scip/upload", []string{authz.ScopeSearchRead}, http.StatusForbidden, "The scopes of the access token do not permit this request.\n"},
+		{"search token, unknown path", "/.api/src-cli/versions/latest", []string{authz.ScopeSearchRead, authz.ScopeRepoRead}, http.StatusForbidden, "The scopes of the access token do not permit this request.\n"},
+	} {
+		t.Run("fine-grained token: "+tc.name, func(t *testing.T) {
+			req, _ := http.NewRequest("GET", tc.path, nil)
+			req.Header.Set("Authorization", "token abcdef")
+
+			accessTokens := dbmocks.NewMockAccessTokenStore()
+			accessTokens.LookupScopesFunc.SetDefaultReturn(123, tc.scopes, nil)
+			db.AccessTokensFunc.SetDefaultReturn(accessTokens)
+
+			checkHTTPResponse(t, db, req, tc.wantStatusCode, tc.wantBody)
+		})
+	}
+
+	t.Run("fine-grained token scopes are attached to actor", func(t *testing.T) {
+		req, _ := http.NewRequest("GET", "/.api/graphql", nil)
+		req.Header.Set("Authorization", "token abcdef")
+
+		accessTokens := dbmocks.NewMockAccessTokenStore()
+		accessTokens.LookupScopesFunc.SetDefaultReturn(123, []string{authz.ScopeRepoRead, authz.ScopeSearchRead}, nil)
+		db.AccessTokensFunc.SetDefaultReturn(accessTokens)
+
+		var gotActor *sgactor.Actor
+		handler := AccessTokenAuthMiddleware(db, logtest.NoOp(t), http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			gotActor = sgactor.FromContext(r.Context())
+		}))
+		handler.ServeHTTP(httptest.NewRecorder(), req)
+
+		require.NotNil(t, gotActor)
+		require.True(t, gotActor.IsScopeRestricted())
+		require.True(t, gotActor.HasAccessTokenScope(authz.ScopeSearchRead))
+		require.False(t, gotActor.HasAccessTokenScope(authz.ScopeBatchesWrite))
+	})
+
 	for _, headerValue := range []string{"token abcdef", `token token="abcdef"`} {
 		t.Run("valid non-sudo token: "+headerValue, func(t *testing.T) {
 			req, _ := http.NewRequest("GET", "/", nil)
 			req.Header.Set("Authorization", headerValue)
 
 			accessTokens := dbmocks.NewMockAccessTokenStore()
-			accessTokens.LookupFunc.SetDefaultHook(func(_ context.Context, tokenHexEncoded, requiredScope string) (subjectUserID int32, err error) {
+			accessTokens.LookupScopesFunc.SetDefaultHook(func(_ context.Context, tokenHexEncoded string) (subjectUserID int32, scopes []string, err error) {
 				if want := "abcdef"; tokenHexEncoded != want {
 					t.Errorf("got %q, want %q", tokenHexEncoded, want)
 				}
-				if want := authz.ScopeUserAll; requiredScope != want {
-					t.Errorf("got %q, want %q", requiredScope, want)
-				}
-				return 123, nil
+				return 123, []string{authz.ScopeUserAll}, nil
 			})
 			db.AccessTokensFunc.SetDefaultReturn(accessTokens)
 
 			checkHTTPResponse(t, db, req, http.StatusOK, "user 123")
-			mockrequire.Called(t, accessTokens.LookupFunc)
+			mockrequire.Called(t, accessTokens.LookupScopesFunc)
 		})
 	}
 
@@ -127,19 +187,16 @@ func TestAccessTokenAuthMiddleware(t *testing.T) {
 		req = req.WithContext(sgactor.WithActor(context.Background(), &sgactor.Actor{UID: 456}))
 
 		accessTokens := dbmocks.NewMockAccessTokenStore()
-		accessTokens.LookupFunc.SetDefaultHook(func(_ context.Context, tokenHexEncoded, requiredScope string) (subjectUserID int32, err error) {
+		accessTokens.LookupScopesFunc.SetDefaultHook(func(_ context.Context, tokenHexEncoded string) (subjectUserID int32, scopes []string, err error) {
 			if want := "abcdef"; tokenHexEncoded != want {
 				t.Errorf("got %q, want %q", tokenHexEncoded, want)
 			}
-			if want := authz.ScopeUserAll; requiredScope != want {
-				t.Errorf("got %q, want %q", requiredScope, want)
-			}
-			return 123, nil
+			return 123, []string{authz.ScopeUserAll}, nil
 		})
 		db.AccessTokensFunc.SetDefaultReturn(accessTokens)
 
 		checkHTTPResponse(t, db, req, http.StatusOK, "user 123")
-		mockrequire.Called(t, accessTokens.LookupFunc)
+		mockrequire.Called(t, accessTokens.LookupScopesFunc)
 	})
 
 	// Test that an access token overwrites the actor set by a prior auth middleware.
@@ -160,19 +217,16 @@ func TestAccessTokenAuthMiddleware(t *testing.T) {
 			req = req.WithContext(sgactor.WithActor(context.Background(), &sgactor.Actor{UID: 456}))
 
 			accessTokens := dbmocks.NewMockAccessTokenStore()
-			accessTokens.LookupFunc.SetDefaultHook(func(_ context.Context, tokenHexEncoded, requiredScope string) (subjectUserID int32, err error) {
+			accessTokens.LookupScopesFunc.SetDefaultHook(func(_ context.Context, tokenHexEncoded string) (subjectUserID int32, scopes []string, err error) {
 				if want := "abcdef"; tokenHexEncoded != want {
 					t.Errorf("got %q, want %q", tokenHexEncoded, want)
 				}
-				if want := authz.ScopeUserAll; requiredScope != want {
-					t.Errorf("got %q, want %q", requiredScope, want)
-				}
-				return 123, nil
+				return 123, []string{authz.ScopeUserAll}, nil
 			})
 			db.AccessTokensFunc.SetDefaultReturn(accessTokens)
 
 			checkHTTPResponse(t, db, req, http.StatusOK, "user 123")
-			mockrequire.Called(t, accessTokens.LookupFunc)
+			mockrequire.Called(t, accessTokens.LookupScopesFunc)
 		})
 	}
 
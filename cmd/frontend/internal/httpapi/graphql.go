@@ -76,6 +76,20 @@ func serveGraphQL(logger log.Logger, schema *graphql.Schema, rlw graphqlbackend.
 		traceData.uid = uid
 		traceData.anonymous = anonymous
 
+		// 🚨 SECURITY: Actors authenticated with an access token that is restricted to
+		// fine-grained scopes may only select the root fields permitted by those scopes.
+		if err := graphqlbackend.CheckAccessTokenScopes(r.Context(), params.Query, params.OperationName); err != nil {
+			responseJSON, err := json.Marshal(graphql.Response{Errors: []*gqlerrors.QueryError{gqlerrors.Errorf("%s", err)}})
+			if err != nil {
+				return errors.Wrap(err, "failed to marshal GraphQL response")
+			}
+
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write(responseJSON)
+			return nil
+		}
+
 		validationErrs := schema.ValidateWithVariables(params.Query, params.Variables)
 
 		var cost *graphqlbackend.QueryCost
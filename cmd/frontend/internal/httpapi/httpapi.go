@@ -67,6 +67,8 @@ type Handlers struct {
 
 	// Code intel
 	NewCodeIntelUploadHandler enterprise.NewCodeIntelUploadHandler
+	CodeIntelSBOMHandler      http.Handler
+	CodeIntelSBOMsHandler     http.Handler
 
 	// Compute
 	NewComputeStreamHandler enterprise.NewComputeStreamHandler
@@ -162,6 +164,8 @@ func NewHandler(
 	m.Get(apirouter.CodeCompletions).Handler(trace.Route(handlers.NewCodeCompletionsHandler()))
 
 	m.Get(apirouter.CodeInsightsDataExport).Handler(trace.Route(handlers.CodeInsightsDataExportHandler))
+	m.Get(apirouter.CodeIntelSBOM).Handler(trace.Route(handlers.CodeIntelSBOMHandler))
+	m.Get(apirouter.CodeIntelSBOMs).Handler(trace.Route(handlers.CodeIntelSBOMsHandler))
 
 	if envvar.SourcegraphDotComMode() {
 		m.Path("/app/check/update").Name(codyapp.RouteAppUpdateCheck).Handler(trace.Route(codyapp.AppUpdateHandler(logger)))
@@ -44,6 +44,9 @@ const (
 
 	CodeInsightsDataExport = "insights.data.export"
 
+	CodeIntelSBOM  = "codeintel.sbom"
+	CodeIntelSBOMs = "codeintel.sboms"
+
 	GitInfoRefs         = "internal.git.info-refs"
 	GitUploadPack       = "internal.git.upload-pack"
 	ReposIndex          = "internal.repos.index"
@@ -86,6 +89,8 @@ func New(base *mux.Router) *mux.Router {
 	base.Path("/src-cli/versions/{rest:.*}").Methods("GET", "POST").Name(SrcCliVersionCache)
 	base.Path("/src-cli/{rest:.*}").Methods("GET").Name(SrcCli)
 	base.Path("/insights/export/{id}").Methods("GET").Name(CodeInsightsDataExport)
+	base.Path("/codeintel/sbom/" + routevar.Repo + routevar.RepoRevSuffix).Methods("GET").Name(CodeIntelSBOM)
+	base.Path("/codeintel/sboms").Methods("POST").Name(CodeIntelSBOMs)
 	base.Path("/completions/stream").Methods("POST").Name(ChatCompletionsStream)
 	base.Path("/completions/code").Methods("POST").Name(CodeCompletions)
 
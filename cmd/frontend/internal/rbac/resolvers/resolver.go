@@ -5,16 +5,20 @@ import (
 	"encoding/json"
 	"fmt"
 
+	"github.com/graph-gophers/graphql-go/relay"
 	"github.com/sourcegraph/log"
 
 	gql "github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend"
 	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/api"
 	"github.com/sourcegraph/sourcegraph/internal/auth"
 	"github.com/sourcegraph/sourcegraph/internal/database"
 	"github.com/sourcegraph/sourcegraph/internal/deviceid"
 	"github.com/sourcegraph/sourcegraph/internal/featureflag"
+	"github.com/sourcegraph/sourcegraph/internal/search/searchcontexts"
 	"github.com/sourcegraph/sourcegraph/internal/types"
 	"github.com/sourcegraph/sourcegraph/internal/usagestats"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
 )
 
 // Resolver is the GraphQL resolver of all things related to batch changes.
@@ -37,6 +41,14 @@ type setRolesEventArgs struct {
 	RoleIDs []int32 `json:"role_ids"`
 }
 
+type scopedRoleEventArgs struct {
+	UserID          int32      `json:"user_id"`
+	RoleID          int32      `json:"role_id"`
+	OrgID           int32      `json:"org_id,omitempty"`
+	SearchContextID int64      `json:"search_context_id,omitempty"`
+	RepoID          api.RepoID `json:"repo_id,omitempty"`
+}
+
 func New(logger log.Logger, db database.DB) gql.RBACResolver {
 	return &Resolver{logger: logger, db: db}
 }
@@ -172,6 +184,90 @@ func (r *Resolver) SetRoles(ctx context.Context, args *gql.SetRolesArgs) (*gql.E
 	return &gql.EmptyResponse{}, nil
 }
 
+func (r *Resolver) AssignScopedRole(ctx context.Context, args *gql.ScopedRoleArgs) (*gql.EmptyResponse, error) {
+	// 🚨 SECURITY: Only site administrators can assign roles to a user.
+	if err := auth.CheckCurrentUserIsSiteAdmin(ctx, r.db); err != nil {
+		return nil, err
+	}
+
+	opts, err := r.scopedUserRoleOpts(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.db.ScopedUserRoles().Assign(ctx, database.AssignScopedUserRoleOpts(opts)); err != nil {
+		return nil, err
+	}
+
+	r.logBackendEvent(ctx, "ScopedUserRoleAssigned", newScopedRoleEventArgs(opts))
+	return &gql.EmptyResponse{}, nil
+}
+
+func (r *Resolver) RevokeScopedRole(ctx context.Context, args *gql.ScopedRoleArgs) (*gql.EmptyResponse, error) {
+	// 🚨 SECURITY: Only site administrators can revoke roles from a user.
+	if err := auth.CheckCurrentUserIsSiteAdmin(ctx, r.db); err != nil {
+		return nil, err
+	}
+
+	opts, err := r.scopedUserRoleOpts(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.db.ScopedUserRoles().Revoke(ctx, database.RevokeScopedUserRoleOpts(opts)); err != nil {
+		return nil, err
+	}
+
+	r.logBackendEvent(ctx, "ScopedUserRoleRevoked", newScopedRoleEventArgs(opts))
+	return &gql.EmptyResponse{}, nil
+}
+
+func (r *Resolver) scopedUserRoleOpts(ctx context.Context, args *gql.ScopedRoleArgs) (opts database.ScopedUserRoleOpts, err error) {
+	if opts.UserID, err = gql.UnmarshalUserID(args.User); err != nil {
+		return opts, err
+	}
+	if opts.RoleID, err = gql.UnmarshalRoleID(args.Role); err != nil {
+		return opts, err
+	}
+
+	if args.Scope.Organization != nil {
+		if opts.Scope.OrgID, err = gql.UnmarshalOrgID(*args.Scope.Organization); err != nil {
+			return opts, err
+		}
+	}
+	if args.Scope.SearchContext != nil {
+		var spec string
+		if err := relay.UnmarshalSpec(*args.Scope.SearchContext, &spec); err != nil {
+			return opts, err
+		}
+		sc, err := searchcontexts.ResolveSearchContextSpec(ctx, r.db, spec)
+		if err != nil {
+			return opts, err
+		}
+		if sc.ID == 0 {
+			return opts, errors.New("roles cannot be scoped to the global search context")
+		}
+		opts.Scope.SearchContextID = sc.ID
+	}
+	if args.Scope.Repository != nil {
+		if opts.Scope.RepoID, err = gql.UnmarshalRepositoryID(*args.Scope.Repository); err != nil {
+			return opts, err
+		}
+	}
+
+	return opts, nil
+}
+
+func newScopedRoleEventArgs(opts database.ScopedUserRoleOpts) *scopedRoleEventArgs {
+	return &scopedRoleEventArgs{
+		UserID:          opts.UserID,
+		RoleID:          opts.RoleID,
+		OrgID:           opts.Scope.OrgID,
+		SearchContextID: opts.Scope.SearchContextID,
+		RepoID:          opts.Scope.RepoID,
+	}
+}
+
 func (r *Resolver) logBackendEvent(ctx context.Context, eventName string, args any) {
 	a := actor.FromContext(ctx)
 	if a.IsAuthenticated() && !a.IsMockUser() {
@@ -2,6 +2,7 @@ package search
 
 import (
 	"github.com/sourcegraph/sourcegraph/internal/search"
+	"github.com/sourcegraph/sourcegraph/internal/search/job/printer"
 	"github.com/sourcegraph/sourcegraph/internal/search/streaming"
 	"github.com/sourcegraph/sourcegraph/internal/search/streaming/api"
 	streamhttp "github.com/sourcegraph/sourcegraph/internal/search/streaming/http"
@@ -47,6 +48,10 @@ func (e *eventWriter) Filters(fs []*streaming.Filter) error {
 	return nil
 }
 
+func (e *eventWriter) Explain(explained *printer.ExplainedJob) error {
+	return e.inner.Event("explain", explained)
+}
+
 func (e *eventWriter) Error(err error) error {
 	return e.inner.Event("error", streamhttp.EventError{Message: err.Error()})
 }
@@ -0,0 +1,181 @@
+package search
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/sourcegraph/log"
+
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/search"
+	"github.com/sourcegraph/sourcegraph/internal/search/client"
+	"github.com/sourcegraph/sourcegraph/internal/search/result"
+	"github.com/sourcegraph/sourcegraph/internal/search/streaming"
+	streamclient "github.com/sourcegraph/sourcegraph/internal/search/streaming/client"
+	streamhttp "github.com/sourcegraph/sourcegraph/internal/search/streaming/http"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+	"github.com/sourcegraph/sourcegraph/lib/pointers"
+)
+
+// formatSARIF is the value of the "format" URL parameter which requests
+// results as a SARIF log instead of an event stream.
+const formatSARIF = "sarif"
+
+// serveSARIF runs the search and writes the results as a SARIF 2.1.0 log, so
+// that they can be ingested by code scanning tools. Only file matches are
+// included since SARIF results are locations in files.
+func (h *streamHandler) serveSARIF(w http.ResponseWriter, r *http.Request) (err error) {
+	ctx := r.Context()
+	start := time.Now()
+
+	args, err := parseURLQuery(r.URL.Query())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return err
+	}
+
+	inputs, err := h.searchClient.Plan(
+		ctx,
+		args.Version,
+		pointers.NonZeroPtr(args.PatternType),
+		args.Query,
+		search.Mode(args.SearchMode),
+		search.Streaming,
+	)
+	if err != nil {
+		var queryErr *client.QueryError
+		if errors.As(err, &queryErr) {
+			http.Error(w, queryErr.Err.Error(), http.StatusBadRequest)
+		} else {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+		return err
+	}
+
+	inputs.Cursor = args.Cursor
+
+	displayLimit := args.Display
+	limit := inputs.MaxResults()
+	if displayLimit < 0 || displayLimit > limit {
+		displayLimit = limit
+	}
+
+	progress := &streamclient.ProgressAggregator{
+		Start:        start,
+		Limit:        limit,
+		DisplayLimit: displayLimit,
+		RepoNamer:    streamclient.RepoNamer(ctx, h.db),
+	}
+
+	w.Header().Set("Content-Type", streamhttp.SARIFContentType)
+	w.Header().Set("Content-Disposition", `attachment; filename="sourcegraph-search.sarif"`)
+	w.WriteHeader(http.StatusOK)
+
+	writer := streamhttp.NewSARIFWriter(w, sarifRule(args))
+	stream := &sarifStream{
+		ctx:              ctx,
+		logger:           h.logger,
+		db:               h.db,
+		writer:           writer,
+		progress:         progress,
+		ruleID:           sarifRuleID(args),
+		displayRemaining: displayLimit,
+	}
+
+	alert, err := h.searchClient.Execute(ctx, stream, inputs)
+	if alert != nil {
+		writer.Notify("warning", alert.Title+": "+alert.Description)
+	}
+	if err != nil {
+		writer.Notify("error", err.Error())
+	}
+	if closeErr := writer.Close(); closeErr != nil {
+		h.logger.Warn("failed to write SARIF log", log.Error(closeErr))
+	}
+
+	logSearch(ctx, h.logger, alert, err, time.Since(start), nil, inputs.OriginalQuery, progress)
+	return err
+}
+
+// sarifRuleID returns the ID of the SARIF rule the results are reported for.
+// Callers can name the rule with the "rule" URL parameter, otherwise the query
+// is used.
+func sarifRuleID(args *args) string {
+	if args.Rule != "" {
+		return args.Rule
+	}
+	return args.Query
+}
+
+func sarifRule(args *args) streamhttp.SARIFRule {
+	rule := streamhttp.SARIFRule{
+		ID:               sarifRuleID(args),
+		Name:             args.Rule,
+		ShortDescription: &streamhttp.SARIFMessage{Text: args.Query},
+		Properties:       map[string]any{"query": args.Query},
+	}
+
+	if externalURL, err := url.Parse(conf.ExternalURL()); err == nil {
+		u := externalURL.ResolveReference(&url.URL{Path: "search"})
+		q := u.Query()
+		q.Set("q", args.Query)
+		u.RawQuery = q.Encode()
+		rule.HelpURI = u.String()
+	}
+
+	return rule
+}
+
+// sarifStream is a streaming.Sender which writes file matches to a SARIF log.
+type sarifStream struct {
+	ctx      context.Context
+	logger   log.Logger
+	db       database.DB
+	ruleID   string
+	writer   *streamhttp.SARIFWriter
+	progress *streamclient.ProgressAggregator
+
+	// Everything below this line is protected by the mutex
+	mu               sync.Mutex
+	displayRemaining int
+}
+
+func (s *sarifStream) Send(event streaming.SearchEvent) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.progress.Update(event)
+	s.displayRemaining = event.Results.Limit(s.displayRemaining)
+
+	repoMetadata, err := getEventRepoMetadata(s.ctx, s.db, event)
+	if err != nil {
+		if !errors.IsContextCanceled(err) {
+			s.logger.Error("failed to get repo metadata", log.Error(err))
+		}
+		return
+	}
+
+	for _, match := range event.Results {
+		fm, ok := match.(*result.FileMatch)
+		if !ok {
+			continue
+		}
+
+		// Don't send matches which we cannot map to a repo the actor has access to.
+		repo := match.RepoName()
+		if md, ok := repoMetadata[repo.ID]; !ok || md.Name != repo.Name {
+			continue
+		}
+
+		if err := s.writer.Write(streamhttp.SARIFResultsFromFileMatch(fm, s.ruleID)...); err != nil {
+			if !errors.IsContextCanceled(err) {
+				s.logger.Warn("failed to write SARIF results", log.Error(err))
+			}
+			return
+		}
+	}
+}
@@ -27,6 +27,8 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/lazyregexp"
 	"github.com/sourcegraph/sourcegraph/internal/search"
 	"github.com/sourcegraph/sourcegraph/internal/search/client"
+	"github.com/sourcegraph/sourcegraph/internal/search/job"
+	"github.com/sourcegraph/sourcegraph/internal/search/job/printer"
 	"github.com/sourcegraph/sourcegraph/internal/search/result"
 	"github.com/sourcegraph/sourcegraph/internal/search/streaming"
 	streamclient "github.com/sourcegraph/sourcegraph/internal/search/streaming/client"
@@ -62,6 +64,13 @@ func (h *streamHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	defer tr.End()
 	r = r.WithContext(ctx)
 
+	if r.URL.Query().Get("format") == formatSARIF {
+		if err := h.serveSARIF(w, r); err != nil {
+			tr.SetError(err)
+		}
+		return
+	}
+
 	streamWriter, err := streamhttp.NewWriter(w)
 	if err != nil {
 		tr.SetError(err)
@@ -115,6 +124,13 @@ func (h *streamHandler) serveHTTP(r *http.Request, tr trace.Trace, eventWriter *
 		}
 	}
 
+	inputs.Cursor = args.Cursor
+
+	var explain *job.Explain
+	if args.Explain || inputs.Query.IsExplain() {
+		ctx, explain = job.WithExplain(ctx)
+	}
+
 	// Display is the number of results we send down. If display is < 0 we
 	// want to send everything we find before hitting a limit. Otherwise we
 	// can only send up to limit results.
@@ -160,6 +176,7 @@ func (h *streamHandler) serveHTTP(r *http.Request, tr trace.Trace, eventWriter *
 			h.pingTickerInterval,
 			displayLimit,
 			args.EnableChunkMatches,
+			streaming.NewCursorTracker(args.Query, displayLimit),
 			logLatency,
 		)
 		defer eventHandler.Done()
@@ -172,6 +189,11 @@ func (h *streamHandler) serveHTTP(r *http.Request, tr trace.Trace, eventWriter *
 	if alert != nil {
 		eventWriter.Alert(alert)
 	}
+	if explain != nil {
+		if root := explain.Root(); root != nil {
+			eventWriter.Explain(printer.Explain(root))
+		}
+	}
 	logSearch(ctx, h.logger, alert, err, time.Since(start), latency, inputs.OriginalQuery, progress)
 	return err
 }
@@ -216,6 +238,16 @@ type args struct {
 	Display            int
 	EnableChunkMatches bool
 	SearchMode         int
+
+	// Format is the output format. The default is an event stream, "sarif"
+	// returns a SARIF log instead.
+	Format string
+	// Rule names the SARIF rule the results are reported for.
+	Rule string
+	// Cursor is the position to resume a previous search from.
+	Cursor *search.Cursor
+	// Explain requests the executed job tree with per-job timings.
+	Explain bool
 }
 
 func parseURLQuery(q url.Values) (*args, error) {
@@ -231,12 +263,18 @@ func parseURLQuery(q url.Values) (*args, error) {
 		Query:       get("q", ""),
 		Version:     get("v", "V3"),
 		PatternType: get("t", ""),
+		Format:      get("format", ""),
+		Rule:        get("rule", ""),
 	}
 
 	if a.Query == "" {
 		return nil, errors.New("no query found")
 	}
 
+	if a.Format != "" && a.Format != formatSARIF {
+		return nil, errors.Errorf("unsupported format %q", a.Format)
+	}
+
 	display := get("display", "-1")
 	var err error
 	if a.Display, err = strconv.Atoi(display); err != nil {
@@ -253,6 +291,20 @@ func parseURLQuery(q url.Values) (*args, error) {
 		return nil, errors.Errorf("search mode must be integer, got %q: %w", searchMode, err)
 	}
 
+	explain := get("explain", "f")
+	if a.Explain, err = strconv.ParseBool(explain); err != nil {
+		return nil, errors.Errorf("explain must be parseable as a boolean, got %q: %w", explain, err)
+	}
+
+	if cursor := get("cursor", ""); cursor != "" {
+		if a.Cursor, err = search.DecodeCursor(cursor); err != nil {
+			return nil, err
+		}
+		if !a.Cursor.Matches(a.Query) {
+			return nil, errors.New("cursor was created for a different query")
+		}
+	}
+
 	return &a, nil
 }
 
@@ -589,6 +641,7 @@ func newEventHandler(
 	progressInterval time.Duration,
 	displayLimit int,
 	enableChunkMatches bool,
+	cursor *streaming.CursorTracker,
 	logLatency func(),
 ) *eventHandler {
 	// Store marshalled matches and flush periodically or when we go over
@@ -607,6 +660,7 @@ func newEventHandler(
 		filters:            &streaming.SearchFilters{},
 		flushInterval:      flushInterval,
 		progress:           progress,
+		cursor:             cursor,
 		progressInterval:   progressInterval,
 		displayRemaining:   displayLimit,
 		enableChunkMatches: enableChunkMatches,
@@ -645,6 +699,7 @@ type eventHandler struct {
 	matchesBuf *streamhttp.JSONArrayBuf
 	filters    *streaming.SearchFilters
 	progress   *streamclient.ProgressAggregator
+	cursor     *streaming.CursorTracker
 
 	// These timers will be non-nil unless Done() was called
 	flushTimer    *time.Timer
@@ -660,6 +715,7 @@ func (h *eventHandler) Send(event streaming.SearchEvent) {
 
 	h.progress.Update(event)
 	h.filters.Update(event)
+	h.cursor.Update(event)
 
 	h.displayRemaining = event.Results.Limit(h.displayRemaining)
 
@@ -708,7 +764,11 @@ func (h *eventHandler) Done() {
 	// Flush the final state
 	h.eventWriter.Filters(h.filters.Compute())
 	h.matchesBuf.Flush()
-	h.eventWriter.Progress(h.progress.Final())
+	final := h.progress.Final()
+	if cursor := h.cursor.Cursor(); cursor != nil {
+		final.Cursor = cursor.Encode()
+	}
+	h.eventWriter.Progress(final)
 }
 
 func (h *eventHandler) progressTick() {
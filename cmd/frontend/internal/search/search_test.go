@@ -2,6 +2,7 @@ package search
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
@@ -128,6 +129,69 @@ func TestServeStream_chunkMatches(t *testing.T) {
 	require.Len(t, chunkMatches[0].Ranges, 1)
 }
 
+func TestServeStream_sarif(t *testing.T) {
+	settings.MockCurrentUserFinal = &schema.Settings{}
+	t.Cleanup(func() { settings.MockCurrentUserFinal = nil })
+
+	mock := client.NewMockSearchClient()
+	mock.PlanFunc.SetDefaultReturn(&search.Inputs{Query: query.Q{query.Parameter{Field: "count", Value: "1000"}}}, nil)
+	mock.ExecuteFunc.SetDefaultHook(func(_ context.Context, s streaming.Sender, _ *search.Inputs) (*search.Alert, error) {
+		s.Send(streaming.SearchEvent{
+			Results: result.Matches{
+				&result.FileMatch{
+					File: result.File{Path: "testpath"},
+					ChunkMatches: result.ChunkMatches{{
+						Content: "line1",
+						Ranges: result.Ranges{{
+							Start: result.Location{0, 0, 0},
+							End:   result.Location{1, 0, 1},
+						}},
+					}},
+				},
+				&result.RepoMatch{},
+			},
+		})
+		return nil, nil
+	})
+
+	mockRepos := dbmocks.NewMockRepoStore()
+	mockRepos.MetadataFunc.SetDefaultHook(func(_ context.Context, ids ...api2.RepoID) ([]*types.SearchedRepo, error) {
+		out := make([]*types.SearchedRepo, 0, len(ids))
+		for _, id := range ids {
+			out = append(out, &types.SearchedRepo{ID: id})
+		}
+		return out, nil
+	})
+
+	db := dbmocks.NewMockDB()
+	db.ReposFunc.SetDefaultReturn(mockRepos)
+
+	ts := httptest.NewServer(&streamHandler{
+		logger:              logtest.Scoped(t),
+		db:                  db,
+		flushTickerInternal: 1 * time.Millisecond,
+		pingTickerInterval:  1 * time.Millisecond,
+		searchClient:        mock,
+	})
+	defer ts.Close()
+
+	res, err := http.Get(ts.URL + "?q=test&format=sarif&rule=my-rule")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Body.Close()
+
+	require.Equal(t, http.StatusOK, res.StatusCode)
+	require.Equal(t, streamhttp.SARIFContentType, res.Header.Get("Content-Type"))
+
+	var log streamhttp.SARIFLog
+	require.NoError(t, json.NewDecoder(res.Body).Decode(&log))
+	require.Len(t, log.Runs, 1)
+	require.Equal(t, "my-rule", log.Runs[0].Tool.Driver.Rules[0].ID)
+	require.Len(t, log.Runs[0].Results, 1)
+	require.Equal(t, "testpath", log.Runs[0].Results[0].Locations[0].PhysicalLocation.ArtifactLocation.URI)
+}
+
 func TestDisplayLimit(t *testing.T) {
 	cases := []struct {
 		queryString         string
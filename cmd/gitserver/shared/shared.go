@@ -24,6 +24,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/cmd/gitserver/internal/vcssyncer"
 	"github.com/sourcegraph/sourcegraph/internal/actor"
 	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/audit"
 	"github.com/sourcegraph/sourcegraph/internal/authz"
 	"github.com/sourcegraph/sourcegraph/internal/authz/subrepoperms"
 	"github.com/sourcegraph/sourcegraph/internal/codeintel/dependencies"
@@ -144,6 +145,7 @@ func Main(ctx context.Context, observationCtx *observation.Context, ready servic
 			Handler: handler,
 		}),
 		gitserver.NewClonePipeline(logger, cloneQueue),
+		audit.NewOutboxRoutine(logger, db.AuditLogOutbox()),
 		server.NewRepoStateSyncer(
 			ctx,
 			logger,
@@ -14,7 +14,9 @@ import (
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/envvar"
 	"github.com/sourcegraph/sourcegraph/internal/api"
 	"github.com/sourcegraph/sourcegraph/internal/authz"
+	"github.com/sourcegraph/sourcegraph/internal/authz/groupmappings"
 	"github.com/sourcegraph/sourcegraph/internal/collections"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
 	"github.com/sourcegraph/sourcegraph/internal/database"
 	"github.com/sourcegraph/sourcegraph/internal/errcode"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc"
@@ -292,7 +294,7 @@ func (s *PermsSyncer) syncUserPerms(ctx context.Context, userID int32, noPerms b
 	repoIDs := collections.Set[int32]{}
 	result := &database.SetPermissionsResult{}
 	for acctID, rp := range results.repoPerms {
-		stats, err := s.saveUserPermsForAccount(ctx, userID, acctID, rp)
+		stats, err := s.saveUserPermsForAccount(ctx, userID, acctID, rp, authz.SourceUserSync)
 		if err != nil {
 			return result, providerStates, errors.Wrapf(err, "set user repo permissions for user %q (id: %d, external_account_id: %d)", user.Username, user.ID, acctID)
 		}
@@ -302,6 +304,17 @@ func (s *PermsSyncer) syncUserPerms(ctx context.Context, userID int32, noPerms b
 
 		repoIDs.Add(rp...)
 	}
+	for acctID, rp := range results.groupRepoPerms {
+		stats, err := s.saveUserPermsForAccount(ctx, userID, acctID, rp, authz.SourceGroupSync)
+		if err != nil {
+			return result, providerStates, errors.Wrapf(err, "set group repo permissions for user %q (id: %d, external_account_id: %d)", user.Username, user.ID, acctID)
+		}
+		result.Added += stats.Added
+		result.Found += stats.Found
+		result.Removed += stats.Removed
+
+		repoIDs.Add(rp...)
+	}
 
 	// Set sub-repository permissions.
 	srp := s.db.SubRepoPerms()
@@ -364,6 +377,10 @@ type fetchUserPermsViaExternalAccountsResults struct {
 	// A map from external account ID to a list of repository IDs. This stores the
 	// repository IDs that the user has access to for each external account.
 	repoPerms map[int32][]int32
+	// A map from external account ID to a list of repository IDs granted by the
+	// groups of the external account, see the "permissions.groupMappings" site
+	// configuration.
+	groupRepoPerms map[int32][]int32
 	// A map from external repository spec to sub-repository permissions. This stores
 	// the permissions for sub-repositories of private repositories.
 	subRepoPerms map[api.ExternalRepoSpec]*authz.SubRepoPermissions
@@ -469,12 +486,31 @@ func (s *PermsSyncer) fetchUserPermsViaExternalAccounts(ctx context.Context, use
 
 	results.subRepoPerms = make(map[api.ExternalRepoSpec]*authz.SubRepoPermissions)
 	results.repoPerms = make(map[int32][]int32, len(accts))
+	results.groupRepoPerms = make(map[int32][]int32)
+
+	groupMappings, mappingsErr := groupmappings.FromConfig(conf.Get().PermissionsGroupMappings)
+	if mappingsErr != nil {
+		logger.Warn("invalid group mappings, skipping permissions granted by groups", log.Error(mappingsErr))
+	}
 
 	for _, acct := range accts {
 		var repoSpecs, includeContainsSpecs, excludeContainsSpecs []api.ExternalRepoSpec
 
 		acctLogger := logger.With(log.Int32("acct.ID", acct.ID))
 
+		// Permissions granted by groups are computed even without any group mappings
+		// configured, so that the permissions of removed mappings are revoked.
+		if groupMappings != nil && groupmappings.SupportsAccount(acct) {
+			groups, err := groupmappings.AccountGroups(ctx, acct)
+			if err != nil {
+				return results, errors.Wrapf(err, "get groups of external account %d", acct.ID)
+			}
+			results.groupRepoPerms[acct.ID], err = groupMappings.ListRepoIDs(ctx, s.reposStore.RepoStore(), groups)
+			if err != nil {
+				return results, err
+			}
+		}
+
 		provider := byServiceID[acct.ServiceID]
 		if provider == nil {
 			// We have no authz provider configured for this external account.
@@ -685,7 +721,7 @@ func (s *PermsSyncer) listPrivateRepoNamesBySpecs(ctx context.Context, repoSpecs
 	return repoNames, nil
 }
 
-func (s *PermsSyncer) saveUserPermsForAccount(ctx context.Context, userID int32, acctID int32, repoIDs []int32) (*database.SetPermissionsResult, error) {
+func (s *PermsSyncer) saveUserPermsForAccount(ctx context.Context, userID int32, acctID int32, repoIDs []int32, source authz.PermsSource) (*database.SetPermissionsResult, error) {
 	logger := s.logger.Scoped("saveUserPermsForAccount", "saves permissions per external account").With(
 		log.Object("user",
 			log.Int32("ID", userID),
@@ -700,7 +736,7 @@ func (s *PermsSyncer) saveUserPermsForAccount(ctx context.Context, userID int32,
 	stats, err := s.permsStore.SetUserExternalAccountPerms(ctx, authz.UserIDWithExternalAccountID{
 		UserID:            userID,
 		ExternalAccountID: acctID,
-	}, repoIDs, authz.SourceUserSync)
+	}, repoIDs, source)
 	if err != nil {
 		logger.Warn("saving perms to DB", log.Error(err))
 		return nil, err
@@ -14,6 +14,7 @@ import (
 
 	"github.com/sourcegraph/sourcegraph/internal/api"
 	"github.com/sourcegraph/sourcegraph/internal/authz"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
 	"github.com/sourcegraph/sourcegraph/internal/database"
 	"github.com/sourcegraph/sourcegraph/internal/database/dbmocks"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc"
@@ -23,6 +24,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/timeutil"
 	"github.com/sourcegraph/sourcegraph/internal/types"
 	"github.com/sourcegraph/sourcegraph/lib/errors"
+	"github.com/sourcegraph/sourcegraph/schema"
 )
 
 type mockProvider struct {
@@ -151,6 +153,75 @@ func TestPermsSyncer_syncUserPerms(t *testing.T) {
 	}}, providers)
 }
 
+func TestPermsSyncer_syncUserPerms_groupMappings(t *testing.T) {
+	conf.Mock(&conf.Unified{SiteConfiguration: schema.SiteConfiguration{
+		PermissionsGroupMappings: []*schema.PermissionsGroupMapping{
+			{Group: "engineering", Repos: []string{"^git.example.com/engineering/"}},
+		},
+	}})
+	t.Cleanup(func() { conf.Mock(nil) })
+
+	samlAccount := extsvc.Account{
+		ID: 7,
+		AccountSpec: extsvc.AccountSpec{
+			ServiceType: "saml",
+			ServiceID:   "https://idp.example.com/",
+		},
+		AccountData: extsvc.AccountData{
+			Data: extsvc.NewUnencryptedData([]byte(`{"groups":["engineering","sales"]}`)),
+		},
+	}
+
+	users := dbmocks.NewMockUserStore()
+	users.GetByIDFunc.SetDefaultHook(func(ctx context.Context, id int32) (*types.User, error) {
+		return &types.User{ID: id}, nil
+	})
+
+	mockRepos := dbmocks.NewMockRepoStore()
+	mockRepos.ListMinimalReposFunc.SetDefaultHook(func(ctx context.Context, opt database.ReposListOptions) ([]types.MinimalRepo, error) {
+		assert.Equal(t, []string{"(?:^git.example.com/engineering/)"}, opt.IncludePatterns)
+		assert.True(t, opt.OnlyPrivate)
+		return []types.MinimalRepo{{ID: 1}, {ID: 2}}, nil
+	})
+
+	externalAccounts := dbmocks.NewMockUserExternalAccountsStore()
+	externalAccounts.ListFunc.SetDefaultHook(func(_ context.Context, opts database.ExternalAccountsListOptions) ([]*extsvc.Account, error) {
+		if opts.OnlyExpired {
+			return []*extsvc.Account{}, nil
+		}
+		return []*extsvc.Account{&samlAccount}, nil
+	})
+
+	syncJobs := dbmocks.NewMockPermissionSyncJobStore()
+	syncJobs.GetLatestFinishedSyncJobFunc.SetDefaultReturn(nil, nil)
+
+	db := dbmocks.NewMockDB()
+	db.UsersFunc.SetDefaultReturn(users)
+	db.ReposFunc.SetDefaultReturn(mockRepos)
+	db.UserEmailsFunc.SetDefaultReturn(dbmocks.NewMockUserEmailsStore())
+	db.UserExternalAccountsFunc.SetDefaultReturn(externalAccounts)
+	db.FeatureFlagsFunc.SetDefaultReturn(dbmocks.NewMockFeatureFlagStore())
+	db.PermissionSyncJobsFunc.SetDefaultReturn(syncJobs)
+
+	reposStore := repos.NewMockStoreFrom(repos.NewStore(logtest.Scoped(t), db))
+	reposStore.RepoStoreFunc.SetDefaultReturn(mockRepos)
+
+	perms := dbmocks.NewMockPermsStore()
+	perms.SetUserExternalAccountPermsFunc.SetDefaultReturn(&database.SetPermissionsResult{Added: 2}, nil)
+
+	s := NewPermsSyncer(logtest.Scoped(t), db, reposStore, perms, timeutil.Now)
+
+	result, _, err := s.syncUserPerms(context.Background(), 1, true, authz.FetchPermsOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, 2, result.Added)
+
+	history := perms.SetUserExternalAccountPermsFunc.History()
+	require.Len(t, history, 1)
+	assert.Equal(t, authz.UserIDWithExternalAccountID{UserID: 1, ExternalAccountID: 7}, history[0].Arg1)
+	assert.Equal(t, []int32{1, 2}, history[0].Arg2)
+	assert.Equal(t, authz.SourceGroupSync, history[0].Arg3)
+}
+
 func TestPermsSyncer_syncUserPerms_listExternalAccountsError(t *testing.T) {
 	p := &mockProvider{
 		id:          1,
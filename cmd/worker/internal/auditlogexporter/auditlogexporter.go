@@ -0,0 +1,71 @@
+package auditlogexporter
+
+import (
+	"context"
+	"time"
+
+	workerdb "github.com/sourcegraph/sourcegraph/cmd/worker/shared/init/db"
+	"github.com/sourcegraph/sourcegraph/internal/env"
+	"github.com/sourcegraph/sourcegraph/internal/goroutine"
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+type config struct {
+	env.BaseConfig
+
+	ExportInterval  time.Duration
+	ExportBatchSize int
+
+	DeliveredEntriesRetention   time.Duration
+	RemovedSinkEntriesRetention time.Duration
+	CleanupInterval             time.Duration
+}
+
+var ConfigInst = &config{}
+
+func (c *config) Load() {
+	c.ExportInterval = env.MustGetDuration("AUDIT_LOG_EXPORTER_INTERVAL", 10*time.Second,
+		"Interval at which to deliver queued audit log entries to the configured sinks")
+
+	c.ExportBatchSize = env.MustGetInt("AUDIT_LOG_EXPORTER_BATCH_SIZE", 1000,
+		"Maximum number of audit log entries to deliver to a sink at once")
+	if c.ExportBatchSize < 1 {
+		c.AddError(errors.New("AUDIT_LOG_EXPORTER_BATCH_SIZE must be at least 1"))
+	}
+
+	c.DeliveredEntriesRetention = env.MustGetDuration("AUDIT_LOG_EXPORTER_DELIVERED_RETENTION", 24*time.Hour,
+		"Duration to retain delivered audit log entries in the queue before deleting them")
+
+	c.RemovedSinkEntriesRetention = env.MustGetDuration("AUDIT_LOG_EXPORTER_REMOVED_SINK_RETENTION", 24*time.Hour,
+		"Duration to retain undelivered audit log entries of sinks that were removed from log.auditLog.sinks before deleting them")
+
+	c.CleanupInterval = env.MustGetDuration("AUDIT_LOG_EXPORTER_CLEANUP_INTERVAL", time.Hour,
+		"Interval at which to delete delivered audit log entries from the queue")
+}
+
+type auditLogExporter struct{}
+
+func NewJob() *auditLogExporter {
+	return &auditLogExporter{}
+}
+
+func (j *auditLogExporter) Description() string {
+	return "A background routine that delivers queued audit log entries to the sinks configured in log.auditLog.sinks"
+}
+
+func (j *auditLogExporter) Config() []env.Config {
+	return []env.Config{ConfigInst}
+}
+
+func (j *auditLogExporter) Routines(_ context.Context, observationCtx *observation.Context) ([]goroutine.BackgroundRoutine, error) {
+	db, err := workerdb.InitDB(observationCtx)
+	if err != nil {
+		return nil, err
+	}
+
+	return []goroutine.BackgroundRoutine{
+		newExporterJob(observationCtx, db.AuditLogOutbox(), *ConfigInst),
+		newCleanupJob(observationCtx, db.AuditLogOutbox(), *ConfigInst),
+	}, nil
+}
@@ -0,0 +1,63 @@
+package auditlogexporter
+
+import (
+	"context"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"github.com/sourcegraph/sourcegraph/internal/audit"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/goroutine"
+	"github.com/sourcegraph/sourcegraph/internal/metrics"
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+)
+
+var prunedEntriesCounter = promauto.NewCounter(prometheus.CounterOpts{
+	Name: "src_audit_log_exporter_pruned_entries_total",
+	Help: "Number of delivered audit log entries deleted from the queue.",
+})
+
+var prunedRemovedSinkEntriesCounter = promauto.NewCounter(prometheus.CounterOpts{
+	Name: "src_audit_log_exporter_pruned_removed_sink_entries_total",
+	Help: "Number of undelivered audit log entries of removed sinks deleted from the queue.",
+})
+
+type cleanupJob struct {
+	store     database.AuditLogOutboxStore
+	retention time.Duration
+	// removedSinkRetention is how long undelivered entries of sinks that are no
+	// longer configured are kept, in case the sink is added back.
+	removedSinkRetention time.Duration
+}
+
+func newCleanupJob(obctx *observation.Context, store database.AuditLogOutboxStore, cfg config) goroutine.BackgroundRoutine {
+	return goroutine.NewPeriodicGoroutine(
+		context.Background(),
+		&cleanupJob{store: store, retention: cfg.DeliveredEntriesRetention, removedSinkRetention: cfg.RemovedSinkEntriesRetention},
+		goroutine.WithName("auditlogexporter.cleanup"),
+		goroutine.WithDescription("deletes delivered audit log entries and the entries of removed sinks from the queue"),
+		goroutine.WithInterval(cfg.CleanupInterval),
+		goroutine.WithOperation(obctx.Operation(observation.Op{
+			Name:    "AuditLogExporter.Cleanup",
+			Metrics: metrics.NewREDMetrics(prometheus.DefaultRegisterer, "auditlogexporter_cleanup"),
+		})),
+	)
+}
+
+func (j *cleanupJob) Handle(ctx context.Context) error {
+	count, err := j.store.DeleteDelivered(ctx, time.Now().Add(-j.retention))
+	if err != nil {
+		return err
+	}
+	prunedEntriesCounter.Add(float64(count))
+
+	count, err = j.store.DeleteUndeliveredForRemovedSinks(ctx, audit.SinkKeys(conf.SiteConfig()), time.Now().Add(-j.removedSinkRetention))
+	if err != nil {
+		return err
+	}
+	prunedRemovedSinkEntriesCounter.Add(float64(count))
+	return nil
+}
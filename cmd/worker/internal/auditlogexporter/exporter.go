@@ -0,0 +1,196 @@
+package auditlogexporter
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/sourcegraph/log"
+
+	"github.com/sourcegraph/sourcegraph/internal/audit"
+	"github.com/sourcegraph/sourcegraph/internal/audit/auditsink"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/goroutine"
+	"github.com/sourcegraph/sourcegraph/internal/metrics"
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+var (
+	deliveredEntriesCounter = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "src_audit_log_exporter_delivered_entries_total",
+		Help: "Number of audit log entries delivered to a sink.",
+	}, []string{"sink"})
+	failedDeliveriesCounter = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "src_audit_log_exporter_failed_deliveries_total",
+		Help: "Number of failed attempts to deliver a batch of audit log entries to a sink.",
+	}, []string{"sink"})
+	queueSizeGauge = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "src_audit_log_exporter_queue_size",
+		Help: "Number of audit log entries waiting to be delivered to a sink.",
+	}, []string{"sink"})
+)
+
+type exporterJob struct {
+	logger    log.Logger
+	store     database.AuditLogOutboxStore
+	batchSize int
+
+	// newSink is replaced in tests.
+	newSink func(context.Context, *schema.AuditLogSink) (auditsink.Sink, error)
+	// sinks are the sinks created for the current configuration by key.
+	sinks map[string]*configuredSink
+}
+
+type configuredSink struct {
+	config string
+	sink   auditsink.Sink
+}
+
+func newExporterJob(obctx *observation.Context, store database.AuditLogOutboxStore, cfg config) goroutine.BackgroundRoutine {
+	job := &exporterJob{
+		logger:    obctx.Logger.Scoped("exporter", "audit log exporter"),
+		store:     store,
+		batchSize: cfg.ExportBatchSize,
+		newSink:   auditsink.New,
+		sinks:     map[string]*configuredSink{},
+	}
+	return goroutine.NewPeriodicGoroutine(
+		context.Background(),
+		job,
+		goroutine.WithName("auditlogexporter.exporter"),
+		goroutine.WithDescription("delivers queued audit log entries to the configured sinks"),
+		goroutine.WithInterval(cfg.ExportInterval),
+		goroutine.WithOperation(obctx.Operation(observation.Op{
+			Name:    "AuditLogExporter.Export",
+			Metrics: metrics.NewREDMetrics(prometheus.DefaultRegisterer, "auditlogexporter_exporter"),
+		})),
+	)
+}
+
+var _ goroutine.Finalizer = (*exporterJob)(nil)
+
+func (j *exporterJob) OnShutdown() {
+	for key, s := range j.sinks {
+		_ = s.sink.Close()
+		delete(j.sinks, key)
+	}
+}
+
+func (j *exporterJob) Handle(ctx context.Context) error {
+	sinks := j.updateSinks(ctx, conf.SiteConfig())
+
+	var errs error
+	for _, key := range sinks {
+		if err := j.deliver(ctx, key, j.sinks[key].sink); err != nil {
+			errs = errors.Append(errs, err)
+		}
+	}
+
+	// Entries queued for sinks that are no longer configured are reported too,
+	// so that they can be noticed.
+	counts, err := j.store.CountUndelivered(ctx)
+	if err != nil {
+		return errors.Append(errs, err)
+	}
+	queueSizeGauge.Reset()
+	for key, count := range counts {
+		queueSizeGauge.WithLabelValues(key).Set(float64(count))
+	}
+
+	return errs
+}
+
+// updateSinks creates the sinks of the given configuration, replacing sinks
+// whose configuration has changed and closing those that are no longer
+// configured. It returns the keys of the sinks to deliver entries to.
+func (j *exporterJob) updateSinks(ctx context.Context, cfg schema.SiteConfiguration) []string {
+	var configs []*schema.AuditLogSink
+	if cfg.Log != nil && cfg.Log.AuditLog != nil {
+		configs = cfg.Log.AuditLog.Sinks
+	}
+
+	var keys []string
+	configured := map[string]struct{}{}
+	for _, sinkConfig := range configs {
+		key := audit.SinkKey(sinkConfig)
+		if _, ok := configured[key]; ok || key == "" {
+			continue
+		}
+		configured[key] = struct{}{}
+
+		b, _ := json.Marshal(sinkConfig)
+		if s, ok := j.sinks[key]; ok {
+			if s.config == string(b) {
+				keys = append(keys, key)
+				continue
+			}
+			_ = s.sink.Close()
+			delete(j.sinks, key)
+		}
+
+		sink, err := j.newSink(ctx, sinkConfig)
+		if err != nil {
+			// The entries remain queued until the configuration is fixed.
+			j.logger.Error("invalid audit log sink", log.String("sink", key), log.Error(err))
+			continue
+		}
+		j.sinks[key] = &configuredSink{config: string(b), sink: sink}
+		keys = append(keys, key)
+	}
+
+	for key, s := range j.sinks {
+		if _, ok := configured[key]; !ok {
+			_ = s.sink.Close()
+			delete(j.sinks, key)
+		}
+	}
+	return keys
+}
+
+// deliver delivers all queued entries to the sink in batches, stopping at the
+// first failed batch so that entries are delivered in order.
+func (j *exporterJob) deliver(ctx context.Context, key string, sink auditsink.Sink) error {
+	for {
+		queued, err := j.store.ListUndelivered(ctx, key, j.batchSize)
+		if err != nil {
+			return err
+		}
+		if len(queued) == 0 {
+			return nil
+		}
+
+		entries := make([]auditsink.Entry, 0, len(queued))
+		ids := make([]int64, 0, len(queued))
+		for _, e := range queued {
+			entries = append(entries, auditsink.Entry{
+				ID:        e.ID,
+				Timestamp: e.Timestamp,
+				Action:    e.Action,
+				Payload:   e.Payload,
+			})
+			ids = append(ids, e.ID)
+		}
+
+		if err := sink.Deliver(ctx, entries); err != nil {
+			failedDeliveriesCounter.WithLabelValues(key).Inc()
+			if markErr := j.store.MarkFailed(ctx, ids, err.Error()); markErr != nil {
+				err = errors.Append(err, markErr)
+			}
+			return errors.Wrapf(err, "delivering audit log entries to %q", key)
+		}
+
+		// If marking the entries fails, they are delivered again on the next run.
+		if err := j.store.MarkDelivered(ctx, ids); err != nil {
+			return err
+		}
+		deliveredEntriesCounter.WithLabelValues(key).Add(float64(len(entries)))
+
+		if len(queued) < j.batchSize {
+			return nil
+		}
+	}
+}
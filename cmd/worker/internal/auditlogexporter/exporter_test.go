@@ -0,0 +1,144 @@
+package auditlogexporter
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	mockassert "github.com/derision-test/go-mockgen/testutil/assert"
+	"github.com/sourcegraph/log/logtest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcegraph/sourcegraph/internal/audit/auditsink"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbmocks"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+type fakeSink struct {
+	delivered []int64
+	err       error
+	closed    bool
+}
+
+func (s *fakeSink) Deliver(_ context.Context, entries []auditsink.Entry) error {
+	if s.err != nil {
+		return s.err
+	}
+	for _, e := range entries {
+		s.delivered = append(s.delivered, e.ID)
+	}
+	return nil
+}
+
+func (s *fakeSink) Close() error {
+	s.closed = true
+	return nil
+}
+
+func mockSinks(t *testing.T, sinks ...*schema.AuditLogSink) {
+	conf.Mock(&conf.Unified{SiteConfiguration: schema.SiteConfiguration{
+		Log: &schema.Log{AuditLog: &schema.AuditLog{Sinks: sinks}},
+	}})
+	t.Cleanup(func() { conf.Mock(nil) })
+}
+
+func TestExporterJob(t *testing.T) {
+	ctx := context.Background()
+	syslogConfig := &schema.AuditLogSink{Syslog: &schema.SyslogAuditLogSink{Type: "syslog", Address: "syslog.example.com:6514"}}
+	fileConfig := &schema.AuditLogSink{File: &schema.FileAuditLogSink{Type: "file", Path: "/var/log/audit.jsonl"}}
+
+	// The queue holds 3 entries for the syslog sink and 1 for the file sink.
+	queued := map[string][]*database.AuditLogOutboxEntry{
+		"syslog:syslog.example.com:6514": {{ID: 1}, {ID: 2}, {ID: 3}},
+		"file:/var/log/audit.jsonl":      {{ID: 4}},
+	}
+	delivered := map[int64]bool{}
+	store := dbmocks.NewMockAuditLogOutboxStore()
+	store.ListUndeliveredFunc.SetDefaultHook(func(_ context.Context, sink string, limit int) ([]*database.AuditLogOutboxEntry, error) {
+		var entries []*database.AuditLogOutboxEntry
+		for _, e := range queued[sink] {
+			if !delivered[e.ID] && len(entries) < limit {
+				entries = append(entries, e)
+			}
+		}
+		return entries, nil
+	})
+	store.MarkDeliveredFunc.SetDefaultHook(func(_ context.Context, ids []int64) error {
+		for _, id := range ids {
+			delivered[id] = true
+		}
+		return nil
+	})
+
+	sinks := map[string]*fakeSink{}
+	job := &exporterJob{
+		logger:    logtest.Scoped(t),
+		store:     store,
+		batchSize: 2,
+		newSink: func(_ context.Context, config *schema.AuditLogSink) (auditsink.Sink, error) {
+			if config.File != nil {
+				return nil, errors.New("invalid path")
+			}
+			s := &fakeSink{}
+			sinks[config.Syslog.Address] = s
+			return s, nil
+		},
+		sinks: map[string]*configuredSink{},
+	}
+
+	mockSinks(t, syslogConfig, fileConfig)
+	require.NoError(t, job.Handle(ctx))
+
+	// All entries are delivered to the syslog sink in batches, while the entry of
+	// the invalid file sink remains queued.
+	syslog := sinks["syslog.example.com:6514"]
+	require.NotNil(t, syslog)
+	assert.Equal(t, []int64{1, 2, 3}, syslog.delivered)
+	assert.False(t, delivered[4])
+	mockassert.CalledN(t, store.MarkDeliveredFunc, 2)
+
+	t.Run("failed delivery", func(t *testing.T) {
+		queued["syslog:syslog.example.com:6514"] = append(queued["syslog:syslog.example.com:6514"], &database.AuditLogOutboxEntry{ID: 5})
+		syslog.err = errors.New("connection refused")
+
+		err := job.Handle(ctx)
+		assert.ErrorContains(t, err, "connection refused")
+		assert.False(t, delivered[5])
+		mockassert.CalledOnceWith(t, store.MarkFailedFunc, mockassert.Values(mockassert.Skip, []int64{5}, "connection refused"))
+
+		// The sink is reused, and the entry is delivered once the sink recovers.
+		syslog.err = nil
+		require.NoError(t, job.Handle(ctx))
+		assert.Equal(t, []int64{1, 2, 3, 5}, syslog.delivered)
+	})
+
+	t.Run("configuration changes", func(t *testing.T) {
+		mockSinks(t, &schema.AuditLogSink{Syslog: &schema.SyslogAuditLogSink{Type: "syslog", Address: "syslog.example.com:6514", Tls: true}})
+		require.NoError(t, job.Handle(ctx))
+		assert.True(t, syslog.closed)
+		assert.NotSame(t, syslog, sinks["syslog.example.com:6514"])
+
+		mockSinks(t)
+		require.NoError(t, job.Handle(ctx))
+		assert.True(t, sinks["syslog.example.com:6514"].closed)
+		assert.Empty(t, job.sinks)
+	})
+}
+
+func TestCleanupJob(t *testing.T) {
+	mockSinks(t, &schema.AuditLogSink{File: &schema.FileAuditLogSink{Type: "file", Path: "/var/log/audit.jsonl"}})
+
+	store := dbmocks.NewMockAuditLogOutboxStore()
+	job := &cleanupJob{store: store, retention: time.Hour, removedSinkRetention: 24 * time.Hour}
+	require.NoError(t, job.Handle(context.Background()))
+
+	mockassert.CalledOnce(t, store.DeleteDeliveredFunc)
+	// Only entries of sinks that are no longer configured are deleted.
+	mockassert.CalledOnceWith(t, store.DeleteUndeliveredForRemovedSinksFunc, mockassert.Values(mockassert.Skip, []string{"file:/var/log/audit.jsonl"}))
+	before := store.DeleteUndeliveredForRemovedSinksFunc.History()[0].Arg2
+	assert.WithinDuration(t, time.Now().Add(-24*time.Hour), before, time.Minute)
+}
@@ -0,0 +1,201 @@
+package auth
+
+import (
+	"context"
+	"time"
+
+	"github.com/sourcegraph/log"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/globals"
+	"github.com/sourcegraph/sourcegraph/cmd/worker/job"
+	workerdb "github.com/sourcegraph/sourcegraph/cmd/worker/shared/init/db"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/env"
+	"github.com/sourcegraph/sourcegraph/internal/errcode"
+	"github.com/sourcegraph/sourcegraph/internal/goroutine"
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+	"github.com/sourcegraph/sourcegraph/internal/txemail"
+	"github.com/sourcegraph/sourcegraph/internal/txemail/txtypes"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+var _ job.Job = (*accessTokenExpirer)(nil)
+
+// accessTokenExpirer is a worker responsible for revoking expired access tokens
+// and notifying the owners of access tokens that are about to expire.
+type accessTokenExpirer struct{}
+
+func NewAccessTokenExpirer() job.Job {
+	return &accessTokenExpirer{}
+}
+
+func (j *accessTokenExpirer) Description() string {
+	return "Revokes expired access tokens and notifies the owners of expiring access tokens."
+}
+
+func (j *accessTokenExpirer) Config() []env.Config {
+	return nil
+}
+
+func (j *accessTokenExpirer) Routines(_ context.Context, observationCtx *observation.Context) ([]goroutine.BackgroundRoutine, error) {
+	db, err := workerdb.InitDB(observationCtx)
+	if err != nil {
+		return nil, errors.Wrap(err, "init DB")
+	}
+
+	return []goroutine.BackgroundRoutine{
+		goroutine.NewPeriodicGoroutine(
+			context.Background(),
+			&accessTokenExpireHandler{
+				db:             db,
+				logger:         observationCtx.Logger.Scoped("accessTokenExpirer", "revokes expired access tokens"),
+				notifyInterval: accessTokenExpirationNotifyInterval,
+				sendEmail:      sendAccessTokenExpiringEmail,
+			},
+			goroutine.WithName("auth.access-token-expirer"),
+			goroutine.WithDescription("revokes expired access tokens and notifies the owners of expiring access tokens"),
+			goroutine.WithInterval(time.Minute),
+		),
+	}, nil
+}
+
+// accessTokenExpirationNotifyInterval is how long before an access token expires its owner is
+// notified of the upcoming expiration.
+const accessTokenExpirationNotifyInterval = 7 * 24 * time.Hour
+
+var _ goroutine.Handler = (*accessTokenExpireHandler)(nil)
+
+type accessTokenExpireHandler struct {
+	db             database.DB
+	logger         log.Logger
+	notifyInterval time.Duration
+	sendEmail      func(ctx context.Context, db database.DB, token *database.AccessToken, expired bool) error
+}
+
+// Handle revokes all access tokens that have expired and notifies the owners of
+// the access tokens that expire within the notify interval. Owners are notified
+// at most once before expiration, and once more on revocation. Access tokens are
+// only marked as notified once the notification has been sent, so owners are
+// notified later if email is disabled or sending fails. Revocation notifications
+// are retried for up to the notify interval after the revocation.
+func (h *accessTokenExpireHandler) Handle(ctx context.Context) error {
+	now := time.Now()
+
+	if _, err := h.db.AccessTokens().DeleteExpired(ctx, now); err != nil {
+		return errors.Wrap(err, "delete expired access tokens")
+	}
+
+	if !conf.CanSendEmail() {
+		return nil
+	}
+
+	expiring, err := h.db.AccessTokens().ListExpiring(ctx, now.Add(h.notifyInterval))
+	if err != nil {
+		return errors.Wrap(err, "list expiring access tokens")
+	}
+	revoked, err := h.db.AccessTokens().ListRevokedExpired(ctx, now.Add(-h.notifyInterval))
+	if err != nil {
+		return errors.Wrap(err, "list revoked access tokens")
+	}
+
+	notify := func(token *database.AccessToken, expired bool) bool {
+		if err := h.sendEmail(ctx, h.db, token, expired); err != nil {
+			h.logger.Warn("failed to notify user of access token expiration",
+				log.Int64("accessTokenID", token.ID),
+				log.Int32("userID", token.SubjectUserID),
+				log.Error(err),
+			)
+			return false
+		}
+		return true
+	}
+	for _, token := range expiring {
+		if !notify(token, false) {
+			continue
+		}
+		if err := h.db.AccessTokens().MarkExpirationNotified(ctx, token.ID); err != nil {
+			return errors.Wrap(err, "mark access token as notified")
+		}
+	}
+	for _, token := range revoked {
+		if !notify(token, true) {
+			continue
+		}
+		if err := h.db.AccessTokens().MarkRevocationNotified(ctx, token.ID); err != nil {
+			return errors.Wrap(err, "mark access token as notified of revocation")
+		}
+	}
+
+	return nil
+}
+
+func sendAccessTokenExpiringEmail(ctx context.Context, db database.DB, token *database.AccessToken, expired bool) error {
+	email, verified, err := db.UserEmails().GetPrimaryEmail(ctx, token.SubjectUserID)
+	if err != nil {
+		if errcode.IsNotFound(err) {
+			return errors.Errorf("unable to send email to user ID %d with unknown email address", token.SubjectUserID)
+		}
+		return errors.Wrapf(err, "getting primary email for user ID %d", token.SubjectUserID)
+	}
+	if !verified {
+		return errors.Newf("unable to send email to user ID %d's unverified primary email address", token.SubjectUserID)
+	}
+	user, err := db.Users().GetByID(ctx, token.SubjectUserID)
+	if err != nil {
+		return errors.Wrapf(err, "getting user ID %d", token.SubjectUserID)
+	}
+
+	tmpl := accessTokenExpiringEmailTemplate
+	if expired {
+		tmpl = accessTokenExpiredEmailTemplate
+	}
+
+	return txemail.Send(ctx, "user_access_token_expiration", txtypes.Message{
+		To:       []string{email},
+		Template: tmpl,
+		Data: struct {
+			TokenName string
+			Username  string
+			Host      string
+			ExpiresAt string
+		}{
+			TokenName: token.Note,
+			Username:  user.Username,
+			Host:      globals.ExternalURL().Host,
+			ExpiresAt: token.ExpiresAt.UTC().Format(time.RFC1123),
+		},
+	})
+}
+
+var accessTokenExpiringEmailTemplate = txemail.MustValidate(txtypes.Templates{
+	Subject: `Sourcegraph access token expiring soon ({{.Host}})`,
+	Text: `
+Hi there! The access token "{{.TokenName}}" for the user {{.Username}} on Sourcegraph ({{.Host}}) expires at {{.ExpiresAt}}.
+
+If the token is still in use, create a new access token and replace the expiring one before then.
+`,
+	HTML: `
+<p>
+Hi there! The access token "{{.TokenName}}" for the user {{.Username}} on Sourcegraph ({{.Host}}) expires at {{.ExpiresAt}}.
+</p>
+
+<p>If the token is still in use, create a new access token and replace the expiring one before then.</p>
+`,
+})
+
+var accessTokenExpiredEmailTemplate = txemail.MustValidate(txtypes.Templates{
+	Subject: `Sourcegraph access token expired ({{.Host}})`,
+	Text: `
+Hi there! The access token "{{.TokenName}}" for the user {{.Username}} on Sourcegraph ({{.Host}}) expired at {{.ExpiresAt}} and has been revoked.
+
+If the token is still in use, create a new access token to replace it.
+`,
+	HTML: `
+<p>
+Hi there! The access token "{{.TokenName}}" for the user {{.Username}} on Sourcegraph ({{.Host}}) expired at {{.ExpiresAt}} and has been revoked.
+</p>
+
+<p>If the token is still in use, create a new access token to replace it.</p>
+`,
+})
@@ -0,0 +1,95 @@
+package auth
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	mockrequire "github.com/derision-test/go-mockgen/testutil/require"
+	"github.com/sourcegraph/log/logtest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbmocks"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+func TestAccessTokenExpireHandler(t *testing.T) {
+	expiresAt := time.Now().Add(24 * time.Hour)
+	expiring := &database.AccessToken{ID: 1, SubjectUserID: 10, Note: "ci", ExpiresAt: &expiresAt}
+	undeliverable := &database.AccessToken{ID: 3, SubjectUserID: 30, Note: "old", ExpiresAt: &expiresAt}
+	expired := &database.AccessToken{ID: 2, SubjectUserID: 20, Note: "bot", ExpiresAt: &expiresAt}
+	undeliverableExpired := &database.AccessToken{ID: 4, SubjectUserID: 30, Note: "older", ExpiresAt: &expiresAt}
+
+	type notification struct {
+		tokenID int64
+		expired bool
+	}
+	setup := func(t *testing.T) (*dbmocks.MockAccessTokenStore, *accessTokenExpireHandler, *[]notification) {
+		accessTokens := dbmocks.NewMockAccessTokenStore()
+		accessTokens.ListExpiringFunc.SetDefaultReturn([]*database.AccessToken{expiring, undeliverable}, nil)
+		accessTokens.DeleteExpiredFunc.SetDefaultReturn([]*database.AccessToken{expired}, nil)
+		accessTokens.ListRevokedExpiredFunc.SetDefaultReturn([]*database.AccessToken{expired, undeliverableExpired}, nil)
+		db := dbmocks.NewMockDB()
+		db.AccessTokensFunc.SetDefaultReturn(accessTokens)
+
+		var notifications []notification
+		handler := &accessTokenExpireHandler{
+			db:             db,
+			logger:         logtest.Scoped(t),
+			notifyInterval: 7 * 24 * time.Hour,
+			sendEmail: func(_ context.Context, _ database.DB, token *database.AccessToken, expired bool) error {
+				if token.SubjectUserID == undeliverable.SubjectUserID {
+					return errors.New("unverified email address")
+				}
+				notifications = append(notifications, notification{tokenID: token.ID, expired: expired})
+				return nil
+			},
+		}
+		return accessTokens, handler, &notifications
+	}
+
+	t.Run("email enabled", func(t *testing.T) {
+		conf.Mock(&conf.Unified{SiteConfiguration: schema.SiteConfiguration{
+			EmailSmtp:    &schema.SMTPServerConfig{},
+			EmailAddress: "noreply@sourcegraph.com",
+		}})
+		t.Cleanup(func() { conf.Mock(nil) })
+
+		accessTokens, handler, notifications := setup(t)
+		before := time.Now()
+		require.NoError(t, handler.Handle(context.Background()))
+
+		mockrequire.CalledOnce(t, accessTokens.ListExpiringFunc)
+		expiresBefore := accessTokens.ListExpiringFunc.History()[0].Arg1
+		assert.True(t, !expiresBefore.Before(before.Add(7*24*time.Hour)), "unexpected notification cutoff %s", expiresBefore)
+
+		mockrequire.CalledOnce(t, accessTokens.DeleteExpiredFunc)
+		assert.Equal(t, []notification{{tokenID: 1, expired: false}, {tokenID: 2, expired: true}}, *notifications)
+
+		mockrequire.CalledOnce(t, accessTokens.ListRevokedExpiredFunc)
+		revokedAfter := accessTokens.ListRevokedExpiredFunc.History()[0].Arg1
+		assert.True(t, !revokedAfter.After(time.Now().Add(-7*24*time.Hour)), "unexpected revocation cutoff %s", revokedAfter)
+
+		// Only access tokens whose owner was notified are marked, so that the others are
+		// retried.
+		mockrequire.CalledOnceWith(t, accessTokens.MarkExpirationNotifiedFunc, mockrequire.Values(mockrequire.Skip, int64(1)))
+		mockrequire.CalledOnceWith(t, accessTokens.MarkRevocationNotifiedFunc, mockrequire.Values(mockrequire.Skip, int64(2)))
+	})
+
+	t.Run("email disabled", func(t *testing.T) {
+		conf.Mock(&conf.Unified{})
+		t.Cleanup(func() { conf.Mock(nil) })
+
+		accessTokens, handler, notifications := setup(t)
+		require.NoError(t, handler.Handle(context.Background()))
+
+		mockrequire.CalledOnce(t, accessTokens.DeleteExpiredFunc)
+		mockrequire.NotCalled(t, accessTokens.MarkExpirationNotifiedFunc)
+		mockrequire.NotCalled(t, accessTokens.MarkRevocationNotifiedFunc)
+		assert.Empty(t, *notifications)
+	})
+}
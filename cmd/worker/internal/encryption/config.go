@@ -4,14 +4,18 @@ import (
 	"time"
 
 	"github.com/sourcegraph/sourcegraph/internal/env"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
 )
 
 type config struct {
 	env.BaseConfig
 
-	EncryptionInterval time.Duration
-	MetricsInterval    time.Duration
-	Decrypt            bool
+	EncryptionInterval    time.Duration
+	MetricsInterval       time.Duration
+	Decrypt               bool
+	Reencrypt             bool
+	ReencryptionInterval  time.Duration
+	ReencryptionRateLimit int
 }
 
 var ConfigInst = &config{}
@@ -20,4 +24,10 @@ func (c *config) Load() {
 	c.EncryptionInterval = c.GetInterval("RECORD_ENCRYPTER_INTERVAL", "1s", "How frequently to encrypt/decrypt a batch of records in the database.")
 	c.MetricsInterval = c.GetInterval("RECORD_ENCRYPTER_METRICS_INTERVAL", "10s", "How frequently to update progress metrics related to encryption/decryption.")
 	c.Decrypt = c.GetBool("ALLOW_DECRYPTION", "false", "If true, encrypted records will be decrypted and stored in plaintext.")
+	c.Reencrypt = c.GetBool("RECORD_REENCRYPTER_ENABLED", "true", "If true, records encrypted with a key or key version other than the active key will be re-encrypted with the active key.")
+	c.ReencryptionInterval = c.GetInterval("RECORD_REENCRYPTER_INTERVAL", "1m", "How frequently to re-encrypt a batch of records in the database.")
+	c.ReencryptionRateLimit = c.GetInt("RECORD_REENCRYPTER_RATE_LIMIT", "100", "The maximum number of records to re-encrypt per second. Zero means no limit.")
+	if c.ReencryptionRateLimit < 0 {
+		c.AddError(errors.New("RECORD_REENCRYPTER_RATE_LIMIT cannot be negative"))
+	}
 }
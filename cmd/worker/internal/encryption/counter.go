@@ -27,8 +27,14 @@ func (c *recordCounter) Handle(ctx context.Context) (err error) {
 			return err
 		}
 
+		numWithInactiveKey, err := c.store.CountWithInactiveKey(ctx, config)
+		if err != nil {
+			return err
+		}
+
 		c.metrics.numEncryptedAtRest.WithLabelValues(config.TableName).Set(float64(numEncrypted))
 		c.metrics.numUnencryptedAtRest.WithLabelValues(config.TableName).Set(float64(numUnencrypted))
+		c.metrics.numEncryptedWithInactiveKey.WithLabelValues(config.TableName).Set(float64(numWithInactiveKey))
 	}
 
 	return err
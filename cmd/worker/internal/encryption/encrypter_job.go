@@ -36,7 +36,7 @@ func (j *recordEncrypterJob) Routines(_ context.Context, observationCtx *observa
 	}
 	store := database.NewRecordEncrypter(db)
 
-	return []goroutine.BackgroundRoutine{
+	routines := []goroutine.BackgroundRoutine{
 		goroutine.NewPeriodicGoroutine(
 			context.Background(),
 			&recordEncrypter{
@@ -60,5 +60,18 @@ func (j *recordEncrypterJob) Routines(_ context.Context, observationCtx *observa
 			goroutine.WithDescription("tracks number of encrypted vs unencrypted records"),
 			goroutine.WithInterval(ConfigInst.MetricsInterval),
 		),
-	}, nil
+	}
+
+	// Records must not be re-encrypted while the database is being decrypted.
+	if ConfigInst.Reencrypt && !ConfigInst.Decrypt {
+		routines = append(routines, goroutine.NewPeriodicGoroutine(
+			context.Background(),
+			newRecordReencrypter(store, ConfigInst.ReencryptionRateLimit, metrics, observationCtx.Logger),
+			goroutine.WithName("encryption.record-reencrypter"),
+			goroutine.WithDescription("re-encrypts existing data encrypted with a key or key version other than the active key"),
+			goroutine.WithInterval(ConfigInst.ReencryptionInterval),
+		))
+	}
+
+	return routines, nil
 }
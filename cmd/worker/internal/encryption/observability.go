@@ -9,13 +9,16 @@ import (
 
 type metrics struct {
 	// current state
-	numEncryptedAtRest   *prometheus.GaugeVec
-	numUnencryptedAtRest *prometheus.GaugeVec
+	numEncryptedAtRest          *prometheus.GaugeVec
+	numUnencryptedAtRest        *prometheus.GaugeVec
+	numEncryptedWithInactiveKey *prometheus.GaugeVec
 
 	// processing status
-	numRecordsEncrypted *prometheus.CounterVec
-	numRecordsDecrypted *prometheus.CounterVec
-	numErrors           prometheus.Counter
+	numRecordsEncrypted     *prometheus.CounterVec
+	numRecordsDecrypted     *prometheus.CounterVec
+	numRecordsReencrypted   *prometheus.CounterVec
+	numReencryptionFailures *prometheus.CounterVec
+	numErrors               prometheus.Counter
 }
 
 func newMetrics(observationCtx *observation.Context) *metrics {
@@ -57,6 +60,10 @@ func newMetrics(observationCtx *observation.Context) *metrics {
 		"src_records_unencrypted_at_rest_total",
 		"The number of database records unencrypted at rest.",
 	)
+	numEncryptedWithInactiveKey := gaugeVec(
+		"src_records_encrypted_with_inactive_key_total",
+		"The number of database records encrypted with a key or key version other than the active key.",
+	)
 	numRecordsEncrypted := counterVec(
 		"src_records_encrypted_total",
 		"The number of unencrypted database records that have been encrypted.",
@@ -65,6 +72,14 @@ func newMetrics(observationCtx *observation.Context) *metrics {
 		"src_records_decrypted_total",
 		"The number of encrypted database records that have been decrypted.",
 	)
+	numRecordsReencrypted := counterVec(
+		"src_records_reencrypted_total",
+		"The number of database records that have been re-encrypted with the active key.",
+	)
+	numReencryptionFailures := counterVec(
+		"src_record_reencryption_failures_total",
+		"The number of database records that could not be decrypted with the active key to be re-encrypted.",
+	)
 	numErrors := counter(
 		"src_record_encryption_errors_total",
 		"The number of errors that occur during record encryption/decryption.",
@@ -74,13 +89,18 @@ func newMetrics(observationCtx *observation.Context) *metrics {
 		// Initialize counters to zero
 		numRecordsEncrypted.WithLabelValues(config.TableName).Add(0)
 		numRecordsDecrypted.WithLabelValues(config.TableName).Add(0)
+		numRecordsReencrypted.WithLabelValues(config.TableName).Add(0)
+		numReencryptionFailures.WithLabelValues(config.TableName).Add(0)
 	}
 
 	return &metrics{
-		numEncryptedAtRest:   numEncryptedAtRest,
-		numUnencryptedAtRest: numUnencryptedAtRest,
-		numRecordsEncrypted:  numRecordsEncrypted,
-		numRecordsDecrypted:  numRecordsDecrypted,
-		numErrors:            numErrors,
+		numEncryptedAtRest:          numEncryptedAtRest,
+		numUnencryptedAtRest:        numUnencryptedAtRest,
+		numEncryptedWithInactiveKey: numEncryptedWithInactiveKey,
+		numRecordsEncrypted:         numRecordsEncrypted,
+		numRecordsDecrypted:         numRecordsDecrypted,
+		numRecordsReencrypted:       numRecordsReencrypted,
+		numReencryptionFailures:     numReencryptionFailures,
+		numErrors:                   numErrors,
 	}
 }
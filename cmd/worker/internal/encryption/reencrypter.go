@@ -0,0 +1,109 @@
+package encryption
+
+import (
+	"context"
+
+	"github.com/sourcegraph/log"
+	"golang.org/x/time/rate"
+
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/goroutine"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// recordReencrypter re-encrypts records encrypted with a key or key version other
+// than the active key, e.g. after the key has been rotated, so that old key
+// versions can be retired. Every run walks each table in batches by ID, at the pace
+// allowed by the rate limiter.
+type recordReencrypter struct {
+	store   *database.RecordEncrypter
+	limiter *rate.Limiter
+	metrics *metrics
+	logger  log.Logger
+}
+
+var (
+	_ goroutine.Handler      = &recordReencrypter{}
+	_ goroutine.ErrorHandler = &recordReencrypter{}
+)
+
+func newRecordReencrypter(store *database.RecordEncrypter, rateLimit int, metrics *metrics, logger log.Logger) *recordReencrypter {
+	limiter := rate.NewLimiter(rate.Inf, 0)
+	if rateLimit > 0 {
+		// A whole batch is waited for at once, so the burst must fit the largest batch.
+		burst := rateLimit
+		for _, config := range database.EncryptionConfigs {
+			if config.Limit > burst {
+				burst = config.Limit
+			}
+		}
+		limiter = rate.NewLimiter(rate.Limit(rateLimit), burst)
+	}
+
+	return &recordReencrypter{
+		store:   store,
+		limiter: limiter,
+		metrics: metrics,
+		logger:  logger,
+	}
+}
+
+func (r *recordReencrypter) Handle(ctx context.Context) (err error) {
+	for _, config := range database.EncryptionConfigs {
+		if handleErr := r.handleTable(ctx, config); handleErr != nil {
+			err = errors.CombineErrors(err, handleErr)
+		}
+	}
+
+	return err
+}
+
+func (r *recordReencrypter) handleTable(ctx context.Context, config database.EncryptionConfig) error {
+	if config.Key() == nil {
+		return nil
+	}
+
+	var reencrypted, failed int
+	for afterID := 0; ; {
+		if err := r.limiter.WaitN(ctx, config.Limit); err != nil {
+			return err
+		}
+
+		result, err := r.store.ReencryptBatch(ctx, config, afterID)
+		if err != nil {
+			return err
+		}
+		if result.LastID == 0 {
+			break
+		}
+		afterID = result.LastID
+
+		reencrypted += result.Reencrypted
+		failed += result.Failed
+		r.metrics.numRecordsReencrypted.WithLabelValues(config.TableName).Add(float64(result.Reencrypted))
+		r.metrics.numReencryptionFailures.WithLabelValues(config.TableName).Add(float64(result.Failed))
+		r.logger.Debug(
+			"re-encrypted records",
+			log.String("tableName", config.TableName),
+			log.Int("count", result.Reencrypted),
+			log.Int("lastID", result.LastID),
+		)
+	}
+
+	if failed > 0 {
+		r.logger.Warn(
+			"failed to decrypt records with the active key, they may be encrypted with a different key",
+			log.String("tableName", config.TableName),
+			log.Int("count", failed),
+		)
+	}
+	if reencrypted > 0 {
+		r.logger.Info("re-encrypted records", log.String("tableName", config.TableName), log.Int("count", reencrypted))
+	}
+	return nil
+}
+
+func (r *recordReencrypter) HandleError(err error) {
+	r.metrics.numErrors.Add(1)
+	r.logger.Error("failed to re-encrypt batch of records", log.Error(err))
+}
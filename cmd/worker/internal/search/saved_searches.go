@@ -0,0 +1,338 @@
+package search
+
+import (
+	"context"
+	"hash/fnv"
+	"net/url"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/sourcegraph/log"
+
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/goroutine"
+	"github.com/sourcegraph/sourcegraph/internal/search"
+	"github.com/sourcegraph/sourcegraph/internal/search/client"
+	"github.com/sourcegraph/sourcegraph/internal/search/result"
+	"github.com/sourcegraph/sourcegraph/internal/search/streaming"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// savedSearchNotifier executes every saved search which has email or Slack
+// notifications enabled and notifies its owners when the results changed
+// meaningfully since the previous execution. Saved searches are executed as
+// each user they notify, so that notifications never reflect results the user
+// cannot see.
+type savedSearchNotifier struct {
+	logger         log.Logger
+	db             database.DB
+	store          *savedSearchStateStore
+	searchClient   client.SearchClient
+	thresholds     []int
+	maxFingerprint int
+}
+
+var _ goroutine.Handler = &savedSearchNotifier{}
+
+func (n *savedSearchNotifier) Handle(ctx context.Context) error {
+	savedSearches, err := n.db.SavedSearches().ListAll(ctx)
+	if err != nil {
+		return errors.Wrap(err, "listing saved searches")
+	}
+
+	var errs error
+	for _, ss := range savedSearches {
+		if !ss.Config.Notify && !ss.Config.NotifySlack {
+			continue
+		}
+		if err := n.handleSavedSearch(ctx, ss); err != nil {
+			errs = errors.Append(errs, errors.Wrapf(err, "saved search %s", ss.Spec.Key))
+		}
+	}
+	return errs
+}
+
+func (n *savedSearchNotifier) handleSavedSearch(ctx context.Context, ss api.SavedQuerySpecAndConfig) error {
+	id, err := savedSearchID(ss)
+	if err != nil {
+		return err
+	}
+
+	notifySlack := ss.Config.NotifySlack && ss.Config.SlackWebhookURL != nil && *ss.Config.SlackWebhookURL != ""
+
+	switch {
+	case ss.Config.UserID != nil:
+		userID := *ss.Config.UserID
+		matches, err := n.searchAsUser(ctx, userID, ss.Config.Query)
+		if err != nil {
+			return errors.Wrap(err, "executing search")
+		}
+		return n.update(ctx, ss, id, &userID, matches, func(data *templateDataSavedSearchChange) error {
+			var errs error
+			if ss.Config.Notify {
+				errs = errors.Append(errs, sendSavedSearchEmail(ctx, n.db, userID, data))
+			}
+			if notifySlack {
+				errs = errors.Append(errs, sendSavedSearchSlackNotification(ctx, *ss.Config.SlackWebhookURL, data))
+			}
+			return errs
+		})
+
+	case ss.Config.OrgID != nil:
+		// 🚨 SECURITY: Organization saved searches are executed as each member,
+		// so that the notification a member receives only reflects the
+		// results that member is allowed to see.
+		members, err := n.db.OrgMembers().GetByOrgID(ctx, *ss.Config.OrgID)
+		if err != nil {
+			return errors.Wrap(err, "listing organization members")
+		}
+
+		var (
+			errs error
+			// shared holds the matches visible to every member so far. The
+			// Slack channel of the organization is only notified about these.
+			shared       result.Matches
+			sharedFailed bool
+		)
+		for i, member := range members {
+			userID := member.UserID
+			matches, err := n.searchAsUser(ctx, userID, ss.Config.Query)
+			if err != nil {
+				errs = errors.Append(errs, errors.Wrapf(err, "executing search as user %d", userID))
+				sharedFailed = true
+				continue
+			}
+
+			if ss.Config.Notify {
+				errs = errors.Append(errs, n.update(ctx, ss, id, &userID, matches, func(data *templateDataSavedSearchChange) error {
+					return sendSavedSearchEmail(ctx, n.db, userID, data)
+				}))
+			}
+			if i == 0 {
+				shared = matches
+			} else {
+				shared = intersectMatches(shared, matches)
+			}
+		}
+
+		// The shared matches are unknown if the search failed for one of the
+		// members, in which case we must not notify the Slack channel.
+		if notifySlack && !sharedFailed && len(members) > 0 {
+			errs = errors.Append(errs, n.update(ctx, ss, id, nil, shared, func(data *templateDataSavedSearchChange) error {
+				return sendSavedSearchSlackNotification(ctx, *ss.Config.SlackWebhookURL, data)
+			}))
+		}
+		return errs
+
+	default:
+		return nil
+	}
+}
+
+// update compares the matches of an execution of a saved search as the given
+// user with the previous execution, calls notify if the results changed, and
+// records the new state. The first execution only establishes a baseline to
+// compare against.
+func (n *savedSearchNotifier) update(
+	ctx context.Context,
+	ss api.SavedQuerySpecAndConfig,
+	savedSearchID int32,
+	userID *int32,
+	matches result.Matches,
+	notify func(*templateDataSavedSearchChange) error,
+) error {
+	prev, ok, err := n.store.Get(ctx, savedSearchID, userID)
+	if err != nil {
+		return errors.Wrap(err, "getting previous state")
+	}
+
+	next := &savedSearchState{
+		SavedSearchID: savedSearchID,
+		UserID:        userID,
+		ResultCount:   matches.ResultCount(),
+		Fingerprint:   newFingerprint(matches, n.maxFingerprint),
+		LastRunAt:     time.Now(),
+	}
+
+	if ok {
+		if change, changed := diffSavedSearchStates(prev, next, n.thresholds, n.maxFingerprint); changed {
+			if err := notify(newTemplateDataSavedSearchChange(ss, change)); err != nil {
+				// We still record the new state below so that owners are not
+				// notified repeatedly about the same change.
+				n.logger.Error("failed to notify saved search owners", log.Int32("savedSearchID", savedSearchID), log.Error(err))
+			}
+		}
+	}
+
+	return n.store.Upsert(ctx, next)
+}
+
+// searchAsUser executes the query with the permissions of the given user.
+func (n *savedSearchNotifier) searchAsUser(ctx context.Context, userID int32, query string) (result.Matches, error) {
+	ctx = actor.WithActor(ctx, actor.FromUser(userID))
+
+	inputs, err := n.searchClient.Plan(ctx, "V3", nil, query, search.Precise, search.Streaming)
+	if err != nil {
+		return nil, err
+	}
+
+	agg := streaming.NewAggregatingStream()
+	if _, err := n.searchClient.Execute(ctx, agg, inputs); err != nil {
+		return nil, err
+	}
+	return agg.Results, nil
+}
+
+func newTemplateDataSavedSearchChange(ss api.SavedQuerySpecAndConfig, change savedSearchChange) *templateDataSavedSearchChange {
+	return &templateDataSavedSearchChange{
+		Description:      ss.Config.Description,
+		Query:            ss.Config.Query,
+		SearchURL:        savedSearchURL(ss.Config.Query),
+		ResultCount:      change.ResultCount,
+		PreviousCount:    change.PreviousCount,
+		NewMatches:       change.NewMatches,
+		CrossedThreshold: change.CrossedThreshold,
+	}
+}
+
+// intersectMatches returns the matches of a whose key is also the key of one
+// of the matches of b.
+func intersectMatches(a, b result.Matches) result.Matches {
+	keys := make(map[int64]struct{}, len(b))
+	for _, m := range b {
+		keys[hashMatchKey(m.Key())] = struct{}{}
+	}
+
+	intersection := make(result.Matches, 0, len(a))
+	for _, m := range a {
+		if _, ok := keys[hashMatchKey(m.Key())]; ok {
+			intersection = append(intersection, m)
+		}
+	}
+	return intersection
+}
+
+func savedSearchID(ss api.SavedQuerySpecAndConfig) (int32, error) {
+	id, err := strconv.ParseInt(ss.Spec.Key, 10, 32)
+	if err != nil {
+		return 0, errors.Wrapf(err, "invalid saved search key %q", ss.Spec.Key)
+	}
+	return int32(id), nil
+}
+
+func savedSearchURL(query string) string {
+	externalURL, err := url.Parse(conf.ExternalURL())
+	if err != nil {
+		return ""
+	}
+	u := externalURL.ResolveReference(&url.URL{Path: "search"})
+	q := u.Query()
+	q.Set("q", query)
+	q.Set("utm_source", "saved-search-notification")
+	u.RawQuery = q.Encode()
+	return u.String()
+}
+
+// savedSearchChange describes how the results of a saved search changed
+// between two executions.
+type savedSearchChange struct {
+	ResultCount      int
+	PreviousCount    int
+	NewMatches       int
+	CrossedThreshold int
+}
+
+// diffSavedSearchStates compares two executions of a saved search. It returns
+// true if the owners should be notified, which is the case when the result
+// count crossed one of the thresholds (in either direction) or when results
+// appeared that were not part of the previous execution.
+func diffSavedSearchStates(prev, next *savedSearchState, thresholds []int, maxFingerprint int) (savedSearchChange, bool) {
+	change := savedSearchChange{
+		ResultCount:   next.ResultCount,
+		PreviousCount: prev.ResultCount,
+		NewMatches:    next.Fingerprint.countNew(prev.Fingerprint, maxFingerprint > 0 && len(prev.Fingerprint) >= maxFingerprint),
+	}
+	if threshold, ok := crossedThreshold(thresholds, prev.ResultCount, next.ResultCount); ok {
+		change.CrossedThreshold = threshold
+	}
+	return change, change.NewMatches > 0 || change.CrossedThreshold > 0
+}
+
+// crossedThreshold returns the highest threshold that lies between the two
+// result counts. Thresholds must be sorted in ascending order.
+func crossedThreshold(thresholds []int, prev, next int) (int, bool) {
+	lo, hi := prev, next
+	if lo > hi {
+		lo, hi = hi, lo
+	}
+	for i := len(thresholds) - 1; i >= 0; i-- {
+		if t := thresholds[i]; lo < t && t <= hi {
+			return t, true
+		}
+	}
+	return 0, false
+}
+
+// fingerprint is a compact representation of a set of search results: the
+// sorted hashes of the match keys, truncated to the smallest N hashes.
+type fingerprint []int64
+
+func newFingerprint(matches result.Matches, max int) fingerprint {
+	seen := make(map[int64]struct{}, len(matches))
+	fp := make(fingerprint, 0, len(matches))
+	for _, m := range matches {
+		h := hashMatchKey(m.Key())
+		if _, ok := seen[h]; ok {
+			continue
+		}
+		seen[h] = struct{}{}
+		fp = append(fp, h)
+	}
+	sort.Slice(fp, func(i, j int) bool { return fp[i] < fp[j] })
+	if max > 0 && len(fp) > max {
+		fp = fp[:max]
+	}
+	return fp
+}
+
+// countNew returns the number of hashes in fp which are not in prev. If prev
+// was truncated, hashes beyond its largest element are unknown and therefore
+// not counted.
+func (fp fingerprint) countNew(prev fingerprint, prevTruncated bool) int {
+	count := 0
+	for _, h := range fp {
+		if prevTruncated && len(prev) > 0 && h > prev[len(prev)-1] {
+			break
+		}
+		i := sort.Search(len(prev), func(i int) bool { return prev[i] >= h })
+		if i == len(prev) || prev[i] != h {
+			count++
+		}
+	}
+	return count
+}
+
+// hashMatchKey hashes the parts of a match key that identify a result across
+// executions. File matches on the same path are considered equal even if the
+// commit they were found in changed, so that every push to the default branch
+// does not turn all results into new ones.
+func hashMatchKey(k result.Key) int64 {
+	h := fnv.New64a()
+	write := func(s string) {
+		_, _ = h.Write([]byte(s))
+		_, _ = h.Write([]byte{0})
+	}
+	write(string(k.Repo))
+	write(k.Rev)
+	write(k.Path)
+	write(k.OwnerMetadata)
+	if k.Path == "" {
+		write(string(k.Commit))
+	}
+	_, _ = h.Write([]byte{byte(k.TypeRank)})
+	return int64(h.Sum64())
+}
@@ -0,0 +1,101 @@
+package search
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/cmd/worker/job"
+	workerdb "github.com/sourcegraph/sourcegraph/cmd/worker/shared/init/db"
+	"github.com/sourcegraph/sourcegraph/internal/env"
+	"github.com/sourcegraph/sourcegraph/internal/goroutine"
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+	"github.com/sourcegraph/sourcegraph/internal/search/client"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+type savedSearchesConfig struct {
+	env.BaseConfig
+
+	Interval       time.Duration
+	Thresholds     []int
+	MaxFingerprint int
+}
+
+var savedSearchesConfigInst = &savedSearchesConfig{}
+
+func (c *savedSearchesConfig) Load() {
+	c.Interval = c.GetInterval("SAVED_SEARCHES_NOTIFIER_INTERVAL", "15m", "How frequently to execute saved searches with notifications enabled.")
+	rawThresholds := c.Get("SAVED_SEARCHES_NOTIFIER_THRESHOLDS", "1,10,100,1000,10000", "Comma-separated result counts. Owners are notified when the result count of a saved search crosses one of them.")
+	c.MaxFingerprint = c.GetInt("SAVED_SEARCHES_NOTIFIER_MAX_FINGERPRINT", "10000", "The maximum number of result hashes stored per saved search to detect new matches.")
+
+	thresholds, err := parseThresholds(rawThresholds)
+	if err != nil {
+		c.AddError(errors.Wrap(err, "SAVED_SEARCHES_NOTIFIER_THRESHOLDS"))
+	}
+	c.Thresholds = thresholds
+}
+
+// parseThresholds parses a comma-separated list of positive integers and
+// returns them in ascending order.
+func parseThresholds(raw string) ([]int, error) {
+	var thresholds []int
+	for _, s := range strings.Split(raw, ",") {
+		s = strings.TrimSpace(s)
+		if s == "" {
+			continue
+		}
+		n, err := strconv.Atoi(s)
+		if err != nil {
+			return nil, err
+		}
+		if n <= 0 {
+			return nil, errors.Newf("threshold must be positive, got %d", n)
+		}
+		thresholds = append(thresholds, n)
+	}
+	sort.Ints(thresholds)
+	return thresholds, nil
+}
+
+type savedSearchesJob struct{}
+
+func NewSavedSearchesNotifierJob() job.Job {
+	return &savedSearchesJob{}
+}
+
+func (j *savedSearchesJob) Description() string {
+	return "Periodically executes saved searches and notifies their owners about changes in the results."
+}
+
+func (j *savedSearchesJob) Config() []env.Config {
+	return []env.Config{savedSearchesConfigInst}
+}
+
+func (j *savedSearchesJob) Routines(_ context.Context, observationCtx *observation.Context) ([]goroutine.BackgroundRoutine, error) {
+	db, err := workerdb.InitDB(observationCtx)
+	if err != nil {
+		return nil, errors.Wrap(err, "init DB")
+	}
+
+	logger := observationCtx.Logger.Scoped("saved-searches-notifier", "executes saved searches and notifies their owners")
+
+	return []goroutine.BackgroundRoutine{
+		goroutine.NewPeriodicGoroutine(
+			context.Background(),
+			&savedSearchNotifier{
+				logger:         logger,
+				db:             db,
+				store:          newSavedSearchStateStore(db),
+				searchClient:   client.New(logger, db),
+				thresholds:     savedSearchesConfigInst.Thresholds,
+				maxFingerprint: savedSearchesConfigInst.MaxFingerprint,
+			},
+			goroutine.WithName("search.saved-searches-notifier"),
+			goroutine.WithDescription("executes saved searches with notifications enabled and notifies owners about changes"),
+			goroutine.WithInterval(savedSearchesConfigInst.Interval),
+		),
+	}, nil
+}
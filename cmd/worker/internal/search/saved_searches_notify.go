@@ -0,0 +1,132 @@
+package search
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/slack-go/slack"
+
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/errcode"
+	"github.com/sourcegraph/sourcegraph/internal/httpcli"
+	"github.com/sourcegraph/sourcegraph/internal/txemail"
+	"github.com/sourcegraph/sourcegraph/internal/txemail/txtypes"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+type templateDataSavedSearchChange struct {
+	Description      string
+	Query            string
+	SearchURL        string
+	ResultCount      int
+	PreviousCount    int
+	NewMatches       int
+	CrossedThreshold int
+}
+
+var savedSearchChangeEmailTemplates = txemail.MustValidate(txtypes.Templates{
+	Subject: `Sourcegraph saved search "{{.Description}}" has {{.ResultCount}} results`,
+	Text: `
+The results of your saved search "{{.Description}}" changed.
+
+Query: {{.Query}}
+Results: {{.ResultCount}} (previously {{.PreviousCount}})
+{{- if .NewMatches}}
+New matches: {{.NewMatches}}
+{{- end}}
+{{- if .CrossedThreshold}}
+The number of results crossed {{.CrossedThreshold}}.
+{{- end}}
+
+View the results: {{.SearchURL}}
+`,
+	HTML: `
+<p>The results of your saved search <strong>{{.Description}}</strong> changed.</p>
+
+<p>
+Query: <code>{{.Query}}</code><br>
+Results: {{.ResultCount}} (previously {{.PreviousCount}})
+{{- if .NewMatches}}<br>
+New matches: {{.NewMatches}}
+{{- end}}
+{{- if .CrossedThreshold}}<br>
+The number of results crossed {{.CrossedThreshold}}.
+{{- end}}
+</p>
+
+<p><a href="{{.SearchURL}}">View the results</a></p>
+`,
+})
+
+func sendSavedSearchEmail(ctx context.Context, db database.DB, userID int32, data *templateDataSavedSearchChange) error {
+	email, verified, err := db.UserEmails().GetPrimaryEmail(ctx, userID)
+	if err != nil {
+		if errcode.IsNotFound(err) {
+			return errors.Errorf("unable to send email to user ID %d with unknown email address", userID)
+		}
+		return errors.Wrapf(err, "getting primary email for user ID %d", userID)
+	}
+	if !verified {
+		return errors.Newf("unable to send email to user ID %d's unverified primary email address", userID)
+	}
+
+	if err := txemail.Send(ctx, "saved-search", txtypes.Message{
+		To:       []string{email},
+		Template: savedSearchChangeEmailTemplates,
+		Data:     data,
+	}); err != nil {
+		return errors.Wrapf(err, "sending email to user ID %d", userID)
+	}
+	return nil
+}
+
+func savedSearchSlackPayload(data *templateDataSavedSearchChange) *slack.WebhookMessage {
+	text := fmt.Sprintf("Sourcegraph saved search *%s* has *%d* results (previously %d).", data.Description, data.ResultCount, data.PreviousCount)
+	if data.NewMatches > 0 {
+		text += fmt.Sprintf(" %d new matches.", data.NewMatches)
+	}
+	if data.CrossedThreshold > 0 {
+		text += fmt.Sprintf(" The number of results crossed %d.", data.CrossedThreshold)
+	}
+
+	blocks := []slack.Block{
+		slack.NewSectionBlock(slack.NewTextBlockObject("mrkdwn", text, false, false), nil, nil),
+	}
+	if data.SearchURL != "" {
+		blocks = append(blocks, slack.NewSectionBlock(
+			slack.NewTextBlockObject("mrkdwn", fmt.Sprintf("<%s|View results>", data.SearchURL), false, false),
+			nil,
+			nil,
+		))
+	}
+	return &slack.WebhookMessage{Blocks: &slack.Blocks{BlockSet: blocks}}
+}
+
+func sendSavedSearchSlackNotification(ctx context.Context, webhookURL string, data *templateDataSavedSearchChange) error {
+	raw, err := json.Marshal(savedSearchSlackPayload(data))
+	if err != nil {
+		return errors.Wrap(err, "marshal failed")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhookURL, bytes.NewReader(raw))
+	if err != nil {
+		return errors.Wrap(err, "failed new request")
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := httpcli.ExternalDoer.Do(req)
+	if err != nil {
+		return errors.Wrap(err, "failed to post webhook")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return errors.Newf("unexpected status code %d posting Slack webhook: %s", resp.StatusCode, string(body))
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+package search
+
+import (
+	"context"
+	"time"
+
+	"github.com/keegancsmith/sqlf"
+	"github.com/lib/pq"
+
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
+)
+
+// savedSearchState is the outcome of the last execution of a saved search as
+// a single user. UserID is nil for the state that the Slack notifications of
+// organization saved searches are based on.
+type savedSearchState struct {
+	SavedSearchID int32
+	UserID        *int32
+	ResultCount   int
+	Fingerprint   fingerprint
+	LastRunAt     time.Time
+}
+
+// savedSearchStateStore persists savedSearchState in the
+// saved_search_notification_states table.
+type savedSearchStateStore struct {
+	*basestore.Store
+}
+
+func newSavedSearchStateStore(db database.DB) *savedSearchStateStore {
+	return &savedSearchStateStore{Store: basestore.NewWithHandle(db.Handle())}
+}
+
+// Get returns the state recorded for the given saved search and user. The
+// second return value is false if the saved search has never been executed as
+// that user.
+func (s *savedSearchStateStore) Get(ctx context.Context, savedSearchID int32, userID *int32) (*savedSearchState, bool, error) {
+	return scanFirstSavedSearchState(s.Query(ctx, sqlf.Sprintf(getSavedSearchStateQuery, savedSearchID, userID)))
+}
+
+const getSavedSearchStateQuery = `
+SELECT saved_search_id, user_id, result_count, fingerprint, last_run_at
+FROM saved_search_notification_states
+WHERE saved_search_id = %s AND user_id IS NOT DISTINCT FROM %s
+`
+
+// Upsert records the state of the latest execution of a saved search.
+func (s *savedSearchStateStore) Upsert(ctx context.Context, state *savedSearchState) error {
+	return s.Exec(ctx, sqlf.Sprintf(
+		upsertSavedSearchStateQuery,
+		state.SavedSearchID,
+		state.UserID,
+		state.ResultCount,
+		pq.Array(state.Fingerprint),
+		state.LastRunAt,
+	))
+}
+
+const upsertSavedSearchStateQuery = `
+INSERT INTO saved_search_notification_states (saved_search_id, user_id, result_count, fingerprint, last_run_at)
+VALUES (%s, %s, %s, %s, %s)
+ON CONFLICT (saved_search_id, COALESCE(user_id, 0)) DO UPDATE SET
+	result_count = EXCLUDED.result_count,
+	fingerprint = EXCLUDED.fingerprint,
+	last_run_at = EXCLUDED.last_run_at
+`
+
+var scanFirstSavedSearchState = basestore.NewFirstScanner(func(s dbutil.Scanner) (*savedSearchState, error) {
+	var state savedSearchState
+	var hashes []int64
+	if err := s.Scan(&state.SavedSearchID, &state.UserID, &state.ResultCount, pq.Array(&hashes), &state.LastRunAt); err != nil {
+		return nil, err
+	}
+	state.Fingerprint = fingerprint(hashes)
+	return &state, nil
+})
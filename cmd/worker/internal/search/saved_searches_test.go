@@ -0,0 +1,131 @@
+package search
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/search/result"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+)
+
+func TestParseThresholds(t *testing.T) {
+	thresholds, err := parseThresholds("100, 1,10,,1000")
+	require.NoError(t, err)
+	require.Equal(t, []int{1, 10, 100, 1000}, thresholds)
+
+	_, err = parseThresholds("1,-5")
+	require.Error(t, err)
+
+	_, err = parseThresholds("1,ten")
+	require.Error(t, err)
+}
+
+func TestCrossedThreshold(t *testing.T) {
+	thresholds := []int{1, 10, 100}
+
+	cases := []struct {
+		name      string
+		prev      int
+		next      int
+		threshold int
+		crossed   bool
+	}{
+		{name: "unchanged", prev: 5, next: 5},
+		{name: "within bucket", prev: 2, next: 9},
+		{name: "first result", prev: 0, next: 1, threshold: 1, crossed: true},
+		{name: "upwards", prev: 9, next: 10, threshold: 10, crossed: true},
+		{name: "downwards", prev: 10, next: 9, threshold: 10, crossed: true},
+		{name: "multiple", prev: 0, next: 500, threshold: 100, crossed: true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			threshold, crossed := crossedThreshold(thresholds, tc.prev, tc.next)
+			require.Equal(t, tc.crossed, crossed)
+			require.Equal(t, tc.threshold, threshold)
+		})
+	}
+}
+
+func TestFingerprint(t *testing.T) {
+	fileMatch := func(repo, path, commit string) result.Match {
+		return &result.FileMatch{File: result.File{
+			Repo:     types.MinimalRepo{Name: api.RepoName(repo)},
+			Path:     path,
+			CommitID: api.CommitID(commit),
+		}}
+	}
+
+	prev := newFingerprint(result.Matches{
+		fileMatch("a", "main.go", "c1"),
+		fileMatch("a", "main.go", "c1"),
+		fileMatch("b", "README.md", "c1"),
+	}, 0)
+	require.Len(t, prev, 2)
+
+	t.Run("commit changes do not produce new matches", func(t *testing.T) {
+		next := newFingerprint(result.Matches{
+			fileMatch("a", "main.go", "c2"),
+			fileMatch("b", "README.md", "c2"),
+		}, 0)
+		require.Equal(t, 0, next.countNew(prev, false))
+	})
+
+	t.Run("new paths produce new matches", func(t *testing.T) {
+		next := newFingerprint(result.Matches{
+			fileMatch("a", "main.go", "c2"),
+			fileMatch("a", "util.go", "c2"),
+			fileMatch("c", "main.go", "c2"),
+		}, 0)
+		require.Equal(t, 2, next.countNew(prev, false))
+	})
+
+	t.Run("truncated fingerprints ignore unknown hashes", func(t *testing.T) {
+		full := fingerprint{1, 2, 3, 4}
+		truncated := fingerprint{1, 3}
+		require.Equal(t, 1, full.countNew(truncated, true))
+		require.Equal(t, 2, full.countNew(truncated, false))
+	})
+
+	t.Run("max size", func(t *testing.T) {
+		fp := newFingerprint(result.Matches{
+			fileMatch("a", "1", ""),
+			fileMatch("a", "2", ""),
+			fileMatch("a", "3", ""),
+		}, 2)
+		require.Len(t, fp, 2)
+		require.Less(t, fp[0], fp[1])
+	})
+}
+
+func TestIntersectMatches(t *testing.T) {
+	fileMatch := func(repo, path string) result.Match {
+		return &result.FileMatch{File: result.File{
+			Repo: types.MinimalRepo{Name: api.RepoName(repo)},
+			Path: path,
+		}}
+	}
+
+	a := result.Matches{fileMatch("public", "a.go"), fileMatch("private", "b.go"), fileMatch("public", "c.go")}
+	b := result.Matches{fileMatch("public", "c.go"), fileMatch("public", "a.go")}
+
+	require.Equal(t, result.Matches{a[0], a[2]}, intersectMatches(a, b))
+	require.Empty(t, intersectMatches(a, nil))
+}
+
+func TestDiffSavedSearchStates(t *testing.T) {
+	prev := &savedSearchState{ResultCount: 3, Fingerprint: fingerprint{1, 2, 3}}
+
+	_, notify := diffSavedSearchStates(prev, &savedSearchState{ResultCount: 3, Fingerprint: fingerprint{1, 2, 3}}, []int{10}, 100)
+	require.False(t, notify)
+
+	change, notify := diffSavedSearchStates(prev, &savedSearchState{ResultCount: 3, Fingerprint: fingerprint{1, 2, 4}}, []int{10}, 100)
+	require.True(t, notify)
+	require.Equal(t, savedSearchChange{ResultCount: 3, PreviousCount: 3, NewMatches: 1}, change)
+
+	change, notify = diffSavedSearchStates(prev, &savedSearchState{ResultCount: 12, Fingerprint: fingerprint{1, 2}}, []int{10}, 100)
+	require.True(t, notify)
+	require.Equal(t, savedSearchChange{ResultCount: 12, PreviousCount: 3, CrossedThreshold: 10}, change)
+}
@@ -0,0 +1,114 @@
+package search
+
+import (
+	"context"
+	"time"
+
+	"github.com/sourcegraph/log"
+
+	"github.com/sourcegraph/sourcegraph/cmd/worker/job"
+	workerdb "github.com/sourcegraph/sourcegraph/cmd/worker/shared/init/db"
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/env"
+	"github.com/sourcegraph/sourcegraph/internal/goroutine"
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+	"github.com/sourcegraph/sourcegraph/internal/search/searchcontexts"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+type searchContextsConfig struct {
+	env.BaseConfig
+
+	Interval time.Duration
+	MaxAge   time.Duration
+}
+
+var searchContextsConfigInst = &searchContextsConfig{}
+
+func (c *searchContextsConfig) Load() {
+	c.Interval = c.GetInterval("SEARCH_CONTEXTS_MATERIALIZER_INTERVAL", "1m", "How frequently to check for query-based search contexts whose repositories need to be materialized.")
+	c.MaxAge = c.GetInterval("SEARCH_CONTEXTS_MATERIALIZER_MAX_AGE", "24h", "The age after which the materialized repositories of a search context are refreshed even if no repository changed.")
+}
+
+type searchContextsMaterializerJob struct{}
+
+func NewSearchContextsMaterializerJob() job.Job {
+	return &searchContextsMaterializerJob{}
+}
+
+func (j *searchContextsMaterializerJob) Description() string {
+	return "Materializes the repositories matched by query-based search contexts."
+}
+
+func (j *searchContextsMaterializerJob) Config() []env.Config {
+	return []env.Config{searchContextsConfigInst}
+}
+
+func (j *searchContextsMaterializerJob) Routines(_ context.Context, observationCtx *observation.Context) ([]goroutine.BackgroundRoutine, error) {
+	db, err := workerdb.InitDB(observationCtx)
+	if err != nil {
+		return nil, errors.Wrap(err, "init DB")
+	}
+
+	return []goroutine.BackgroundRoutine{
+		goroutine.NewPeriodicGoroutine(
+			actor.WithInternalActor(context.Background()),
+			&searchContextsMaterializer{
+				logger: observationCtx.Logger.Scoped("search-contexts-materializer", "materializes the repositories of query-based search contexts"),
+				db:     db,
+				maxAge: searchContextsConfigInst.MaxAge,
+			},
+			goroutine.WithName("search.search-contexts-materializer"),
+			goroutine.WithDescription("materializes the repositories matched by query-based search contexts"),
+			goroutine.WithInterval(searchContextsConfigInst.Interval),
+		),
+	}, nil
+}
+
+// searchContextsMaterializer resolves the queries of query-based search
+// contexts to repositories and stores them, so that searches scoped to those
+// contexts do not need to evaluate the query against all repositories.
+type searchContextsMaterializer struct {
+	logger log.Logger
+	db     database.DB
+	maxAge time.Duration
+}
+
+var _ goroutine.Handler = &searchContextsMaterializer{}
+
+func (m *searchContextsMaterializer) Handle(ctx context.Context) error {
+	// 🚨 SECURITY: Repositories are materialized for all users, so they must
+	// be resolved without the permissions of a particular user. Permissions
+	// are enforced when the materialized repositories are read.
+	ctx = actor.WithInternalActor(ctx)
+
+	searchContexts, changeID, err := m.db.SearchContexts().ListSearchContextsToMaterialize(ctx, m.maxAge)
+	if err != nil {
+		return errors.Wrap(err, "listing search contexts to materialize")
+	}
+
+	var errs error
+	for _, sc := range searchContexts {
+		if !searchcontexts.CanMaterializeQuery(sc.Query) {
+			continue
+		}
+
+		repoRevs, err := searchcontexts.ResolveQueryRepositoryRevisions(ctx, m.db, sc.Query)
+		if err == nil {
+			err = m.db.SearchContexts().SetMaterializedRepositoryRevisions(ctx, sc, changeID, repoRevs)
+		}
+		if err != nil {
+			errs = errors.Append(errs, errors.Wrapf(err, "materializing repositories of search context %d", sc.ID))
+			// Back off so that a search context which cannot be materialized
+			// does not delay the others on every run.
+			if err := m.db.SearchContexts().MarkMaterializationFailed(ctx, sc.ID, err.Error(), m.maxAge); err != nil {
+				errs = errors.Append(errs, errors.Wrapf(err, "recording materialization failure of search context %d", sc.ID))
+			}
+			continue
+		}
+
+		m.logger.Debug("materialized search context repositories", log.Int64("searchContextID", sc.ID), log.Int("repos", len(repoRevs)))
+	}
+	return errs
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/prometheus/client_golang/prometheus"
 
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/globals"
+	"github.com/sourcegraph/sourcegraph/cmd/worker/internal/auditlogexporter"
 	"github.com/sourcegraph/sourcegraph/cmd/worker/internal/auth"
 	"github.com/sourcegraph/sourcegraph/cmd/worker/internal/batches"
 	"github.com/sourcegraph/sourcegraph/cmd/worker/internal/codeintel"
@@ -100,6 +101,7 @@ func LoadConfig(registerEnterpriseMigrators oobmigration.RegisterMigratorsFunc)
 		"permission-sync-job-scheduler":         permissions.NewPermissionSyncJobScheduler(),
 		"export-usage-telemetry":                telemetry.NewTelemetryJob(),
 		"telemetrygateway-exporter":             telemetrygatewayexporter.NewJob(),
+		"audit-log-exporter":                    auditlogexporter.NewJob(),
 
 		"codeintel-policies-repository-matcher":       codeintel.NewPoliciesRepositoryMatcherJob(),
 		"codeintel-autoindexing-summary-builder":      codeintel.NewAutoindexingSummaryBuilder(),
@@ -117,6 +119,7 @@ func LoadConfig(registerEnterpriseMigrators oobmigration.RegisterMigratorsFunc)
 		"codeintel-package-filter-applicator":         codeintel.NewPackagesFilterApplicatorJob(),
 
 		"auth-sourcegraph-operator-cleaner": auth.NewSourcegraphOperatorCleaner(),
+		"auth-access-token-expirer":         auth.NewAccessTokenExpirer(),
 
 		"repo-embedding-janitor":   repoembeddings.NewRepoEmbeddingJanitorJob(),
 		"repo-embedding-job":       repoembeddings.NewRepoEmbeddingJob(),
@@ -126,7 +129,10 @@ func LoadConfig(registerEnterpriseMigrators oobmigration.RegisterMigratorsFunc)
 
 		"github-apps-installation-validation-job": githubapps.NewGitHubApsInstallationJob(),
 
-		"exhaustive-search-job": search.NewSearchJob(),
+		"exhaustive-search-job":   search.NewSearchJob(),
+		"saved-searches-notifier": search.NewSavedSearchesNotifierJob(),
+
+		"search-contexts-materializer": search.NewSearchContextsMaterializerJob(),
 	}
 
 	var config Config
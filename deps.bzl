@@ -2474,6 +2474,20 @@ def go_dependencies():
         sum = "h1:qZNfIGkIANxGv/OqtnntR4DfOY2+BgwR60cAcu/i3SE=",
         version = "v0.0.0-20190211030409-01e6764cf0a4",
     )
+    go_repository(
+        name = "com_github_go_webauthn_webauthn",
+        build_file_proto_mode = "disable_global",
+        importpath = "github.com/go-webauthn/webauthn",
+        sum = "h1:bKMtL1qzd2WTFkf1mFTVbreYrwn7dsYmEPjTq6QN90E=",
+        version = "v0.8.6",
+    )
+    go_repository(
+        name = "com_github_go_webauthn_x",
+        build_file_proto_mode = "disable_global",
+        importpath = "github.com/go-webauthn/x",
+        sum = "h1:sGmIFhcY70l6k7JIDfnjVBiAAFEssga5lXIUXe0GtAs=",
+        version = "v0.1.4",
+    )
 
     go_repository(
         name = "com_github_go_zookeeper_zk",
@@ -2717,6 +2731,13 @@ def go_dependencies():
         sum = "h1:7cYmW1XlMY7h7ii7UhUyChSgS5wUJEnm9uZVTGqOWzg=",
         version = "v4.5.0",
     )
+    go_repository(
+        name = "com_github_golang_jwt_jwt_v5",
+        build_file_proto_mode = "disable_global",
+        importpath = "github.com/golang-jwt/jwt/v5",
+        sum = "h1:1n1XNM9hk7O9mnQoNBGolZvzebBQ7p93ULHRc28XJUE=",
+        version = "v5.0.0",
+    )
     go_repository(
         name = "com_github_golang_lint",
         build_file_proto_mode = "disable_global",
@@ -2889,6 +2910,13 @@ def go_dependencies():
         sum = "h1:AnCroh3fv4ZBgVIf1Iwtovgjaw/GiKJo8M8yD/fhyJ8=",
         version = "v1.1.0",
     )
+    go_repository(
+        name = "com_github_google_go_tpm",
+        build_file_proto_mode = "disable_global",
+        importpath = "github.com/google/go-tpm",
+        sum = "h1:sQF6YqWMi+SCXpsmS3fd21oPy/vSddwZry4JnmltHVk=",
+        version = "v0.9.0",
+    )
 
     go_repository(
         name = "com_github_google_gofuzz",
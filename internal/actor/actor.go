@@ -45,6 +45,12 @@ type Actor struct {
 	// cookie, logout would be ineffective.)
 	FromSessionCookie bool `json:"-"`
 
+	// AccessTokenScopes is the set of fine-grained scopes of the access token used to
+	// authenticate the actor. It is nil unless the actor was authenticated with an access token
+	// that does not grant full control of the user account, in which case the actor may only
+	// perform the operations permitted by these scopes.
+	AccessTokenScopes []string `json:"-"`
+
 	// user is populated lazily by (*Actor).User()
 	user     *types.User
 	userErr  error
@@ -87,6 +93,26 @@ func (a *Actor) IsInternal() bool {
 	return a != nil && a.Internal
 }
 
+// IsScopeRestricted returns true if the Actor was authenticated with an access token that
+// only grants a restricted set of fine-grained scopes.
+func (a *Actor) IsScopeRestricted() bool {
+	return a != nil && a.AccessTokenScopes != nil
+}
+
+// HasAccessTokenScope returns true if the Actor is permitted to perform operations requiring
+// the given access token scope. Actors that are not scope restricted have every scope.
+func (a *Actor) HasAccessTokenScope(scope string) bool {
+	if !a.IsScopeRestricted() {
+		return true
+	}
+	for _, s := range a.AccessTokenScopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
 // IsMockUser returns true if the Actor is a test user.
 func (a *Actor) IsMockUser() bool {
 	return a != nil && a.mockUser
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/sourcegraph/log"
@@ -55,6 +56,22 @@ func Log(ctx context.Context, logger log.Logger, record Record) {
 	loggerFunc := getLoggerFuncWithSeverity(logger)
 	// message string looks like: #{record.Action} (sampling immunity token: #{auditId})
 	loggerFunc(fmt.Sprintf("%s (sampling immunity token: %s)", record.Action, auditId), fields...)
+
+	if outbox := currentOutbox.Load(); outbox != nil && !record.ExcludeFromSinks && len(SinkKeys(siteConfig)) > 0 {
+		outbox.enqueue(Entry{
+			AuditID:   auditId,
+			Timestamp: time.Now().UTC(),
+			Entity:    record.Entity,
+			Action:    record.Action,
+			Actor: EntryActor{
+				ActorUID:     actorId(act),
+				IP:           ip(client),
+				UserAgent:    userAgent(client),
+				ForwardedFor: forwardedFor(client),
+			},
+			Event: fieldsToMap(record.Fields),
+		})
+	}
 }
 
 func actorId(act *actor.Actor) string {
@@ -95,6 +112,10 @@ type Record struct {
 	Action string
 	// Fields hold any additional context relevant to the Action
 	Fields []log.Field
+	// ExcludeFromSinks excludes the record from the entries delivered to the
+	// sinks configured in log.auditLog.sinks, for callers that queue their own
+	// entries.
+	ExcludeFromSinks bool
 
 	// auditIDGenerator can be provided in tests to generate a stable audit
 	// log ID.
@@ -107,6 +128,8 @@ const (
 	GitserverAccess = iota
 	InternalTraffic
 	GraphQL
+	SearchQueries
+	FileViews
 )
 
 // IsEnabled returns the value of the respective setting from the site config (if set).
@@ -121,6 +144,10 @@ func IsEnabled(cfg schema.SiteConfiguration, setting AuditLogSetting) bool {
 			return auditCfg.InternalTraffic
 		case GraphQL:
 			return auditCfg.GraphQL
+		case SearchQueries:
+			return auditCfg.SearchQueries
+		case FileViews:
+			return auditCfg.FileViews
 		}
 	}
 	// all settings now currently default to 'false', but that's a coincidence, not intention
@@ -166,12 +166,12 @@ func TestIsEnabled(t *testing.T) {
 		{
 			name:     "empty log results in default audit log settings",
 			cfg:      schema.SiteConfiguration{},
-			expected: map[AuditLogSetting]bool{GitserverAccess: false, InternalTraffic: false, GraphQL: false},
+			expected: map[AuditLogSetting]bool{GitserverAccess: false, InternalTraffic: false, GraphQL: false, SearchQueries: false, FileViews: false},
 		},
 		{
 			name:     "empty audit log config results in default audit log settings",
 			cfg:      schema.SiteConfiguration{Log: &schema.Log{}},
-			expected: map[AuditLogSetting]bool{GitserverAccess: false, InternalTraffic: false, GraphQL: false},
+			expected: map[AuditLogSetting]bool{GitserverAccess: false, InternalTraffic: false, GraphQL: false, SearchQueries: false, FileViews: false},
 		},
 		{
 			name: "fully populated audit log is read  correctly",
@@ -181,8 +181,10 @@ func TestIsEnabled(t *testing.T) {
 						InternalTraffic: true,
 						GitserverAccess: true,
 						GraphQL:         true,
+						SearchQueries:   true,
+						FileViews:       true,
 					}}},
-			expected: map[AuditLogSetting]bool{GitserverAccess: true, InternalTraffic: true, GraphQL: true},
+			expected: map[AuditLogSetting]bool{GitserverAccess: true, InternalTraffic: true, GraphQL: true, SearchQueries: true, FileViews: true},
 		},
 	}
 	for _, tt := range tests {
@@ -0,0 +1,78 @@
+package auditsink
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"sync"
+
+	"gopkg.in/natefinch/lumberjack.v2"
+
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+const (
+	defaultFileMaxSizeMB  = 100
+	defaultFileMaxBackups = 10
+)
+
+// fileSink appends entries as JSON lines to a file that is rotated when it
+// reaches its maximum size.
+type fileSink struct {
+	mu     sync.Mutex
+	logger *lumberjack.Logger
+}
+
+// NewFile returns a sink that writes entries to a rotating file.
+func NewFile(config schema.FileAuditLogSink) (Sink, error) {
+	if config.Path == "" {
+		return nil, errors.New("path is required")
+	}
+	if !filepath.IsAbs(config.Path) {
+		return nil, errors.Errorf("path %q must be absolute", config.Path)
+	}
+
+	maxSize := config.MaxSizeMB
+	if maxSize == 0 {
+		maxSize = defaultFileMaxSizeMB
+	}
+	maxBackups := config.MaxBackups
+	if maxBackups == 0 {
+		maxBackups = defaultFileMaxBackups
+	}
+
+	return &fileSink{logger: &lumberjack.Logger{
+		Filename:   config.Path,
+		MaxSize:    maxSize,
+		MaxBackups: maxBackups,
+		MaxAge:     config.MaxAgeDays,
+		Compress:   config.Compress,
+	}}, nil
+}
+
+func (s *fileSink) Deliver(_ context.Context, entries []Entry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, entry := range entries {
+		var line bytes.Buffer
+		if err := json.Compact(&line, entry.Payload); err != nil {
+			return errors.Wrapf(err, "invalid payload of audit log entry %d", entry.ID)
+		}
+		line.WriteByte('\n')
+		// Each line is written separately, so that the file is only rotated
+		// between lines.
+		if _, err := s.logger.Write(line.Bytes()); err != nil {
+			return errors.Wrapf(err, "writing to %q", s.logger.Filename)
+		}
+	}
+	return nil
+}
+
+func (s *fileSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.logger.Close()
+}
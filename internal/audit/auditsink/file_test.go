@@ -0,0 +1,41 @@
+package auditsink
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+func TestFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	sink, err := NewFile(schema.FileAuditLogSink{Type: "file", Path: path})
+	require.NoError(t, err)
+	t.Cleanup(func() { sink.Close() })
+
+	require.NoError(t, sink.Deliver(context.Background(), testEntries))
+	require.NoError(t, sink.Deliver(context.Background(), testEntries[:1]))
+
+	b, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, strings.Join([]string{
+		`{"auditId":"a","action":"SignInSucceeded"}`,
+		`{"auditId":"b"}`,
+		`{"auditId":"a","action":"SignInSucceeded"}`,
+		"",
+	}, "\n"), string(b))
+}
+
+func TestNewFileConfig(t *testing.T) {
+	_, err := NewFile(schema.FileAuditLogSink{Type: "file"})
+	assert.ErrorContains(t, err, "path is required")
+
+	_, err = NewFile(schema.FileAuditLogSink{Type: "file", Path: "audit.jsonl"})
+	assert.ErrorContains(t, err, `path "audit.jsonl" must be absolute`)
+}
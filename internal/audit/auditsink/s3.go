@@ -0,0 +1,111 @@
+package auditsink
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+const defaultS3Prefix = "audit-log/"
+
+// s3API is the subset of the S3 API used by the sink.
+type s3API interface {
+	PutObject(ctx context.Context, input *s3.PutObjectInput, optFns ...func(*s3.Options)) (*s3.PutObjectOutput, error)
+}
+
+// s3Sink uploads each batch of entries as a gzip-compressed JSON lines object.
+// The key of an object is derived from the IDs of the first and last entries of
+// the batch, so a batch that is uploaded again after a failure overwrites its
+// object if the batch is unchanged.
+type s3Sink struct {
+	client s3API
+	bucket string
+	prefix string
+}
+
+// NewS3 returns a sink that uploads entries to an S3-compatible bucket.
+func NewS3(ctx context.Context, config schema.S3AuditLogSink) (Sink, error) {
+	if config.Bucket == "" {
+		return nil, errors.New("bucket is required")
+	}
+
+	var optFns []func(*awsconfig.LoadOptions) error
+	if config.Region != "" {
+		optFns = append(optFns, awsconfig.WithRegion(config.Region))
+	}
+	if config.CredentialsFile != "" {
+		optFns = append(optFns, awsconfig.WithSharedCredentialsFiles([]string{config.CredentialsFile}))
+	}
+	cfg, err := awsconfig.LoadDefaultConfig(ctx, optFns...)
+	if err != nil {
+		return nil, errors.Wrap(err, "loading AWS config")
+	}
+
+	client := s3.NewFromConfig(cfg, func(o *s3.Options) {
+		if config.Endpoint != "" {
+			o.EndpointResolver = s3.EndpointResolverFromURL(config.Endpoint)
+		}
+		o.UsePathStyle = config.UsePathStyle
+	})
+	return newS3(client, config), nil
+}
+
+func newS3(client s3API, config schema.S3AuditLogSink) *s3Sink {
+	prefix := config.Prefix
+	if prefix == "" {
+		prefix = defaultS3Prefix
+	}
+	return &s3Sink{client: client, bucket: config.Bucket, prefix: prefix}
+}
+
+func (s *s3Sink) Deliver(ctx context.Context, entries []Entry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	for _, entry := range entries {
+		var line bytes.Buffer
+		if err := json.Compact(&line, entry.Payload); err != nil {
+			return errors.Wrapf(err, "invalid payload of audit log entry %d", entry.ID)
+		}
+		line.WriteByte('\n')
+		if _, err := gz.Write(line.Bytes()); err != nil {
+			return err
+		}
+	}
+	if err := gz.Close(); err != nil {
+		return err
+	}
+
+	key := s.objectKey(entries)
+	if _, err := s.client.PutObject(ctx, &s3.PutObjectInput{
+		Bucket:        aws.String(s.bucket),
+		Key:           aws.String(key),
+		Body:          bytes.NewReader(buf.Bytes()),
+		ContentLength: int64(buf.Len()),
+		ContentType:   aws.String("application/gzip"),
+	}); err != nil {
+		return errors.Wrapf(err, "uploading %q to bucket %q", key, s.bucket)
+	}
+	return nil
+}
+
+// objectKey returns the key of the object of the batch, which is stored under
+// the date of its first entry, e.g. audit-log/2023/10/20/00000000000000000001-00000000000000000100.jsonl.gz.
+func (s *s3Sink) objectKey(entries []Entry) string {
+	first, last := entries[0], entries[len(entries)-1]
+	return fmt.Sprintf("%s%s/%020d-%020d.jsonl.gz", s.prefix, first.Timestamp.UTC().Format("2006/01/02"), first.ID, last.ID)
+}
+
+func (s *s3Sink) Close() error { return nil }
@@ -0,0 +1,56 @@
+package auditsink
+
+import (
+	"compress/gzip"
+	"context"
+	"io"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+type fakeS3 struct {
+	objects map[string]string
+	err     error
+}
+
+func (f *fakeS3) PutObject(_ context.Context, input *s3.PutObjectInput, _ ...func(*s3.Options)) (*s3.PutObjectOutput, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	gz, err := gzip.NewReader(input.Body)
+	if err != nil {
+		return nil, err
+	}
+	b, err := io.ReadAll(gz)
+	if err != nil {
+		return nil, err
+	}
+	f.objects[*input.Bucket+"/"+*input.Key] = string(b)
+	return &s3.PutObjectOutput{}, nil
+}
+
+func TestS3(t *testing.T) {
+	client := &fakeS3{objects: map[string]string{}}
+	sink := newS3(client, schema.S3AuditLogSink{Type: "s3", Bucket: "audit"})
+
+	require.NoError(t, sink.Deliver(context.Background(), nil))
+	assert.Empty(t, client.objects)
+
+	require.NoError(t, sink.Deliver(context.Background(), testEntries))
+	assert.Equal(t, map[string]string{
+		"audit/audit-log/2023/10/20/00000000000000000001-00000000000000000002.jsonl.gz": `{"auditId":"a","action":"SignInSucceeded"}` + "\n" + `{"auditId":"b"}` + "\n",
+	}, client.objects)
+
+	// Uploading the same batch again overwrites the object.
+	require.NoError(t, sink.Deliver(context.Background(), testEntries))
+	assert.Len(t, client.objects, 1)
+
+	client.err = errors.New("access denied")
+	assert.ErrorContains(t, sink.Deliver(context.Background(), testEntries), `uploading "audit-log/2023/10/20/00000000000000000001-00000000000000000002.jsonl.gz" to bucket "audit": access denied`)
+}
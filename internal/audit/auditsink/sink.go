@@ -0,0 +1,45 @@
+// Package auditsink implements the destinations that audit log entries are
+// exported to, as configured in log.auditLog.sinks.
+package auditsink
+
+import (
+	"context"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+// Entry is an audit log entry to deliver.
+type Entry struct {
+	// ID uniquely identifies the entry among all entries delivered to a sink.
+	ID        int64
+	Timestamp time.Time
+	Action    string
+	// Payload is the JSON encoded entry.
+	Payload []byte
+}
+
+// Sink delivers audit log entries to an external destination.
+type Sink interface {
+	// Deliver delivers the entries in order. If it returns an error, some of the
+	// entries may have been delivered, and all of them will be delivered again.
+	Deliver(ctx context.Context, entries []Entry) error
+	// Close releases the resources held by the sink.
+	Close() error
+}
+
+// New returns the sink for the given configuration.
+func New(ctx context.Context, config *schema.AuditLogSink) (Sink, error) {
+	switch {
+	case config == nil:
+		return nil, errors.New("no sink configured")
+	case config.Syslog != nil:
+		return NewSyslog(*config.Syslog)
+	case config.File != nil:
+		return NewFile(*config.File)
+	case config.S3 != nil:
+		return NewS3(ctx, *config.S3)
+	}
+	return nil, errors.New("unknown sink type")
+}
@@ -0,0 +1,195 @@
+package auditsink
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"net"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+const (
+	defaultSyslogAppName  = "sourcegraph"
+	defaultSyslogFacility = 13 // log audit
+	syslogSeverityInfo    = 6
+
+	// syslogTimestampFormat is RFC 3339 with at most 6 fractional digits, as
+	// required by RFC 5424.
+	syslogTimestampFormat = "2006-01-02T15:04:05.999999Z07:00"
+
+	// syslogWriteTimeout bounds writes to the syslog server if the context of a
+	// delivery has no deadline.
+	syslogWriteTimeout = 30 * time.Second
+)
+
+// utf8BOM prefixes messages to mark them as UTF-8, as required by RFC 5424.
+var utf8BOM = []byte{0xEF, 0xBB, 0xBF}
+
+// syslogSink sends entries as RFC 5424 messages over a TCP or TLS connection
+// using octet-counting framing (RFC 6587 and RFC 5425). The connection is kept
+// open between deliveries and established again after a failed write.
+type syslogSink struct {
+	address   string
+	tlsConfig *tls.Config
+	hostname  string
+	appName   string
+	facility  int
+
+	mu   sync.Mutex
+	conn net.Conn
+}
+
+// NewSyslog returns a sink that sends entries to a syslog server.
+func NewSyslog(config schema.SyslogAuditLogSink) (Sink, error) {
+	host, _, err := net.SplitHostPort(config.Address)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid syslog address %q", config.Address)
+	}
+
+	s := &syslogSink{
+		address:  config.Address,
+		hostname: config.Hostname,
+		appName:  config.AppName,
+		facility: config.Facility,
+	}
+	if s.hostname == "" {
+		s.hostname, _ = os.Hostname()
+	}
+	if s.appName == "" {
+		s.appName = defaultSyslogAppName
+	}
+	if s.facility == 0 {
+		s.facility = defaultSyslogFacility
+	}
+	if s.facility < 0 || s.facility > 23 {
+		return nil, errors.Errorf("invalid syslog facility %d", s.facility)
+	}
+
+	if config.Tls {
+		s.tlsConfig = &tls.Config{ServerName: host, MinVersion: tls.VersionTLS12}
+		if config.CaCertificate != "" {
+			pool := x509.NewCertPool()
+			if !pool.AppendCertsFromPEM([]byte(config.CaCertificate)) {
+				return nil, errors.New("caCertificate does not contain a valid PEM-encoded certificate")
+			}
+			s.tlsConfig.RootCAs = pool
+		}
+	} else if config.CaCertificate != "" {
+		return nil, errors.New("caCertificate requires tls to be enabled")
+	}
+
+	return s, nil
+}
+
+func (s *syslogSink) Deliver(ctx context.Context, entries []Entry) error {
+	var buf bytes.Buffer
+	for _, entry := range entries {
+		msg := s.format(entry)
+		buf.WriteString(strconv.Itoa(len(msg)))
+		buf.WriteByte(' ')
+		buf.Write(msg)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.conn == nil {
+		conn, err := s.dial(ctx)
+		if err != nil {
+			return errors.Wrapf(err, "connecting to syslog server %q", s.address)
+		}
+		s.conn = conn
+	}
+
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		deadline = time.Now().Add(syslogWriteTimeout)
+	}
+	if err := s.conn.SetWriteDeadline(deadline); err != nil {
+		s.closeConn()
+		return err
+	}
+	if _, err := s.conn.Write(buf.Bytes()); err != nil {
+		// The server may have received part of the messages, but we cannot tell
+		// which, so the whole batch is sent again on a new connection.
+		s.closeConn()
+		return errors.Wrapf(err, "writing to syslog server %q", s.address)
+	}
+	return nil
+}
+
+func (s *syslogSink) dial(ctx context.Context) (net.Conn, error) {
+	if s.tlsConfig != nil {
+		d := &tls.Dialer{Config: s.tlsConfig}
+		return d.DialContext(ctx, "tcp", s.address)
+	}
+	var d net.Dialer
+	return d.DialContext(ctx, "tcp", s.address)
+}
+
+func (s *syslogSink) closeConn() {
+	_ = s.conn.Close()
+	s.conn = nil
+}
+
+func (s *syslogSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.conn == nil {
+		return nil
+	}
+	err := s.conn.Close()
+	s.conn = nil
+	return err
+}
+
+// format returns the RFC 5424 message of the entry:
+//
+//	<PRI>1 TIMESTAMP HOSTNAME APP-NAME PROCID MSGID STRUCTURED-DATA MSG
+//
+// The action of the entry is used as MSGID and the JSON encoded entry as MSG.
+func (s *syslogSink) format(entry Entry) []byte {
+	var buf bytes.Buffer
+	buf.WriteByte('<')
+	buf.WriteString(strconv.Itoa(s.facility*8 + syslogSeverityInfo))
+	buf.WriteString(">1 ")
+	buf.WriteString(entry.Timestamp.UTC().Format(syslogTimestampFormat))
+	buf.WriteByte(' ')
+	buf.WriteString(syslogHeaderField(s.hostname, 255))
+	buf.WriteByte(' ')
+	buf.WriteString(syslogHeaderField(s.appName, 48))
+	buf.WriteString(" - ")
+	buf.WriteString(syslogHeaderField(entry.Action, 32))
+	buf.WriteString(" - ")
+	buf.Write(utf8BOM)
+	// MSG must not contain newlines for receivers that split on them, which
+	// compacting the JSON payload guarantees.
+	if err := json.Compact(&buf, entry.Payload); err != nil {
+		buf.Write(bytes.ReplaceAll(entry.Payload, []byte("\n"), []byte(" ")))
+	}
+	return buf.Bytes()
+}
+
+// syslogHeaderField returns the value restricted to the printable US-ASCII
+// characters and the maximum length allowed in RFC 5424 header fields, or the
+// NILVALUE "-" if it is empty.
+func syslogHeaderField(value string, maxLen int) string {
+	b := make([]byte, 0, len(value))
+	for i := 0; i < len(value) && len(b) < maxLen; i++ {
+		if c := value[i]; c >= 33 && c <= 126 {
+			b = append(b, c)
+		}
+	}
+	if len(b) == 0 {
+		return "-"
+	}
+	return string(b)
+}
@@ -0,0 +1,188 @@
+package auditsink
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"encoding/pem"
+	"io"
+	"net"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+var testEntries = []Entry{
+	{
+		ID:        1,
+		Timestamp: time.Date(2023, 10, 20, 12, 0, 0, 123456789, time.UTC),
+		Action:    "SignInSucceeded",
+		Payload:   []byte(`{"auditId": "a", "action": "SignInSucceeded"}`),
+	},
+	{
+		ID:        2,
+		Timestamp: time.Date(2023, 10, 20, 12, 1, 0, 0, time.UTC),
+		Action:    "Access Token Created",
+		Payload:   []byte("{\n  \"auditId\": \"b\"\n}"),
+	},
+}
+
+// syslogServer accepts connections and sends the received messages, parsed with
+// octet-counting framing, to a channel.
+func syslogServer(t *testing.T, ln net.Listener) <-chan string {
+	t.Helper()
+	t.Cleanup(func() { ln.Close() })
+
+	messages := make(chan string, 10)
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				r := bufio.NewReader(conn)
+				for {
+					length, err := r.ReadString(' ')
+					if err != nil {
+						return
+					}
+					n, err := strconv.Atoi(strings.TrimSuffix(length, " "))
+					if err != nil {
+						return
+					}
+					msg := make([]byte, n)
+					if _, err := io.ReadFull(r, msg); err != nil {
+						return
+					}
+					messages <- string(msg)
+				}
+			}()
+		}
+	}()
+	return messages
+}
+
+func receive(t *testing.T, messages <-chan string) string {
+	t.Helper()
+	select {
+	case msg := <-messages:
+		return msg
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for syslog message")
+		return ""
+	}
+}
+
+func TestSyslog(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	messages := syslogServer(t, ln)
+
+	sink, err := NewSyslog(schema.SyslogAuditLogSink{
+		Type:     "syslog",
+		Address:  ln.Addr().String(),
+		Hostname: "sourcegraph.example.com",
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() { sink.Close() })
+
+	require.NoError(t, sink.Deliver(context.Background(), testEntries))
+	assert.Equal(t,
+		"<110>1 2023-10-20T12:00:00.123456Z sourcegraph.example.com sourcegraph - SignInSucceeded - \ufeff"+`{"auditId":"a","action":"SignInSucceeded"}`,
+		receive(t, messages),
+	)
+	assert.Equal(t,
+		"<110>1 2023-10-20T12:01:00Z sourcegraph.example.com sourcegraph - AccessTokenCreated - \ufeff"+`{"auditId":"b"}`,
+		receive(t, messages),
+	)
+
+	t.Run("reconnects after the connection is closed", func(t *testing.T) {
+		s := sink.(*syslogSink)
+		s.mu.Lock()
+		s.conn.Close()
+		s.mu.Unlock()
+
+		// The first write fails on the closed connection.
+		require.Error(t, sink.Deliver(context.Background(), testEntries[:1]))
+		require.NoError(t, sink.Deliver(context.Background(), testEntries[:1]))
+		assert.Contains(t, receive(t, messages), "SignInSucceeded")
+	})
+}
+
+func TestSyslog_TLS(t *testing.T) {
+	// The test server is only used for its self-signed certificate.
+	srv := httptest.NewTLSServer(nil)
+	srv.Close()
+	caCertificate := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw}))
+
+	ln, err := tls.Listen("tcp", "127.0.0.1:0", &tls.Config{Certificates: srv.TLS.Certificates})
+	require.NoError(t, err)
+	messages := syslogServer(t, ln)
+
+	sink, err := NewSyslog(schema.SyslogAuditLogSink{
+		Type:          "syslog",
+		Address:       ln.Addr().String(),
+		Tls:           true,
+		CaCertificate: caCertificate,
+		AppName:       "sourcegraph-audit",
+		Facility:      10,
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() { sink.Close() })
+
+	require.NoError(t, sink.Deliver(context.Background(), testEntries[:1]))
+	assert.True(t, strings.HasPrefix(receive(t, messages), "<86>1 "))
+
+	t.Run("untrusted certificate", func(t *testing.T) {
+		sink, err := NewSyslog(schema.SyslogAuditLogSink{
+			Type:    "syslog",
+			Address: ln.Addr().String(),
+			Tls:     true,
+		})
+		require.NoError(t, err)
+		assert.ErrorContains(t, sink.Deliver(context.Background(), testEntries[:1]), "certificate")
+	})
+}
+
+func TestNewSyslogConfig(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		config schema.SyslogAuditLogSink
+		err    string
+	}{
+		{
+			name:   "missing port",
+			config: schema.SyslogAuditLogSink{Address: "syslog.example.com"},
+			err:    `invalid syslog address "syslog.example.com"`,
+		},
+		{
+			name:   "invalid facility",
+			config: schema.SyslogAuditLogSink{Address: "syslog.example.com:6514", Facility: 24},
+			err:    "invalid syslog facility 24",
+		},
+		{
+			name:   "invalid CA certificate",
+			config: schema.SyslogAuditLogSink{Address: "syslog.example.com:6514", Tls: true, CaCertificate: "invalid"},
+			err:    "caCertificate does not contain a valid PEM-encoded certificate",
+		},
+		{
+			name:   "CA certificate without TLS",
+			config: schema.SyslogAuditLogSink{Address: "syslog.example.com:6514", CaCertificate: "invalid"},
+			err:    "caCertificate requires tls to be enabled",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := NewSyslog(tc.config)
+			assert.ErrorContains(t, err, tc.err)
+		})
+	}
+}
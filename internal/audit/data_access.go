@@ -0,0 +1,81 @@
+package audit
+
+import (
+	"math/rand"
+	"strings"
+	"sync"
+
+	"github.com/grafana/regexp"
+
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+// Redacted replaces the parts of search queries and file paths that match the
+// configured redaction patterns.
+const Redacted = "[REDACTED]"
+
+// randFloat64 is replaced in tests.
+var randFloat64 = rand.Float64
+
+// SampleDataAccess reports whether a search query or file view event should be
+// recorded, according to log.auditLog.dataAccessSampleRate.
+func SampleDataAccess(cfg schema.SiteConfiguration) bool {
+	auditCfg := getAuditCfg(cfg)
+	if auditCfg == nil || auditCfg.DataAccessSampleRate <= 0 || auditCfg.DataAccessSampleRate >= 1 {
+		return true
+	}
+	return randFloat64() < auditCfg.DataAccessSampleRate
+}
+
+// RedactDataAccess replaces the matches of log.auditLog.redactionPatterns in s
+// with Redacted. If a pattern is invalid, all of s is redacted, so that a
+// configuration mistake never leaks the data it was meant to hide.
+func RedactDataAccess(cfg schema.SiteConfiguration, s string) string {
+	auditCfg := getAuditCfg(cfg)
+	if auditCfg == nil || len(auditCfg.RedactionPatterns) == 0 || s == "" {
+		return s
+	}
+
+	patterns, err := redactionPatterns.get(auditCfg.RedactionPatterns)
+	if err != nil {
+		return Redacted
+	}
+	for _, p := range patterns {
+		s = p.ReplaceAllLiteralString(s, Redacted)
+	}
+	return s
+}
+
+var redactionPatterns = &compiledPatterns{}
+
+// compiledPatterns caches the compiled redaction patterns of the current site
+// configuration, so that they are not compiled for every event.
+type compiledPatterns struct {
+	mu       sync.Mutex
+	key      string
+	patterns []*regexp.Regexp
+	err      error
+}
+
+func (c *compiledPatterns) get(exprs []string) ([]*regexp.Regexp, error) {
+	key := strings.Join(exprs, "\n")
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.key == key && (c.patterns != nil || c.err != nil) {
+		return c.patterns, c.err
+	}
+
+	c.key, c.patterns, c.err = key, nil, nil
+	patterns := make([]*regexp.Regexp, 0, len(exprs))
+	for _, expr := range exprs {
+		p, err := regexp.Compile(expr)
+		if err != nil {
+			c.err = err
+			return nil, err
+		}
+		patterns = append(patterns, p)
+	}
+	c.patterns = patterns
+	return patterns, nil
+}
@@ -0,0 +1,61 @@
+package audit
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+func TestSampleDataAccess(t *testing.T) {
+	t.Cleanup(func() { randFloat64 = rand.Float64 })
+	randFloat64 = func() float64 { return 0.5 }
+
+	withSampleRate := func(rate float64) schema.SiteConfiguration {
+		return schema.SiteConfiguration{Log: &schema.Log{AuditLog: &schema.AuditLog{DataAccessSampleRate: rate}}}
+	}
+
+	assert.True(t, SampleDataAccess(schema.SiteConfiguration{}))
+	assert.True(t, SampleDataAccess(withSampleRate(1)))
+	assert.True(t, SampleDataAccess(withSampleRate(0.75)))
+	assert.False(t, SampleDataAccess(withSampleRate(0.25)))
+}
+
+func TestRedactDataAccess(t *testing.T) {
+	withPatterns := func(patterns ...string) schema.SiteConfiguration {
+		return schema.SiteConfiguration{Log: &schema.Log{AuditLog: &schema.AuditLog{RedactionPatterns: patterns}}}
+	}
+
+	tests := []struct {
+		name string
+		cfg  schema.SiteConfiguration
+		in   string
+		want string
+	}{
+		{
+			name: "no patterns",
+			cfg:  schema.SiteConfiguration{},
+			in:   "repo:^github.com/sourcegraph/sourcegraph$ 123-45-6789",
+			want: "repo:^github.com/sourcegraph/sourcegraph$ 123-45-6789",
+		},
+		{
+			name: "all matches of every pattern",
+			cfg:  withPatterns(`[0-9]{3}-[0-9]{2}-[0-9]{4}`, `(?i)password\S*`),
+			in:   "123-45-6789 or 987-65-4321 PASSWORD=hunter2",
+			want: "[REDACTED] or [REDACTED] [REDACTED]",
+		},
+		{
+			name: "invalid pattern redacts everything",
+			cfg:  withPatterns(`ok`, `(`),
+			in:   "docs/ok.md",
+			want: Redacted,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, RedactDataAccess(tt.cfg, tt.in))
+		})
+	}
+}
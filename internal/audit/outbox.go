@@ -0,0 +1,141 @@
+package audit
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"github.com/sourcegraph/log"
+	"go.uber.org/zap/zapcore"
+
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/goroutine"
+)
+
+// Outbox queues audit log entries for delivery to the sinks configured in
+// log.auditLog.sinks. It is implemented by database.AuditLogOutboxStore.
+type Outbox interface {
+	Enqueue(ctx context.Context, sinks []string, entries []Entry) error
+}
+
+const (
+	// outboxBufferSize is the number of entries buffered in memory, and the
+	// maximum size of a batch queued in the outbox. Log blocks while both the
+	// buffer and a batch that could not be queued are full.
+	outboxBufferSize = 1000
+	// outboxFlushInterval is the interval at which buffered entries are queued
+	// in the outbox, and at which batches that could not be queued are retried.
+	outboxFlushInterval = time.Second
+)
+
+// currentOutbox is the running outbox routine the entries of Log are buffered
+// in, if any.
+var currentOutbox atomic.Pointer[outboxRoutine]
+
+type outboxRoutine struct {
+	logger  log.Logger
+	outbox  Outbox
+	entries chan Entry
+	stop    chan struct{}
+	done    chan struct{}
+}
+
+var _ goroutine.BackgroundRoutine = &outboxRoutine{}
+
+// NewOutboxRoutine returns a background routine that queues the entries written
+// by Log in the given outbox, so that they are delivered to the sinks configured
+// in log.auditLog.sinks. Entries are buffered and queued in batches, such that
+// Log only waits for the database while it cannot keep up or is unavailable.
+// Only entries written while the routine is running are queued, and buffered
+// entries are lost if the process crashes: unlike security events, which are
+// queued in the same transaction as they are stored, these entries are only
+// delivered at least once after they have been queued.
+func NewOutboxRoutine(logger log.Logger, outbox Outbox) goroutine.BackgroundRoutine {
+	return &outboxRoutine{
+		logger:  logger.Scoped("auditLogOutbox", "queues audit log entries for delivery to the audit log sinks"),
+		outbox:  outbox,
+		entries: make(chan Entry, outboxBufferSize),
+		stop:    make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+}
+
+func (r *outboxRoutine) Start() {
+	currentOutbox.Store(r)
+	defer close(r.done)
+
+	ticker := time.NewTicker(outboxFlushInterval)
+	defer ticker.Stop()
+
+	batch := make([]Entry, 0, outboxBufferSize)
+	for {
+		// While a full batch cannot be queued, stop receiving entries such
+		// that the buffer fills up and Log blocks until the batch is queued.
+		entries := r.entries
+		if len(batch) >= outboxBufferSize {
+			entries = nil
+		}
+
+		select {
+		case entry := <-entries:
+			batch = append(batch, entry)
+			if len(batch) == outboxBufferSize {
+				batch = r.flush(batch)
+			}
+		case <-ticker.C:
+			batch = r.flush(batch)
+		case <-r.stop:
+			for len(r.entries) > 0 {
+				batch = append(batch, <-r.entries)
+			}
+			if batch = r.flush(batch); len(batch) > 0 {
+				r.logger.Error("dropping audit log entries that could not be queued for delivery to the audit log sinks", log.Int("entries", len(batch)))
+			}
+			return
+		}
+	}
+}
+
+func (r *outboxRoutine) Stop() {
+	currentOutbox.CompareAndSwap(r, nil)
+	close(r.stop)
+	<-r.done
+}
+
+// enqueue buffers the entry. While the buffer is full, enqueue blocks until
+// the buffered entries are queued in the outbox.
+func (r *outboxRoutine) enqueue(entry Entry) {
+	select {
+	case r.entries <- entry:
+	case <-r.stop:
+		if len(r.flush([]Entry{entry})) > 0 {
+			r.logger.Error("dropping audit log entry that could not be queued for delivery to the audit log sinks")
+		}
+	}
+}
+
+// flush queues the entries in the outbox. If they cannot be queued, the entries
+// are returned so that they are retried by the next flush.
+func (r *outboxRoutine) flush(entries []Entry) []Entry {
+	if len(entries) == 0 {
+		return entries
+	}
+	if err := r.outbox.Enqueue(context.Background(), SinkKeys(conf.SiteConfig()), entries); err != nil {
+		r.logger.Warn("failed to queue audit log entries for delivery to the audit log sinks, retrying", log.Int("entries", len(entries)), log.Error(err))
+		return entries
+	}
+	return entries[:0]
+}
+
+// fieldsToMap encodes the fields of a record such that they are marshalled to
+// the same JSON object as in the log output.
+func fieldsToMap(fields []log.Field) map[string]any {
+	if len(fields) == 0 {
+		return nil
+	}
+	enc := zapcore.NewMapObjectEncoder()
+	for _, f := range fields {
+		f.AddTo(enc)
+	}
+	return enc.Fields
+}
@@ -0,0 +1,100 @@
+package audit
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sourcegraph/log"
+	"github.com/sourcegraph/log/logtest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+type fakeOutbox struct {
+	mu       sync.Mutex
+	failures int
+	sinks    []string
+	entries  []Entry
+}
+
+func (o *fakeOutbox) Enqueue(_ context.Context, sinks []string, entries []Entry) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if o.failures > 0 {
+		o.failures--
+		return errors.New("database unavailable")
+	}
+	o.sinks = sinks
+	o.entries = append(o.entries, entries...)
+	return nil
+}
+
+func TestOutboxRoutine(t *testing.T) {
+	conf.Mock(&conf.Unified{SiteConfiguration: schema.SiteConfiguration{Log: &schema.Log{AuditLog: &schema.AuditLog{
+		Sinks: []*schema.AuditLogSink{{File: &schema.FileAuditLogSink{Type: "file", Path: "/var/log/audit.jsonl"}}},
+	}}}})
+	t.Cleanup(func() { conf.Mock(nil) })
+
+	ctx := actor.WithActor(context.Background(), &actor.Actor{UID: 1})
+	logger := logtest.Scoped(t)
+
+	outbox := &fakeOutbox{}
+	routine := NewOutboxRoutine(logger, outbox)
+	go routine.Start()
+	require.Eventually(t, func() bool { return currentOutbox.Load() != nil }, time.Second, time.Millisecond)
+
+	Log(ctx, logger, Record{
+		Entity: "GraphQL",
+		Action: "request",
+		Fields: []log.Field{log.Object("graphql", log.String("name", "CurrentUser"))},
+	})
+	Log(ctx, logger, Record{Entity: "security events", Action: "SignInSucceeded", ExcludeFromSinks: true})
+
+	// Stopping the routine queues the buffered entries.
+	routine.Stop()
+	require.Len(t, outbox.entries, 1)
+	assert.Equal(t, []string{"file:/var/log/audit.jsonl"}, outbox.sinks)
+	entry := outbox.entries[0]
+	assert.Equal(t, "GraphQL", entry.Entity)
+	assert.Equal(t, "request", entry.Action)
+	assert.Equal(t, "1", entry.Actor.ActorUID)
+	assert.Equal(t, map[string]any{"graphql": map[string]any{"name": "CurrentUser"}}, entry.Event)
+
+	// Entries are not queued while the routine is not running.
+	Log(ctx, logger, Record{Entity: "GraphQL", Action: "request"})
+	assert.Len(t, outbox.entries, 1)
+}
+
+func TestOutboxRoutineRetriesFailedBatches(t *testing.T) {
+	conf.Mock(&conf.Unified{SiteConfiguration: schema.SiteConfiguration{Log: &schema.Log{AuditLog: &schema.AuditLog{
+		Sinks: []*schema.AuditLogSink{{File: &schema.FileAuditLogSink{Type: "file", Path: "/var/log/audit.jsonl"}}},
+	}}}})
+	t.Cleanup(func() { conf.Mock(nil) })
+
+	ctx := actor.WithActor(context.Background(), &actor.Actor{UID: 1})
+	logger := logtest.Scoped(t)
+
+	outbox := &fakeOutbox{failures: 1}
+	routine := NewOutboxRoutine(logger, outbox)
+	go routine.Start()
+	t.Cleanup(routine.Stop)
+	require.Eventually(t, func() bool { return currentOutbox.Load() != nil }, time.Second, time.Millisecond)
+
+	Log(ctx, logger, Record{Entity: "GraphQL", Action: "request"})
+
+	// The first flush fails, and the entry is queued by the next one.
+	require.Eventually(t, func() bool {
+		outbox.mu.Lock()
+		defer outbox.mu.Unlock()
+		return len(outbox.entries) == 1
+	}, 5*outboxFlushInterval, 10*time.Millisecond)
+	assert.Equal(t, 0, outbox.failures)
+	assert.Equal(t, "GraphQL", outbox.entries[0].Entity)
+}
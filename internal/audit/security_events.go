@@ -1,6 +1,10 @@
 package audit
 
-import "github.com/sourcegraph/sourcegraph/schema"
+import (
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/schema"
+)
 
 type SecurityEventsLocation int
 
@@ -28,6 +32,20 @@ func SecurityEventLocation(cfg schema.SiteConfiguration) SecurityEventsLocation
 	return AuditLog
 }
 
+// defaultSecurityEventRetentionDays ensures that we have at least the last
+// month's worth of security events at all times.
+const defaultSecurityEventRetentionDays = 30
+
+// SecurityEventRetention returns how long security events stored in the
+// database are retained before they are deleted.
+func SecurityEventRetention(cfg schema.SiteConfiguration) time.Duration {
+	days := defaultSecurityEventRetentionDays
+	if securityEvent := securityEventConf(cfg); securityEvent != nil && securityEvent.RetentionDays > 0 {
+		days = securityEvent.RetentionDays
+	}
+	return time.Duration(days) * 24 * time.Hour
+}
+
 func securityEventConf(cfg schema.SiteConfiguration) *schema.SecurityEventLog {
 	if logCg := cfg.Log; logCg != nil {
 		return logCg.SecurityEventLog
@@ -2,6 +2,7 @@ package audit
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 
@@ -42,3 +43,10 @@ func TestSecurityEventLocation(t *testing.T) {
 		})
 	}
 }
+
+func TestSecurityEventRetention(t *testing.T) {
+	assert.Equal(t, 30*24*time.Hour, SecurityEventRetention(schema.SiteConfiguration{}))
+
+	cfg := schema.SiteConfiguration{Log: &schema.Log{SecurityEventLog: &schema.SecurityEventLog{RetentionDays: 365}}}
+	assert.Equal(t, 365*24*time.Hour, SecurityEventRetention(cfg))
+}
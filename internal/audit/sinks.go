@@ -0,0 +1,88 @@
+package audit
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/requestclient"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+// Entry is an audit log entry as it is exported to the sinks configured in
+// log.auditLog.sinks. It mirrors the fields of the entries written by Log.
+type Entry struct {
+	AuditID   string     `json:"auditId"`
+	Timestamp time.Time  `json:"timestamp"`
+	Entity    string     `json:"entity"`
+	Action    string     `json:"action"`
+	Actor     EntryActor `json:"actor"`
+	// Event holds any additional context relevant to the Action.
+	Event any `json:"event,omitempty"`
+}
+
+type EntryActor struct {
+	ActorUID     string `json:"actorUID"`
+	IP           string `json:"ip"`
+	UserAgent    string `json:"userAgent"`
+	ForwardedFor string `json:"X-Forwarded-For"`
+}
+
+// NewEntry returns an entry for the action taken on the entity by the actor of
+// the context.
+func NewEntry(ctx context.Context, entity, action string, timestamp time.Time, event any) Entry {
+	act := actor.FromContext(ctx)
+	client := requestclient.FromContext(ctx)
+	return Entry{
+		AuditID:   uuid.New().String(),
+		Timestamp: timestamp.UTC(),
+		Entity:    entity,
+		Action:    action,
+		Actor: EntryActor{
+			ActorUID:     actorId(act),
+			IP:           ip(client),
+			UserAgent:    userAgent(client),
+			ForwardedFor: forwardedFor(client),
+		},
+		Event: event,
+	}
+}
+
+// SinkKeys returns the keys of the sinks configured in the site config.
+func SinkKeys(cfg schema.SiteConfiguration) []string {
+	auditCfg := getAuditCfg(cfg)
+	if auditCfg == nil {
+		return nil
+	}
+	keys := make([]string, 0, len(auditCfg.Sinks))
+	seen := make(map[string]struct{}, len(auditCfg.Sinks))
+	for _, sink := range auditCfg.Sinks {
+		key := SinkKey(sink)
+		if _, ok := seen[key]; ok || key == "" {
+			continue
+		}
+		seen[key] = struct{}{}
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// SinkKey returns a key that identifies the destination of the sink, such that
+// entries queued for a sink are still delivered to it after other settings of
+// the sink or the order of the sinks have changed. It returns an empty string
+// for an invalid sink.
+func SinkKey(sink *schema.AuditLogSink) string {
+	switch {
+	case sink == nil:
+		return ""
+	case sink.Syslog != nil:
+		return "syslog:" + sink.Syslog.Address
+	case sink.File != nil:
+		return "file:" + sink.File.Path
+	case sink.S3 != nil:
+		return "s3:" + sink.S3.Endpoint + "/" + sink.S3.Bucket + "/" + sink.S3.Prefix
+	}
+	return ""
+}
@@ -0,0 +1,54 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/requestclient"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+func TestSinkKeys(t *testing.T) {
+	assert.Empty(t, SinkKeys(schema.SiteConfiguration{}))
+
+	cfg := schema.SiteConfiguration{Log: &schema.Log{AuditLog: &schema.AuditLog{Sinks: []*schema.AuditLogSink{
+		{Syslog: &schema.SyslogAuditLogSink{Type: "syslog", Address: "syslog.example.com:6514", Tls: true}},
+		{File: &schema.FileAuditLogSink{Type: "file", Path: "/var/log/audit.jsonl"}},
+		{S3: &schema.S3AuditLogSink{Type: "s3", Bucket: "audit", Prefix: "sourcegraph/"}},
+		// Duplicate destinations only receive entries once.
+		{Syslog: &schema.SyslogAuditLogSink{Type: "syslog", Address: "syslog.example.com:6514"}},
+		{},
+	}}}}
+	assert.Equal(t, []string{
+		"syslog:syslog.example.com:6514",
+		"file:/var/log/audit.jsonl",
+		"s3:/audit/sourcegraph/",
+	}, SinkKeys(cfg))
+}
+
+func TestNewEntry(t *testing.T) {
+	ctx := actor.WithActor(context.Background(), &actor.Actor{UID: 1})
+	ctx = requestclient.WithClient(ctx, &requestclient.Client{IP: "192.168.0.1", UserAgent: "curl", ForwardedFor: "192.168.0.2"})
+
+	timestamp := time.Date(2023, 10, 20, 12, 0, 0, 0, time.FixedZone("CEST", 2*60*60))
+	entry := NewEntry(ctx, "security events", "AccessTokenCreated", timestamp, map[string]string{"url": "/"})
+	assert.NotEmpty(t, entry.AuditID)
+
+	entry.AuditID = "test"
+	b, err := json.Marshal(entry)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"auditId": "test",
+		"timestamp": "2023-10-20T10:00:00Z",
+		"entity": "security events",
+		"action": "AccessTokenCreated",
+		"actor": {"actorUID": "1", "ip": "192.168.0.1", "userAgent": "curl", "X-Forwarded-For": "192.168.0.2"},
+		"event": {"url": "/"}
+	}`, string(b))
+}
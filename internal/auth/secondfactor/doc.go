@@ -0,0 +1,4 @@
+// Package secondfactor implements the second factors that users of the builtin
+// username-password auth provider can enroll: TOTP codes of authenticator apps
+// (RFC 6238), WebAuthn security keys, and single-use recovery codes.
+package secondfactor
@@ -0,0 +1,27 @@
+package secondfactor
+
+import (
+	"testing"
+
+	"github.com/grafana/regexp"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerateRecoveryCodes(t *testing.T) {
+	codes, err := GenerateRecoveryCodes()
+	require.NoError(t, err)
+	require.Len(t, codes, RecoveryCodeCount)
+
+	seen := map[string]bool{}
+	for _, code := range codes {
+		require.Regexp(t, regexp.MustCompile(`^[a-hjkmnp-z2-9]{5}-[a-hjkmnp-z2-9]{5}$`), code)
+		require.False(t, seen[code], "duplicate code %s", code)
+		seen[code] = true
+		require.Equal(t, code, NormalizeRecoveryCode(code))
+	}
+}
+
+func TestNormalizeRecoveryCode(t *testing.T) {
+	require.Equal(t, "abcde-fghjk", NormalizeRecoveryCode(" ABCDE-fghjk\n"))
+	require.Equal(t, "abcde-fghjk", NormalizeRecoveryCode("abcde- fghjk"))
+}
@@ -0,0 +1,69 @@
+package secondfactor
+
+import (
+	"encoding/base32"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateTOTP(t *testing.T) {
+	// The SHA-1 test vectors of RFC 6238, truncated to 6 digits.
+	secret := base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString([]byte("12345678901234567890"))
+	for _, tc := range []struct {
+		unix int64
+		code string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1111111111, "050471"},
+		{1234567890, "005924"},
+		{2000000000, "279037"},
+	} {
+		step, ok := ValidateTOTP(secret, tc.code, time.Unix(tc.unix, 0))
+		require.True(t, ok, "code %s at %d", tc.code, tc.unix)
+		require.Equal(t, tc.unix/30, step)
+	}
+
+	t.Run("clock drift", func(t *testing.T) {
+		step, ok := ValidateTOTP(secret, "287082", time.Unix(59+30, 0))
+		require.True(t, ok)
+		require.Equal(t, int64(1), step)
+
+		_, ok = ValidateTOTP(secret, "287082", time.Unix(59+90, 0))
+		require.False(t, ok)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		for _, code := range []string{"", "28708", "2870821", "287083", "abcdef"} {
+			_, ok := ValidateTOTP(secret, code, time.Unix(59, 0))
+			require.False(t, ok, code)
+		}
+		_, ok := ValidateTOTP("not base32!", "287082", time.Unix(59, 0))
+		require.False(t, ok)
+	})
+}
+
+func TestGenerateTOTPSecret(t *testing.T) {
+	secret, err := GenerateTOTPSecret()
+	require.NoError(t, err)
+	require.Len(t, secret, 32)
+
+	other, err := GenerateTOTPSecret()
+	require.NoError(t, err)
+	require.NotEqual(t, secret, other)
+}
+
+func TestTOTPKeyURI(t *testing.T) {
+	u, err := url.Parse(TOTPKeyURI("JBSWY3DPEHPK3PXP", "Sourcegraph", "alice"))
+	require.NoError(t, err)
+	require.Equal(t, "otpauth", u.Scheme)
+	require.Equal(t, "totp", u.Host)
+	require.Equal(t, "/Sourcegraph:alice", u.Path)
+	require.Equal(t, "JBSWY3DPEHPK3PXP", u.Query().Get("secret"))
+	require.Equal(t, "Sourcegraph", u.Query().Get("issuer"))
+	require.Equal(t, "6", u.Query().Get("digits"))
+	require.Equal(t, "30", u.Query().Get("period"))
+}
@@ -0,0 +1,176 @@
+package secondfactor
+
+import (
+	"bytes"
+	"net/url"
+	"time"
+
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/go-webauthn/webauthn/webauthn"
+
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// webAuthnTimeout is how long browsers wait for the user to interact with their
+// security key.
+const webAuthnTimeout = time.Minute
+
+// RelyingParty is the Sourcegraph instance as a WebAuthn relying party, which
+// credentials are registered with. The ceremonies are implemented by
+// github.com/go-webauthn/webauthn.
+type RelyingParty struct {
+	webAuthn *webauthn.WebAuthn
+}
+
+// NewRelyingParty returns the relying party of the instance at externalURL.
+// Credentials are scoped to its host name, and browsers must report its origin.
+func NewRelyingParty(externalURL *url.URL, name string) (*RelyingParty, error) {
+	webAuthn, err := webauthn.New(&webauthn.Config{
+		RPID:          externalURL.Hostname(),
+		RPDisplayName: name,
+		RPOrigins:     []string{externalURL.Scheme + "://" + externalURL.Host},
+		// The security key is used as a second factor, so the make and model
+		// of the authenticator are not verified, and the user only needs to be
+		// present.
+		AttestationPreference: protocol.PreferNoAttestation,
+		AuthenticatorSelection: protocol.AuthenticatorSelection{
+			UserVerification: protocol.VerificationDiscouraged,
+		},
+		Timeouts: webauthn.TimeoutsConfig{
+			Login:        webauthn.TimeoutConfig{Timeout: webAuthnTimeout, TimeoutUVD: webAuthnTimeout},
+			Registration: webauthn.TimeoutConfig{Timeout: webAuthnTimeout, TimeoutUVD: webAuthnTimeout},
+		},
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "configuring WebAuthn relying party")
+	}
+	return &RelyingParty{webAuthn: webAuthn}, nil
+}
+
+// Credential is a registered WebAuthn credential.
+type Credential struct {
+	ID []byte
+	// PublicKey is the COSE-encoded public key of the credential.
+	PublicKey []byte
+	// SignCount is the signature counter of the security key, which increases
+	// with every use on security keys that support it.
+	SignCount uint32
+}
+
+// WebAuthnUser is a user that registers or verifies a credential.
+type WebAuthnUser struct {
+	// Handle is the opaque user handle that credentials are registered for.
+	Handle      []byte
+	Name        string
+	DisplayName string
+	// Credentials are the credentials the user already registered.
+	Credentials []Credential
+}
+
+var _ webauthn.User = &WebAuthnUser{}
+
+func (u *WebAuthnUser) WebAuthnID() []byte   { return u.Handle }
+func (u *WebAuthnUser) WebAuthnName() string { return u.Name }
+func (u *WebAuthnUser) WebAuthnIcon() string { return "" }
+
+func (u *WebAuthnUser) WebAuthnDisplayName() string {
+	if u.DisplayName == "" {
+		return u.Name
+	}
+	return u.DisplayName
+}
+
+func (u *WebAuthnUser) WebAuthnCredentials() []webauthn.Credential {
+	creds := make([]webauthn.Credential, 0, len(u.Credentials))
+	for _, cred := range u.Credentials {
+		creds = append(creds, webauthn.Credential{
+			ID:            cred.ID,
+			PublicKey:     cred.PublicKey,
+			Authenticator: webauthn.Authenticator{SignCount: cred.SignCount},
+		})
+	}
+	return creds
+}
+
+// BeginRegistration returns the options of navigator.credentials.create to
+// register a new credential for the user, excluding the credentials the user
+// already registered. The returned session data must be stored until the
+// browser's response is verified, and expires after ttl.
+func (rp *RelyingParty) BeginRegistration(user *WebAuthnUser, ttl time.Duration) (*protocol.PublicKeyCredentialCreationOptions, *webauthn.SessionData, error) {
+	exclusions := make([]protocol.CredentialDescriptor, 0, len(user.Credentials))
+	for _, cred := range user.WebAuthnCredentials() {
+		exclusions = append(exclusions, cred.Descriptor())
+	}
+
+	creation, session, err := rp.webAuthn.BeginRegistration(user, webauthn.WithExclusions(exclusions))
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "beginning WebAuthn registration")
+	}
+	session.Expires = time.Now().Add(ttl)
+	return &creation.Response, session, nil
+}
+
+// FinishRegistration verifies the PublicKeyCredential returned by
+// navigator.credentials.create for the given session, and returns the
+// registered credential.
+func (rp *RelyingParty) FinishRegistration(user *WebAuthnUser, session *webauthn.SessionData, response []byte) (*Credential, error) {
+	parsed, err := protocol.ParseCredentialCreationResponseBody(bytes.NewReader(response))
+	if err != nil {
+		return nil, wrapProtocolError(err, "parsing WebAuthn registration")
+	}
+	cred, err := rp.webAuthn.CreateCredential(user, *session, parsed)
+	if err != nil {
+		return nil, wrapProtocolError(err, "verifying WebAuthn registration")
+	}
+	return &Credential{
+		ID:        cred.ID,
+		PublicKey: cred.PublicKey,
+		SignCount: cred.Authenticator.SignCount,
+	}, nil
+}
+
+// BeginLogin returns the options of navigator.credentials.get to verify one of
+// the user's credentials. The returned session data must be stored until the
+// browser's response is verified, and expires after ttl.
+func (rp *RelyingParty) BeginLogin(user *WebAuthnUser, ttl time.Duration) (*protocol.PublicKeyCredentialRequestOptions, *webauthn.SessionData, error) {
+	assertion, session, err := rp.webAuthn.BeginLogin(user)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "beginning WebAuthn login")
+	}
+	session.Expires = time.Now().Add(ttl)
+	return &assertion.Response, session, nil
+}
+
+// FinishLogin verifies the PublicKeyCredential returned by
+// navigator.credentials.get for the given session. It returns the credential
+// that was used with its new signature counter, which must be stored.
+func (rp *RelyingParty) FinishLogin(user *WebAuthnUser, session *webauthn.SessionData, response []byte) (*Credential, error) {
+	parsed, err := protocol.ParseCredentialRequestResponseBody(bytes.NewReader(response))
+	if err != nil {
+		return nil, wrapProtocolError(err, "parsing WebAuthn assertion")
+	}
+	cred, err := rp.webAuthn.ValidateLogin(user, *session, parsed)
+	if err != nil {
+		return nil, wrapProtocolError(err, "verifying WebAuthn assertion")
+	}
+	// Security keys without a counter always report 0. Otherwise, a counter
+	// that did not increase indicates that the key was cloned.
+	if cred.Authenticator.CloneWarning {
+		return nil, errors.New("signature counter did not increase, the security key may have been cloned")
+	}
+	return &Credential{
+		ID:        cred.ID,
+		PublicKey: cred.PublicKey,
+		SignCount: cred.Authenticator.SignCount,
+	}, nil
+}
+
+// wrapProtocolError includes the details of WebAuthn protocol errors, which are
+// not part of their messages.
+func wrapProtocolError(err error, msg string) error {
+	var protocolErr *protocol.Error
+	if errors.As(err, &protocolErr) && protocolErr.DevInfo != "" {
+		return errors.Wrapf(err, "%s: %s", msg, protocolErr.DevInfo)
+	}
+	return errors.Wrap(err, msg)
+}
@@ -0,0 +1,294 @@
+package secondfactor
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/go-webauthn/webauthn/protocol/webauthncbor"
+	"github.com/go-webauthn/webauthn/webauthn"
+	"github.com/stretchr/testify/require"
+)
+
+// The flags of the authenticator data.
+const (
+	flagUserPresent            = 0x01
+	flagAttestedCredentialData = 0x40
+)
+
+// testAuthenticator is a security key that signs with a P-256 or Ed25519 key.
+type testAuthenticator struct {
+	t            *testing.T
+	credentialID []byte
+	ecKey        *ecdsa.PrivateKey
+	edKey        ed25519.PrivateKey
+	signCount    uint32
+
+	// The relying party ID, origin and flags reported by the security key and
+	// the browser. They are the expected ones unless a test changes them.
+	rpID   string
+	origin string
+	flags  byte
+}
+
+func newTestAuthenticator(t *testing.T, ed bool) *testAuthenticator {
+	a := &testAuthenticator{
+		t:            t,
+		credentialID: []byte("test-credential"),
+		rpID:         "sourcegraph.example.com",
+		origin:       "https://sourcegraph.example.com",
+		flags:        flagUserPresent,
+	}
+	var err error
+	if ed {
+		_, a.edKey, err = ed25519.GenerateKey(rand.Reader)
+	} else {
+		a.ecKey, err = ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	}
+	require.NoError(t, err)
+	return a
+}
+
+// coseKey returns the COSE_Key (RFC 8152) of the public key.
+func (a *testAuthenticator) coseKey() []byte {
+	var key map[int]any
+	if a.edKey != nil {
+		key = map[int]any{1: 1, 3: -8, -1: 6, -2: []byte(a.edKey.Public().(ed25519.PublicKey))}
+	} else {
+		x, y := make([]byte, 32), make([]byte, 32)
+		a.ecKey.X.FillBytes(x)
+		a.ecKey.Y.FillBytes(y)
+		key = map[int]any{1: 2, 3: -7, -1: 1, -2: x, -3: y}
+	}
+	b, err := webauthncbor.Marshal(key)
+	require.NoError(a.t, err)
+	return b
+}
+
+func (a *testAuthenticator) authData(flags byte) []byte {
+	rpIDHash := sha256.Sum256([]byte(a.rpID))
+	b := append([]byte{}, rpIDHash[:]...)
+	b = append(b, flags)
+	b = binary.BigEndian.AppendUint32(b, a.signCount)
+	if flags&flagAttestedCredentialData != 0 {
+		b = append(b, make([]byte, 16)...)
+		b = binary.BigEndian.AppendUint16(b, uint16(len(a.credentialID)))
+		b = append(b, a.credentialID...)
+		b = append(b, a.coseKey()...)
+	}
+	return b
+}
+
+func (a *testAuthenticator) clientDataJSON(typ, challenge string) []byte {
+	b, err := json.Marshal(map[string]any{
+		"type":      typ,
+		"challenge": challenge,
+		"origin":    a.origin,
+	})
+	require.NoError(a.t, err)
+	return b
+}
+
+// credential returns the PublicKeyCredential as serialized by the browser.
+func (a *testAuthenticator) credential(response map[string][]byte) []byte {
+	encoded := map[string]string{}
+	for k, v := range response {
+		encoded[k] = base64.RawURLEncoding.EncodeToString(v)
+	}
+	b, err := json.Marshal(map[string]any{
+		"id":       base64.RawURLEncoding.EncodeToString(a.credentialID),
+		"rawId":    base64.RawURLEncoding.EncodeToString(a.credentialID),
+		"type":     "public-key",
+		"response": encoded,
+	})
+	require.NoError(a.t, err)
+	return b
+}
+
+// create responds to navigator.credentials.create.
+func (a *testAuthenticator) create(challenge string) []byte {
+	attestationObject, err := webauthncbor.Marshal(map[string]any{
+		"fmt":      "none",
+		"attStmt":  map[string]any{},
+		"authData": a.authData(a.flags | flagAttestedCredentialData),
+	})
+	require.NoError(a.t, err)
+	return a.credential(map[string][]byte{
+		"clientDataJSON":    a.clientDataJSON("webauthn.create", challenge),
+		"attestationObject": attestationObject,
+	})
+}
+
+// get responds to navigator.credentials.get.
+func (a *testAuthenticator) get(challenge string) []byte {
+	a.signCount++
+	authData := a.authData(a.flags)
+	clientData := a.clientDataJSON("webauthn.get", challenge)
+	clientDataHash := sha256.Sum256(clientData)
+	signed := append(append([]byte{}, authData...), clientDataHash[:]...)
+
+	var sig []byte
+	if a.edKey != nil {
+		sig = ed25519.Sign(a.edKey, signed)
+	} else {
+		digest := sha256.Sum256(signed)
+		var err error
+		sig, err = ecdsa.SignASN1(rand.Reader, a.ecKey, digest[:])
+		require.NoError(a.t, err)
+	}
+	return a.credential(map[string][]byte{
+		"clientDataJSON":    clientData,
+		"authenticatorData": authData,
+		"signature":         sig,
+	})
+}
+
+func TestWebAuthn(t *testing.T) {
+	externalURL, _ := url.Parse("https://sourcegraph.example.com")
+	rp, err := NewRelyingParty(externalURL, "Sourcegraph")
+	require.NoError(t, err)
+
+	newUser := func() *WebAuthnUser {
+		return &WebAuthnUser{Handle: []byte{0, 0, 0, 1}, Name: "alice"}
+	}
+
+	register := func(t *testing.T, a *testAuthenticator, user *WebAuthnUser) (*Credential, error) {
+		options, session, err := rp.BeginRegistration(user, time.Minute)
+		require.NoError(t, err)
+		require.Equal(t, "sourcegraph.example.com", options.RelyingParty.ID)
+		return rp.FinishRegistration(user, session, a.create(options.Challenge.String()))
+	}
+
+	login := func(t *testing.T, a *testAuthenticator, user *WebAuthnUser) (*Credential, error) {
+		options, session, err := rp.BeginLogin(user, time.Minute)
+		require.NoError(t, err)
+		return rp.FinishLogin(user, session, a.get(options.Challenge.String()))
+	}
+
+	for _, ed := range []bool{false, true} {
+		name := "ES256"
+		if ed {
+			name = "EdDSA"
+		}
+		t.Run(name, func(t *testing.T) {
+			a := newTestAuthenticator(t, ed)
+			user := newUser()
+
+			cred, err := register(t, a, user)
+			require.NoError(t, err)
+			require.Equal(t, a.credentialID, cred.ID)
+			user.Credentials = []Credential{*cred}
+
+			verified, err := login(t, a, user)
+			require.NoError(t, err)
+			require.Equal(t, a.credentialID, verified.ID)
+			require.Equal(t, uint32(1), verified.SignCount)
+			user.Credentials[0].SignCount = verified.SignCount
+
+			t.Run("wrong challenge", func(t *testing.T) {
+				_, session, err := rp.BeginLogin(user, time.Minute)
+				require.NoError(t, err)
+				other, _, err := rp.BeginLogin(user, time.Minute)
+				require.NoError(t, err)
+				_, err = rp.FinishLogin(user, session, a.get(other.Challenge.String()))
+				require.ErrorContains(t, err, "challenge")
+			})
+
+			t.Run("expired challenge", func(t *testing.T) {
+				options, session, err := rp.BeginLogin(user, -time.Minute)
+				require.NoError(t, err)
+				_, err = rp.FinishLogin(user, session, a.get(options.Challenge.String()))
+				require.ErrorContains(t, err, "Session has Expired")
+			})
+
+			t.Run("wrong origin", func(t *testing.T) {
+				a.origin = "https://evil.example.com"
+				defer func() { a.origin = "https://sourcegraph.example.com" }()
+				_, err := login(t, a, user)
+				require.ErrorContains(t, err, "origin")
+			})
+
+			t.Run("wrong relying party", func(t *testing.T) {
+				a.rpID = "evil.example.com"
+				defer func() { a.rpID = "sourcegraph.example.com" }()
+				_, err := login(t, a, user)
+				require.ErrorContains(t, err, "RP Hash mismatch")
+			})
+
+			t.Run("user not present", func(t *testing.T) {
+				a.flags = 0
+				defer func() { a.flags = flagUserPresent }()
+				_, err := login(t, a, user)
+				require.ErrorContains(t, err, "User presence")
+			})
+
+			t.Run("invalid signature", func(t *testing.T) {
+				other := newTestAuthenticator(t, ed)
+				other.signCount = a.signCount
+				_, err := login(t, other, user)
+				require.ErrorContains(t, err, "signature")
+			})
+
+			t.Run("sign count regression", func(t *testing.T) {
+				a.signCount = 0
+				_, err := login(t, a, user)
+				require.ErrorContains(t, err, "signature counter did not increase")
+			})
+
+			t.Run("unknown credential", func(t *testing.T) {
+				other := newTestAuthenticator(t, ed)
+				other.credentialID = []byte("other-credential")
+				_, err := login(t, other, user)
+				require.ErrorContains(t, err, "credential")
+			})
+		})
+	}
+
+	t.Run("registration with wrong origin", func(t *testing.T) {
+		a := newTestAuthenticator(t, false)
+		a.origin = "https://evil.example.com"
+		_, err := register(t, a, newUser())
+		require.ErrorContains(t, err, "origin")
+	})
+
+	t.Run("registration without user presence", func(t *testing.T) {
+		a := newTestAuthenticator(t, false)
+		a.flags = 0
+		_, err := register(t, a, newUser())
+		require.ErrorContains(t, err, "User presence")
+	})
+
+	t.Run("registration excludes registered credentials", func(t *testing.T) {
+		user := newUser()
+		user.Credentials = []Credential{{ID: []byte("registered")}}
+		options, _, err := rp.BeginRegistration(user, time.Minute)
+		require.NoError(t, err)
+		require.Len(t, options.CredentialExcludeList, 1)
+		require.Equal(t, []byte("registered"), []byte(options.CredentialExcludeList[0].CredentialID))
+	})
+
+	t.Run("session data is stored as JSON", func(t *testing.T) {
+		a := newTestAuthenticator(t, false)
+		user := newUser()
+		cred, err := register(t, a, user)
+		require.NoError(t, err)
+		user.Credentials = []Credential{*cred}
+
+		options, session, err := rp.BeginLogin(user, time.Minute)
+		require.NoError(t, err)
+		b, err := json.Marshal(session)
+		require.NoError(t, err)
+		var stored *webauthn.SessionData
+		require.NoError(t, json.Unmarshal(b, &stored))
+		_, err = rp.FinishLogin(user, stored, a.get(options.Challenge.String()))
+		require.NoError(t, err)
+	})
+}
@@ -292,6 +292,10 @@ func getByEmailOrUsername(ctx context.Context, db database.DB, emailOrUsername s
 //
 // The account will be locked out after consecutive failed attempts in a certain
 // period of time.
+//
+// If the user has to verify a second factor, the session is not authenticated
+// until they do so through the HandleSecondFactor endpoints, and the response
+// describes their second factors.
 func HandleSignIn(logger log.Logger, db database.DB, store LockoutStore, recorder *telemetry.EventRecorder) http.HandlerFunc {
 	logger = logger.Scoped("HandleSignin", "sign in request handler")
 	events := telemetry.NewBestEffortEventRecorder(logger, recorder)
@@ -381,6 +385,25 @@ func HandleSignIn(logger log.Logger, db database.DB, store LockoutStore, recorde
 		// Make sure we're in the context of our newly signed in user
 		ctx = actor.WithActor(ctx, &act)
 
+		// 🚨 SECURITY: Users who enrolled a second factor, and site admins if the
+		// site config requires it, must verify a second factor before the session
+		// is authenticated.
+		secondFactor, err := getSecondFactorStatus(ctx, db, &user)
+		if err != nil {
+			httpLogError(logger.Error, w, "Error getting second factors", http.StatusInternalServerError, log.Error(err))
+			return
+		}
+		if secondFactor.SecondFactorRequired {
+			if err := session.SetActorPendingSecondFactor(w, r, &act, user.CreatedAt); err != nil {
+				httpLogError(logger.Error, w, "Could not create new user session", http.StatusInternalServerError, log.Error(err))
+				return
+			}
+			signInResult = database.SecurityEventNameSecondFactorRequired
+			telemetrySignInResult = telemetry.ActionAttempted
+			writeJSON(w, secondFactor)
+			return
+		}
+
 		// Write the session cookie
 		if err := session.SetActor(w, r, &act, 0, user.CreatedAt); err != nil {
 			httpLogError(logger.Error, w, "Could not create new user session", http.StatusInternalServerError, log.Error(err))
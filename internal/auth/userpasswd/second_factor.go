@@ -0,0 +1,775 @@
+package userpasswd
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/go-webauthn/webauthn/webauthn"
+	"github.com/gorilla/mux"
+	"github.com/sourcegraph/log"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/globals"
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/auth/secondfactor"
+	"github.com/sourcegraph/sourcegraph/internal/cookie"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/encryption/keyring"
+	"github.com/sourcegraph/sourcegraph/internal/errcode"
+	"github.com/sourcegraph/sourcegraph/internal/session"
+	"github.com/sourcegraph/sourcegraph/internal/telemetry"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// The second factors users can verify.
+const (
+	secondFactorMethodTOTP     = "totp"
+	secondFactorMethodWebAuthn = "webauthn"
+	secondFactorMethodRecovery = "recovery"
+)
+
+// recentSecondFactorVerification is how recently users must have verified a
+// second factor in their session to change their second factors.
+const recentSecondFactorVerification = 15 * time.Minute
+
+// webAuthnChallengeSessionKey is the key of the session data that holds the
+// challenge of the WebAuthn ceremony in progress.
+const webAuthnChallengeSessionKey = "webAuthnChallenge"
+
+// webAuthnChallengeExpiry is how long a WebAuthn challenge can be answered.
+const webAuthnChallengeExpiry = 5 * time.Minute
+
+// secondFactorIssuer is the name of the instance shown by authenticator apps
+// and browsers.
+const secondFactorIssuer = "Sourcegraph"
+
+// secondFactorStatus describes the second factors of a user. It is returned by
+// the sign-in endpoint if the user has to verify a second factor.
+type secondFactorStatus struct {
+	// SecondFactorRequired is set if the user has to verify a second factor to
+	// sign in.
+	SecondFactorRequired bool `json:"secondFactorRequired"`
+	// EnrollmentRequired is set if the user has no second factor yet, but the
+	// site configuration requires them to use one.
+	EnrollmentRequired bool `json:"enrollmentRequired"`
+	// Methods are the second factors the user can verify.
+	Methods []string `json:"methods"`
+
+	TOTPEnrolled           bool                     `json:"totpEnrolled"`
+	WebAuthnCredentials    []webAuthnCredentialInfo `json:"webAuthnCredentials"`
+	RecoveryCodesRemaining int                      `json:"recoveryCodesRemaining"`
+}
+
+type webAuthnCredentialInfo struct {
+	ID         int32      `json:"id"`
+	Name       string     `json:"name"`
+	CreatedAt  time.Time  `json:"createdAt"`
+	LastUsedAt *time.Time `json:"lastUsedAt"`
+}
+
+func userSecondFactors(db database.DB) database.UserSecondFactorsStore {
+	return db.UserSecondFactors(keyring.Default().UserSecondFactorKey)
+}
+
+// secondFactorRequiredBySiteConfig reports whether the site configuration
+// requires the user to use a second factor.
+func secondFactorRequiredBySiteConfig(user *types.User) bool {
+	pc, _ := GetProviderConfig()
+	return pc != nil && pc.RequireSecondFactorForSiteAdmins && user.SiteAdmin
+}
+
+func getSecondFactorStatus(ctx context.Context, db database.DB, user *types.User) (*secondFactorStatus, error) {
+	store := userSecondFactors(db)
+	status := &secondFactorStatus{Methods: []string{}, WebAuthnCredentials: []webAuthnCredentialInfo{}}
+
+	totp, err := store.GetTOTP(ctx, user.ID)
+	if err != nil && !errcode.IsNotFound(err) {
+		return nil, err
+	}
+	if totp != nil && totp.ConfirmedAt != nil {
+		status.TOTPEnrolled = true
+		status.Methods = append(status.Methods, secondFactorMethodTOTP)
+	}
+
+	creds, err := store.ListWebAuthnCredentials(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	for _, cred := range creds {
+		status.WebAuthnCredentials = append(status.WebAuthnCredentials, webAuthnCredentialInfo{
+			ID:         cred.ID,
+			Name:       cred.Name,
+			CreatedAt:  cred.CreatedAt,
+			LastUsedAt: cred.LastUsedAt,
+		})
+	}
+	if len(creds) > 0 {
+		status.Methods = append(status.Methods, secondFactorMethodWebAuthn)
+	}
+
+	enrolled := len(status.Methods) > 0
+	if enrolled {
+		status.RecoveryCodesRemaining, err = store.CountRecoveryCodes(ctx, user.ID)
+		if err != nil {
+			return nil, err
+		}
+		if status.RecoveryCodesRemaining > 0 {
+			status.Methods = append(status.Methods, secondFactorMethodRecovery)
+		}
+	}
+
+	status.SecondFactorRequired = enrolled || secondFactorRequiredBySiteConfig(user)
+	status.EnrollmentRequired = !enrolled && secondFactorRequiredBySiteConfig(user)
+	return status, nil
+}
+
+// HandleSecondFactor returns the handler of the /-/second-factor endpoints,
+// which verify and manage the second factors of users signing in with the
+// builtin auth provider.
+//
+// The endpoints act on behalf of the user of a session that is waiting for the
+// second factor after a correct password, or on behalf of the signed in user.
+func HandleSecondFactor(logger log.Logger, db database.DB, store LockoutStore, recorder *telemetry.EventRecorder) http.Handler {
+	h := &secondFactorHandler{
+		logger: logger.Scoped("HandleSecondFactor", "second factor request handler"),
+		db:     db,
+		store:  store,
+		events: telemetry.NewBestEffortEventRecorder(logger, recorder),
+	}
+
+	r := mux.NewRouter()
+	r.Path("/-/second-factor").Methods("GET").HandlerFunc(h.serveStatus)
+	r.Path("/-/second-factor/verify").Methods("POST").HandlerFunc(h.serveVerify)
+	r.Path("/-/second-factor/webauthn/challenge").Methods("POST").HandlerFunc(h.serveWebAuthnChallenge)
+	r.Path("/-/second-factor/totp/enroll").Methods("POST").HandlerFunc(h.serveTOTPEnroll)
+	r.Path("/-/second-factor/totp/confirm").Methods("POST").HandlerFunc(h.serveTOTPConfirm)
+	r.Path("/-/second-factor/webauthn/register/begin").Methods("POST").HandlerFunc(h.serveWebAuthnRegisterBegin)
+	r.Path("/-/second-factor/webauthn/register/finish").Methods("POST").HandlerFunc(h.serveWebAuthnRegisterFinish)
+	r.Path("/-/second-factor/recovery-codes").Methods("POST").HandlerFunc(h.serveRecoveryCodes)
+	r.Path("/-/second-factor/remove").Methods("POST").HandlerFunc(h.serveRemove)
+
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if handleEnabledCheck(h.logger, w) {
+			return
+		}
+		r.ServeHTTP(w, req)
+	})
+}
+
+type secondFactorHandler struct {
+	logger log.Logger
+	db     database.DB
+	store  LockoutStore
+	events *telemetry.BestEffortEventRecorder
+}
+
+// user returns the user the request acts on behalf of. pending is set if the
+// session is waiting for the user to verify a second factor.
+func (h *secondFactorHandler) user(w http.ResponseWriter, r *http.Request) (user *types.User, pending bool, ok bool) {
+	ctx := r.Context()
+	if a := session.PendingSecondFactorActor(r); a != nil {
+		user, err := h.db.Users().GetByID(ctx, a.UID)
+		if err != nil {
+			httpLogError(h.logger.Warn, w, "Authentication failed", http.StatusUnauthorized, log.Error(err))
+			return nil, false, false
+		}
+		return user, true, true
+	}
+
+	// 🚨 SECURITY: Second factors protect sessions, so they cannot be managed
+	// with access tokens.
+	a := actor.FromContext(ctx)
+	if !a.IsAuthenticated() || !a.FromSessionCookie {
+		http.Error(w, "Not signed in", http.StatusUnauthorized)
+		return nil, false, false
+	}
+	user, err := a.User(ctx, h.db.Users())
+	if err != nil {
+		httpLogError(h.logger.Error, w, "Error getting user", http.StatusInternalServerError, log.Error(err))
+		return nil, false, false
+	}
+	return user, false, true
+}
+
+// enrollmentAllowed reports whether the user may enroll a new second factor. A
+// session that is waiting for the second factor may only enroll the first one,
+// and users who already have a second factor must have verified it recently.
+func (h *secondFactorHandler) enrollmentAllowed(w http.ResponseWriter, r *http.Request, user *types.User, pending bool) bool {
+	has, err := userSecondFactors(h.db).HasSecondFactor(r.Context(), user.ID)
+	if err != nil {
+		httpLogError(h.logger.Error, w, "Error getting second factors", http.StatusInternalServerError, log.Error(err))
+		return false
+	}
+	if !has {
+		return true
+	}
+	if pending || !session.SecondFactorVerifiedWithin(r, recentSecondFactorVerification) {
+		http.Error(w, "Verify your second factor first", http.StatusForbidden)
+		return false
+	}
+	return true
+}
+
+// recentlyVerified checks that the signed in user recently verified a second
+// factor, which is required to change second factors.
+func (h *secondFactorHandler) recentlyVerified(w http.ResponseWriter, r *http.Request, pending bool) bool {
+	if pending || !session.SecondFactorVerifiedWithin(r, recentSecondFactorVerification) {
+		http.Error(w, "Verify your second factor first", http.StatusForbidden)
+		return false
+	}
+	return true
+}
+
+func (h *secondFactorHandler) serveStatus(w http.ResponseWriter, r *http.Request) {
+	user, _, ok := h.user(w, r)
+	if !ok {
+		return
+	}
+	h.writeStatus(w, r, user)
+}
+
+func (h *secondFactorHandler) writeStatus(w http.ResponseWriter, r *http.Request, user *types.User) {
+	status, err := getSecondFactorStatus(r.Context(), h.db, user)
+	if err != nil {
+		httpLogError(h.logger.Error, w, "Error getting second factors", http.StatusInternalServerError, log.Error(err))
+		return
+	}
+	writeJSON(w, status)
+}
+
+type verifySecondFactorRequest struct {
+	Method string `json:"method"`
+	// Code is the TOTP code or the recovery code.
+	Code string `json:"code"`
+	// Credential is the PublicKeyCredential returned by the browser in
+	// response to the WebAuthn challenge.
+	Credential json.RawMessage `json:"credential"`
+}
+
+func (h *secondFactorHandler) serveVerify(w http.ResponseWriter, r *http.Request) {
+	user, pending, ok := h.user(w, r)
+	if !ok {
+		return
+	}
+	if reason, locked := h.store.IsLockedOut(user.ID); locked {
+		httpLogError(h.logger.Error, w, "Account has been locked out due to "+reason, http.StatusUnprocessableEntity)
+		return
+	}
+
+	var req verifySecondFactorRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Could not decode request body", http.StatusBadRequest)
+		return
+	}
+
+	verified, err := h.verify(w, r, user, &req)
+	if err != nil {
+		httpLogError(h.logger.Error, w, "Error verifying second factor", http.StatusInternalServerError, log.Error(err))
+		return
+	}
+	if !verified {
+		// 🚨 SECURITY: Failed verifications count towards the account lockout,
+		// like failed passwords.
+		h.store.IncreaseFailedAttempt(user.ID)
+		recordSecondFactorSecurityEvent(r, h.db, user.ID, database.SecurityEventNameSecondFactorFailed, req.Method)
+		if pending {
+			h.events.Record(r.Context(), telemetry.FeatureSignIn, telemetry.ActionFailed, nil)
+		}
+		httpLogError(h.logger.Warn, w, "Second factor verification failed", http.StatusUnauthorized)
+		return
+	}
+
+	if err := h.complete(w, r, user, pending); err != nil {
+		httpLogError(h.logger.Error, w, "Could not update session", http.StatusInternalServerError, log.Error(err))
+		return
+	}
+	recordSecondFactorSecurityEvent(r, h.db, user.ID, database.SecurityEventNameSecondFactorSucceeded, req.Method)
+	h.writeStatus(w, r, user)
+}
+
+// verify reports whether the request verifies one of the user's second factors.
+func (h *secondFactorHandler) verify(w http.ResponseWriter, r *http.Request, user *types.User, req *verifySecondFactorRequest) (bool, error) {
+	ctx := r.Context()
+	store := userSecondFactors(h.db)
+
+	switch req.Method {
+	case secondFactorMethodTOTP:
+		totp, err := store.GetTOTP(ctx, user.ID)
+		if err != nil {
+			if errcode.IsNotFound(err) {
+				return false, nil
+			}
+			return false, err
+		}
+		if totp.ConfirmedAt == nil {
+			return false, nil
+		}
+		step, ok := secondfactor.ValidateTOTP(totp.Secret, req.Code, time.Now())
+		if !ok {
+			return false, nil
+		}
+		// 🚨 SECURITY: A code is only valid once.
+		return store.UseTOTPStep(ctx, user.ID, step)
+
+	case secondFactorMethodRecovery:
+		return store.UseRecoveryCode(ctx, user.ID, secondfactor.NormalizeRecoveryCode(req.Code))
+
+	case secondFactorMethodWebAuthn:
+		if len(req.Credential) == 0 {
+			return false, nil
+		}
+		challenge, err := takeWebAuthnChallenge(w, r)
+		if err != nil || challenge == nil {
+			return false, err
+		}
+		creds, err := store.ListWebAuthnCredentials(ctx, user.ID)
+		if err != nil {
+			return false, err
+		}
+		rp, err := relyingParty()
+		if err != nil {
+			return false, err
+		}
+		verified, err := rp.FinishLogin(webAuthnUser(user, creds), challenge, req.Credential)
+		if err != nil {
+			h.logger.Warn("WebAuthn assertion verification failed", log.Int32("userID", user.ID), log.Error(err))
+			return false, nil
+		}
+		for _, cred := range creds {
+			if bytes.Equal(cred.CredentialID, verified.ID) {
+				return true, store.UpdateWebAuthnSignCount(ctx, cred.ID, verified.SignCount)
+			}
+		}
+		return false, nil
+	}
+	return false, nil
+}
+
+// complete records in the session that the user verified a second factor. If
+// the session was waiting for it, the user is now signed in.
+func (h *secondFactorHandler) complete(w http.ResponseWriter, r *http.Request, user *types.User, pending bool) error {
+	if err := session.CompleteSecondFactor(w, r); err != nil {
+		return err
+	}
+	if pending {
+		h.store.Reset(user.ID)
+		signInResult := database.SecurityEventNameSignInSucceeded
+		recordSignInSecurityEvent(r, h.db, user, &signInResult)
+		h.events.Record(r.Context(), telemetry.FeatureSignIn, telemetry.ActionSucceeded, nil)
+	}
+	return nil
+}
+
+func (h *secondFactorHandler) serveWebAuthnChallenge(w http.ResponseWriter, r *http.Request) {
+	user, _, ok := h.user(w, r)
+	if !ok {
+		return
+	}
+	creds, err := userSecondFactors(h.db).ListWebAuthnCredentials(r.Context(), user.ID)
+	if err != nil {
+		httpLogError(h.logger.Error, w, "Error getting WebAuthn credentials", http.StatusInternalServerError, log.Error(err))
+		return
+	}
+	if len(creds) == 0 {
+		http.Error(w, "No security keys registered", http.StatusBadRequest)
+		return
+	}
+
+	rp, err := relyingParty()
+	if err != nil {
+		httpLogError(h.logger.Error, w, "Error configuring WebAuthn", http.StatusInternalServerError, log.Error(err))
+		return
+	}
+	options, challenge, err := rp.BeginLogin(webAuthnUser(user, creds), webAuthnChallengeExpiry)
+	if err != nil {
+		httpLogError(h.logger.Error, w, "Error generating WebAuthn challenge", http.StatusInternalServerError, log.Error(err))
+		return
+	}
+	if !h.storeWebAuthnChallenge(w, r, challenge) {
+		return
+	}
+	writeJSON(w, options)
+}
+
+type totpEnrollment struct {
+	Secret string `json:"secret"`
+	// KeyURI is the otpauth:// URI of the secret, to be shown as a QR code.
+	KeyURI string `json:"keyURI"`
+}
+
+func (h *secondFactorHandler) serveTOTPEnroll(w http.ResponseWriter, r *http.Request) {
+	user, pending, ok := h.user(w, r)
+	if !ok || !h.enrollmentAllowed(w, r, user, pending) {
+		return
+	}
+
+	secret, err := secondfactor.GenerateTOTPSecret()
+	if err != nil {
+		httpLogError(h.logger.Error, w, "Error generating TOTP secret", http.StatusInternalServerError, log.Error(err))
+		return
+	}
+	if err := userSecondFactors(h.db).EnrollTOTP(r.Context(), user.ID, secret); err != nil {
+		if err == database.ErrTOTPAlreadyConfirmed {
+			http.Error(w, "An authenticator app is already enrolled", http.StatusConflict)
+			return
+		}
+		httpLogError(h.logger.Error, w, "Error storing TOTP secret", http.StatusInternalServerError, log.Error(err))
+		return
+	}
+
+	writeJSON(w, totpEnrollment{
+		Secret: secret,
+		KeyURI: secondfactor.TOTPKeyURI(secret, secondFactorIssuer, user.Username),
+	})
+}
+
+func (h *secondFactorHandler) serveTOTPConfirm(w http.ResponseWriter, r *http.Request) {
+	user, pending, ok := h.user(w, r)
+	if !ok || !h.enrollmentAllowed(w, r, user, pending) {
+		return
+	}
+
+	var req struct {
+		Code string `json:"code"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Could not decode request body", http.StatusBadRequest)
+		return
+	}
+
+	ctx := r.Context()
+	store := userSecondFactors(h.db)
+	totp, err := store.GetTOTP(ctx, user.ID)
+	if err != nil {
+		if errcode.IsNotFound(err) {
+			http.Error(w, "No authenticator app enrollment in progress", http.StatusBadRequest)
+			return
+		}
+		httpLogError(h.logger.Error, w, "Error getting TOTP secret", http.StatusInternalServerError, log.Error(err))
+		return
+	}
+	if totp.ConfirmedAt != nil {
+		http.Error(w, "An authenticator app is already enrolled", http.StatusConflict)
+		return
+	}
+
+	step, valid := secondfactor.ValidateTOTP(totp.Secret, req.Code, time.Now())
+	if !valid {
+		http.Error(w, "Invalid code", http.StatusUnauthorized)
+		return
+	}
+	if confirmed, err := store.ConfirmTOTP(ctx, user.ID, step); err != nil {
+		httpLogError(h.logger.Error, w, "Error confirming TOTP secret", http.StatusInternalServerError, log.Error(err))
+		return
+	} else if !confirmed {
+		http.Error(w, "An authenticator app is already enrolled", http.StatusConflict)
+		return
+	}
+
+	h.finishEnrollment(w, r, user, pending, secondFactorMethodTOTP)
+}
+
+func (h *secondFactorHandler) serveWebAuthnRegisterBegin(w http.ResponseWriter, r *http.Request) {
+	user, pending, ok := h.user(w, r)
+	if !ok || !h.enrollmentAllowed(w, r, user, pending) {
+		return
+	}
+	creds, err := userSecondFactors(h.db).ListWebAuthnCredentials(r.Context(), user.ID)
+	if err != nil {
+		httpLogError(h.logger.Error, w, "Error getting WebAuthn credentials", http.StatusInternalServerError, log.Error(err))
+		return
+	}
+
+	rp, err := relyingParty()
+	if err != nil {
+		httpLogError(h.logger.Error, w, "Error configuring WebAuthn", http.StatusInternalServerError, log.Error(err))
+		return
+	}
+	options, challenge, err := rp.BeginRegistration(webAuthnUser(user, creds), webAuthnChallengeExpiry)
+	if err != nil {
+		httpLogError(h.logger.Error, w, "Error generating WebAuthn challenge", http.StatusInternalServerError, log.Error(err))
+		return
+	}
+	if !h.storeWebAuthnChallenge(w, r, challenge) {
+		return
+	}
+	writeJSON(w, options)
+}
+
+type registerWebAuthnRequest struct {
+	// Name is the name of the security key chosen by the user.
+	Name string `json:"name"`
+	// Credential is the PublicKeyCredential returned by the browser in
+	// response to the registration options.
+	Credential json.RawMessage `json:"credential"`
+}
+
+func (h *secondFactorHandler) serveWebAuthnRegisterFinish(w http.ResponseWriter, r *http.Request) {
+	user, pending, ok := h.user(w, r)
+	if !ok || !h.enrollmentAllowed(w, r, user, pending) {
+		return
+	}
+
+	var req registerWebAuthnRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || len(req.Credential) == 0 {
+		http.Error(w, "Could not decode request body", http.StatusBadRequest)
+		return
+	}
+	if req.Name == "" {
+		req.Name = "Security key"
+	}
+
+	challenge, err := takeWebAuthnChallenge(w, r)
+	if err != nil {
+		httpLogError(h.logger.Error, w, "Error getting WebAuthn challenge", http.StatusInternalServerError, log.Error(err))
+		return
+	}
+	if challenge == nil {
+		http.Error(w, "No security key registration in progress", http.StatusBadRequest)
+		return
+	}
+
+	creds, err := userSecondFactors(h.db).ListWebAuthnCredentials(r.Context(), user.ID)
+	if err != nil {
+		httpLogError(h.logger.Error, w, "Error getting WebAuthn credentials", http.StatusInternalServerError, log.Error(err))
+		return
+	}
+	rp, err := relyingParty()
+	if err != nil {
+		httpLogError(h.logger.Error, w, "Error configuring WebAuthn", http.StatusInternalServerError, log.Error(err))
+		return
+	}
+	cred, err := rp.FinishRegistration(webAuthnUser(user, creds), challenge, req.Credential)
+	if err != nil {
+		httpLogError(h.logger.Warn, w, "Security key registration failed", http.StatusBadRequest, log.Error(err))
+		return
+	}
+	if err := userSecondFactors(h.db).CreateWebAuthnCredential(r.Context(), &database.UserWebAuthnCredential{
+		UserID:       user.ID,
+		Name:         req.Name,
+		CredentialID: cred.ID,
+		PublicKey:    cred.PublicKey,
+		SignCount:    cred.SignCount,
+	}); err != nil {
+		httpLogError(h.logger.Error, w, "Error storing WebAuthn credential", http.StatusInternalServerError, log.Error(err))
+		return
+	}
+
+	h.finishEnrollment(w, r, user, pending, secondFactorMethodWebAuthn)
+}
+
+type enrollmentResult struct {
+	// RecoveryCodes are only set when the first second factor is enrolled, and
+	// cannot be retrieved again.
+	RecoveryCodes []string `json:"recoveryCodes,omitempty"`
+}
+
+// finishEnrollment completes the enrollment of a second factor. Enrolling a
+// second factor verifies it, so a session waiting for the second factor is
+// signed in.
+func (h *secondFactorHandler) finishEnrollment(w http.ResponseWriter, r *http.Request, user *types.User, pending bool, method string) {
+	ctx := r.Context()
+	recordSecondFactorSecurityEvent(r, h.db, user.ID, database.SecurityEventNameSecondFactorEnrolled, method)
+
+	var result enrollmentResult
+	count, err := userSecondFactors(h.db).CountRecoveryCodes(ctx, user.ID)
+	if err != nil {
+		httpLogError(h.logger.Error, w, "Error getting recovery codes", http.StatusInternalServerError, log.Error(err))
+		return
+	}
+	if count == 0 {
+		if result.RecoveryCodes, err = h.replaceRecoveryCodes(r, user); err != nil {
+			httpLogError(h.logger.Error, w, "Error creating recovery codes", http.StatusInternalServerError, log.Error(err))
+			return
+		}
+	}
+
+	if err := h.complete(w, r, user, pending); err != nil {
+		httpLogError(h.logger.Error, w, "Could not update session", http.StatusInternalServerError, log.Error(err))
+		return
+	}
+	writeJSON(w, result)
+}
+
+func (h *secondFactorHandler) replaceRecoveryCodes(r *http.Request, user *types.User) ([]string, error) {
+	codes, err := secondfactor.GenerateRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+	if err := userSecondFactors(h.db).ReplaceRecoveryCodes(r.Context(), user.ID, codes); err != nil {
+		return nil, err
+	}
+	recordSecondFactorSecurityEvent(r, h.db, user.ID, database.SecurityEventNameRecoveryCodesCreated, secondFactorMethodRecovery)
+	return codes, nil
+}
+
+func (h *secondFactorHandler) serveRecoveryCodes(w http.ResponseWriter, r *http.Request) {
+	user, pending, ok := h.user(w, r)
+	if !ok || !h.recentlyVerified(w, r, pending) {
+		return
+	}
+	has, err := userSecondFactors(h.db).HasSecondFactor(r.Context(), user.ID)
+	if err != nil {
+		httpLogError(h.logger.Error, w, "Error getting second factors", http.StatusInternalServerError, log.Error(err))
+		return
+	}
+	if !has {
+		http.Error(w, "No second factor enrolled", http.StatusBadRequest)
+		return
+	}
+
+	codes, err := h.replaceRecoveryCodes(r, user)
+	if err != nil {
+		httpLogError(h.logger.Error, w, "Error creating recovery codes", http.StatusInternalServerError, log.Error(err))
+		return
+	}
+	writeJSON(w, enrollmentResult{RecoveryCodes: codes})
+}
+
+type removeSecondFactorRequest struct {
+	Method string `json:"method"`
+	// ID is the ID of the WebAuthn credential to remove.
+	ID int32 `json:"id"`
+}
+
+func (h *secondFactorHandler) serveRemove(w http.ResponseWriter, r *http.Request) {
+	user, pending, ok := h.user(w, r)
+	if !ok || !h.recentlyVerified(w, r, pending) {
+		return
+	}
+
+	var req removeSecondFactorRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Could not decode request body", http.StatusBadRequest)
+		return
+	}
+
+	ctx := r.Context()
+	status, err := getSecondFactorStatus(ctx, h.db, user)
+	if err != nil {
+		httpLogError(h.logger.Error, w, "Error getting second factors", http.StatusInternalServerError, log.Error(err))
+		return
+	}
+	remaining := len(status.WebAuthnCredentials)
+	if status.TOTPEnrolled {
+		remaining++
+	}
+	// 🚨 SECURITY: Site admins cannot remove their last second factor if the
+	// site configuration requires them to use one.
+	if remaining <= 1 && secondFactorRequiredBySiteConfig(user) {
+		http.Error(w, "Site admins are required to use a second factor", http.StatusForbidden)
+		return
+	}
+
+	err = userSecondFactors(h.db).WithTransact(ctx, func(tx database.UserSecondFactorsStore) error {
+		switch req.Method {
+		case secondFactorMethodTOTP:
+			if !status.TOTPEnrolled {
+				return database.SecondFactorNotFoundErr{}
+			}
+			if err := tx.DeleteTOTP(ctx, user.ID); err != nil {
+				return err
+			}
+		case secondFactorMethodWebAuthn:
+			if err := tx.DeleteWebAuthnCredential(ctx, user.ID, req.ID); err != nil {
+				return err
+			}
+		default:
+			return errors.Newf("unknown second factor method %q", req.Method)
+		}
+
+		// Recovery codes are only useful while the user has a second factor.
+		if has, err := tx.HasSecondFactor(ctx, user.ID); err != nil || has {
+			return err
+		}
+		return tx.DeleteRecoveryCodes(ctx, user.ID)
+	})
+	if err != nil {
+		if errcode.IsNotFound(err) {
+			http.Error(w, "Second factor not found", http.StatusNotFound)
+			return
+		}
+		httpLogError(h.logger.Error, w, "Error removing second factor", http.StatusInternalServerError, log.Error(err))
+		return
+	}
+
+	recordSecondFactorSecurityEvent(r, h.db, user.ID, database.SecurityEventNameSecondFactorRemoved, req.Method)
+	h.writeStatus(w, r, user)
+}
+
+func relyingParty() (*secondfactor.RelyingParty, error) {
+	return secondfactor.NewRelyingParty(globals.ExternalURL(), secondFactorIssuer)
+}
+
+// webAuthnUser returns the user with the given registered credentials as the
+// user of a WebAuthn ceremony.
+func webAuthnUser(user *types.User, creds []*database.UserWebAuthnCredential) *secondfactor.WebAuthnUser {
+	u := &secondfactor.WebAuthnUser{
+		Handle:      binary.BigEndian.AppendUint32(nil, uint32(user.ID)),
+		Name:        user.Username,
+		DisplayName: user.DisplayName,
+	}
+	for _, cred := range creds {
+		u.Credentials = append(u.Credentials, secondfactor.Credential{
+			ID:        cred.CredentialID,
+			PublicKey: cred.PublicKey,
+			SignCount: cred.SignCount,
+		})
+	}
+	return u
+}
+
+// storeWebAuthnChallenge stores the session data of a WebAuthn ceremony in the
+// session until the browser responds to it.
+func (h *secondFactorHandler) storeWebAuthnChallenge(w http.ResponseWriter, r *http.Request, challenge *webauthn.SessionData) bool {
+	if err := session.SetData(w, r, webAuthnChallengeSessionKey, challenge); err != nil {
+		httpLogError(h.logger.Error, w, "Could not store WebAuthn challenge", http.StatusInternalServerError, log.Error(err))
+		return false
+	}
+	return true
+}
+
+// takeWebAuthnChallenge returns the session data of the WebAuthn ceremony in
+// progress, or nil if there is none or it expired. It is removed from the
+// session so that the challenge can only be answered once.
+func takeWebAuthnChallenge(w http.ResponseWriter, r *http.Request) (*webauthn.SessionData, error) {
+	var challenge *webauthn.SessionData
+	if err := session.GetData(r, webAuthnChallengeSessionKey, &challenge); err != nil {
+		return nil, err
+	}
+	if challenge == nil {
+		return nil, nil
+	}
+	if err := session.SetData(w, r, webAuthnChallengeSessionKey, nil); err != nil {
+		return nil, err
+	}
+	if time.Now().After(challenge.Expires) {
+		return nil, nil
+	}
+	return challenge, nil
+}
+
+func recordSecondFactorSecurityEvent(r *http.Request, db database.DB, userID int32, name database.SecurityEventName, method string) {
+	argument, _ := json.Marshal(map[string]string{"method": method})
+	event := &database.SecurityEvent{
+		Name:      name,
+		URL:       r.URL.Path,
+		UserID:    uint32(userID),
+		Argument:  argument,
+		Source:    "BACKEND",
+		Timestamp: time.Now(),
+	}
+
+	// Safe to ignore this error
+	event.AnonymousUserID, _ = cookie.AnonymousUID(r)
+	db.SecurityEventLogs().LogEvent(r.Context(), event)
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	_ = json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,266 @@
+package userpasswd
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/base32"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	mockrequire "github.com/derision-test/go-mockgen/testutil/require"
+	"github.com/sourcegraph/log/logtest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/auth/secondfactor"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbmocks"
+	"github.com/sourcegraph/sourcegraph/internal/session"
+	"github.com/sourcegraph/sourcegraph/internal/telemetry"
+	"github.com/sourcegraph/sourcegraph/internal/telemetry/telemetrytest"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+// testTOTPCode returns the current code of the TOTP secret, like an
+// authenticator app.
+func testTOTPCode(t *testing.T, secret string) string {
+	key, err := base32.StdEncoding.WithPadding(base32.NoPadding).DecodeString(secret)
+	require.NoError(t, err)
+	var counter [8]byte
+	binary.BigEndian.PutUint64(counter[:], uint64(time.Now().Unix()/30))
+	mac := hmac.New(sha1.New, key)
+	mac.Write(counter[:])
+	sum := mac.Sum(nil)
+	offset := sum[len(sum)-1] & 0x0f
+	return fmt.Sprintf("%06d", (binary.BigEndian.Uint32(sum[offset:offset+4])&0x7fffffff)%1000000)
+}
+
+func mockBuiltinAuthProvider(t *testing.T, requireSecondFactorForSiteAdmins bool) {
+	conf.Mock(&conf.Unified{
+		SiteConfiguration: schema.SiteConfiguration{
+			AuthProviders: []schema.AuthProviders{
+				{
+					Builtin: &schema.BuiltinAuthProvider{
+						Type:                             providerType,
+						RequireSecondFactorForSiteAdmins: requireSecondFactorForSiteAdmins,
+					},
+				},
+			},
+		},
+	})
+	t.Cleanup(func() { conf.Mock(nil) })
+}
+
+func TestHandleSignIn_SecondFactor(t *testing.T) {
+	cleanup := session.ResetMockSessionStore(t)
+	defer cleanup()
+
+	secret, err := secondfactor.GenerateTOTPSecret()
+	require.NoError(t, err)
+	confirmedAt := time.Now()
+
+	user := &types.User{ID: 1, Username: "alice", CreatedAt: time.Now()}
+	users := dbmocks.NewMockUserStore()
+	users.GetByUsernameFunc.SetDefaultReturn(user, nil)
+	users.GetByIDFunc.SetDefaultReturn(user, nil)
+	users.IsPasswordFunc.SetDefaultReturn(true, nil)
+
+	secondFactors := dbmocks.NewMockUserSecondFactorsStore()
+	secondFactors.GetTOTPFunc.SetDefaultReturn(&database.UserTOTPCredential{UserID: 1, Secret: secret, ConfirmedAt: &confirmedAt}, nil)
+	secondFactors.UseTOTPStepFunc.SetDefaultReturn(true, nil)
+	secondFactors.CountRecoveryCodesFunc.SetDefaultReturn(10, nil)
+
+	db := dbmocks.NewMockDB()
+	db.UsersFunc.SetDefaultReturn(users)
+	db.UserSecondFactorsFunc.SetDefaultReturn(secondFactors)
+	db.EventLogsFunc.SetDefaultReturn(dbmocks.NewMockEventLogStore())
+	db.SecurityEventLogsFunc.SetDefaultReturn(dbmocks.NewMockSecurityEventLogsStore())
+
+	lockout := NewMockLockoutStore()
+	logger := logtest.NoOp(t)
+	recorder := telemetry.NewEventRecorder(telemetrytest.NewMockEventsStore())
+	signIn := HandleSignIn(logger, db, lockout, recorder)
+	secondFactor := HandleSecondFactor(logger, db, lockout, recorder)
+
+	signInWithPassword := func(t *testing.T) []*http.Cookie {
+		req := httptest.NewRequest(http.MethodPost, "/-/sign-in", strings.NewReader(`{"email":"alice","password":"correct"}`))
+		resp := httptest.NewRecorder()
+		signIn(resp, req)
+		require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+
+		var status secondFactorStatus
+		require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &status))
+		assert.True(t, status.SecondFactorRequired)
+		assert.False(t, status.EnrollmentRequired)
+		assert.Equal(t, []string{secondFactorMethodTOTP, secondFactorMethodRecovery}, status.Methods)
+		return resp.Result().Cookies()
+	}
+
+	verify := func(cookies []*http.Cookie, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/-/second-factor/verify", strings.NewReader(body))
+		for _, c := range cookies {
+			req.AddCookie(c)
+		}
+		resp := httptest.NewRecorder()
+		secondFactor.ServeHTTP(resp, req)
+		return resp
+	}
+
+	mockBuiltinAuthProvider(t, false)
+
+	t.Run("pending session is not signed in", func(t *testing.T) {
+		cookies := signInWithPassword(t)
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		for _, c := range cookies {
+			req.AddCookie(c)
+		}
+		var called bool
+		session.CookieMiddleware(logger, db, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			called = true
+			assert.False(t, actor.FromContext(r.Context()).IsAuthenticated())
+		})).ServeHTTP(httptest.NewRecorder(), req)
+		assert.True(t, called)
+
+		// The lockout is not reset until the second factor is verified.
+		mockrequire.NotCalled(t, lockout.ResetFunc)
+	})
+
+	t.Run("invalid code", func(t *testing.T) {
+		cookies := signInWithPassword(t)
+		resp := verify(cookies, `{"method":"totp","code":"000000x"}`)
+		assert.Equal(t, http.StatusUnauthorized, resp.Code)
+		mockrequire.Called(t, lockout.IncreaseFailedAttemptFunc)
+		assert.NotNil(t, session.PendingSecondFactorActor(requestWithCookies(cookies)))
+	})
+
+	t.Run("valid code", func(t *testing.T) {
+		cookies := signInWithPassword(t)
+		resp := verify(cookies, fmt.Sprintf(`{"method":"totp","code":%q}`, testTOTPCode(t, secret)))
+		require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+		mockrequire.Called(t, lockout.ResetFunc)
+		mockrequire.Called(t, secondFactors.UseTOTPStepFunc)
+
+		req := requestWithCookies(cookies)
+		assert.Nil(t, session.PendingSecondFactorActor(req))
+		assert.True(t, session.SecondFactorVerifiedWithin(req, time.Minute))
+	})
+
+	t.Run("replayed code", func(t *testing.T) {
+		secondFactors.UseTOTPStepFunc.PushReturn(false, nil)
+		cookies := signInWithPassword(t)
+		resp := verify(cookies, fmt.Sprintf(`{"method":"totp","code":%q}`, testTOTPCode(t, secret)))
+		assert.Equal(t, http.StatusUnauthorized, resp.Code)
+	})
+
+	t.Run("locked out", func(t *testing.T) {
+		cookies := signInWithPassword(t)
+		lockout.IsLockedOutFunc.PushReturn("too many attempts", true)
+		resp := verify(cookies, fmt.Sprintf(`{"method":"totp","code":%q}`, testTOTPCode(t, secret)))
+		assert.Equal(t, http.StatusUnprocessableEntity, resp.Code)
+	})
+}
+
+func TestHandleSignIn_SecondFactorRequiredForSiteAdmins(t *testing.T) {
+	cleanup := session.ResetMockSessionStore(t)
+	defer cleanup()
+
+	user := &types.User{ID: 1, Username: "admin", SiteAdmin: true, CreatedAt: time.Now()}
+	users := dbmocks.NewMockUserStore()
+	users.GetByUsernameFunc.SetDefaultReturn(user, nil)
+	users.IsPasswordFunc.SetDefaultReturn(true, nil)
+
+	secondFactors := dbmocks.NewMockUserSecondFactorsStore()
+	secondFactors.GetTOTPFunc.SetDefaultReturn(nil, database.SecondFactorNotFoundErr{})
+
+	db := dbmocks.NewMockDB()
+	db.UsersFunc.SetDefaultReturn(users)
+	db.UserSecondFactorsFunc.SetDefaultReturn(secondFactors)
+	db.EventLogsFunc.SetDefaultReturn(dbmocks.NewMockEventLogStore())
+	db.SecurityEventLogsFunc.SetDefaultReturn(dbmocks.NewMockSecurityEventLogsStore())
+
+	signIn := HandleSignIn(logtest.NoOp(t), db, NewMockLockoutStore(), telemetry.NewEventRecorder(telemetrytest.NewMockEventsStore()))
+	signInWithPassword := func() *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/-/sign-in", strings.NewReader(`{"email":"admin","password":"correct"}`))
+		resp := httptest.NewRecorder()
+		signIn(resp, req)
+		return resp
+	}
+
+	t.Run("not required", func(t *testing.T) {
+		mockBuiltinAuthProvider(t, false)
+		resp := signInWithPassword()
+		assert.Equal(t, http.StatusOK, resp.Code)
+		assert.Empty(t, resp.Body.String())
+	})
+
+	t.Run("required", func(t *testing.T) {
+		mockBuiltinAuthProvider(t, true)
+		resp := signInWithPassword()
+		require.Equal(t, http.StatusOK, resp.Code)
+
+		var status secondFactorStatus
+		require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &status))
+		assert.True(t, status.SecondFactorRequired)
+		assert.True(t, status.EnrollmentRequired)
+		assert.Empty(t, status.Methods)
+		assert.NotNil(t, session.PendingSecondFactorActor(requestWithCookies(resp.Result().Cookies())))
+	})
+}
+
+func TestHandleSecondFactor_Remove(t *testing.T) {
+	cleanup := session.ResetMockSessionStore(t)
+	defer cleanup()
+	mockBuiltinAuthProvider(t, true)
+
+	confirmedAt := time.Now()
+	user := &types.User{ID: 1, Username: "admin", SiteAdmin: true}
+	users := dbmocks.NewMockUserStore()
+	users.GetByIDFunc.SetDefaultReturn(user, nil)
+
+	secondFactors := dbmocks.NewMockUserSecondFactorsStore()
+	secondFactors.GetTOTPFunc.SetDefaultReturn(&database.UserTOTPCredential{UserID: 1, ConfirmedAt: &confirmedAt}, nil)
+
+	db := dbmocks.NewMockDB()
+	db.UsersFunc.SetDefaultReturn(users)
+	db.UserSecondFactorsFunc.SetDefaultReturn(secondFactors)
+
+	h := HandleSecondFactor(logtest.NoOp(t), db, NewMockLockoutStore(), telemetry.NewEventRecorder(telemetrytest.NewMockEventsStore()))
+	remove := func(ctx context.Context) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/-/second-factor/remove", strings.NewReader(`{"method":"totp"}`)).WithContext(ctx)
+		resp := httptest.NewRecorder()
+		h.ServeHTTP(resp, req)
+		return resp
+	}
+
+	t.Run("not signed in", func(t *testing.T) {
+		assert.Equal(t, http.StatusUnauthorized, remove(context.Background()).Code)
+	})
+
+	t.Run("access token", func(t *testing.T) {
+		assert.Equal(t, http.StatusUnauthorized, remove(actor.WithActor(context.Background(), &actor.Actor{UID: 1})).Code)
+	})
+
+	t.Run("not recently verified", func(t *testing.T) {
+		resp := remove(actor.WithActor(context.Background(), &actor.Actor{UID: 1, FromSessionCookie: true}))
+		assert.Equal(t, http.StatusForbidden, resp.Code)
+		mockrequire.NotCalled(t, secondFactors.WithTransactFunc)
+	})
+}
+
+func requestWithCookies(cookies []*http.Cookie) *http.Request {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	for _, c := range cookies {
+		req.AddCookie(c)
+	}
+	return req
+}
@@ -0,0 +1,117 @@
+// Package groupmappings grants repository permissions based on the groups asserted
+// by OpenID Connect and SAML authentication providers, as configured by the
+// "permissions.groupMappings" site configuration.
+package groupmappings
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/grafana/regexp"
+
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/encryption"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+// Mappings maps the name of a group to the repository name patterns its members
+// are granted read access to.
+type Mappings map[string][]string
+
+// FromConfig returns the mappings of the given "permissions.groupMappings" site
+// configuration. It returns an error if any of the patterns is not a valid regular
+// expression.
+func FromConfig(cfg []*schema.PermissionsGroupMapping) (Mappings, error) {
+	m := make(Mappings, len(cfg))
+	for _, mapping := range cfg {
+		if mapping.Group == "" {
+			return nil, errors.New("group mapping is missing a group name")
+		}
+		for _, pattern := range mapping.Repos {
+			if _, err := regexp.Compile(pattern); err != nil {
+				return nil, errors.Wrapf(err, "invalid repository pattern %q for group %q", pattern, mapping.Group)
+			}
+			m[mapping.Group] = append(m[mapping.Group], pattern)
+		}
+	}
+	return m, nil
+}
+
+// Enabled returns true if at least one group is mapped to repositories.
+func (m Mappings) Enabled() bool {
+	return len(m) > 0
+}
+
+// RepoPatterns returns the deduplicated and sorted repository name patterns that
+// the given groups are mapped to.
+func (m Mappings) RepoPatterns(groups []string) []string {
+	seen := make(map[string]struct{})
+	var patterns []string
+	for _, group := range groups {
+		for _, pattern := range m[group] {
+			if _, ok := seen[pattern]; ok {
+				continue
+			}
+			seen[pattern] = struct{}{}
+			patterns = append(patterns, pattern)
+		}
+	}
+	sort.Strings(patterns)
+	return patterns
+}
+
+// ListRepoIDs returns the IDs of the private repositories whose names match any of
+// the patterns the given groups are mapped to.
+func (m Mappings) ListRepoIDs(ctx context.Context, repos database.RepoStore, groups []string) ([]int32, error) {
+	patterns := m.RepoPatterns(groups)
+	if len(patterns) == 0 {
+		return []int32{}, nil
+	}
+
+	// IncludePatterns must all match, so the patterns are combined into a single
+	// alternation to match any of them.
+	alternatives := make([]string, 0, len(patterns))
+	for _, pattern := range patterns {
+		alternatives = append(alternatives, "(?:"+pattern+")")
+	}
+
+	rs, err := repos.ListMinimalRepos(ctx, database.ReposListOptions{
+		IncludePatterns: []string{strings.Join(alternatives, "|")},
+		OnlyPrivate:     true,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "list repositories matching group mappings")
+	}
+
+	ids := make([]int32, 0, len(rs))
+	for _, r := range rs {
+		ids = append(ids, int32(r.ID))
+	}
+	return ids, nil
+}
+
+// SupportsAccount returns true if the groups of the given external account are
+// recorded in its account data, which is the case for accounts of the OpenID
+// Connect and SAML authentication providers.
+func SupportsAccount(acct *extsvc.Account) bool {
+	return acct.ServiceType == "openidconnect" || acct.ServiceType == "saml"
+}
+
+// AccountGroups returns the groups recorded in the account data of the given
+// external account when the user last signed in.
+func AccountGroups(ctx context.Context, acct *extsvc.Account) ([]string, error) {
+	if acct.Data == nil {
+		return nil, nil
+	}
+
+	data, err := encryption.DecryptJSON[struct {
+		Groups []string `json:"groups"`
+	}](ctx, acct.Data)
+	if err != nil {
+		return nil, errors.Wrap(err, "decrypt account data")
+	}
+	return data.Groups, nil
+}
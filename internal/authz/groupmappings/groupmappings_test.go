@@ -0,0 +1,88 @@
+package groupmappings
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbmocks"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+func TestFromConfig(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		m, err := FromConfig([]*schema.PermissionsGroupMapping{
+			{Group: "engineering", Repos: []string{"^github.com/acme/"}},
+			{Group: "engineering", Repos: []string{"^gitlab.com/acme/"}},
+			{Group: "security", Repos: []string{"secret"}},
+		})
+		require.NoError(t, err)
+		assert.True(t, m.Enabled())
+		assert.Equal(t, Mappings{
+			"engineering": {"^github.com/acme/", "^gitlab.com/acme/"},
+			"security":    {"secret"},
+		}, m)
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		m, err := FromConfig(nil)
+		require.NoError(t, err)
+		assert.False(t, m.Enabled())
+	})
+
+	t.Run("invalid pattern", func(t *testing.T) {
+		_, err := FromConfig([]*schema.PermissionsGroupMapping{{Group: "engineering", Repos: []string{"("}}})
+		assert.ErrorContains(t, err, `invalid repository pattern "(" for group "engineering"`)
+	})
+
+	t.Run("missing group", func(t *testing.T) {
+		_, err := FromConfig([]*schema.PermissionsGroupMapping{{Repos: []string{"^github.com/acme/"}}})
+		assert.Error(t, err)
+	})
+}
+
+func TestMappings_ListRepoIDs(t *testing.T) {
+	m := Mappings{
+		"engineering": {"^github.com/acme/", "secret"},
+		"security":    {"secret"},
+	}
+
+	repos := dbmocks.NewMockRepoStore()
+	repos.ListMinimalReposFunc.SetDefaultHook(func(ctx context.Context, opts database.ReposListOptions) ([]types.MinimalRepo, error) {
+		assert.Equal(t, []string{"(?:^github.com/acme/)|(?:secret)"}, opts.IncludePatterns)
+		assert.True(t, opts.OnlyPrivate)
+		return []types.MinimalRepo{{ID: 1}, {ID: 2}}, nil
+	})
+
+	ids, err := m.ListRepoIDs(context.Background(), repos, []string{"security", "engineering", "sales"})
+	require.NoError(t, err)
+	assert.Equal(t, []int32{1, 2}, ids)
+
+	t.Run("no matching group", func(t *testing.T) {
+		ids, err := m.ListRepoIDs(context.Background(), repos, []string{"sales"})
+		require.NoError(t, err)
+		assert.Empty(t, ids)
+		assert.Len(t, repos.ListMinimalReposFunc.History(), 1)
+	})
+}
+
+func TestAccountGroups(t *testing.T) {
+	acct := &extsvc.Account{
+		AccountSpec: extsvc.AccountSpec{ServiceType: "saml"},
+		AccountData: extsvc.AccountData{
+			Data: extsvc.NewUnencryptedData([]byte(`{"NameID":"alice","groups":["engineering","security"]}`)),
+		},
+	}
+	assert.True(t, SupportsAccount(acct))
+
+	groups, err := AccountGroups(context.Background(), acct)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"engineering", "security"}, groups)
+
+	assert.False(t, SupportsAccount(&extsvc.Account{AccountSpec: extsvc.AccountSpec{ServiceType: extsvc.TypeGitHub}}))
+}
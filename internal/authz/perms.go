@@ -96,6 +96,9 @@ const (
 	SourceRepoSync PermsSource = "repo_sync"
 	SourceUserSync PermsSource = "user_sync"
 	SourceAPI      PermsSource = "api"
+	// SourceGroupSync is the source of permissions granted by the groups asserted by an
+	// authentication provider, see the "permissions.groupMappings" site configuration.
+	SourceGroupSync PermsSource = "group_sync"
 )
 
 func (s PermsSource) ToGraphQL() string { return strings.ToUpper(string(s)) }
@@ -0,0 +1,346 @@
+package permssync
+
+import (
+	"context"
+	"sort"
+
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/authz"
+	"github.com/sourcegraph/sourcegraph/internal/authz/groupmappings"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// PermsChange describes how a permissions sync would change a permission.
+type PermsChange string
+
+const (
+	PermsChangeAdded     PermsChange = "ADDED"
+	PermsChangeRemoved   PermsChange = "REMOVED"
+	PermsChangeUnchanged PermsChange = "UNCHANGED"
+)
+
+// ExplainedPermission is a permission of a user to read a repository, which is
+// stored, would be stored by a permissions sync, or both.
+type ExplainedPermission struct {
+	UserID            int32
+	ExternalAccountID int32
+	RepoID            api.RepoID
+	// Source is where the permission originates from.
+	Source authz.PermsSource
+	// Change is how a permissions sync would change the permission.
+	Change PermsChange
+}
+
+// Explanation is the result of a permissions sync dry run for a user or a
+// repository, compared with the permissions currently stored.
+type Explanation struct {
+	// ProviderStates are the states of the authz providers that were fetched.
+	ProviderStates database.CodeHostStatusesSet
+	// SiteAdmin is true if the user is a site admin who can read all repositories
+	// regardless of permissions.
+	SiteAdmin bool
+	// Unrestricted is true if the repository can be read by all users regardless of
+	// permissions.
+	Unrestricted bool
+	// Permissions are sorted by repository ID and user ID.
+	Permissions []ExplainedPermission
+}
+
+// explainKey identifies a permission of an external account of a user to read a
+// repository.
+type explainKey struct {
+	userID            int32
+	externalAccountID int32
+	repoID            api.RepoID
+}
+
+// syncSource returns the source that replaces permissions of the given source when
+// permissions are synced. Explicit permissions are never replaced by a sync.
+func syncSource(source authz.PermsSource) authz.PermsSource {
+	switch source {
+	case authz.SourceRepoSync, authz.SourceUserSync:
+		return authz.SourceUserSync
+	default:
+		return source
+	}
+}
+
+// diffPermissions compares the stored permissions with the fetched ones. Stored
+// permissions are only reported as removed if they would be replaced, i.e. if
+// replaced returns true for them.
+func diffPermissions(stored []authz.Permission, fetched map[explainKey]authz.PermsSource, replaced func(authz.Permission) bool) []ExplainedPermission {
+	perms := make([]ExplainedPermission, 0, len(stored)+len(fetched))
+	seen := make(map[explainKey]struct{}, len(stored))
+	for _, p := range stored {
+		key := explainKey{userID: p.UserID, externalAccountID: p.ExternalAccountID, repoID: api.RepoID(p.RepoID)}
+		seen[key] = struct{}{}
+
+		change := PermsChangeUnchanged
+		if _, ok := fetched[key]; !ok && replaced(p) {
+			change = PermsChangeRemoved
+		}
+		perms = append(perms, ExplainedPermission{
+			UserID:            p.UserID,
+			ExternalAccountID: p.ExternalAccountID,
+			RepoID:            api.RepoID(p.RepoID),
+			Source:            p.Source,
+			Change:            change,
+		})
+	}
+	for key, source := range fetched {
+		if _, ok := seen[key]; ok {
+			continue
+		}
+		perms = append(perms, ExplainedPermission{
+			UserID:            key.userID,
+			ExternalAccountID: key.externalAccountID,
+			RepoID:            key.repoID,
+			Source:            source,
+			Change:            PermsChangeAdded,
+		})
+	}
+
+	sort.Slice(perms, func(i, j int) bool {
+		if perms[i].RepoID != perms[j].RepoID {
+			return perms[i].RepoID < perms[j].RepoID
+		}
+		if perms[i].UserID != perms[j].UserID {
+			return perms[i].UserID < perms[j].UserID
+		}
+		return perms[i].ExternalAccountID < perms[j].ExternalAccountID
+	})
+	return perms
+}
+
+// ExplainUserPerms fetches the permissions of the given user from the authz
+// providers of the user's external accounts and from group mappings, without
+// storing them, and compares them with the permissions currently stored.
+func ExplainUserPerms(ctx context.Context, db database.DB, userID int32) (*Explanation, error) {
+	user, err := db.Users().GetByID(ctx, userID)
+	if err != nil {
+		return nil, errors.Wrap(err, "get user")
+	}
+
+	accts, err := db.UserExternalAccounts().List(ctx, database.ExternalAccountsListOptions{
+		UserID:         userID,
+		ExcludeExpired: true,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "list external accounts")
+	}
+
+	mappings, err := groupmappings.FromConfig(conf.Get().PermissionsGroupMappings)
+	if err != nil {
+		return nil, err
+	}
+
+	_, providers := authz.GetProviders()
+	byServiceID := make(map[string]authz.Provider, len(providers))
+	for _, p := range providers {
+		byServiceID[p.ServiceID()] = p
+	}
+
+	explanation := &Explanation{
+		SiteAdmin: user.SiteAdmin && !conf.Get().AuthzEnforceForSiteAdmins,
+	}
+	fetched := make(map[explainKey]authz.PermsSource)
+	// The sources of the permissions each external account would have replaced by a sync.
+	replacedSources := make(map[int32]authz.PermsSource)
+	addFetched := func(acctID int32, repoIDs []api.RepoID, source authz.PermsSource) {
+		replacedSources[acctID] = source
+		for _, repoID := range repoIDs {
+			fetched[explainKey{userID: userID, externalAccountID: acctID, repoID: repoID}] = source
+		}
+	}
+
+	// 🚨 SECURITY: Repositories are looked up as the internal actor, since the
+	// explanation must include the repositories the user can't read yet.
+	internalCtx := actor.WithInternalActor(ctx)
+	repos := db.Repos()
+	for _, acct := range accts {
+		if groupmappings.SupportsAccount(acct) {
+			groups, err := groupmappings.AccountGroups(ctx, acct)
+			if err != nil {
+				return nil, errors.Wrapf(err, "get groups of external account %d", acct.ID)
+			}
+			ids, err := mappings.ListRepoIDs(internalCtx, repos, groups)
+			if err != nil {
+				return nil, err
+			}
+			repoIDs := make([]api.RepoID, 0, len(ids))
+			for _, id := range ids {
+				repoIDs = append(repoIDs, api.RepoID(id))
+			}
+			addFetched(acct.ID, repoIDs, authz.SourceGroupSync)
+		}
+
+		provider := byServiceID[acct.ServiceID]
+		if provider == nil {
+			continue
+		}
+
+		extPerms, err := provider.FetchUserPerms(ctx, acct, authz.FetchPermsOptions{})
+		explanation.ProviderStates = append(explanation.ProviderStates, database.NewProviderStatus(provider, err, "FetchUserPerms"))
+		if err != nil {
+			// The stored permissions of the account are kept when they can't be fetched.
+			continue
+		}
+
+		repoIDs, err := listPrivateRepoIDs(internalCtx, repos, provider, extPerms)
+		if err != nil {
+			return nil, err
+		}
+		addFetched(acct.ID, repoIDs, authz.SourceUserSync)
+	}
+
+	stored, err := db.Perms().LoadUserPermissions(ctx, userID)
+	if err != nil {
+		return nil, errors.Wrap(err, "load user permissions")
+	}
+
+	explanation.Permissions = diffPermissions(stored, fetched, func(p authz.Permission) bool {
+		source, ok := replacedSources[p.ExternalAccountID]
+		return ok && source == syncSource(p.Source)
+	})
+	return explanation, nil
+}
+
+// ExplainRepoPerms fetches the permissions of the given repository from the authz
+// provider of its code host, without storing them, and compares them with the
+// permissions currently stored.
+func ExplainRepoPerms(ctx context.Context, db database.DB, repoID api.RepoID) (*Explanation, error) {
+	// 🚨 SECURITY: The repository is looked up as the internal actor, since its
+	// permissions are explained regardless of whether the caller can read it.
+	repo, err := db.Repos().Get(actor.WithInternalActor(ctx), repoID)
+	if err != nil {
+		return nil, errors.Wrap(err, "get repository")
+	}
+
+	explanation := &Explanation{}
+	if !repo.Private {
+		explanation.Unrestricted = true
+	} else {
+		explanation.Unrestricted, err = db.Perms().IsRepoUnrestricted(ctx, repoID)
+		if err != nil {
+			return nil, errors.Wrap(err, "check if repository is unrestricted")
+		}
+	}
+
+	// Only private repositories have their permissions fetched, from the authz
+	// provider of any of the code hosts the repository is synced from.
+	var provider authz.Provider
+	if repo.Private {
+		_, providers := authz.GetProviders()
+		for _, p := range providers {
+			if _, ok := repo.Sources[p.URN()]; ok {
+				provider = p
+				break
+			}
+		}
+	}
+
+	fetched := make(map[explainKey]authz.PermsSource)
+	replaced := false
+	if provider != nil {
+		accountIDs, err := provider.FetchRepoPerms(ctx, &extsvc.Repository{
+			URI:              repo.URI,
+			ExternalRepoSpec: repo.ExternalRepo,
+		}, authz.FetchPermsOptions{})
+		explanation.ProviderStates = append(explanation.ProviderStates, database.NewProviderStatus(provider, err, "FetchRepoPerms"))
+		if err == nil {
+			replaced = true
+
+			ids := make([]string, 0, len(accountIDs))
+			for _, id := range accountIDs {
+				ids = append(ids, string(id))
+			}
+			users, err := db.Perms().GetUserIDsByExternalAccounts(ctx, &extsvc.Accounts{
+				ServiceType: provider.ServiceType(),
+				ServiceID:   provider.ServiceID(),
+				AccountIDs:  ids,
+			})
+			if err != nil {
+				return nil, errors.Wrap(err, "get user IDs by external accounts")
+			}
+			for _, u := range users {
+				fetched[explainKey{userID: u.UserID, externalAccountID: u.ExternalAccountID, repoID: repoID}] = authz.SourceRepoSync
+			}
+		}
+	}
+
+	stored, err := db.Perms().LoadRepoPermissions(ctx, int32(repoID))
+	if err != nil {
+		return nil, errors.Wrap(err, "load repository permissions")
+	}
+	// The permission of an unrestricted repository is not a user permission.
+	if len(stored) == 1 && stored[0].UserID == 0 {
+		stored = nil
+	}
+
+	explanation.Permissions = diffPermissions(stored, fetched, func(p authz.Permission) bool {
+		return replaced && syncSource(p.Source) == authz.SourceUserSync
+	})
+	return explanation, nil
+}
+
+// listPrivateRepoIDs returns the IDs of the private repositories the given
+// permissions fetched from the given provider grant access to.
+func listPrivateRepoIDs(ctx context.Context, repos database.RepoStore, provider authz.Provider, extPerms *authz.ExternalUserPermissions) ([]api.RepoID, error) {
+	if extPerms == nil {
+		return nil, nil
+	}
+
+	toSpecs := func(ids []extsvc.RepoID) []api.ExternalRepoSpec {
+		specs := make([]api.ExternalRepoSpec, 0, len(ids))
+		for _, id := range ids {
+			specs = append(specs, api.ExternalRepoSpec{
+				ID:          string(id),
+				ServiceType: provider.ServiceType(),
+				ServiceID:   provider.ServiceID(),
+			})
+		}
+		return specs
+	}
+
+	var repoIDs []api.RepoID
+	// Specs are listed in batches to stay below the Postgres limit of bind parameters.
+	const batchSize = 10000
+	exacts := toSpecs(extPerms.Exacts)
+	for len(exacts) > 0 {
+		n := batchSize
+		if len(exacts) < n {
+			n = len(exacts)
+		}
+		rs, err := repos.ListMinimalRepos(ctx, database.ReposListOptions{
+			ExternalRepos: exacts[:n],
+			OnlyPrivate:   true,
+		})
+		if err != nil {
+			return nil, errors.Wrap(err, "list private repositories by exact matching")
+		}
+		for _, r := range rs {
+			repoIDs = append(repoIDs, r.ID)
+		}
+		exacts = exacts[n:]
+	}
+
+	// Exclusions are relative to inclusions, so they are meaningless without any.
+	if len(extPerms.IncludeContains) > 0 {
+		rs, err := repos.ListMinimalRepos(ctx, database.ReposListOptions{
+			ExternalRepoIncludeContains: toSpecs(extPerms.IncludeContains),
+			ExternalRepoExcludeContains: toSpecs(extPerms.ExcludeContains),
+			OnlyPrivate:                 true,
+		})
+		if err != nil {
+			return nil, errors.Wrap(err, "list private repositories by contains matching")
+		}
+		for _, r := range rs {
+			repoIDs = append(repoIDs, r.ID)
+		}
+	}
+	return repoIDs, nil
+}
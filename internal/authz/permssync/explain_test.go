@@ -0,0 +1,161 @@
+package permssync
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/authz"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbmocks"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+type explainProvider struct {
+	fetchUserPerms func(*extsvc.Account) (*authz.ExternalUserPermissions, error)
+	fetchRepoPerms func(*extsvc.Repository) ([]extsvc.AccountID, error)
+}
+
+func (p *explainProvider) FetchAccount(context.Context, *types.User, []*extsvc.Account, []string) (*extsvc.Account, error) {
+	return nil, nil
+}
+
+func (p *explainProvider) FetchUserPerms(_ context.Context, acct *extsvc.Account, _ authz.FetchPermsOptions) (*authz.ExternalUserPermissions, error) {
+	return p.fetchUserPerms(acct)
+}
+
+func (p *explainProvider) FetchRepoPerms(_ context.Context, repo *extsvc.Repository, _ authz.FetchPermsOptions) ([]extsvc.AccountID, error) {
+	return p.fetchRepoPerms(repo)
+}
+
+func (p *explainProvider) ServiceType() string                      { return extsvc.TypeGitHub }
+func (p *explainProvider) ServiceID() string                        { return "https://github.com/" }
+func (p *explainProvider) URN() string                              { return extsvc.URN(extsvc.TypeGitHub, 1) }
+func (p *explainProvider) ValidateConnection(context.Context) error { return nil }
+
+func TestExplainUserPerms(t *testing.T) {
+	provider := &explainProvider{
+		fetchUserPerms: func(acct *extsvc.Account) (*authz.ExternalUserPermissions, error) {
+			if acct.ID == 2 {
+				return nil, errors.New("rate limited")
+			}
+			return &authz.ExternalUserPermissions{Exacts: []extsvc.RepoID{"MDEwOlJlcG9zaXRvcnkx", "MDEwOlJlcG9zaXRvcnky"}}, nil
+		},
+	}
+	authz.SetProviders(false, []authz.Provider{provider})
+	t.Cleanup(func() { authz.SetProviders(true, nil) })
+
+	users := dbmocks.NewMockUserStore()
+	users.GetByIDFunc.SetDefaultReturn(&types.User{ID: 1, SiteAdmin: true}, nil)
+
+	accounts := dbmocks.NewMockUserExternalAccountsStore()
+	accounts.ListFunc.SetDefaultReturn([]*extsvc.Account{
+		{ID: 1, UserID: 1, AccountSpec: extsvc.AccountSpec{ServiceType: extsvc.TypeGitHub, ServiceID: "https://github.com/"}},
+		{ID: 2, UserID: 1, AccountSpec: extsvc.AccountSpec{ServiceType: extsvc.TypeGitHub, ServiceID: "https://github.com/"}},
+	}, nil)
+
+	repos := dbmocks.NewMockRepoStore()
+	repos.ListMinimalReposFunc.SetDefaultHook(func(ctx context.Context, opts database.ReposListOptions) ([]types.MinimalRepo, error) {
+		assert.True(t, actor.FromContext(ctx).IsInternal())
+		assert.True(t, opts.OnlyPrivate)
+		require.Len(t, opts.ExternalRepos, 2)
+		return []types.MinimalRepo{{ID: 10}, {ID: 11}}, nil
+	})
+
+	perms := dbmocks.NewMockPermsStore()
+	perms.LoadUserPermissionsFunc.SetDefaultReturn([]authz.Permission{
+		{UserID: 1, ExternalAccountID: 1, RepoID: 11, Source: authz.SourceRepoSync},
+		{UserID: 1, ExternalAccountID: 1, RepoID: 12, Source: authz.SourceUserSync},
+		{UserID: 1, ExternalAccountID: 2, RepoID: 13, Source: authz.SourceUserSync},
+		{UserID: 1, RepoID: 14, Source: authz.SourceAPI},
+	}, nil)
+
+	db := dbmocks.NewMockDB()
+	db.UsersFunc.SetDefaultReturn(users)
+	db.UserExternalAccountsFunc.SetDefaultReturn(accounts)
+	db.ReposFunc.SetDefaultReturn(repos)
+	db.PermsFunc.SetDefaultReturn(perms)
+
+	ctx := actor.WithActor(context.Background(), actor.FromUser(1))
+	explanation, err := ExplainUserPerms(ctx, db, 1)
+	require.NoError(t, err)
+
+	assert.True(t, explanation.SiteAdmin)
+	require.Len(t, explanation.ProviderStates, 2)
+	assert.Equal(t, database.CodeHostStatusSuccess, explanation.ProviderStates[0].Status)
+	assert.Equal(t, database.CodeHostStatusError, explanation.ProviderStates[1].Status)
+	assert.Equal(t, []ExplainedPermission{
+		{UserID: 1, ExternalAccountID: 1, RepoID: 10, Source: authz.SourceUserSync, Change: PermsChangeAdded},
+		{UserID: 1, ExternalAccountID: 1, RepoID: 11, Source: authz.SourceRepoSync, Change: PermsChangeUnchanged},
+		{UserID: 1, ExternalAccountID: 1, RepoID: 12, Source: authz.SourceUserSync, Change: PermsChangeRemoved},
+		// Permissions of accounts that failed to fetch are kept.
+		{UserID: 1, ExternalAccountID: 2, RepoID: 13, Source: authz.SourceUserSync, Change: PermsChangeUnchanged},
+		// Explicit permissions are never removed by a sync.
+		{UserID: 1, RepoID: 14, Source: authz.SourceAPI, Change: PermsChangeUnchanged},
+	}, explanation.Permissions)
+}
+
+func TestExplainRepoPerms(t *testing.T) {
+	provider := &explainProvider{
+		fetchRepoPerms: func(*extsvc.Repository) ([]extsvc.AccountID, error) {
+			return []extsvc.AccountID{"alice", "bob"}, nil
+		},
+	}
+	authz.SetProviders(false, []authz.Provider{provider})
+	t.Cleanup(func() { authz.SetProviders(true, nil) })
+
+	repos := dbmocks.NewMockRepoStore()
+	repos.GetFunc.SetDefaultHook(func(ctx context.Context, _ api.RepoID) (*types.Repo, error) {
+		assert.True(t, actor.FromContext(ctx).IsInternal())
+		return &types.Repo{
+			ID:      10,
+			Private: true,
+			Sources: map[string]*types.SourceInfo{provider.URN(): {}},
+		}, nil
+	})
+
+	perms := dbmocks.NewMockPermsStore()
+	perms.GetUserIDsByExternalAccountsFunc.SetDefaultReturn(map[string]authz.UserIDWithExternalAccountID{
+		"alice": {UserID: 1, ExternalAccountID: 1},
+		"bob":   {UserID: 2, ExternalAccountID: 2},
+	}, nil)
+	perms.LoadRepoPermissionsFunc.SetDefaultReturn([]authz.Permission{
+		{UserID: 1, ExternalAccountID: 1, RepoID: 10, Source: authz.SourceRepoSync},
+		{UserID: 3, ExternalAccountID: 3, RepoID: 10, Source: authz.SourceUserSync},
+		{UserID: 4, ExternalAccountID: 4, RepoID: 10, Source: authz.SourceGroupSync},
+	}, nil)
+
+	db := dbmocks.NewMockDB()
+	db.ReposFunc.SetDefaultReturn(repos)
+	db.PermsFunc.SetDefaultReturn(perms)
+
+	ctx := actor.WithActor(context.Background(), actor.FromUser(1))
+	explanation, err := ExplainRepoPerms(ctx, db, 10)
+	require.NoError(t, err)
+
+	assert.False(t, explanation.Unrestricted)
+	require.Len(t, explanation.ProviderStates, 1)
+	assert.Equal(t, []ExplainedPermission{
+		{UserID: 1, ExternalAccountID: 1, RepoID: 10, Source: authz.SourceRepoSync, Change: PermsChangeUnchanged},
+		{UserID: 2, ExternalAccountID: 2, RepoID: 10, Source: authz.SourceRepoSync, Change: PermsChangeAdded},
+		{UserID: 3, ExternalAccountID: 3, RepoID: 10, Source: authz.SourceUserSync, Change: PermsChangeRemoved},
+		{UserID: 4, ExternalAccountID: 4, RepoID: 10, Source: authz.SourceGroupSync, Change: PermsChangeUnchanged},
+	}, explanation.Permissions)
+
+	t.Run("public repository", func(t *testing.T) {
+		repos.GetFunc.PushReturn(&types.Repo{ID: 11}, nil)
+		perms.LoadRepoPermissionsFunc.PushReturn(nil, nil)
+
+		explanation, err := ExplainRepoPerms(context.Background(), db, 11)
+		require.NoError(t, err)
+		assert.True(t, explanation.Unrestricted)
+		assert.Empty(t, explanation.ProviderStates)
+		assert.Empty(t, explanation.Permissions)
+	})
+}
@@ -181,12 +181,15 @@ func RefreshInterval() time.Duration {
 
 // PermissionSyncingDisabled returns true if the background permissions syncing is not enabled.
 // It is not enabled if:
-//   - There are no code host connections with authorization or enforcePermissions enabled
+//   - There are no code host connections with authorization or enforcePermissions enabled,
+//     and no group mappings are configured
 //   - Not purchased with the current license
 //   - `disableAutoCodeHostSyncs` site setting is set to true
 func PermissionSyncingDisabled() bool {
 	_, p := authz.GetProviders()
-	return len(p) == 0 ||
+	// Users are also synced to grant permissions from group mappings, which
+	// don't need an authz provider.
+	return (len(p) == 0 && len(conf.Get().PermissionsGroupMappings) == 0) ||
 		licensing.Check(licensing.FeatureACLs) != nil ||
 		conf.Get().DisableAutoCodeHostSyncs
 }
@@ -807,6 +807,19 @@ func TestPermissionSyncingDisabled(t *testing.T) {
 		assert.True(t, PermissionSyncingDisabled())
 	})
 
+	t.Run("no authz providers with group mappings", func(t *testing.T) {
+		authz.SetProviders(true, nil)
+		conf.Mock(&conf.Unified{SiteConfiguration: schema.SiteConfiguration{
+			PermissionsGroupMappings: []*schema.PermissionsGroupMapping{{Group: "engineering", Repos: []string{"^git.example.com/"}}},
+		}})
+		t.Cleanup(func() {
+			authz.SetProviders(true, []authz.Provider{&mockProvider{}})
+			conf.Mock(nil)
+		})
+
+		assert.False(t, PermissionSyncingDisabled())
+	})
+
 	t.Run("permissions user mapping enabled", func(t *testing.T) {
 		cleanup := mockExplicitPermissions(true)
 		t.Cleanup(func() {
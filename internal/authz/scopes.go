@@ -4,10 +4,40 @@ const (
 	// Access token scopes.
 	ScopeUserAll       = "user:all"        // Full control of all resources accessible to the user account.
 	ScopeSiteAdminSudo = "site-admin:sudo" // Ability to perform any action as any other user.
+
+	// Fine-grained access token scopes. A token with only fine-grained scopes (and without
+	// ScopeUserAll) may only be used for the operations that its scopes permit.
+	ScopeSearchRead      = "search:read"      // Ability to run searches.
+	ScopeRepoRead        = "repo:read"        // Read access to repositories and their contents.
+	ScopeBatchesWrite    = "batches:write"    // Ability to create, apply, and manage batch changes.
+	ScopeCodeIntelUpload = "codeintel:upload" // Ability to upload precise code intelligence indexes.
 )
 
 // AllScopes is a list of all known access token scopes.
 var AllScopes = []string{
 	ScopeUserAll,
 	ScopeSiteAdminSudo,
+	ScopeSearchRead,
+	ScopeRepoRead,
+	ScopeBatchesWrite,
+	ScopeCodeIntelUpload,
+}
+
+// FineGrainedScopes is a list of all access token scopes that grant access to a restricted set
+// of operations.
+var FineGrainedScopes = []string{
+	ScopeSearchRead,
+	ScopeRepoRead,
+	ScopeBatchesWrite,
+	ScopeCodeIntelUpload,
+}
+
+// IsFineGrainedScope returns true if the given scope is one of FineGrainedScopes.
+func IsFineGrainedScope(scope string) bool {
+	for _, s := range FineGrainedScopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
 }
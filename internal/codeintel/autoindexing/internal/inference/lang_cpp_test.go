@@ -0,0 +1,40 @@
+package inference
+
+import (
+	"testing"
+)
+
+func TestCppGenerator(t *testing.T) {
+	mockIndexers(t, map[string]string{"cpp": "sourcegraph/scip-clang"})
+
+	testGenerators(t,
+		generatorTestCase{
+			description: "scip-clang compilation database",
+			repositoryContents: map[string]string{
+				"compile_commands.json":             "",
+				"CMakeLists.txt":                    "",
+				"lib/compile_commands.json":         "",
+				"third_party/compile_commands.json": "",
+			},
+		},
+		generatorTestCase{
+			description: "scip-clang cmake",
+			repositoryContents: map[string]string{
+				"CMakeLists.txt":             "",
+				"src/CMakeLists.txt":         "",
+				"src/util/CMakeLists.txt":    "",
+				"tools/gen/CMakeLists.txt":   "",
+				"test/CMakeLists.txt":        "",
+				"third_party/CMakeLists.txt": "",
+			},
+		},
+		generatorTestCase{
+			description: "scip-clang nested cmake projects",
+			repositoryContents: map[string]string{
+				"a/CMakeLists.txt":     "",
+				"a/lib/CMakeLists.txt": "",
+				"b/CMakeLists.txt":     "",
+			},
+		},
+	)
+}
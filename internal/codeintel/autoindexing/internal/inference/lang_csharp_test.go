@@ -0,0 +1,32 @@
+package inference
+
+import (
+	"testing"
+)
+
+func TestCSharpGenerator(t *testing.T) {
+	mockIndexers(t, map[string]string{"csharp": "sourcegraph/scip-dotnet"})
+
+	testGenerators(t,
+		generatorTestCase{
+			description: "scip-dotnet solution",
+			repositoryContents: map[string]string{
+				"App.sln":             "",
+				"src/App/App.csproj":  "",
+				"src/Lib/Lib.csproj":  "",
+				"tools/Tools.sln":     "",
+				"tests/App.Tests.sln": "",
+				"src/App/obj/Gen.sln": "",
+			},
+		},
+		generatorTestCase{
+			description: "scip-dotnet projects",
+			repositoryContents: map[string]string{
+				"src/App/App.csproj":           "",
+				"src/Lib/Lib.csproj":           "",
+				"tests/App.Tests.csproj":       "",
+				"src/App/bin/Debug/Gen.csproj": "",
+			},
+		},
+	)
+}
@@ -0,0 +1,22 @@
+package inference
+
+import (
+	"testing"
+)
+
+func TestDartGenerator(t *testing.T) {
+	mockIndexers(t, map[string]string{"dart": "sourcegraph/scip-dart"})
+
+	testGenerators(t,
+		generatorTestCase{
+			description: "scip-dart",
+			repositoryContents: map[string]string{
+				"pubspec.yaml":                "",
+				"packages/a/pubspec.yaml":     "",
+				"packages/b/pubspec.yaml":     "",
+				"example/pubspec.yaml":        "",
+				".dart_tool/pkg/pubspec.yaml": "",
+			},
+		},
+	)
+}
@@ -13,6 +13,14 @@ func TestGoGenerator(t *testing.T) {
 				"foo/baz/go.mod": "",
 			},
 		},
+		generatorTestCase{
+			description: "go modules in vendor",
+			repositoryContents: map[string]string{
+				"go.mod":                             "",
+				"vendor/github.com/foo/bar/go.mod":   "",
+				"internal/vendor/example.com/go.mod": "",
+			},
+		},
 		generatorTestCase{
 			description: "go files in root",
 			repositoryContents: map[string]string{
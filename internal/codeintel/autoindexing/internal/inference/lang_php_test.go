@@ -0,0 +1,34 @@
+package inference
+
+import (
+	"testing"
+)
+
+func TestPHPGenerator(t *testing.T) {
+	mockIndexers(t, map[string]string{"php": "sourcegraph/scip-php"})
+
+	testGenerators(t,
+		generatorTestCase{
+			description: "scip-php",
+			repositoryContents: map[string]string{
+				"composer.json":                 "",
+				"composer.lock":                 "",
+				"packages/a/composer.json":      "",
+				"vendor/acme/lib/composer.json": "",
+				"examples/demo/composer.json":   "",
+			},
+		},
+	)
+}
+
+func TestPHPGeneratorWithoutIndexer(t *testing.T) {
+	// There is no default indexer for PHP, so no jobs are inferred unless one is configured.
+	testGenerators(t,
+		generatorTestCase{
+			description: "scip-php without indexer",
+			repositoryContents: map[string]string{
+				"composer.json": "",
+			},
+		},
+	)
+}
@@ -33,6 +33,14 @@ func TestTypeScriptGenerator(t *testing.T) {
 				"c/tsconfig.json": "",
 			},
 		},
+		generatorTestCase{
+			description: "tsconfig in node_modules",
+			repositoryContents: map[string]string{
+				"tsconfig.json":                   "",
+				"node_modules/foo/tsconfig.json":  "",
+				"a/node_modules/bar/package.json": "",
+			},
+		},
 		generatorTestCase{
 			description: "typescript installation steps",
 			repositoryContents: map[string]string{
@@ -15,6 +15,9 @@ var Indexes = indexesAPI{}
 
 type indexesAPI struct{}
 
+// Only indexers whose image is pinned in defaultIndexerSHAs may be added here. The indexers
+// for C/C++, C#, Dart and PHP must be configured in codeIntelAutoIndexing.indexerMap until
+// their images are pinned.
 var defaultIndexers = map[string]string{
 	"go":         "sourcegraph/scip-go",
 	"java":       "sourcegraph/scip-java",
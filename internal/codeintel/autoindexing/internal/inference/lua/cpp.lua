@@ -0,0 +1,109 @@
+local path = require "path"
+local pattern = require "sg.autoindex.patterns"
+local recognizer = require "sg.autoindex.recognizer"
+
+local shared = require "sg.autoindex.shared"
+
+-- There is no pinned default image for scip-clang yet, so jobs are only inferred
+-- if an indexer is configured in codeIntelAutoIndexing.indexerMap.
+local has_indexer, indexer = pcall(require("sg.autoindex.indexes").get, "cpp")
+local outfile = "index.scip"
+
+local exclude_paths = pattern.new_path_combine {
+  shared.exclude_paths,
+  pattern.new_path_segment "third_party",
+  pattern.new_path_segment "vendor",
+}
+
+-- Returns the unique directories of the given paths which are not nested
+-- within the directory of another path.
+local toplevel_roots = function(paths)
+  local dirs = {}
+  for i = 1, #paths do
+    dirs[path.dirname(paths[i])] = true
+  end
+
+  local roots = {}
+  for dir in pairs(dirs) do
+    local nested = false
+    if dir ~= "" then
+      local ancestors = path.ancestors(dir)
+      for i = 1, #ancestors do
+        if dirs[ancestors[i]] then
+          nested = true
+          break
+        end
+      end
+    end
+
+    if not nested then
+      table.insert(roots, dir)
+    end
+  end
+
+  return roots
+end
+
+local compdb_recognizer = recognizer.new_path_recognizer {
+  patterns = {
+    pattern.new_path_basename "compile_commands.json",
+    pattern.new_path_exclude(exclude_paths),
+  },
+
+  -- Invoked when compile_commands.json files exist. Each compilation database
+  -- is indexed from its own directory, as its entries are commonly relative
+  -- to the project root.
+  generate = function(_, paths)
+    if not has_indexer then
+      return {}
+    end
+
+    local jobs = {}
+    for i = 1, #paths do
+      table.insert(jobs, {
+        steps = {},
+        root = path.dirname(paths[i]),
+        indexer = indexer,
+        indexer_args = { "scip-clang", "--compdb-path=compile_commands.json" },
+        outfile = outfile,
+      })
+    end
+
+    return jobs
+  end,
+}
+
+local cmake_recognizer = recognizer.new_path_recognizer {
+  patterns = {
+    pattern.new_path_basename "CMakeLists.txt",
+    pattern.new_path_exclude(exclude_paths),
+  },
+
+  -- Invoked when no compile_commands.json files exist but CMake projects do.
+  -- Nested CMakeLists.txt files are part of the enclosing project, so only
+  -- top-level projects are configured to emit a compilation database.
+  generate = function(_, paths)
+    if not has_indexer then
+      return {}
+    end
+
+    local jobs = {}
+    for _, root in ipairs(toplevel_roots(paths)) do
+      table.insert(jobs, {
+        steps = {},
+        local_steps = { "cmake -B build -DCMAKE_EXPORT_COMPILE_COMMANDS=ON" },
+        root = root,
+        indexer = indexer,
+        indexer_args = { "scip-clang", "--compdb-path=build/compile_commands.json" },
+        outfile = outfile,
+      })
+    end
+
+    return jobs
+  end,
+}
+
+return recognizer.new_fallback_recognizer {
+  compdb_recognizer,
+  cmake_recognizer,
+}
@@ -0,0 +1,79 @@
+local path = require "path"
+local pattern = require "sg.autoindex.patterns"
+local recognizer = require "sg.autoindex.recognizer"
+
+local shared = require "sg.autoindex.shared"
+
+-- There is no pinned default image for scip-dotnet yet, so jobs are only inferred
+-- if an indexer is configured in codeIntelAutoIndexing.indexerMap.
+local has_indexer, indexer = pcall(require("sg.autoindex.indexes").get, "csharp")
+local outfile = "index.scip"
+
+local exclude_paths = pattern.new_path_combine {
+  shared.exclude_paths,
+  pattern.new_path_segment "bin",
+  pattern.new_path_segment "obj",
+}
+
+-- Returns a job indexing the given solution or project file from its directory.
+local make_job = function(filepath)
+  local root = path.dirname(filepath)
+  local file = path.basename(filepath)
+
+  return {
+    steps = {},
+    local_steps = { "dotnet restore " .. file },
+    root = root,
+    indexer = indexer,
+    indexer_args = { "scip-dotnet", "index", file },
+    outfile = outfile,
+  }
+end
+
+local solution_recognizer = recognizer.new_path_recognizer {
+  patterns = {
+    pattern.new_path_extension "sln",
+    pattern.new_path_exclude(exclude_paths),
+  },
+
+  -- Invoked when solution files exist. A solution references all of the
+  -- projects which make it up, so projects are not indexed separately.
+  generate = function(_, paths)
+    if not has_indexer then
+      return {}
+    end
+
+    local jobs = {}
+    for i = 1, #paths do
+      table.insert(jobs, make_job(paths[i]))
+    end
+
+    return jobs
+  end,
+}
+
+local project_recognizer = recognizer.new_path_recognizer {
+  patterns = {
+    pattern.new_path_extension "csproj",
+    pattern.new_path_exclude(exclude_paths),
+  },
+
+  -- Invoked when no solution files exist but project files do
+  generate = function(_, paths)
+    if not has_indexer then
+      return {}
+    end
+
+    local jobs = {}
+    for i = 1, #paths do
+      table.insert(jobs, make_job(paths[i]))
+    end
+
+    return jobs
+  end,
+}
+
+return recognizer.new_fallback_recognizer {
+  solution_recognizer,
+  project_recognizer,
+}
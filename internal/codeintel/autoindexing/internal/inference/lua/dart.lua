@@ -0,0 +1,44 @@
+local path = require "path"
+local pattern = require "sg.autoindex.patterns"
+local recognizer = require "sg.autoindex.recognizer"
+
+local shared = require "sg.autoindex.shared"
+
+-- There is no pinned default image for scip-dart yet, so jobs are only inferred
+-- if an indexer is configured in codeIntelAutoIndexing.indexerMap.
+local has_indexer, indexer = pcall(require("sg.autoindex.indexes").get, "dart")
+local outfile = "index.scip"
+
+local exclude_paths = pattern.new_path_combine {
+  shared.exclude_paths,
+  pattern.new_path_segment ".dart_tool",
+}
+
+return recognizer.new_path_recognizer {
+  patterns = {
+    pattern.new_path_basename "pubspec.yaml",
+    pattern.new_path_exclude(exclude_paths),
+  },
+
+  -- Invoked when pubspec.yaml files exist. Each package is indexed
+  -- separately after its dependencies have been fetched.
+  generate = function(_, paths)
+    if not has_indexer then
+      return {}
+    end
+
+    local jobs = {}
+    for i = 1, #paths do
+      table.insert(jobs, {
+        steps = {},
+        local_steps = { "dart pub get" },
+        root = path.dirname(paths[i]),
+        indexer = indexer,
+        indexer_args = { "scip-dart", "./" },
+        outfile = outfile,
+      })
+    end
+
+    return jobs
+  end,
+}
@@ -15,9 +15,10 @@ else
 fi
 ]]
 
-local exclude_paths = pattern.new_path_combine(shared.exclude_paths, {
+local exclude_paths = pattern.new_path_combine {
+  shared.exclude_paths,
   pattern.new_path_segment "vendor",
-})
+}
 
 local gomod_recognizer = recognizer.new_path_recognizer {
   patterns = {
@@ -0,0 +1,44 @@
+local path = require "path"
+local pattern = require "sg.autoindex.patterns"
+local recognizer = require "sg.autoindex.recognizer"
+
+local shared = require "sg.autoindex.shared"
+
+-- There is no pinned default image for scip-php yet, so jobs are only inferred
+-- if an indexer is configured in codeIntelAutoIndexing.indexerMap.
+local has_indexer, indexer = pcall(require("sg.autoindex.indexes").get, "php")
+local outfile = "index.scip"
+
+local exclude_paths = pattern.new_path_combine {
+  shared.exclude_paths,
+  pattern.new_path_segment "vendor",
+}
+
+return recognizer.new_path_recognizer {
+  patterns = {
+    pattern.new_path_basename "composer.json",
+    pattern.new_path_exclude(exclude_paths),
+  },
+
+  -- Invoked when composer.json files exist. scip-php resolves symbols via
+  -- the composer autoloader, so dependencies are installed before indexing.
+  generate = function(_, paths)
+    if not has_indexer then
+      return {}
+    end
+
+    local jobs = {}
+    for i = 1, #paths do
+      table.insert(jobs, {
+        steps = {},
+        local_steps = { "composer install --no-interaction --no-progress --no-scripts" },
+        root = path.dirname(paths[i]),
+        indexer = indexer,
+        indexer_args = { "scip-php" },
+        outfile = outfile,
+      })
+    end
+
+    return jobs
+  end,
+}
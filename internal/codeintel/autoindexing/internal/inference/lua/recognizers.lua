@@ -1,8 +1,12 @@
 local config = require("sg.autoindex.config").new {}
 
 for _, name in ipairs {
+  "cpp",
+  "csharp",
+  "dart",
   "go",
   "java",
+  "php",
   "python",
   "ruby",
   "rust",
@@ -10,9 +10,10 @@ local indexer = require("sg.autoindex.indexes").get "typescript"
 local typescript_nmusl_command =
 	"N_NODE_MIRROR=https://unofficial-builds.nodejs.org/download/release n --arch x64-musl auto"
 
-local exclude_paths = pattern.new_path_combine(shared.exclude_paths, {
+local exclude_paths = pattern.new_path_combine {
+	shared.exclude_paths,
 	pattern.new_path_segment("node_modules"),
-})
+}
 
 local safe_decode = function(encoded)
   local _, payload = pcall(function()
@@ -0,0 +1,49 @@
+package luatypes
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestFlattenPattern(t *testing.T) {
+	include := func(glob string) *PathPattern { return NewPattern(glob, []string{glob}) }
+	vendor := include("vendor/")
+	nodeModules := include("node_modules/")
+	goMod := include("go.mod")
+	packageJSON := include("package.json")
+
+	pattern := NewCombinedPattern([]*PathPattern{
+		goMod,
+		NewCombinedPattern([]*PathPattern{packageJSON}),
+		NewExcludePattern([]*PathPattern{
+			// The descendants of exclude patterns are flattened like top-level patterns
+			NewCombinedPattern([]*PathPattern{vendor, nodeModules}),
+		}),
+	})
+
+	testCases := []struct {
+		name     string
+		inverted bool
+		expected []GlobAndPathspecPattern
+	}{
+		{
+			name:     "included patterns",
+			inverted: false,
+			expected: []GlobAndPathspecPattern{goMod.pattern, packageJSON.pattern},
+		},
+		{
+			name:     "excluded patterns",
+			inverted: true,
+			expected: []GlobAndPathspecPattern{vendor.pattern, nodeModules.pattern},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			if diff := cmp.Diff(testCase.expected, FlattenPattern(pattern, testCase.inverted)); diff != "" {
+				t.Errorf("unexpected patterns (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
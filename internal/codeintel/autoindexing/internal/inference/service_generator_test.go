@@ -15,7 +15,9 @@ import (
 	"github.com/google/go-cmp/cmp"
 	"gopkg.in/yaml.v3"
 
+	"github.com/sourcegraph/sourcegraph/internal/conf"
 	"github.com/sourcegraph/sourcegraph/lib/codeintel/autoindex/config"
+	"github.com/sourcegraph/sourcegraph/schema"
 )
 
 func TestMain(m *testing.M) {
@@ -25,6 +27,13 @@ func TestMain(m *testing.M) {
 
 var update = flag.Bool("update", false, "update testdata")
 
+// mockIndexers configures the given indexers in codeIntelAutoIndexing.indexerMap for the
+// duration of the test.
+func mockIndexers(t *testing.T, indexers map[string]string) {
+	conf.Mock(&conf.Unified{SiteConfiguration: schema.SiteConfiguration{CodeIntelAutoIndexingIndexerMap: indexers}})
+	t.Cleanup(func() { conf.Mock(nil) })
+}
+
 func TestEmptyGenerators(t *testing.T) {
 	testGenerators(t,
 		generatorTestCase{
@@ -3,7 +3,11 @@ package codenav
 import (
 	"context"
 
+	"github.com/sourcegraph/sourcegraph/cmd/searcher/protocol"
+	"github.com/sourcegraph/sourcegraph/internal/api"
 	"github.com/sourcegraph/sourcegraph/internal/codeintel/uploads/shared"
+	"github.com/sourcegraph/sourcegraph/internal/search/result"
+	"github.com/sourcegraph/sourcegraph/internal/types"
 	"github.com/sourcegraph/sourcegraph/lib/codeintel/precise"
 )
 
@@ -13,3 +17,11 @@ type UploadService interface {
 	GetDumpsByIDs(ctx context.Context, ids []int) (_ []shared.Dump, err error)
 	InferClosestUploads(ctx context.Context, repositoryID int, commit, path string, exactPath bool, indexer string) (_ []shared.Dump, err error)
 }
+
+type SymbolSearcher interface {
+	SearchSymbols(ctx context.Context, repo types.MinimalRepo, commit api.CommitID, query string, includePatterns []string, limit int) ([]*result.SymbolMatch, error)
+}
+
+type TextSearcher interface {
+	SearchText(ctx context.Context, repo types.MinimalRepo, commit api.CommitID, identifier string, includePattern string, limit int) ([]*protocol.FileMatch, error)
+}
@@ -23,6 +23,8 @@ func NewService(
 		lsifStore,
 		uploadSvc,
 		gitserver,
+		symbolSearcher{},
+		textSearcher{},
 	)
 }
 
@@ -11,9 +11,13 @@ import (
 	"sync"
 
 	scip "github.com/sourcegraph/scip/bindings/go/scip"
+	protocol "github.com/sourcegraph/sourcegraph/cmd/searcher/protocol"
+	api "github.com/sourcegraph/sourcegraph/internal/api"
 	lsifstore "github.com/sourcegraph/sourcegraph/internal/codeintel/codenav/internal/lsifstore"
 	shared "github.com/sourcegraph/sourcegraph/internal/codeintel/codenav/shared"
 	shared1 "github.com/sourcegraph/sourcegraph/internal/codeintel/uploads/shared"
+	result "github.com/sourcegraph/sourcegraph/internal/search/result"
+	types "github.com/sourcegraph/sourcegraph/internal/types"
 	precise "github.com/sourcegraph/sourcegraph/lib/codeintel/precise"
 )
 
@@ -2960,6 +2964,337 @@ func (c GitTreeTranslatorGetTargetCommitRangeFromSourceRangeFuncCall) Results()
 	return []interface{}{c.Result0, c.Result1, c.Result2, c.Result3}
 }
 
+// MockSymbolSearcher is a mock implementation of the SymbolSearcher
+// interface (from the package
+// github.com/sourcegraph/sourcegraph/internal/codeintel/codenav) used for
+// unit testing.
+type MockSymbolSearcher struct {
+	// SearchSymbolsFunc is an instance of a mock function object
+	// controlling the behavior of the method SearchSymbols.
+	SearchSymbolsFunc *SymbolSearcherSearchSymbolsFunc
+}
+
+// NewMockSymbolSearcher creates a new mock of the SymbolSearcher interface.
+// All methods return zero values for all results, unless overwritten.
+func NewMockSymbolSearcher() *MockSymbolSearcher {
+	return &MockSymbolSearcher{
+		SearchSymbolsFunc: &SymbolSearcherSearchSymbolsFunc{
+			defaultHook: func(context.Context, types.MinimalRepo, api.CommitID, string, []string, int) (r0 []*result.SymbolMatch, r1 error) {
+				return
+			},
+		},
+	}
+}
+
+// NewStrictMockSymbolSearcher creates a new mock of the SymbolSearcher
+// interface. All methods panic on invocation, unless overwritten.
+func NewStrictMockSymbolSearcher() *MockSymbolSearcher {
+	return &MockSymbolSearcher{
+		SearchSymbolsFunc: &SymbolSearcherSearchSymbolsFunc{
+			defaultHook: func(context.Context, types.MinimalRepo, api.CommitID, string, []string, int) ([]*result.SymbolMatch, error) {
+				panic("unexpected invocation of MockSymbolSearcher.SearchSymbols")
+			},
+		},
+	}
+}
+
+// NewMockSymbolSearcherFrom creates a new mock of the MockSymbolSearcher
+// interface. All methods delegate to the given implementation, unless
+// overwritten.
+func NewMockSymbolSearcherFrom(i SymbolSearcher) *MockSymbolSearcher {
+	return &MockSymbolSearcher{
+		SearchSymbolsFunc: &SymbolSearcherSearchSymbolsFunc{
+			defaultHook: i.SearchSymbols,
+		},
+	}
+}
+
+// SymbolSearcherSearchSymbolsFunc describes the behavior when the
+// SearchSymbols method of the parent MockSymbolSearcher instance is
+// invoked.
+type SymbolSearcherSearchSymbolsFunc struct {
+	defaultHook func(context.Context, types.MinimalRepo, api.CommitID, string, []string, int) ([]*result.SymbolMatch, error)
+	hooks       []func(context.Context, types.MinimalRepo, api.CommitID, string, []string, int) ([]*result.SymbolMatch, error)
+	history     []SymbolSearcherSearchSymbolsFuncCall
+	mutex       sync.Mutex
+}
+
+// SearchSymbols delegates to the next hook function in the queue and stores
+// the parameter and result values of this invocation.
+func (m *MockSymbolSearcher) SearchSymbols(v0 context.Context, v1 types.MinimalRepo, v2 api.CommitID, v3 string, v4 []string, v5 int) ([]*result.SymbolMatch, error) {
+	r0, r1 := m.SearchSymbolsFunc.nextHook()(v0, v1, v2, v3, v4, v5)
+	m.SearchSymbolsFunc.appendCall(SymbolSearcherSearchSymbolsFuncCall{v0, v1, v2, v3, v4, v5, r0, r1})
+	return r0, r1
+}
+
+// SetDefaultHook sets function that is called when the SearchSymbols method
+// of the parent MockSymbolSearcher instance is invoked and the hook queue
+// is empty.
+func (f *SymbolSearcherSearchSymbolsFunc) SetDefaultHook(hook func(context.Context, types.MinimalRepo, api.CommitID, string, []string, int) ([]*result.SymbolMatch, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// SearchSymbols method of the parent MockSymbolSearcher instance invokes
+// the hook at the front of the queue and discards it. After the queue is
+// empty, the default hook function is invoked for any future action.
+func (f *SymbolSearcherSearchSymbolsFunc) PushHook(hook func(context.Context, types.MinimalRepo, api.CommitID, string, []string, int) ([]*result.SymbolMatch, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultHook with a function that returns the
+// given values.
+func (f *SymbolSearcherSearchSymbolsFunc) SetDefaultReturn(r0 []*result.SymbolMatch, r1 error) {
+	f.SetDefaultHook(func(context.Context, types.MinimalRepo, api.CommitID, string, []string, int) ([]*result.SymbolMatch, error) {
+		return r0, r1
+	})
+}
+
+// PushReturn calls PushHook with a function that returns the given values.
+func (f *SymbolSearcherSearchSymbolsFunc) PushReturn(r0 []*result.SymbolMatch, r1 error) {
+	f.PushHook(func(context.Context, types.MinimalRepo, api.CommitID, string, []string, int) ([]*result.SymbolMatch, error) {
+		return r0, r1
+	})
+}
+
+func (f *SymbolSearcherSearchSymbolsFunc) nextHook() func(context.Context, types.MinimalRepo, api.CommitID, string, []string, int) ([]*result.SymbolMatch, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *SymbolSearcherSearchSymbolsFunc) appendCall(r0 SymbolSearcherSearchSymbolsFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of SymbolSearcherSearchSymbolsFuncCall objects
+// describing the invocations of this function.
+func (f *SymbolSearcherSearchSymbolsFunc) History() []SymbolSearcherSearchSymbolsFuncCall {
+	f.mutex.Lock()
+	history := make([]SymbolSearcherSearchSymbolsFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// SymbolSearcherSearchSymbolsFuncCall is an object that describes an
+// invocation of method SearchSymbols on an instance of MockSymbolSearcher.
+type SymbolSearcherSearchSymbolsFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 types.MinimalRepo
+	// Arg2 is the value of the 3rd argument passed to this method
+	// invocation.
+	Arg2 api.CommitID
+	// Arg3 is the value of the 4th argument passed to this method
+	// invocation.
+	Arg3 string
+	// Arg4 is the value of the 5th argument passed to this method
+	// invocation.
+	Arg4 []string
+	// Arg5 is the value of the 6th argument passed to this method
+	// invocation.
+	Arg5 int
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 []*result.SymbolMatch
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c SymbolSearcherSearchSymbolsFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1, c.Arg2, c.Arg3, c.Arg4, c.Arg5}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c SymbolSearcherSearchSymbolsFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1}
+}
+
+// MockTextSearcher is a mock implementation of the TextSearcher interface
+// (from the package
+// github.com/sourcegraph/sourcegraph/internal/codeintel/codenav) used for
+// unit testing.
+type MockTextSearcher struct {
+	// SearchTextFunc is an instance of a mock function object controlling
+	// the behavior of the method SearchText.
+	SearchTextFunc *TextSearcherSearchTextFunc
+}
+
+// NewMockTextSearcher creates a new mock of the TextSearcher interface. All
+// methods return zero values for all results, unless overwritten.
+func NewMockTextSearcher() *MockTextSearcher {
+	return &MockTextSearcher{
+		SearchTextFunc: &TextSearcherSearchTextFunc{
+			defaultHook: func(context.Context, types.MinimalRepo, api.CommitID, string, string, int) (r0 []*protocol.FileMatch, r1 error) {
+				return
+			},
+		},
+	}
+}
+
+// NewStrictMockTextSearcher creates a new mock of the TextSearcher
+// interface. All methods panic on invocation, unless overwritten.
+func NewStrictMockTextSearcher() *MockTextSearcher {
+	return &MockTextSearcher{
+		SearchTextFunc: &TextSearcherSearchTextFunc{
+			defaultHook: func(context.Context, types.MinimalRepo, api.CommitID, string, string, int) ([]*protocol.FileMatch, error) {
+				panic("unexpected invocation of MockTextSearcher.SearchText")
+			},
+		},
+	}
+}
+
+// NewMockTextSearcherFrom creates a new mock of the MockTextSearcher
+// interface. All methods delegate to the given implementation, unless
+// overwritten.
+func NewMockTextSearcherFrom(i TextSearcher) *MockTextSearcher {
+	return &MockTextSearcher{
+		SearchTextFunc: &TextSearcherSearchTextFunc{
+			defaultHook: i.SearchText,
+		},
+	}
+}
+
+// TextSearcherSearchTextFunc describes the behavior when the SearchText
+// method of the parent MockTextSearcher instance is invoked.
+type TextSearcherSearchTextFunc struct {
+	defaultHook func(context.Context, types.MinimalRepo, api.CommitID, string, string, int) ([]*protocol.FileMatch, error)
+	hooks       []func(context.Context, types.MinimalRepo, api.CommitID, string, string, int) ([]*protocol.FileMatch, error)
+	history     []TextSearcherSearchTextFuncCall
+	mutex       sync.Mutex
+}
+
+// SearchText delegates to the next hook function in the queue and stores
+// the parameter and result values of this invocation.
+func (m *MockTextSearcher) SearchText(v0 context.Context, v1 types.MinimalRepo, v2 api.CommitID, v3 string, v4 string, v5 int) ([]*protocol.FileMatch, error) {
+	r0, r1 := m.SearchTextFunc.nextHook()(v0, v1, v2, v3, v4, v5)
+	m.SearchTextFunc.appendCall(TextSearcherSearchTextFuncCall{v0, v1, v2, v3, v4, v5, r0, r1})
+	return r0, r1
+}
+
+// SetDefaultHook sets function that is called when the SearchText method of
+// the parent MockTextSearcher instance is invoked and the hook queue is
+// empty.
+func (f *TextSearcherSearchTextFunc) SetDefaultHook(hook func(context.Context, types.MinimalRepo, api.CommitID, string, string, int) ([]*protocol.FileMatch, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// SearchText method of the parent MockTextSearcher instance invokes the
+// hook at the front of the queue and discards it. After the queue is empty,
+// the default hook function is invoked for any future action.
+func (f *TextSearcherSearchTextFunc) PushHook(hook func(context.Context, types.MinimalRepo, api.CommitID, string, string, int) ([]*protocol.FileMatch, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultHook with a function that returns the
+// given values.
+func (f *TextSearcherSearchTextFunc) SetDefaultReturn(r0 []*protocol.FileMatch, r1 error) {
+	f.SetDefaultHook(func(context.Context, types.MinimalRepo, api.CommitID, string, string, int) ([]*protocol.FileMatch, error) {
+		return r0, r1
+	})
+}
+
+// PushReturn calls PushHook with a function that returns the given values.
+func (f *TextSearcherSearchTextFunc) PushReturn(r0 []*protocol.FileMatch, r1 error) {
+	f.PushHook(func(context.Context, types.MinimalRepo, api.CommitID, string, string, int) ([]*protocol.FileMatch, error) {
+		return r0, r1
+	})
+}
+
+func (f *TextSearcherSearchTextFunc) nextHook() func(context.Context, types.MinimalRepo, api.CommitID, string, string, int) ([]*protocol.FileMatch, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *TextSearcherSearchTextFunc) appendCall(r0 TextSearcherSearchTextFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of TextSearcherSearchTextFuncCall objects
+// describing the invocations of this function.
+func (f *TextSearcherSearchTextFunc) History() []TextSearcherSearchTextFuncCall {
+	f.mutex.Lock()
+	history := make([]TextSearcherSearchTextFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// TextSearcherSearchTextFuncCall is an object that describes an invocation
+// of method SearchText on an instance of MockTextSearcher.
+type TextSearcherSearchTextFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 types.MinimalRepo
+	// Arg2 is the value of the 3rd argument passed to this method
+	// invocation.
+	Arg2 api.CommitID
+	// Arg3 is the value of the 4th argument passed to this method
+	// invocation.
+	Arg3 string
+	// Arg4 is the value of the 5th argument passed to this method
+	// invocation.
+	Arg4 string
+	// Arg5 is the value of the 6th argument passed to this method
+	// invocation.
+	Arg5 int
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 []*protocol.FileMatch
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c TextSearcherSearchTextFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1, c.Arg2, c.Arg3, c.Arg4, c.Arg5}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c TextSearcherSearchTextFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1}
+}
+
 // MockUploadService is a mock implementation of the UploadService interface
 // (from the package
 // github.com/sourcegraph/sourcegraph/internal/codeintel/codenav) used for
@@ -23,6 +23,14 @@ type operations struct {
 	getClosestDumpsForBlob *observation.Operation
 	snapshotForDocument    *observation.Operation
 	visibleUploadsForPath  *observation.Operation
+	getIncomingCalls       *observation.Operation
+	getOutgoingCalls       *observation.Operation
+	getTypeHierarchy       *observation.Operation
+	getRenameEdits         *observation.Operation
+	generateRenameDiffs    *observation.Operation
+
+	getSearchBasedDefinitions *observation.Operation
+	getSearchBasedReferences  *observation.Operation
 }
 
 var m = new(metrics.SingletonREDMetrics)
@@ -57,6 +65,14 @@ func newOperations(observationCtx *observation.Context) *operations {
 		getClosestDumpsForBlob: op("GetClosestDumpsForBlob"),
 		snapshotForDocument:    op("SnapshotForDocument"),
 		visibleUploadsForPath:  op("VisibleUploadsForPath"),
+		getIncomingCalls:       op("getIncomingCalls"),
+		getOutgoingCalls:       op("getOutgoingCalls"),
+		getTypeHierarchy:       op("getTypeHierarchy"),
+		getRenameEdits:         op("getRenameEdits"),
+		generateRenameDiffs:    op("generateRenameDiffs"),
+
+		getSearchBasedDefinitions: op("getSearchBasedDefinitions"),
+		getSearchBasedReferences:  op("getSearchBasedReferences"),
 	}
 }
 
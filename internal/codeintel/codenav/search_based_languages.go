@@ -0,0 +1,251 @@
+package codenav
+
+import (
+	"path"
+	"regexp"
+	"strings"
+)
+
+// searchBasedLanguage describes the language-aware scoping rules used to rank the results of
+// search-based code navigation for a family of languages sharing an import model.
+type searchBasedLanguage struct {
+	name       string
+	extensions []string
+
+	// scopes returns the (possibly partial) paths of the packages, modules, and files which are
+	// visible from the given file without qualification or via its import statements. Scopes are
+	// matched against the paths of candidate files with matchesScope.
+	scopes func(filePath, contents string) []string
+
+	// qualifier returns the name with which identifiers defined in the given file are qualified
+	// when referenced from a different directory (e.g., the package name in Go). An empty string
+	// indicates that references are not qualified.
+	qualifier func(definitionPath string) string
+}
+
+var searchBasedLanguages = []searchBasedLanguage{
+	{
+		name:       "go",
+		extensions: []string{".go"},
+		scopes:     goScopes,
+		qualifier: func(definitionPath string) string {
+			return path.Base(path.Dir(definitionPath))
+		},
+	},
+	{
+		name:       "typescript",
+		extensions: []string{".ts", ".tsx", ".js", ".jsx", ".mjs", ".cjs"},
+		scopes:     typeScriptScopes,
+	},
+	{
+		name:       "python",
+		extensions: []string{".py"},
+		scopes:     pythonScopes,
+	},
+	{
+		name:       "java",
+		extensions: []string{".java", ".kt", ".kts", ".scala"},
+		scopes:     javaScopes,
+	},
+}
+
+// searchBasedLanguageForPath returns the language of the given path, or nil if search-based
+// code navigation has no scoping rules for the file's extension.
+func searchBasedLanguageForPath(filePath string) *searchBasedLanguage {
+	ext := path.Ext(filePath)
+	for i, language := range searchBasedLanguages {
+		for _, extension := range language.extensions {
+			if ext == extension {
+				return &searchBasedLanguages[i]
+			}
+		}
+	}
+
+	return nil
+}
+
+// includePatterns returns the path patterns (as regular expressions) matching files of this language.
+func (l *searchBasedLanguage) includePatterns() []string {
+	patterns := make([]string, 0, len(l.extensions))
+	for _, extension := range l.extensions {
+		patterns = append(patterns, regexp.QuoteMeta(extension)+"$")
+	}
+
+	return patterns
+}
+
+var (
+	goImportBlockPattern   = regexp.MustCompile(`(?s)\bimport\s*\((.*?)\)`)
+	goSingleImportPattern  = regexp.MustCompile(`(?m)^import\s+(?:[\w.]+\s+)?"([^"]+)"`)
+	goImportPathPattern    = regexp.MustCompile(`"([^"]+)"`)
+	tsImportPattern        = regexp.MustCompile(`(?:\bfrom|\bimport|\brequire\()\s*['"]([^'"]+)['"]`)
+	pythonFromPattern      = regexp.MustCompile(`(?m)^\s*from\s+(\.*)([\w.]*)\s+import\b`)
+	pythonImportPattern    = regexp.MustCompile(`(?m)^\s*import\s+([\w.]+(?:\s*,\s*[\w.]+)*)`)
+	javaPackagePattern     = regexp.MustCompile(`(?m)^\s*package\s+([\w.]+)`)
+	javaImportPattern      = regexp.MustCompile(`(?m)^\s*import\s+(?:static\s+)?([\w.]+)`)
+	pythonImportSeparators = regexp.MustCompile(`\s*,\s*`)
+)
+
+// goScopes returns the import paths of the given Go file. Files within the same directory
+// belong to the same package, which is handled separately by the caller.
+func goScopes(_, contents string) []string {
+	var scopes []string
+	for _, match := range goSingleImportPattern.FindAllStringSubmatch(contents, -1) {
+		scopes = append(scopes, match[1])
+	}
+	for _, block := range goImportBlockPattern.FindAllStringSubmatch(contents, -1) {
+		for _, match := range goImportPathPattern.FindAllStringSubmatch(block[1], -1) {
+			scopes = append(scopes, match[1])
+		}
+	}
+
+	return scopes
+}
+
+// typeScriptScopes returns the modules imported by the given TypeScript or JavaScript file.
+// Relative module specifiers are resolved against the directory of the file.
+func typeScriptScopes(filePath, contents string) []string {
+	var scopes []string
+	for _, match := range tsImportPattern.FindAllStringSubmatch(contents, -1) {
+		specifier := match[1]
+		if strings.HasPrefix(specifier, ".") {
+			specifier = path.Join(path.Dir(filePath), specifier)
+		}
+		scopes = append(scopes, specifier)
+	}
+
+	return scopes
+}
+
+// pythonScopes returns the modules imported by the given Python file as paths. Relative
+// imports are resolved against the directory of the file.
+func pythonScopes(filePath, contents string) []string {
+	var scopes []string
+	for _, match := range pythonFromPattern.FindAllStringSubmatch(contents, -1) {
+		module := strings.ReplaceAll(match[2], ".", "/")
+
+		if dots := len(match[1]); dots > 0 {
+			dir := path.Dir(filePath)
+			for i := 1; i < dots; i++ {
+				dir = path.Dir(dir)
+			}
+			module = path.Join(dir, module)
+		}
+		scopes = append(scopes, module)
+	}
+	for _, match := range pythonImportPattern.FindAllStringSubmatch(contents, -1) {
+		for _, module := range pythonImportSeparators.Split(match[1], -1) {
+			scopes = append(scopes, strings.ReplaceAll(module, ".", "/"))
+		}
+	}
+
+	return scopes
+}
+
+// javaScopes returns the package declared by the given JVM-language file along with the
+// packages and classes it imports. Wildcard imports resolve to the imported package.
+func javaScopes(_, contents string) []string {
+	var scopes []string
+	if match := javaPackagePattern.FindStringSubmatch(contents); match != nil {
+		scopes = append(scopes, strings.ReplaceAll(match[1], ".", "/"))
+	}
+	for _, match := range javaImportPattern.FindAllStringSubmatch(contents, -1) {
+		scopes = append(scopes, strings.ReplaceAll(strings.TrimSuffix(match[1], "."), ".", "/"))
+	}
+
+	return scopes
+}
+
+// matchesScope returns true if the file at the given path lies within one of the given scopes.
+// Scopes may be repository-relative paths or paths with a prefix unknown to the repository (such
+// as Go import paths or Java packages rooted in a source directory), so a scope matches if either
+// it or the file's directory (or extension-less path) is a path suffix of the other.
+func matchesScope(filePath string, scopes []string) bool {
+	candidates := []string{path.Dir(filePath), strings.TrimSuffix(filePath, path.Ext(filePath))}
+
+	for _, scope := range scopes {
+		scope = strings.Trim(scope, "/")
+		if scope == "" || scope == "." {
+			continue
+		}
+
+		for _, candidate := range candidates {
+			if candidate == "." {
+				continue
+			}
+			if candidate == scope || strings.HasSuffix(scope, "/"+candidate) || strings.HasSuffix(candidate, "/"+scope) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// identifierAt returns the identifier enclosing the given zero-based position in the given file
+// contents, where the character offset is given in UTF-16 code units. An empty string is returned
+// if the position does not fall on an identifier.
+func identifierAt(contents string, line, character int) string {
+	lines := strings.Split(contents, "\n")
+	if line < 0 || line >= len(lines) {
+		return ""
+	}
+
+	text := lines[line]
+	character, ok := utf16Offset(text, character)
+	if !ok {
+		return ""
+	}
+
+	start := character
+	for start > 0 && isIdentifierByte(text[start-1]) {
+		start--
+	}
+	end := character
+	for end < len(text) && isIdentifierByte(text[end]) {
+		end++
+	}
+	if start == end || (text[start] >= '0' && text[start] <= '9') {
+		return ""
+	}
+
+	return text[start:end]
+}
+
+// identifierOccurrences returns the offsets of the whole-word occurrences of the given identifier in the given text.
+func identifierOccurrences(text, identifier string) []int {
+	var offsets []int
+	for offset := 0; offset+len(identifier) <= len(text); {
+		index := strings.Index(text[offset:], identifier)
+		if index < 0 {
+			break
+		}
+
+		start, end := offset+index, offset+index+len(identifier)
+		if (start == 0 || !isIdentifierByte(text[start-1])) && (end == len(text) || !isIdentifierByte(text[end])) {
+			offsets = append(offsets, start)
+		}
+		offset = start + 1
+	}
+
+	return offsets
+}
+
+// utf16Length returns the number of UTF-16 code units encoding the given text.
+func utf16Length(text string) int {
+	length := 0
+	for _, r := range text {
+		if r > 0xFFFF {
+			// Encoded as a surrogate pair
+			length += 2
+		} else {
+			length++
+		}
+	}
+
+	return length
+}
+
+func isIdentifierByte(b byte) bool {
+	return b == '_' || b == '$' || (b >= 'a' && b <= 'z') || (b >= 'A' && b <= 'Z') || (b >= '0' && b <= '9')
+}
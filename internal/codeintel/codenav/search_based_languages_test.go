@@ -0,0 +1,145 @@
+package codenav
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestSearchBasedLanguageScopes(t *testing.T) {
+	testCases := []struct {
+		name     string
+		path     string
+		contents string
+		expected []string
+	}{
+		{
+			name: "go",
+			path: "cmd/server/main.go",
+			contents: `package main
+
+import "fmt"
+
+import (
+	"github.com/example/r/internal/store"
+	log "github.com/example/r/internal/logging"
+)
+`,
+			expected: []string{"fmt", "github.com/example/r/internal/store", "github.com/example/r/internal/logging"},
+		},
+		{
+			name: "typescript",
+			path: "client/src/app.tsx",
+			contents: `import React from 'react'
+import { render } from "./render"
+import type { Config } from '../../shared/config'
+const util = require('./util')
+`,
+			expected: []string{"react", "client/src/render", "shared/config", "client/src/util"},
+		},
+		{
+			name: "python",
+			path: "app/api/views.py",
+			contents: `import os, app.models
+from app.db import session
+from . import forms
+from ..core.utils import slugify
+`,
+			expected: []string{"app/db", "app/api", "app/core/utils", "os", "app/models"},
+		},
+		{
+			name: "java",
+			path: "src/main/java/com/example/app/Main.java",
+			contents: `package com.example.app;
+
+import com.example.util.Strings;
+import static com.example.util.Math.max;
+import com.example.model.*;
+`,
+			expected: []string{"com/example/app", "com/example/util/Strings", "com/example/util/Math/max", "com/example/model"},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			language := searchBasedLanguageForPath(testCase.path)
+			if language == nil {
+				t.Fatalf("expected a language for %q", testCase.path)
+			}
+
+			if diff := cmp.Diff(testCase.expected, language.scopes(testCase.path, testCase.contents)); diff != "" {
+				t.Errorf("unexpected scopes (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestMatchesScope(t *testing.T) {
+	testCases := []struct {
+		path     string
+		scopes   []string
+		expected bool
+	}{
+		{path: "internal/store/store.go", scopes: []string{"github.com/example/r/internal/store"}, expected: true},
+		{path: "internal/store/store.go", scopes: []string{"github.com/example/r/internal/storage"}, expected: false},
+		{path: "client/src/render.tsx", scopes: []string{"client/src/render"}, expected: true},
+		{path: "src/main/java/com/example/util/Strings.java", scopes: []string{"com/example/util/Strings"}, expected: true},
+		{path: "src/main/java/com/example/model/User.java", scopes: []string{"com/example/model/"}, expected: true},
+		{path: "main.go", scopes: []string{"fmt"}, expected: false},
+		{path: "app/models.py", scopes: []string{""}, expected: false},
+	}
+
+	for _, testCase := range testCases {
+		if matches := matchesScope(testCase.path, testCase.scopes); matches != testCase.expected {
+			t.Errorf("unexpected result for %q in %v. want=%v have=%v", testCase.path, testCase.scopes, testCase.expected, matches)
+		}
+	}
+}
+
+func TestIdentifierAt(t *testing.T) {
+	contents := "func f() { b.Helper(x1) }\n\tvar $el = 42"
+
+	testCases := []struct {
+		line      int
+		character int
+		expected  string
+	}{
+		{line: 0, character: 13, expected: "Helper"},
+		{line: 0, character: 19, expected: "Helper"},
+		{line: 0, character: 11, expected: "b"},
+		{line: 0, character: 20, expected: "x1"},
+		{line: 0, character: 9, expected: ""},
+		{line: 1, character: 6, expected: "$el"},
+		{line: 1, character: 12, expected: ""},
+		{line: 2, character: 0, expected: ""},
+	}
+
+	for _, testCase := range testCases {
+		if identifier := identifierAt(contents, testCase.line, testCase.character); identifier != testCase.expected {
+			t.Errorf("unexpected identifier at %d:%d. want=%q have=%q", testCase.line, testCase.character, testCase.expected, identifier)
+		}
+	}
+}
+
+func TestIdentifierAtUTF16(t *testing.T) {
+	contents := "s := \"\U0001F600\"; Helper()"
+
+	if identifier := identifierAt(contents, 0, 11); identifier != "Helper" {
+		t.Errorf("unexpected identifier. want=%q have=%q", "Helper", identifier)
+	}
+	if identifier := identifierAt(contents, 0, 20); identifier != "" {
+		t.Errorf("unexpected identifier past the end of the line. want=%q have=%q", "", identifier)
+	}
+}
+
+func TestUTF16Length(t *testing.T) {
+	if length := utf16Length("var \u00e9 = \"\U0001F600\"; "); length != 14 {
+		t.Errorf("unexpected length. want=%d have=%d", 14, length)
+	}
+}
+
+func TestIdentifierOccurrences(t *testing.T) {
+	if diff := cmp.Diff([]int{0, 7, 30}, identifierOccurrences("Helper Helper HelperX xHelper Helper", "Helper")); diff != "" {
+		t.Errorf("unexpected occurrences (-want +got):\n%s", diff)
+	}
+}
@@ -25,12 +25,14 @@ import (
 )
 
 type Service struct {
-	repoStore  database.RepoStore
-	lsifstore  lsifstore.LsifStore
-	gitserver  gitserver.Client
-	uploadSvc  UploadService
-	operations *operations
-	logger     log.Logger
+	repoStore      database.RepoStore
+	lsifstore      lsifstore.LsifStore
+	gitserver      gitserver.Client
+	uploadSvc      UploadService
+	symbolSearcher SymbolSearcher
+	textSearcher   TextSearcher
+	operations     *operations
+	logger         log.Logger
 }
 
 func newService(
@@ -39,14 +41,18 @@ func newService(
 	lsifstore lsifstore.LsifStore,
 	uploadSvc UploadService,
 	gitserver gitserver.Client,
+	symbolSearcher SymbolSearcher,
+	textSearcher TextSearcher,
 ) *Service {
 	return &Service{
-		repoStore:  repoStore,
-		lsifstore:  lsifstore,
-		gitserver:  gitserver,
-		uploadSvc:  uploadSvc,
-		operations: newOperations(observationCtx),
-		logger:     log.Scoped("codenav", ""),
+		repoStore:      repoStore,
+		lsifstore:      lsifstore,
+		gitserver:      gitserver,
+		uploadSvc:      uploadSvc,
+		symbolSearcher: symbolSearcher,
+		textSearcher:   textSearcher,
+		operations:     newOperations(observationCtx),
+		logger:         log.Scoped("codenav", ""),
 	}
 }
 
@@ -0,0 +1,365 @@
+package codenav
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"github.com/sourcegraph/scip/bindings/go/scip"
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/sourcegraph/sourcegraph/internal/codeintel/codenav/internal/lsifstore"
+	"github.com/sourcegraph/sourcegraph/internal/codeintel/codenav/shared"
+	uploadsshared "github.com/sourcegraph/sourcegraph/internal/codeintel/uploads/shared"
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// maximumCallHierarchyReferences is the maximum number of references of a symbol that are
+// grouped into incoming calls. References beyond this limit are not considered.
+const maximumCallHierarchyReferences = 1000
+
+// GetIncomingCalls returns the functions and methods which reference the symbol at the given
+// position. References are gathered the same way as GetReferences, so they span all uploads
+// which refer to the symbol via its monikers. Each reference is attributed to the innermost
+// callable definition whose enclosing range contains it; references outside of any callable
+// definition (e.g. in top-level variable initializers) are not returned.
+func (s *Service) GetIncomingCalls(ctx context.Context, args PositionalRequestArgs, requestState RequestState) (_ []CallHierarchyItem, err error) {
+	ctx, trace, endObservation := observeResolver(ctx, &err, s.operations.getIncomingCalls, serviceObserverThreshold, observation.Args{Attrs: []attribute.KeyValue{
+		attribute.Int("repositoryID", args.RepositoryID),
+		attribute.String("commit", args.Commit),
+		attribute.String("path", args.Path),
+		attribute.Int("line", args.Line),
+		attribute.Int("character", args.Character),
+		attribute.Int("limit", args.Limit),
+	}})
+	defer endObservation()
+
+	referenceArgs := args
+	referenceArgs.Limit = maximumCallHierarchyReferences
+	references, _, err := s.GetReferences(ctx, referenceArgs, requestState, Cursor{})
+	if err != nil {
+		return nil, errors.Wrap(err, "GetReferences")
+	}
+	trace.AddEvent("GetReferences", attribute.Int("numReferences", len(references)))
+
+	documents := newDocumentCache(s.lsifstore)
+	items := newCallHierarchyItems()
+
+	for _, reference := range references {
+		path := strings.TrimPrefix(reference.Path, reference.Dump.Root)
+		document, err := documents.get(ctx, reference.Dump.ID, path)
+		if err != nil {
+			return nil, err
+		}
+		if document == nil {
+			continue
+		}
+
+		rng, ok, err := s.getIndexedRange(ctx, requestState, reference)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			continue
+		}
+
+		caller := findEnclosingCallable(document, rng)
+		if caller == nil {
+			continue
+		}
+
+		item, ok := items.get(reference.Dump, path, caller.Symbol)
+		if !ok {
+			if items.len() >= args.Limit {
+				continue
+			}
+
+			definition, _, err := s.getUploadLocation(ctx, args.RequestArgs, requestState, reference.Dump, shared.Location{
+				DumpID: reference.Dump.ID,
+				Path:   path,
+				Range:  translateSCIPRange(caller.Range),
+			})
+			if err != nil {
+				return nil, err
+			}
+
+			item = items.add(reference.Dump, path, caller.Symbol)
+			item.Definitions = []shared.UploadLocation{definition}
+		}
+		item.CallSites = append(item.CallSites, reference)
+	}
+
+	return items.values(), nil
+}
+
+// GetOutgoingCalls returns the functions and methods which are referenced within the body of
+// the callable definition at the given position. Definitions of callees are resolved within
+// the same document when possible, and otherwise across uploads via their monikers.
+func (s *Service) GetOutgoingCalls(ctx context.Context, args PositionalRequestArgs, requestState RequestState) (_ []CallHierarchyItem, err error) {
+	ctx, trace, endObservation := observeResolver(ctx, &err, s.operations.getOutgoingCalls, serviceObserverThreshold, observation.Args{Attrs: []attribute.KeyValue{
+		attribute.Int("repositoryID", args.RepositoryID),
+		attribute.String("commit", args.Commit),
+		attribute.String("path", args.Path),
+		attribute.Int("line", args.Line),
+		attribute.Int("character", args.Character),
+		attribute.Int("limit", args.Limit),
+	}})
+	defer endObservation()
+
+	visibleUploads, err := s.getVisibleUploads(ctx, args.Line, args.Character, requestState)
+	if err != nil {
+		return nil, err
+	}
+	trace.AddEvent("VisibleUploads", attribute.Int("numUploads", len(visibleUploads)))
+
+	items := newCallHierarchyItems()
+	localDefinitions := map[*CallHierarchyItem]shared.UploadLocation{}
+
+	for _, upload := range visibleUploads {
+		document, err := s.lsifstore.SCIPDocument(ctx, upload.Upload.ID, upload.TargetPathWithoutRoot)
+		if err != nil {
+			return nil, err
+		}
+		if document == nil {
+			continue
+		}
+
+		caller := findCallableDefinition(document, upload.TargetPosition)
+		if caller == nil {
+			continue
+		}
+		body := translateSCIPRange(caller.EnclosingRange)
+
+		for _, occurrence := range document.Occurrences {
+			if scip.SymbolRole_Definition.Matches(occurrence) || !isCallableSymbol(occurrence.Symbol) {
+				continue
+			}
+			rng := translateSCIPRange(occurrence.Range)
+			if !rangeContainsRange(body, rng) {
+				continue
+			}
+
+			item, ok := items.get(upload.Upload, upload.TargetPathWithoutRoot, occurrence.Symbol)
+			if !ok {
+				if items.len() >= args.Limit {
+					continue
+				}
+				item = items.add(upload.Upload, upload.TargetPathWithoutRoot, occurrence.Symbol)
+
+				if definition := findDefinition(document, occurrence.Symbol); definition != nil {
+					location, _, err := s.getUploadLocation(ctx, args.RequestArgs, requestState, upload.Upload, shared.Location{
+						DumpID: upload.Upload.ID,
+						Path:   upload.TargetPathWithoutRoot,
+						Range:  translateSCIPRange(definition.Range),
+					})
+					if err != nil {
+						return nil, err
+					}
+					localDefinitions[item] = location
+				}
+			}
+
+			callSite, _, err := s.getUploadLocation(ctx, args.RequestArgs, requestState, upload.Upload, shared.Location{
+				DumpID: upload.Upload.ID,
+				Path:   upload.TargetPathWithoutRoot,
+				Range:  rng,
+			})
+			if err != nil {
+				return nil, err
+			}
+			item.CallSites = append(item.CallSites, callSite)
+		}
+	}
+
+	for _, item := range items.items {
+		if definition, ok := localDefinitions[item]; ok {
+			item.Definitions = []shared.UploadLocation{definition}
+			continue
+		}
+		if scip.IsLocalSymbol(item.Symbol) {
+			continue
+		}
+
+		definitionArgs := args.RequestArgs
+		definitionArgs.RawCursor = ""
+		definitions, err := s.GetDefinitionsBySymbolNames(ctx, definitionArgs, requestState, []string{item.Symbol})
+		if err != nil {
+			return nil, errors.Wrap(err, "GetDefinitionsBySymbolNames")
+		}
+		item.Definitions = definitions
+	}
+
+	return items.values(), nil
+}
+
+// getIndexedRange translates the range of the given location, which is relative to the target
+// commit, back into the commit of its upload. A false-valued flag is returned if the range
+// cannot be translated.
+func (s *Service) getIndexedRange(ctx context.Context, requestState RequestState, location shared.UploadLocation) (shared.Range, bool, error) {
+	if location.TargetCommit == location.Dump.Commit {
+		return location.TargetRange, true, nil
+	}
+
+	_, rng, ok, err := requestState.GitTreeTranslator.GetTargetCommitRangeFromSourceRange(ctx, location.Dump.Commit, location.Path, location.TargetRange, false)
+	if err != nil {
+		return shared.Range{}, false, errors.Wrap(err, "gitTreeTranslator.GetTargetCommitRangeFromSourceRange")
+	}
+
+	return rng, ok, nil
+}
+
+// callHierarchyItems collects call hierarchy items in the order they are first seen.
+type callHierarchyItems struct {
+	items []*CallHierarchyItem
+	byKey map[string]*CallHierarchyItem
+}
+
+func newCallHierarchyItems() *callHierarchyItems {
+	return &callHierarchyItems{byKey: map[string]*CallHierarchyItem{}}
+}
+
+func (c *callHierarchyItems) get(upload uploadsshared.Dump, path, symbol string) (*CallHierarchyItem, bool) {
+	item, ok := c.byKey[callHierarchyItemKey(upload, path, symbol)]
+	return item, ok
+}
+
+func (c *callHierarchyItems) add(upload uploadsshared.Dump, path, symbol string) *CallHierarchyItem {
+	item := &CallHierarchyItem{Symbol: symbol}
+	c.byKey[callHierarchyItemKey(upload, path, symbol)] = item
+	c.items = append(c.items, item)
+	return item
+}
+
+func (c *callHierarchyItems) len() int {
+	return len(c.items)
+}
+
+func (c *callHierarchyItems) values() []CallHierarchyItem {
+	values := make([]CallHierarchyItem, 0, len(c.items))
+	for _, item := range c.items {
+		values = append(values, *item)
+	}
+	return values
+}
+
+// callHierarchyItemKey identifies a callable symbol. Global symbols are identical across
+// uploads, whereas local symbols are only unique within their document.
+func callHierarchyItemKey(upload uploadsshared.Dump, path, symbol string) string {
+	if scip.IsLocalSymbol(symbol) {
+		return strings.Join([]string{strconv.Itoa(upload.ID), path, symbol}, ":")
+	}
+	return symbol
+}
+
+// documentCache fetches each SCIP document of an upload at most once.
+type documentCache struct {
+	lsifstore lsifstore.LsifStore
+	documents map[documentKey]*scip.Document
+}
+
+type documentKey struct {
+	uploadID int
+	path     string
+}
+
+func newDocumentCache(store lsifstore.LsifStore) *documentCache {
+	return &documentCache{lsifstore: store, documents: map[documentKey]*scip.Document{}}
+}
+
+func (c *documentCache) get(ctx context.Context, uploadID int, path string) (*scip.Document, error) {
+	key := documentKey{uploadID: uploadID, path: path}
+	if document, ok := c.documents[key]; ok {
+		return document, nil
+	}
+
+	document, err := c.lsifstore.SCIPDocument(ctx, uploadID, path)
+	if err != nil {
+		return nil, err
+	}
+	c.documents[key] = document
+	return document, nil
+}
+
+// isCallableSymbol returns true if the given symbol names a function or a method. Local
+// symbols carry no descriptors and are never considered callable.
+func isCallableSymbol(symbol string) bool {
+	if scip.IsLocalSymbol(symbol) {
+		return false
+	}
+
+	parsed, err := scip.ParseSymbol(symbol)
+	if err != nil || len(parsed.Descriptors) == 0 {
+		return false
+	}
+
+	return parsed.Descriptors[len(parsed.Descriptors)-1].Suffix == scip.Descriptor_Method
+}
+
+// findEnclosingCallable returns the definition occurrence of the innermost callable symbol
+// whose enclosing range contains the given range. Nil is returned if the range belongs to a
+// definition itself or is not contained in a callable definition.
+func findEnclosingCallable(document *scip.Document, rng shared.Range) *scip.Occurrence {
+	var innermost *scip.Occurrence
+	var innermostRange shared.Range
+
+	for _, occurrence := range document.Occurrences {
+		if !scip.SymbolRole_Definition.Matches(occurrence) {
+			continue
+		}
+		if translateSCIPRange(occurrence.Range) == rng {
+			// The location is a definition, not a call
+			return nil
+		}
+		if len(occurrence.EnclosingRange) == 0 || !isCallableSymbol(occurrence.Symbol) {
+			continue
+		}
+
+		enclosingRange := translateSCIPRange(occurrence.EnclosingRange)
+		if !rangeContainsRange(enclosingRange, rng) {
+			continue
+		}
+		if innermost == nil || rangeContainsRange(innermostRange, enclosingRange) {
+			innermost, innermostRange = occurrence, enclosingRange
+		}
+	}
+
+	return innermost
+}
+
+// findCallableDefinition returns the definition occurrence of the callable symbol at the given
+// position, if it has an enclosing range.
+func findCallableDefinition(document *scip.Document, position shared.Position) *scip.Occurrence {
+	for _, occurrence := range scip.FindOccurrences(document.Occurrences, int32(position.Line), int32(position.Character)) {
+		if scip.SymbolRole_Definition.Matches(occurrence) && len(occurrence.EnclosingRange) > 0 && isCallableSymbol(occurrence.Symbol) {
+			return occurrence
+		}
+	}
+
+	return nil
+}
+
+// findDefinition returns the definition occurrence of the given symbol within the document.
+func findDefinition(document *scip.Document, symbol string) *scip.Occurrence {
+	for _, occurrence := range document.Occurrences {
+		if occurrence.Symbol == symbol && scip.SymbolRole_Definition.Matches(occurrence) {
+			return occurrence
+		}
+	}
+
+	return nil
+}
+
+func translateSCIPRange(r []int32) shared.Range {
+	rng := scip.NewRange(r)
+
+	return shared.Range{
+		Start: shared.Position{Line: int(rng.Start.Line), Character: int(rng.Start.Character)},
+		End:   shared.Position{Line: int(rng.End.Line), Character: int(rng.End.Character)},
+	}
+}
+
+// rangeContainsRange returns true if the outer range encloses the inner range.
+func rangeContainsRange(outer, inner shared.Range) bool {
+	return rangeContainsPosition(outer, inner.Start) && rangeContainsPosition(outer, inner.End)
+}
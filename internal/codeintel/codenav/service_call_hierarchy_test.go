@@ -0,0 +1,180 @@
+package codenav
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/sourcegraph/scip/bindings/go/scip"
+
+	"github.com/sourcegraph/sourcegraph/internal/codeintel/codenav/shared"
+	uploadsshared "github.com/sourcegraph/sourcegraph/internal/codeintel/uploads/shared"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver"
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+	sgtypes "github.com/sourcegraph/sourcegraph/internal/types"
+)
+
+const (
+	callerSymbol = "scip-go gomod github.com/example/m v1 `github.com/example/m`/caller()."
+	calleeSymbol = "scip-go gomod github.com/example/m v1 `github.com/example/m`/callee()."
+	typeSymbol   = "scip-go gomod github.com/example/m v1 `github.com/example/m`/T#"
+)
+
+// callHierarchyDocument models the following document:
+//
+//	func callee() {}
+//
+//	func caller() {
+//		callee()
+//		callee()
+//	}
+//
+//	type T struct {
+//		f = callee
+//	}
+var callHierarchyDocument = &scip.Document{
+	RelativePath: "a.go",
+	Occurrences: []*scip.Occurrence{
+		{Range: []int32{0, 5, 11}, Symbol: calleeSymbol, SymbolRoles: int32(scip.SymbolRole_Definition), EnclosingRange: []int32{0, 0, 16}},
+		{Range: []int32{2, 5, 11}, Symbol: callerSymbol, SymbolRoles: int32(scip.SymbolRole_Definition), EnclosingRange: []int32{2, 0, 5, 1}},
+		{Range: []int32{3, 1, 7}, Symbol: calleeSymbol},
+		{Range: []int32{4, 1, 7}, Symbol: calleeSymbol},
+		{Range: []int32{7, 5, 6}, Symbol: typeSymbol, SymbolRoles: int32(scip.SymbolRole_Definition), EnclosingRange: []int32{7, 0, 9, 1}},
+		{Range: []int32{8, 5, 11}, Symbol: calleeSymbol},
+	},
+}
+
+func newCallHierarchyRange(line, startCharacter, endCharacter int) shared.Range {
+	return shared.Range{
+		Start: shared.Position{Line: line, Character: startCharacter},
+		End:   shared.Position{Line: line, Character: endCharacter},
+	}
+}
+
+func TestGetIncomingCalls(t *testing.T) {
+	// Set up mocks
+	mockRepoStore := defaultMockRepoStore()
+	mockLsifStore := NewMockLsifStore()
+	mockUploadSvc := NewMockUploadService()
+	mockGitserverClient := gitserver.NewMockClient()
+	hunkCache, _ := NewHunkCache(50)
+
+	// Init service
+	svc := newService(&observation.TestContext, mockRepoStore, mockLsifStore, mockUploadSvc, mockGitserverClient, nil, nil)
+
+	// Set up request state
+	mockRequestState := RequestState{}
+	mockRequestState.SetLocalCommitCache(mockRepoStore, mockGitserverClient)
+	mockRequestState.SetLocalGitTreeTranslator(mockGitserverClient, &sgtypes.Repo{}, mockCommit, mockPath, hunkCache)
+	uploads := []uploadsshared.Dump{
+		{ID: 50, Commit: mockCommit, Root: "sub1/"},
+	}
+	mockRequestState.SetUploadsDataLoader(uploads)
+
+	// Empty result set (prevents nil pointer as scanner is always non-nil)
+	mockUploadSvc.GetUploadIDsWithReferencesFunc.PushReturn([]int{}, 0, 0, nil)
+
+	locations := []shared.Location{
+		{DumpID: 50, Path: "a.go", Range: newCallHierarchyRange(0, 5, 11)}, // definition
+		{DumpID: 50, Path: "a.go", Range: newCallHierarchyRange(3, 1, 7)},
+		{DumpID: 50, Path: "a.go", Range: newCallHierarchyRange(4, 1, 7)},
+		{DumpID: 50, Path: "a.go", Range: newCallHierarchyRange(8, 5, 11)}, // not within a callable
+	}
+	mockLsifStore.ExtractReferenceLocationsFromPositionFunc.PushReturn(locations, nil, nil)
+	mockLsifStore.SCIPDocumentFunc.SetDefaultReturn(callHierarchyDocument, nil)
+
+	mockRequest := PositionalRequestArgs{
+		RequestArgs: RequestArgs{
+			RepositoryID: 42,
+			Commit:       mockCommit,
+			Limit:        50,
+		},
+		Path:      mockPath,
+		Line:      0,
+		Character: 6,
+	}
+	calls, err := svc.GetIncomingCalls(context.Background(), mockRequest, mockRequestState)
+	if err != nil {
+		t.Fatalf("unexpected error querying incoming calls: %s", err)
+	}
+
+	expectedCalls := []CallHierarchyItem{
+		{
+			Symbol: callerSymbol,
+			Definitions: []shared.UploadLocation{
+				{Dump: uploads[0], Path: "sub1/a.go", TargetCommit: mockCommit, TargetRange: newCallHierarchyRange(2, 5, 11)},
+			},
+			CallSites: []shared.UploadLocation{
+				{Dump: uploads[0], Path: "sub1/a.go", TargetCommit: mockCommit, TargetRange: newCallHierarchyRange(3, 1, 7)},
+				{Dump: uploads[0], Path: "sub1/a.go", TargetCommit: mockCommit, TargetRange: newCallHierarchyRange(4, 1, 7)},
+			},
+		},
+	}
+	if diff := cmp.Diff(expectedCalls, calls); diff != "" {
+		t.Errorf("unexpected calls (-want +got):\n%s", diff)
+	}
+}
+
+func TestGetOutgoingCalls(t *testing.T) {
+	// Set up mocks
+	mockRepoStore := defaultMockRepoStore()
+	mockLsifStore := NewMockLsifStore()
+	mockUploadSvc := NewMockUploadService()
+	mockGitserverClient := gitserver.NewMockClient()
+	hunkCache, _ := NewHunkCache(50)
+
+	// Init service
+	svc := newService(&observation.TestContext, mockRepoStore, mockLsifStore, mockUploadSvc, mockGitserverClient, nil, nil)
+
+	// Set up request state
+	mockRequestState := RequestState{}
+	mockRequestState.SetLocalCommitCache(mockRepoStore, mockGitserverClient)
+	mockRequestState.SetLocalGitTreeTranslator(mockGitserverClient, &sgtypes.Repo{}, mockCommit, mockPath, hunkCache)
+	uploads := []uploadsshared.Dump{
+		{ID: 50, Commit: mockCommit, Root: "sub1/"},
+	}
+	mockRequestState.SetUploadsDataLoader(uploads)
+
+	mockLsifStore.SCIPDocumentFunc.SetDefaultReturn(callHierarchyDocument, nil)
+
+	mockRequest := PositionalRequestArgs{
+		RequestArgs: RequestArgs{
+			RepositoryID: 42,
+			Commit:       mockCommit,
+			Limit:        50,
+		},
+		Path:      mockPath,
+		Line:      2,
+		Character: 6,
+	}
+	calls, err := svc.GetOutgoingCalls(context.Background(), mockRequest, mockRequestState)
+	if err != nil {
+		t.Fatalf("unexpected error querying outgoing calls: %s", err)
+	}
+
+	expectedCalls := []CallHierarchyItem{
+		{
+			Symbol: calleeSymbol,
+			Definitions: []shared.UploadLocation{
+				{Dump: uploads[0], Path: "sub1/a.go", TargetCommit: mockCommit, TargetRange: newCallHierarchyRange(0, 5, 11)},
+			},
+			CallSites: []shared.UploadLocation{
+				{Dump: uploads[0], Path: "sub1/a.go", TargetCommit: mockCommit, TargetRange: newCallHierarchyRange(3, 1, 7)},
+				{Dump: uploads[0], Path: "sub1/a.go", TargetCommit: mockCommit, TargetRange: newCallHierarchyRange(4, 1, 7)},
+			},
+		},
+	}
+	if diff := cmp.Diff(expectedCalls, calls); diff != "" {
+		t.Errorf("unexpected calls (-want +got):\n%s", diff)
+	}
+
+	// Positions which are not on a callable definition have no outgoing calls
+	mockRequest.Line, mockRequest.Character = 7, 5
+	calls, err = svc.GetOutgoingCalls(context.Background(), mockRequest, mockRequestState)
+	if err != nil {
+		t.Fatalf("unexpected error querying outgoing calls: %s", err)
+	}
+	if len(calls) != 0 {
+		t.Errorf("unexpected calls: %v", calls)
+	}
+}
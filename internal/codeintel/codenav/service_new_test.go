@@ -25,7 +25,7 @@ func TestGetDefinitions(t *testing.T) {
 		hunkCache, _ := NewHunkCache(50)
 
 		// Init service
-		svc := newService(&observation.TestContext, mockRepoStore, mockLsifStore, mockUploadSvc, mockGitserverClient)
+		svc := newService(&observation.TestContext, mockRepoStore, mockLsifStore, mockUploadSvc, mockGitserverClient, nil, nil)
 
 		// Set up request state
 		mockRequestState := RequestState{}
@@ -85,7 +85,7 @@ func TestGetDefinitions(t *testing.T) {
 		hunkCache, _ := NewHunkCache(50)
 
 		// Init service
-		svc := newService(&observation.TestContext, mockRepoStore, mockLsifStore, mockUploadSvc, mockGitserverClient)
+		svc := newService(&observation.TestContext, mockRepoStore, mockLsifStore, mockUploadSvc, mockGitserverClient, nil, nil)
 
 		// Set up request state
 		mockRequestState := RequestState{}
@@ -210,7 +210,7 @@ func TestGetReferences(t *testing.T) {
 		hunkCache, _ := NewHunkCache(50)
 
 		// Init service
-		svc := newService(&observation.TestContext, mockRepoStore, mockLsifStore, mockUploadSvc, mockGitserverClient)
+		svc := newService(&observation.TestContext, mockRepoStore, mockLsifStore, mockUploadSvc, mockGitserverClient, nil, nil)
 
 		// Set up request state
 		mockRequestState := RequestState{}
@@ -275,7 +275,7 @@ func TestGetReferences(t *testing.T) {
 		hunkCache, _ := NewHunkCache(50)
 
 		// Init service
-		svc := newService(&observation.TestContext, mockRepoStore, mockLsifStore, mockUploadSvc, mockGitserverClient)
+		svc := newService(&observation.TestContext, mockRepoStore, mockLsifStore, mockUploadSvc, mockGitserverClient, nil, nil)
 
 		// Set up request state
 		mockRequestState := RequestState{}
@@ -456,7 +456,7 @@ func TestGetImplementations(t *testing.T) {
 		hunkCache, _ := NewHunkCache(50)
 
 		// Init service
-		svc := newService(&observation.TestContext, mockRepoStore, mockLsifStore, mockUploadSvc, mockGitserverClient)
+		svc := newService(&observation.TestContext, mockRepoStore, mockLsifStore, mockUploadSvc, mockGitserverClient, nil, nil)
 
 		// Set up request state
 		mockRequestState := RequestState{}
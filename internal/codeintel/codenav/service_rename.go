@@ -0,0 +1,387 @@
+package codenav
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hexops/gotextdiff"
+	"github.com/hexops/gotextdiff/myers"
+	"github.com/sourcegraph/scip/bindings/go/scip"
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/authz"
+	"github.com/sourcegraph/sourcegraph/internal/codeintel/codenav/shared"
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// maximumRenameLocations is the maximum number of definitions and references of a symbol which
+// can be renamed at once. Edit sets must be complete, so larger renames are rejected.
+const maximumRenameLocations = 10000
+
+// ErrRenameTooLarge occurs when a symbol has more definitions and references than can be renamed at once.
+var ErrRenameTooLarge = errors.Newf("symbol has more than %d definitions and references", maximumRenameLocations)
+
+// GetRenameEdits returns the ranges which need to be edited to rename the symbol at the given
+// position: all of its definitions and references within the visible uploads, as well as all of
+// its references within uploads of dependent repositories. Ranges are grouped by repository,
+// commit, and path, and have been adjusted to fit the target (originally requested) commit for
+// the requested repository. A nil edit set is returned if there is no global symbol at the given
+// position.
+func (s *Service) GetRenameEdits(ctx context.Context, args PositionalRequestArgs, requestState RequestState) (_ *RenameEditSet, err error) {
+	ctx, trace, endObservation := observeResolver(ctx, &err, s.operations.getRenameEdits, serviceObserverThreshold, observation.Args{Attrs: []attribute.KeyValue{
+		attribute.Int("repositoryID", args.RepositoryID),
+		attribute.String("commit", args.Commit),
+		attribute.String("path", args.Path),
+		attribute.Int("line", args.Line),
+		attribute.Int("character", args.Character),
+	}})
+	defer endObservation()
+
+	symbol, err := s.getRenameSymbolAtPosition(ctx, args, requestState)
+	if err != nil || symbol == "" {
+		return nil, err
+	}
+	name, err := symbolDisplayName(symbol)
+	if err != nil {
+		return nil, err
+	}
+	trace.AddEvent("Symbol", attribute.String("symbol", symbol), attribute.String("name", name))
+
+	locationArgs := args
+	locationArgs.Limit = maximumRenameLocations
+	locationArgs.RawCursor = ""
+
+	locations, err := s.GetDefinitions(ctx, locationArgs, requestState)
+	if err != nil {
+		return nil, errors.Wrap(err, "GetDefinitions")
+	}
+
+	for cursor := (Cursor{}); cursor.Phase != "done"; {
+		if len(locations) > maximumRenameLocations {
+			return nil, ErrRenameTooLarge
+		}
+
+		var references []shared.UploadLocation
+		locationArgs.Limit = maximumRenameLocations - len(locations) + 1
+		references, cursor, err = s.GetReferences(ctx, locationArgs, requestState, cursor)
+		if err != nil {
+			return nil, errors.Wrap(err, "GetReferences")
+		}
+		locations = append(locations, references...)
+	}
+	if len(locations) > maximumRenameLocations {
+		return nil, ErrRenameTooLarge
+	}
+	trace.AddEvent("Locations", attribute.Int("numLocations", len(locations)))
+
+	repositories, err := s.filterRenameEdits(ctx, requestState, groupRenameEdits(locations))
+	if err != nil {
+		return nil, err
+	}
+
+	return &RenameEditSet{
+		Symbol:       symbol,
+		Name:         name,
+		Repositories: repositories,
+	}, nil
+}
+
+// filterRenameEdits removes the repositories the current user cannot access and the files they
+// cannot read due to sub-repo permissions. Repositories are looked up as the actor of the given
+// context, so that repository permissions are enforced by the repo store. Repositories left
+// without any files are removed.
+func (s *Service) filterRenameEdits(ctx context.Context, requestState RequestState, repositories []RepositoryRenameEdits) ([]RepositoryRenameEdits, error) {
+	if len(repositories) == 0 {
+		return repositories, nil
+	}
+
+	repositoryIDs := make([]api.RepoID, 0, len(repositories))
+	for _, repository := range repositories {
+		repositoryIDs = append(repositoryIDs, api.RepoID(repository.RepositoryID))
+	}
+	repos, err := s.repoStore.GetReposSetByIDs(ctx, repositoryIDs...)
+	if err != nil {
+		return nil, errors.Wrap(err, "repoStore.GetReposSetByIDs")
+	}
+
+	checkerEnabled := authz.SubRepoEnabled(requestState.authChecker)
+	var a *actor.Actor
+	if checkerEnabled {
+		a = actor.FromContext(ctx)
+	}
+
+	filtered := make([]RepositoryRenameEdits, 0, len(repositories))
+	for _, repository := range repositories {
+		repo, ok := repos[api.RepoID(repository.RepositoryID)]
+		if !ok {
+			continue
+		}
+
+		files := make([]FileRenameEdits, 0, len(repository.Files))
+		for _, file := range repository.Files {
+			if checkerEnabled {
+				include, err := authz.FilterActorPath(ctx, requestState.authChecker, a, repo.Name, file.Path)
+				if err != nil {
+					return nil, err
+				}
+				if !include {
+					continue
+				}
+			}
+
+			files = append(files, file)
+		}
+		if len(files) == 0 {
+			continue
+		}
+
+		repository.RepositoryName = string(repo.Name)
+		repository.Files = files
+		filtered = append(filtered, repository)
+	}
+
+	return filtered, nil
+}
+
+// getRenameSymbolAtPosition returns the global symbol at the given position within the first
+// visible upload which has one. Unlike for type hierarchies, the symbol itself is renamed rather
+// than its type.
+func (s *Service) getRenameSymbolAtPosition(ctx context.Context, args PositionalRequestArgs, requestState RequestState) (string, error) {
+	visibleUploads, err := s.getVisibleUploads(ctx, args.Line, args.Character, requestState)
+	if err != nil {
+		return "", err
+	}
+
+	documents := newDocumentCache(s.lsifstore)
+	for _, upload := range visibleUploads {
+		document, err := documents.get(ctx, upload.Upload.ID, upload.TargetPathWithoutRoot)
+		if err != nil {
+			return "", err
+		}
+		if document == nil {
+			continue
+		}
+
+		position := upload.TargetPosition
+		for _, occurrence := range scip.FindOccurrences(document.Occurrences, int32(position.Line), int32(position.Character)) {
+			if occurrence.Symbol != "" && !scip.IsLocalSymbol(occurrence.Symbol) {
+				return occurrence.Symbol, nil
+			}
+		}
+	}
+
+	return "", nil
+}
+
+// symbolDisplayName returns the name of the given symbol as it appears in source code.
+func symbolDisplayName(symbol string) (string, error) {
+	parsed, err := scip.ParseSymbol(symbol)
+	if err != nil {
+		return "", errors.Wrap(err, "scip.ParseSymbol")
+	}
+	if len(parsed.Descriptors) == 0 {
+		return "", errors.Newf("symbol %q has no descriptors", symbol)
+	}
+
+	return parsed.Descriptors[len(parsed.Descriptors)-1].Name, nil
+}
+
+// groupRenameEdits groups the given locations by repository, commit, and path. Duplicate ranges
+// are removed, and all groups are sorted.
+func groupRenameEdits(locations []shared.UploadLocation) []RepositoryRenameEdits {
+	type repositoryKey struct {
+		repositoryID int
+		commit       string
+	}
+
+	var repositories []RepositoryRenameEdits
+	repositoryIndexes := map[repositoryKey]int{}
+	rangesByPath := map[repositoryKey]map[string]map[shared.Range]struct{}{}
+
+	for _, location := range locations {
+		key := repositoryKey{repositoryID: location.Dump.RepositoryID, commit: location.TargetCommit}
+		if _, ok := repositoryIndexes[key]; !ok {
+			repositoryIndexes[key] = len(repositories)
+			repositories = append(repositories, RepositoryRenameEdits{
+				RepositoryID:   location.Dump.RepositoryID,
+				RepositoryName: location.Dump.RepositoryName,
+				Commit:         location.TargetCommit,
+			})
+			rangesByPath[key] = map[string]map[shared.Range]struct{}{}
+		}
+
+		if _, ok := rangesByPath[key][location.Path]; !ok {
+			rangesByPath[key][location.Path] = map[shared.Range]struct{}{}
+		}
+		rangesByPath[key][location.Path][location.TargetRange] = struct{}{}
+	}
+
+	for key, index := range repositoryIndexes {
+		for path, rangeSet := range rangesByPath[key] {
+			ranges := make([]shared.Range, 0, len(rangeSet))
+			for rng := range rangeSet {
+				ranges = append(ranges, rng)
+			}
+			sort.Slice(ranges, func(i, j int) bool {
+				if ranges[i].Start.Line != ranges[j].Start.Line {
+					return ranges[i].Start.Line < ranges[j].Start.Line
+				}
+				return ranges[i].Start.Character < ranges[j].Start.Character
+			})
+
+			repositories[index].Files = append(repositories[index].Files, FileRenameEdits{Path: path, Ranges: ranges})
+		}
+		sort.Slice(repositories[index].Files, func(i, j int) bool {
+			return repositories[index].Files[i].Path < repositories[index].Files[j].Path
+		})
+	}
+
+	sort.Slice(repositories, func(i, j int) bool {
+		if repositories[i].RepositoryName != repositories[j].RepositoryName {
+			return repositories[i].RepositoryName < repositories[j].RepositoryName
+		}
+		return repositories[i].Commit < repositories[j].Commit
+	})
+
+	return repositories
+}
+
+// GenerateRenameDiffs turns the given edit set into one unified diff per repository, replacing
+// each range with the given name. The diffs are relative to the repository root and can be
+// applied with `git apply`, e.g. as the output of a batch change step.
+//
+// Each range is checked against the current name of the symbol before it is replaced. Ranges
+// which do not span the current name (e.g. because they are stale or refer to an alias) are
+// left untouched and counted as skipped.
+//
+// Repositories and files the current user cannot access are removed from the edit set before
+// any file contents are read, so the returned diffs line up with the remaining repositories.
+func (s *Service) GenerateRenameDiffs(ctx context.Context, editSet *RenameEditSet, newName string, requestState RequestState) (_ []RepositoryRenameDiff, err error) {
+	ctx, _, endObservation := observeResolver(ctx, &err, s.operations.generateRenameDiffs, serviceObserverThreshold, observation.Args{Attrs: []attribute.KeyValue{
+		attribute.String("symbol", editSet.Symbol),
+		attribute.String("newName", newName),
+		attribute.Int("numRepositories", len(editSet.Repositories)),
+	}})
+	defer endObservation()
+
+	if newName == "" {
+		return nil, errors.New("new name must not be empty")
+	}
+
+	repositories, err := s.filterRenameEdits(ctx, requestState, editSet.Repositories)
+	if err != nil {
+		return nil, err
+	}
+	editSet.Repositories = repositories
+
+	diffs := make([]RepositoryRenameDiff, 0, len(editSet.Repositories))
+	for _, repository := range editSet.Repositories {
+		diff := RepositoryRenameDiff{
+			RepositoryID:   repository.RepositoryID,
+			RepositoryName: repository.RepositoryName,
+			Commit:         repository.Commit,
+		}
+
+		var sb strings.Builder
+		for _, file := range repository.Files {
+			contents, err := s.gitserver.ReadFile(ctx, api.RepoName(repository.RepositoryName), api.CommitID(repository.Commit), file.Path)
+			if err != nil {
+				return nil, errors.Wrap(err, "gitserver.ReadFile")
+			}
+
+			renamed, skipped := renameInContents(string(contents), file.Ranges, editSet.Name, newName)
+			diff.SkippedRanges += skipped
+			if renamed == string(contents) {
+				continue
+			}
+
+			edits := myers.ComputeEdits("", string(contents), renamed)
+			fmt.Fprint(&sb, gotextdiff.ToUnified("a/"+file.Path, "b/"+file.Path, string(contents), edits))
+		}
+		diff.Diff = sb.String()
+
+		diffs = append(diffs, diff)
+	}
+
+	return diffs, nil
+}
+
+// renameInContents replaces each of the given single-line ranges spanning oldName with newName.
+// The number of ranges which could not be replaced is also returned.
+//
+// Indexers disagree on the unit of character offsets, so each range is matched by interpreting
+// its offsets as UTF-8 bytes, runes, and UTF-16 code units, in that order.
+func renameInContents(contents string, ranges []shared.Range, oldName, newName string) (string, int) {
+	lines := strings.SplitAfter(contents, "\n")
+	skipped := 0
+
+	// Replace from the end of each line so earlier offsets remain valid
+	for i := len(ranges) - 1; i >= 0; i-- {
+		rng := ranges[i]
+		if rng.Start.Line != rng.End.Line || rng.Start.Line < 0 || rng.Start.Line >= len(lines) {
+			skipped++
+			continue
+		}
+
+		line := lines[rng.Start.Line]
+		start, ok := findNameOffset(line, rng.Start.Character, rng.End.Character, oldName)
+		if !ok {
+			skipped++
+			continue
+		}
+
+		lines[rng.Start.Line] = line[:start] + newName + line[start+len(oldName):]
+	}
+
+	return strings.Join(lines, ""), skipped
+}
+
+// findNameOffset returns the byte offset of the given name on the line, if the given character
+// offsets span the name under one of the supported units.
+func findNameOffset(line string, startCharacter, endCharacter int, name string) (int, bool) {
+	for _, toByteOffset := range []func(string, int) (int, bool){byteOffset, runeOffset, utf16Offset} {
+		start, ok1 := toByteOffset(line, startCharacter)
+		end, ok2 := toByteOffset(line, endCharacter)
+		if ok1 && ok2 && start <= end && line[start:end] == name {
+			return start, true
+		}
+	}
+
+	return 0, false
+}
+
+func byteOffset(line string, offset int) (int, bool) {
+	return offset, offset >= 0 && offset <= len(line)
+}
+
+func runeOffset(line string, offset int) (int, bool) {
+	for i := range line {
+		if offset == 0 {
+			return i, true
+		}
+		offset--
+	}
+
+	return len(line), offset == 0
+}
+
+func utf16Offset(line string, offset int) (int, bool) {
+	for i, r := range line {
+		if offset <= 0 {
+			return i, offset == 0
+		}
+		if r > 0xFFFF {
+			// Encoded as a surrogate pair
+			offset -= 2
+		} else {
+			offset--
+		}
+	}
+
+	return len(line), offset == 0
+}
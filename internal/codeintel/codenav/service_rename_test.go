@@ -0,0 +1,277 @@
+package codenav
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/authz"
+	"github.com/sourcegraph/sourcegraph/internal/codeintel/codenav/shared"
+	uploadsshared "github.com/sourcegraph/sourcegraph/internal/codeintel/uploads/shared"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbmocks"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver"
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+	sgtypes "github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+func TestGroupRenameEdits(t *testing.T) {
+	upload1 := uploadsshared.Dump{ID: 50, RepositoryID: 42, RepositoryName: "github.com/example/b"}
+	upload2 := uploadsshared.Dump{ID: 51, RepositoryID: 43, RepositoryName: "github.com/example/a"}
+
+	locations := []shared.UploadLocation{
+		{Dump: upload1, Path: "b.go", TargetCommit: "c1", TargetRange: newCallHierarchyRange(3, 1, 4)},
+		{Dump: upload1, Path: "a.go", TargetCommit: "c1", TargetRange: newCallHierarchyRange(5, 1, 4)},
+		{Dump: upload1, Path: "a.go", TargetCommit: "c1", TargetRange: newCallHierarchyRange(1, 1, 4)},
+		{Dump: upload1, Path: "a.go", TargetCommit: "c1", TargetRange: newCallHierarchyRange(5, 1, 4)}, // duplicate
+		{Dump: upload2, Path: "main.go", TargetCommit: "c2", TargetRange: newCallHierarchyRange(7, 8, 11)},
+	}
+
+	expected := []RepositoryRenameEdits{
+		{
+			RepositoryID:   43,
+			RepositoryName: "github.com/example/a",
+			Commit:         "c2",
+			Files: []FileRenameEdits{
+				{Path: "main.go", Ranges: []shared.Range{newCallHierarchyRange(7, 8, 11)}},
+			},
+		},
+		{
+			RepositoryID:   42,
+			RepositoryName: "github.com/example/b",
+			Commit:         "c1",
+			Files: []FileRenameEdits{
+				{Path: "a.go", Ranges: []shared.Range{newCallHierarchyRange(1, 1, 4), newCallHierarchyRange(5, 1, 4)}},
+				{Path: "b.go", Ranges: []shared.Range{newCallHierarchyRange(3, 1, 4)}},
+			},
+		},
+	}
+	if diff := cmp.Diff(expected, groupRenameEdits(locations)); diff != "" {
+		t.Errorf("unexpected edits (-want +got):\n%s", diff)
+	}
+}
+
+func TestRenameInContents(t *testing.T) {
+	testCases := []struct {
+		name            string
+		contents        string
+		ranges          []shared.Range
+		expected        string
+		expectedSkipped int
+	}{
+		{
+			name:     "bytes",
+			contents: "func foo() {}\n\nfunc bar() { foo(); foo() }\n",
+			ranges: []shared.Range{
+				newCallHierarchyRange(0, 5, 8),
+				newCallHierarchyRange(2, 13, 16),
+				newCallHierarchyRange(2, 20, 23),
+			},
+			expected: "func renamed() {}\n\nfunc bar() { renamed(); renamed() }\n",
+		},
+		{
+			name:     "runes",
+			contents: "x := \"é\"; foo()\n",
+			ranges:   []shared.Range{newCallHierarchyRange(0, 10, 13)},
+			expected: "x := \"é\"; renamed()\n",
+		},
+		{
+			name:     "utf16",
+			contents: "x := \"😀\"; foo()\n",
+			ranges:   []shared.Range{newCallHierarchyRange(0, 11, 14)},
+			expected: "x := \"😀\"; renamed()\n",
+		},
+		{
+			name:     "mismatch",
+			contents: "bar()\nfoo()\n",
+			ranges: []shared.Range{
+				newCallHierarchyRange(0, 0, 3),
+				newCallHierarchyRange(1, 0, 3),
+				newCallHierarchyRange(5, 0, 3),
+			},
+			expected:        "bar()\nrenamed()\n",
+			expectedSkipped: 2,
+		},
+		{
+			name:     "invalid ranges",
+			contents: "foo()\n",
+			ranges: []shared.Range{
+				newCallHierarchyRange(0, 3, 0),
+				newCallHierarchyRange(-1, 0, 3),
+			},
+			expected:        "foo()\n",
+			expectedSkipped: 2,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			renamed, skipped := renameInContents(testCase.contents, testCase.ranges, "foo", "renamed")
+			if renamed != testCase.expected {
+				t.Errorf("unexpected contents. want=%q have=%q", testCase.expected, renamed)
+			}
+			if skipped != testCase.expectedSkipped {
+				t.Errorf("unexpected number of skipped ranges. want=%d have=%d", testCase.expectedSkipped, skipped)
+			}
+		})
+	}
+}
+
+func TestGenerateRenameDiffs(t *testing.T) {
+	mockGitserverClient := gitserver.NewMockClient()
+	mockGitserverClient.ReadFileFunc.SetDefaultHook(func(_ context.Context, repo api.RepoName, commit api.CommitID, name string) ([]byte, error) {
+		if repo == "github.com/example/a" && commit == "c1" && name == "main.go" {
+			return []byte("package main\n\nfunc foo() {}\n\nfunc main() {\n\tfoo()\n}\n"), nil
+		}
+		return nil, errors.Newf("unexpected file %s@%s:%s", repo, commit, name)
+	})
+
+	mockRepoStore := dbmocks.NewMockRepoStore()
+	mockRepoStore.GetReposSetByIDsFunc.SetDefaultReturn(map[api.RepoID]*sgtypes.Repo{
+		42: {ID: 42, Name: "github.com/example/a"},
+	}, nil)
+
+	svc := newService(&observation.TestContext, mockRepoStore, NewMockLsifStore(), NewMockUploadService(), mockGitserverClient, nil, nil)
+
+	editSet := &RenameEditSet{
+		Symbol: "scip-go gomod github.com/example/a v1 main/foo().",
+		Name:   "foo",
+		Repositories: []RepositoryRenameEdits{
+			{
+				RepositoryID:   42,
+				RepositoryName: "github.com/example/a",
+				Commit:         "c1",
+				Files: []FileRenameEdits{
+					{Path: "main.go", Ranges: []shared.Range{newCallHierarchyRange(2, 5, 8), newCallHierarchyRange(5, 1, 4)}},
+				},
+			},
+		},
+	}
+
+	diffs, err := svc.GenerateRenameDiffs(context.Background(), editSet, "bar", RequestState{})
+	if err != nil {
+		t.Fatalf("unexpected error generating diffs: %s", err)
+	}
+
+	expected := []RepositoryRenameDiff{
+		{
+			RepositoryID:   42,
+			RepositoryName: "github.com/example/a",
+			Commit:         "c1",
+			Diff: strings.Join([]string{
+				"--- a/main.go",
+				"+++ b/main.go",
+				"@@ -1,7 +1,7 @@",
+				" package main",
+				" ",
+				"-func foo() {}",
+				"+func bar() {}",
+				" ",
+				" func main() {",
+				"-\tfoo()",
+				"+\tbar()",
+				" }",
+				"",
+			}, "\n"),
+		},
+	}
+	if diff := cmp.Diff(expected, diffs); diff != "" {
+		t.Errorf("unexpected diffs (-want +got):\n%s", diff)
+	}
+}
+
+func TestGenerateRenameDiffsWithInaccessibleRepositories(t *testing.T) {
+	mockGitserverClient := gitserver.NewMockClient()
+	mockGitserverClient.ReadFileFunc.SetDefaultHook(func(_ context.Context, repo api.RepoName, commit api.CommitID, name string) ([]byte, error) {
+		if repo == "github.com/example/a" && commit == "c1" && name == "main.go" {
+			return []byte("package main\n\nfunc foo() {}\n"), nil
+		}
+		return nil, errors.Newf("unexpected file %s@%s:%s", repo, commit, name)
+	})
+
+	// Repository 43 is not visible to the current user
+	mockRepoStore := dbmocks.NewMockRepoStore()
+	mockRepoStore.GetReposSetByIDsFunc.SetDefaultHook(func(_ context.Context, ids ...api.RepoID) (map[api.RepoID]*sgtypes.Repo, error) {
+		repos := map[api.RepoID]*sgtypes.Repo{}
+		for _, id := range ids {
+			if id == 42 {
+				repos[id] = &sgtypes.Repo{ID: id, Name: "github.com/example/a"}
+			}
+		}
+		return repos, nil
+	})
+
+	svc := newService(&observation.TestContext, mockRepoStore, NewMockLsifStore(), NewMockUploadService(), mockGitserverClient, nil, nil)
+
+	// Applying sub-repo permissions
+	checker := authz.NewMockSubRepoPermissionChecker()
+	checker.EnabledFunc.SetDefaultReturn(true)
+	checker.PermissionsFunc.SetDefaultHook(func(_ context.Context, _ int32, content authz.RepoContent) (authz.Perms, error) {
+		if content.Path == "main.go" {
+			return authz.Read, nil
+		}
+		return authz.None, nil
+	})
+	requestState := RequestState{}
+	requestState.SetAuthChecker(checker)
+
+	editSet := &RenameEditSet{
+		Symbol: "scip-go gomod github.com/example/a v1 main/foo().",
+		Name:   "foo",
+		Repositories: []RepositoryRenameEdits{
+			{
+				RepositoryID:   42,
+				RepositoryName: "github.com/example/a",
+				Commit:         "c1",
+				Files: []FileRenameEdits{
+					{Path: "main.go", Ranges: []shared.Range{newCallHierarchyRange(2, 5, 8)}},
+					{Path: "secret.go", Ranges: []shared.Range{newCallHierarchyRange(4, 1, 4)}},
+				},
+			},
+			{
+				RepositoryID:   43,
+				RepositoryName: "github.com/example/private",
+				Commit:         "c2",
+				Files: []FileRenameEdits{
+					{Path: "main.go", Ranges: []shared.Range{newCallHierarchyRange(6, 1, 4)}},
+				},
+			},
+		},
+	}
+
+	ctx := actor.WithActor(context.Background(), &actor.Actor{UID: 1})
+	diffs, err := svc.GenerateRenameDiffs(ctx, editSet, "bar", requestState)
+	if err != nil {
+		t.Fatalf("unexpected error generating diffs: %s", err)
+	}
+
+	expectedRepositories := []RepositoryRenameEdits{
+		{
+			RepositoryID:   42,
+			RepositoryName: "github.com/example/a",
+			Commit:         "c1",
+			Files: []FileRenameEdits{
+				{Path: "main.go", Ranges: []shared.Range{newCallHierarchyRange(2, 5, 8)}},
+			},
+		},
+	}
+	if diff := cmp.Diff(expectedRepositories, editSet.Repositories); diff != "" {
+		t.Errorf("unexpected repositories (-want +got):\n%s", diff)
+	}
+
+	if len(diffs) != 1 || diffs[0].RepositoryID != 42 {
+		t.Fatalf("expected a single diff for repository 42, got %+v", diffs)
+	}
+	if strings.Contains(diffs[0].Diff, "secret.go") {
+		t.Errorf("unexpected diff of unreadable file:\n%s", diffs[0].Diff)
+	}
+	for _, call := range mockGitserverClient.ReadFileFunc.History() {
+		if call.Arg1 != "github.com/example/a" || call.Arg3 != "main.go" {
+			t.Errorf("unexpected read of %s:%s", call.Arg1, call.Arg3)
+		}
+	}
+}
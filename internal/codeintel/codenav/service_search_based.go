@@ -0,0 +1,485 @@
+package codenav
+
+import (
+	"context"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/sourcegraph/sourcegraph/cmd/searcher/protocol"
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/authz"
+	"github.com/sourcegraph/sourcegraph/internal/codeintel/codenav/shared"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+	"github.com/sourcegraph/sourcegraph/internal/search"
+	"github.com/sourcegraph/sourcegraph/internal/search/result"
+	"github.com/sourcegraph/sourcegraph/internal/search/searcher"
+	"github.com/sourcegraph/sourcegraph/internal/search/symbol"
+	proto "github.com/sourcegraph/sourcegraph/internal/searcher/v1"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+const (
+	// maximumSearchBasedSymbolCandidates is the maximum number of symbols requested from symbol
+	// search. Symbol search matches on substrings, so this must be large enough to include exact
+	// matches of short identifiers.
+	maximumSearchBasedSymbolCandidates = 1000
+
+	// maximumSearchBasedReferenceFiles is the maximum number of files matched by text search for references.
+	maximumSearchBasedReferenceFiles = 1000
+
+	// searchBasedTextSearchTimeout bounds the text search for references.
+	searchBasedTextSearchTimeout = 10 * time.Second
+)
+
+// Scores contributed by each scoping rule satisfied by a search-based location.
+const (
+	searchBasedScoreSameFile      = 8
+	searchBasedScoreSameDirectory = 4
+	searchBasedScoreImported      = 2
+	searchBasedScoreSameLanguage  = 1
+)
+
+// GetSearchBasedDefinitions returns best-effort definitions of the identifier at the given position,
+// for use when no precise upload can answer the request. Candidates are found via symbol search
+// and ranked by language-aware scoping rules: definitions in the same file rank highest, then
+// those in the same directory (or package), then those in files imported by the requesting file.
+// Candidates in a file of a different language than the requesting file are discarded.
+func (s *Service) GetSearchBasedDefinitions(ctx context.Context, args PositionalRequestArgs, requestState RequestState) (_ []shared.SearchBasedLocation, err error) {
+	ctx, trace, endObservation := observeResolver(ctx, &err, s.operations.getSearchBasedDefinitions, serviceObserverThreshold, observation.Args{Attrs: []attribute.KeyValue{
+		attribute.Int("repositoryID", args.RepositoryID),
+		attribute.String("commit", args.Commit),
+		attribute.String("path", args.Path),
+		attribute.Int("line", args.Line),
+		attribute.Int("character", args.Character),
+		attribute.Int("limit", args.Limit),
+	}})
+	defer endObservation()
+
+	request, ok, err := s.newSearchBasedRequest(ctx, args)
+	if err != nil || !ok {
+		return nil, err
+	}
+	trace.AddEvent("identifier", attribute.String("identifier", request.identifier))
+
+	definitions, err := s.searchBasedDefinitions(ctx, request, requestState)
+	if err != nil {
+		return nil, err
+	}
+	trace.AddEvent("definitions", attribute.Int("numDefinitions", len(definitions)))
+
+	if args.Limit > 0 && len(definitions) > args.Limit {
+		definitions = definitions[:args.Limit]
+	}
+
+	return definitions, nil
+}
+
+// GetSearchBasedReferences returns best-effort references to the identifier at the given position,
+// for use when no precise upload can answer the request. Files of the requesting file's language are
+// scanned for whole-word occurrences of the identifier. If any definition of the identifier could
+// be found, only occurrences in the requesting file, in the directory of a definition, or in files
+// importing a definition are returned. In languages which qualify references to identifiers of
+// other packages (e.g. Go), occurrences outside of a definition's directory must be qualified.
+func (s *Service) GetSearchBasedReferences(ctx context.Context, args PositionalRequestArgs, requestState RequestState) (_ []shared.SearchBasedLocation, err error) {
+	ctx, trace, endObservation := observeResolver(ctx, &err, s.operations.getSearchBasedReferences, serviceObserverThreshold, observation.Args{Attrs: []attribute.KeyValue{
+		attribute.Int("repositoryID", args.RepositoryID),
+		attribute.String("commit", args.Commit),
+		attribute.String("path", args.Path),
+		attribute.Int("line", args.Line),
+		attribute.Int("character", args.Character),
+		attribute.Int("limit", args.Limit),
+	}})
+	defer endObservation()
+
+	request, ok, err := s.newSearchBasedRequest(ctx, args)
+	if err != nil || !ok {
+		return nil, err
+	}
+	trace.AddEvent("identifier", attribute.String("identifier", request.identifier))
+
+	definitions, err := s.searchBasedDefinitions(ctx, request, requestState)
+	if err != nil {
+		return nil, err
+	}
+	trace.AddEvent("definitions", attribute.Int("numDefinitions", len(definitions)))
+
+	includePattern := "^" + regexp.QuoteMeta(args.Path) + "$"
+	if request.language != nil {
+		includePattern = "(?:" + strings.Join(request.language.includePatterns(), "|") + ")"
+	}
+
+	fileMatches, err := s.textSearcher.SearchText(ctx, request.repo, request.commit, request.identifier, includePattern, maximumSearchBasedReferenceFiles)
+	if err != nil {
+		return nil, errors.Wrap(err, "textSearcher.SearchText")
+	}
+
+	scorer := newSearchBasedReferenceScorer(request, definitions)
+
+	var (
+		references []shared.SearchBasedLocation
+		numFiles   = len(fileMatches)
+	)
+	for _, fileMatch := range fileMatches {
+		// Scoping rules may depend on the imports of the file, which are not part of the matched
+		// chunks, so the contents of matching files are read when imports can affect the score.
+		var contents string
+		if scorer.needsContents() {
+			buf, err := s.gitserver.ReadFile(ctx, request.repo.Name, request.commit, fileMatch.Path)
+			if err != nil {
+				return nil, errors.Wrap(err, "gitserver.ReadFile")
+			}
+			contents = string(buf)
+		}
+
+		score, ok := scorer.score(fileMatch.Path, contents)
+		if !ok {
+			continue
+		}
+
+		for _, chunkMatch := range fileMatch.ChunkMatches {
+			for i, line := range strings.Split(chunkMatch.Content, "\n") {
+				lineNumber := int(chunkMatch.ContentStart.Line) + i
+
+				for _, start := range identifierOccurrences(line, request.identifier) {
+					end := start + len(request.identifier)
+					if !scorer.qualified(fileMatch.Path, line[:start]) {
+						continue
+					}
+
+					references = append(references, shared.SearchBasedLocation{
+						Path: fileMatch.Path,
+						Range: shared.Range{
+							Start: shared.Position{Line: lineNumber, Character: utf16Length(line[:start])},
+							End:   shared.Position{Line: lineNumber, Character: utf16Length(line[:end])},
+						},
+						Score: score,
+					})
+				}
+			}
+		}
+	}
+	trace.AddEvent("references", attribute.Int("numFiles", numFiles), attribute.Int("numReferences", len(references)))
+
+	references, err = filterSearchBasedLocations(ctx, requestState, request.repo.Name, references)
+	if err != nil {
+		return nil, err
+	}
+	sortSearchBasedLocations(references)
+
+	if args.Limit > 0 && len(references) > args.Limit {
+		references = references[:args.Limit]
+	}
+
+	return references, nil
+}
+
+// searchBasedRequest holds the context of a search-based code navigation request.
+type searchBasedRequest struct {
+	repo       types.MinimalRepo
+	commit     api.CommitID
+	path       string
+	identifier string
+	language   *searchBasedLanguage
+	scopes     []string
+}
+
+// newSearchBasedRequest resolves the repository of the given request and the identifier at the
+// requested position. A false-valued flag is returned if there is no identifier at the position.
+func (s *Service) newSearchBasedRequest(ctx context.Context, args PositionalRequestArgs) (searchBasedRequest, bool, error) {
+	repo, err := s.repoStore.Get(ctx, api.RepoID(args.RepositoryID))
+	if err != nil {
+		return searchBasedRequest{}, false, errors.Wrap(err, "repoStore.Get")
+	}
+
+	contents, err := s.gitserver.ReadFile(ctx, repo.Name, api.CommitID(args.Commit), args.Path)
+	if err != nil {
+		return searchBasedRequest{}, false, errors.Wrap(err, "gitserver.ReadFile")
+	}
+
+	identifier := identifierAt(string(contents), args.Line, args.Character)
+	if identifier == "" {
+		return searchBasedRequest{}, false, nil
+	}
+
+	request := searchBasedRequest{
+		repo:       types.MinimalRepo{ID: repo.ID, Name: repo.Name},
+		commit:     api.CommitID(args.Commit),
+		path:       args.Path,
+		identifier: identifier,
+		language:   searchBasedLanguageForPath(args.Path),
+	}
+	if request.language != nil {
+		request.scopes = request.language.scopes(args.Path, string(contents))
+	}
+
+	return request, true, nil
+}
+
+// searchBasedDefinitions returns the ranked definitions of the requested identifier.
+func (s *Service) searchBasedDefinitions(ctx context.Context, request searchBasedRequest, requestState RequestState) ([]shared.SearchBasedLocation, error) {
+	var includePatterns []string
+	if request.language != nil {
+		includePatterns = request.language.includePatterns()
+	}
+
+	matches, err := s.symbolSearcher.SearchSymbols(ctx, request.repo, request.commit, request.identifier, includePatterns, maximumSearchBasedSymbolCandidates)
+	if err != nil {
+		return nil, errors.Wrap(err, "symbolSearcher.SearchSymbols")
+	}
+
+	definitions := make([]shared.SearchBasedLocation, 0, len(matches))
+	for _, match := range matches {
+		if match.Symbol.Name != request.identifier {
+			continue
+		}
+
+		definitionPath := match.File.Path
+		if definitionPath == "" {
+			definitionPath = match.Symbol.Path
+		}
+		if request.language != nil && searchBasedLanguageForPath(definitionPath) != request.language {
+			continue
+		}
+
+		rng := match.Symbol.Range()
+		definitions = append(definitions, shared.SearchBasedLocation{
+			Path: definitionPath,
+			Range: shared.Range{
+				Start: shared.Position{Line: rng.Start.Line, Character: rng.Start.Character},
+				End:   shared.Position{Line: rng.End.Line, Character: rng.End.Character},
+			},
+			Score: scoreSearchBasedDefinition(request, definitionPath),
+		})
+	}
+
+	definitions, err = filterSearchBasedLocations(ctx, requestState, request.repo.Name, definitions)
+	if err != nil {
+		return nil, err
+	}
+	sortSearchBasedLocations(definitions)
+
+	return definitions, nil
+}
+
+// scoreSearchBasedDefinition scores a definition candidate by its proximity to the requesting file.
+func scoreSearchBasedDefinition(request searchBasedRequest, definitionPath string) int {
+	score := 0
+	if definitionPath == request.path {
+		score += searchBasedScoreSameFile
+	}
+	if path.Dir(definitionPath) == path.Dir(request.path) {
+		score += searchBasedScoreSameDirectory
+	}
+	if matchesScope(definitionPath, request.scopes) {
+		score += searchBasedScoreImported
+	}
+	if request.language != nil {
+		score += searchBasedScoreSameLanguage
+	}
+
+	return score
+}
+
+// searchBasedReferenceScorer decides which files are in scope of a set of definitions.
+type searchBasedReferenceScorer struct {
+	request         searchBasedRequest
+	definitionPaths []string
+	definitionDirs  map[string]struct{}
+	qualifiers      map[string]struct{}
+}
+
+func newSearchBasedReferenceScorer(request searchBasedRequest, definitions []shared.SearchBasedLocation) *searchBasedReferenceScorer {
+	scorer := &searchBasedReferenceScorer{
+		request:        request,
+		definitionDirs: map[string]struct{}{},
+		qualifiers:     map[string]struct{}{},
+	}
+
+	for _, definition := range definitions {
+		scorer.definitionPaths = append(scorer.definitionPaths, definition.Path)
+		scorer.definitionDirs[path.Dir(definition.Path)] = struct{}{}
+
+		if request.language != nil && request.language.qualifier != nil {
+			if qualifier := request.language.qualifier(definition.Path); qualifier != "" {
+				scorer.qualifiers[qualifier] = struct{}{}
+			}
+		}
+	}
+
+	return scorer
+}
+
+// needsContents returns true if the score of a file depends on its contents.
+func (s *searchBasedReferenceScorer) needsContents() bool {
+	return s.request.language != nil && len(s.definitionPaths) > 0
+}
+
+// score returns the score of references within the given file. A false-valued flag is returned
+// if the file is not in scope of any definition. The file contents are only consulted if
+// needsContents returns true.
+func (s *searchBasedReferenceScorer) score(filePath, contents string) (int, bool) {
+	score := 0
+	if filePath == s.request.path {
+		score += searchBasedScoreSameFile
+	}
+	if _, ok := s.definitionDirs[path.Dir(filePath)]; ok {
+		score += searchBasedScoreSameDirectory
+	}
+	if s.request.language != nil && len(s.definitionPaths) > 0 {
+		scopes := s.request.language.scopes(filePath, contents)
+		for _, definitionPath := range s.definitionPaths {
+			if matchesScope(definitionPath, scopes) {
+				score += searchBasedScoreImported
+				break
+			}
+		}
+	}
+
+	if score == 0 && len(s.definitionPaths) > 0 {
+		return 0, false
+	}
+	if s.request.language != nil {
+		score += searchBasedScoreSameLanguage
+	}
+
+	return score, true
+}
+
+// qualified returns true if an occurrence preceded by the given text on its line is a reference
+// to one of the definitions in scope. Occurrences outside of the directory of all definitions must
+// be qualified in languages which qualify references to other packages.
+func (s *searchBasedReferenceScorer) qualified(filePath, prefix string) bool {
+	if len(s.qualifiers) == 0 {
+		return true
+	}
+	if _, ok := s.definitionDirs[path.Dir(filePath)]; ok {
+		return true
+	}
+
+	if !strings.HasSuffix(prefix, ".") {
+		return false
+	}
+	prefix = strings.TrimSuffix(prefix, ".")
+	start := len(prefix)
+	for start > 0 && isIdentifierByte(prefix[start-1]) {
+		start--
+	}
+
+	_, ok := s.qualifiers[prefix[start:]]
+	return ok
+}
+
+// filterSearchBasedLocations removes the locations the current actor cannot view due to sub-repository permissions.
+func filterSearchBasedLocations(ctx context.Context, requestState RequestState, repo api.RepoName, locations []shared.SearchBasedLocation) ([]shared.SearchBasedLocation, error) {
+	if !authz.SubRepoEnabled(requestState.authChecker) {
+		return locations, nil
+	}
+
+	a := actor.FromContext(ctx)
+	filtered := locations[:0]
+	for _, location := range locations {
+		include, err := authz.FilterActorPath(ctx, requestState.authChecker, a, repo, location.Path)
+		if err != nil {
+			return nil, err
+		}
+		if include {
+			filtered = append(filtered, location)
+		}
+	}
+
+	return filtered, nil
+}
+
+// sortSearchBasedLocations orders locations by descending score, then by path and position.
+func sortSearchBasedLocations(locations []shared.SearchBasedLocation) {
+	sort.SliceStable(locations, func(i, j int) bool {
+		if locations[i].Score != locations[j].Score {
+			return locations[i].Score > locations[j].Score
+		}
+		if locations[i].Path != locations[j].Path {
+			return locations[i].Path < locations[j].Path
+		}
+		if locations[i].Range.Start.Line != locations[j].Range.Start.Line {
+			return locations[i].Range.Start.Line < locations[j].Range.Start.Line
+		}
+		return locations[i].Range.Start.Character < locations[j].Range.Start.Character
+	})
+}
+
+// textSearcher is the default TextSearcher, backed by searcher (which defers to Zoekt for
+// indexed commits).
+type textSearcher struct{}
+
+func (textSearcher) SearchText(ctx context.Context, repo types.MinimalRepo, commit api.CommitID, identifier string, includePattern string, limit int) ([]*protocol.FileMatch, error) {
+	branch := indexedBranch(ctx, repo, commit)
+	info := &search.TextPatternInfo{
+		Pattern:               identifier,
+		IsWordMatch:           true,
+		IsCaseSensitive:       true,
+		FileMatchLimit:        int32(limit),
+		IncludePatterns:       []string{includePattern},
+		PatternMatchesContent: true,
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, searchBasedTextSearchTimeout)
+	defer cancel()
+
+	var fileMatches []*protocol.FileMatch
+	if conf.IsGRPCEnabled(ctx) {
+		onMatch := func(match *proto.FileMatch) {
+			var fileMatch protocol.FileMatch
+			fileMatch.FromProto(match)
+			fileMatches = append(fileMatches, &fileMatch)
+		}
+		if _, err := searcher.SearchGRPC(ctx, search.SearcherURLs(), search.SearcherGRPCConnectionCache(), repo.Name, repo.ID, branch, commit, branch != "", info, searchBasedTextSearchTimeout, search.Features{}, onMatch); err != nil {
+			return nil, err
+		}
+	} else {
+		onMatches := func(matches []*protocol.FileMatch) {
+			fileMatches = append(fileMatches, matches...)
+		}
+		if _, err := searcher.Search(ctx, search.SearcherURLs(), repo.Name, repo.ID, branch, commit, branch != "", info, searchBasedTextSearchTimeout, search.Features{}, onMatches); err != nil {
+			return nil, err
+		}
+	}
+
+	return fileMatches, nil
+}
+
+// indexedBranch returns the name of a branch Zoekt has indexed at the given commit, or an
+// empty string if the commit is not indexed.
+func indexedBranch(ctx context.Context, repo types.MinimalRepo, commit api.CommitID) string {
+	ctx, cancel := context.WithTimeout(ctx, time.Second)
+	defer cancel()
+
+	indexed, err := search.ListAllIndexed(ctx)
+	if err != nil {
+		return ""
+	}
+
+	if r, ok := indexed.ReposMap[uint32(repo.ID)]; ok {
+		for _, branch := range r.Branches {
+			if branch.Version == string(commit) {
+				return branch.Name
+			}
+		}
+	}
+
+	return ""
+}
+
+// symbolSearcher is the default SymbolSearcher, backed by Zoekt or the symbols service.
+type symbolSearcher struct{}
+
+func (symbolSearcher) SearchSymbols(ctx context.Context, repo types.MinimalRepo, commit api.CommitID, query string, includePatterns []string, limit int) ([]*result.SymbolMatch, error) {
+	first := int32(limit)
+	return symbol.Compute(ctx, authz.DefaultSubRepoPermsChecker, repo, commit, nil, &query, &first, &includePatterns)
+}
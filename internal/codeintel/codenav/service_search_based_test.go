@@ -0,0 +1,164 @@
+package codenav
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/sourcegraph/sourcegraph/cmd/searcher/protocol"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/codeintel/codenav/shared"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver"
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+	"github.com/sourcegraph/sourcegraph/internal/search/result"
+	sgtypes "github.com/sourcegraph/sourcegraph/internal/types"
+)
+
+const searchBasedRequestFile = `package a
+
+import "github.com/example/r/pkg/b"
+
+func f() { b.Helper() }
+`
+
+var searchBasedFiles = map[string]string{
+	"pkg/a/a.go": searchBasedRequestFile,
+	"pkg/b/b.go": "package b\n\nfunc Helper() {}\n\nfunc g() { Helper() }\n",
+	"pkg/b/u.go": "package b\n\nvar \u00e9 = \"\U0001F600\"; Helper()\n",
+	"pkg/d/d.go": "package d\n\nimport \"github.com/example/r/pkg/b\"\n\nfunc h() { b.Helper(); x.Helper(); b.Helper2() }\n",
+	"pkg/e/e.go": "package e\n\nfunc i() { b.Helper() }\n",
+}
+
+func newSearchBasedTestService(t *testing.T, matches []*result.SymbolMatch) (*Service, RequestState) {
+	mockRepoStore := defaultMockRepoStore()
+	mockRepoStore.GetFunc.SetDefaultReturn(&sgtypes.Repo{ID: 42, Name: "github.com/example/r"}, nil)
+
+	mockGitserverClient := gitserver.NewMockClient()
+	mockGitserverClient.ReadFileFunc.SetDefaultHook(func(_ context.Context, _ api.RepoName, _ api.CommitID, path string) ([]byte, error) {
+		return []byte(searchBasedFiles[path]), nil
+	})
+
+	mockSymbolSearcher := NewMockSymbolSearcher()
+	mockSymbolSearcher.SearchSymbolsFunc.SetDefaultReturn(matches, nil)
+
+	mockTextSearcher := NewMockTextSearcher()
+	mockTextSearcher.SearchTextFunc.SetDefaultHook(func(_ context.Context, _ sgtypes.MinimalRepo, _ api.CommitID, identifier string, includePattern string, _ int) ([]*protocol.FileMatch, error) {
+		if includePattern != `(?:\.go$)` {
+			t.Fatalf("unexpected include pattern %q", includePattern)
+		}
+
+		var fileMatches []*protocol.FileMatch
+		for _, path := range []string{"pkg/a/a.go", "pkg/b/b.go", "pkg/b/u.go", "pkg/d/d.go", "pkg/e/e.go"} {
+			fileMatch := &protocol.FileMatch{Path: path}
+			for i, line := range strings.Split(searchBasedFiles[path], "\n") {
+				if len(identifierOccurrences(line, identifier)) > 0 {
+					fileMatch.ChunkMatches = append(fileMatch.ChunkMatches, protocol.ChunkMatch{
+						Content:      line,
+						ContentStart: protocol.Location{Line: int32(i)},
+					})
+				}
+			}
+			if len(fileMatch.ChunkMatches) > 0 {
+				fileMatches = append(fileMatches, fileMatch)
+			}
+		}
+
+		return fileMatches, nil
+	})
+
+	svc := newService(&observation.TestContext, mockRepoStore, NewMockLsifStore(), NewMockUploadService(), mockGitserverClient, mockSymbolSearcher, mockTextSearcher)
+	return svc, RequestState{RepositoryID: 42, Commit: mockCommit, Path: "pkg/a/a.go"}
+}
+
+func newSymbolMatch(name, path string, line, character int) *result.SymbolMatch {
+	return &result.SymbolMatch{
+		Symbol: result.Symbol{Name: name, Path: path, Line: line, Character: character},
+		File:   &result.File{Path: path},
+	}
+}
+
+func newSearchBasedLocation(path string, line, startCharacter, endCharacter, score int) shared.SearchBasedLocation {
+	return shared.SearchBasedLocation{
+		Path: path,
+		Range: shared.Range{
+			Start: shared.Position{Line: line, Character: startCharacter},
+			End:   shared.Position{Line: line, Character: endCharacter},
+		},
+		Score: score,
+	}
+}
+
+func TestGetSearchBasedDefinitions(t *testing.T) {
+	svc, requestState := newSearchBasedTestService(t, []*result.SymbolMatch{
+		newSymbolMatch("Helper", "pkg/c/c.go", 3, 5),
+		newSymbolMatch("Helper", "pkg/b/b.go", 3, 5),
+		newSymbolMatch("HelperFunc", "pkg/b/b.go", 7, 5),
+		newSymbolMatch("Helper", "pkg/a/other.go", 10, 5),
+		newSymbolMatch("Helper", "web/helper.ts", 1, 16),
+	})
+
+	definitions, err := svc.GetSearchBasedDefinitions(context.Background(), PositionalRequestArgs{
+		RequestArgs: RequestArgs{RepositoryID: 42, Commit: mockCommit, Limit: 10},
+		Path:        "pkg/a/a.go",
+		Line:        4,
+		Character:   15,
+	}, requestState)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	expected := []shared.SearchBasedLocation{
+		newSearchBasedLocation("pkg/a/other.go", 9, 5, 11, searchBasedScoreSameDirectory+searchBasedScoreSameLanguage),
+		newSearchBasedLocation("pkg/b/b.go", 2, 5, 11, searchBasedScoreImported+searchBasedScoreSameLanguage),
+		newSearchBasedLocation("pkg/c/c.go", 2, 5, 11, searchBasedScoreSameLanguage),
+	}
+	if diff := cmp.Diff(expected, definitions); diff != "" {
+		t.Errorf("unexpected definitions (-want +got):\n%s", diff)
+	}
+}
+
+func TestGetSearchBasedDefinitionsNoIdentifier(t *testing.T) {
+	svc, requestState := newSearchBasedTestService(t, nil)
+
+	definitions, err := svc.GetSearchBasedDefinitions(context.Background(), PositionalRequestArgs{
+		RequestArgs: RequestArgs{RepositoryID: 42, Commit: mockCommit, Limit: 10},
+		Path:        "pkg/a/a.go",
+		Line:        1,
+		Character:   0,
+	}, requestState)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(definitions) != 0 {
+		t.Errorf("unexpected definitions: %v", definitions)
+	}
+}
+
+func TestGetSearchBasedReferences(t *testing.T) {
+	svc, requestState := newSearchBasedTestService(t, []*result.SymbolMatch{
+		newSymbolMatch("Helper", "pkg/b/b.go", 3, 5),
+	})
+
+	references, err := svc.GetSearchBasedReferences(context.Background(), PositionalRequestArgs{
+		RequestArgs: RequestArgs{RepositoryID: 42, Commit: mockCommit, Limit: 10},
+		Path:        "pkg/a/a.go",
+		Line:        4,
+		Character:   15,
+	}, requestState)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	expected := []shared.SearchBasedLocation{
+		newSearchBasedLocation("pkg/a/a.go", 4, 13, 19, searchBasedScoreSameFile+searchBasedScoreImported+searchBasedScoreSameLanguage),
+		newSearchBasedLocation("pkg/b/b.go", 2, 5, 11, searchBasedScoreSameDirectory+searchBasedScoreSameLanguage),
+		newSearchBasedLocation("pkg/b/b.go", 4, 11, 17, searchBasedScoreSameDirectory+searchBasedScoreSameLanguage),
+		newSearchBasedLocation("pkg/b/u.go", 2, 14, 20, searchBasedScoreSameDirectory+searchBasedScoreSameLanguage),
+		newSearchBasedLocation("pkg/d/d.go", 4, 13, 19, searchBasedScoreImported+searchBasedScoreSameLanguage),
+	}
+	if diff := cmp.Diff(expected, references); diff != "" {
+		t.Errorf("unexpected references (-want +got):\n%s", diff)
+	}
+}
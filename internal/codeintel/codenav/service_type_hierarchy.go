@@ -0,0 +1,312 @@
+package codenav
+
+import (
+	"context"
+	"strings"
+
+	"github.com/sourcegraph/scip/bindings/go/scip"
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/sourcegraph/sourcegraph/internal/codeintel/codenav/shared"
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// maximumTypeHierarchyLocations is the maximum number of definitions or implementations of a
+// single type which are considered while expanding a node of a type hierarchy.
+const maximumTypeHierarchyLocations = 1000
+
+// GetTypeHierarchy returns the supertypes or subtypes of the type at the given position. If the
+// position refers to a symbol with a type definition relationship (e.g. a variable), the hierarchy
+// of its type is returned instead.
+//
+// The hierarchy is walked transitively via SCIP implementation relationships, breadth-first, up to
+// args.Depth levels below the root. Relationships are followed across uploads and repositories via
+// the monikers of each type. Each page contains at most args.Limit nodes besides the root, along
+// with the ancestors connecting them to the root. A nil root is returned if there is no type at
+// the given position, and a nil cursor is returned once the hierarchy is exhausted.
+func (s *Service) GetTypeHierarchy(
+	ctx context.Context,
+	args TypeHierarchyArgs,
+	requestState RequestState,
+	cursor TypeHierarchyCursor,
+) (_ *TypeHierarchyNode, nextCursor *TypeHierarchyCursor, err error) {
+	ctx, trace, endObservation := observeResolver(ctx, &err, s.operations.getTypeHierarchy, serviceObserverThreshold, observation.Args{Attrs: []attribute.KeyValue{
+		attribute.Int("repositoryID", args.RepositoryID),
+		attribute.String("commit", args.Commit),
+		attribute.String("path", args.Path),
+		attribute.Int("line", args.Line),
+		attribute.Int("character", args.Character),
+		attribute.String("direction", string(args.Direction)),
+		attribute.Int("depth", args.Depth),
+		attribute.Int("limit", args.Limit),
+		attribute.Int("offset", cursor.Offset),
+	}})
+	defer endObservation()
+
+	if args.Direction != TypeHierarchySupertypes && args.Direction != TypeHierarchySubtypes {
+		return nil, nil, errors.Newf("unknown type hierarchy direction %q", args.Direction)
+	}
+
+	documents := newDocumentCache(s.lsifstore)
+
+	symbol, err := s.getTypeSymbolAtPosition(ctx, args.PositionalRequestArgs, requestState, documents)
+	if err != nil || symbol == "" {
+		return nil, nil, err
+	}
+	trace.AddEvent("TypeSymbol", attribute.String("symbol", symbol))
+
+	locationArgs := args.RequestArgs
+	locationArgs.Limit = maximumTypeHierarchyLocations
+	locationArgs.RawCursor = ""
+
+	root := &TypeHierarchyNode{Symbol: symbol}
+	if root.Definitions, err = s.GetDefinitionsBySymbolNames(ctx, locationArgs, requestState, []string{symbol}); err != nil {
+		return nil, nil, errors.Wrap(err, "GetDefinitionsBySymbolNames")
+	}
+
+	var (
+		end     = cursor.Offset + args.Limit
+		visited = map[string]struct{}{symbol: {}}
+		indexes = map[*TypeHierarchyNode]int{root: -1}
+		queue   = []*TypeHierarchyNode{root}
+		depths  = map[*TypeHierarchyNode]int{root: 0}
+		count   = 0
+	)
+
+outer:
+	for len(queue) > 0 {
+		node := queue[0]
+		queue = queue[1:]
+		if depths[node] >= args.Depth {
+			continue
+		}
+
+		related, err := s.getRelatedTypes(ctx, locationArgs, requestState, documents, node, args.Direction)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		for _, child := range related {
+			if _, ok := visited[child.Symbol]; ok {
+				continue
+			}
+			if count >= end {
+				// There is at least one more node beyond this page
+				nextCursor = &TypeHierarchyCursor{Offset: end}
+				break outer
+			}
+			visited[child.Symbol] = struct{}{}
+
+			indexes[child] = count
+			depths[child] = depths[node] + 1
+			count++
+
+			node.Children = append(node.Children, child)
+			queue = append(queue, child)
+		}
+	}
+	trace.AddEvent("Walk", attribute.Int("numNodes", count))
+
+	pruneTypeHierarchy(root, func(node *TypeHierarchyNode) bool {
+		index := indexes[node]
+		return index >= cursor.Offset && index < end
+	})
+
+	return root, nextCursor, nil
+}
+
+// getTypeSymbolAtPosition returns the global symbol at the given position within the first
+// visible upload which defines one, substituting the type of symbols with a type definition
+// relationship.
+func (s *Service) getTypeSymbolAtPosition(ctx context.Context, args PositionalRequestArgs, requestState RequestState, documents *documentCache) (string, error) {
+	visibleUploads, err := s.getVisibleUploads(ctx, args.Line, args.Character, requestState)
+	if err != nil {
+		return "", err
+	}
+
+	for _, upload := range visibleUploads {
+		document, err := documents.get(ctx, upload.Upload.ID, upload.TargetPathWithoutRoot)
+		if err != nil {
+			return "", err
+		}
+		if document == nil {
+			continue
+		}
+
+		position := upload.TargetPosition
+		for _, occurrence := range scip.FindOccurrences(document.Occurrences, int32(position.Line), int32(position.Character)) {
+			if occurrence.Symbol == "" || scip.IsLocalSymbol(occurrence.Symbol) {
+				continue
+			}
+
+			if symbol := scip.FindSymbol(document, occurrence.Symbol); symbol != nil {
+				for _, relationship := range symbol.Relationships {
+					if relationship.IsTypeDefinition && !scip.IsLocalSymbol(relationship.Symbol) {
+						return relationship.Symbol, nil
+					}
+				}
+			}
+
+			return occurrence.Symbol, nil
+		}
+	}
+
+	return "", nil
+}
+
+// getRelatedTypes returns the direct supertypes or subtypes of the given node, each with the
+// locations of its definitions.
+func (s *Service) getRelatedTypes(
+	ctx context.Context,
+	args RequestArgs,
+	requestState RequestState,
+	documents *documentCache,
+	node *TypeHierarchyNode,
+	direction TypeHierarchyDirection,
+) ([]*TypeHierarchyNode, error) {
+	if direction == TypeHierarchySupertypes {
+		return s.getSupertypes(ctx, args, requestState, documents, node)
+	}
+
+	return s.getSubtypes(ctx, args, requestState, documents, node)
+}
+
+// getSupertypes returns the types the given node implements, according to the relationships of
+// the symbol information stored alongside each of its definitions.
+func (s *Service) getSupertypes(
+	ctx context.Context,
+	args RequestArgs,
+	requestState RequestState,
+	documents *documentCache,
+	node *TypeHierarchyNode,
+) ([]*TypeHierarchyNode, error) {
+	var symbols []string
+	seen := map[string]struct{}{}
+
+	for _, definition := range node.Definitions {
+		document, err := documents.get(ctx, definition.Dump.ID, strings.TrimPrefix(definition.Path, definition.Dump.Root))
+		if err != nil {
+			return nil, err
+		}
+		if document == nil {
+			continue
+		}
+
+		symbol := scip.FindSymbol(document, node.Symbol)
+		if symbol == nil {
+			continue
+		}
+
+		for _, relationship := range symbol.Relationships {
+			if !relationship.IsImplementation || scip.IsLocalSymbol(relationship.Symbol) {
+				continue
+			}
+			if _, ok := seen[relationship.Symbol]; ok {
+				continue
+			}
+
+			seen[relationship.Symbol] = struct{}{}
+			symbols = append(symbols, relationship.Symbol)
+		}
+	}
+
+	supertypes := make([]*TypeHierarchyNode, 0, len(symbols))
+	for _, symbol := range symbols {
+		definitions, err := s.GetDefinitionsBySymbolNames(ctx, args, requestState, []string{symbol})
+		if err != nil {
+			return nil, errors.Wrap(err, "GetDefinitionsBySymbolNames")
+		}
+
+		supertypes = append(supertypes, &TypeHierarchyNode{
+			Symbol:      symbol,
+			Definitions: definitions,
+		})
+	}
+
+	return supertypes, nil
+}
+
+// getSubtypes returns the types implementing the given node. Implementations are gathered the same
+// way as GetImplementations, so they span all uploads which refer to the symbol via its monikers.
+// Each implementation location is attributed to the symbol defined at that location.
+func (s *Service) getSubtypes(
+	ctx context.Context,
+	args RequestArgs,
+	requestState RequestState,
+	documents *documentCache,
+	node *TypeHierarchyNode,
+) ([]*TypeHierarchyNode, error) {
+	implementations, _, err := s.gatherLocationsBySymbolNames(
+		ctx, args, requestState, Cursor{},
+
+		s.operations.getImplementations, // operation
+		"implementations",               // tableName
+		true,                            // includeReferencingIndexes
+		[]string{node.Symbol},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	var subtypes []*TypeHierarchyNode
+	bySymbol := map[string]*TypeHierarchyNode{}
+
+	for _, implementation := range implementations {
+		document, err := documents.get(ctx, implementation.Dump.ID, strings.TrimPrefix(implementation.Path, implementation.Dump.Root))
+		if err != nil {
+			return nil, err
+		}
+		if document == nil {
+			continue
+		}
+
+		rng, ok, err := s.getIndexedRange(ctx, requestState, implementation)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			continue
+		}
+
+		symbol := findDefinitionAtRange(document, rng)
+		if symbol == "" || symbol == node.Symbol || scip.IsLocalSymbol(symbol) {
+			continue
+		}
+
+		subtype, ok := bySymbol[symbol]
+		if !ok {
+			subtype = &TypeHierarchyNode{Symbol: symbol}
+			bySymbol[symbol] = subtype
+			subtypes = append(subtypes, subtype)
+		}
+		subtype.Definitions = append(subtype.Definitions, implementation)
+	}
+
+	return subtypes, nil
+}
+
+// findDefinitionAtRange returns the symbol defined exactly at the given range, if any.
+func findDefinitionAtRange(document *scip.Document, rng shared.Range) string {
+	for _, occurrence := range document.Occurrences {
+		if scip.SymbolRole_Definition.Matches(occurrence) && translateSCIPRange(occurrence.Range) == rng {
+			return occurrence.Symbol
+		}
+	}
+
+	return ""
+}
+
+// pruneTypeHierarchy removes the descendants of the given node which are neither kept nor have
+// a kept descendant. The return value indicates whether the node itself should be retained.
+func pruneTypeHierarchy(node *TypeHierarchyNode, keep func(node *TypeHierarchyNode) bool) bool {
+	children := node.Children[:0]
+	for _, child := range node.Children {
+		if pruneTypeHierarchy(child, keep) {
+			children = append(children, child)
+		}
+	}
+	node.Children = children
+
+	return keep(node) || len(children) > 0
+}
@@ -0,0 +1,224 @@
+package codenav
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/sourcegraph/scip/bindings/go/scip"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/codeintel/codenav/shared"
+	uploadsshared "github.com/sourcegraph/sourcegraph/internal/codeintel/uploads/shared"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver"
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+	sgtypes "github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/lib/codeintel/precise"
+)
+
+const (
+	baseTypeSymbol      = "scip-go gomod github.com/example/m v1 `github.com/example/m`/Base#"
+	interfaceTypeSymbol = "scip-go gomod github.com/example/m v1 `github.com/example/m`/Interface#"
+	structTypeSymbol    = "scip-go gomod github.com/example/m v1 `github.com/example/m`/Struct#"
+	variableSymbol      = "scip-go gomod github.com/example/m v1 `github.com/example/m`/value."
+)
+
+// typeHierarchyDocument models a document in which Struct implements Interface, which in turn
+// implements (embeds) Base. The variable value is of type Struct.
+var typeHierarchyDocument = &scip.Document{
+	RelativePath: "a.go",
+	Occurrences: []*scip.Occurrence{
+		{Range: []int32{0, 5, 14}, Symbol: interfaceTypeSymbol, SymbolRoles: int32(scip.SymbolRole_Definition)},
+		{Range: []int32{2, 5, 11}, Symbol: structTypeSymbol, SymbolRoles: int32(scip.SymbolRole_Definition)},
+		{Range: []int32{4, 5, 9}, Symbol: baseTypeSymbol, SymbolRoles: int32(scip.SymbolRole_Definition)},
+		{Range: []int32{6, 4, 9}, Symbol: variableSymbol, SymbolRoles: int32(scip.SymbolRole_Definition)},
+	},
+	Symbols: []*scip.SymbolInformation{
+		{Symbol: structTypeSymbol, Relationships: []*scip.Relationship{{Symbol: interfaceTypeSymbol, IsImplementation: true}}},
+		{Symbol: interfaceTypeSymbol, Relationships: []*scip.Relationship{{Symbol: baseTypeSymbol, IsImplementation: true}}},
+		{Symbol: variableSymbol, Relationships: []*scip.Relationship{{Symbol: structTypeSymbol, IsTypeDefinition: true}}},
+	},
+}
+
+var typeHierarchyDefinitionRanges = map[string]shared.Range{
+	interfaceTypeSymbol: newCallHierarchyRange(0, 5, 14),
+	structTypeSymbol:    newCallHierarchyRange(2, 5, 11),
+	baseTypeSymbol:      newCallHierarchyRange(4, 5, 9),
+}
+
+func TestGetTypeHierarchy(t *testing.T) {
+	// The upload defining all types belongs to another repository, so locations are not adjusted
+	remoteUpload := uploadsshared.Dump{ID: 150, RepositoryID: 43, Commit: "deadbeef"}
+	definition := func(symbol string) shared.UploadLocation {
+		return shared.UploadLocation{Dump: remoteUpload, Path: "a.go", TargetCommit: "deadbeef", TargetRange: typeHierarchyDefinitionRanges[symbol]}
+	}
+
+	newTestService := func() (*Service, RequestState) {
+		// Set up mocks
+		mockRepoStore := defaultMockRepoStore()
+		mockLsifStore := NewMockLsifStore()
+		mockUploadSvc := NewMockUploadService()
+		mockGitserverClient := gitserver.NewMockClient()
+		hunkCache, _ := NewHunkCache(50)
+
+		// Init service
+		svc := newService(&observation.TestContext, mockRepoStore, mockLsifStore, mockUploadSvc, mockGitserverClient, nil, nil)
+
+		// Set up request state
+		mockRequestState := RequestState{}
+		mockRequestState.SetLocalCommitCache(mockRepoStore, mockGitserverClient)
+		mockRequestState.SetLocalGitTreeTranslator(mockGitserverClient, &sgtypes.Repo{}, mockCommit, mockPath, hunkCache)
+		mockRequestState.SetUploadsDataLoader([]uploadsshared.Dump{
+			{ID: 50, RepositoryID: 42, Commit: mockCommit, Root: "sub1/"},
+		})
+
+		mockGitserverClient.CommitsExistFunc.SetDefaultHook(func(ctx context.Context, rcs []api.RepoCommit) (exists []bool, _ error) {
+			for range rcs {
+				exists = append(exists, true)
+			}
+			return
+		})
+		mockUploadSvc.GetDumpsWithDefinitionsForMonikersFunc.SetDefaultReturn([]uploadsshared.Dump{remoteUpload}, nil)
+		mockLsifStore.SCIPDocumentFunc.SetDefaultReturn(typeHierarchyDocument, nil)
+		mockLsifStore.GetMinimalBulkMonikerLocationsFunc.SetDefaultHook(func(_ context.Context, tableName string, _ []int, _ map[int]string, monikers []precise.MonikerData, _, _ int) ([]shared.Location, int, error) {
+			var locations []shared.Location
+			for _, moniker := range monikers {
+				switch tableName {
+				case "definitions":
+					locations = append(locations, shared.Location{DumpID: 150, Path: "a.go", Range: typeHierarchyDefinitionRanges[moniker.Identifier]})
+				case "implementations":
+					for _, symbol := range typeHierarchyDocument.Symbols {
+						for _, relationship := range symbol.Relationships {
+							if relationship.IsImplementation && relationship.Symbol == moniker.Identifier {
+								locations = append(locations, shared.Location{DumpID: 150, Path: "a.go", Range: typeHierarchyDefinitionRanges[symbol.Symbol]})
+							}
+						}
+					}
+				}
+			}
+			return locations, len(locations), nil
+		})
+
+		return svc, mockRequestState
+	}
+
+	newArgs := func(line, character int, direction TypeHierarchyDirection, depth, limit int) TypeHierarchyArgs {
+		return TypeHierarchyArgs{
+			PositionalRequestArgs: PositionalRequestArgs{
+				RequestArgs: RequestArgs{
+					RepositoryID: 42,
+					Commit:       mockCommit,
+					Limit:        limit,
+				},
+				Path:      mockPath,
+				Line:      line,
+				Character: character,
+			},
+			Direction: direction,
+			Depth:     depth,
+		}
+	}
+
+	t.Run("supertypes", func(t *testing.T) {
+		svc, requestState := newTestService()
+
+		root, nextCursor, err := svc.GetTypeHierarchy(context.Background(), newArgs(2, 6, TypeHierarchySupertypes, 5, 50), requestState, TypeHierarchyCursor{})
+		if err != nil {
+			t.Fatalf("unexpected error querying type hierarchy: %s", err)
+		}
+		if nextCursor != nil {
+			t.Errorf("unexpected cursor: %v", nextCursor)
+		}
+
+		expected := &TypeHierarchyNode{
+			Symbol:      structTypeSymbol,
+			Definitions: []shared.UploadLocation{definition(structTypeSymbol)},
+			Children: []*TypeHierarchyNode{{
+				Symbol:      interfaceTypeSymbol,
+				Definitions: []shared.UploadLocation{definition(interfaceTypeSymbol)},
+				Children: []*TypeHierarchyNode{{
+					Symbol:      baseTypeSymbol,
+					Definitions: []shared.UploadLocation{definition(baseTypeSymbol)},
+				}},
+			}},
+		}
+		if diff := cmp.Diff(expected, root); diff != "" {
+			t.Errorf("unexpected hierarchy (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("depth", func(t *testing.T) {
+		svc, requestState := newTestService()
+
+		// The variable resolves to its type definition
+		root, _, err := svc.GetTypeHierarchy(context.Background(), newArgs(6, 5, TypeHierarchySupertypes, 1, 50), requestState, TypeHierarchyCursor{})
+		if err != nil {
+			t.Fatalf("unexpected error querying type hierarchy: %s", err)
+		}
+
+		expected := &TypeHierarchyNode{
+			Symbol:      structTypeSymbol,
+			Definitions: []shared.UploadLocation{definition(structTypeSymbol)},
+			Children: []*TypeHierarchyNode{{
+				Symbol:      interfaceTypeSymbol,
+				Definitions: []shared.UploadLocation{definition(interfaceTypeSymbol)},
+			}},
+		}
+		if diff := cmp.Diff(expected, root); diff != "" {
+			t.Errorf("unexpected hierarchy (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("pagination", func(t *testing.T) {
+		svc, requestState := newTestService()
+		args := newArgs(2, 6, TypeHierarchySupertypes, 5, 1)
+
+		root, nextCursor, err := svc.GetTypeHierarchy(context.Background(), args, requestState, TypeHierarchyCursor{})
+		if err != nil {
+			t.Fatalf("unexpected error querying type hierarchy: %s", err)
+		}
+		if diff := cmp.Diff(&TypeHierarchyCursor{Offset: 1}, nextCursor); diff != "" {
+			t.Errorf("unexpected cursor (-want +got):\n%s", diff)
+		}
+		if len(root.Children) != 1 || len(root.Children[0].Children) != 0 {
+			t.Errorf("unexpected first page: %v", root)
+		}
+
+		// The second page repeats the ancestors of its nodes
+		root, nextCursor, err = svc.GetTypeHierarchy(context.Background(), args, requestState, *nextCursor)
+		if err != nil {
+			t.Fatalf("unexpected error querying type hierarchy: %s", err)
+		}
+		if nextCursor != nil {
+			t.Errorf("unexpected cursor: %v", nextCursor)
+		}
+		if len(root.Children) != 1 || len(root.Children[0].Children) != 1 || root.Children[0].Children[0].Symbol != baseTypeSymbol {
+			t.Errorf("unexpected second page: %v", root)
+		}
+	})
+
+	t.Run("subtypes", func(t *testing.T) {
+		svc, requestState := newTestService()
+
+		root, _, err := svc.GetTypeHierarchy(context.Background(), newArgs(4, 6, TypeHierarchySubtypes, 5, 50), requestState, TypeHierarchyCursor{})
+		if err != nil {
+			t.Fatalf("unexpected error querying type hierarchy: %s", err)
+		}
+
+		expected := &TypeHierarchyNode{
+			Symbol:      baseTypeSymbol,
+			Definitions: []shared.UploadLocation{definition(baseTypeSymbol)},
+			Children: []*TypeHierarchyNode{{
+				Symbol:      interfaceTypeSymbol,
+				Definitions: []shared.UploadLocation{definition(interfaceTypeSymbol)},
+				Children: []*TypeHierarchyNode{{
+					Symbol:      structTypeSymbol,
+					Definitions: []shared.UploadLocation{definition(structTypeSymbol)},
+				}},
+			}},
+		}
+		if diff := cmp.Diff(expected, root); diff != "" {
+			t.Errorf("unexpected hierarchy (-want +got):\n%s", diff)
+		}
+	})
+}
@@ -38,6 +38,15 @@ type UploadLocation struct {
 	TargetRange  Range
 }
 
+// SearchBasedLocation is a path and range pair within the requested repository and commit which
+// was inferred via symbol search and language-aware scoping heuristics rather than read from a
+// precise index. Locations with a higher score are more likely to be correct.
+type SearchBasedLocation struct {
+	Path  string
+	Range Range
+	Score int
+}
+
 type SnapshotData struct {
 	DocumentOffset int
 	Symbol         string
@@ -14,12 +14,19 @@ type CodeNavService interface {
 	GetImplementations(ctx context.Context, args codenav.PositionalRequestArgs, requestState codenav.RequestState, cursor codenav.Cursor) (_ []shared.UploadLocation, nextCursor codenav.Cursor, err error)
 	GetPrototypes(ctx context.Context, args codenav.PositionalRequestArgs, requestState codenav.RequestState, cursor codenav.Cursor) (_ []shared.UploadLocation, nextCursor codenav.Cursor, err error)
 	GetDefinitions(ctx context.Context, args codenav.PositionalRequestArgs, requestState codenav.RequestState) (_ []shared.UploadLocation, err error)
+	GetIncomingCalls(ctx context.Context, args codenav.PositionalRequestArgs, requestState codenav.RequestState) (_ []codenav.CallHierarchyItem, err error)
+	GetOutgoingCalls(ctx context.Context, args codenav.PositionalRequestArgs, requestState codenav.RequestState) (_ []codenav.CallHierarchyItem, err error)
+	GetRenameEdits(ctx context.Context, args codenav.PositionalRequestArgs, requestState codenav.RequestState) (_ *codenav.RenameEditSet, err error)
+	GenerateRenameDiffs(ctx context.Context, editSet *codenav.RenameEditSet, newName string, requestState codenav.RequestState) (_ []codenav.RepositoryRenameDiff, err error)
+	GetTypeHierarchy(ctx context.Context, args codenav.TypeHierarchyArgs, requestState codenav.RequestState, cursor codenav.TypeHierarchyCursor) (_ *codenav.TypeHierarchyNode, nextCursor *codenav.TypeHierarchyCursor, err error)
 	GetDiagnostics(ctx context.Context, args codenav.PositionalRequestArgs, requestState codenav.RequestState) (diagnosticsAtUploads []codenav.DiagnosticAtUpload, _ int, err error)
 	GetRanges(ctx context.Context, args codenav.PositionalRequestArgs, requestState codenav.RequestState, startLine, endLine int) (adjustedRanges []codenav.AdjustedCodeIntelligenceRange, err error)
 	GetStencil(ctx context.Context, args codenav.PositionalRequestArgs, requestState codenav.RequestState) (adjustedRanges []shared.Range, err error)
 	GetClosestDumpsForBlob(ctx context.Context, repositoryID int, commit, path string, exactPath bool, indexer string) (_ []uploadsshared.Dump, err error)
 	VisibleUploadsForPath(ctx context.Context, requestState codenav.RequestState) ([]uploadsshared.Dump, error)
 	SnapshotForDocument(ctx context.Context, repositoryID int, commit, path string, uploadID int) (data []shared.SnapshotData, err error)
+	GetSearchBasedDefinitions(ctx context.Context, args codenav.PositionalRequestArgs, requestState codenav.RequestState) (_ []shared.SearchBasedLocation, err error)
+	GetSearchBasedReferences(ctx context.Context, args codenav.PositionalRequestArgs, requestState codenav.RequestState) (_ []shared.SearchBasedLocation, err error)
 }
 
 type AutoIndexingService interface {
@@ -178,6 +178,9 @@ func (c AutoIndexingServiceQueueRepoRevFuncCall) Results() []interface{} {
 // github.com/sourcegraph/sourcegraph/internal/codeintel/codenav/transport/graphql)
 // used for unit testing.
 type MockCodeNavService struct {
+	// GenerateRenameDiffsFunc is an instance of a mock function object
+	// controlling the behavior of the method GenerateRenameDiffs.
+	GenerateRenameDiffsFunc *CodeNavServiceGenerateRenameDiffsFunc
 	// GetClosestDumpsForBlobFunc is an instance of a mock function object
 	// controlling the behavior of the method GetClosestDumpsForBlob.
 	GetClosestDumpsForBlobFunc *CodeNavServiceGetClosestDumpsForBlobFunc
@@ -193,6 +196,12 @@ type MockCodeNavService struct {
 	// GetImplementationsFunc is an instance of a mock function object
 	// controlling the behavior of the method GetImplementations.
 	GetImplementationsFunc *CodeNavServiceGetImplementationsFunc
+	// GetIncomingCallsFunc is an instance of a mock function object
+	// controlling the behavior of the method GetIncomingCalls.
+	GetIncomingCallsFunc *CodeNavServiceGetIncomingCallsFunc
+	// GetOutgoingCallsFunc is an instance of a mock function object
+	// controlling the behavior of the method GetOutgoingCalls.
+	GetOutgoingCallsFunc *CodeNavServiceGetOutgoingCallsFunc
 	// GetPrototypesFunc is an instance of a mock function object
 	// controlling the behavior of the method GetPrototypes.
 	GetPrototypesFunc *CodeNavServiceGetPrototypesFunc
@@ -202,9 +211,22 @@ type MockCodeNavService struct {
 	// GetReferencesFunc is an instance of a mock function object
 	// controlling the behavior of the method GetReferences.
 	GetReferencesFunc *CodeNavServiceGetReferencesFunc
+	// GetRenameEditsFunc is an instance of a mock function object
+	// controlling the behavior of the method GetRenameEdits.
+	GetRenameEditsFunc *CodeNavServiceGetRenameEditsFunc
+	// GetSearchBasedDefinitionsFunc is an instance of a mock function
+	// object controlling the behavior of the method
+	// GetSearchBasedDefinitions.
+	GetSearchBasedDefinitionsFunc *CodeNavServiceGetSearchBasedDefinitionsFunc
+	// GetSearchBasedReferencesFunc is an instance of a mock function object
+	// controlling the behavior of the method GetSearchBasedReferences.
+	GetSearchBasedReferencesFunc *CodeNavServiceGetSearchBasedReferencesFunc
 	// GetStencilFunc is an instance of a mock function object controlling
 	// the behavior of the method GetStencil.
 	GetStencilFunc *CodeNavServiceGetStencilFunc
+	// GetTypeHierarchyFunc is an instance of a mock function object
+	// controlling the behavior of the method GetTypeHierarchy.
+	GetTypeHierarchyFunc *CodeNavServiceGetTypeHierarchyFunc
 	// SnapshotForDocumentFunc is an instance of a mock function object
 	// controlling the behavior of the method SnapshotForDocument.
 	SnapshotForDocumentFunc *CodeNavServiceSnapshotForDocumentFunc
@@ -217,6 +239,11 @@ type MockCodeNavService struct {
 // All methods return zero values for all results, unless overwritten.
 func NewMockCodeNavService() *MockCodeNavService {
 	return &MockCodeNavService{
+		GenerateRenameDiffsFunc: &CodeNavServiceGenerateRenameDiffsFunc{
+			defaultHook: func(context.Context, *codenav.RenameEditSet, string, codenav.RequestState) (r0 []codenav.RepositoryRenameDiff, r1 error) {
+				return
+			},
+		},
 		GetClosestDumpsForBlobFunc: &CodeNavServiceGetClosestDumpsForBlobFunc{
 			defaultHook: func(context.Context, int, string, string, bool, string) (r0 []shared.Dump, r1 error) {
 				return
@@ -242,6 +269,16 @@ func NewMockCodeNavService() *MockCodeNavService {
 				return
 			},
 		},
+		GetIncomingCallsFunc: &CodeNavServiceGetIncomingCallsFunc{
+			defaultHook: func(context.Context, codenav.PositionalRequestArgs, codenav.RequestState) (r0 []codenav.CallHierarchyItem, r1 error) {
+				return
+			},
+		},
+		GetOutgoingCallsFunc: &CodeNavServiceGetOutgoingCallsFunc{
+			defaultHook: func(context.Context, codenav.PositionalRequestArgs, codenav.RequestState) (r0 []codenav.CallHierarchyItem, r1 error) {
+				return
+			},
+		},
 		GetPrototypesFunc: &CodeNavServiceGetPrototypesFunc{
 			defaultHook: func(context.Context, codenav.PositionalRequestArgs, codenav.RequestState, codenav.Cursor) (r0 []shared1.UploadLocation, r1 codenav.Cursor, r2 error) {
 				return
@@ -257,11 +294,31 @@ func NewMockCodeNavService() *MockCodeNavService {
 				return
 			},
 		},
+		GetRenameEditsFunc: &CodeNavServiceGetRenameEditsFunc{
+			defaultHook: func(context.Context, codenav.PositionalRequestArgs, codenav.RequestState) (r0 *codenav.RenameEditSet, r1 error) {
+				return
+			},
+		},
+		GetSearchBasedDefinitionsFunc: &CodeNavServiceGetSearchBasedDefinitionsFunc{
+			defaultHook: func(context.Context, codenav.PositionalRequestArgs, codenav.RequestState) (r0 []shared1.SearchBasedLocation, r1 error) {
+				return
+			},
+		},
+		GetSearchBasedReferencesFunc: &CodeNavServiceGetSearchBasedReferencesFunc{
+			defaultHook: func(context.Context, codenav.PositionalRequestArgs, codenav.RequestState) (r0 []shared1.SearchBasedLocation, r1 error) {
+				return
+			},
+		},
 		GetStencilFunc: &CodeNavServiceGetStencilFunc{
 			defaultHook: func(context.Context, codenav.PositionalRequestArgs, codenav.RequestState) (r0 []shared1.Range, r1 error) {
 				return
 			},
 		},
+		GetTypeHierarchyFunc: &CodeNavServiceGetTypeHierarchyFunc{
+			defaultHook: func(context.Context, codenav.TypeHierarchyArgs, codenav.RequestState, codenav.TypeHierarchyCursor) (r0 *codenav.TypeHierarchyNode, r1 *codenav.TypeHierarchyCursor, r2 error) {
+				return
+			},
+		},
 		SnapshotForDocumentFunc: &CodeNavServiceSnapshotForDocumentFunc{
 			defaultHook: func(context.Context, int, string, string, int) (r0 []shared1.SnapshotData, r1 error) {
 				return
@@ -279,6 +336,11 @@ func NewMockCodeNavService() *MockCodeNavService {
 // interface. All methods panic on invocation, unless overwritten.
 func NewStrictMockCodeNavService() *MockCodeNavService {
 	return &MockCodeNavService{
+		GenerateRenameDiffsFunc: &CodeNavServiceGenerateRenameDiffsFunc{
+			defaultHook: func(context.Context, *codenav.RenameEditSet, string, codenav.RequestState) ([]codenav.RepositoryRenameDiff, error) {
+				panic("unexpected invocation of MockCodeNavService.GenerateRenameDiffs")
+			},
+		},
 		GetClosestDumpsForBlobFunc: &CodeNavServiceGetClosestDumpsForBlobFunc{
 			defaultHook: func(context.Context, int, string, string, bool, string) ([]shared.Dump, error) {
 				panic("unexpected invocation of MockCodeNavService.GetClosestDumpsForBlob")
@@ -304,6 +366,16 @@ func NewStrictMockCodeNavService() *MockCodeNavService {
 				panic("unexpected invocation of MockCodeNavService.GetImplementations")
 			},
 		},
+		GetIncomingCallsFunc: &CodeNavServiceGetIncomingCallsFunc{
+			defaultHook: func(context.Context, codenav.PositionalRequestArgs, codenav.RequestState) ([]codenav.CallHierarchyItem, error) {
+				panic("unexpected invocation of MockCodeNavService.GetIncomingCalls")
+			},
+		},
+		GetOutgoingCallsFunc: &CodeNavServiceGetOutgoingCallsFunc{
+			defaultHook: func(context.Context, codenav.PositionalRequestArgs, codenav.RequestState) ([]codenav.CallHierarchyItem, error) {
+				panic("unexpected invocation of MockCodeNavService.GetOutgoingCalls")
+			},
+		},
 		GetPrototypesFunc: &CodeNavServiceGetPrototypesFunc{
 			defaultHook: func(context.Context, codenav.PositionalRequestArgs, codenav.RequestState, codenav.Cursor) ([]shared1.UploadLocation, codenav.Cursor, error) {
 				panic("unexpected invocation of MockCodeNavService.GetPrototypes")
@@ -319,11 +391,31 @@ func NewStrictMockCodeNavService() *MockCodeNavService {
 				panic("unexpected invocation of MockCodeNavService.GetReferences")
 			},
 		},
+		GetRenameEditsFunc: &CodeNavServiceGetRenameEditsFunc{
+			defaultHook: func(context.Context, codenav.PositionalRequestArgs, codenav.RequestState) (*codenav.RenameEditSet, error) {
+				panic("unexpected invocation of MockCodeNavService.GetRenameEdits")
+			},
+		},
+		GetSearchBasedDefinitionsFunc: &CodeNavServiceGetSearchBasedDefinitionsFunc{
+			defaultHook: func(context.Context, codenav.PositionalRequestArgs, codenav.RequestState) ([]shared1.SearchBasedLocation, error) {
+				panic("unexpected invocation of MockCodeNavService.GetSearchBasedDefinitions")
+			},
+		},
+		GetSearchBasedReferencesFunc: &CodeNavServiceGetSearchBasedReferencesFunc{
+			defaultHook: func(context.Context, codenav.PositionalRequestArgs, codenav.RequestState) ([]shared1.SearchBasedLocation, error) {
+				panic("unexpected invocation of MockCodeNavService.GetSearchBasedReferences")
+			},
+		},
 		GetStencilFunc: &CodeNavServiceGetStencilFunc{
 			defaultHook: func(context.Context, codenav.PositionalRequestArgs, codenav.RequestState) ([]shared1.Range, error) {
 				panic("unexpected invocation of MockCodeNavService.GetStencil")
 			},
 		},
+		GetTypeHierarchyFunc: &CodeNavServiceGetTypeHierarchyFunc{
+			defaultHook: func(context.Context, codenav.TypeHierarchyArgs, codenav.RequestState, codenav.TypeHierarchyCursor) (*codenav.TypeHierarchyNode, *codenav.TypeHierarchyCursor, error) {
+				panic("unexpected invocation of MockCodeNavService.GetTypeHierarchy")
+			},
+		},
 		SnapshotForDocumentFunc: &CodeNavServiceSnapshotForDocumentFunc{
 			defaultHook: func(context.Context, int, string, string, int) ([]shared1.SnapshotData, error) {
 				panic("unexpected invocation of MockCodeNavService.SnapshotForDocument")
@@ -342,6 +434,9 @@ func NewStrictMockCodeNavService() *MockCodeNavService {
 // overwritten.
 func NewMockCodeNavServiceFrom(i CodeNavService) *MockCodeNavService {
 	return &MockCodeNavService{
+		GenerateRenameDiffsFunc: &CodeNavServiceGenerateRenameDiffsFunc{
+			defaultHook: i.GenerateRenameDiffs,
+		},
 		GetClosestDumpsForBlobFunc: &CodeNavServiceGetClosestDumpsForBlobFunc{
 			defaultHook: i.GetClosestDumpsForBlob,
 		},
@@ -357,6 +452,12 @@ func NewMockCodeNavServiceFrom(i CodeNavService) *MockCodeNavService {
 		GetImplementationsFunc: &CodeNavServiceGetImplementationsFunc{
 			defaultHook: i.GetImplementations,
 		},
+		GetIncomingCallsFunc: &CodeNavServiceGetIncomingCallsFunc{
+			defaultHook: i.GetIncomingCalls,
+		},
+		GetOutgoingCallsFunc: &CodeNavServiceGetOutgoingCallsFunc{
+			defaultHook: i.GetOutgoingCalls,
+		},
 		GetPrototypesFunc: &CodeNavServiceGetPrototypesFunc{
 			defaultHook: i.GetPrototypes,
 		},
@@ -366,9 +467,21 @@ func NewMockCodeNavServiceFrom(i CodeNavService) *MockCodeNavService {
 		GetReferencesFunc: &CodeNavServiceGetReferencesFunc{
 			defaultHook: i.GetReferences,
 		},
+		GetRenameEditsFunc: &CodeNavServiceGetRenameEditsFunc{
+			defaultHook: i.GetRenameEdits,
+		},
+		GetSearchBasedDefinitionsFunc: &CodeNavServiceGetSearchBasedDefinitionsFunc{
+			defaultHook: i.GetSearchBasedDefinitions,
+		},
+		GetSearchBasedReferencesFunc: &CodeNavServiceGetSearchBasedReferencesFunc{
+			defaultHook: i.GetSearchBasedReferences,
+		},
 		GetStencilFunc: &CodeNavServiceGetStencilFunc{
 			defaultHook: i.GetStencil,
 		},
+		GetTypeHierarchyFunc: &CodeNavServiceGetTypeHierarchyFunc{
+			defaultHook: i.GetTypeHierarchy,
+		},
 		SnapshotForDocumentFunc: &CodeNavServiceSnapshotForDocumentFunc{
 			defaultHook: i.SnapshotForDocument,
 		},
@@ -378,6 +491,123 @@ func NewMockCodeNavServiceFrom(i CodeNavService) *MockCodeNavService {
 	}
 }
 
+// CodeNavServiceGenerateRenameDiffsFunc describes the behavior when the
+// GenerateRenameDiffs method of the parent MockCodeNavService instance is
+// invoked.
+type CodeNavServiceGenerateRenameDiffsFunc struct {
+	defaultHook func(context.Context, *codenav.RenameEditSet, string, codenav.RequestState) ([]codenav.RepositoryRenameDiff, error)
+	hooks       []func(context.Context, *codenav.RenameEditSet, string, codenav.RequestState) ([]codenav.RepositoryRenameDiff, error)
+	history     []CodeNavServiceGenerateRenameDiffsFuncCall
+	mutex       sync.Mutex
+}
+
+// GenerateRenameDiffs delegates to the next hook function in the queue and
+// stores the parameter and result values of this invocation.
+func (m *MockCodeNavService) GenerateRenameDiffs(v0 context.Context, v1 *codenav.RenameEditSet, v2 string, v3 codenav.RequestState) ([]codenav.RepositoryRenameDiff, error) {
+	r0, r1 := m.GenerateRenameDiffsFunc.nextHook()(v0, v1, v2, v3)
+	m.GenerateRenameDiffsFunc.appendCall(CodeNavServiceGenerateRenameDiffsFuncCall{v0, v1, v2, v3, r0, r1})
+	return r0, r1
+}
+
+// SetDefaultHook sets function that is called when the GenerateRenameDiffs
+// method of the parent MockCodeNavService instance is invoked and the hook
+// queue is empty.
+func (f *CodeNavServiceGenerateRenameDiffsFunc) SetDefaultHook(hook func(context.Context, *codenav.RenameEditSet, string, codenav.RequestState) ([]codenav.RepositoryRenameDiff, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// GenerateRenameDiffs method of the parent MockCodeNavService instance
+// invokes the hook at the front of the queue and discards it. After the
+// queue is empty, the default hook function is invoked for any future
+// action.
+func (f *CodeNavServiceGenerateRenameDiffsFunc) PushHook(hook func(context.Context, *codenav.RenameEditSet, string, codenav.RequestState) ([]codenav.RepositoryRenameDiff, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultHook with a function that returns the
+// given values.
+func (f *CodeNavServiceGenerateRenameDiffsFunc) SetDefaultReturn(r0 []codenav.RepositoryRenameDiff, r1 error) {
+	f.SetDefaultHook(func(context.Context, *codenav.RenameEditSet, string, codenav.RequestState) ([]codenav.RepositoryRenameDiff, error) {
+		return r0, r1
+	})
+}
+
+// PushReturn calls PushHook with a function that returns the given values.
+func (f *CodeNavServiceGenerateRenameDiffsFunc) PushReturn(r0 []codenav.RepositoryRenameDiff, r1 error) {
+	f.PushHook(func(context.Context, *codenav.RenameEditSet, string, codenav.RequestState) ([]codenav.RepositoryRenameDiff, error) {
+		return r0, r1
+	})
+}
+
+func (f *CodeNavServiceGenerateRenameDiffsFunc) nextHook() func(context.Context, *codenav.RenameEditSet, string, codenav.RequestState) ([]codenav.RepositoryRenameDiff, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *CodeNavServiceGenerateRenameDiffsFunc) appendCall(r0 CodeNavServiceGenerateRenameDiffsFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of CodeNavServiceGenerateRenameDiffsFuncCall
+// objects describing the invocations of this function.
+func (f *CodeNavServiceGenerateRenameDiffsFunc) History() []CodeNavServiceGenerateRenameDiffsFuncCall {
+	f.mutex.Lock()
+	history := make([]CodeNavServiceGenerateRenameDiffsFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// CodeNavServiceGenerateRenameDiffsFuncCall is an object that describes an
+// invocation of method GenerateRenameDiffs on an instance of
+// MockCodeNavService.
+type CodeNavServiceGenerateRenameDiffsFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 *codenav.RenameEditSet
+	// Arg2 is the value of the 3rd argument passed to this method
+	// invocation.
+	Arg2 string
+	// Arg3 is the value of the 4th argument passed to this method
+	// invocation.
+	Arg3 codenav.RequestState
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 []codenav.RepositoryRenameDiff
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c CodeNavServiceGenerateRenameDiffsFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1, c.Arg2, c.Arg3}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c CodeNavServiceGenerateRenameDiffsFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1}
+}
+
 // CodeNavServiceGetClosestDumpsForBlobFunc describes the behavior when the
 // GetClosestDumpsForBlob method of the parent MockCodeNavService instance
 // is invoked.
@@ -966,36 +1196,36 @@ func (c CodeNavServiceGetImplementationsFuncCall) Results() []interface{} {
 	return []interface{}{c.Result0, c.Result1, c.Result2}
 }
 
-// CodeNavServiceGetPrototypesFunc describes the behavior when the
-// GetPrototypes method of the parent MockCodeNavService instance is
+// CodeNavServiceGetIncomingCallsFunc describes the behavior when the
+// GetIncomingCalls method of the parent MockCodeNavService instance is
 // invoked.
-type CodeNavServiceGetPrototypesFunc struct {
-	defaultHook func(context.Context, codenav.PositionalRequestArgs, codenav.RequestState, codenav.Cursor) ([]shared1.UploadLocation, codenav.Cursor, error)
-	hooks       []func(context.Context, codenav.PositionalRequestArgs, codenav.RequestState, codenav.Cursor) ([]shared1.UploadLocation, codenav.Cursor, error)
-	history     []CodeNavServiceGetPrototypesFuncCall
+type CodeNavServiceGetIncomingCallsFunc struct {
+	defaultHook func(context.Context, codenav.PositionalRequestArgs, codenav.RequestState) ([]codenav.CallHierarchyItem, error)
+	hooks       []func(context.Context, codenav.PositionalRequestArgs, codenav.RequestState) ([]codenav.CallHierarchyItem, error)
+	history     []CodeNavServiceGetIncomingCallsFuncCall
 	mutex       sync.Mutex
 }
 
-// GetPrototypes delegates to the next hook function in the queue and stores
-// the parameter and result values of this invocation.
-func (m *MockCodeNavService) GetPrototypes(v0 context.Context, v1 codenav.PositionalRequestArgs, v2 codenav.RequestState, v3 codenav.Cursor) ([]shared1.UploadLocation, codenav.Cursor, error) {
-	r0, r1, r2 := m.GetPrototypesFunc.nextHook()(v0, v1, v2, v3)
-	m.GetPrototypesFunc.appendCall(CodeNavServiceGetPrototypesFuncCall{v0, v1, v2, v3, r0, r1, r2})
-	return r0, r1, r2
+// GetIncomingCalls delegates to the next hook function in the queue and
+// stores the parameter and result values of this invocation.
+func (m *MockCodeNavService) GetIncomingCalls(v0 context.Context, v1 codenav.PositionalRequestArgs, v2 codenav.RequestState) ([]codenav.CallHierarchyItem, error) {
+	r0, r1 := m.GetIncomingCallsFunc.nextHook()(v0, v1, v2)
+	m.GetIncomingCallsFunc.appendCall(CodeNavServiceGetIncomingCallsFuncCall{v0, v1, v2, r0, r1})
+	return r0, r1
 }
 
-// SetDefaultHook sets function that is called when the GetPrototypes method
-// of the parent MockCodeNavService instance is invoked and the hook queue
-// is empty.
-func (f *CodeNavServiceGetPrototypesFunc) SetDefaultHook(hook func(context.Context, codenav.PositionalRequestArgs, codenav.RequestState, codenav.Cursor) ([]shared1.UploadLocation, codenav.Cursor, error)) {
+// SetDefaultHook sets function that is called when the GetIncomingCalls
+// method of the parent MockCodeNavService instance is invoked and the hook
+// queue is empty.
+func (f *CodeNavServiceGetIncomingCallsFunc) SetDefaultHook(hook func(context.Context, codenav.PositionalRequestArgs, codenav.RequestState) ([]codenav.CallHierarchyItem, error)) {
 	f.defaultHook = hook
 }
 
 // PushHook adds a function to the end of hook queue. Each invocation of the
-// GetPrototypes method of the parent MockCodeNavService instance invokes
+// GetIncomingCalls method of the parent MockCodeNavService instance invokes
 // the hook at the front of the queue and discards it. After the queue is
 // empty, the default hook function is invoked for any future action.
-func (f *CodeNavServiceGetPrototypesFunc) PushHook(hook func(context.Context, codenav.PositionalRequestArgs, codenav.RequestState, codenav.Cursor) ([]shared1.UploadLocation, codenav.Cursor, error)) {
+func (f *CodeNavServiceGetIncomingCallsFunc) PushHook(hook func(context.Context, codenav.PositionalRequestArgs, codenav.RequestState) ([]codenav.CallHierarchyItem, error)) {
 	f.mutex.Lock()
 	f.hooks = append(f.hooks, hook)
 	f.mutex.Unlock()
@@ -1003,20 +1233,20 @@ func (f *CodeNavServiceGetPrototypesFunc) PushHook(hook func(context.Context, co
 
 // SetDefaultReturn calls SetDefaultHook with a function that returns the
 // given values.
-func (f *CodeNavServiceGetPrototypesFunc) SetDefaultReturn(r0 []shared1.UploadLocation, r1 codenav.Cursor, r2 error) {
-	f.SetDefaultHook(func(context.Context, codenav.PositionalRequestArgs, codenav.RequestState, codenav.Cursor) ([]shared1.UploadLocation, codenav.Cursor, error) {
-		return r0, r1, r2
+func (f *CodeNavServiceGetIncomingCallsFunc) SetDefaultReturn(r0 []codenav.CallHierarchyItem, r1 error) {
+	f.SetDefaultHook(func(context.Context, codenav.PositionalRequestArgs, codenav.RequestState) ([]codenav.CallHierarchyItem, error) {
+		return r0, r1
 	})
 }
 
 // PushReturn calls PushHook with a function that returns the given values.
-func (f *CodeNavServiceGetPrototypesFunc) PushReturn(r0 []shared1.UploadLocation, r1 codenav.Cursor, r2 error) {
-	f.PushHook(func(context.Context, codenav.PositionalRequestArgs, codenav.RequestState, codenav.Cursor) ([]shared1.UploadLocation, codenav.Cursor, error) {
-		return r0, r1, r2
+func (f *CodeNavServiceGetIncomingCallsFunc) PushReturn(r0 []codenav.CallHierarchyItem, r1 error) {
+	f.PushHook(func(context.Context, codenav.PositionalRequestArgs, codenav.RequestState) ([]codenav.CallHierarchyItem, error) {
+		return r0, r1
 	})
 }
 
-func (f *CodeNavServiceGetPrototypesFunc) nextHook() func(context.Context, codenav.PositionalRequestArgs, codenav.RequestState, codenav.Cursor) ([]shared1.UploadLocation, codenav.Cursor, error) {
+func (f *CodeNavServiceGetIncomingCallsFunc) nextHook() func(context.Context, codenav.PositionalRequestArgs, codenav.RequestState) ([]codenav.CallHierarchyItem, error) {
 	f.mutex.Lock()
 	defer f.mutex.Unlock()
 
@@ -1029,26 +1259,27 @@ func (f *CodeNavServiceGetPrototypesFunc) nextHook() func(context.Context, coden
 	return hook
 }
 
-func (f *CodeNavServiceGetPrototypesFunc) appendCall(r0 CodeNavServiceGetPrototypesFuncCall) {
+func (f *CodeNavServiceGetIncomingCallsFunc) appendCall(r0 CodeNavServiceGetIncomingCallsFuncCall) {
 	f.mutex.Lock()
 	f.history = append(f.history, r0)
 	f.mutex.Unlock()
 }
 
-// History returns a sequence of CodeNavServiceGetPrototypesFuncCall objects
-// describing the invocations of this function.
-func (f *CodeNavServiceGetPrototypesFunc) History() []CodeNavServiceGetPrototypesFuncCall {
+// History returns a sequence of CodeNavServiceGetIncomingCallsFuncCall
+// objects describing the invocations of this function.
+func (f *CodeNavServiceGetIncomingCallsFunc) History() []CodeNavServiceGetIncomingCallsFuncCall {
 	f.mutex.Lock()
-	history := make([]CodeNavServiceGetPrototypesFuncCall, len(f.history))
+	history := make([]CodeNavServiceGetIncomingCallsFuncCall, len(f.history))
 	copy(history, f.history)
 	f.mutex.Unlock()
 
 	return history
 }
 
-// CodeNavServiceGetPrototypesFuncCall is an object that describes an
-// invocation of method GetPrototypes on an instance of MockCodeNavService.
-type CodeNavServiceGetPrototypesFuncCall struct {
+// CodeNavServiceGetIncomingCallsFuncCall is an object that describes an
+// invocation of method GetIncomingCalls on an instance of
+// MockCodeNavService.
+type CodeNavServiceGetIncomingCallsFuncCall struct {
 	// Arg0 is the value of the 1st argument passed to this method
 	// invocation.
 	Arg0 context.Context
@@ -1058,61 +1289,56 @@ type CodeNavServiceGetPrototypesFuncCall struct {
 	// Arg2 is the value of the 3rd argument passed to this method
 	// invocation.
 	Arg2 codenav.RequestState
-	// Arg3 is the value of the 4th argument passed to this method
-	// invocation.
-	Arg3 codenav.Cursor
 	// Result0 is the value of the 1st result returned from this method
 	// invocation.
-	Result0 []shared1.UploadLocation
+	Result0 []codenav.CallHierarchyItem
 	// Result1 is the value of the 2nd result returned from this method
 	// invocation.
-	Result1 codenav.Cursor
-	// Result2 is the value of the 3rd result returned from this method
-	// invocation.
-	Result2 error
+	Result1 error
 }
 
 // Args returns an interface slice containing the arguments of this
 // invocation.
-func (c CodeNavServiceGetPrototypesFuncCall) Args() []interface{} {
-	return []interface{}{c.Arg0, c.Arg1, c.Arg2, c.Arg3}
+func (c CodeNavServiceGetIncomingCallsFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1, c.Arg2}
 }
 
 // Results returns an interface slice containing the results of this
 // invocation.
-func (c CodeNavServiceGetPrototypesFuncCall) Results() []interface{} {
-	return []interface{}{c.Result0, c.Result1, c.Result2}
+func (c CodeNavServiceGetIncomingCallsFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1}
 }
 
-// CodeNavServiceGetRangesFunc describes the behavior when the GetRanges
-// method of the parent MockCodeNavService instance is invoked.
-type CodeNavServiceGetRangesFunc struct {
-	defaultHook func(context.Context, codenav.PositionalRequestArgs, codenav.RequestState, int, int) ([]codenav.AdjustedCodeIntelligenceRange, error)
-	hooks       []func(context.Context, codenav.PositionalRequestArgs, codenav.RequestState, int, int) ([]codenav.AdjustedCodeIntelligenceRange, error)
-	history     []CodeNavServiceGetRangesFuncCall
+// CodeNavServiceGetOutgoingCallsFunc describes the behavior when the
+// GetOutgoingCalls method of the parent MockCodeNavService instance is
+// invoked.
+type CodeNavServiceGetOutgoingCallsFunc struct {
+	defaultHook func(context.Context, codenav.PositionalRequestArgs, codenav.RequestState) ([]codenav.CallHierarchyItem, error)
+	hooks       []func(context.Context, codenav.PositionalRequestArgs, codenav.RequestState) ([]codenav.CallHierarchyItem, error)
+	history     []CodeNavServiceGetOutgoingCallsFuncCall
 	mutex       sync.Mutex
 }
 
-// GetRanges delegates to the next hook function in the queue and stores the
-// parameter and result values of this invocation.
-func (m *MockCodeNavService) GetRanges(v0 context.Context, v1 codenav.PositionalRequestArgs, v2 codenav.RequestState, v3 int, v4 int) ([]codenav.AdjustedCodeIntelligenceRange, error) {
-	r0, r1 := m.GetRangesFunc.nextHook()(v0, v1, v2, v3, v4)
-	m.GetRangesFunc.appendCall(CodeNavServiceGetRangesFuncCall{v0, v1, v2, v3, v4, r0, r1})
+// GetOutgoingCalls delegates to the next hook function in the queue and
+// stores the parameter and result values of this invocation.
+func (m *MockCodeNavService) GetOutgoingCalls(v0 context.Context, v1 codenav.PositionalRequestArgs, v2 codenav.RequestState) ([]codenav.CallHierarchyItem, error) {
+	r0, r1 := m.GetOutgoingCallsFunc.nextHook()(v0, v1, v2)
+	m.GetOutgoingCallsFunc.appendCall(CodeNavServiceGetOutgoingCallsFuncCall{v0, v1, v2, r0, r1})
 	return r0, r1
 }
 
-// SetDefaultHook sets function that is called when the GetRanges method of
-// the parent MockCodeNavService instance is invoked and the hook queue is
-// empty.
-func (f *CodeNavServiceGetRangesFunc) SetDefaultHook(hook func(context.Context, codenav.PositionalRequestArgs, codenav.RequestState, int, int) ([]codenav.AdjustedCodeIntelligenceRange, error)) {
+// SetDefaultHook sets function that is called when the GetOutgoingCalls
+// method of the parent MockCodeNavService instance is invoked and the hook
+// queue is empty.
+func (f *CodeNavServiceGetOutgoingCallsFunc) SetDefaultHook(hook func(context.Context, codenav.PositionalRequestArgs, codenav.RequestState) ([]codenav.CallHierarchyItem, error)) {
 	f.defaultHook = hook
 }
 
 // PushHook adds a function to the end of hook queue. Each invocation of the
-// GetRanges method of the parent MockCodeNavService instance invokes the
-// hook at the front of the queue and discards it. After the queue is empty,
-// the default hook function is invoked for any future action.
-func (f *CodeNavServiceGetRangesFunc) PushHook(hook func(context.Context, codenav.PositionalRequestArgs, codenav.RequestState, int, int) ([]codenav.AdjustedCodeIntelligenceRange, error)) {
+// GetOutgoingCalls method of the parent MockCodeNavService instance invokes
+// the hook at the front of the queue and discards it. After the queue is
+// empty, the default hook function is invoked for any future action.
+func (f *CodeNavServiceGetOutgoingCallsFunc) PushHook(hook func(context.Context, codenav.PositionalRequestArgs, codenav.RequestState) ([]codenav.CallHierarchyItem, error)) {
 	f.mutex.Lock()
 	f.hooks = append(f.hooks, hook)
 	f.mutex.Unlock()
@@ -1120,20 +1346,20 @@ func (f *CodeNavServiceGetRangesFunc) PushHook(hook func(context.Context, codena
 
 // SetDefaultReturn calls SetDefaultHook with a function that returns the
 // given values.
-func (f *CodeNavServiceGetRangesFunc) SetDefaultReturn(r0 []codenav.AdjustedCodeIntelligenceRange, r1 error) {
-	f.SetDefaultHook(func(context.Context, codenav.PositionalRequestArgs, codenav.RequestState, int, int) ([]codenav.AdjustedCodeIntelligenceRange, error) {
+func (f *CodeNavServiceGetOutgoingCallsFunc) SetDefaultReturn(r0 []codenav.CallHierarchyItem, r1 error) {
+	f.SetDefaultHook(func(context.Context, codenav.PositionalRequestArgs, codenav.RequestState) ([]codenav.CallHierarchyItem, error) {
 		return r0, r1
 	})
 }
 
 // PushReturn calls PushHook with a function that returns the given values.
-func (f *CodeNavServiceGetRangesFunc) PushReturn(r0 []codenav.AdjustedCodeIntelligenceRange, r1 error) {
-	f.PushHook(func(context.Context, codenav.PositionalRequestArgs, codenav.RequestState, int, int) ([]codenav.AdjustedCodeIntelligenceRange, error) {
+func (f *CodeNavServiceGetOutgoingCallsFunc) PushReturn(r0 []codenav.CallHierarchyItem, r1 error) {
+	f.PushHook(func(context.Context, codenav.PositionalRequestArgs, codenav.RequestState) ([]codenav.CallHierarchyItem, error) {
 		return r0, r1
 	})
 }
 
-func (f *CodeNavServiceGetRangesFunc) nextHook() func(context.Context, codenav.PositionalRequestArgs, codenav.RequestState, int, int) ([]codenav.AdjustedCodeIntelligenceRange, error) {
+func (f *CodeNavServiceGetOutgoingCallsFunc) nextHook() func(context.Context, codenav.PositionalRequestArgs, codenav.RequestState) ([]codenav.CallHierarchyItem, error) {
 	f.mutex.Lock()
 	defer f.mutex.Unlock()
 
@@ -1146,26 +1372,27 @@ func (f *CodeNavServiceGetRangesFunc) nextHook() func(context.Context, codenav.P
 	return hook
 }
 
-func (f *CodeNavServiceGetRangesFunc) appendCall(r0 CodeNavServiceGetRangesFuncCall) {
+func (f *CodeNavServiceGetOutgoingCallsFunc) appendCall(r0 CodeNavServiceGetOutgoingCallsFuncCall) {
 	f.mutex.Lock()
 	f.history = append(f.history, r0)
 	f.mutex.Unlock()
 }
 
-// History returns a sequence of CodeNavServiceGetRangesFuncCall objects
-// describing the invocations of this function.
-func (f *CodeNavServiceGetRangesFunc) History() []CodeNavServiceGetRangesFuncCall {
+// History returns a sequence of CodeNavServiceGetOutgoingCallsFuncCall
+// objects describing the invocations of this function.
+func (f *CodeNavServiceGetOutgoingCallsFunc) History() []CodeNavServiceGetOutgoingCallsFuncCall {
 	f.mutex.Lock()
-	history := make([]CodeNavServiceGetRangesFuncCall, len(f.history))
+	history := make([]CodeNavServiceGetOutgoingCallsFuncCall, len(f.history))
 	copy(history, f.history)
 	f.mutex.Unlock()
 
 	return history
 }
 
-// CodeNavServiceGetRangesFuncCall is an object that describes an invocation
-// of method GetRanges on an instance of MockCodeNavService.
-type CodeNavServiceGetRangesFuncCall struct {
+// CodeNavServiceGetOutgoingCallsFuncCall is an object that describes an
+// invocation of method GetOutgoingCalls on an instance of
+// MockCodeNavService.
+type CodeNavServiceGetOutgoingCallsFuncCall struct {
 	// Arg0 is the value of the 1st argument passed to this method
 	// invocation.
 	Arg0 context.Context
@@ -1175,15 +1402,9 @@ type CodeNavServiceGetRangesFuncCall struct {
 	// Arg2 is the value of the 3rd argument passed to this method
 	// invocation.
 	Arg2 codenav.RequestState
-	// Arg3 is the value of the 4th argument passed to this method
-	// invocation.
-	Arg3 int
-	// Arg4 is the value of the 5th argument passed to this method
-	// invocation.
-	Arg4 int
 	// Result0 is the value of the 1st result returned from this method
 	// invocation.
-	Result0 []codenav.AdjustedCodeIntelligenceRange
+	Result0 []codenav.CallHierarchyItem
 	// Result1 is the value of the 2nd result returned from this method
 	// invocation.
 	Result1 error
@@ -1191,43 +1412,278 @@ type CodeNavServiceGetRangesFuncCall struct {
 
 // Args returns an interface slice containing the arguments of this
 // invocation.
-func (c CodeNavServiceGetRangesFuncCall) Args() []interface{} {
-	return []interface{}{c.Arg0, c.Arg1, c.Arg2, c.Arg3, c.Arg4}
+func (c CodeNavServiceGetOutgoingCallsFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1, c.Arg2}
 }
 
 // Results returns an interface slice containing the results of this
 // invocation.
-func (c CodeNavServiceGetRangesFuncCall) Results() []interface{} {
+func (c CodeNavServiceGetOutgoingCallsFuncCall) Results() []interface{} {
 	return []interface{}{c.Result0, c.Result1}
 }
 
-// CodeNavServiceGetReferencesFunc describes the behavior when the
-// GetReferences method of the parent MockCodeNavService instance is
+// CodeNavServiceGetPrototypesFunc describes the behavior when the
+// GetPrototypes method of the parent MockCodeNavService instance is
 // invoked.
-type CodeNavServiceGetReferencesFunc struct {
+type CodeNavServiceGetPrototypesFunc struct {
 	defaultHook func(context.Context, codenav.PositionalRequestArgs, codenav.RequestState, codenav.Cursor) ([]shared1.UploadLocation, codenav.Cursor, error)
 	hooks       []func(context.Context, codenav.PositionalRequestArgs, codenav.RequestState, codenav.Cursor) ([]shared1.UploadLocation, codenav.Cursor, error)
-	history     []CodeNavServiceGetReferencesFuncCall
+	history     []CodeNavServiceGetPrototypesFuncCall
 	mutex       sync.Mutex
 }
 
-// GetReferences delegates to the next hook function in the queue and stores
+// GetPrototypes delegates to the next hook function in the queue and stores
 // the parameter and result values of this invocation.
-func (m *MockCodeNavService) GetReferences(v0 context.Context, v1 codenav.PositionalRequestArgs, v2 codenav.RequestState, v3 codenav.Cursor) ([]shared1.UploadLocation, codenav.Cursor, error) {
-	r0, r1, r2 := m.GetReferencesFunc.nextHook()(v0, v1, v2, v3)
-	m.GetReferencesFunc.appendCall(CodeNavServiceGetReferencesFuncCall{v0, v1, v2, v3, r0, r1, r2})
+func (m *MockCodeNavService) GetPrototypes(v0 context.Context, v1 codenav.PositionalRequestArgs, v2 codenav.RequestState, v3 codenav.Cursor) ([]shared1.UploadLocation, codenav.Cursor, error) {
+	r0, r1, r2 := m.GetPrototypesFunc.nextHook()(v0, v1, v2, v3)
+	m.GetPrototypesFunc.appendCall(CodeNavServiceGetPrototypesFuncCall{v0, v1, v2, v3, r0, r1, r2})
 	return r0, r1, r2
 }
 
-// SetDefaultHook sets function that is called when the GetReferences method
+// SetDefaultHook sets function that is called when the GetPrototypes method
 // of the parent MockCodeNavService instance is invoked and the hook queue
 // is empty.
-func (f *CodeNavServiceGetReferencesFunc) SetDefaultHook(hook func(context.Context, codenav.PositionalRequestArgs, codenav.RequestState, codenav.Cursor) ([]shared1.UploadLocation, codenav.Cursor, error)) {
+func (f *CodeNavServiceGetPrototypesFunc) SetDefaultHook(hook func(context.Context, codenav.PositionalRequestArgs, codenav.RequestState, codenav.Cursor) ([]shared1.UploadLocation, codenav.Cursor, error)) {
 	f.defaultHook = hook
 }
 
 // PushHook adds a function to the end of hook queue. Each invocation of the
-// GetReferences method of the parent MockCodeNavService instance invokes
+// GetPrototypes method of the parent MockCodeNavService instance invokes
+// the hook at the front of the queue and discards it. After the queue is
+// empty, the default hook function is invoked for any future action.
+func (f *CodeNavServiceGetPrototypesFunc) PushHook(hook func(context.Context, codenav.PositionalRequestArgs, codenav.RequestState, codenav.Cursor) ([]shared1.UploadLocation, codenav.Cursor, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultHook with a function that returns the
+// given values.
+func (f *CodeNavServiceGetPrototypesFunc) SetDefaultReturn(r0 []shared1.UploadLocation, r1 codenav.Cursor, r2 error) {
+	f.SetDefaultHook(func(context.Context, codenav.PositionalRequestArgs, codenav.RequestState, codenav.Cursor) ([]shared1.UploadLocation, codenav.Cursor, error) {
+		return r0, r1, r2
+	})
+}
+
+// PushReturn calls PushHook with a function that returns the given values.
+func (f *CodeNavServiceGetPrototypesFunc) PushReturn(r0 []shared1.UploadLocation, r1 codenav.Cursor, r2 error) {
+	f.PushHook(func(context.Context, codenav.PositionalRequestArgs, codenav.RequestState, codenav.Cursor) ([]shared1.UploadLocation, codenav.Cursor, error) {
+		return r0, r1, r2
+	})
+}
+
+func (f *CodeNavServiceGetPrototypesFunc) nextHook() func(context.Context, codenav.PositionalRequestArgs, codenav.RequestState, codenav.Cursor) ([]shared1.UploadLocation, codenav.Cursor, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *CodeNavServiceGetPrototypesFunc) appendCall(r0 CodeNavServiceGetPrototypesFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of CodeNavServiceGetPrototypesFuncCall objects
+// describing the invocations of this function.
+func (f *CodeNavServiceGetPrototypesFunc) History() []CodeNavServiceGetPrototypesFuncCall {
+	f.mutex.Lock()
+	history := make([]CodeNavServiceGetPrototypesFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// CodeNavServiceGetPrototypesFuncCall is an object that describes an
+// invocation of method GetPrototypes on an instance of MockCodeNavService.
+type CodeNavServiceGetPrototypesFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 codenav.PositionalRequestArgs
+	// Arg2 is the value of the 3rd argument passed to this method
+	// invocation.
+	Arg2 codenav.RequestState
+	// Arg3 is the value of the 4th argument passed to this method
+	// invocation.
+	Arg3 codenav.Cursor
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 []shared1.UploadLocation
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 codenav.Cursor
+	// Result2 is the value of the 3rd result returned from this method
+	// invocation.
+	Result2 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c CodeNavServiceGetPrototypesFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1, c.Arg2, c.Arg3}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c CodeNavServiceGetPrototypesFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1, c.Result2}
+}
+
+// CodeNavServiceGetRangesFunc describes the behavior when the GetRanges
+// method of the parent MockCodeNavService instance is invoked.
+type CodeNavServiceGetRangesFunc struct {
+	defaultHook func(context.Context, codenav.PositionalRequestArgs, codenav.RequestState, int, int) ([]codenav.AdjustedCodeIntelligenceRange, error)
+	hooks       []func(context.Context, codenav.PositionalRequestArgs, codenav.RequestState, int, int) ([]codenav.AdjustedCodeIntelligenceRange, error)
+	history     []CodeNavServiceGetRangesFuncCall
+	mutex       sync.Mutex
+}
+
+// GetRanges delegates to the next hook function in the queue and stores the
+// parameter and result values of this invocation.
+func (m *MockCodeNavService) GetRanges(v0 context.Context, v1 codenav.PositionalRequestArgs, v2 codenav.RequestState, v3 int, v4 int) ([]codenav.AdjustedCodeIntelligenceRange, error) {
+	r0, r1 := m.GetRangesFunc.nextHook()(v0, v1, v2, v3, v4)
+	m.GetRangesFunc.appendCall(CodeNavServiceGetRangesFuncCall{v0, v1, v2, v3, v4, r0, r1})
+	return r0, r1
+}
+
+// SetDefaultHook sets function that is called when the GetRanges method of
+// the parent MockCodeNavService instance is invoked and the hook queue is
+// empty.
+func (f *CodeNavServiceGetRangesFunc) SetDefaultHook(hook func(context.Context, codenav.PositionalRequestArgs, codenav.RequestState, int, int) ([]codenav.AdjustedCodeIntelligenceRange, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// GetRanges method of the parent MockCodeNavService instance invokes the
+// hook at the front of the queue and discards it. After the queue is empty,
+// the default hook function is invoked for any future action.
+func (f *CodeNavServiceGetRangesFunc) PushHook(hook func(context.Context, codenav.PositionalRequestArgs, codenav.RequestState, int, int) ([]codenav.AdjustedCodeIntelligenceRange, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultHook with a function that returns the
+// given values.
+func (f *CodeNavServiceGetRangesFunc) SetDefaultReturn(r0 []codenav.AdjustedCodeIntelligenceRange, r1 error) {
+	f.SetDefaultHook(func(context.Context, codenav.PositionalRequestArgs, codenav.RequestState, int, int) ([]codenav.AdjustedCodeIntelligenceRange, error) {
+		return r0, r1
+	})
+}
+
+// PushReturn calls PushHook with a function that returns the given values.
+func (f *CodeNavServiceGetRangesFunc) PushReturn(r0 []codenav.AdjustedCodeIntelligenceRange, r1 error) {
+	f.PushHook(func(context.Context, codenav.PositionalRequestArgs, codenav.RequestState, int, int) ([]codenav.AdjustedCodeIntelligenceRange, error) {
+		return r0, r1
+	})
+}
+
+func (f *CodeNavServiceGetRangesFunc) nextHook() func(context.Context, codenav.PositionalRequestArgs, codenav.RequestState, int, int) ([]codenav.AdjustedCodeIntelligenceRange, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *CodeNavServiceGetRangesFunc) appendCall(r0 CodeNavServiceGetRangesFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of CodeNavServiceGetRangesFuncCall objects
+// describing the invocations of this function.
+func (f *CodeNavServiceGetRangesFunc) History() []CodeNavServiceGetRangesFuncCall {
+	f.mutex.Lock()
+	history := make([]CodeNavServiceGetRangesFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// CodeNavServiceGetRangesFuncCall is an object that describes an invocation
+// of method GetRanges on an instance of MockCodeNavService.
+type CodeNavServiceGetRangesFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 codenav.PositionalRequestArgs
+	// Arg2 is the value of the 3rd argument passed to this method
+	// invocation.
+	Arg2 codenav.RequestState
+	// Arg3 is the value of the 4th argument passed to this method
+	// invocation.
+	Arg3 int
+	// Arg4 is the value of the 5th argument passed to this method
+	// invocation.
+	Arg4 int
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 []codenav.AdjustedCodeIntelligenceRange
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c CodeNavServiceGetRangesFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1, c.Arg2, c.Arg3, c.Arg4}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c CodeNavServiceGetRangesFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1}
+}
+
+// CodeNavServiceGetReferencesFunc describes the behavior when the
+// GetReferences method of the parent MockCodeNavService instance is
+// invoked.
+type CodeNavServiceGetReferencesFunc struct {
+	defaultHook func(context.Context, codenav.PositionalRequestArgs, codenav.RequestState, codenav.Cursor) ([]shared1.UploadLocation, codenav.Cursor, error)
+	hooks       []func(context.Context, codenav.PositionalRequestArgs, codenav.RequestState, codenav.Cursor) ([]shared1.UploadLocation, codenav.Cursor, error)
+	history     []CodeNavServiceGetReferencesFuncCall
+	mutex       sync.Mutex
+}
+
+// GetReferences delegates to the next hook function in the queue and stores
+// the parameter and result values of this invocation.
+func (m *MockCodeNavService) GetReferences(v0 context.Context, v1 codenav.PositionalRequestArgs, v2 codenav.RequestState, v3 codenav.Cursor) ([]shared1.UploadLocation, codenav.Cursor, error) {
+	r0, r1, r2 := m.GetReferencesFunc.nextHook()(v0, v1, v2, v3)
+	m.GetReferencesFunc.appendCall(CodeNavServiceGetReferencesFuncCall{v0, v1, v2, v3, r0, r1, r2})
+	return r0, r1, r2
+}
+
+// SetDefaultHook sets function that is called when the GetReferences method
+// of the parent MockCodeNavService instance is invoked and the hook queue
+// is empty.
+func (f *CodeNavServiceGetReferencesFunc) SetDefaultHook(hook func(context.Context, codenav.PositionalRequestArgs, codenav.RequestState, codenav.Cursor) ([]shared1.UploadLocation, codenav.Cursor, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// GetReferences method of the parent MockCodeNavService instance invokes
 // the hook at the front of the queue and discards it. After the queue is
 // empty, the default hook function is invoked for any future action.
 func (f *CodeNavServiceGetReferencesFunc) PushHook(hook func(context.Context, codenav.PositionalRequestArgs, codenav.RequestState, codenav.Cursor) ([]shared1.UploadLocation, codenav.Cursor, error)) {
@@ -1238,20 +1694,251 @@ func (f *CodeNavServiceGetReferencesFunc) PushHook(hook func(context.Context, co
 
 // SetDefaultReturn calls SetDefaultHook with a function that returns the
 // given values.
-func (f *CodeNavServiceGetReferencesFunc) SetDefaultReturn(r0 []shared1.UploadLocation, r1 codenav.Cursor, r2 error) {
-	f.SetDefaultHook(func(context.Context, codenav.PositionalRequestArgs, codenav.RequestState, codenav.Cursor) ([]shared1.UploadLocation, codenav.Cursor, error) {
-		return r0, r1, r2
+func (f *CodeNavServiceGetReferencesFunc) SetDefaultReturn(r0 []shared1.UploadLocation, r1 codenav.Cursor, r2 error) {
+	f.SetDefaultHook(func(context.Context, codenav.PositionalRequestArgs, codenav.RequestState, codenav.Cursor) ([]shared1.UploadLocation, codenav.Cursor, error) {
+		return r0, r1, r2
+	})
+}
+
+// PushReturn calls PushHook with a function that returns the given values.
+func (f *CodeNavServiceGetReferencesFunc) PushReturn(r0 []shared1.UploadLocation, r1 codenav.Cursor, r2 error) {
+	f.PushHook(func(context.Context, codenav.PositionalRequestArgs, codenav.RequestState, codenav.Cursor) ([]shared1.UploadLocation, codenav.Cursor, error) {
+		return r0, r1, r2
+	})
+}
+
+func (f *CodeNavServiceGetReferencesFunc) nextHook() func(context.Context, codenav.PositionalRequestArgs, codenav.RequestState, codenav.Cursor) ([]shared1.UploadLocation, codenav.Cursor, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *CodeNavServiceGetReferencesFunc) appendCall(r0 CodeNavServiceGetReferencesFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of CodeNavServiceGetReferencesFuncCall objects
+// describing the invocations of this function.
+func (f *CodeNavServiceGetReferencesFunc) History() []CodeNavServiceGetReferencesFuncCall {
+	f.mutex.Lock()
+	history := make([]CodeNavServiceGetReferencesFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// CodeNavServiceGetReferencesFuncCall is an object that describes an
+// invocation of method GetReferences on an instance of MockCodeNavService.
+type CodeNavServiceGetReferencesFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 codenav.PositionalRequestArgs
+	// Arg2 is the value of the 3rd argument passed to this method
+	// invocation.
+	Arg2 codenav.RequestState
+	// Arg3 is the value of the 4th argument passed to this method
+	// invocation.
+	Arg3 codenav.Cursor
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 []shared1.UploadLocation
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 codenav.Cursor
+	// Result2 is the value of the 3rd result returned from this method
+	// invocation.
+	Result2 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c CodeNavServiceGetReferencesFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1, c.Arg2, c.Arg3}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c CodeNavServiceGetReferencesFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1, c.Result2}
+}
+
+// CodeNavServiceGetRenameEditsFunc describes the behavior when the
+// GetRenameEdits method of the parent MockCodeNavService instance is
+// invoked.
+type CodeNavServiceGetRenameEditsFunc struct {
+	defaultHook func(context.Context, codenav.PositionalRequestArgs, codenav.RequestState) (*codenav.RenameEditSet, error)
+	hooks       []func(context.Context, codenav.PositionalRequestArgs, codenav.RequestState) (*codenav.RenameEditSet, error)
+	history     []CodeNavServiceGetRenameEditsFuncCall
+	mutex       sync.Mutex
+}
+
+// GetRenameEdits delegates to the next hook function in the queue and
+// stores the parameter and result values of this invocation.
+func (m *MockCodeNavService) GetRenameEdits(v0 context.Context, v1 codenav.PositionalRequestArgs, v2 codenav.RequestState) (*codenav.RenameEditSet, error) {
+	r0, r1 := m.GetRenameEditsFunc.nextHook()(v0, v1, v2)
+	m.GetRenameEditsFunc.appendCall(CodeNavServiceGetRenameEditsFuncCall{v0, v1, v2, r0, r1})
+	return r0, r1
+}
+
+// SetDefaultHook sets function that is called when the GetRenameEdits
+// method of the parent MockCodeNavService instance is invoked and the hook
+// queue is empty.
+func (f *CodeNavServiceGetRenameEditsFunc) SetDefaultHook(hook func(context.Context, codenav.PositionalRequestArgs, codenav.RequestState) (*codenav.RenameEditSet, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// GetRenameEdits method of the parent MockCodeNavService instance invokes
+// the hook at the front of the queue and discards it. After the queue is
+// empty, the default hook function is invoked for any future action.
+func (f *CodeNavServiceGetRenameEditsFunc) PushHook(hook func(context.Context, codenav.PositionalRequestArgs, codenav.RequestState) (*codenav.RenameEditSet, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultHook with a function that returns the
+// given values.
+func (f *CodeNavServiceGetRenameEditsFunc) SetDefaultReturn(r0 *codenav.RenameEditSet, r1 error) {
+	f.SetDefaultHook(func(context.Context, codenav.PositionalRequestArgs, codenav.RequestState) (*codenav.RenameEditSet, error) {
+		return r0, r1
+	})
+}
+
+// PushReturn calls PushHook with a function that returns the given values.
+func (f *CodeNavServiceGetRenameEditsFunc) PushReturn(r0 *codenav.RenameEditSet, r1 error) {
+	f.PushHook(func(context.Context, codenav.PositionalRequestArgs, codenav.RequestState) (*codenav.RenameEditSet, error) {
+		return r0, r1
+	})
+}
+
+func (f *CodeNavServiceGetRenameEditsFunc) nextHook() func(context.Context, codenav.PositionalRequestArgs, codenav.RequestState) (*codenav.RenameEditSet, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *CodeNavServiceGetRenameEditsFunc) appendCall(r0 CodeNavServiceGetRenameEditsFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of CodeNavServiceGetRenameEditsFuncCall
+// objects describing the invocations of this function.
+func (f *CodeNavServiceGetRenameEditsFunc) History() []CodeNavServiceGetRenameEditsFuncCall {
+	f.mutex.Lock()
+	history := make([]CodeNavServiceGetRenameEditsFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// CodeNavServiceGetRenameEditsFuncCall is an object that describes an
+// invocation of method GetRenameEdits on an instance of MockCodeNavService.
+type CodeNavServiceGetRenameEditsFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 codenav.PositionalRequestArgs
+	// Arg2 is the value of the 3rd argument passed to this method
+	// invocation.
+	Arg2 codenav.RequestState
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 *codenav.RenameEditSet
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c CodeNavServiceGetRenameEditsFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1, c.Arg2}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c CodeNavServiceGetRenameEditsFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1}
+}
+
+// CodeNavServiceGetSearchBasedDefinitionsFunc describes the behavior when
+// the GetSearchBasedDefinitions method of the parent MockCodeNavService
+// instance is invoked.
+type CodeNavServiceGetSearchBasedDefinitionsFunc struct {
+	defaultHook func(context.Context, codenav.PositionalRequestArgs, codenav.RequestState) ([]shared1.SearchBasedLocation, error)
+	hooks       []func(context.Context, codenav.PositionalRequestArgs, codenav.RequestState) ([]shared1.SearchBasedLocation, error)
+	history     []CodeNavServiceGetSearchBasedDefinitionsFuncCall
+	mutex       sync.Mutex
+}
+
+// GetSearchBasedDefinitions delegates to the next hook function in the
+// queue and stores the parameter and result values of this invocation.
+func (m *MockCodeNavService) GetSearchBasedDefinitions(v0 context.Context, v1 codenav.PositionalRequestArgs, v2 codenav.RequestState) ([]shared1.SearchBasedLocation, error) {
+	r0, r1 := m.GetSearchBasedDefinitionsFunc.nextHook()(v0, v1, v2)
+	m.GetSearchBasedDefinitionsFunc.appendCall(CodeNavServiceGetSearchBasedDefinitionsFuncCall{v0, v1, v2, r0, r1})
+	return r0, r1
+}
+
+// SetDefaultHook sets function that is called when the
+// GetSearchBasedDefinitions method of the parent MockCodeNavService
+// instance is invoked and the hook queue is empty.
+func (f *CodeNavServiceGetSearchBasedDefinitionsFunc) SetDefaultHook(hook func(context.Context, codenav.PositionalRequestArgs, codenav.RequestState) ([]shared1.SearchBasedLocation, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// GetSearchBasedDefinitions method of the parent MockCodeNavService
+// instance invokes the hook at the front of the queue and discards it.
+// After the queue is empty, the default hook function is invoked for any
+// future action.
+func (f *CodeNavServiceGetSearchBasedDefinitionsFunc) PushHook(hook func(context.Context, codenav.PositionalRequestArgs, codenav.RequestState) ([]shared1.SearchBasedLocation, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultHook with a function that returns the
+// given values.
+func (f *CodeNavServiceGetSearchBasedDefinitionsFunc) SetDefaultReturn(r0 []shared1.SearchBasedLocation, r1 error) {
+	f.SetDefaultHook(func(context.Context, codenav.PositionalRequestArgs, codenav.RequestState) ([]shared1.SearchBasedLocation, error) {
+		return r0, r1
 	})
 }
 
 // PushReturn calls PushHook with a function that returns the given values.
-func (f *CodeNavServiceGetReferencesFunc) PushReturn(r0 []shared1.UploadLocation, r1 codenav.Cursor, r2 error) {
-	f.PushHook(func(context.Context, codenav.PositionalRequestArgs, codenav.RequestState, codenav.Cursor) ([]shared1.UploadLocation, codenav.Cursor, error) {
-		return r0, r1, r2
+func (f *CodeNavServiceGetSearchBasedDefinitionsFunc) PushReturn(r0 []shared1.SearchBasedLocation, r1 error) {
+	f.PushHook(func(context.Context, codenav.PositionalRequestArgs, codenav.RequestState) ([]shared1.SearchBasedLocation, error) {
+		return r0, r1
 	})
 }
 
-func (f *CodeNavServiceGetReferencesFunc) nextHook() func(context.Context, codenav.PositionalRequestArgs, codenav.RequestState, codenav.Cursor) ([]shared1.UploadLocation, codenav.Cursor, error) {
+func (f *CodeNavServiceGetSearchBasedDefinitionsFunc) nextHook() func(context.Context, codenav.PositionalRequestArgs, codenav.RequestState) ([]shared1.SearchBasedLocation, error) {
 	f.mutex.Lock()
 	defer f.mutex.Unlock()
 
@@ -1264,26 +1951,28 @@ func (f *CodeNavServiceGetReferencesFunc) nextHook() func(context.Context, coden
 	return hook
 }
 
-func (f *CodeNavServiceGetReferencesFunc) appendCall(r0 CodeNavServiceGetReferencesFuncCall) {
+func (f *CodeNavServiceGetSearchBasedDefinitionsFunc) appendCall(r0 CodeNavServiceGetSearchBasedDefinitionsFuncCall) {
 	f.mutex.Lock()
 	f.history = append(f.history, r0)
 	f.mutex.Unlock()
 }
 
-// History returns a sequence of CodeNavServiceGetReferencesFuncCall objects
-// describing the invocations of this function.
-func (f *CodeNavServiceGetReferencesFunc) History() []CodeNavServiceGetReferencesFuncCall {
+// History returns a sequence of
+// CodeNavServiceGetSearchBasedDefinitionsFuncCall objects describing the
+// invocations of this function.
+func (f *CodeNavServiceGetSearchBasedDefinitionsFunc) History() []CodeNavServiceGetSearchBasedDefinitionsFuncCall {
 	f.mutex.Lock()
-	history := make([]CodeNavServiceGetReferencesFuncCall, len(f.history))
+	history := make([]CodeNavServiceGetSearchBasedDefinitionsFuncCall, len(f.history))
 	copy(history, f.history)
 	f.mutex.Unlock()
 
 	return history
 }
 
-// CodeNavServiceGetReferencesFuncCall is an object that describes an
-// invocation of method GetReferences on an instance of MockCodeNavService.
-type CodeNavServiceGetReferencesFuncCall struct {
+// CodeNavServiceGetSearchBasedDefinitionsFuncCall is an object that
+// describes an invocation of method GetSearchBasedDefinitions on an
+// instance of MockCodeNavService.
+type CodeNavServiceGetSearchBasedDefinitionsFuncCall struct {
 	// Arg0 is the value of the 1st argument passed to this method
 	// invocation.
 	Arg0 context.Context
@@ -1293,30 +1982,139 @@ type CodeNavServiceGetReferencesFuncCall struct {
 	// Arg2 is the value of the 3rd argument passed to this method
 	// invocation.
 	Arg2 codenav.RequestState
-	// Arg3 is the value of the 4th argument passed to this method
-	// invocation.
-	Arg3 codenav.Cursor
 	// Result0 is the value of the 1st result returned from this method
 	// invocation.
-	Result0 []shared1.UploadLocation
+	Result0 []shared1.SearchBasedLocation
 	// Result1 is the value of the 2nd result returned from this method
 	// invocation.
-	Result1 codenav.Cursor
-	// Result2 is the value of the 3rd result returned from this method
+	Result1 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c CodeNavServiceGetSearchBasedDefinitionsFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1, c.Arg2}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c CodeNavServiceGetSearchBasedDefinitionsFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1}
+}
+
+// CodeNavServiceGetSearchBasedReferencesFunc describes the behavior when
+// the GetSearchBasedReferences method of the parent MockCodeNavService
+// instance is invoked.
+type CodeNavServiceGetSearchBasedReferencesFunc struct {
+	defaultHook func(context.Context, codenav.PositionalRequestArgs, codenav.RequestState) ([]shared1.SearchBasedLocation, error)
+	hooks       []func(context.Context, codenav.PositionalRequestArgs, codenav.RequestState) ([]shared1.SearchBasedLocation, error)
+	history     []CodeNavServiceGetSearchBasedReferencesFuncCall
+	mutex       sync.Mutex
+}
+
+// GetSearchBasedReferences delegates to the next hook function in the queue
+// and stores the parameter and result values of this invocation.
+func (m *MockCodeNavService) GetSearchBasedReferences(v0 context.Context, v1 codenav.PositionalRequestArgs, v2 codenav.RequestState) ([]shared1.SearchBasedLocation, error) {
+	r0, r1 := m.GetSearchBasedReferencesFunc.nextHook()(v0, v1, v2)
+	m.GetSearchBasedReferencesFunc.appendCall(CodeNavServiceGetSearchBasedReferencesFuncCall{v0, v1, v2, r0, r1})
+	return r0, r1
+}
+
+// SetDefaultHook sets function that is called when the
+// GetSearchBasedReferences method of the parent MockCodeNavService instance
+// is invoked and the hook queue is empty.
+func (f *CodeNavServiceGetSearchBasedReferencesFunc) SetDefaultHook(hook func(context.Context, codenav.PositionalRequestArgs, codenav.RequestState) ([]shared1.SearchBasedLocation, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// GetSearchBasedReferences method of the parent MockCodeNavService instance
+// invokes the hook at the front of the queue and discards it. After the
+// queue is empty, the default hook function is invoked for any future
+// action.
+func (f *CodeNavServiceGetSearchBasedReferencesFunc) PushHook(hook func(context.Context, codenav.PositionalRequestArgs, codenav.RequestState) ([]shared1.SearchBasedLocation, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultHook with a function that returns the
+// given values.
+func (f *CodeNavServiceGetSearchBasedReferencesFunc) SetDefaultReturn(r0 []shared1.SearchBasedLocation, r1 error) {
+	f.SetDefaultHook(func(context.Context, codenav.PositionalRequestArgs, codenav.RequestState) ([]shared1.SearchBasedLocation, error) {
+		return r0, r1
+	})
+}
+
+// PushReturn calls PushHook with a function that returns the given values.
+func (f *CodeNavServiceGetSearchBasedReferencesFunc) PushReturn(r0 []shared1.SearchBasedLocation, r1 error) {
+	f.PushHook(func(context.Context, codenav.PositionalRequestArgs, codenav.RequestState) ([]shared1.SearchBasedLocation, error) {
+		return r0, r1
+	})
+}
+
+func (f *CodeNavServiceGetSearchBasedReferencesFunc) nextHook() func(context.Context, codenav.PositionalRequestArgs, codenav.RequestState) ([]shared1.SearchBasedLocation, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *CodeNavServiceGetSearchBasedReferencesFunc) appendCall(r0 CodeNavServiceGetSearchBasedReferencesFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of
+// CodeNavServiceGetSearchBasedReferencesFuncCall objects describing the
+// invocations of this function.
+func (f *CodeNavServiceGetSearchBasedReferencesFunc) History() []CodeNavServiceGetSearchBasedReferencesFuncCall {
+	f.mutex.Lock()
+	history := make([]CodeNavServiceGetSearchBasedReferencesFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// CodeNavServiceGetSearchBasedReferencesFuncCall is an object that
+// describes an invocation of method GetSearchBasedReferences on an instance
+// of MockCodeNavService.
+type CodeNavServiceGetSearchBasedReferencesFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
 	// invocation.
-	Result2 error
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 codenav.PositionalRequestArgs
+	// Arg2 is the value of the 3rd argument passed to this method
+	// invocation.
+	Arg2 codenav.RequestState
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 []shared1.SearchBasedLocation
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 error
 }
 
 // Args returns an interface slice containing the arguments of this
 // invocation.
-func (c CodeNavServiceGetReferencesFuncCall) Args() []interface{} {
-	return []interface{}{c.Arg0, c.Arg1, c.Arg2, c.Arg3}
+func (c CodeNavServiceGetSearchBasedReferencesFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1, c.Arg2}
 }
 
 // Results returns an interface slice containing the results of this
 // invocation.
-func (c CodeNavServiceGetReferencesFuncCall) Results() []interface{} {
-	return []interface{}{c.Result0, c.Result1, c.Result2}
+func (c CodeNavServiceGetSearchBasedReferencesFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1}
 }
 
 // CodeNavServiceGetStencilFunc describes the behavior when the GetStencil
@@ -1430,6 +2228,125 @@ func (c CodeNavServiceGetStencilFuncCall) Results() []interface{} {
 	return []interface{}{c.Result0, c.Result1}
 }
 
+// CodeNavServiceGetTypeHierarchyFunc describes the behavior when the
+// GetTypeHierarchy method of the parent MockCodeNavService instance is
+// invoked.
+type CodeNavServiceGetTypeHierarchyFunc struct {
+	defaultHook func(context.Context, codenav.TypeHierarchyArgs, codenav.RequestState, codenav.TypeHierarchyCursor) (*codenav.TypeHierarchyNode, *codenav.TypeHierarchyCursor, error)
+	hooks       []func(context.Context, codenav.TypeHierarchyArgs, codenav.RequestState, codenav.TypeHierarchyCursor) (*codenav.TypeHierarchyNode, *codenav.TypeHierarchyCursor, error)
+	history     []CodeNavServiceGetTypeHierarchyFuncCall
+	mutex       sync.Mutex
+}
+
+// GetTypeHierarchy delegates to the next hook function in the queue and
+// stores the parameter and result values of this invocation.
+func (m *MockCodeNavService) GetTypeHierarchy(v0 context.Context, v1 codenav.TypeHierarchyArgs, v2 codenav.RequestState, v3 codenav.TypeHierarchyCursor) (*codenav.TypeHierarchyNode, *codenav.TypeHierarchyCursor, error) {
+	r0, r1, r2 := m.GetTypeHierarchyFunc.nextHook()(v0, v1, v2, v3)
+	m.GetTypeHierarchyFunc.appendCall(CodeNavServiceGetTypeHierarchyFuncCall{v0, v1, v2, v3, r0, r1, r2})
+	return r0, r1, r2
+}
+
+// SetDefaultHook sets function that is called when the GetTypeHierarchy
+// method of the parent MockCodeNavService instance is invoked and the hook
+// queue is empty.
+func (f *CodeNavServiceGetTypeHierarchyFunc) SetDefaultHook(hook func(context.Context, codenav.TypeHierarchyArgs, codenav.RequestState, codenav.TypeHierarchyCursor) (*codenav.TypeHierarchyNode, *codenav.TypeHierarchyCursor, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// GetTypeHierarchy method of the parent MockCodeNavService instance invokes
+// the hook at the front of the queue and discards it. After the queue is
+// empty, the default hook function is invoked for any future action.
+func (f *CodeNavServiceGetTypeHierarchyFunc) PushHook(hook func(context.Context, codenav.TypeHierarchyArgs, codenav.RequestState, codenav.TypeHierarchyCursor) (*codenav.TypeHierarchyNode, *codenav.TypeHierarchyCursor, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultHook with a function that returns the
+// given values.
+func (f *CodeNavServiceGetTypeHierarchyFunc) SetDefaultReturn(r0 *codenav.TypeHierarchyNode, r1 *codenav.TypeHierarchyCursor, r2 error) {
+	f.SetDefaultHook(func(context.Context, codenav.TypeHierarchyArgs, codenav.RequestState, codenav.TypeHierarchyCursor) (*codenav.TypeHierarchyNode, *codenav.TypeHierarchyCursor, error) {
+		return r0, r1, r2
+	})
+}
+
+// PushReturn calls PushHook with a function that returns the given values.
+func (f *CodeNavServiceGetTypeHierarchyFunc) PushReturn(r0 *codenav.TypeHierarchyNode, r1 *codenav.TypeHierarchyCursor, r2 error) {
+	f.PushHook(func(context.Context, codenav.TypeHierarchyArgs, codenav.RequestState, codenav.TypeHierarchyCursor) (*codenav.TypeHierarchyNode, *codenav.TypeHierarchyCursor, error) {
+		return r0, r1, r2
+	})
+}
+
+func (f *CodeNavServiceGetTypeHierarchyFunc) nextHook() func(context.Context, codenav.TypeHierarchyArgs, codenav.RequestState, codenav.TypeHierarchyCursor) (*codenav.TypeHierarchyNode, *codenav.TypeHierarchyCursor, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *CodeNavServiceGetTypeHierarchyFunc) appendCall(r0 CodeNavServiceGetTypeHierarchyFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of CodeNavServiceGetTypeHierarchyFuncCall
+// objects describing the invocations of this function.
+func (f *CodeNavServiceGetTypeHierarchyFunc) History() []CodeNavServiceGetTypeHierarchyFuncCall {
+	f.mutex.Lock()
+	history := make([]CodeNavServiceGetTypeHierarchyFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// CodeNavServiceGetTypeHierarchyFuncCall is an object that describes an
+// invocation of method GetTypeHierarchy on an instance of
+// MockCodeNavService.
+type CodeNavServiceGetTypeHierarchyFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 codenav.TypeHierarchyArgs
+	// Arg2 is the value of the 3rd argument passed to this method
+	// invocation.
+	Arg2 codenav.RequestState
+	// Arg3 is the value of the 4th argument passed to this method
+	// invocation.
+	Arg3 codenav.TypeHierarchyCursor
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 *codenav.TypeHierarchyNode
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 *codenav.TypeHierarchyCursor
+	// Result2 is the value of the 3rd result returned from this method
+	// invocation.
+	Result2 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c CodeNavServiceGetTypeHierarchyFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1, c.Arg2, c.Arg3}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c CodeNavServiceGetTypeHierarchyFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1, c.Result2}
+}
+
 // CodeNavServiceSnapshotForDocumentFunc describes the behavior when the
 // SnapshotForDocument method of the parent MockCodeNavService instance is
 // invoked.
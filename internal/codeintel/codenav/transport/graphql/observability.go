@@ -25,6 +25,13 @@ type operations struct {
 	ranges          *observation.Operation
 	snapshot        *observation.Operation
 	visibleIndexes  *observation.Operation
+	incomingCalls   *observation.Operation
+	outgoingCalls   *observation.Operation
+	typeHierarchy   *observation.Operation
+	renamePreview   *observation.Operation
+
+	searchBasedDefinitions *observation.Operation
+	searchBasedReferences  *observation.Operation
 }
 
 func newOperations(observationCtx *observation.Context) *operations {
@@ -55,6 +62,13 @@ func newOperations(observationCtx *observation.Context) *operations {
 		ranges:          op("Ranges"),
 		snapshot:        op("Snapshot"),
 		visibleIndexes:  op("VisibleIndexes"),
+		incomingCalls:   op("IncomingCalls"),
+		outgoingCalls:   op("OutgoingCalls"),
+		typeHierarchy:   op("TypeHierarchy"),
+		renamePreview:   op("RenamePreview"),
+
+		searchBasedDefinitions: op("SearchBasedDefinitions"),
+		searchBasedReferences:  op("SearchBasedReferences"),
 	}
 }
 
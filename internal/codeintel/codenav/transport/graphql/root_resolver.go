@@ -82,6 +82,9 @@ func (r *rootResolver) GitBlobLSIFData(ctx context.Context, args *resolverstubs.
 
 	uploads, err := r.svc.GetClosestDumpsForBlob(ctx, int(args.Repo.ID), string(args.Commit), args.Path, args.ExactPath, args.ToolName)
 	if err != nil || len(uploads) == 0 {
+		if err == nil && args.SearchBasedFallback && args.ExactPath {
+			return r.searchBasedGitBlobLSIFData(args), nil
+		}
 		return nil, err
 	}
 
@@ -129,6 +132,10 @@ type gitBlobLSIFDataResolver struct {
 	indexLoader          uploadsgraphql.IndexLoader
 	locationResolver     *gitresolvers.CachedLocationResolver
 	operations           *operations
+
+	// searchBased is true if no precise upload can answer queries for the requested path, in
+	// which case definitions and references are resolved via search-based code navigation.
+	searchBased bool
 }
 
 // NewQueryResolver creates a new QueryResolver with the given resolver that defines all code intel-specific
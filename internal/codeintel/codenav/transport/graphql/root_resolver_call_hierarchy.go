@@ -0,0 +1,100 @@
+package graphql
+
+import (
+	"context"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/internal/codeintel/codenav"
+	resolverstubs "github.com/sourcegraph/sourcegraph/internal/codeintel/resolvers"
+	"github.com/sourcegraph/sourcegraph/internal/codeintel/shared/resolvers/gitresolvers"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+	"github.com/sourcegraph/sourcegraph/lib/pointers"
+)
+
+// DefaultCallHierarchyPageSize is the number of callers or callees returned when no limit is supplied.
+const DefaultCallHierarchyPageSize = 100
+
+// IncomingCalls returns the functions and methods calling the symbol at the given position.
+func (r *gitBlobLSIFDataResolver) IncomingCalls(ctx context.Context, args *resolverstubs.LSIFCallHierarchyArgs) (_ resolverstubs.CallHierarchyItemConnectionResolver, err error) {
+	requestArgs, err := r.callHierarchyRequestArgs(args)
+	if err != nil {
+		return nil, err
+	}
+	ctx, _, endObservation := observeResolver(ctx, &err, r.operations.incomingCalls, time.Second, getObservationArgs(requestArgs))
+	defer endObservation()
+
+	calls, err := r.codeNavSvc.GetIncomingCalls(ctx, requestArgs, r.requestState)
+	if err != nil {
+		return nil, errors.Wrap(err, "codeNavSvc.GetIncomingCalls")
+	}
+
+	return newCallHierarchyItemConnectionResolver(calls, r.locationResolver), nil
+}
+
+// OutgoingCalls returns the functions and methods called by the function or method defined at the
+// given position.
+func (r *gitBlobLSIFDataResolver) OutgoingCalls(ctx context.Context, args *resolverstubs.LSIFCallHierarchyArgs) (_ resolverstubs.CallHierarchyItemConnectionResolver, err error) {
+	requestArgs, err := r.callHierarchyRequestArgs(args)
+	if err != nil {
+		return nil, err
+	}
+	ctx, _, endObservation := observeResolver(ctx, &err, r.operations.outgoingCalls, time.Second, getObservationArgs(requestArgs))
+	defer endObservation()
+
+	calls, err := r.codeNavSvc.GetOutgoingCalls(ctx, requestArgs, r.requestState)
+	if err != nil {
+		return nil, errors.Wrap(err, "codeNavSvc.GetOutgoingCalls")
+	}
+
+	return newCallHierarchyItemConnectionResolver(calls, r.locationResolver), nil
+}
+
+func (r *gitBlobLSIFDataResolver) callHierarchyRequestArgs(args *resolverstubs.LSIFCallHierarchyArgs) (codenav.PositionalRequestArgs, error) {
+	limit := int(pointers.Deref(args.First, DefaultCallHierarchyPageSize))
+	if limit <= 0 {
+		return codenav.PositionalRequestArgs{}, ErrIllegalLimit
+	}
+
+	return codenav.PositionalRequestArgs{
+		RequestArgs: codenav.RequestArgs{
+			RepositoryID: r.requestState.RepositoryID,
+			Commit:       r.requestState.Commit,
+			Limit:        limit,
+		},
+		Path:      r.requestState.Path,
+		Line:      int(args.Line),
+		Character: int(args.Character),
+	}, nil
+}
+
+//
+//
+
+func newCallHierarchyItemConnectionResolver(calls []codenav.CallHierarchyItem, locationResolver *gitresolvers.CachedLocationResolver) resolverstubs.CallHierarchyItemConnectionResolver {
+	resolvers := make([]resolverstubs.CallHierarchyItemResolver, 0, len(calls))
+	for _, call := range calls {
+		resolvers = append(resolvers, &callHierarchyItemResolver{
+			item:             call,
+			locationResolver: locationResolver,
+		})
+	}
+
+	return resolverstubs.NewConnectionResolver(resolvers)
+}
+
+type callHierarchyItemResolver struct {
+	item             codenav.CallHierarchyItem
+	locationResolver *gitresolvers.CachedLocationResolver
+}
+
+func (r *callHierarchyItemResolver) Symbol() string {
+	return r.item.Symbol
+}
+
+func (r *callHierarchyItemResolver) Definitions(ctx context.Context) (resolverstubs.LocationConnectionResolver, error) {
+	return newLocationConnectionResolver(r.item.Definitions, nil, r.locationResolver), nil
+}
+
+func (r *callHierarchyItemResolver) CallSites(ctx context.Context) (resolverstubs.LocationConnectionResolver, error) {
+	return newLocationConnectionResolver(r.item.CallSites, nil, r.locationResolver), nil
+}
@@ -17,6 +17,10 @@ const DefaultDefinitionsPageSize = 100
 
 // Definitions returns the list of source locations that define the symbol at the given position.
 func (r *gitBlobLSIFDataResolver) Definitions(ctx context.Context, args *resolverstubs.LSIFQueryPositionArgs) (_ resolverstubs.LocationConnectionResolver, err error) {
+	if r.searchBased {
+		return r.searchBasedDefinitions(ctx, args)
+	}
+
 	requestArgs := codenav.PositionalRequestArgs{
 		RequestArgs: codenav.RequestArgs{
 			RepositoryID: r.requestState.RepositoryID,
@@ -18,6 +18,10 @@ const DefaultReferencesPageSize = 100
 
 // References returns the list of source locations that reference the symbol at the given position.
 func (r *gitBlobLSIFDataResolver) References(ctx context.Context, args *resolverstubs.LSIFPagedQueryPositionArgs) (_ resolverstubs.LocationConnectionResolver, err error) {
+	if r.searchBased {
+		return r.searchBasedReferences(ctx, args)
+	}
+
 	limit := int(pointers.Deref(args.First, DefaultReferencesPageSize))
 	if limit <= 0 {
 		return nil, ErrIllegalLimit
@@ -0,0 +1,128 @@
+package graphql
+
+import (
+	"context"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/codeintel/codenav"
+	resolverstubs "github.com/sourcegraph/sourcegraph/internal/codeintel/resolvers"
+	"github.com/sourcegraph/sourcegraph/internal/codeintel/shared/resolvers/gitresolvers"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+func (r *gitBlobLSIFDataResolver) RenamePreview(ctx context.Context, args *resolverstubs.LSIFRenamePreviewArgs) (_ resolverstubs.RenamePreviewResolver, err error) {
+	if args.NewName == "" {
+		return nil, errors.New("newName must not be empty")
+	}
+
+	requestArgs := codenav.PositionalRequestArgs{
+		RequestArgs: codenav.RequestArgs{
+			RepositoryID: r.requestState.RepositoryID,
+			Commit:       r.requestState.Commit,
+		},
+		Path:      r.requestState.Path,
+		Line:      int(args.Line),
+		Character: int(args.Character),
+	}
+	ctx, _, endObservation := observeResolver(ctx, &err, r.operations.renamePreview, time.Second, getObservationArgs(requestArgs))
+	defer endObservation()
+
+	editSet, err := r.codeNavSvc.GetRenameEdits(ctx, requestArgs, r.requestState)
+	if err != nil {
+		return nil, errors.Wrap(err, "codeNavSvc.GetRenameEdits")
+	}
+	if editSet == nil {
+		return nil, nil
+	}
+
+	diffs, err := r.codeNavSvc.GenerateRenameDiffs(ctx, editSet, args.NewName, r.requestState)
+	if err != nil {
+		return nil, errors.Wrap(err, "codeNavSvc.GenerateRenameDiffs")
+	}
+
+	return &renamePreviewResolver{
+		editSet:          editSet,
+		diffs:            diffs,
+		locationResolver: r.locationResolver,
+	}, nil
+}
+
+//
+//
+
+type renamePreviewResolver struct {
+	editSet          *codenav.RenameEditSet
+	diffs            []codenav.RepositoryRenameDiff
+	locationResolver *gitresolvers.CachedLocationResolver
+}
+
+func (r *renamePreviewResolver) Symbol() string {
+	return r.editSet.Symbol
+}
+
+func (r *renamePreviewResolver) Name() string {
+	return r.editSet.Name
+}
+
+func (r *renamePreviewResolver) Repositories() []resolverstubs.RenamePreviewRepositoryResolver {
+	resolvers := make([]resolverstubs.RenamePreviewRepositoryResolver, 0, len(r.editSet.Repositories))
+	for i, edits := range r.editSet.Repositories {
+		// Diffs are generated in the same order as the repositories of the edit set
+		resolvers = append(resolvers, &renamePreviewRepositoryResolver{
+			edits:            edits,
+			diff:             r.diffs[i],
+			locationResolver: r.locationResolver,
+		})
+	}
+
+	return resolvers
+}
+
+type renamePreviewRepositoryResolver struct {
+	edits            codenav.RepositoryRenameEdits
+	diff             codenav.RepositoryRenameDiff
+	locationResolver *gitresolvers.CachedLocationResolver
+}
+
+func (r *renamePreviewRepositoryResolver) Repository(ctx context.Context) (resolverstubs.RepositoryResolver, error) {
+	return r.locationResolver.Repository(ctx, api.RepoID(r.edits.RepositoryID))
+}
+
+func (r *renamePreviewRepositoryResolver) Commit() string {
+	return r.edits.Commit
+}
+
+func (r *renamePreviewRepositoryResolver) Files() []resolverstubs.RenamePreviewFileResolver {
+	resolvers := make([]resolverstubs.RenamePreviewFileResolver, 0, len(r.edits.Files))
+	for _, file := range r.edits.Files {
+		resolvers = append(resolvers, &renamePreviewFileResolver{file: file})
+	}
+
+	return resolvers
+}
+
+func (r *renamePreviewRepositoryResolver) Diff() string {
+	return r.diff.Diff
+}
+
+func (r *renamePreviewRepositoryResolver) SkippedRanges() int32 {
+	return int32(r.diff.SkippedRanges)
+}
+
+type renamePreviewFileResolver struct {
+	file codenav.FileRenameEdits
+}
+
+func (r *renamePreviewFileResolver) Path() string {
+	return r.file.Path
+}
+
+func (r *renamePreviewFileResolver) Ranges() []resolverstubs.RangeResolver {
+	resolvers := make([]resolverstubs.RangeResolver, 0, len(r.file.Ranges))
+	for _, rng := range r.file.Ranges {
+		resolvers = append(resolvers, newRangeResolver(convertRange(rng)))
+	}
+
+	return resolvers
+}
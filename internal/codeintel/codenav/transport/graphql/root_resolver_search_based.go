@@ -0,0 +1,158 @@
+package graphql
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/sourcegraph/sourcegraph/internal/authz"
+	"github.com/sourcegraph/sourcegraph/internal/codeintel/codenav"
+	"github.com/sourcegraph/sourcegraph/internal/codeintel/codenav/shared"
+	resolverstubs "github.com/sourcegraph/sourcegraph/internal/codeintel/resolvers"
+	uploadsshared "github.com/sourcegraph/sourcegraph/internal/codeintel/uploads/shared"
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+	"github.com/sourcegraph/sourcegraph/lib/pointers"
+)
+
+// searchBasedGitBlobLSIFData returns a resolver answering definitions and references queries for
+// the requested file via search-based code navigation. All other queries resolve to empty results,
+// as there is no precise upload from which to read them.
+func (r *rootResolver) searchBasedGitBlobLSIFData(args *resolverstubs.GitBlobLSIFDataArgs) resolverstubs.GitBlobLSIFDataResolver {
+	reqState := codenav.NewRequestState(
+		nil,
+		r.repoStore,
+		authz.DefaultSubRepoPermsChecker,
+		r.gitserverClient,
+		args.Repo,
+		string(args.Commit),
+		args.Path,
+		r.maximumIndexesPerMonikerSearch,
+		r.hunkCache,
+	)
+
+	return &gitBlobLSIFDataResolver{
+		codeNavSvc:           r.svc,
+		indexResolverFactory: r.indexResolverFactory,
+		requestState:         reqState,
+		uploadLoader:         r.uploadLoaderFactory.Create(),
+		indexLoader:          r.indexLoaderFactory.Create(),
+		locationResolver:     r.locationResolverFactory.Create(),
+		operations:           r.operations,
+		searchBased:          true,
+	}
+}
+
+// Precise returns false if this resolver answers queries via search-based code navigation.
+func (r *gitBlobLSIFDataResolver) Precise() bool {
+	return !r.searchBased
+}
+
+// searchBasedDefinitions returns the best-effort definitions of the symbol at the given position.
+func (r *gitBlobLSIFDataResolver) searchBasedDefinitions(ctx context.Context, args *resolverstubs.LSIFQueryPositionArgs) (_ resolverstubs.LocationConnectionResolver, err error) {
+	requestArgs := codenav.PositionalRequestArgs{
+		RequestArgs: codenav.RequestArgs{
+			RepositoryID: r.requestState.RepositoryID,
+			Commit:       r.requestState.Commit,
+			Limit:        DefaultDefinitionsPageSize,
+		},
+		Path:      r.requestState.Path,
+		Line:      int(args.Line),
+		Character: int(args.Character),
+	}
+	ctx, _, endObservation := observeResolver(ctx, &err, r.operations.searchBasedDefinitions, time.Second, getObservationArgs(requestArgs))
+	defer endObservation()
+
+	locations, err := r.codeNavSvc.GetSearchBasedDefinitions(ctx, requestArgs, r.requestState)
+	if err != nil {
+		return nil, errors.Wrap(err, "codeNavSvc.GetSearchBasedDefinitions")
+	}
+
+	return r.newSearchBasedLocationConnectionResolver(filterSearchBasedLocations(locations, args.Filter), nil), nil
+}
+
+// searchBasedReferences returns a page of the best-effort references to the symbol at the given
+// position. The cursor is the offset of the page within the ranked result set.
+func (r *gitBlobLSIFDataResolver) searchBasedReferences(ctx context.Context, args *resolverstubs.LSIFPagedQueryPositionArgs) (_ resolverstubs.LocationConnectionResolver, err error) {
+	limit := int(pointers.Deref(args.First, DefaultReferencesPageSize))
+	if limit <= 0 {
+		return nil, ErrIllegalLimit
+	}
+
+	rawCursor, err := decodeCursor(args.After)
+	if err != nil {
+		return nil, err
+	}
+	offset := 0
+	if rawCursor != "" {
+		if offset, err = strconv.Atoi(rawCursor); err != nil || offset < 0 {
+			return nil, errors.Newf("invalid cursor: %q", rawCursor)
+		}
+	}
+
+	requestArgs := codenav.PositionalRequestArgs{
+		RequestArgs: codenav.RequestArgs{
+			RepositoryID: r.requestState.RepositoryID,
+			Commit:       r.requestState.Commit,
+			Limit:        offset + limit + 1,
+			RawCursor:    rawCursor,
+		},
+		Path:      r.requestState.Path,
+		Line:      int(args.Line),
+		Character: int(args.Character),
+	}
+	ctx, _, endObservation := observeResolver(ctx, &err, r.operations.searchBasedReferences, time.Second, observation.Args{Attrs: append(
+		getObservationArgs(requestArgs).Attrs,
+		attribute.Int("offset", offset),
+	)})
+	defer endObservation()
+
+	locations, err := r.codeNavSvc.GetSearchBasedReferences(ctx, requestArgs, r.requestState)
+	if err != nil {
+		return nil, errors.Wrap(err, "codeNavSvc.GetSearchBasedReferences")
+	}
+
+	var nextCursor *string
+	if len(locations) > offset+limit {
+		nextCursor = pointers.Ptr(strconv.Itoa(offset + limit))
+		locations = locations[offset : offset+limit]
+	} else if len(locations) > offset {
+		locations = locations[offset:]
+	} else {
+		locations = nil
+	}
+
+	return r.newSearchBasedLocationConnectionResolver(filterSearchBasedLocations(locations, args.Filter), nextCursor), nil
+}
+
+func (r *gitBlobLSIFDataResolver) newSearchBasedLocationConnectionResolver(locations []shared.SearchBasedLocation, cursor *string) resolverstubs.LocationConnectionResolver {
+	uploadLocations := make([]shared.UploadLocation, 0, len(locations))
+	for _, location := range locations {
+		uploadLocations = append(uploadLocations, shared.UploadLocation{
+			Dump:         uploadsshared.Dump{RepositoryID: r.requestState.RepositoryID},
+			Path:         location.Path,
+			TargetCommit: r.requestState.Commit,
+			TargetRange:  location.Range,
+		})
+	}
+
+	return newLocationConnectionResolver(uploadLocations, cursor, r.locationResolver)
+}
+
+func filterSearchBasedLocations(locations []shared.SearchBasedLocation, filter *string) []shared.SearchBasedLocation {
+	if filter == nil || *filter == "" {
+		return locations
+	}
+
+	filtered := locations[:0]
+	for _, location := range locations {
+		if strings.Contains(location.Path, *filter) {
+			filtered = append(filtered, location)
+		}
+	}
+
+	return filtered
+}
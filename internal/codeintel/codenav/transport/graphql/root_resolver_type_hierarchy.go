@@ -0,0 +1,153 @@
+package graphql
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/internal/codeintel/codenav"
+	resolverstubs "github.com/sourcegraph/sourcegraph/internal/codeintel/resolvers"
+	"github.com/sourcegraph/sourcegraph/internal/codeintel/shared/resolvers/gitresolvers"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+	"github.com/sourcegraph/sourcegraph/lib/pointers"
+)
+
+// DefaultTypeHierarchyPageSize is the number of types returned per page when no limit is supplied.
+const DefaultTypeHierarchyPageSize = 100
+
+// DefaultTypeHierarchyDepth is the number of levels walked below the root type when no depth is supplied.
+const DefaultTypeHierarchyDepth = 3
+
+// ErrIllegalDepth occurs when the user requests a type hierarchy with a negative depth.
+var ErrIllegalDepth = errors.New("illegal depth")
+
+func (r *gitBlobLSIFDataResolver) TypeHierarchy(ctx context.Context, args *resolverstubs.LSIFTypeHierarchyArgs) (_ resolverstubs.TypeHierarchyResolver, err error) {
+	limit := int(pointers.Deref(args.First, DefaultTypeHierarchyPageSize))
+	if limit <= 0 {
+		return nil, ErrIllegalLimit
+	}
+	depth := int(pointers.Deref(args.Depth, DefaultTypeHierarchyDepth))
+	if depth < 0 {
+		return nil, ErrIllegalDepth
+	}
+
+	rawCursor, err := decodeCursor(args.After)
+	if err != nil {
+		return nil, err
+	}
+
+	requestArgs := codenav.TypeHierarchyArgs{
+		PositionalRequestArgs: codenav.PositionalRequestArgs{
+			RequestArgs: codenav.RequestArgs{
+				RepositoryID: r.requestState.RepositoryID,
+				Commit:       r.requestState.Commit,
+				Limit:        limit,
+				RawCursor:    rawCursor,
+			},
+			Path:      r.requestState.Path,
+			Line:      int(args.Line),
+			Character: int(args.Character),
+		},
+		Direction: codenav.TypeHierarchyDirection(strings.ToLower(args.Direction)),
+		Depth:     depth,
+	}
+	ctx, _, endObservation := observeResolver(ctx, &err, r.operations.typeHierarchy, time.Second, getObservationArgs(requestArgs.PositionalRequestArgs))
+	defer endObservation()
+
+	cursor, err := decodeTypeHierarchyCursor(rawCursor)
+	if err != nil {
+		return nil, errors.Wrap(err, fmt.Sprintf("invalid cursor: %q", rawCursor))
+	}
+
+	root, nextCursor, err := r.codeNavSvc.GetTypeHierarchy(ctx, requestArgs, r.requestState, cursor)
+	if err != nil {
+		return nil, errors.Wrap(err, "codeNavSvc.GetTypeHierarchy")
+	}
+	if root == nil {
+		return nil, nil
+	}
+
+	var endCursor string
+	if nextCursor != nil {
+		endCursor = encodeCursor(pointers.Ptr(encodeTypeHierarchyCursor(*nextCursor)))
+	}
+
+	return &typeHierarchyResolver{
+		root:      newTypeHierarchyNodeResolver(root, r.locationResolver),
+		endCursor: endCursor,
+	}, nil
+}
+
+//
+//
+
+type typeHierarchyResolver struct {
+	root      resolverstubs.TypeHierarchyNodeResolver
+	endCursor string
+}
+
+func (r *typeHierarchyResolver) Root() resolverstubs.TypeHierarchyNodeResolver {
+	return r.root
+}
+
+func (r *typeHierarchyResolver) PageInfo() resolverstubs.PageInfo {
+	return resolverstubs.NewPageInfoFromCursor(r.endCursor)
+}
+
+type typeHierarchyNodeResolver struct {
+	node             *codenav.TypeHierarchyNode
+	locationResolver *gitresolvers.CachedLocationResolver
+}
+
+func newTypeHierarchyNodeResolver(node *codenav.TypeHierarchyNode, locationResolver *gitresolvers.CachedLocationResolver) resolverstubs.TypeHierarchyNodeResolver {
+	return &typeHierarchyNodeResolver{
+		node:             node,
+		locationResolver: locationResolver,
+	}
+}
+
+func (r *typeHierarchyNodeResolver) Symbol() string {
+	return r.node.Symbol
+}
+
+func (r *typeHierarchyNodeResolver) Definitions(ctx context.Context) (resolverstubs.LocationConnectionResolver, error) {
+	return newLocationConnectionResolver(r.node.Definitions, nil, r.locationResolver), nil
+}
+
+func (r *typeHierarchyNodeResolver) Children() []resolverstubs.TypeHierarchyNodeResolver {
+	children := make([]resolverstubs.TypeHierarchyNodeResolver, 0, len(r.node.Children))
+	for _, child := range r.node.Children {
+		children = append(children, newTypeHierarchyNodeResolver(child, r.locationResolver))
+	}
+
+	return children
+}
+
+//
+//
+
+// decodeTypeHierarchyCursor is the inverse of encodeTypeHierarchyCursor. If the given encoded
+// string is empty, then a fresh cursor is returned.
+func decodeTypeHierarchyCursor(rawEncoded string) (codenav.TypeHierarchyCursor, error) {
+	if rawEncoded == "" {
+		return codenav.TypeHierarchyCursor{}, nil
+	}
+
+	raw, err := base64.RawURLEncoding.DecodeString(rawEncoded)
+	if err != nil {
+		return codenav.TypeHierarchyCursor{}, err
+	}
+
+	var cursor codenav.TypeHierarchyCursor
+	err = json.Unmarshal(raw, &cursor)
+	return cursor, err
+}
+
+// encodeTypeHierarchyCursor returns an encoding of the given cursor suitable for a URL or a GraphQL token.
+func encodeTypeHierarchyCursor(cursor codenav.TypeHierarchyCursor) string {
+	rawEncoded, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(rawEncoded)
+}
@@ -81,6 +81,78 @@ type AdjustedCodeIntelligenceRange struct {
 	HoverText       string
 }
 
+// CallHierarchyItem is a function or method related to the symbol for which a call hierarchy
+// was requested, along with the call sites relating the two. For incoming calls, the item is a
+// caller and the call sites are references to the requested symbol within the caller's body.
+// For outgoing calls, the item is a callee and the call sites are references to the callee
+// within the body of the requested symbol. All locations have been adjusted to fit the target
+// (originally requested) commit.
+type CallHierarchyItem struct {
+	Symbol      string
+	Definitions []shared.UploadLocation
+	CallSites   []shared.UploadLocation
+}
+
+// TypeHierarchyDirection determines whether a type hierarchy is walked towards the types a type
+// implements, or towards the types implementing it.
+type TypeHierarchyDirection string
+
+const (
+	TypeHierarchySupertypes TypeHierarchyDirection = "supertypes"
+	TypeHierarchySubtypes   TypeHierarchyDirection = "subtypes"
+)
+
+type TypeHierarchyArgs struct {
+	PositionalRequestArgs
+	Direction TypeHierarchyDirection
+	Depth     int
+}
+
+// TypeHierarchyNode is a type within a type hierarchy. The children of a node are its supertypes
+// or subtypes, depending on the direction of the hierarchy. The definition locations have been
+// adjusted to fit the target (originally requested) commit.
+type TypeHierarchyNode struct {
+	Symbol      string
+	Definitions []shared.UploadLocation
+	Children    []*TypeHierarchyNode
+}
+
+// TypeHierarchyCursor stores the state of a previous type hierarchy request used to resume the
+// walk on a subsequent page.
+type TypeHierarchyCursor struct {
+	Offset int `json:"o"` // number of nodes (besides the root) returned on previous pages
+}
+
+// RenameEditSet is the set of ranges which need to be edited to rename a symbol, grouped by
+// repository, commit, and path. Name is the current name of the symbol as it appears in source.
+type RenameEditSet struct {
+	Symbol       string
+	Name         string
+	Repositories []RepositoryRenameEdits
+}
+
+type RepositoryRenameEdits struct {
+	RepositoryID   int
+	RepositoryName string
+	Commit         string
+	Files          []FileRenameEdits
+}
+
+type FileRenameEdits struct {
+	Path   string
+	Ranges []shared.Range
+}
+
+// RepositoryRenameDiff is a unified diff renaming a symbol within a single repository. Skipped
+// ranges are edits of the edit set which could not be applied to the files of the repository.
+type RepositoryRenameDiff struct {
+	RepositoryID   int
+	RepositoryName string
+	Commit         string
+	Diff           string
+	SkippedRanges  int
+}
+
 // Cursor is a struct that holds the state necessary to resume a locations query from a second or
 // subsequent request. This struct is used internally as a request-specific context object that is
 // mutated as the locations request is fulfilled. This struct is serialized to JSON then base64
@@ -17,6 +17,7 @@ type operations struct {
 	getRepoIDsByGlobPatterns                    *observation.Operation
 	updateReposMatchingPatterns                 *observation.Operation
 	selectPoliciesForRepositoryMembershipUpdate *observation.Operation
+	getRepoIDsWithUploads                       *observation.Operation
 }
 
 var m = new(metrics.SingletonREDMetrics)
@@ -49,5 +50,6 @@ func newOperations(observationCtx *observation.Context) *operations {
 		getRepoIDsByGlobPatterns:                    op("GetRepoIDsByGlobPatterns"),
 		updateReposMatchingPatterns:                 op("UpdateReposMatchingPatterns"),
 		selectPoliciesForRepositoryMembershipUpdate: op("SelectPoliciesForRepositoryMembershipUpdate"),
+		getRepoIDsWithUploads:                       op("GetRepoIDsWithUploads"),
 	}
 }
@@ -194,3 +194,37 @@ RETURNING
 	index_intermediate_commits,
 	embeddings_enabled
 `
+
+// GetRepoIDsWithUploads returns the identifiers of the repositories, ordered by identifier and
+// greater than the given one, that have completed and unexpired uploads which are part of the
+// commit graph. These are the uploads considered by the upload expirer.
+func (s *store) GetRepoIDsWithUploads(ctx context.Context, afterRepositoryID, limit int) (_ []int, err error) {
+	ctx, _, endObservation := s.operations.getRepoIDsWithUploads.With(ctx, &err, observation.Args{Attrs: []attribute.KeyValue{
+		attribute.Int("afterRepositoryID", afterRepositoryID),
+		attribute.Int("limit", limit),
+	}})
+	defer endObservation(1, observation.Args{})
+
+	return basestore.ScanInts(s.db.Query(ctx, sqlf.Sprintf(repoIDsWithUploadsQuery, afterRepositoryID, limit)))
+}
+
+const repoIDsWithUploadsQuery = `
+SELECT ldr.repository_id
+FROM lsif_dirty_repositories ldr
+JOIN repo r ON r.id = ldr.repository_id
+WHERE
+	ldr.repository_id > %s AND
+	r.deleted_at IS NULL AND
+	r.blocked IS NULL AND
+	EXISTS (
+		SELECT 1
+		FROM lsif_uploads u
+		WHERE
+			u.repository_id = ldr.repository_id AND
+			u.state = 'completed' AND
+			NOT u.expired AND
+			u.finished_at < ldr.updated_at
+	)
+ORDER BY ldr.repository_id
+LIMIT %s
+`
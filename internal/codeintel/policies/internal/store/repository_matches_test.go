@@ -89,6 +89,59 @@ func TestRepoIDsByGlobPatterns(t *testing.T) {
 	})
 }
 
+func TestGetRepoIDsWithUploads(t *testing.T) {
+	ctx := context.Background()
+	logger := logtest.Scoped(t)
+	db := database.NewDB(logger, dbtest.NewDB(t))
+	store := New(&observation.TestContext, db)
+
+	for id := 50; id <= 55; id++ {
+		insertRepo(t, db, id, "", false)
+	}
+	insertRepo(t, db, 56, "DELETED-r56", false)
+
+	for _, query := range []string{
+		`INSERT INTO lsif_dirty_repositories (repository_id, dirty_token, update_token, updated_at) SELECT id, 1, 1, NOW() FROM repo`,
+		`INSERT INTO lsif_uploads (repository_id, commit, indexer, num_parts, uploaded_parts, state, expired, finished_at) VALUES
+			(50, 'deadbeef', 'scip-go', 1, '{}', 'completed', false, NOW() - '1 hour'::interval),
+			(51, 'deadbeef', 'scip-go', 1, '{}', 'completed', false, NOW() - '1 hour'::interval),
+			(52, 'deadbeef', 'scip-go', 1, '{}', 'completed', true,  NOW() - '1 hour'::interval), -- expired
+			(53, 'deadbeef', 'scip-go', 1, '{}', 'errored',   false, NOW() - '1 hour'::interval), -- not completed
+			(54, 'deadbeef', 'scip-go', 1, '{}', 'completed', false, NOW() + '1 hour'::interval), -- not yet in commit graph
+			(55, 'deadbeef', 'scip-go', 1, '{}', 'completed', false, NOW() - '1 hour'::interval),
+			(56, 'deadbeef', 'scip-go', 1, '{}', 'completed', false, NOW() - '1 hour'::interval)  -- deleted repository
+		`,
+	} {
+		if _, err := db.ExecContext(ctx, query); err != nil {
+			t.Fatalf("unexpected error inserting test data: %s", err)
+		}
+	}
+
+	testCases := []struct {
+		afterRepositoryID     int
+		limit                 int
+		expectedRepositoryIDs []int
+	}{
+		{afterRepositoryID: 0, limit: 10, expectedRepositoryIDs: []int{50, 51, 55}},
+		{afterRepositoryID: 0, limit: 2, expectedRepositoryIDs: []int{50, 51}},
+		{afterRepositoryID: 51, limit: 2, expectedRepositoryIDs: []int{55}},
+		{afterRepositoryID: 55, limit: 2, expectedRepositoryIDs: nil},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(fmt.Sprintf("after=%d limit=%d", testCase.afterRepositoryID, testCase.limit), func(t *testing.T) {
+			repositoryIDs, err := store.GetRepoIDsWithUploads(ctx, testCase.afterRepositoryID, testCase.limit)
+			if err != nil {
+				t.Fatalf("unexpected error fetching repository ids with uploads: %s", err)
+			}
+
+			if diff := cmp.Diff(testCase.expectedRepositoryIDs, repositoryIDs); diff != "" {
+				t.Errorf("unexpected repository ids (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
 func TestUpdateReposMatchingPatterns(t *testing.T) {
 	ctx := context.Background()
 	logger := logtest.Scoped(t)
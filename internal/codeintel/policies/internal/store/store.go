@@ -27,6 +27,7 @@ type Store interface {
 	GetRepoIDsByGlobPatterns(ctx context.Context, patterns []string, limit, offset int) ([]int, int, error)
 	UpdateReposMatchingPatterns(ctx context.Context, patterns []string, policyID int, repositoryMatchLimit *int) error
 	SelectPoliciesForRepositoryMembershipUpdate(ctx context.Context, batchSize int) ([]shared.ConfigurationPolicy, error)
+	GetRepoIDsWithUploads(ctx context.Context, afterRepositoryID, limit int) ([]int, error)
 }
 
 type store struct {
@@ -12,6 +12,7 @@ import (
 
 	store "github.com/sourcegraph/sourcegraph/internal/codeintel/policies/internal/store"
 	shared "github.com/sourcegraph/sourcegraph/internal/codeintel/policies/shared"
+	shared1 "github.com/sourcegraph/sourcegraph/internal/codeintel/uploads/shared"
 )
 
 // MockStore is a mock implementation of the Store interface (from the
@@ -37,6 +38,9 @@ type MockStore struct {
 	// GetRepoIDsByGlobPatternsFunc is an instance of a mock function object
 	// controlling the behavior of the method GetRepoIDsByGlobPatterns.
 	GetRepoIDsByGlobPatternsFunc *StoreGetRepoIDsByGlobPatternsFunc
+	// GetRepoIDsWithUploadsFunc is an instance of a mock function object
+	// controlling the behavior of the method GetRepoIDsWithUploads.
+	GetRepoIDsWithUploadsFunc *StoreGetRepoIDsWithUploadsFunc
 	// RepoCountFunc is an instance of a mock function object controlling
 	// the behavior of the method RepoCount.
 	RepoCountFunc *StoreRepoCountFunc
@@ -83,6 +87,11 @@ func NewMockStore() *MockStore {
 				return
 			},
 		},
+		GetRepoIDsWithUploadsFunc: &StoreGetRepoIDsWithUploadsFunc{
+			defaultHook: func(context.Context, int, int) (r0 []int, r1 error) {
+				return
+			},
+		},
 		RepoCountFunc: &StoreRepoCountFunc{
 			defaultHook: func(context.Context) (r0 int, r1 error) {
 				return
@@ -135,6 +144,11 @@ func NewStrictMockStore() *MockStore {
 				panic("unexpected invocation of MockStore.GetRepoIDsByGlobPatterns")
 			},
 		},
+		GetRepoIDsWithUploadsFunc: &StoreGetRepoIDsWithUploadsFunc{
+			defaultHook: func(context.Context, int, int) ([]int, error) {
+				panic("unexpected invocation of MockStore.GetRepoIDsWithUploads")
+			},
+		},
 		RepoCountFunc: &StoreRepoCountFunc{
 			defaultHook: func(context.Context) (int, error) {
 				panic("unexpected invocation of MockStore.RepoCount")
@@ -177,6 +191,9 @@ func NewMockStoreFrom(i store.Store) *MockStore {
 		GetRepoIDsByGlobPatternsFunc: &StoreGetRepoIDsByGlobPatternsFunc{
 			defaultHook: i.GetRepoIDsByGlobPatterns,
 		},
+		GetRepoIDsWithUploadsFunc: &StoreGetRepoIDsWithUploadsFunc{
+			defaultHook: i.GetRepoIDsWithUploads,
+		},
 		RepoCountFunc: &StoreRepoCountFunc{
 			defaultHook: i.RepoCount,
 		},
@@ -756,6 +773,117 @@ func (c StoreGetRepoIDsByGlobPatternsFuncCall) Results() []interface{} {
 	return []interface{}{c.Result0, c.Result1, c.Result2}
 }
 
+// StoreGetRepoIDsWithUploadsFunc describes the behavior when the
+// GetRepoIDsWithUploads method of the parent MockStore instance is invoked.
+type StoreGetRepoIDsWithUploadsFunc struct {
+	defaultHook func(context.Context, int, int) ([]int, error)
+	hooks       []func(context.Context, int, int) ([]int, error)
+	history     []StoreGetRepoIDsWithUploadsFuncCall
+	mutex       sync.Mutex
+}
+
+// GetRepoIDsWithUploads delegates to the next hook function in the queue
+// and stores the parameter and result values of this invocation.
+func (m *MockStore) GetRepoIDsWithUploads(v0 context.Context, v1 int, v2 int) ([]int, error) {
+	r0, r1 := m.GetRepoIDsWithUploadsFunc.nextHook()(v0, v1, v2)
+	m.GetRepoIDsWithUploadsFunc.appendCall(StoreGetRepoIDsWithUploadsFuncCall{v0, v1, v2, r0, r1})
+	return r0, r1
+}
+
+// SetDefaultHook sets function that is called when the
+// GetRepoIDsWithUploads method of the parent MockStore instance is invoked
+// and the hook queue is empty.
+func (f *StoreGetRepoIDsWithUploadsFunc) SetDefaultHook(hook func(context.Context, int, int) ([]int, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// GetRepoIDsWithUploads method of the parent MockStore instance invokes the
+// hook at the front of the queue and discards it. After the queue is empty,
+// the default hook function is invoked for any future action.
+func (f *StoreGetRepoIDsWithUploadsFunc) PushHook(hook func(context.Context, int, int) ([]int, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultHook with a function that returns the
+// given values.
+func (f *StoreGetRepoIDsWithUploadsFunc) SetDefaultReturn(r0 []int, r1 error) {
+	f.SetDefaultHook(func(context.Context, int, int) ([]int, error) {
+		return r0, r1
+	})
+}
+
+// PushReturn calls PushHook with a function that returns the given values.
+func (f *StoreGetRepoIDsWithUploadsFunc) PushReturn(r0 []int, r1 error) {
+	f.PushHook(func(context.Context, int, int) ([]int, error) {
+		return r0, r1
+	})
+}
+
+func (f *StoreGetRepoIDsWithUploadsFunc) nextHook() func(context.Context, int, int) ([]int, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *StoreGetRepoIDsWithUploadsFunc) appendCall(r0 StoreGetRepoIDsWithUploadsFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of StoreGetRepoIDsWithUploadsFuncCall objects
+// describing the invocations of this function.
+func (f *StoreGetRepoIDsWithUploadsFunc) History() []StoreGetRepoIDsWithUploadsFuncCall {
+	f.mutex.Lock()
+	history := make([]StoreGetRepoIDsWithUploadsFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// StoreGetRepoIDsWithUploadsFuncCall is an object that describes an
+// invocation of method GetRepoIDsWithUploads on an instance of MockStore.
+type StoreGetRepoIDsWithUploadsFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 int
+	// Arg2 is the value of the 3rd argument passed to this method
+	// invocation.
+	Arg2 int
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 []int
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c StoreGetRepoIDsWithUploadsFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1, c.Arg2}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c StoreGetRepoIDsWithUploadsFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1}
+}
+
 // StoreRepoCountFunc describes the behavior when the RepoCount method of
 // the parent MockStore instance is invoked.
 type StoreRepoCountFunc struct {
@@ -1203,6 +1331,9 @@ type MockUploadService struct {
 	// object controlling the behavior of the method
 	// GetCommitsVisibleToUpload.
 	GetCommitsVisibleToUploadFunc *UploadServiceGetCommitsVisibleToUploadFunc
+	// GetUploadsFunc is an instance of a mock function object controlling
+	// the behavior of the method GetUploads.
+	GetUploadsFunc *UploadServiceGetUploadsFunc
 }
 
 // NewMockUploadService creates a new mock of the UploadService interface.
@@ -1214,6 +1345,11 @@ func NewMockUploadService() *MockUploadService {
 				return
 			},
 		},
+		GetUploadsFunc: &UploadServiceGetUploadsFunc{
+			defaultHook: func(context.Context, shared1.GetUploadsOptions) (r0 []shared1.Upload, r1 int, r2 error) {
+				return
+			},
+		},
 	}
 }
 
@@ -1226,6 +1362,11 @@ func NewStrictMockUploadService() *MockUploadService {
 				panic("unexpected invocation of MockUploadService.GetCommitsVisibleToUpload")
 			},
 		},
+		GetUploadsFunc: &UploadServiceGetUploadsFunc{
+			defaultHook: func(context.Context, shared1.GetUploadsOptions) ([]shared1.Upload, int, error) {
+				panic("unexpected invocation of MockUploadService.GetUploads")
+			},
+		},
 	}
 }
 
@@ -1237,6 +1378,9 @@ func NewMockUploadServiceFrom(i UploadService) *MockUploadService {
 		GetCommitsVisibleToUploadFunc: &UploadServiceGetCommitsVisibleToUploadFunc{
 			defaultHook: i.GetCommitsVisibleToUpload,
 		},
+		GetUploadsFunc: &UploadServiceGetUploadsFunc{
+			defaultHook: i.GetUploads,
+		},
 	}
 }
 
@@ -1360,3 +1504,114 @@ func (c UploadServiceGetCommitsVisibleToUploadFuncCall) Args() []interface{} {
 func (c UploadServiceGetCommitsVisibleToUploadFuncCall) Results() []interface{} {
 	return []interface{}{c.Result0, c.Result1, c.Result2}
 }
+
+// UploadServiceGetUploadsFunc describes the behavior when the GetUploads
+// method of the parent MockUploadService instance is invoked.
+type UploadServiceGetUploadsFunc struct {
+	defaultHook func(context.Context, shared1.GetUploadsOptions) ([]shared1.Upload, int, error)
+	hooks       []func(context.Context, shared1.GetUploadsOptions) ([]shared1.Upload, int, error)
+	history     []UploadServiceGetUploadsFuncCall
+	mutex       sync.Mutex
+}
+
+// GetUploads delegates to the next hook function in the queue and stores
+// the parameter and result values of this invocation.
+func (m *MockUploadService) GetUploads(v0 context.Context, v1 shared1.GetUploadsOptions) ([]shared1.Upload, int, error) {
+	r0, r1, r2 := m.GetUploadsFunc.nextHook()(v0, v1)
+	m.GetUploadsFunc.appendCall(UploadServiceGetUploadsFuncCall{v0, v1, r0, r1, r2})
+	return r0, r1, r2
+}
+
+// SetDefaultHook sets function that is called when the GetUploads method of
+// the parent MockUploadService instance is invoked and the hook queue is
+// empty.
+func (f *UploadServiceGetUploadsFunc) SetDefaultHook(hook func(context.Context, shared1.GetUploadsOptions) ([]shared1.Upload, int, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// GetUploads method of the parent MockUploadService instance invokes the
+// hook at the front of the queue and discards it. After the queue is empty,
+// the default hook function is invoked for any future action.
+func (f *UploadServiceGetUploadsFunc) PushHook(hook func(context.Context, shared1.GetUploadsOptions) ([]shared1.Upload, int, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultHook with a function that returns the
+// given values.
+func (f *UploadServiceGetUploadsFunc) SetDefaultReturn(r0 []shared1.Upload, r1 int, r2 error) {
+	f.SetDefaultHook(func(context.Context, shared1.GetUploadsOptions) ([]shared1.Upload, int, error) {
+		return r0, r1, r2
+	})
+}
+
+// PushReturn calls PushHook with a function that returns the given values.
+func (f *UploadServiceGetUploadsFunc) PushReturn(r0 []shared1.Upload, r1 int, r2 error) {
+	f.PushHook(func(context.Context, shared1.GetUploadsOptions) ([]shared1.Upload, int, error) {
+		return r0, r1, r2
+	})
+}
+
+func (f *UploadServiceGetUploadsFunc) nextHook() func(context.Context, shared1.GetUploadsOptions) ([]shared1.Upload, int, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *UploadServiceGetUploadsFunc) appendCall(r0 UploadServiceGetUploadsFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of UploadServiceGetUploadsFuncCall objects
+// describing the invocations of this function.
+func (f *UploadServiceGetUploadsFunc) History() []UploadServiceGetUploadsFuncCall {
+	f.mutex.Lock()
+	history := make([]UploadServiceGetUploadsFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// UploadServiceGetUploadsFuncCall is an object that describes an invocation
+// of method GetUploads on an instance of MockUploadService.
+type UploadServiceGetUploadsFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 shared1.GetUploadsOptions
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 []shared1.Upload
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 int
+	// Result2 is the value of the 3rd result returned from this method
+	// invocation.
+	Result2 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c UploadServiceGetUploadsFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c UploadServiceGetUploadsFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1, c.Result2}
+}
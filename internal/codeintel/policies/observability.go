@@ -10,6 +10,7 @@ import (
 type operations struct {
 	updateConfigurationPolicy  *observation.Operation
 	getRetentionPolicyOverview *observation.Operation
+	getRetentionPolicyImpact   *observation.Operation
 	getPreviewRepositoryFilter *observation.Operation
 	getPreviewGitObjectFilter  *observation.Operation
 }
@@ -37,6 +38,7 @@ func newOperations(observationCtx *observation.Context) *operations {
 	return &operations{
 		updateConfigurationPolicy:  op("UpdateConfigurationPolicy"),
 		getRetentionPolicyOverview: op("GetRetentionPolicyOverview"),
+		getRetentionPolicyImpact:   op("GetRetentionPolicyImpact"),
 		getPreviewRepositoryFilter: op("GetPreviewRepositoryFilter"),
 		getPreviewGitObjectFilter:  op("GetPreviewGitObjectFilter"),
 	}
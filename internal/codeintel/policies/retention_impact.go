@@ -0,0 +1,252 @@
+package policies
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	policiesshared "github.com/sourcegraph/sourcegraph/internal/codeintel/policies/shared"
+	"github.com/sourcegraph/sourcegraph/internal/codeintel/uploads/shared"
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+const (
+	retentionImpactPolicyBatchSize     = 100
+	retentionImpactRepositoryBatchSize = 1000
+
+	// retentionImpactMaxUploadsPerRepository bounds the number of uploads evaluated per repository,
+	// as the commits visible to each upload are determined individually. The oldest uploads, which
+	// are the first to be expired, are evaluated.
+	retentionImpactMaxUploadsPerRepository = 1000
+)
+
+// GetRetentionPolicyImpact determines which uploads would be expired if the given configuration policy
+// were in effect. If the proposed policy has an identifier, it replaces the existing policy with that
+// identifier; otherwise it is evaluated in addition to the existing policies.
+//
+// Uploads are evaluated in the same way as the upload expirer does: an upload is protected if any of
+// the commits from which it is visible is matched by a data retention policy whose retention duration
+// has not yet passed. Only repositories to which the proposed (or replaced) policy applies can be
+// affected, so all other repositories are omitted from the result. No records are modified.
+//
+// Repositories are evaluated in order of their identifier, starting after the given repository. At most
+// limit repositories are returned, and at most retentionImpactRepositoryBatchSize repositories are
+// considered in one call. If there may be more affected repositories, the repository identifier to
+// pass to a subsequent call is returned; otherwise zero is returned.
+func (s *Service) GetRetentionPolicyImpact(ctx context.Context, proposedPolicy policiesshared.ConfigurationPolicy, now time.Time, limit, afterRepositoryID int) (_ []policiesshared.RetentionPolicyImpact, nextRepositoryID int, err error) {
+	ctx, _, endObservation := s.operations.getRetentionPolicyImpact.With(ctx, &err, observation.Args{Attrs: []attribute.KeyValue{
+		attribute.Int("policyID", proposedPolicy.ID),
+		attribute.Int("limit", limit),
+		attribute.Int("afterRepositoryID", afterRepositoryID),
+	}})
+	defer endObservation(1, observation.Args{})
+
+	repositoryIDs, err := s.getRetentionPolicyImpactCandidates(ctx, proposedPolicy, afterRepositoryID)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	appliesToRepository, err := s.proposedPolicyApplicability(ctx, proposedPolicy)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var impacts []policiesshared.RetentionPolicyImpact
+	for i, repositoryID := range repositoryIDs {
+		impact, ok, err := s.getRetentionPolicyImpactForRepository(ctx, proposedPolicy, appliesToRepository(repositoryID), repositoryID, now)
+		if err != nil {
+			return nil, 0, err
+		}
+		if !ok {
+			continue
+		}
+
+		impacts = append(impacts, impact)
+		if len(impacts) >= limit && i < len(repositoryIDs)-1 {
+			return impacts, repositoryID, nil
+		}
+	}
+
+	if len(repositoryIDs) == retentionImpactRepositoryBatchSize {
+		return impacts, repositoryIDs[len(repositoryIDs)-1], nil
+	}
+	return impacts, 0, nil
+}
+
+// getRetentionPolicyImpactCandidates returns the identifiers of the repositories following the given
+// repository that may be affected by the proposed policy.
+func (s *Service) getRetentionPolicyImpactCandidates(ctx context.Context, proposedPolicy policiesshared.ConfigurationPolicy, afterRepositoryID int) ([]int, error) {
+	if proposedPolicy.ID == 0 && proposedPolicy.RepositoryID != nil {
+		// A new policy for a single repository cannot affect other repositories
+		if *proposedPolicy.RepositoryID <= afterRepositoryID {
+			return nil, nil
+		}
+
+		return []int{*proposedPolicy.RepositoryID}, nil
+	}
+
+	repositoryIDs, err := s.store.GetRepoIDsWithUploads(ctx, afterRepositoryID, retentionImpactRepositoryBatchSize)
+	if err != nil {
+		return nil, errors.Wrap(err, "store.GetRepoIDsWithUploads")
+	}
+
+	return repositoryIDs, nil
+}
+
+// getRetentionPolicyImpactForRepository determines which uploads of the given repository would be
+// expired with the proposed policy in effect. False is returned if the proposed policy does not
+// affect the repository or the repository has no uploads to evaluate.
+func (s *Service) getRetentionPolicyImpactForRepository(ctx context.Context, proposedPolicy policiesshared.ConfigurationPolicy, appliesToRepository bool, repositoryID int, now time.Time) (policiesshared.RetentionPolicyImpact, bool, error) {
+	currentPolicies, err := s.getRetentionPoliciesForRepository(ctx, repositoryID)
+	if err != nil {
+		return policiesshared.RetentionPolicyImpact{}, false, err
+	}
+
+	var (
+		replacesPolicy   = proposedPolicy.ID != 0 && policyByID(currentPolicies, proposedPolicy.ID) != nil
+		proposedApplies  = proposedPolicy.RetentionEnabled && appliesToRepository
+		proposedPolicies = make([]policiesshared.ConfigurationPolicy, 0, len(currentPolicies)+1)
+	)
+	if !replacesPolicy && !proposedApplies {
+		return policiesshared.RetentionPolicyImpact{}, false, nil
+	}
+
+	uploads, _, err := s.uploadSvc.GetUploads(ctx, shared.GetUploadsOptions{
+		RepositoryID:  repositoryID,
+		State:         "completed",
+		AllowExpired:  false,
+		InCommitGraph: true,
+		OldestFirst:   true,
+		Limit:         retentionImpactMaxUploadsPerRepository,
+	})
+	if err != nil {
+		return policiesshared.RetentionPolicyImpact{}, false, errors.Wrap(err, "uploadSvc.GetUploads")
+	}
+	if len(uploads) == 0 {
+		return policiesshared.RetentionPolicyImpact{}, false, nil
+	}
+
+	for _, policy := range currentPolicies {
+		if proposedPolicy.ID == 0 || policy.ID != proposedPolicy.ID {
+			proposedPolicies = append(proposedPolicies, policy)
+		}
+	}
+	if proposedApplies {
+		proposedPolicies = append(proposedPolicies, proposedPolicy)
+	}
+
+	repo, err := s.repoStore.Get(ctx, api.RepoID(repositoryID))
+	if err != nil {
+		return policiesshared.RetentionPolicyImpact{}, false, err
+	}
+
+	policyMatcher := s.getPolicyMatcherFromFactory(RetentionExtractor, true, false)
+	currentCommitMap, err := policyMatcher.CommitsDescribedByPolicy(ctx, repositoryID, repo.Name, currentPolicies, now)
+	if err != nil {
+		return policiesshared.RetentionPolicyImpact{}, false, err
+	}
+	proposedCommitMap, err := policyMatcher.CommitsDescribedByPolicy(ctx, repositoryID, repo.Name, proposedPolicies, now)
+	if err != nil {
+		return policiesshared.RetentionPolicyImpact{}, false, err
+	}
+
+	impact := policiesshared.RetentionPolicyImpact{RepositoryID: repositoryID}
+	for _, upload := range uploads {
+		visibleCommits, err := s.getCommitsVisibleToUpload(ctx, upload)
+		if err != nil {
+			return policiesshared.RetentionPolicyImpact{}, false, err
+		}
+
+		impact.NumUploads++
+		if !isUploadProtected(currentCommitMap, upload, visibleCommits, now) {
+			impact.NumCurrentlyExpired++
+		}
+		if !isUploadProtected(proposedCommitMap, upload, visibleCommits, now) {
+			impact.NumExpired++
+			if upload.UploadSize != nil {
+				impact.ExpiredUploadSize += *upload.UploadSize
+			}
+			if upload.UncompressedSize != nil {
+				impact.ExpiredUncompressedSize += *upload.UncompressedSize
+			}
+		}
+	}
+
+	return impact, true, nil
+}
+
+// getRetentionPoliciesForRepository returns all data retention policies that apply to the given repository.
+func (s *Service) getRetentionPoliciesForRepository(ctx context.Context, repositoryID int) ([]policiesshared.ConfigurationPolicy, error) {
+	var (
+		t        = true
+		policies []policiesshared.ConfigurationPolicy
+	)
+
+	for {
+		policyBatch, totalCount, err := s.store.GetConfigurationPolicies(ctx, policiesshared.GetConfigurationPoliciesOptions{
+			RepositoryID:     repositoryID,
+			ForDataRetention: &t,
+			Limit:            retentionImpactPolicyBatchSize,
+			Offset:           len(policies),
+		})
+		if err != nil {
+			return nil, errors.Wrap(err, "store.GetConfigurationPolicies")
+		}
+
+		policies = append(policies, policyBatch...)
+		if len(policyBatch) == 0 || len(policies) >= totalCount {
+			return policies, nil
+		}
+	}
+}
+
+// proposedPolicyApplicability returns a function that determines whether or not the given policy
+// applies to a repository. Policies apply to a single repository, to the repositories matching a
+// set of name patterns, or (if neither is supplied) globally.
+func (s *Service) proposedPolicyApplicability(ctx context.Context, policy policiesshared.ConfigurationPolicy) (func(repositoryID int) bool, error) {
+	if policy.RepositoryID != nil {
+		return func(repositoryID int) bool { return repositoryID == *policy.RepositoryID }, nil
+	}
+	if policy.RepositoryPatterns == nil || len(*policy.RepositoryPatterns) == 0 {
+		return func(repositoryID int) bool { return true }, nil
+	}
+
+	matchingRepositoryIDs := map[int]struct{}{}
+	for offset := 0; ; {
+		ids, totalCount, err := s.store.GetRepoIDsByGlobPatterns(ctx, *policy.RepositoryPatterns, retentionImpactRepositoryBatchSize, offset)
+		if err != nil {
+			return nil, errors.Wrap(err, "store.GetRepoIDsByGlobPatterns")
+		}
+
+		for _, id := range ids {
+			matchingRepositoryIDs[id] = struct{}{}
+		}
+
+		offset += len(ids)
+		if len(ids) == 0 || offset >= totalCount {
+			break
+		}
+	}
+
+	return func(repositoryID int) bool {
+		_, ok := matchingRepositoryIDs[repositoryID]
+		return ok
+	}, nil
+}
+
+// isUploadProtected returns true if any of the given commits visible to the upload is matched by a
+// policy whose retention duration has not yet passed for that upload.
+func isUploadProtected(commitMap map[string][]PolicyMatch, upload shared.Upload, visibleCommits []string, now time.Time) bool {
+	for _, commit := range visibleCommits {
+		for _, policyMatch := range commitMap[commit] {
+			if policyMatch.PolicyDuration == nil || now.Sub(upload.UploadedAt) < *policyMatch.PolicyDuration {
+				return true
+			}
+		}
+	}
+
+	return false
+}
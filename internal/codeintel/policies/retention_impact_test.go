@@ -0,0 +1,212 @@
+package policies
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	policiesshared "github.com/sourcegraph/sourcegraph/internal/codeintel/policies/shared"
+	"github.com/sourcegraph/sourcegraph/internal/codeintel/uploads/shared"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver/gitdomain"
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+	"github.com/sourcegraph/sourcegraph/lib/pointers"
+)
+
+func TestGetRetentionPolicyImpact(t *testing.T) {
+	mockStore := NewMockStore()
+	mockRepoStore := defaultMockRepoStore()
+	mockUploadSvc := NewMockUploadService()
+	mockGitserverClient := gitserver.NewMockClient()
+
+	svc := newService(&observation.TestContext, mockStore, mockRepoStore, mockUploadSvc, mockGitserverClient)
+
+	now := time.Unix(1687880000, 0)
+	uploads := []shared.Upload{
+		// protected by tag policy (currently), expired under proposed policy
+		{ID: 1, RepositoryID: 50, Commit: "deadbeef01", UploadedAt: now.Add(-time.Hour * 48), UploadSize: pointers.Ptr(int64(100)), UncompressedSize: pointers.Ptr(int64(1000))},
+		// not protected by any policy
+		{ID: 2, RepositoryID: 50, Commit: "deadbeef02", UploadedAt: now.Add(-time.Hour * 2), UploadSize: pointers.Ptr(int64(200)), UncompressedSize: pointers.Ptr(int64(2000))},
+		// visible from the tip of the default branch
+		{ID: 3, RepositoryID: 50, Commit: "deadbeef03", UploadedAt: now.Add(-time.Hour * 400)},
+		// repository not matched by the proposed policy
+		{ID: 4, RepositoryID: 51, Commit: "deadbeef04", UploadedAt: now.Add(-time.Hour * 400)},
+	}
+	mockStore.GetRepoIDsWithUploadsFunc.SetDefaultReturn([]int{50, 51}, nil)
+	mockUploadSvc.GetUploadsFunc.SetDefaultHook(uploadsByRepository(uploads))
+	mockUploadSvc.GetCommitsVisibleToUploadFunc.SetDefaultHook(func(_ context.Context, uploadID, _ int, _ *string) ([]string, *string, error) {
+		for _, upload := range uploads {
+			if upload.ID == uploadID {
+				return []string{upload.Commit}, nil, nil
+			}
+		}
+
+		return nil, nil, nil
+	})
+
+	existingPolicy := policiesshared.ConfigurationPolicy{
+		ID:                 7,
+		RepositoryPatterns: &[]string{"r5*"},
+		Type:               policiesshared.GitObjectTypeTag,
+		Pattern:            "*",
+		RetentionEnabled:   true,
+		RetentionDuration:  pointers.Ptr(time.Hour * 72),
+	}
+	mockStore.GetConfigurationPoliciesFunc.SetDefaultHook(func(_ context.Context, opts policiesshared.GetConfigurationPoliciesOptions) ([]policiesshared.ConfigurationPolicy, int, error) {
+		if opts.RepositoryID == 50 {
+			return []policiesshared.ConfigurationPolicy{existingPolicy}, 1, nil
+		}
+
+		return nil, 0, nil
+	})
+	mockStore.GetRepoIDsByGlobPatternsFunc.SetDefaultReturn([]int{50}, 1, nil)
+
+	mockGitserverClient.RefDescriptionsFunc.SetDefaultHook(func(_ context.Context, repoName api.RepoName, _ ...string) (map[string][]gitdomain.RefDescription, error) {
+		if repoName != "r50" {
+			return nil, nil
+		}
+
+		return map[string][]gitdomain.RefDescription{
+			"deadbeef01": {{Name: "v1.0.0", Type: gitdomain.RefTypeTag}},
+			"deadbeef02": {{Name: "feature", Type: gitdomain.RefTypeBranch}},
+			"deadbeef03": {{Name: "main", Type: gitdomain.RefTypeBranch, IsDefaultBranch: true}},
+		}, nil
+	})
+
+	proposedPolicy := existingPolicy
+	proposedPolicy.RepositoryPatterns = &[]string{"r50"}
+	proposedPolicy.RetentionDuration = pointers.Ptr(time.Hour * 24)
+
+	impacts, nextRepositoryID, err := svc.GetRetentionPolicyImpact(context.Background(), proposedPolicy, now, 10, 0)
+	if err != nil {
+		t.Fatalf("unexpected error getting retention policy impact: %s", err)
+	}
+	if nextRepositoryID != 0 {
+		t.Errorf("unexpected next repository. want=%d have=%d", 0, nextRepositoryID)
+	}
+
+	expectedImpacts := []policiesshared.RetentionPolicyImpact{
+		{
+			RepositoryID:            50,
+			NumUploads:              3,
+			NumCurrentlyExpired:     1,
+			NumExpired:              2,
+			ExpiredUploadSize:       300,
+			ExpiredUncompressedSize: 3000,
+		},
+	}
+	if diff := cmp.Diff(expectedImpacts, impacts); diff != "" {
+		t.Errorf("unexpected retention policy impact (-want +got):\n%s", diff)
+	}
+
+	if calls := len(mockStore.UpdateConfigurationPolicyFunc.History()); calls != 0 {
+		t.Errorf("unexpected policy updates: %d", calls)
+	}
+}
+
+func TestGetRetentionPolicyImpactNewPolicy(t *testing.T) {
+	mockStore := NewMockStore()
+	mockRepoStore := defaultMockRepoStore()
+	mockUploadSvc := NewMockUploadService()
+	mockGitserverClient := gitserver.NewMockClient()
+
+	svc := newService(&observation.TestContext, mockStore, mockRepoStore, mockUploadSvc, mockGitserverClient)
+
+	now := time.Unix(1687880000, 0)
+	uploads := []shared.Upload{
+		{ID: 1, RepositoryID: 50, Commit: "deadbeef01", UploadedAt: now.Add(-time.Hour * 2), UploadSize: pointers.Ptr(int64(100))},
+		{ID: 2, RepositoryID: 51, Commit: "deadbeef02", UploadedAt: now.Add(-time.Hour * 2), UploadSize: pointers.Ptr(int64(200))},
+	}
+	mockStore.GetRepoIDsWithUploadsFunc.SetDefaultHook(func(_ context.Context, afterRepositoryID, _ int) ([]int, error) {
+		if afterRepositoryID < 50 {
+			return []int{50, 51}, nil
+		}
+		if afterRepositoryID < 51 {
+			return []int{51}, nil
+		}
+
+		return nil, nil
+	})
+	mockUploadSvc.GetUploadsFunc.SetDefaultHook(uploadsByRepository(uploads))
+	mockUploadSvc.GetCommitsVisibleToUploadFunc.SetDefaultHook(func(_ context.Context, uploadID, _ int, _ *string) ([]string, *string, error) {
+		return []string{uploads[uploadID-1].Commit}, nil, nil
+	})
+	mockGitserverClient.RefDescriptionsFunc.SetDefaultReturn(map[string][]gitdomain.RefDescription{
+		"deadbeef01": {{Name: "feature", Type: gitdomain.RefTypeBranch}},
+		"deadbeef02": {{Name: "feature", Type: gitdomain.RefTypeBranch}},
+	}, nil)
+
+	// A new global policy protecting all branches for a week
+	proposedPolicy := policiesshared.ConfigurationPolicy{
+		Type:              policiesshared.GitObjectTypeTree,
+		Pattern:           "*",
+		RetentionEnabled:  true,
+		RetentionDuration: pointers.Ptr(time.Hour * 24 * 7),
+	}
+
+	impacts, nextRepositoryID, err := svc.GetRetentionPolicyImpact(context.Background(), proposedPolicy, now, 10, 0)
+	if err != nil {
+		t.Fatalf("unexpected error getting retention policy impact: %s", err)
+	}
+	if nextRepositoryID != 0 {
+		t.Errorf("unexpected next repository. want=%d have=%d", 0, nextRepositoryID)
+	}
+
+	expectedImpacts := []policiesshared.RetentionPolicyImpact{
+		{RepositoryID: 50, NumUploads: 1, NumCurrentlyExpired: 1, NumExpired: 0},
+		{RepositoryID: 51, NumUploads: 1, NumCurrentlyExpired: 1, NumExpired: 0},
+	}
+	if diff := cmp.Diff(expectedImpacts, impacts); diff != "" {
+		t.Errorf("unexpected retention policy impact (-want +got):\n%s", diff)
+	}
+
+	// Repositories are evaluated page by page
+	var pagedImpacts []policiesshared.RetentionPolicyImpact
+	for afterRepositoryID, pages := 0, 0; ; pages++ {
+		if pages > len(expectedImpacts) {
+			t.Fatalf("too many pages")
+		}
+
+		impacts, nextRepositoryID, err := svc.GetRetentionPolicyImpact(context.Background(), proposedPolicy, now, 1, afterRepositoryID)
+		if err != nil {
+			t.Fatalf("unexpected error getting retention policy impact: %s", err)
+		}
+		if len(impacts) > 1 {
+			t.Fatalf("unexpected page size: %d", len(impacts))
+		}
+
+		pagedImpacts = append(pagedImpacts, impacts...)
+		if nextRepositoryID == 0 {
+			break
+		}
+		afterRepositoryID = nextRepositoryID
+	}
+	if diff := cmp.Diff(expectedImpacts, pagedImpacts); diff != "" {
+		t.Errorf("unexpected paged retention policy impact (-want +got):\n%s", diff)
+	}
+
+	// Only a bounded number of uploads is evaluated per repository
+	for _, call := range mockUploadSvc.GetUploadsFunc.History() {
+		if call.Arg1.RepositoryID == 0 || call.Arg1.Limit != retentionImpactMaxUploadsPerRepository {
+			t.Errorf("unexpected uploads query: %+v", call.Arg1)
+		}
+	}
+}
+
+// uploadsByRepository returns a hook for UploadService.GetUploads returning the given uploads of the
+// requested repository.
+func uploadsByRepository(uploads []shared.Upload) func(context.Context, shared.GetUploadsOptions) ([]shared.Upload, int, error) {
+	return func(_ context.Context, opts shared.GetUploadsOptions) ([]shared.Upload, int, error) {
+		var filtered []shared.Upload
+		for _, upload := range uploads {
+			if upload.RepositoryID == opts.RepositoryID {
+				filtered = append(filtered, upload)
+			}
+		}
+
+		return filtered, len(filtered), nil
+	}
+}
@@ -33,6 +33,26 @@ type RetentionPolicyMatchCandidate struct {
 	ProtectingCommits []string
 }
 
+// RetentionPolicyImpact describes the uploads of a single repository that would be expired by
+// the data retention policies applying to that repository, both as currently configured and with
+// a proposed configuration policy in effect.
+type RetentionPolicyImpact struct {
+	RepositoryID int
+
+	// NumUploads is the number of completed and unexpired uploads that were evaluated. At most 1000
+	// uploads, the oldest ones, are evaluated per repository.
+	NumUploads int
+
+	// NumCurrentlyExpired is the number of uploads that are not protected by the current policies.
+	NumCurrentlyExpired int
+
+	// NumExpired is the number of uploads that are not protected once the proposed policy is in
+	// effect. The sizes are the summed (compressed and uncompressed) sizes of those uploads.
+	NumExpired              int
+	ExpiredUploadSize       int64
+	ExpiredUncompressedSize int64
+}
+
 type GetConfigurationPoliciesOptions struct {
 	// RepositoryID indicates that only configuration policies that apply to the
 	// specified repository (directly or via pattern) should be returned. This value
@@ -14,6 +14,7 @@ type operations struct {
 	deleteConfigurationPolicy *observation.Operation
 	previewGitObjectFilter    *observation.Operation
 	previewRepoFilter         *observation.Operation
+	previewRetentionImpact    *observation.Operation
 	updateConfigurationPolicy *observation.Operation
 }
 
@@ -40,6 +41,7 @@ func newOperations(observationCtx *observation.Context) *operations {
 		deleteConfigurationPolicy: op("DeleteConfigurationPolicy"),
 		previewGitObjectFilter:    op("PreviewGitObjectFilter"),
 		previewRepoFilter:         op("PreviewRepoFilter"),
+		previewRetentionImpact:    op("PreviewRetentionImpact"),
 		updateConfigurationPolicy: op("UpdateConfigurationPolicy"),
 	}
 }
@@ -2,6 +2,7 @@ package graphql
 
 import (
 	"context"
+	"strconv"
 	"time"
 
 	"github.com/graph-gophers/graphql-go"
@@ -10,14 +11,18 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/codeintel/policies/shared"
 	resolverstubs "github.com/sourcegraph/sourcegraph/internal/codeintel/resolvers"
 	"github.com/sourcegraph/sourcegraph/internal/codeintel/shared/resolvers/gitresolvers"
+	"github.com/sourcegraph/sourcegraph/internal/database"
 	"github.com/sourcegraph/sourcegraph/internal/gqlutil"
 	"github.com/sourcegraph/sourcegraph/internal/observation"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
 	"github.com/sourcegraph/sourcegraph/lib/pointers"
 )
 
 const (
 	DefaultRepositoryFilterPreviewPageSize = 15 // TEMP: 50
 	DefaultGitObjectFilterPreviewPageSize  = 15 // TEMP: 100
+
+	DefaultRetentionPolicyImpactPreviewPageSize = 10
 )
 
 func (r *rootResolver) PreviewRepositoryFilter(ctx context.Context, args *resolverstubs.PreviewRepositoryFilterArgs) (_ resolverstubs.RepositoryFilterPreviewResolver, err error) {
@@ -88,6 +93,75 @@ func (r *rootResolver) PreviewGitObjectFilter(ctx context.Context, id graphql.ID
 	return newGitObjectFilterPreviewResolver(gitObjectResolvers, totalCount, totalCountYoungerThanThreshold), nil
 }
 
+// 🚨 SECURITY: Only site admins may preview the effect of data retention policies across all repositories
+func (r *rootResolver) PreviewRetentionPolicyImpact(ctx context.Context, args *resolverstubs.PreviewRetentionPolicyImpactArgs) (_ resolverstubs.RetentionPolicyImpactPreviewResolver, err error) {
+	ctx, _, endObservation := r.operations.previewRetentionImpact.With(ctx, &err, observation.Args{Attrs: []attribute.KeyValue{
+		attribute.String("policy", string(pointers.Deref(args.Policy, ""))),
+		attribute.String("repository", string(pointers.Deref(args.Repository, ""))),
+		attribute.String("type", string(args.Type)),
+		attribute.String("pattern", args.Pattern),
+	}})
+	defer endObservation(1, observation.Args{})
+
+	if err := r.siteAdminChecker.CheckCurrentUserIsSiteAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	if err := validateConfigurationPolicy(resolverstubs.CodeIntelConfigurationPolicy{
+		Name:                   "proposed policy",
+		Type:                   args.Type,
+		Pattern:                args.Pattern,
+		RetentionEnabled:       args.RetentionEnabled,
+		RetentionDurationHours: args.RetentionDurationHours,
+	}); err != nil {
+		return nil, err
+	}
+
+	proposedPolicy := shared.ConfigurationPolicy{
+		Type:                      shared.GitObjectType(args.Type),
+		Pattern:                   args.Pattern,
+		RetentionEnabled:          args.RetentionEnabled,
+		RetentionDuration:         toDuration(args.RetentionDurationHours),
+		RetainIntermediateCommits: args.RetainIntermediateCommits,
+	}
+	if args.Policy != nil {
+		if proposedPolicy.ID, err = resolverstubs.UnmarshalID[int](*args.Policy); err != nil {
+			return nil, err
+		}
+	}
+	if args.Repository != nil {
+		repositoryID, err := resolverstubs.UnmarshalID[int](*args.Repository)
+		if err != nil {
+			return nil, err
+		}
+
+		proposedPolicy.RepositoryID = &repositoryID
+	}
+	if args.RepositoryPatterns != nil {
+		proposedPolicy.RepositoryPatterns = args.RepositoryPatterns
+	}
+
+	limit, afterRepositoryID, err := args.ParseLimitOffset(DefaultRetentionPolicyImpactPreviewPageSize)
+	if err != nil {
+		return nil, err
+	}
+	if limit <= 0 {
+		return nil, errors.New("illegal page size: first must be positive")
+	}
+
+	impacts, nextRepositoryID, err := r.policySvc.GetRetentionPolicyImpact(ctx, proposedPolicy, time.Now(), int(limit), int(afterRepositoryID))
+	if err != nil {
+		return nil, err
+	}
+
+	resolvers := make([]resolverstubs.RepositoryRetentionPolicyImpactResolver, 0, len(impacts))
+	for _, impact := range impacts {
+		resolvers = append(resolvers, newRepositoryRetentionPolicyImpactResolver(r.repoStore, impact))
+	}
+
+	return newRetentionPolicyImpactPreviewResolver(resolvers, impacts, nextRepositoryID), nil
+}
+
 //
 //
 
@@ -166,6 +240,88 @@ func (r *gitObjectFilterPreviewResolver) TotalCountYoungerThanThreshold() *int32
 //
 //
 
+type retentionPolicyImpactPreviewResolver struct {
+	impactResolvers        []resolverstubs.RepositoryRetentionPolicyImpactResolver
+	totalExpiredIndexes    int
+	totalExpiredUploadSize int64
+	nextRepositoryID       int
+}
+
+func newRetentionPolicyImpactPreviewResolver(impactResolvers []resolverstubs.RepositoryRetentionPolicyImpactResolver, impacts []shared.RetentionPolicyImpact, nextRepositoryID int) resolverstubs.RetentionPolicyImpactPreviewResolver {
+	var totalExpiredIndexes int
+	var totalExpiredUploadSize int64
+	for _, impact := range impacts {
+		totalExpiredIndexes += impact.NumExpired
+		totalExpiredUploadSize += impact.ExpiredUploadSize
+	}
+
+	return &retentionPolicyImpactPreviewResolver{
+		impactResolvers:        impactResolvers,
+		totalExpiredIndexes:    totalExpiredIndexes,
+		totalExpiredUploadSize: totalExpiredUploadSize,
+		nextRepositoryID:       nextRepositoryID,
+	}
+}
+
+func (r *retentionPolicyImpactPreviewResolver) Nodes() []resolverstubs.RepositoryRetentionPolicyImpactResolver {
+	return r.impactResolvers
+}
+
+func (r *retentionPolicyImpactPreviewResolver) TotalExpiredIndexes() int32 {
+	return int32(r.totalExpiredIndexes)
+}
+
+func (r *retentionPolicyImpactPreviewResolver) TotalExpiredUploadSize() resolverstubs.BigInt {
+	return resolverstubs.BigInt(r.totalExpiredUploadSize)
+}
+
+func (r *retentionPolicyImpactPreviewResolver) PageInfo() resolverstubs.PageInfo {
+	if r.nextRepositoryID == 0 {
+		return resolverstubs.NewSimplePageInfo(false)
+	}
+
+	return resolverstubs.NewPageInfoFromCursor(strconv.Itoa(r.nextRepositoryID))
+}
+
+//
+//
+
+type repositoryRetentionPolicyImpactResolver struct {
+	repoStore database.RepoStore
+	impact    shared.RetentionPolicyImpact
+}
+
+func newRepositoryRetentionPolicyImpactResolver(repoStore database.RepoStore, impact shared.RetentionPolicyImpact) resolverstubs.RepositoryRetentionPolicyImpactResolver {
+	return &repositoryRetentionPolicyImpactResolver{repoStore: repoStore, impact: impact}
+}
+
+func (r *repositoryRetentionPolicyImpactResolver) Repository(ctx context.Context) (resolverstubs.RepositoryResolver, error) {
+	return gitresolvers.NewRepositoryFromID(ctx, r.repoStore, r.impact.RepositoryID)
+}
+
+func (r *repositoryRetentionPolicyImpactResolver) EvaluatedIndexes() int32 {
+	return int32(r.impact.NumUploads)
+}
+
+func (r *repositoryRetentionPolicyImpactResolver) CurrentlyExpiredIndexes() int32 {
+	return int32(r.impact.NumCurrentlyExpired)
+}
+
+func (r *repositoryRetentionPolicyImpactResolver) ExpiredIndexes() int32 {
+	return int32(r.impact.NumExpired)
+}
+
+func (r *repositoryRetentionPolicyImpactResolver) ExpiredUploadSize() resolverstubs.BigInt {
+	return resolverstubs.BigInt(r.impact.ExpiredUploadSize)
+}
+
+func (r *repositoryRetentionPolicyImpactResolver) ExpiredUncompressedSize() resolverstubs.BigInt {
+	return resolverstubs.BigInt(r.impact.ExpiredUncompressedSize)
+}
+
+//
+//
+
 type gitObjectResolver struct {
 	name        string
 	rev         string
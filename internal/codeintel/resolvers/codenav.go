@@ -15,11 +15,12 @@ type CodeNavServiceResolver interface {
 }
 
 type GitBlobLSIFDataArgs struct {
-	Repo      *types.Repo
-	Commit    api.CommitID
-	Path      string
-	ExactPath bool
-	ToolName  string
+	Repo                *types.Repo
+	Commit              api.CommitID
+	Path                string
+	ExactPath           bool
+	ToolName            string
+	SearchBasedFallback bool
 }
 
 type GitBlobLSIFDataResolver interface {
@@ -33,9 +34,14 @@ type GitBlobLSIFDataResolver interface {
 	References(ctx context.Context, args *LSIFPagedQueryPositionArgs) (LocationConnectionResolver, error)
 	Implementations(ctx context.Context, args *LSIFPagedQueryPositionArgs) (LocationConnectionResolver, error)
 	Prototypes(ctx context.Context, args *LSIFPagedQueryPositionArgs) (LocationConnectionResolver, error)
+	IncomingCalls(ctx context.Context, args *LSIFCallHierarchyArgs) (CallHierarchyItemConnectionResolver, error)
+	OutgoingCalls(ctx context.Context, args *LSIFCallHierarchyArgs) (CallHierarchyItemConnectionResolver, error)
+	TypeHierarchy(ctx context.Context, args *LSIFTypeHierarchyArgs) (TypeHierarchyResolver, error)
+	RenamePreview(ctx context.Context, args *LSIFRenamePreviewArgs) (RenamePreviewResolver, error)
 	Hover(ctx context.Context, args *LSIFQueryPositionArgs) (HoverResolver, error)
 	VisibleIndexes(ctx context.Context) (_ *[]PreciseIndexResolver, err error)
 	Snapshot(ctx context.Context, args *struct{ IndexID graphql.ID }) (_ *[]SnapshotDataResolver, err error)
+	Precise() bool
 }
 
 type SnapshotDataResolver interface {
@@ -61,10 +67,68 @@ type LSIFPagedQueryPositionArgs struct {
 	Filter *string
 }
 
+type LSIFCallHierarchyArgs struct {
+	Line      int32
+	Character int32
+	First     *int32
+}
+
 type (
 	CodeIntelligenceRangeConnectionResolver = ConnectionResolver[CodeIntelligenceRangeResolver]
+	CallHierarchyItemConnectionResolver     = ConnectionResolver[CallHierarchyItemResolver]
 )
 
+type CallHierarchyItemResolver interface {
+	Symbol() string
+	Definitions(ctx context.Context) (LocationConnectionResolver, error)
+	CallSites(ctx context.Context) (LocationConnectionResolver, error)
+}
+
+type LSIFTypeHierarchyArgs struct {
+	Line      int32
+	Character int32
+	Direction string
+	Depth     *int32
+	After     *string
+	First     *int32
+}
+
+type TypeHierarchyResolver interface {
+	Root() TypeHierarchyNodeResolver
+	PageInfo() PageInfo
+}
+
+type TypeHierarchyNodeResolver interface {
+	Symbol() string
+	Definitions(ctx context.Context) (LocationConnectionResolver, error)
+	Children() []TypeHierarchyNodeResolver
+}
+
+type LSIFRenamePreviewArgs struct {
+	Line      int32
+	Character int32
+	NewName   string
+}
+
+type RenamePreviewResolver interface {
+	Symbol() string
+	Name() string
+	Repositories() []RenamePreviewRepositoryResolver
+}
+
+type RenamePreviewRepositoryResolver interface {
+	Repository(ctx context.Context) (RepositoryResolver, error)
+	Commit() string
+	Files() []RenamePreviewFileResolver
+	Diff() string
+	SkippedRanges() int32
+}
+
+type RenamePreviewFileResolver interface {
+	Path() string
+	Ranges() []RangeResolver
+}
+
 type CodeIntelligenceRangeResolver interface {
 	Range(ctx context.Context) (RangeResolver, error)
 	Definitions(ctx context.Context) (LocationConnectionResolver, error)
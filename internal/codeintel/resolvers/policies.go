@@ -21,6 +21,7 @@ type PoliciesServiceResolver interface {
 	// Filter previews
 	PreviewRepositoryFilter(ctx context.Context, args *PreviewRepositoryFilterArgs) (RepositoryFilterPreviewResolver, error)
 	PreviewGitObjectFilter(ctx context.Context, id graphql.ID, args *PreviewGitObjectFilterArgs) (GitObjectFilterPreviewResolver, error)
+	PreviewRetentionPolicyImpact(ctx context.Context, args *PreviewRetentionPolicyImpactArgs) (RetentionPolicyImpactPreviewResolver, error)
 }
 
 type CodeIntelligenceConfigurationPoliciesArgs struct {
@@ -76,6 +77,18 @@ type PreviewGitObjectFilterArgs struct {
 	CountObjectsYoungerThanHours *int32
 }
 
+type PreviewRetentionPolicyImpactArgs struct {
+	PagedConnectionArgs
+	Policy                    *graphql.ID
+	Repository                *graphql.ID
+	RepositoryPatterns        *[]string
+	Type                      GitObjectType
+	Pattern                   string
+	RetentionEnabled          bool
+	RetentionDurationHours    *int32
+	RetainIntermediateCommits bool
+}
+
 type (
 	CodeIntelligenceConfigurationPolicyConnectionResolver = PagedConnectionWithTotalCountResolver[CodeIntelligenceConfigurationPolicyResolver]
 )
@@ -111,6 +124,22 @@ type GitObjectFilterPreviewResolver interface {
 	TotalCountYoungerThanThreshold() *int32
 }
 
+type RetentionPolicyImpactPreviewResolver interface {
+	Nodes() []RepositoryRetentionPolicyImpactResolver
+	TotalExpiredIndexes() int32
+	TotalExpiredUploadSize() BigInt
+	PageInfo() PageInfo
+}
+
+type RepositoryRetentionPolicyImpactResolver interface {
+	Repository(ctx context.Context) (RepositoryResolver, error)
+	EvaluatedIndexes() int32
+	CurrentlyExpiredIndexes() int32
+	ExpiredIndexes() int32
+	ExpiredUploadSize() BigInt
+	ExpiredUncompressedSize() BigInt
+}
+
 type CodeIntelGitObjectResolver interface {
 	Name() string
 	Rev() string
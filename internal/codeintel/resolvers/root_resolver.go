@@ -209,6 +209,10 @@ func (r *Resolver) PreviewGitObjectFilter(ctx context.Context, id graphql.ID, ar
 	return r.policiesRootResolver.PreviewGitObjectFilter(ctx, id, args)
 }
 
+func (r *Resolver) PreviewRetentionPolicyImpact(ctx context.Context, args *PreviewRetentionPolicyImpactArgs) (_ RetentionPolicyImpactPreviewResolver, err error) {
+	return r.policiesRootResolver.PreviewRetentionPolicyImpact(ctx, args)
+}
+
 func (r *Resolver) RankingSummary(ctx context.Context) (_ GlobalRankingSummaryResolver, err error) {
 	return r.rankingServiceResolver.RankingSummary(ctx)
 }
@@ -74,6 +74,14 @@ type VulnerabilityMatchResolver interface {
 	Vulnerability(ctx context.Context) (VulnerabilityResolver, error)
 	AffectedPackage(ctx context.Context) (VulnerabilityAffectedPackageResolver, error)
 	PreciseIndex(ctx context.Context) (PreciseIndexResolver, error)
+	Reachable() bool
+	CallSites() []VulnerabilityCallSiteResolver
+}
+
+type VulnerabilityCallSiteResolver interface {
+	Symbol() string
+	Path() string
+	Range() RangeResolver
 }
 
 type VulnerabilityMatchesSummaryCountResolver interface {
@@ -2,11 +2,13 @@ package resolvers
 
 import (
 	"context"
+	"encoding/json"
 	"strconv"
 
 	"github.com/graph-gophers/graphql-go"
 	"github.com/graph-gophers/graphql-go/relay"
 
+	"github.com/sourcegraph/sourcegraph/lib/errors"
 	"github.com/sourcegraph/sourcegraph/lib/pointers"
 )
 
@@ -209,3 +211,29 @@ func UnmarshalID[T any](id graphql.ID) (val T, err error) {
 func MarshalID[T any](kind string, id T) graphql.ID {
 	return relay.MarshalID(kind, id)
 }
+
+// BigInt implements the BigInt GraphQL scalar type.
+type BigInt int64
+
+func (BigInt) ImplementsGraphQLType(name string) bool {
+	return name == "BigInt"
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+func (v BigInt) MarshalJSON() ([]byte, error) {
+	return json.Marshal(strconv.FormatInt(int64(v), 10))
+}
+
+// UnmarshalGraphQL implements the graphql.Unmarshaler interface.
+func (v *BigInt) UnmarshalGraphQL(input any) error {
+	s, ok := input.(string)
+	if !ok {
+		return errors.Errorf("invalid GraphQL BigInt scalar value input (got %T, expected string)", input)
+	}
+	n, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return err
+	}
+	*v = BigInt(n)
+	return nil
+}
@@ -0,0 +1,86 @@
+package sbom
+
+import (
+	"encoding/json"
+	"io"
+	"time"
+)
+
+// See https://cyclonedx.org/docs/1.5/json/.
+
+type cycloneDXDocument struct {
+	BOMFormat    string                `json:"bomFormat"`
+	SpecVersion  string                `json:"specVersion"`
+	SerialNumber string                `json:"serialNumber,omitempty"`
+	Version      int                   `json:"version"`
+	Metadata     cycloneDXMetadata     `json:"metadata"`
+	Components   []cycloneDXComponent  `json:"components"`
+	Dependencies []cycloneDXDependency `json:"dependencies"`
+}
+
+type cycloneDXMetadata struct {
+	Timestamp string             `json:"timestamp"`
+	Tools     []cycloneDXTool    `json:"tools"`
+	Component cycloneDXComponent `json:"component"`
+}
+
+type cycloneDXTool struct {
+	Vendor string `json:"vendor"`
+	Name   string `json:"name"`
+}
+
+type cycloneDXComponent struct {
+	Type    string `json:"type"`
+	BOMRef  string `json:"bom-ref"`
+	Name    string `json:"name"`
+	Version string `json:"version,omitempty"`
+	PURL    string `json:"purl,omitempty"`
+}
+
+type cycloneDXDependency struct {
+	Ref       string   `json:"ref"`
+	DependsOn []string `json:"dependsOn"`
+}
+
+func writeCycloneDX(w io.Writer, doc Document) error {
+	root := cycloneDXComponent{
+		Type:    "application",
+		BOMRef:  doc.RepositoryName + "@" + doc.Commit,
+		Name:    doc.RepositoryName,
+		Version: doc.Commit,
+	}
+
+	components := make([]cycloneDXComponent, 0, len(doc.Components))
+	dependsOn := make([]string, 0, len(doc.Components))
+	for _, component := range doc.Components {
+		components = append(components, cycloneDXComponent{
+			Type:    "library",
+			BOMRef:  component.PURL,
+			Name:    component.Name,
+			Version: component.Version,
+			PURL:    component.PURL,
+		})
+		dependsOn = append(dependsOn, component.PURL)
+	}
+
+	serialNumber := ""
+	if doc.SerialNumber != "" {
+		serialNumber = "urn:uuid:" + doc.SerialNumber
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(cycloneDXDocument{
+		BOMFormat:    "CycloneDX",
+		SpecVersion:  "1.5",
+		SerialNumber: serialNumber,
+		Version:      1,
+		Metadata: cycloneDXMetadata{
+			Timestamp: doc.CreatedAt.UTC().Format(time.RFC3339),
+			Tools:     []cycloneDXTool{{Vendor: "Sourcegraph", Name: "sourcegraph"}},
+			Component: root,
+		},
+		Components:   components,
+		Dependencies: []cycloneDXDependency{{Ref: root.BOMRef, DependsOn: dependsOn}},
+	})
+}
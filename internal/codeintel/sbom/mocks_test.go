@@ -0,0 +1,576 @@
+// Code generated by go-mockgen 1.3.7; DO NOT EDIT.
+//
+// This file was generated by running `sg generate` (or `go-mockgen`) at the root of
+// this repository. To add additional mocks to this or another package, add a new entry
+// to the mockgen.yaml file in the root of this repository.
+
+package sbom
+
+import (
+	"context"
+	"sync"
+
+	shared "github.com/sourcegraph/sourcegraph/internal/codeintel/uploads/shared"
+)
+
+// MockUploadService is a mock implementation of the UploadService interface
+// (from the package
+// github.com/sourcegraph/sourcegraph/internal/codeintel/sbom) used for unit
+// testing.
+type MockUploadService struct {
+	// InferClosestUploadsFunc is an instance of a mock function object
+	// controlling the behavior of the method InferClosestUploads.
+	InferClosestUploadsFunc *UploadServiceInferClosestUploadsFunc
+	// ReferencesForUploadFunc is an instance of a mock function object
+	// controlling the behavior of the method ReferencesForUpload.
+	ReferencesForUploadFunc *UploadServiceReferencesForUploadFunc
+}
+
+// NewMockUploadService creates a new mock of the UploadService interface.
+// All methods return zero values for all results, unless overwritten.
+func NewMockUploadService() *MockUploadService {
+	return &MockUploadService{
+		InferClosestUploadsFunc: &UploadServiceInferClosestUploadsFunc{
+			defaultHook: func(context.Context, int, string, string, bool, string) (r0 []shared.Dump, r1 error) {
+				return
+			},
+		},
+		ReferencesForUploadFunc: &UploadServiceReferencesForUploadFunc{
+			defaultHook: func(context.Context, int) (r0 shared.PackageReferenceScanner, r1 error) {
+				return
+			},
+		},
+	}
+}
+
+// NewStrictMockUploadService creates a new mock of the UploadService
+// interface. All methods panic on invocation, unless overwritten.
+func NewStrictMockUploadService() *MockUploadService {
+	return &MockUploadService{
+		InferClosestUploadsFunc: &UploadServiceInferClosestUploadsFunc{
+			defaultHook: func(context.Context, int, string, string, bool, string) ([]shared.Dump, error) {
+				panic("unexpected invocation of MockUploadService.InferClosestUploads")
+			},
+		},
+		ReferencesForUploadFunc: &UploadServiceReferencesForUploadFunc{
+			defaultHook: func(context.Context, int) (shared.PackageReferenceScanner, error) {
+				panic("unexpected invocation of MockUploadService.ReferencesForUpload")
+			},
+		},
+	}
+}
+
+// NewMockUploadServiceFrom creates a new mock of the MockUploadService
+// interface. All methods delegate to the given implementation, unless
+// overwritten.
+func NewMockUploadServiceFrom(i UploadService) *MockUploadService {
+	return &MockUploadService{
+		InferClosestUploadsFunc: &UploadServiceInferClosestUploadsFunc{
+			defaultHook: i.InferClosestUploads,
+		},
+		ReferencesForUploadFunc: &UploadServiceReferencesForUploadFunc{
+			defaultHook: i.ReferencesForUpload,
+		},
+	}
+}
+
+// UploadServiceInferClosestUploadsFunc describes the behavior when the
+// InferClosestUploads method of the parent MockUploadService instance is
+// invoked.
+type UploadServiceInferClosestUploadsFunc struct {
+	defaultHook func(context.Context, int, string, string, bool, string) ([]shared.Dump, error)
+	hooks       []func(context.Context, int, string, string, bool, string) ([]shared.Dump, error)
+	history     []UploadServiceInferClosestUploadsFuncCall
+	mutex       sync.Mutex
+}
+
+// InferClosestUploads delegates to the next hook function in the queue and
+// stores the parameter and result values of this invocation.
+func (m *MockUploadService) InferClosestUploads(v0 context.Context, v1 int, v2 string, v3 string, v4 bool, v5 string) ([]shared.Dump, error) {
+	r0, r1 := m.InferClosestUploadsFunc.nextHook()(v0, v1, v2, v3, v4, v5)
+	m.InferClosestUploadsFunc.appendCall(UploadServiceInferClosestUploadsFuncCall{v0, v1, v2, v3, v4, v5, r0, r1})
+	return r0, r1
+}
+
+// SetDefaultHook sets function that is called when the InferClosestUploads
+// method of the parent MockUploadService instance is invoked and the hook
+// queue is empty.
+func (f *UploadServiceInferClosestUploadsFunc) SetDefaultHook(hook func(context.Context, int, string, string, bool, string) ([]shared.Dump, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// InferClosestUploads method of the parent MockUploadService instance
+// invokes the hook at the front of the queue and discards it. After the
+// queue is empty, the default hook function is invoked for any future
+// action.
+func (f *UploadServiceInferClosestUploadsFunc) PushHook(hook func(context.Context, int, string, string, bool, string) ([]shared.Dump, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultHook with a function that returns the
+// given values.
+func (f *UploadServiceInferClosestUploadsFunc) SetDefaultReturn(r0 []shared.Dump, r1 error) {
+	f.SetDefaultHook(func(context.Context, int, string, string, bool, string) ([]shared.Dump, error) {
+		return r0, r1
+	})
+}
+
+// PushReturn calls PushHook with a function that returns the given values.
+func (f *UploadServiceInferClosestUploadsFunc) PushReturn(r0 []shared.Dump, r1 error) {
+	f.PushHook(func(context.Context, int, string, string, bool, string) ([]shared.Dump, error) {
+		return r0, r1
+	})
+}
+
+func (f *UploadServiceInferClosestUploadsFunc) nextHook() func(context.Context, int, string, string, bool, string) ([]shared.Dump, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *UploadServiceInferClosestUploadsFunc) appendCall(r0 UploadServiceInferClosestUploadsFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of UploadServiceInferClosestUploadsFuncCall
+// objects describing the invocations of this function.
+func (f *UploadServiceInferClosestUploadsFunc) History() []UploadServiceInferClosestUploadsFuncCall {
+	f.mutex.Lock()
+	history := make([]UploadServiceInferClosestUploadsFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// UploadServiceInferClosestUploadsFuncCall is an object that describes an
+// invocation of method InferClosestUploads on an instance of
+// MockUploadService.
+type UploadServiceInferClosestUploadsFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 int
+	// Arg2 is the value of the 3rd argument passed to this method
+	// invocation.
+	Arg2 string
+	// Arg3 is the value of the 4th argument passed to this method
+	// invocation.
+	Arg3 string
+	// Arg4 is the value of the 5th argument passed to this method
+	// invocation.
+	Arg4 bool
+	// Arg5 is the value of the 6th argument passed to this method
+	// invocation.
+	Arg5 string
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 []shared.Dump
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c UploadServiceInferClosestUploadsFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1, c.Arg2, c.Arg3, c.Arg4, c.Arg5}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c UploadServiceInferClosestUploadsFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1}
+}
+
+// UploadServiceReferencesForUploadFunc describes the behavior when the
+// ReferencesForUpload method of the parent MockUploadService instance is
+// invoked.
+type UploadServiceReferencesForUploadFunc struct {
+	defaultHook func(context.Context, int) (shared.PackageReferenceScanner, error)
+	hooks       []func(context.Context, int) (shared.PackageReferenceScanner, error)
+	history     []UploadServiceReferencesForUploadFuncCall
+	mutex       sync.Mutex
+}
+
+// ReferencesForUpload delegates to the next hook function in the queue and
+// stores the parameter and result values of this invocation.
+func (m *MockUploadService) ReferencesForUpload(v0 context.Context, v1 int) (shared.PackageReferenceScanner, error) {
+	r0, r1 := m.ReferencesForUploadFunc.nextHook()(v0, v1)
+	m.ReferencesForUploadFunc.appendCall(UploadServiceReferencesForUploadFuncCall{v0, v1, r0, r1})
+	return r0, r1
+}
+
+// SetDefaultHook sets function that is called when the ReferencesForUpload
+// method of the parent MockUploadService instance is invoked and the hook
+// queue is empty.
+func (f *UploadServiceReferencesForUploadFunc) SetDefaultHook(hook func(context.Context, int) (shared.PackageReferenceScanner, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// ReferencesForUpload method of the parent MockUploadService instance
+// invokes the hook at the front of the queue and discards it. After the
+// queue is empty, the default hook function is invoked for any future
+// action.
+func (f *UploadServiceReferencesForUploadFunc) PushHook(hook func(context.Context, int) (shared.PackageReferenceScanner, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultHook with a function that returns the
+// given values.
+func (f *UploadServiceReferencesForUploadFunc) SetDefaultReturn(r0 shared.PackageReferenceScanner, r1 error) {
+	f.SetDefaultHook(func(context.Context, int) (shared.PackageReferenceScanner, error) {
+		return r0, r1
+	})
+}
+
+// PushReturn calls PushHook with a function that returns the given values.
+func (f *UploadServiceReferencesForUploadFunc) PushReturn(r0 shared.PackageReferenceScanner, r1 error) {
+	f.PushHook(func(context.Context, int) (shared.PackageReferenceScanner, error) {
+		return r0, r1
+	})
+}
+
+func (f *UploadServiceReferencesForUploadFunc) nextHook() func(context.Context, int) (shared.PackageReferenceScanner, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *UploadServiceReferencesForUploadFunc) appendCall(r0 UploadServiceReferencesForUploadFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of UploadServiceReferencesForUploadFuncCall
+// objects describing the invocations of this function.
+func (f *UploadServiceReferencesForUploadFunc) History() []UploadServiceReferencesForUploadFuncCall {
+	f.mutex.Lock()
+	history := make([]UploadServiceReferencesForUploadFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// UploadServiceReferencesForUploadFuncCall is an object that describes an
+// invocation of method ReferencesForUpload on an instance of
+// MockUploadService.
+type UploadServiceReferencesForUploadFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 int
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 shared.PackageReferenceScanner
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c UploadServiceReferencesForUploadFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c UploadServiceReferencesForUploadFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1}
+}
+
+// MockPackageReferenceScanner is a mock implementation of the
+// PackageReferenceScanner interface (from the package
+// github.com/sourcegraph/sourcegraph/internal/codeintel/uploads/shared)
+// used for unit testing.
+type MockPackageReferenceScanner struct {
+	// CloseFunc is an instance of a mock function object controlling the
+	// behavior of the method Close.
+	CloseFunc *PackageReferenceScannerCloseFunc
+	// NextFunc is an instance of a mock function object controlling the
+	// behavior of the method Next.
+	NextFunc *PackageReferenceScannerNextFunc
+}
+
+// NewMockPackageReferenceScanner creates a new mock of the
+// PackageReferenceScanner interface. All methods return zero values for all
+// results, unless overwritten.
+func NewMockPackageReferenceScanner() *MockPackageReferenceScanner {
+	return &MockPackageReferenceScanner{
+		CloseFunc: &PackageReferenceScannerCloseFunc{
+			defaultHook: func() (r0 error) {
+				return
+			},
+		},
+		NextFunc: &PackageReferenceScannerNextFunc{
+			defaultHook: func() (r0 shared.PackageReference, r1 bool, r2 error) {
+				return
+			},
+		},
+	}
+}
+
+// NewStrictMockPackageReferenceScanner creates a new mock of the
+// PackageReferenceScanner interface. All methods panic on invocation,
+// unless overwritten.
+func NewStrictMockPackageReferenceScanner() *MockPackageReferenceScanner {
+	return &MockPackageReferenceScanner{
+		CloseFunc: &PackageReferenceScannerCloseFunc{
+			defaultHook: func() error {
+				panic("unexpected invocation of MockPackageReferenceScanner.Close")
+			},
+		},
+		NextFunc: &PackageReferenceScannerNextFunc{
+			defaultHook: func() (shared.PackageReference, bool, error) {
+				panic("unexpected invocation of MockPackageReferenceScanner.Next")
+			},
+		},
+	}
+}
+
+// NewMockPackageReferenceScannerFrom creates a new mock of the
+// MockPackageReferenceScanner interface. All methods delegate to the given
+// implementation, unless overwritten.
+func NewMockPackageReferenceScannerFrom(i shared.PackageReferenceScanner) *MockPackageReferenceScanner {
+	return &MockPackageReferenceScanner{
+		CloseFunc: &PackageReferenceScannerCloseFunc{
+			defaultHook: i.Close,
+		},
+		NextFunc: &PackageReferenceScannerNextFunc{
+			defaultHook: i.Next,
+		},
+	}
+}
+
+// PackageReferenceScannerCloseFunc describes the behavior when the Close
+// method of the parent MockPackageReferenceScanner instance is invoked.
+type PackageReferenceScannerCloseFunc struct {
+	defaultHook func() error
+	hooks       []func() error
+	history     []PackageReferenceScannerCloseFuncCall
+	mutex       sync.Mutex
+}
+
+// Close delegates to the next hook function in the queue and stores the
+// parameter and result values of this invocation.
+func (m *MockPackageReferenceScanner) Close() error {
+	r0 := m.CloseFunc.nextHook()()
+	m.CloseFunc.appendCall(PackageReferenceScannerCloseFuncCall{r0})
+	return r0
+}
+
+// SetDefaultHook sets function that is called when the Close method of the
+// parent MockPackageReferenceScanner instance is invoked and the hook queue
+// is empty.
+func (f *PackageReferenceScannerCloseFunc) SetDefaultHook(hook func() error) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// Close method of the parent MockPackageReferenceScanner instance invokes
+// the hook at the front of the queue and discards it. After the queue is
+// empty, the default hook function is invoked for any future action.
+func (f *PackageReferenceScannerCloseFunc) PushHook(hook func() error) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultHook with a function that returns the
+// given values.
+func (f *PackageReferenceScannerCloseFunc) SetDefaultReturn(r0 error) {
+	f.SetDefaultHook(func() error {
+		return r0
+	})
+}
+
+// PushReturn calls PushHook with a function that returns the given values.
+func (f *PackageReferenceScannerCloseFunc) PushReturn(r0 error) {
+	f.PushHook(func() error {
+		return r0
+	})
+}
+
+func (f *PackageReferenceScannerCloseFunc) nextHook() func() error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *PackageReferenceScannerCloseFunc) appendCall(r0 PackageReferenceScannerCloseFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of PackageReferenceScannerCloseFuncCall
+// objects describing the invocations of this function.
+func (f *PackageReferenceScannerCloseFunc) History() []PackageReferenceScannerCloseFuncCall {
+	f.mutex.Lock()
+	history := make([]PackageReferenceScannerCloseFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// PackageReferenceScannerCloseFuncCall is an object that describes an
+// invocation of method Close on an instance of MockPackageReferenceScanner.
+type PackageReferenceScannerCloseFuncCall struct {
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c PackageReferenceScannerCloseFuncCall) Args() []interface{} {
+	return []interface{}{}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c PackageReferenceScannerCloseFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0}
+}
+
+// PackageReferenceScannerNextFunc describes the behavior when the Next
+// method of the parent MockPackageReferenceScanner instance is invoked.
+type PackageReferenceScannerNextFunc struct {
+	defaultHook func() (shared.PackageReference, bool, error)
+	hooks       []func() (shared.PackageReference, bool, error)
+	history     []PackageReferenceScannerNextFuncCall
+	mutex       sync.Mutex
+}
+
+// Next delegates to the next hook function in the queue and stores the
+// parameter and result values of this invocation.
+func (m *MockPackageReferenceScanner) Next() (shared.PackageReference, bool, error) {
+	r0, r1, r2 := m.NextFunc.nextHook()()
+	m.NextFunc.appendCall(PackageReferenceScannerNextFuncCall{r0, r1, r2})
+	return r0, r1, r2
+}
+
+// SetDefaultHook sets function that is called when the Next method of the
+// parent MockPackageReferenceScanner instance is invoked and the hook queue
+// is empty.
+func (f *PackageReferenceScannerNextFunc) SetDefaultHook(hook func() (shared.PackageReference, bool, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// Next method of the parent MockPackageReferenceScanner instance invokes
+// the hook at the front of the queue and discards it. After the queue is
+// empty, the default hook function is invoked for any future action.
+func (f *PackageReferenceScannerNextFunc) PushHook(hook func() (shared.PackageReference, bool, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultHook with a function that returns the
+// given values.
+func (f *PackageReferenceScannerNextFunc) SetDefaultReturn(r0 shared.PackageReference, r1 bool, r2 error) {
+	f.SetDefaultHook(func() (shared.PackageReference, bool, error) {
+		return r0, r1, r2
+	})
+}
+
+// PushReturn calls PushHook with a function that returns the given values.
+func (f *PackageReferenceScannerNextFunc) PushReturn(r0 shared.PackageReference, r1 bool, r2 error) {
+	f.PushHook(func() (shared.PackageReference, bool, error) {
+		return r0, r1, r2
+	})
+}
+
+func (f *PackageReferenceScannerNextFunc) nextHook() func() (shared.PackageReference, bool, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *PackageReferenceScannerNextFunc) appendCall(r0 PackageReferenceScannerNextFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of PackageReferenceScannerNextFuncCall objects
+// describing the invocations of this function.
+func (f *PackageReferenceScannerNextFunc) History() []PackageReferenceScannerNextFuncCall {
+	f.mutex.Lock()
+	history := make([]PackageReferenceScannerNextFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// PackageReferenceScannerNextFuncCall is an object that describes an
+// invocation of method Next on an instance of MockPackageReferenceScanner.
+type PackageReferenceScannerNextFuncCall struct {
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 shared.PackageReference
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 bool
+	// Result2 is the value of the 3rd result returned from this method
+	// invocation.
+	Result2 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c PackageReferenceScannerNextFuncCall) Args() []interface{} {
+	return []interface{}{}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c PackageReferenceScannerNextFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1, c.Result2}
+}
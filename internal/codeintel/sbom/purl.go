@@ -0,0 +1,164 @@
+package sbom
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// packageType describes how the package references of a single package ecosystem (rows of
+// lsif_references) are represented as package URLs. See https://github.com/package-url/purl-spec.
+type packageType struct {
+	// purlType is the package URL type of the ecosystem (e.g., `npm`).
+	purlType string
+
+	// schemes and managers are the values of the scheme and manager columns of lsif_references
+	// identifying a package of this ecosystem.
+	schemes  []string
+	managers []string
+
+	// split returns the package URL namespace and name of the given package name.
+	split func(name string) (namespace, packageName string)
+}
+
+var packageTypes = []packageType{
+	{
+		purlType: "golang",
+		schemes:  []string{"gomod", "scip-go", "go"},
+		managers: []string{"gomod"},
+		split:    splitLastSegment,
+	},
+	{
+		purlType: "npm",
+		schemes:  []string{"npm", "scip-typescript"},
+		managers: []string{"npm"},
+		split: func(name string) (string, string) {
+			if strings.HasPrefix(name, "@") {
+				return splitLastSegment(name)
+			}
+			return "", name
+		},
+	},
+	{
+		purlType: "pypi",
+		schemes:  []string{"python", "scip-python", "pip"},
+		managers: []string{"python", "pip", "pypi"},
+		split: func(name string) (string, string) {
+			return "", pythonPackageNameSeparators.ReplaceAllString(strings.ToLower(name), "-")
+		},
+	},
+	{
+		purlType: "maven",
+		schemes:  []string{"semanticdb", "maven", "scip-java"},
+		managers: []string{"maven"},
+		split: func(name string) (string, string) {
+			// SCIP indexers name Maven packages `maven/groupId/artifactId`
+			name = strings.ReplaceAll(strings.TrimPrefix(name, "maven/"), ":", "/")
+			return splitLastSegment(name)
+		},
+	},
+	{
+		purlType: "cargo",
+		schemes:  []string{"rust-analyzer", "cargo"},
+		managers: []string{"cargo"},
+		split:    noNamespace,
+	},
+	{
+		purlType: "gem",
+		schemes:  []string{"scip-ruby", "rubygems"},
+		managers: []string{"gem", "rubygems"},
+		split:    noNamespace,
+	},
+	{
+		purlType: "nuget",
+		schemes:  []string{"scip-dotnet", "nuget"},
+		managers: []string{"nuget"},
+		split:    noNamespace,
+	},
+}
+
+var pythonPackageNameSeparators = regexp.MustCompile(`[-_.]+`)
+
+// genericPackageType is used for package references that do not belong to a known ecosystem.
+var genericPackageType = packageType{purlType: "generic", split: noNamespace}
+
+// packageTypeForReference returns the package type of a package reference with the given scheme
+// and manager. The manager takes precedence over the scheme when both identify an ecosystem.
+func packageTypeForReference(scheme, manager string) packageType {
+	for _, t := range packageTypes {
+		if manager != "" && contains(t.managers, manager) {
+			return t
+		}
+	}
+	for _, t := range packageTypes {
+		if contains(t.schemes, scheme) {
+			return t
+		}
+	}
+
+	return genericPackageType
+}
+
+// PackageURL returns the package URL of the package with the given scheme, manager, name, and
+// version as recorded for a package reference of a precise index.
+func PackageURL(scheme, manager, name, version string) string {
+	t := packageTypeForReference(scheme, manager)
+	namespace, name := t.split(strings.TrimSpace(name))
+
+	var sb strings.Builder
+	sb.WriteString("pkg:")
+	sb.WriteString(t.purlType)
+	sb.WriteString("/")
+	if namespace != "" {
+		for _, segment := range strings.Split(namespace, "/") {
+			if segment != "" {
+				sb.WriteString(escapePURLComponent(segment))
+				sb.WriteString("/")
+			}
+		}
+	}
+	sb.WriteString(escapePURLComponent(name))
+	if version = strings.TrimSpace(version); version != "" {
+		sb.WriteString("@")
+		sb.WriteString(escapePURLComponent(version))
+	}
+
+	return sb.String()
+}
+
+func splitLastSegment(name string) (string, string) {
+	if i := strings.LastIndex(name, "/"); i >= 0 {
+		return name[:i], name[i+1:]
+	}
+
+	return "", name
+}
+
+func noNamespace(name string) (string, string) {
+	return "", name
+}
+
+// escapePURLComponent percent-encodes all characters of the given package URL component other
+// than ASCII letters, digits, and the characters `.`, `-`, `_`, and `~`.
+func escapePURLComponent(s string) string {
+	var sb strings.Builder
+	for _, b := range []byte(s) {
+		if ('a' <= b && b <= 'z') || ('A' <= b && b <= 'Z') || ('0' <= b && b <= '9') || b == '.' || b == '-' || b == '_' || b == '~' {
+			sb.WriteByte(b)
+		} else {
+			fmt.Fprintf(&sb, "%%%02X", b)
+		}
+	}
+
+	return sb.String()
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
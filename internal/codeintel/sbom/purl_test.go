@@ -0,0 +1,33 @@
+package sbom
+
+import (
+	"testing"
+)
+
+func TestPackageURL(t *testing.T) {
+	testCases := []struct {
+		scheme   string
+		manager  string
+		name     string
+		version  string
+		expected string
+	}{
+		{scheme: "gomod", name: "github.com/go-nacelle/config", version: "v1.2.5", expected: "pkg:golang/github.com/go-nacelle/config@v1.2.5"},
+		{scheme: "scip-go", manager: "gomod", name: "golang.org/x/net", version: "v0.0.0-20220722155237-a158d28d115b", expected: "pkg:golang/golang.org/x/net@v0.0.0-20220722155237-a158d28d115b"},
+		{scheme: "scip-go", manager: "gomod", name: "github.com/docker/docker", version: "v20.10.7+incompatible", expected: "pkg:golang/github.com/docker/docker@v20.10.7%2Bincompatible"},
+		{scheme: "scip-typescript", manager: "npm", name: "lodash", version: "4.17.20", expected: "pkg:npm/lodash@4.17.20"},
+		{scheme: "scip-typescript", manager: "npm", name: "@types/node", version: "18.0.0", expected: "pkg:npm/%40types/node@18.0.0"},
+		{scheme: "scip-python", manager: "python", name: "Typing_Extensions", version: "4.5.0", expected: "pkg:pypi/typing-extensions@4.5.0"},
+		{scheme: "semanticdb", manager: "maven", name: "maven/com.google.guava/guava", version: "31.1-jre", expected: "pkg:maven/com.google.guava/guava@31.1-jre"},
+		{scheme: "scip-java", manager: "maven", name: "org.slf4j:slf4j-api", version: "2.0.7", expected: "pkg:maven/org.slf4j/slf4j-api@2.0.7"},
+		{scheme: "rust-analyzer", manager: "cargo", name: "tokio", version: "1.28.0", expected: "pkg:cargo/tokio@1.28.0"},
+		{scheme: "scip-ruby", manager: "gem", name: "rails", version: "7.0.4", expected: "pkg:gem/rails@7.0.4"},
+		{scheme: "scip-unknown", name: "some package", expected: "pkg:generic/some%20package"},
+	}
+
+	for _, testCase := range testCases {
+		if purl := PackageURL(testCase.scheme, testCase.manager, testCase.name, testCase.version); purl != testCase.expected {
+			t.Errorf("unexpected package URL for %s %s %q %q. want=%q have=%q", testCase.scheme, testCase.manager, testCase.name, testCase.version, testCase.expected, purl)
+		}
+	}
+}
@@ -0,0 +1,159 @@
+// Package sbom generates software bills of materials for a repository at a commit from the package
+// references of the precise indexes visible at that commit.
+package sbom
+
+import (
+	"context"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	uploadsshared "github.com/sourcegraph/sourcegraph/internal/codeintel/uploads/shared"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// Format is a serialization format of a software bill of materials.
+type Format string
+
+const (
+	// FormatCycloneDX is the CycloneDX 1.5 JSON format.
+	FormatCycloneDX Format = "cyclonedx"
+
+	// FormatSPDX is the SPDX 2.3 JSON format.
+	FormatSPDX Format = "spdx"
+)
+
+// ParseFormat returns the format with the given name. An empty name selects CycloneDX.
+func ParseFormat(name string) (Format, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "", "cyclonedx", "cyclonedx-json":
+		return FormatCycloneDX, nil
+	case "spdx", "spdx-json":
+		return FormatSPDX, nil
+	}
+
+	return "", errors.Newf("unsupported SBOM format %q", name)
+}
+
+// ContentType returns the media type of documents of the given format.
+func (f Format) ContentType() string {
+	if f == FormatSPDX {
+		return "application/spdx+json"
+	}
+
+	return "application/vnd.cyclonedx+json"
+}
+
+// Document is a format-independent software bill of materials for a repository at a commit.
+type Document struct {
+	RepositoryName string
+	Commit         string
+	Components     []Component
+	CreatedAt      time.Time
+
+	// SerialNumber uniquely identifies this document (a UUID).
+	SerialNumber string
+}
+
+// Component is a package on which the repository depends.
+type Component struct {
+	Name    string
+	Version string
+
+	// Type is the package URL type of the package's ecosystem (e.g., `golang` or `npm`).
+	Type string
+	PURL string
+}
+
+// Write serializes the given document in the given format.
+func Write(w io.Writer, format Format, doc Document) error {
+	switch format {
+	case FormatCycloneDX:
+		return writeCycloneDX(w, doc)
+	case FormatSPDX:
+		return writeSPDX(w, doc)
+	}
+
+	return errors.Newf("unsupported SBOM format %q", format)
+}
+
+type UploadService interface {
+	InferClosestUploads(ctx context.Context, repositoryID int, commit, path string, exactPath bool, indexer string) ([]uploadsshared.Dump, error)
+	ReferencesForUpload(ctx context.Context, uploadID int) (uploadsshared.PackageReferenceScanner, error)
+}
+
+// Generator builds software bills of materials from precise code intelligence data.
+type Generator struct {
+	uploadSvc UploadService
+}
+
+func NewGenerator(uploadSvc UploadService) *Generator {
+	return &Generator{uploadSvc: uploadSvc}
+}
+
+// Generate returns a software bill of materials listing the packages referenced by the precise
+// indexes visible at the given commit of the given repository. Each package is listed once, even
+// if it is referenced by multiple indexes.
+func (g *Generator) Generate(ctx context.Context, repositoryID int, repositoryName, commit string) (Document, error) {
+	uploads, err := g.uploadSvc.InferClosestUploads(ctx, repositoryID, commit, "", false, "")
+	if err != nil {
+		return Document{}, errors.Wrap(err, "uploadSvc.InferClosestUploads")
+	}
+
+	componentsByPURL := map[string]Component{}
+	for _, upload := range uploads {
+		if err := g.addReferencedComponents(ctx, upload.ID, componentsByPURL); err != nil {
+			return Document{}, err
+		}
+	}
+
+	components := make([]Component, 0, len(componentsByPURL))
+	for _, component := range componentsByPURL {
+		components = append(components, component)
+	}
+	sort.Slice(components, func(i, j int) bool { return components[i].PURL < components[j].PURL })
+
+	return Document{
+		RepositoryName: repositoryName,
+		Commit:         commit,
+		Components:     components,
+		CreatedAt:      time.Now().UTC().Truncate(time.Second),
+		SerialNumber:   uuid.NewString(),
+	}, nil
+}
+
+func (g *Generator) addReferencedComponents(ctx context.Context, uploadID int, componentsByPURL map[string]Component) (err error) {
+	scanner, err := g.uploadSvc.ReferencesForUpload(ctx, uploadID)
+	if err != nil {
+		return errors.Wrap(err, "uploadSvc.ReferencesForUpload")
+	}
+	defer func() {
+		if closeErr := scanner.Close(); closeErr != nil {
+			err = errors.Append(err, closeErr)
+		}
+	}()
+
+	for {
+		reference, exists, err := scanner.Next()
+		if err != nil {
+			return errors.Wrap(err, "scanner.Next")
+		}
+		if !exists {
+			return nil
+		}
+		if reference.Name == "" {
+			continue
+		}
+
+		purl := PackageURL(reference.Scheme, reference.Manager, reference.Name, reference.Version)
+		componentsByPURL[purl] = Component{
+			Name:    reference.Name,
+			Version: reference.Version,
+			Type:    packageTypeForReference(reference.Scheme, reference.Manager).purlType,
+			PURL:    purl,
+		}
+	}
+}
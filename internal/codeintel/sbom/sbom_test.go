@@ -0,0 +1,204 @@
+package sbom
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	uploadsshared "github.com/sourcegraph/sourcegraph/internal/codeintel/uploads/shared"
+)
+
+func TestGenerate(t *testing.T) {
+	referencesByUploadID := map[int][]uploadsshared.PackageReference{
+		42: {
+			{Package: uploadsshared.Package{DumpID: 42, Scheme: "scip-go", Manager: "gomod", Name: "github.com/go-nacelle/config", Version: "v1.2.5"}},
+			{Package: uploadsshared.Package{DumpID: 42, Scheme: "scip-go", Manager: "gomod", Name: "golang.org/x/net", Version: "v0.7.0"}},
+		},
+		43: {
+			{Package: uploadsshared.Package{DumpID: 43, Scheme: "scip-typescript", Manager: "npm", Name: "lodash", Version: "4.17.20"}},
+			{Package: uploadsshared.Package{DumpID: 43, Scheme: "scip-go", Manager: "gomod", Name: "golang.org/x/net", Version: "v0.7.0"}},
+			{Package: uploadsshared.Package{DumpID: 43, Scheme: "scip-typescript", Manager: "npm", Name: ""}},
+		},
+	}
+
+	uploadSvc := NewMockUploadService()
+	uploadSvc.InferClosestUploadsFunc.SetDefaultReturn([]uploadsshared.Dump{{ID: 42}, {ID: 43}}, nil)
+	uploadSvc.ReferencesForUploadFunc.SetDefaultHook(func(_ context.Context, uploadID int) (uploadsshared.PackageReferenceScanner, error) {
+		return newMockReferenceScanner(referencesByUploadID[uploadID]), nil
+	})
+
+	doc, err := NewGenerator(uploadSvc).Generate(context.Background(), 50, "github.com/sourcegraph/sourcegraph", "deadbeef")
+	if err != nil {
+		t.Fatalf("unexpected error generating SBOM: %s", err)
+	}
+
+	expectedComponents := []Component{
+		{Name: "github.com/go-nacelle/config", Version: "v1.2.5", Type: "golang", PURL: "pkg:golang/github.com/go-nacelle/config@v1.2.5"},
+		{Name: "golang.org/x/net", Version: "v0.7.0", Type: "golang", PURL: "pkg:golang/golang.org/x/net@v0.7.0"},
+		{Name: "lodash", Version: "4.17.20", Type: "npm", PURL: "pkg:npm/lodash@4.17.20"},
+	}
+	if diff := cmp.Diff(expectedComponents, doc.Components); diff != "" {
+		t.Errorf("unexpected components (-want +got):\n%s", diff)
+	}
+	if doc.RepositoryName != "github.com/sourcegraph/sourcegraph" || doc.Commit != "deadbeef" {
+		t.Errorf("unexpected document subject %s@%s", doc.RepositoryName, doc.Commit)
+	}
+	if doc.SerialNumber == "" {
+		t.Errorf("expected a serial number")
+	}
+
+	if history := uploadSvc.InferClosestUploadsFunc.History(); len(history) != 1 {
+		t.Fatalf("unexpected number of InferClosestUploads calls. want=%d have=%d", 1, len(history))
+	} else if history[0].Arg1 != 50 || history[0].Arg2 != "deadbeef" {
+		t.Errorf("unexpected InferClosestUploads arguments: %d %s", history[0].Arg1, history[0].Arg2)
+	}
+}
+
+func TestWriteCycloneDX(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Write(&buf, FormatCycloneDX, testDocument); err != nil {
+		t.Fatalf("unexpected error writing SBOM: %s", err)
+	}
+
+	expected := map[string]any{
+		"bomFormat":    "CycloneDX",
+		"specVersion":  "1.5",
+		"serialNumber": "urn:uuid:0b5e5e63-6e8b-4d4e-9b4b-4b9b2d0c6a7e",
+		"version":      float64(1),
+		"metadata": map[string]any{
+			"timestamp": "2023-06-01T12:00:00Z",
+			"tools":     []any{map[string]any{"vendor": "Sourcegraph", "name": "sourcegraph"}},
+			"component": map[string]any{
+				"type":    "application",
+				"bom-ref": "github.com/sourcegraph/sourcegraph@deadbeef",
+				"name":    "github.com/sourcegraph/sourcegraph",
+				"version": "deadbeef",
+			},
+		},
+		"components": []any{
+			map[string]any{
+				"type":    "library",
+				"bom-ref": "pkg:npm/%40types/node@18.0.0",
+				"name":    "@types/node",
+				"version": "18.0.0",
+				"purl":    "pkg:npm/%40types/node@18.0.0",
+			},
+		},
+		"dependencies": []any{
+			map[string]any{
+				"ref":       "github.com/sourcegraph/sourcegraph@deadbeef",
+				"dependsOn": []any{"pkg:npm/%40types/node@18.0.0"},
+			},
+		},
+	}
+	if diff := cmp.Diff(expected, decodeJSON(t, buf.Bytes())); diff != "" {
+		t.Errorf("unexpected CycloneDX document (-want +got):\n%s", diff)
+	}
+}
+
+func TestWriteSPDX(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Write(&buf, FormatSPDX, testDocument); err != nil {
+		t.Fatalf("unexpected error writing SBOM: %s", err)
+	}
+
+	expected := map[string]any{
+		"spdxVersion":       "SPDX-2.3",
+		"dataLicense":       "CC0-1.0",
+		"SPDXID":            "SPDXRef-DOCUMENT",
+		"name":              "github.com/sourcegraph/sourcegraph@deadbeef",
+		"documentNamespace": "https://sourcegraph.com/spdxdocs/github.com%2Fsourcegraph%2Fsourcegraph@deadbeef-0b5e5e63-6e8b-4d4e-9b4b-4b9b2d0c6a7e",
+		"creationInfo": map[string]any{
+			"created":  "2023-06-01T12:00:00Z",
+			"creators": []any{"Organization: Sourcegraph", "Tool: sourcegraph"},
+		},
+		"packages": []any{
+			map[string]any{
+				"name":             "github.com/sourcegraph/sourcegraph",
+				"SPDXID":           "SPDXRef-Repository",
+				"versionInfo":      "deadbeef",
+				"downloadLocation": "NOASSERTION",
+				"filesAnalyzed":    false,
+			},
+			map[string]any{
+				"name":             "@types/node",
+				"SPDXID":           "SPDXRef-Package-1",
+				"versionInfo":      "18.0.0",
+				"downloadLocation": "NOASSERTION",
+				"filesAnalyzed":    false,
+				"externalRefs": []any{
+					map[string]any{
+						"referenceCategory": "PACKAGE-MANAGER",
+						"referenceType":     "purl",
+						"referenceLocator":  "pkg:npm/%40types/node@18.0.0",
+					},
+				},
+			},
+		},
+		"relationships": []any{
+			map[string]any{"spdxElementId": "SPDXRef-DOCUMENT", "relationshipType": "DESCRIBES", "relatedSpdxElement": "SPDXRef-Repository"},
+			map[string]any{"spdxElementId": "SPDXRef-Repository", "relationshipType": "DEPENDS_ON", "relatedSpdxElement": "SPDXRef-Package-1"},
+		},
+	}
+	if diff := cmp.Diff(expected, decodeJSON(t, buf.Bytes())); diff != "" {
+		t.Errorf("unexpected SPDX document (-want +got):\n%s", diff)
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	for name, expected := range map[string]Format{
+		"":               FormatCycloneDX,
+		"CycloneDX":      FormatCycloneDX,
+		"cyclonedx-json": FormatCycloneDX,
+		"spdx":           FormatSPDX,
+		"SPDX-JSON":      FormatSPDX,
+	} {
+		if format, err := ParseFormat(name); err != nil {
+			t.Errorf("unexpected error parsing %q: %s", name, err)
+		} else if format != expected {
+			t.Errorf("unexpected format for %q. want=%q have=%q", name, expected, format)
+		}
+	}
+
+	if _, err := ParseFormat("swid"); err == nil {
+		t.Errorf("expected error parsing unsupported format")
+	}
+}
+
+var testDocument = Document{
+	RepositoryName: "github.com/sourcegraph/sourcegraph",
+	Commit:         "deadbeef",
+	Components: []Component{
+		{Name: "@types/node", Version: "18.0.0", Type: "npm", PURL: "pkg:npm/%40types/node@18.0.0"},
+	},
+	CreatedAt:    time.Date(2023, 6, 1, 12, 0, 0, 0, time.UTC),
+	SerialNumber: "0b5e5e63-6e8b-4d4e-9b4b-4b9b2d0c6a7e",
+}
+
+func newMockReferenceScanner(references []uploadsshared.PackageReference) uploadsshared.PackageReferenceScanner {
+	scanner := NewMockPackageReferenceScanner()
+	scanner.NextFunc.SetDefaultHook(func() (uploadsshared.PackageReference, bool, error) {
+		if len(references) == 0 {
+			return uploadsshared.PackageReference{}, false, nil
+		}
+
+		reference := references[0]
+		references = references[1:]
+		return reference, true, nil
+	})
+
+	return scanner
+}
+
+func decodeJSON(t *testing.T, data []byte) map[string]any {
+	var decoded map[string]any
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("unexpected error decoding JSON: %s", err)
+	}
+
+	return decoded
+}
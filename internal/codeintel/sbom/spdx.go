@@ -0,0 +1,110 @@
+package sbom
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"time"
+)
+
+// See https://spdx.github.io/spdx-spec/v2.3/.
+
+type spdxDocument struct {
+	SPDXVersion       string             `json:"spdxVersion"`
+	DataLicense       string             `json:"dataLicense"`
+	SPDXID            string             `json:"SPDXID"`
+	Name              string             `json:"name"`
+	DocumentNamespace string             `json:"documentNamespace"`
+	CreationInfo      spdxCreationInfo   `json:"creationInfo"`
+	Packages          []spdxPackage      `json:"packages"`
+	Relationships     []spdxRelationship `json:"relationships"`
+}
+
+type spdxCreationInfo struct {
+	Created  string   `json:"created"`
+	Creators []string `json:"creators"`
+}
+
+type spdxPackage struct {
+	Name             string            `json:"name"`
+	SPDXID           string            `json:"SPDXID"`
+	VersionInfo      string            `json:"versionInfo,omitempty"`
+	DownloadLocation string            `json:"downloadLocation"`
+	FilesAnalyzed    bool              `json:"filesAnalyzed"`
+	ExternalRefs     []spdxExternalRef `json:"externalRefs,omitempty"`
+}
+
+type spdxExternalRef struct {
+	ReferenceCategory string `json:"referenceCategory"`
+	ReferenceType     string `json:"referenceType"`
+	ReferenceLocator  string `json:"referenceLocator"`
+}
+
+type spdxRelationship struct {
+	SPDXElementID      string `json:"spdxElementId"`
+	RelationshipType   string `json:"relationshipType"`
+	RelatedSPDXElement string `json:"relatedSpdxElement"`
+}
+
+const (
+	spdxDocumentID    = "SPDXRef-DOCUMENT"
+	spdxRootPackageID = "SPDXRef-Repository"
+	spdxNoAssertion   = "NOASSERTION"
+)
+
+func writeSPDX(w io.Writer, doc Document) error {
+	packages := make([]spdxPackage, 0, len(doc.Components)+1)
+	packages = append(packages, spdxPackage{
+		Name:             doc.RepositoryName,
+		SPDXID:           spdxRootPackageID,
+		VersionInfo:      doc.Commit,
+		DownloadLocation: spdxNoAssertion,
+	})
+
+	relationships := make([]spdxRelationship, 0, len(doc.Components)+1)
+	relationships = append(relationships, spdxRelationship{
+		SPDXElementID:      spdxDocumentID,
+		RelationshipType:   "DESCRIBES",
+		RelatedSPDXElement: spdxRootPackageID,
+	})
+
+	for i, component := range doc.Components {
+		id := fmt.Sprintf("SPDXRef-Package-%d", i+1)
+
+		packages = append(packages, spdxPackage{
+			Name:             component.Name,
+			SPDXID:           id,
+			VersionInfo:      component.Version,
+			DownloadLocation: spdxNoAssertion,
+			ExternalRefs: []spdxExternalRef{{
+				ReferenceCategory: "PACKAGE-MANAGER",
+				ReferenceType:     "purl",
+				ReferenceLocator:  component.PURL,
+			}},
+		})
+		relationships = append(relationships, spdxRelationship{
+			SPDXElementID:      spdxRootPackageID,
+			RelationshipType:   "DEPENDS_ON",
+			RelatedSPDXElement: id,
+		})
+	}
+
+	name := doc.RepositoryName + "@" + doc.Commit
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(spdxDocument{
+		SPDXVersion:       "SPDX-2.3",
+		DataLicense:       "CC0-1.0",
+		SPDXID:            spdxDocumentID,
+		Name:              name,
+		DocumentNamespace: fmt.Sprintf("https://sourcegraph.com/spdxdocs/%s-%s", url.PathEscape(name), doc.SerialNumber),
+		CreationInfo: spdxCreationInfo{
+			Created:  doc.CreatedAt.UTC().Format(time.RFC3339),
+			Creators: []string{"Organization: Sourcegraph", "Tool: sourcegraph"},
+		},
+		Packages:      packages,
+		Relationships: relationships,
+	})
+}
@@ -0,0 +1,138 @@
+package matcher
+
+import (
+	"context"
+	"strings"
+
+	"github.com/sourcegraph/log"
+	"github.com/sourcegraph/scip/bindings/go/scip"
+
+	"github.com/sourcegraph/sourcegraph/internal/codeintel/sentinel/internal/lsifstore"
+	"github.com/sourcegraph/sourcegraph/internal/codeintel/sentinel/internal/store"
+	"github.com/sourcegraph/sourcegraph/internal/codeintel/sentinel/shared"
+)
+
+// resolveCallSites determines the call sites of a batch of vulnerability matches whose call sites have
+// not yet been resolved. A call site is a reference in the matching index to a symbol of the affected
+// package that is named by the vulnerability. Returns the number of resolved matches, the number of
+// those matches that are reachable (have at least one call site), and the number of matches whose call
+// sites could not be resolved. A match that cannot be resolved is marked as failed and retried later so
+// that it does not prevent the remaining matches from being resolved.
+func resolveCallSites(ctx context.Context, logger log.Logger, store store.Store, lsifstore lsifstore.Store, batchSize int) (numResolved, numReachable, numFailed int, err error) {
+	matches, err := store.GetUnresolvedVulnerabilityMatches(ctx, batchSize)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+
+	for _, match := range matches {
+		callSites, err := matchCallSites(ctx, lsifstore, match)
+		if err != nil {
+			if ctx.Err() != nil {
+				return numResolved, numReachable, numFailed, ctx.Err()
+			}
+
+			logger.Warn("Failed to resolve call sites of vulnerability match",
+				log.Int("matchID", match.ID),
+				log.Int("uploadID", match.UploadID),
+				log.Error(err),
+			)
+
+			if err := store.MarkVulnerabilityMatchCallSitesFailed(ctx, match.ID); err != nil {
+				return numResolved, numReachable, numFailed, err
+			}
+
+			numFailed++
+			continue
+		}
+
+		if err := store.UpdateVulnerabilityMatchCallSites(ctx, match.ID, callSites); err != nil {
+			return numResolved, numReachable, numFailed, err
+		}
+
+		numResolved++
+		if len(callSites) > 0 {
+			numReachable++
+		}
+	}
+
+	return numResolved, numReachable, numFailed, nil
+}
+
+// matchCallSites returns the references of the matching index to the symbols affected by the
+// vulnerability.
+func matchCallSites(ctx context.Context, lsifstore lsifstore.Store, match shared.UnresolvedVulnerabilityMatch) (callSites []shared.SymbolReference, _ error) {
+	for _, pkg := range match.Packages {
+		references, err := lsifstore.GetSymbolReferencesByPrefix(ctx, match.UploadID, symbolPrefix(pkg))
+		if err != nil {
+			return nil, err
+		}
+
+		for _, reference := range references {
+			if symbolIsAffected(reference.Symbol, match.AffectedPackage.AffectedSymbols) {
+				callSites = append(callSites, reference)
+			}
+		}
+	}
+
+	return callSites, nil
+}
+
+// symbolPrefix returns the prefix shared by the SCIP symbol names of all global symbols defined
+// by the given package (the scheme and package fields of the symbol).
+func symbolPrefix(pkg shared.PackageReference) string {
+	fields := []string{pkg.Scheme, pkg.Manager, pkg.Name, pkg.Version}
+	for i, field := range fields {
+		if field == "" {
+			fields[i] = "."
+		} else {
+			fields[i] = strings.ReplaceAll(field, " ", "  ")
+		}
+	}
+
+	return strings.Join(fields, " ") + " "
+}
+
+// symbolIsAffected returns true if the given SCIP symbol names one of the affected symbols of a
+// vulnerability. Affected symbols are described by a package path and a list of (optionally
+// receiver-qualified) symbol names, e.g., `Config.Load`; an empty list of names denotes every
+// symbol within the path. If the vulnerability does not name any affected symbols, then every
+// symbol of the affected package is considered to be affected.
+func symbolIsAffected(symbol string, affectedSymbols []shared.AffectedSymbol) bool {
+	if len(affectedSymbols) == 0 {
+		return true
+	}
+
+	parsed, err := scip.ParseSymbol(symbol)
+	if err != nil || parsed.Package == nil {
+		return false
+	}
+
+	var namespaces, names []string
+	for _, descriptor := range parsed.Descriptors {
+		switch descriptor.Suffix {
+		case scip.Descriptor_Namespace:
+			namespaces = append(namespaces, descriptor.Name)
+		case scip.Descriptor_Type, scip.Descriptor_Term, scip.Descriptor_Method, scip.Descriptor_Meta, scip.Descriptor_Macro:
+			names = append(names, descriptor.Name)
+		}
+	}
+	namespace := strings.Join(namespaces, "/")
+	qualifiedName := strings.Join(names, ".")
+
+	for _, affectedSymbol := range affectedSymbols {
+		if affectedSymbol.Path != "" && affectedSymbol.Path != namespace {
+			continue
+		}
+		if len(affectedSymbol.Symbols) == 0 {
+			return true
+		}
+
+		for _, name := range affectedSymbol.Symbols {
+			if name == qualifiedName {
+				return true
+			}
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,106 @@
+package matcher
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/sourcegraph/log/logtest"
+
+	"github.com/sourcegraph/sourcegraph/internal/codeintel/sentinel/shared"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+func TestResolveCallSites(t *testing.T) {
+	pkg := shared.PackageReference{Scheme: "scip-go", Manager: "gomod", Name: "github.com/go-nacelle/config", Version: "v1.2.5"}
+	callSite := shared.SymbolReference{Symbol: "scip-go gomod github.com/go-nacelle/config v1.2.5 `github.com/go-nacelle/config`/Init().", Path: "main.go"}
+
+	mockStore := NewMockStore()
+	mockStore.GetUnresolvedVulnerabilityMatchesFunc.SetDefaultReturn([]shared.UnresolvedVulnerabilityMatch{
+		{ID: 1, UploadID: 10, Packages: []shared.PackageReference{pkg}},
+		{ID: 2, UploadID: 20, Packages: []shared.PackageReference{pkg}},
+		{ID: 3, UploadID: 30, Packages: []shared.PackageReference{pkg}},
+	}, nil)
+
+	mockLSIFStore := NewMockLSIFStore()
+	mockLSIFStore.GetSymbolReferencesByPrefixFunc.SetDefaultHook(func(ctx context.Context, uploadID int, symbolPrefix string) ([]shared.SymbolReference, error) {
+		switch uploadID {
+		case 10:
+			return []shared.SymbolReference{callSite}, nil
+		case 20:
+			return nil, errors.New("corrupt index")
+		default:
+			return nil, nil
+		}
+	})
+
+	numResolved, numReachable, numFailed, err := resolveCallSites(context.Background(), logtest.Scoped(t), mockStore, mockLSIFStore, 10)
+	if err != nil {
+		t.Fatalf("unexpected error resolving call sites: %s", err)
+	}
+	if numResolved != 2 || numReachable != 1 || numFailed != 1 {
+		t.Errorf("unexpected counts. want=(2, 1, 1) have=(%d, %d, %d)", numResolved, numReachable, numFailed)
+	}
+
+	updated := map[int][]shared.SymbolReference{}
+	for _, call := range mockStore.UpdateVulnerabilityMatchCallSitesFunc.History() {
+		updated[call.Arg1] = call.Arg2
+	}
+	if diff := cmp.Diff(map[int][]shared.SymbolReference{1: {callSite}, 3: nil}, updated); diff != "" {
+		t.Errorf("unexpected call sites (-want +got):\n%s", diff)
+	}
+
+	if calls := mockStore.MarkVulnerabilityMatchCallSitesFailedFunc.History(); len(calls) != 1 || calls[0].Arg1 != 2 {
+		t.Errorf("expected match 2 to be marked as failed, have %v", calls)
+	}
+}
+
+func TestSymbolPrefix(t *testing.T) {
+	testCases := []struct {
+		pkg      shared.PackageReference
+		expected string
+	}{
+		{
+			pkg:      shared.PackageReference{Scheme: "scip-go", Manager: "gomod", Name: "github.com/go-nacelle/config", Version: "v1.2.5"},
+			expected: "scip-go gomod github.com/go-nacelle/config v1.2.5 ",
+		},
+		{
+			pkg:      shared.PackageReference{Scheme: "scip-python", Manager: "python", Name: "my package", Version: ""},
+			expected: "scip-python python my  package . ",
+		},
+	}
+
+	for _, testCase := range testCases {
+		if prefix := symbolPrefix(testCase.pkg); prefix != testCase.expected {
+			t.Errorf("unexpected symbol prefix. want=%q have=%q", testCase.expected, prefix)
+		}
+	}
+}
+
+func TestSymbolIsAffected(t *testing.T) {
+	affectedSymbols := []shared.AffectedSymbol{
+		{Path: "github.com/go-nacelle/config", Symbols: []string{"Config.Load", "Init"}},
+		{Path: "github.com/go-nacelle/config/internal/loader"},
+	}
+
+	testCases := []struct {
+		symbol          string
+		affectedSymbols []shared.AffectedSymbol
+		expected        bool
+	}{
+		{symbol: "scip-go gomod github.com/go-nacelle/config v1.2.5 `github.com/go-nacelle/config`/Config#Load().", affectedSymbols: affectedSymbols, expected: true},
+		{symbol: "scip-go gomod github.com/go-nacelle/config v1.2.5 `github.com/go-nacelle/config`/Init().", affectedSymbols: affectedSymbols, expected: true},
+		{symbol: "scip-go gomod github.com/go-nacelle/config v1.2.5 `github.com/go-nacelle/config`/Config#Dump().", affectedSymbols: affectedSymbols, expected: false},
+		{symbol: "scip-go gomod github.com/go-nacelle/config v1.2.5 `github.com/go-nacelle/config`/Config#", affectedSymbols: affectedSymbols, expected: false},
+		{symbol: "scip-go gomod github.com/go-nacelle/config v1.2.5 `github.com/go-nacelle/config/internal/loader`/Load().", affectedSymbols: affectedSymbols, expected: true},
+		{symbol: "scip-go gomod github.com/go-nacelle/config v1.2.5 `github.com/go-nacelle/config/other`/Init().", affectedSymbols: affectedSymbols, expected: false},
+		{symbol: "scip-go gomod github.com/go-nacelle/config v1.2.5 `github.com/go-nacelle/config`/Config#Dump().", affectedSymbols: nil, expected: true},
+		{symbol: "local 42", affectedSymbols: affectedSymbols, expected: false},
+	}
+
+	for _, testCase := range testCases {
+		if affected := symbolIsAffected(testCase.symbol, testCase.affectedSymbols); affected != testCase.expected {
+			t.Errorf("unexpected result for %q. want=%v have=%v", testCase.symbol, testCase.expected, affected)
+		}
+	}
+}
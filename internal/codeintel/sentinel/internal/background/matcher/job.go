@@ -4,12 +4,13 @@ import (
 	"context"
 
 	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/codeintel/sentinel/internal/lsifstore"
 	"github.com/sourcegraph/sourcegraph/internal/codeintel/sentinel/internal/store"
 	"github.com/sourcegraph/sourcegraph/internal/goroutine"
 	"github.com/sourcegraph/sourcegraph/internal/observation"
 )
 
-func NewCVEMatcher(store store.Store, observationCtx *observation.Context, config *Config) goroutine.BackgroundRoutine {
+func NewCVEMatcher(store store.Store, lsifstore lsifstore.Store, observationCtx *observation.Context, config *Config) goroutine.BackgroundRoutine {
 	metrics := newMetrics(observationCtx)
 
 	return goroutine.NewPeriodicGoroutine(
@@ -22,7 +23,12 @@ func NewCVEMatcher(store store.Store, observationCtx *observation.Context, confi
 
 			metrics.numReferencesScanned.Add(float64(numReferencesScanned))
 			metrics.numVulnerabilityMatches.Add(float64(numVulnerabilityMatches))
-			return nil
+
+			numMatchesResolved, numReachableMatches, numMatchesFailed, err := resolveCallSites(ctx, observationCtx.Logger, store, lsifstore, config.BatchSize)
+			metrics.numMatchesResolved.Add(float64(numMatchesResolved))
+			metrics.numReachableMatches.Add(float64(numReachableMatches))
+			metrics.numMatchesFailed.Add(float64(numMatchesFailed))
+			return err
 		}),
 		goroutine.WithName("codeintel.sentinel-cve-matcher"),
 		goroutine.WithDescription("Matches SCIP indexes against known vulnerabilities."),
@@ -9,6 +9,9 @@ import (
 type metrics struct {
 	numReferencesScanned    prometheus.Counter
 	numVulnerabilityMatches prometheus.Counter
+	numMatchesResolved      prometheus.Counter
+	numReachableMatches     prometheus.Counter
+	numMatchesFailed        prometheus.Counter
 }
 
 func newMetrics(observationCtx *observation.Context) *metrics {
@@ -30,9 +33,24 @@ func newMetrics(observationCtx *observation.Context) *metrics {
 		"src_codeintel_sentinel_num_vulnerability_matches_total",
 		"The total number of vulnerability matches found.",
 	)
+	numMatchesResolved := counter(
+		"src_codeintel_sentinel_num_matches_resolved_total",
+		"The total number of vulnerability matches whose call sites were resolved.",
+	)
+	numReachableMatches := counter(
+		"src_codeintel_sentinel_num_reachable_matches_total",
+		"The total number of vulnerability matches with at least one call site.",
+	)
+	numMatchesFailed := counter(
+		"src_codeintel_sentinel_num_matches_failed_total",
+		"The total number of vulnerability matches whose call sites could not be resolved.",
+	)
 
 	return &metrics{
 		numReferencesScanned:    numReferencesScanned,
 		numVulnerabilityMatches: numVulnerabilityMatches,
+		numMatchesResolved:      numMatchesResolved,
+		numReachableMatches:     numReachableMatches,
+		numMatchesFailed:        numMatchesFailed,
 	}
 }
@@ -0,0 +1,1741 @@
+// Code generated by go-mockgen 1.3.7; DO NOT EDIT.
+//
+// This file was generated by running `sg generate` (or `go-mockgen`) at the root of
+// this repository. To add additional mocks to this or another package, add a new entry
+// to the mockgen.yaml file in the root of this repository.
+
+package matcher
+
+import (
+	"context"
+	"sync"
+
+	lsifstore "github.com/sourcegraph/sourcegraph/internal/codeintel/sentinel/internal/lsifstore"
+	store "github.com/sourcegraph/sourcegraph/internal/codeintel/sentinel/internal/store"
+	shared "github.com/sourcegraph/sourcegraph/internal/codeintel/sentinel/shared"
+)
+
+// MockStore is a mock implementation of the Store interface (from the
+// package
+// github.com/sourcegraph/sourcegraph/internal/codeintel/sentinel/internal/store)
+// used for unit testing.
+type MockStore struct {
+	// GetUnresolvedVulnerabilityMatchesFunc is an instance of a mock
+	// function object controlling the behavior of the method
+	// GetUnresolvedVulnerabilityMatches.
+	GetUnresolvedVulnerabilityMatchesFunc *StoreGetUnresolvedVulnerabilityMatchesFunc
+	// GetVulnerabilitiesFunc is an instance of a mock function object
+	// controlling the behavior of the method GetVulnerabilities.
+	GetVulnerabilitiesFunc *StoreGetVulnerabilitiesFunc
+	// GetVulnerabilitiesByIDsFunc is an instance of a mock function object
+	// controlling the behavior of the method GetVulnerabilitiesByIDs.
+	GetVulnerabilitiesByIDsFunc *StoreGetVulnerabilitiesByIDsFunc
+	// GetVulnerabilityMatchesFunc is an instance of a mock function object
+	// controlling the behavior of the method GetVulnerabilityMatches.
+	GetVulnerabilityMatchesFunc *StoreGetVulnerabilityMatchesFunc
+	// GetVulnerabilityMatchesCountByRepositoryFunc is an instance of a mock
+	// function object controlling the behavior of the method
+	// GetVulnerabilityMatchesCountByRepository.
+	GetVulnerabilityMatchesCountByRepositoryFunc *StoreGetVulnerabilityMatchesCountByRepositoryFunc
+	// GetVulnerabilityMatchesSummaryCountFunc is an instance of a mock
+	// function object controlling the behavior of the method
+	// GetVulnerabilityMatchesSummaryCount.
+	GetVulnerabilityMatchesSummaryCountFunc *StoreGetVulnerabilityMatchesSummaryCountFunc
+	// InsertVulnerabilitiesFunc is an instance of a mock function object
+	// controlling the behavior of the method InsertVulnerabilities.
+	InsertVulnerabilitiesFunc *StoreInsertVulnerabilitiesFunc
+	// MarkVulnerabilityMatchCallSitesFailedFunc is an instance of a mock
+	// function object controlling the behavior of the method
+	// MarkVulnerabilityMatchCallSitesFailed.
+	MarkVulnerabilityMatchCallSitesFailedFunc *StoreMarkVulnerabilityMatchCallSitesFailedFunc
+	// ScanMatchesFunc is an instance of a mock function object controlling
+	// the behavior of the method ScanMatches.
+	ScanMatchesFunc *StoreScanMatchesFunc
+	// UpdateVulnerabilityMatchCallSitesFunc is an instance of a mock
+	// function object controlling the behavior of the method
+	// UpdateVulnerabilityMatchCallSites.
+	UpdateVulnerabilityMatchCallSitesFunc *StoreUpdateVulnerabilityMatchCallSitesFunc
+	// VulnerabilityByIDFunc is an instance of a mock function object
+	// controlling the behavior of the method VulnerabilityByID.
+	VulnerabilityByIDFunc *StoreVulnerabilityByIDFunc
+	// VulnerabilityMatchByIDFunc is an instance of a mock function object
+	// controlling the behavior of the method VulnerabilityMatchByID.
+	VulnerabilityMatchByIDFunc *StoreVulnerabilityMatchByIDFunc
+}
+
+// NewMockStore creates a new mock of the Store interface. All methods
+// return zero values for all results, unless overwritten.
+func NewMockStore() *MockStore {
+	return &MockStore{
+		GetUnresolvedVulnerabilityMatchesFunc: &StoreGetUnresolvedVulnerabilityMatchesFunc{
+			defaultHook: func(context.Context, int) (r0 []shared.UnresolvedVulnerabilityMatch, r1 error) {
+				return
+			},
+		},
+		GetVulnerabilitiesFunc: &StoreGetVulnerabilitiesFunc{
+			defaultHook: func(context.Context, shared.GetVulnerabilitiesArgs) (r0 []shared.Vulnerability, r1 int, r2 error) {
+				return
+			},
+		},
+		GetVulnerabilitiesByIDsFunc: &StoreGetVulnerabilitiesByIDsFunc{
+			defaultHook: func(context.Context, ...int) (r0 []shared.Vulnerability, r1 error) {
+				return
+			},
+		},
+		GetVulnerabilityMatchesFunc: &StoreGetVulnerabilityMatchesFunc{
+			defaultHook: func(context.Context, shared.GetVulnerabilityMatchesArgs) (r0 []shared.VulnerabilityMatch, r1 int, r2 error) {
+				return
+			},
+		},
+		GetVulnerabilityMatchesCountByRepositoryFunc: &StoreGetVulnerabilityMatchesCountByRepositoryFunc{
+			defaultHook: func(context.Context, shared.GetVulnerabilityMatchesCountByRepositoryArgs) (r0 []shared.VulnerabilityMatchesByRepository, r1 int, r2 error) {
+				return
+			},
+		},
+		GetVulnerabilityMatchesSummaryCountFunc: &StoreGetVulnerabilityMatchesSummaryCountFunc{
+			defaultHook: func(context.Context) (r0 shared.GetVulnerabilityMatchesSummaryCounts, r1 error) {
+				return
+			},
+		},
+		InsertVulnerabilitiesFunc: &StoreInsertVulnerabilitiesFunc{
+			defaultHook: func(context.Context, []shared.Vulnerability) (r0 int, r1 error) {
+				return
+			},
+		},
+		MarkVulnerabilityMatchCallSitesFailedFunc: &StoreMarkVulnerabilityMatchCallSitesFailedFunc{
+			defaultHook: func(context.Context, int) (r0 error) {
+				return
+			},
+		},
+		ScanMatchesFunc: &StoreScanMatchesFunc{
+			defaultHook: func(context.Context, int) (r0 int, r1 int, r2 error) {
+				return
+			},
+		},
+		UpdateVulnerabilityMatchCallSitesFunc: &StoreUpdateVulnerabilityMatchCallSitesFunc{
+			defaultHook: func(context.Context, int, []shared.SymbolReference) (r0 error) {
+				return
+			},
+		},
+		VulnerabilityByIDFunc: &StoreVulnerabilityByIDFunc{
+			defaultHook: func(context.Context, int) (r0 shared.Vulnerability, r1 bool, r2 error) {
+				return
+			},
+		},
+		VulnerabilityMatchByIDFunc: &StoreVulnerabilityMatchByIDFunc{
+			defaultHook: func(context.Context, int) (r0 shared.VulnerabilityMatch, r1 bool, r2 error) {
+				return
+			},
+		},
+	}
+}
+
+// NewStrictMockStore creates a new mock of the Store interface. All methods
+// panic on invocation, unless overwritten.
+func NewStrictMockStore() *MockStore {
+	return &MockStore{
+		GetUnresolvedVulnerabilityMatchesFunc: &StoreGetUnresolvedVulnerabilityMatchesFunc{
+			defaultHook: func(context.Context, int) ([]shared.UnresolvedVulnerabilityMatch, error) {
+				panic("unexpected invocation of MockStore.GetUnresolvedVulnerabilityMatches")
+			},
+		},
+		GetVulnerabilitiesFunc: &StoreGetVulnerabilitiesFunc{
+			defaultHook: func(context.Context, shared.GetVulnerabilitiesArgs) ([]shared.Vulnerability, int, error) {
+				panic("unexpected invocation of MockStore.GetVulnerabilities")
+			},
+		},
+		GetVulnerabilitiesByIDsFunc: &StoreGetVulnerabilitiesByIDsFunc{
+			defaultHook: func(context.Context, ...int) ([]shared.Vulnerability, error) {
+				panic("unexpected invocation of MockStore.GetVulnerabilitiesByIDs")
+			},
+		},
+		GetVulnerabilityMatchesFunc: &StoreGetVulnerabilityMatchesFunc{
+			defaultHook: func(context.Context, shared.GetVulnerabilityMatchesArgs) ([]shared.VulnerabilityMatch, int, error) {
+				panic("unexpected invocation of MockStore.GetVulnerabilityMatches")
+			},
+		},
+		GetVulnerabilityMatchesCountByRepositoryFunc: &StoreGetVulnerabilityMatchesCountByRepositoryFunc{
+			defaultHook: func(context.Context, shared.GetVulnerabilityMatchesCountByRepositoryArgs) ([]shared.VulnerabilityMatchesByRepository, int, error) {
+				panic("unexpected invocation of MockStore.GetVulnerabilityMatchesCountByRepository")
+			},
+		},
+		GetVulnerabilityMatchesSummaryCountFunc: &StoreGetVulnerabilityMatchesSummaryCountFunc{
+			defaultHook: func(context.Context) (shared.GetVulnerabilityMatchesSummaryCounts, error) {
+				panic("unexpected invocation of MockStore.GetVulnerabilityMatchesSummaryCount")
+			},
+		},
+		InsertVulnerabilitiesFunc: &StoreInsertVulnerabilitiesFunc{
+			defaultHook: func(context.Context, []shared.Vulnerability) (int, error) {
+				panic("unexpected invocation of MockStore.InsertVulnerabilities")
+			},
+		},
+		MarkVulnerabilityMatchCallSitesFailedFunc: &StoreMarkVulnerabilityMatchCallSitesFailedFunc{
+			defaultHook: func(context.Context, int) error {
+				panic("unexpected invocation of MockStore.MarkVulnerabilityMatchCallSitesFailed")
+			},
+		},
+		ScanMatchesFunc: &StoreScanMatchesFunc{
+			defaultHook: func(context.Context, int) (int, int, error) {
+				panic("unexpected invocation of MockStore.ScanMatches")
+			},
+		},
+		UpdateVulnerabilityMatchCallSitesFunc: &StoreUpdateVulnerabilityMatchCallSitesFunc{
+			defaultHook: func(context.Context, int, []shared.SymbolReference) error {
+				panic("unexpected invocation of MockStore.UpdateVulnerabilityMatchCallSites")
+			},
+		},
+		VulnerabilityByIDFunc: &StoreVulnerabilityByIDFunc{
+			defaultHook: func(context.Context, int) (shared.Vulnerability, bool, error) {
+				panic("unexpected invocation of MockStore.VulnerabilityByID")
+			},
+		},
+		VulnerabilityMatchByIDFunc: &StoreVulnerabilityMatchByIDFunc{
+			defaultHook: func(context.Context, int) (shared.VulnerabilityMatch, bool, error) {
+				panic("unexpected invocation of MockStore.VulnerabilityMatchByID")
+			},
+		},
+	}
+}
+
+// NewMockStoreFrom creates a new mock of the MockStore interface. All
+// methods delegate to the given implementation, unless overwritten.
+func NewMockStoreFrom(i store.Store) *MockStore {
+	return &MockStore{
+		GetUnresolvedVulnerabilityMatchesFunc: &StoreGetUnresolvedVulnerabilityMatchesFunc{
+			defaultHook: i.GetUnresolvedVulnerabilityMatches,
+		},
+		GetVulnerabilitiesFunc: &StoreGetVulnerabilitiesFunc{
+			defaultHook: i.GetVulnerabilities,
+		},
+		GetVulnerabilitiesByIDsFunc: &StoreGetVulnerabilitiesByIDsFunc{
+			defaultHook: i.GetVulnerabilitiesByIDs,
+		},
+		GetVulnerabilityMatchesFunc: &StoreGetVulnerabilityMatchesFunc{
+			defaultHook: i.GetVulnerabilityMatches,
+		},
+		GetVulnerabilityMatchesCountByRepositoryFunc: &StoreGetVulnerabilityMatchesCountByRepositoryFunc{
+			defaultHook: i.GetVulnerabilityMatchesCountByRepository,
+		},
+		GetVulnerabilityMatchesSummaryCountFunc: &StoreGetVulnerabilityMatchesSummaryCountFunc{
+			defaultHook: i.GetVulnerabilityMatchesSummaryCount,
+		},
+		InsertVulnerabilitiesFunc: &StoreInsertVulnerabilitiesFunc{
+			defaultHook: i.InsertVulnerabilities,
+		},
+		MarkVulnerabilityMatchCallSitesFailedFunc: &StoreMarkVulnerabilityMatchCallSitesFailedFunc{
+			defaultHook: i.MarkVulnerabilityMatchCallSitesFailed,
+		},
+		ScanMatchesFunc: &StoreScanMatchesFunc{
+			defaultHook: i.ScanMatches,
+		},
+		UpdateVulnerabilityMatchCallSitesFunc: &StoreUpdateVulnerabilityMatchCallSitesFunc{
+			defaultHook: i.UpdateVulnerabilityMatchCallSites,
+		},
+		VulnerabilityByIDFunc: &StoreVulnerabilityByIDFunc{
+			defaultHook: i.VulnerabilityByID,
+		},
+		VulnerabilityMatchByIDFunc: &StoreVulnerabilityMatchByIDFunc{
+			defaultHook: i.VulnerabilityMatchByID,
+		},
+	}
+}
+
+// StoreGetUnresolvedVulnerabilityMatchesFunc describes the behavior when
+// the GetUnresolvedVulnerabilityMatches method of the parent MockStore
+// instance is invoked.
+type StoreGetUnresolvedVulnerabilityMatchesFunc struct {
+	defaultHook func(context.Context, int) ([]shared.UnresolvedVulnerabilityMatch, error)
+	hooks       []func(context.Context, int) ([]shared.UnresolvedVulnerabilityMatch, error)
+	history     []StoreGetUnresolvedVulnerabilityMatchesFuncCall
+	mutex       sync.Mutex
+}
+
+// GetUnresolvedVulnerabilityMatches delegates to the next hook function in
+// the queue and stores the parameter and result values of this invocation.
+func (m *MockStore) GetUnresolvedVulnerabilityMatches(v0 context.Context, v1 int) ([]shared.UnresolvedVulnerabilityMatch, error) {
+	r0, r1 := m.GetUnresolvedVulnerabilityMatchesFunc.nextHook()(v0, v1)
+	m.GetUnresolvedVulnerabilityMatchesFunc.appendCall(StoreGetUnresolvedVulnerabilityMatchesFuncCall{v0, v1, r0, r1})
+	return r0, r1
+}
+
+// SetDefaultHook sets function that is called when the
+// GetUnresolvedVulnerabilityMatches method of the parent MockStore instance
+// is invoked and the hook queue is empty.
+func (f *StoreGetUnresolvedVulnerabilityMatchesFunc) SetDefaultHook(hook func(context.Context, int) ([]shared.UnresolvedVulnerabilityMatch, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// GetUnresolvedVulnerabilityMatches method of the parent MockStore instance
+// invokes the hook at the front of the queue and discards it. After the
+// queue is empty, the default hook function is invoked for any future
+// action.
+func (f *StoreGetUnresolvedVulnerabilityMatchesFunc) PushHook(hook func(context.Context, int) ([]shared.UnresolvedVulnerabilityMatch, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultHook with a function that returns the
+// given values.
+func (f *StoreGetUnresolvedVulnerabilityMatchesFunc) SetDefaultReturn(r0 []shared.UnresolvedVulnerabilityMatch, r1 error) {
+	f.SetDefaultHook(func(context.Context, int) ([]shared.UnresolvedVulnerabilityMatch, error) {
+		return r0, r1
+	})
+}
+
+// PushReturn calls PushHook with a function that returns the given values.
+func (f *StoreGetUnresolvedVulnerabilityMatchesFunc) PushReturn(r0 []shared.UnresolvedVulnerabilityMatch, r1 error) {
+	f.PushHook(func(context.Context, int) ([]shared.UnresolvedVulnerabilityMatch, error) {
+		return r0, r1
+	})
+}
+
+func (f *StoreGetUnresolvedVulnerabilityMatchesFunc) nextHook() func(context.Context, int) ([]shared.UnresolvedVulnerabilityMatch, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *StoreGetUnresolvedVulnerabilityMatchesFunc) appendCall(r0 StoreGetUnresolvedVulnerabilityMatchesFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of
+// StoreGetUnresolvedVulnerabilityMatchesFuncCall objects describing the
+// invocations of this function.
+func (f *StoreGetUnresolvedVulnerabilityMatchesFunc) History() []StoreGetUnresolvedVulnerabilityMatchesFuncCall {
+	f.mutex.Lock()
+	history := make([]StoreGetUnresolvedVulnerabilityMatchesFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// StoreGetUnresolvedVulnerabilityMatchesFuncCall is an object that
+// describes an invocation of method GetUnresolvedVulnerabilityMatches on an
+// instance of MockStore.
+type StoreGetUnresolvedVulnerabilityMatchesFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 int
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 []shared.UnresolvedVulnerabilityMatch
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c StoreGetUnresolvedVulnerabilityMatchesFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c StoreGetUnresolvedVulnerabilityMatchesFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1}
+}
+
+// StoreGetVulnerabilitiesFunc describes the behavior when the
+// GetVulnerabilities method of the parent MockStore instance is invoked.
+type StoreGetVulnerabilitiesFunc struct {
+	defaultHook func(context.Context, shared.GetVulnerabilitiesArgs) ([]shared.Vulnerability, int, error)
+	hooks       []func(context.Context, shared.GetVulnerabilitiesArgs) ([]shared.Vulnerability, int, error)
+	history     []StoreGetVulnerabilitiesFuncCall
+	mutex       sync.Mutex
+}
+
+// GetVulnerabilities delegates to the next hook function in the queue and
+// stores the parameter and result values of this invocation.
+func (m *MockStore) GetVulnerabilities(v0 context.Context, v1 shared.GetVulnerabilitiesArgs) ([]shared.Vulnerability, int, error) {
+	r0, r1, r2 := m.GetVulnerabilitiesFunc.nextHook()(v0, v1)
+	m.GetVulnerabilitiesFunc.appendCall(StoreGetVulnerabilitiesFuncCall{v0, v1, r0, r1, r2})
+	return r0, r1, r2
+}
+
+// SetDefaultHook sets function that is called when the GetVulnerabilities
+// method of the parent MockStore instance is invoked and the hook queue is
+// empty.
+func (f *StoreGetVulnerabilitiesFunc) SetDefaultHook(hook func(context.Context, shared.GetVulnerabilitiesArgs) ([]shared.Vulnerability, int, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// GetVulnerabilities method of the parent MockStore instance invokes the
+// hook at the front of the queue and discards it. After the queue is empty,
+// the default hook function is invoked for any future action.
+func (f *StoreGetVulnerabilitiesFunc) PushHook(hook func(context.Context, shared.GetVulnerabilitiesArgs) ([]shared.Vulnerability, int, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultHook with a function that returns the
+// given values.
+func (f *StoreGetVulnerabilitiesFunc) SetDefaultReturn(r0 []shared.Vulnerability, r1 int, r2 error) {
+	f.SetDefaultHook(func(context.Context, shared.GetVulnerabilitiesArgs) ([]shared.Vulnerability, int, error) {
+		return r0, r1, r2
+	})
+}
+
+// PushReturn calls PushHook with a function that returns the given values.
+func (f *StoreGetVulnerabilitiesFunc) PushReturn(r0 []shared.Vulnerability, r1 int, r2 error) {
+	f.PushHook(func(context.Context, shared.GetVulnerabilitiesArgs) ([]shared.Vulnerability, int, error) {
+		return r0, r1, r2
+	})
+}
+
+func (f *StoreGetVulnerabilitiesFunc) nextHook() func(context.Context, shared.GetVulnerabilitiesArgs) ([]shared.Vulnerability, int, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *StoreGetVulnerabilitiesFunc) appendCall(r0 StoreGetVulnerabilitiesFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of StoreGetVulnerabilitiesFuncCall objects
+// describing the invocations of this function.
+func (f *StoreGetVulnerabilitiesFunc) History() []StoreGetVulnerabilitiesFuncCall {
+	f.mutex.Lock()
+	history := make([]StoreGetVulnerabilitiesFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// StoreGetVulnerabilitiesFuncCall is an object that describes an invocation
+// of method GetVulnerabilities on an instance of MockStore.
+type StoreGetVulnerabilitiesFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 shared.GetVulnerabilitiesArgs
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 []shared.Vulnerability
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 int
+	// Result2 is the value of the 3rd result returned from this method
+	// invocation.
+	Result2 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c StoreGetVulnerabilitiesFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c StoreGetVulnerabilitiesFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1, c.Result2}
+}
+
+// StoreGetVulnerabilitiesByIDsFunc describes the behavior when the
+// GetVulnerabilitiesByIDs method of the parent MockStore instance is
+// invoked.
+type StoreGetVulnerabilitiesByIDsFunc struct {
+	defaultHook func(context.Context, ...int) ([]shared.Vulnerability, error)
+	hooks       []func(context.Context, ...int) ([]shared.Vulnerability, error)
+	history     []StoreGetVulnerabilitiesByIDsFuncCall
+	mutex       sync.Mutex
+}
+
+// GetVulnerabilitiesByIDs delegates to the next hook function in the queue
+// and stores the parameter and result values of this invocation.
+func (m *MockStore) GetVulnerabilitiesByIDs(v0 context.Context, v1 ...int) ([]shared.Vulnerability, error) {
+	r0, r1 := m.GetVulnerabilitiesByIDsFunc.nextHook()(v0, v1...)
+	m.GetVulnerabilitiesByIDsFunc.appendCall(StoreGetVulnerabilitiesByIDsFuncCall{v0, v1, r0, r1})
+	return r0, r1
+}
+
+// SetDefaultHook sets function that is called when the
+// GetVulnerabilitiesByIDs method of the parent MockStore instance is
+// invoked and the hook queue is empty.
+func (f *StoreGetVulnerabilitiesByIDsFunc) SetDefaultHook(hook func(context.Context, ...int) ([]shared.Vulnerability, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// GetVulnerabilitiesByIDs method of the parent MockStore instance invokes
+// the hook at the front of the queue and discards it. After the queue is
+// empty, the default hook function is invoked for any future action.
+func (f *StoreGetVulnerabilitiesByIDsFunc) PushHook(hook func(context.Context, ...int) ([]shared.Vulnerability, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultHook with a function that returns the
+// given values.
+func (f *StoreGetVulnerabilitiesByIDsFunc) SetDefaultReturn(r0 []shared.Vulnerability, r1 error) {
+	f.SetDefaultHook(func(context.Context, ...int) ([]shared.Vulnerability, error) {
+		return r0, r1
+	})
+}
+
+// PushReturn calls PushHook with a function that returns the given values.
+func (f *StoreGetVulnerabilitiesByIDsFunc) PushReturn(r0 []shared.Vulnerability, r1 error) {
+	f.PushHook(func(context.Context, ...int) ([]shared.Vulnerability, error) {
+		return r0, r1
+	})
+}
+
+func (f *StoreGetVulnerabilitiesByIDsFunc) nextHook() func(context.Context, ...int) ([]shared.Vulnerability, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *StoreGetVulnerabilitiesByIDsFunc) appendCall(r0 StoreGetVulnerabilitiesByIDsFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of StoreGetVulnerabilitiesByIDsFuncCall
+// objects describing the invocations of this function.
+func (f *StoreGetVulnerabilitiesByIDsFunc) History() []StoreGetVulnerabilitiesByIDsFuncCall {
+	f.mutex.Lock()
+	history := make([]StoreGetVulnerabilitiesByIDsFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// StoreGetVulnerabilitiesByIDsFuncCall is an object that describes an
+// invocation of method GetVulnerabilitiesByIDs on an instance of MockStore.
+type StoreGetVulnerabilitiesByIDsFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is a slice containing the values of the variadic arguments
+	// passed to this method invocation.
+	Arg1 []int
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 []shared.Vulnerability
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation. The variadic slice argument is flattened in this array such
+// that one positional argument and three variadic arguments would result in
+// a slice of four, not two.
+func (c StoreGetVulnerabilitiesByIDsFuncCall) Args() []interface{} {
+	trailing := []interface{}{}
+	for _, val := range c.Arg1 {
+		trailing = append(trailing, val)
+	}
+
+	return append([]interface{}{c.Arg0}, trailing...)
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c StoreGetVulnerabilitiesByIDsFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1}
+}
+
+// StoreGetVulnerabilityMatchesFunc describes the behavior when the
+// GetVulnerabilityMatches method of the parent MockStore instance is
+// invoked.
+type StoreGetVulnerabilityMatchesFunc struct {
+	defaultHook func(context.Context, shared.GetVulnerabilityMatchesArgs) ([]shared.VulnerabilityMatch, int, error)
+	hooks       []func(context.Context, shared.GetVulnerabilityMatchesArgs) ([]shared.VulnerabilityMatch, int, error)
+	history     []StoreGetVulnerabilityMatchesFuncCall
+	mutex       sync.Mutex
+}
+
+// GetVulnerabilityMatches delegates to the next hook function in the queue
+// and stores the parameter and result values of this invocation.
+func (m *MockStore) GetVulnerabilityMatches(v0 context.Context, v1 shared.GetVulnerabilityMatchesArgs) ([]shared.VulnerabilityMatch, int, error) {
+	r0, r1, r2 := m.GetVulnerabilityMatchesFunc.nextHook()(v0, v1)
+	m.GetVulnerabilityMatchesFunc.appendCall(StoreGetVulnerabilityMatchesFuncCall{v0, v1, r0, r1, r2})
+	return r0, r1, r2
+}
+
+// SetDefaultHook sets function that is called when the
+// GetVulnerabilityMatches method of the parent MockStore instance is
+// invoked and the hook queue is empty.
+func (f *StoreGetVulnerabilityMatchesFunc) SetDefaultHook(hook func(context.Context, shared.GetVulnerabilityMatchesArgs) ([]shared.VulnerabilityMatch, int, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// GetVulnerabilityMatches method of the parent MockStore instance invokes
+// the hook at the front of the queue and discards it. After the queue is
+// empty, the default hook function is invoked for any future action.
+func (f *StoreGetVulnerabilityMatchesFunc) PushHook(hook func(context.Context, shared.GetVulnerabilityMatchesArgs) ([]shared.VulnerabilityMatch, int, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultHook with a function that returns the
+// given values.
+func (f *StoreGetVulnerabilityMatchesFunc) SetDefaultReturn(r0 []shared.VulnerabilityMatch, r1 int, r2 error) {
+	f.SetDefaultHook(func(context.Context, shared.GetVulnerabilityMatchesArgs) ([]shared.VulnerabilityMatch, int, error) {
+		return r0, r1, r2
+	})
+}
+
+// PushReturn calls PushHook with a function that returns the given values.
+func (f *StoreGetVulnerabilityMatchesFunc) PushReturn(r0 []shared.VulnerabilityMatch, r1 int, r2 error) {
+	f.PushHook(func(context.Context, shared.GetVulnerabilityMatchesArgs) ([]shared.VulnerabilityMatch, int, error) {
+		return r0, r1, r2
+	})
+}
+
+func (f *StoreGetVulnerabilityMatchesFunc) nextHook() func(context.Context, shared.GetVulnerabilityMatchesArgs) ([]shared.VulnerabilityMatch, int, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *StoreGetVulnerabilityMatchesFunc) appendCall(r0 StoreGetVulnerabilityMatchesFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of StoreGetVulnerabilityMatchesFuncCall
+// objects describing the invocations of this function.
+func (f *StoreGetVulnerabilityMatchesFunc) History() []StoreGetVulnerabilityMatchesFuncCall {
+	f.mutex.Lock()
+	history := make([]StoreGetVulnerabilityMatchesFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// StoreGetVulnerabilityMatchesFuncCall is an object that describes an
+// invocation of method GetVulnerabilityMatches on an instance of MockStore.
+type StoreGetVulnerabilityMatchesFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 shared.GetVulnerabilityMatchesArgs
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 []shared.VulnerabilityMatch
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 int
+	// Result2 is the value of the 3rd result returned from this method
+	// invocation.
+	Result2 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c StoreGetVulnerabilityMatchesFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c StoreGetVulnerabilityMatchesFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1, c.Result2}
+}
+
+// StoreGetVulnerabilityMatchesCountByRepositoryFunc describes the behavior
+// when the GetVulnerabilityMatchesCountByRepository method of the parent
+// MockStore instance is invoked.
+type StoreGetVulnerabilityMatchesCountByRepositoryFunc struct {
+	defaultHook func(context.Context, shared.GetVulnerabilityMatchesCountByRepositoryArgs) ([]shared.VulnerabilityMatchesByRepository, int, error)
+	hooks       []func(context.Context, shared.GetVulnerabilityMatchesCountByRepositoryArgs) ([]shared.VulnerabilityMatchesByRepository, int, error)
+	history     []StoreGetVulnerabilityMatchesCountByRepositoryFuncCall
+	mutex       sync.Mutex
+}
+
+// GetVulnerabilityMatchesCountByRepository delegates to the next hook
+// function in the queue and stores the parameter and result values of this
+// invocation.
+func (m *MockStore) GetVulnerabilityMatchesCountByRepository(v0 context.Context, v1 shared.GetVulnerabilityMatchesCountByRepositoryArgs) ([]shared.VulnerabilityMatchesByRepository, int, error) {
+	r0, r1, r2 := m.GetVulnerabilityMatchesCountByRepositoryFunc.nextHook()(v0, v1)
+	m.GetVulnerabilityMatchesCountByRepositoryFunc.appendCall(StoreGetVulnerabilityMatchesCountByRepositoryFuncCall{v0, v1, r0, r1, r2})
+	return r0, r1, r2
+}
+
+// SetDefaultHook sets function that is called when the
+// GetVulnerabilityMatchesCountByRepository method of the parent MockStore
+// instance is invoked and the hook queue is empty.
+func (f *StoreGetVulnerabilityMatchesCountByRepositoryFunc) SetDefaultHook(hook func(context.Context, shared.GetVulnerabilityMatchesCountByRepositoryArgs) ([]shared.VulnerabilityMatchesByRepository, int, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// GetVulnerabilityMatchesCountByRepository method of the parent MockStore
+// instance invokes the hook at the front of the queue and discards it.
+// After the queue is empty, the default hook function is invoked for any
+// future action.
+func (f *StoreGetVulnerabilityMatchesCountByRepositoryFunc) PushHook(hook func(context.Context, shared.GetVulnerabilityMatchesCountByRepositoryArgs) ([]shared.VulnerabilityMatchesByRepository, int, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultHook with a function that returns the
+// given values.
+func (f *StoreGetVulnerabilityMatchesCountByRepositoryFunc) SetDefaultReturn(r0 []shared.VulnerabilityMatchesByRepository, r1 int, r2 error) {
+	f.SetDefaultHook(func(context.Context, shared.GetVulnerabilityMatchesCountByRepositoryArgs) ([]shared.VulnerabilityMatchesByRepository, int, error) {
+		return r0, r1, r2
+	})
+}
+
+// PushReturn calls PushHook with a function that returns the given values.
+func (f *StoreGetVulnerabilityMatchesCountByRepositoryFunc) PushReturn(r0 []shared.VulnerabilityMatchesByRepository, r1 int, r2 error) {
+	f.PushHook(func(context.Context, shared.GetVulnerabilityMatchesCountByRepositoryArgs) ([]shared.VulnerabilityMatchesByRepository, int, error) {
+		return r0, r1, r2
+	})
+}
+
+func (f *StoreGetVulnerabilityMatchesCountByRepositoryFunc) nextHook() func(context.Context, shared.GetVulnerabilityMatchesCountByRepositoryArgs) ([]shared.VulnerabilityMatchesByRepository, int, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *StoreGetVulnerabilityMatchesCountByRepositoryFunc) appendCall(r0 StoreGetVulnerabilityMatchesCountByRepositoryFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of
+// StoreGetVulnerabilityMatchesCountByRepositoryFuncCall objects describing
+// the invocations of this function.
+func (f *StoreGetVulnerabilityMatchesCountByRepositoryFunc) History() []StoreGetVulnerabilityMatchesCountByRepositoryFuncCall {
+	f.mutex.Lock()
+	history := make([]StoreGetVulnerabilityMatchesCountByRepositoryFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// StoreGetVulnerabilityMatchesCountByRepositoryFuncCall is an object that
+// describes an invocation of method
+// GetVulnerabilityMatchesCountByRepository on an instance of MockStore.
+type StoreGetVulnerabilityMatchesCountByRepositoryFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 shared.GetVulnerabilityMatchesCountByRepositoryArgs
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 []shared.VulnerabilityMatchesByRepository
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 int
+	// Result2 is the value of the 3rd result returned from this method
+	// invocation.
+	Result2 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c StoreGetVulnerabilityMatchesCountByRepositoryFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c StoreGetVulnerabilityMatchesCountByRepositoryFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1, c.Result2}
+}
+
+// StoreGetVulnerabilityMatchesSummaryCountFunc describes the behavior when
+// the GetVulnerabilityMatchesSummaryCount method of the parent MockStore
+// instance is invoked.
+type StoreGetVulnerabilityMatchesSummaryCountFunc struct {
+	defaultHook func(context.Context) (shared.GetVulnerabilityMatchesSummaryCounts, error)
+	hooks       []func(context.Context) (shared.GetVulnerabilityMatchesSummaryCounts, error)
+	history     []StoreGetVulnerabilityMatchesSummaryCountFuncCall
+	mutex       sync.Mutex
+}
+
+// GetVulnerabilityMatchesSummaryCount delegates to the next hook function
+// in the queue and stores the parameter and result values of this
+// invocation.
+func (m *MockStore) GetVulnerabilityMatchesSummaryCount(v0 context.Context) (shared.GetVulnerabilityMatchesSummaryCounts, error) {
+	r0, r1 := m.GetVulnerabilityMatchesSummaryCountFunc.nextHook()(v0)
+	m.GetVulnerabilityMatchesSummaryCountFunc.appendCall(StoreGetVulnerabilityMatchesSummaryCountFuncCall{v0, r0, r1})
+	return r0, r1
+}
+
+// SetDefaultHook sets function that is called when the
+// GetVulnerabilityMatchesSummaryCount method of the parent MockStore
+// instance is invoked and the hook queue is empty.
+func (f *StoreGetVulnerabilityMatchesSummaryCountFunc) SetDefaultHook(hook func(context.Context) (shared.GetVulnerabilityMatchesSummaryCounts, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// GetVulnerabilityMatchesSummaryCount method of the parent MockStore
+// instance invokes the hook at the front of the queue and discards it.
+// After the queue is empty, the default hook function is invoked for any
+// future action.
+func (f *StoreGetVulnerabilityMatchesSummaryCountFunc) PushHook(hook func(context.Context) (shared.GetVulnerabilityMatchesSummaryCounts, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultHook with a function that returns the
+// given values.
+func (f *StoreGetVulnerabilityMatchesSummaryCountFunc) SetDefaultReturn(r0 shared.GetVulnerabilityMatchesSummaryCounts, r1 error) {
+	f.SetDefaultHook(func(context.Context) (shared.GetVulnerabilityMatchesSummaryCounts, error) {
+		return r0, r1
+	})
+}
+
+// PushReturn calls PushHook with a function that returns the given values.
+func (f *StoreGetVulnerabilityMatchesSummaryCountFunc) PushReturn(r0 shared.GetVulnerabilityMatchesSummaryCounts, r1 error) {
+	f.PushHook(func(context.Context) (shared.GetVulnerabilityMatchesSummaryCounts, error) {
+		return r0, r1
+	})
+}
+
+func (f *StoreGetVulnerabilityMatchesSummaryCountFunc) nextHook() func(context.Context) (shared.GetVulnerabilityMatchesSummaryCounts, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *StoreGetVulnerabilityMatchesSummaryCountFunc) appendCall(r0 StoreGetVulnerabilityMatchesSummaryCountFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of
+// StoreGetVulnerabilityMatchesSummaryCountFuncCall objects describing the
+// invocations of this function.
+func (f *StoreGetVulnerabilityMatchesSummaryCountFunc) History() []StoreGetVulnerabilityMatchesSummaryCountFuncCall {
+	f.mutex.Lock()
+	history := make([]StoreGetVulnerabilityMatchesSummaryCountFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// StoreGetVulnerabilityMatchesSummaryCountFuncCall is an object that
+// describes an invocation of method GetVulnerabilityMatchesSummaryCount on
+// an instance of MockStore.
+type StoreGetVulnerabilityMatchesSummaryCountFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 shared.GetVulnerabilityMatchesSummaryCounts
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c StoreGetVulnerabilityMatchesSummaryCountFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c StoreGetVulnerabilityMatchesSummaryCountFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1}
+}
+
+// StoreInsertVulnerabilitiesFunc describes the behavior when the
+// InsertVulnerabilities method of the parent MockStore instance is invoked.
+type StoreInsertVulnerabilitiesFunc struct {
+	defaultHook func(context.Context, []shared.Vulnerability) (int, error)
+	hooks       []func(context.Context, []shared.Vulnerability) (int, error)
+	history     []StoreInsertVulnerabilitiesFuncCall
+	mutex       sync.Mutex
+}
+
+// InsertVulnerabilities delegates to the next hook function in the queue
+// and stores the parameter and result values of this invocation.
+func (m *MockStore) InsertVulnerabilities(v0 context.Context, v1 []shared.Vulnerability) (int, error) {
+	r0, r1 := m.InsertVulnerabilitiesFunc.nextHook()(v0, v1)
+	m.InsertVulnerabilitiesFunc.appendCall(StoreInsertVulnerabilitiesFuncCall{v0, v1, r0, r1})
+	return r0, r1
+}
+
+// SetDefaultHook sets function that is called when the
+// InsertVulnerabilities method of the parent MockStore instance is invoked
+// and the hook queue is empty.
+func (f *StoreInsertVulnerabilitiesFunc) SetDefaultHook(hook func(context.Context, []shared.Vulnerability) (int, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// InsertVulnerabilities method of the parent MockStore instance invokes the
+// hook at the front of the queue and discards it. After the queue is empty,
+// the default hook function is invoked for any future action.
+func (f *StoreInsertVulnerabilitiesFunc) PushHook(hook func(context.Context, []shared.Vulnerability) (int, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultHook with a function that returns the
+// given values.
+func (f *StoreInsertVulnerabilitiesFunc) SetDefaultReturn(r0 int, r1 error) {
+	f.SetDefaultHook(func(context.Context, []shared.Vulnerability) (int, error) {
+		return r0, r1
+	})
+}
+
+// PushReturn calls PushHook with a function that returns the given values.
+func (f *StoreInsertVulnerabilitiesFunc) PushReturn(r0 int, r1 error) {
+	f.PushHook(func(context.Context, []shared.Vulnerability) (int, error) {
+		return r0, r1
+	})
+}
+
+func (f *StoreInsertVulnerabilitiesFunc) nextHook() func(context.Context, []shared.Vulnerability) (int, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *StoreInsertVulnerabilitiesFunc) appendCall(r0 StoreInsertVulnerabilitiesFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of StoreInsertVulnerabilitiesFuncCall objects
+// describing the invocations of this function.
+func (f *StoreInsertVulnerabilitiesFunc) History() []StoreInsertVulnerabilitiesFuncCall {
+	f.mutex.Lock()
+	history := make([]StoreInsertVulnerabilitiesFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// StoreInsertVulnerabilitiesFuncCall is an object that describes an
+// invocation of method InsertVulnerabilities on an instance of MockStore.
+type StoreInsertVulnerabilitiesFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 []shared.Vulnerability
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 int
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c StoreInsertVulnerabilitiesFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c StoreInsertVulnerabilitiesFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1}
+}
+
+// StoreMarkVulnerabilityMatchCallSitesFailedFunc describes the behavior
+// when the MarkVulnerabilityMatchCallSitesFailed method of the parent
+// MockStore instance is invoked.
+type StoreMarkVulnerabilityMatchCallSitesFailedFunc struct {
+	defaultHook func(context.Context, int) error
+	hooks       []func(context.Context, int) error
+	history     []StoreMarkVulnerabilityMatchCallSitesFailedFuncCall
+	mutex       sync.Mutex
+}
+
+// MarkVulnerabilityMatchCallSitesFailed delegates to the next hook function
+// in the queue and stores the parameter and result values of this
+// invocation.
+func (m *MockStore) MarkVulnerabilityMatchCallSitesFailed(v0 context.Context, v1 int) error {
+	r0 := m.MarkVulnerabilityMatchCallSitesFailedFunc.nextHook()(v0, v1)
+	m.MarkVulnerabilityMatchCallSitesFailedFunc.appendCall(StoreMarkVulnerabilityMatchCallSitesFailedFuncCall{v0, v1, r0})
+	return r0
+}
+
+// SetDefaultHook sets function that is called when the
+// MarkVulnerabilityMatchCallSitesFailed method of the parent MockStore
+// instance is invoked and the hook queue is empty.
+func (f *StoreMarkVulnerabilityMatchCallSitesFailedFunc) SetDefaultHook(hook func(context.Context, int) error) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// MarkVulnerabilityMatchCallSitesFailed method of the parent MockStore
+// instance invokes the hook at the front of the queue and discards it.
+// After the queue is empty, the default hook function is invoked for any
+// future action.
+func (f *StoreMarkVulnerabilityMatchCallSitesFailedFunc) PushHook(hook func(context.Context, int) error) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultHook with a function that returns the
+// given values.
+func (f *StoreMarkVulnerabilityMatchCallSitesFailedFunc) SetDefaultReturn(r0 error) {
+	f.SetDefaultHook(func(context.Context, int) error {
+		return r0
+	})
+}
+
+// PushReturn calls PushHook with a function that returns the given values.
+func (f *StoreMarkVulnerabilityMatchCallSitesFailedFunc) PushReturn(r0 error) {
+	f.PushHook(func(context.Context, int) error {
+		return r0
+	})
+}
+
+func (f *StoreMarkVulnerabilityMatchCallSitesFailedFunc) nextHook() func(context.Context, int) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *StoreMarkVulnerabilityMatchCallSitesFailedFunc) appendCall(r0 StoreMarkVulnerabilityMatchCallSitesFailedFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of
+// StoreMarkVulnerabilityMatchCallSitesFailedFuncCall objects describing the
+// invocations of this function.
+func (f *StoreMarkVulnerabilityMatchCallSitesFailedFunc) History() []StoreMarkVulnerabilityMatchCallSitesFailedFuncCall {
+	f.mutex.Lock()
+	history := make([]StoreMarkVulnerabilityMatchCallSitesFailedFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// StoreMarkVulnerabilityMatchCallSitesFailedFuncCall is an object that
+// describes an invocation of method MarkVulnerabilityMatchCallSitesFailed
+// on an instance of MockStore.
+type StoreMarkVulnerabilityMatchCallSitesFailedFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 int
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c StoreMarkVulnerabilityMatchCallSitesFailedFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c StoreMarkVulnerabilityMatchCallSitesFailedFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0}
+}
+
+// StoreScanMatchesFunc describes the behavior when the ScanMatches method
+// of the parent MockStore instance is invoked.
+type StoreScanMatchesFunc struct {
+	defaultHook func(context.Context, int) (int, int, error)
+	hooks       []func(context.Context, int) (int, int, error)
+	history     []StoreScanMatchesFuncCall
+	mutex       sync.Mutex
+}
+
+// ScanMatches delegates to the next hook function in the queue and stores
+// the parameter and result values of this invocation.
+func (m *MockStore) ScanMatches(v0 context.Context, v1 int) (int, int, error) {
+	r0, r1, r2 := m.ScanMatchesFunc.nextHook()(v0, v1)
+	m.ScanMatchesFunc.appendCall(StoreScanMatchesFuncCall{v0, v1, r0, r1, r2})
+	return r0, r1, r2
+}
+
+// SetDefaultHook sets function that is called when the ScanMatches method
+// of the parent MockStore instance is invoked and the hook queue is empty.
+func (f *StoreScanMatchesFunc) SetDefaultHook(hook func(context.Context, int) (int, int, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// ScanMatches method of the parent MockStore instance invokes the hook at
+// the front of the queue and discards it. After the queue is empty, the
+// default hook function is invoked for any future action.
+func (f *StoreScanMatchesFunc) PushHook(hook func(context.Context, int) (int, int, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultHook with a function that returns the
+// given values.
+func (f *StoreScanMatchesFunc) SetDefaultReturn(r0 int, r1 int, r2 error) {
+	f.SetDefaultHook(func(context.Context, int) (int, int, error) {
+		return r0, r1, r2
+	})
+}
+
+// PushReturn calls PushHook with a function that returns the given values.
+func (f *StoreScanMatchesFunc) PushReturn(r0 int, r1 int, r2 error) {
+	f.PushHook(func(context.Context, int) (int, int, error) {
+		return r0, r1, r2
+	})
+}
+
+func (f *StoreScanMatchesFunc) nextHook() func(context.Context, int) (int, int, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *StoreScanMatchesFunc) appendCall(r0 StoreScanMatchesFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of StoreScanMatchesFuncCall objects describing
+// the invocations of this function.
+func (f *StoreScanMatchesFunc) History() []StoreScanMatchesFuncCall {
+	f.mutex.Lock()
+	history := make([]StoreScanMatchesFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// StoreScanMatchesFuncCall is an object that describes an invocation of
+// method ScanMatches on an instance of MockStore.
+type StoreScanMatchesFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 int
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 int
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 int
+	// Result2 is the value of the 3rd result returned from this method
+	// invocation.
+	Result2 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c StoreScanMatchesFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c StoreScanMatchesFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1, c.Result2}
+}
+
+// StoreUpdateVulnerabilityMatchCallSitesFunc describes the behavior when
+// the UpdateVulnerabilityMatchCallSites method of the parent MockStore
+// instance is invoked.
+type StoreUpdateVulnerabilityMatchCallSitesFunc struct {
+	defaultHook func(context.Context, int, []shared.SymbolReference) error
+	hooks       []func(context.Context, int, []shared.SymbolReference) error
+	history     []StoreUpdateVulnerabilityMatchCallSitesFuncCall
+	mutex       sync.Mutex
+}
+
+// UpdateVulnerabilityMatchCallSites delegates to the next hook function in
+// the queue and stores the parameter and result values of this invocation.
+func (m *MockStore) UpdateVulnerabilityMatchCallSites(v0 context.Context, v1 int, v2 []shared.SymbolReference) error {
+	r0 := m.UpdateVulnerabilityMatchCallSitesFunc.nextHook()(v0, v1, v2)
+	m.UpdateVulnerabilityMatchCallSitesFunc.appendCall(StoreUpdateVulnerabilityMatchCallSitesFuncCall{v0, v1, v2, r0})
+	return r0
+}
+
+// SetDefaultHook sets function that is called when the
+// UpdateVulnerabilityMatchCallSites method of the parent MockStore instance
+// is invoked and the hook queue is empty.
+func (f *StoreUpdateVulnerabilityMatchCallSitesFunc) SetDefaultHook(hook func(context.Context, int, []shared.SymbolReference) error) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// UpdateVulnerabilityMatchCallSites method of the parent MockStore instance
+// invokes the hook at the front of the queue and discards it. After the
+// queue is empty, the default hook function is invoked for any future
+// action.
+func (f *StoreUpdateVulnerabilityMatchCallSitesFunc) PushHook(hook func(context.Context, int, []shared.SymbolReference) error) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultHook with a function that returns the
+// given values.
+func (f *StoreUpdateVulnerabilityMatchCallSitesFunc) SetDefaultReturn(r0 error) {
+	f.SetDefaultHook(func(context.Context, int, []shared.SymbolReference) error {
+		return r0
+	})
+}
+
+// PushReturn calls PushHook with a function that returns the given values.
+func (f *StoreUpdateVulnerabilityMatchCallSitesFunc) PushReturn(r0 error) {
+	f.PushHook(func(context.Context, int, []shared.SymbolReference) error {
+		return r0
+	})
+}
+
+func (f *StoreUpdateVulnerabilityMatchCallSitesFunc) nextHook() func(context.Context, int, []shared.SymbolReference) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *StoreUpdateVulnerabilityMatchCallSitesFunc) appendCall(r0 StoreUpdateVulnerabilityMatchCallSitesFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of
+// StoreUpdateVulnerabilityMatchCallSitesFuncCall objects describing the
+// invocations of this function.
+func (f *StoreUpdateVulnerabilityMatchCallSitesFunc) History() []StoreUpdateVulnerabilityMatchCallSitesFuncCall {
+	f.mutex.Lock()
+	history := make([]StoreUpdateVulnerabilityMatchCallSitesFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// StoreUpdateVulnerabilityMatchCallSitesFuncCall is an object that
+// describes an invocation of method UpdateVulnerabilityMatchCallSites on an
+// instance of MockStore.
+type StoreUpdateVulnerabilityMatchCallSitesFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 int
+	// Arg2 is the value of the 3rd argument passed to this method
+	// invocation.
+	Arg2 []shared.SymbolReference
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c StoreUpdateVulnerabilityMatchCallSitesFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1, c.Arg2}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c StoreUpdateVulnerabilityMatchCallSitesFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0}
+}
+
+// StoreVulnerabilityByIDFunc describes the behavior when the
+// VulnerabilityByID method of the parent MockStore instance is invoked.
+type StoreVulnerabilityByIDFunc struct {
+	defaultHook func(context.Context, int) (shared.Vulnerability, bool, error)
+	hooks       []func(context.Context, int) (shared.Vulnerability, bool, error)
+	history     []StoreVulnerabilityByIDFuncCall
+	mutex       sync.Mutex
+}
+
+// VulnerabilityByID delegates to the next hook function in the queue and
+// stores the parameter and result values of this invocation.
+func (m *MockStore) VulnerabilityByID(v0 context.Context, v1 int) (shared.Vulnerability, bool, error) {
+	r0, r1, r2 := m.VulnerabilityByIDFunc.nextHook()(v0, v1)
+	m.VulnerabilityByIDFunc.appendCall(StoreVulnerabilityByIDFuncCall{v0, v1, r0, r1, r2})
+	return r0, r1, r2
+}
+
+// SetDefaultHook sets function that is called when the VulnerabilityByID
+// method of the parent MockStore instance is invoked and the hook queue is
+// empty.
+func (f *StoreVulnerabilityByIDFunc) SetDefaultHook(hook func(context.Context, int) (shared.Vulnerability, bool, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// VulnerabilityByID method of the parent MockStore instance invokes the
+// hook at the front of the queue and discards it. After the queue is empty,
+// the default hook function is invoked for any future action.
+func (f *StoreVulnerabilityByIDFunc) PushHook(hook func(context.Context, int) (shared.Vulnerability, bool, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultHook with a function that returns the
+// given values.
+func (f *StoreVulnerabilityByIDFunc) SetDefaultReturn(r0 shared.Vulnerability, r1 bool, r2 error) {
+	f.SetDefaultHook(func(context.Context, int) (shared.Vulnerability, bool, error) {
+		return r0, r1, r2
+	})
+}
+
+// PushReturn calls PushHook with a function that returns the given values.
+func (f *StoreVulnerabilityByIDFunc) PushReturn(r0 shared.Vulnerability, r1 bool, r2 error) {
+	f.PushHook(func(context.Context, int) (shared.Vulnerability, bool, error) {
+		return r0, r1, r2
+	})
+}
+
+func (f *StoreVulnerabilityByIDFunc) nextHook() func(context.Context, int) (shared.Vulnerability, bool, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *StoreVulnerabilityByIDFunc) appendCall(r0 StoreVulnerabilityByIDFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of StoreVulnerabilityByIDFuncCall objects
+// describing the invocations of this function.
+func (f *StoreVulnerabilityByIDFunc) History() []StoreVulnerabilityByIDFuncCall {
+	f.mutex.Lock()
+	history := make([]StoreVulnerabilityByIDFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// StoreVulnerabilityByIDFuncCall is an object that describes an invocation
+// of method VulnerabilityByID on an instance of MockStore.
+type StoreVulnerabilityByIDFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 int
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 shared.Vulnerability
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 bool
+	// Result2 is the value of the 3rd result returned from this method
+	// invocation.
+	Result2 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c StoreVulnerabilityByIDFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c StoreVulnerabilityByIDFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1, c.Result2}
+}
+
+// StoreVulnerabilityMatchByIDFunc describes the behavior when the
+// VulnerabilityMatchByID method of the parent MockStore instance is
+// invoked.
+type StoreVulnerabilityMatchByIDFunc struct {
+	defaultHook func(context.Context, int) (shared.VulnerabilityMatch, bool, error)
+	hooks       []func(context.Context, int) (shared.VulnerabilityMatch, bool, error)
+	history     []StoreVulnerabilityMatchByIDFuncCall
+	mutex       sync.Mutex
+}
+
+// VulnerabilityMatchByID delegates to the next hook function in the queue
+// and stores the parameter and result values of this invocation.
+func (m *MockStore) VulnerabilityMatchByID(v0 context.Context, v1 int) (shared.VulnerabilityMatch, bool, error) {
+	r0, r1, r2 := m.VulnerabilityMatchByIDFunc.nextHook()(v0, v1)
+	m.VulnerabilityMatchByIDFunc.appendCall(StoreVulnerabilityMatchByIDFuncCall{v0, v1, r0, r1, r2})
+	return r0, r1, r2
+}
+
+// SetDefaultHook sets function that is called when the
+// VulnerabilityMatchByID method of the parent MockStore instance is invoked
+// and the hook queue is empty.
+func (f *StoreVulnerabilityMatchByIDFunc) SetDefaultHook(hook func(context.Context, int) (shared.VulnerabilityMatch, bool, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// VulnerabilityMatchByID method of the parent MockStore instance invokes
+// the hook at the front of the queue and discards it. After the queue is
+// empty, the default hook function is invoked for any future action.
+func (f *StoreVulnerabilityMatchByIDFunc) PushHook(hook func(context.Context, int) (shared.VulnerabilityMatch, bool, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultHook with a function that returns the
+// given values.
+func (f *StoreVulnerabilityMatchByIDFunc) SetDefaultReturn(r0 shared.VulnerabilityMatch, r1 bool, r2 error) {
+	f.SetDefaultHook(func(context.Context, int) (shared.VulnerabilityMatch, bool, error) {
+		return r0, r1, r2
+	})
+}
+
+// PushReturn calls PushHook with a function that returns the given values.
+func (f *StoreVulnerabilityMatchByIDFunc) PushReturn(r0 shared.VulnerabilityMatch, r1 bool, r2 error) {
+	f.PushHook(func(context.Context, int) (shared.VulnerabilityMatch, bool, error) {
+		return r0, r1, r2
+	})
+}
+
+func (f *StoreVulnerabilityMatchByIDFunc) nextHook() func(context.Context, int) (shared.VulnerabilityMatch, bool, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *StoreVulnerabilityMatchByIDFunc) appendCall(r0 StoreVulnerabilityMatchByIDFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of StoreVulnerabilityMatchByIDFuncCall objects
+// describing the invocations of this function.
+func (f *StoreVulnerabilityMatchByIDFunc) History() []StoreVulnerabilityMatchByIDFuncCall {
+	f.mutex.Lock()
+	history := make([]StoreVulnerabilityMatchByIDFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// StoreVulnerabilityMatchByIDFuncCall is an object that describes an
+// invocation of method VulnerabilityMatchByID on an instance of MockStore.
+type StoreVulnerabilityMatchByIDFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 int
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 shared.VulnerabilityMatch
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 bool
+	// Result2 is the value of the 3rd result returned from this method
+	// invocation.
+	Result2 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c StoreVulnerabilityMatchByIDFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c StoreVulnerabilityMatchByIDFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1, c.Result2}
+}
+
+// MockLSIFStore is a mock implementation of the Store interface (from the
+// package
+// github.com/sourcegraph/sourcegraph/internal/codeintel/sentinel/internal/lsifstore)
+// used for unit testing.
+type MockLSIFStore struct {
+	// GetSymbolReferencesByPrefixFunc is an instance of a mock function
+	// object controlling the behavior of the method
+	// GetSymbolReferencesByPrefix.
+	GetSymbolReferencesByPrefixFunc *LSIFStoreGetSymbolReferencesByPrefixFunc
+}
+
+// NewMockLSIFStore creates a new mock of the Store interface. All methods
+// return zero values for all results, unless overwritten.
+func NewMockLSIFStore() *MockLSIFStore {
+	return &MockLSIFStore{
+		GetSymbolReferencesByPrefixFunc: &LSIFStoreGetSymbolReferencesByPrefixFunc{
+			defaultHook: func(context.Context, int, string) (r0 []shared.SymbolReference, r1 error) {
+				return
+			},
+		},
+	}
+}
+
+// NewStrictMockLSIFStore creates a new mock of the Store interface. All
+// methods panic on invocation, unless overwritten.
+func NewStrictMockLSIFStore() *MockLSIFStore {
+	return &MockLSIFStore{
+		GetSymbolReferencesByPrefixFunc: &LSIFStoreGetSymbolReferencesByPrefixFunc{
+			defaultHook: func(context.Context, int, string) ([]shared.SymbolReference, error) {
+				panic("unexpected invocation of MockLSIFStore.GetSymbolReferencesByPrefix")
+			},
+		},
+	}
+}
+
+// NewMockLSIFStoreFrom creates a new mock of the MockLSIFStore interface.
+// All methods delegate to the given implementation, unless overwritten.
+func NewMockLSIFStoreFrom(i lsifstore.Store) *MockLSIFStore {
+	return &MockLSIFStore{
+		GetSymbolReferencesByPrefixFunc: &LSIFStoreGetSymbolReferencesByPrefixFunc{
+			defaultHook: i.GetSymbolReferencesByPrefix,
+		},
+	}
+}
+
+// LSIFStoreGetSymbolReferencesByPrefixFunc describes the behavior when the
+// GetSymbolReferencesByPrefix method of the parent MockLSIFStore instance
+// is invoked.
+type LSIFStoreGetSymbolReferencesByPrefixFunc struct {
+	defaultHook func(context.Context, int, string) ([]shared.SymbolReference, error)
+	hooks       []func(context.Context, int, string) ([]shared.SymbolReference, error)
+	history     []LSIFStoreGetSymbolReferencesByPrefixFuncCall
+	mutex       sync.Mutex
+}
+
+// GetSymbolReferencesByPrefix delegates to the next hook function in the
+// queue and stores the parameter and result values of this invocation.
+func (m *MockLSIFStore) GetSymbolReferencesByPrefix(v0 context.Context, v1 int, v2 string) ([]shared.SymbolReference, error) {
+	r0, r1 := m.GetSymbolReferencesByPrefixFunc.nextHook()(v0, v1, v2)
+	m.GetSymbolReferencesByPrefixFunc.appendCall(LSIFStoreGetSymbolReferencesByPrefixFuncCall{v0, v1, v2, r0, r1})
+	return r0, r1
+}
+
+// SetDefaultHook sets function that is called when the
+// GetSymbolReferencesByPrefix method of the parent MockLSIFStore instance
+// is invoked and the hook queue is empty.
+func (f *LSIFStoreGetSymbolReferencesByPrefixFunc) SetDefaultHook(hook func(context.Context, int, string) ([]shared.SymbolReference, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// GetSymbolReferencesByPrefix method of the parent MockLSIFStore instance
+// invokes the hook at the front of the queue and discards it. After the
+// queue is empty, the default hook function is invoked for any future
+// action.
+func (f *LSIFStoreGetSymbolReferencesByPrefixFunc) PushHook(hook func(context.Context, int, string) ([]shared.SymbolReference, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultHook with a function that returns the
+// given values.
+func (f *LSIFStoreGetSymbolReferencesByPrefixFunc) SetDefaultReturn(r0 []shared.SymbolReference, r1 error) {
+	f.SetDefaultHook(func(context.Context, int, string) ([]shared.SymbolReference, error) {
+		return r0, r1
+	})
+}
+
+// PushReturn calls PushHook with a function that returns the given values.
+func (f *LSIFStoreGetSymbolReferencesByPrefixFunc) PushReturn(r0 []shared.SymbolReference, r1 error) {
+	f.PushHook(func(context.Context, int, string) ([]shared.SymbolReference, error) {
+		return r0, r1
+	})
+}
+
+func (f *LSIFStoreGetSymbolReferencesByPrefixFunc) nextHook() func(context.Context, int, string) ([]shared.SymbolReference, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *LSIFStoreGetSymbolReferencesByPrefixFunc) appendCall(r0 LSIFStoreGetSymbolReferencesByPrefixFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of
+// LSIFStoreGetSymbolReferencesByPrefixFuncCall objects describing the
+// invocations of this function.
+func (f *LSIFStoreGetSymbolReferencesByPrefixFunc) History() []LSIFStoreGetSymbolReferencesByPrefixFuncCall {
+	f.mutex.Lock()
+	history := make([]LSIFStoreGetSymbolReferencesByPrefixFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// LSIFStoreGetSymbolReferencesByPrefixFuncCall is an object that describes
+// an invocation of method GetSymbolReferencesByPrefix on an instance of
+// MockLSIFStore.
+type LSIFStoreGetSymbolReferencesByPrefixFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 int
+	// Arg2 is the value of the 3rd argument passed to this method
+	// invocation.
+	Arg2 string
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 []shared.SymbolReference
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c LSIFStoreGetSymbolReferencesByPrefixFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1, c.Arg2}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c LSIFStoreGetSymbolReferencesByPrefixFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1}
+}
@@ -0,0 +1,120 @@
+package lsifstore
+
+import (
+	"context"
+
+	"github.com/keegancsmith/sqlf"
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/sourcegraph/sourcegraph/internal/codeintel/sentinel/shared"
+	"github.com/sourcegraph/sourcegraph/internal/codeintel/shared/ranges"
+	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+)
+
+// GetSymbolReferencesByPrefix returns the references within the given upload to all symbols whose name
+// begins with the given prefix. Definitions and other occurrences of the symbols are not returned.
+func (s *store) GetSymbolReferencesByPrefix(ctx context.Context, uploadID int, symbolPrefix string) (_ []shared.SymbolReference, err error) {
+	ctx, trace, endObservation := s.operations.getSymbolReferencesByPrefix.With(ctx, &err, observation.Args{Attrs: []attribute.KeyValue{
+		attribute.Int("uploadID", uploadID),
+		attribute.String("symbolPrefix", symbolPrefix),
+	}})
+	defer endObservation(1, observation.Args{})
+
+	references, err := scanSymbolReferences(s.db.Query(ctx, sqlf.Sprintf(
+		getSymbolReferencesByPrefixQuery,
+		symbolPrefix,
+		uploadID,
+		symbolPrefix,
+		symbolPrefix,
+		uploadID,
+	)))
+	if err != nil {
+		return nil, err
+	}
+	trace.AddEvent("TODO Domain Owner", attribute.Int("numReferences", len(references)))
+
+	return references, nil
+}
+
+const getSymbolReferencesByPrefixQuery = `
+WITH RECURSIVE
+-- Search for the trie paths of the upload's symbol names that are consistent with the
+-- given prefix. Once the prefix has been consumed (the search field becomes empty), all
+-- descendant paths name symbols beginning with the prefix.
+matching_prefixes(id, prefix, search) AS (
+	(
+		SELECT
+			ssn.id,
+			ssn.name_segment,
+			substring(%s from length(ssn.name_segment) + 1) AS search
+		FROM codeintel_scip_symbol_names ssn
+		WHERE
+			ssn.upload_id = %s AND
+			ssn.prefix_id IS NULL AND
+			(starts_with(%s, ssn.name_segment) OR starts_with(ssn.name_segment, %s))
+	) UNION (
+		SELECT
+			ssn.id,
+			mp.prefix || ssn.name_segment,
+			substring(mp.search from length(ssn.name_segment) + 1) AS search
+		FROM matching_prefixes mp
+		JOIN codeintel_scip_symbol_names ssn ON
+			ssn.upload_id = %s AND
+			ssn.prefix_id = mp.id
+		WHERE
+			mp.search = '' OR
+			starts_with(mp.search, ssn.name_segment) OR
+			starts_with(ssn.name_segment, mp.search)
+	)
+)
+SELECT
+	mp.prefix,
+	dl.document_path,
+	ss.reference_ranges
+FROM matching_prefixes mp
+JOIN codeintel_scip_symbols ss ON ss.symbol_id = mp.id
+JOIN codeintel_scip_document_lookup dl ON dl.id = ss.document_lookup_id
+WHERE
+	mp.search = '' AND
+	ss.upload_id = dl.upload_id AND
+	ss.reference_ranges IS NOT NULL
+ORDER BY dl.document_path, mp.prefix
+`
+
+func scanSymbolReferences(rows basestore.Rows, queryErr error) (_ []shared.SymbolReference, err error) {
+	if queryErr != nil {
+		return nil, queryErr
+	}
+	defer func() { err = basestore.CloseRows(rows, err) }()
+
+	var references []shared.SymbolReference
+	for rows.Next() {
+		var (
+			symbol      string
+			path        string
+			scipPayload []byte
+		)
+		if err := rows.Scan(&symbol, &path, &scipPayload); err != nil {
+			return nil, err
+		}
+
+		decoded, err := ranges.DecodeRanges(scipPayload)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, r := range decoded {
+			references = append(references, shared.SymbolReference{
+				Symbol:         symbol,
+				Path:           path,
+				StartLine:      int(r.Start.Line),
+				StartCharacter: int(r.Start.Character),
+				EndLine:        int(r.End.Line),
+				EndCharacter:   int(r.End.Character),
+			})
+		}
+	}
+
+	return references, nil
+}
@@ -0,0 +1,26 @@
+package lsifstore
+
+import (
+	"context"
+
+	"github.com/sourcegraph/sourcegraph/internal/codeintel/sentinel/shared"
+	codeintelshared "github.com/sourcegraph/sourcegraph/internal/codeintel/shared"
+	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+)
+
+type Store interface {
+	GetSymbolReferencesByPrefix(ctx context.Context, uploadID int, symbolPrefix string) ([]shared.SymbolReference, error)
+}
+
+type store struct {
+	db         *basestore.Store
+	operations *operations
+}
+
+func New(observationCtx *observation.Context, db codeintelshared.CodeIntelDB) Store {
+	return &store{
+		db:         basestore.NewWithHandle(db.Handle()),
+		operations: newOperations(observationCtx),
+	}
+}
@@ -0,0 +1,332 @@
+package store
+
+import (
+	"context"
+	"strings"
+
+	"github.com/keegancsmith/sqlf"
+	"github.com/lib/pq"
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/sourcegraph/sourcegraph/internal/codeintel/sentinel/shared"
+	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
+	"github.com/sourcegraph/sourcegraph/internal/database/batch"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+)
+
+// GetUnresolvedVulnerabilityMatches returns a batch of vulnerability matches whose call sites have not
+// yet been resolved, along with the packages referenced by the matching index that are affected by the
+// vulnerability. Matches whose resolution failed are skipped until their retry time has passed, see
+// MarkVulnerabilityMatchCallSitesFailed.
+func (s *store) GetUnresolvedVulnerabilityMatches(ctx context.Context, limit int) (_ []shared.UnresolvedVulnerabilityMatch, err error) {
+	ctx, _, endObservation := s.operations.getUnresolvedVulnerabilityMatches.With(ctx, &err, observation.Args{Attrs: []attribute.KeyValue{
+		attribute.Int("limit", limit),
+	}})
+	defer endObservation(1, observation.Args{})
+
+	matches, err := scanUnresolvedVulnerabilityMatches(s.db.Query(ctx, sqlf.Sprintf(getUnresolvedVulnerabilityMatchesQuery, limit)))
+	if err != nil || len(matches) == 0 {
+		return nil, err
+	}
+
+	uploadIDs := make([]int, 0, len(matches))
+	patterns := make([]string, 0, len(matches))
+	for _, match := range matches {
+		uploadIDs = append(uploadIDs, match.UploadID)
+		patterns = append(patterns, "%"+coarsePackageName(match.AffectedPackage.PackageName)+"%")
+	}
+
+	references, err := scanPackageReferences(s.db.Query(ctx, sqlf.Sprintf(getCandidatePackageReferencesQuery, pq.Array(uploadIDs), pq.Array(patterns))))
+	if err != nil {
+		return nil, err
+	}
+
+	for i, match := range matches {
+		for _, reference := range references[match.UploadID] {
+			ecosystem := ecosystemForReference(reference.Scheme, reference.Manager)
+			if ecosystem == nil || !ecosystem.matchesAffectedPackage(reference.Name, match.AffectedPackage.Language, match.AffectedPackage.PackageName) {
+				continue
+			}
+			if ok, _ := ecosystem.versionMatchesConstraints(reference.Version, match.AffectedPackage.VersionConstraint); !ok {
+				continue
+			}
+
+			matches[i].Packages = append(matches[i].Packages, reference)
+		}
+	}
+
+	return matches, nil
+}
+
+const getUnresolvedVulnerabilityMatchesQuery = `
+WITH candidates AS (
+	SELECT
+		m.id,
+		m.upload_id,
+		m.vulnerability_affected_package_id
+	FROM vulnerability_matches m
+	WHERE
+		m.reachable IS NULL AND
+		(m.call_sites_retry_after IS NULL OR m.call_sites_retry_after <= NOW())
+	ORDER BY m.id
+	LIMIT %s
+)
+SELECT
+	c.id,
+	c.upload_id,
+	` + vulnerabilityAffectedPackageFields + `,
+	` + vulnerabilityAffectedSymbolFields + `
+FROM candidates c
+JOIN vulnerability_affected_packages vap ON vap.id = c.vulnerability_affected_package_id
+LEFT JOIN vulnerability_affected_symbols vas ON vas.vulnerability_affected_package_id = vap.id
+ORDER BY c.id, vas.id
+`
+
+const getCandidatePackageReferencesQuery = `
+SELECT
+	r.dump_id,
+	r.scheme,
+	r.manager,
+	r.name,
+	r.version
+FROM lsif_references r
+WHERE
+	r.dump_id = ANY(%s) AND
+	-- See scanMatchesQuery
+	lower(translate(r.name, '_./:', '----')) LIKE ANY(%s)
+ORDER BY r.dump_id, r.id
+`
+
+// UpdateVulnerabilityMatchCallSites replaces the call sites of the given vulnerability match and marks
+// the match as reachable if there is at least one call site.
+func (s *store) UpdateVulnerabilityMatchCallSites(ctx context.Context, matchID int, callSites []shared.SymbolReference) (err error) {
+	ctx, _, endObservation := s.operations.updateVulnerabilityMatchCallSites.With(ctx, &err, observation.Args{Attrs: []attribute.KeyValue{
+		attribute.Int("matchID", matchID),
+		attribute.Int("numCallSites", len(callSites)),
+	}})
+	defer endObservation(1, observation.Args{})
+
+	return s.db.WithTransact(ctx, func(tx *basestore.Store) error {
+		if err := tx.Exec(ctx, sqlf.Sprintf(deleteVulnerabilityMatchCallSitesQuery, matchID)); err != nil {
+			return err
+		}
+
+		if err := batch.WithInserter(
+			ctx,
+			tx.Handle(),
+			"vulnerability_match_call_sites",
+			batch.MaxNumPostgresParameters,
+			[]string{
+				"vulnerability_match_id",
+				"symbol",
+				"path",
+				"start_line",
+				"start_character",
+				"end_line",
+				"end_character",
+			},
+			func(inserter *batch.Inserter) error {
+				for _, callSite := range callSites {
+					if err := inserter.Insert(
+						ctx,
+						matchID,
+						callSite.Symbol,
+						callSite.Path,
+						callSite.StartLine,
+						callSite.StartCharacter,
+						callSite.EndLine,
+						callSite.EndCharacter,
+					); err != nil {
+						return err
+					}
+				}
+
+				return nil
+			},
+		); err != nil {
+			return err
+		}
+
+		return tx.Exec(ctx, sqlf.Sprintf(updateVulnerabilityMatchReachableQuery, len(callSites) > 0, matchID))
+	})
+}
+
+const deleteVulnerabilityMatchCallSitesQuery = `
+DELETE FROM vulnerability_match_call_sites WHERE vulnerability_match_id = %s
+`
+
+const updateVulnerabilityMatchReachableQuery = `
+UPDATE vulnerability_matches
+SET
+	reachable = %s,
+	call_sites_failures = 0,
+	call_sites_retry_after = NULL
+WHERE id = %s
+`
+
+// MarkVulnerabilityMatchCallSitesFailed records a failure to resolve the call sites of the given
+// vulnerability match. The match is not returned by GetUnresolvedVulnerabilityMatches until its retry
+// time, which backs off exponentially with the number of consecutive failures, has passed.
+func (s *store) MarkVulnerabilityMatchCallSitesFailed(ctx context.Context, matchID int) (err error) {
+	ctx, _, endObservation := s.operations.markVulnerabilityMatchCallSitesFailed.With(ctx, &err, observation.Args{Attrs: []attribute.KeyValue{
+		attribute.Int("matchID", matchID),
+	}})
+	defer endObservation(1, observation.Args{})
+
+	return s.db.Exec(ctx, sqlf.Sprintf(markVulnerabilityMatchCallSitesFailedQuery, matchID))
+}
+
+const markVulnerabilityMatchCallSitesFailedQuery = `
+UPDATE vulnerability_matches
+SET
+	call_sites_failures = call_sites_failures + 1,
+	-- Back off exponentially, starting at one minute and capped at one day.
+	call_sites_retry_after = NOW() + LEAST(
+		interval '1 minute' * power(2, LEAST(call_sites_failures, 20)),
+		interval '1 day'
+	)
+WHERE id = %s
+`
+
+// attachCallSites populates the call sites of the given vulnerability matches.
+func (s *store) attachCallSites(ctx context.Context, matches []shared.VulnerabilityMatch) error {
+	if len(matches) == 0 {
+		return nil
+	}
+
+	ids := make([]int, 0, len(matches))
+	for _, match := range matches {
+		ids = append(ids, match.ID)
+	}
+
+	callSites, err := scanCallSites(s.db.Query(ctx, sqlf.Sprintf(getVulnerabilityMatchCallSitesQuery, pq.Array(ids))))
+	if err != nil {
+		return err
+	}
+
+	for i, match := range matches {
+		matches[i].CallSites = callSites[match.ID]
+	}
+
+	return nil
+}
+
+const getVulnerabilityMatchCallSitesQuery = `
+SELECT
+	cs.vulnerability_match_id,
+	cs.symbol,
+	cs.path,
+	cs.start_line,
+	cs.start_character,
+	cs.end_line,
+	cs.end_character
+FROM vulnerability_match_call_sites cs
+WHERE cs.vulnerability_match_id = ANY(%s)
+ORDER BY cs.vulnerability_match_id, cs.path, cs.start_line, cs.start_character
+`
+
+//
+//
+
+func scanUnresolvedVulnerabilityMatches(rows basestore.Rows, queryErr error) (matches []shared.UnresolvedVulnerabilityMatch, err error) {
+	if queryErr != nil {
+		return nil, queryErr
+	}
+	defer func() { err = basestore.CloseRows(rows, err) }()
+
+	for rows.Next() {
+		var (
+			match   shared.UnresolvedVulnerabilityMatch
+			vas     shared.AffectedSymbol
+			fixedIn string
+		)
+
+		if err := rows.Scan(
+			&match.ID,
+			&match.UploadID,
+			&match.AffectedPackage.PackageName,
+			&match.AffectedPackage.Language,
+			&match.AffectedPackage.Namespace,
+			pq.Array(&match.AffectedPackage.VersionConstraint),
+			&match.AffectedPackage.Fixed,
+			&dbutil.NullString{S: &fixedIn},
+			// RHS of left join (may be null)
+			&dbutil.NullString{S: &vas.Path},
+			pq.Array(&vas.Symbols),
+		); err != nil {
+			return nil, err
+		}
+
+		if n := len(matches) - 1; n < 0 || matches[n].ID != match.ID {
+			if fixedIn != "" {
+				match.AffectedPackage.FixedIn = &fixedIn
+			}
+			matches = append(matches, match)
+		}
+		if vas.Path != "" || len(vas.Symbols) > 0 {
+			n := len(matches) - 1
+			matches[n].AffectedPackage.AffectedSymbols = append(matches[n].AffectedPackage.AffectedSymbols, vas)
+		}
+	}
+
+	return matches, nil
+}
+
+func scanPackageReferences(rows basestore.Rows, queryErr error) (_ map[int][]shared.PackageReference, err error) {
+	if queryErr != nil {
+		return nil, queryErr
+	}
+	defer func() { err = basestore.CloseRows(rows, err) }()
+
+	references := map[int][]shared.PackageReference{}
+	for rows.Next() {
+		var (
+			uploadID  int
+			reference shared.PackageReference
+		)
+		if err := rows.Scan(&uploadID, &reference.Scheme, &reference.Manager, &reference.Name, &dbutil.NullString{S: &reference.Version}); err != nil {
+			return nil, err
+		}
+
+		references[uploadID] = append(references[uploadID], reference)
+	}
+
+	return references, nil
+}
+
+func scanCallSites(rows basestore.Rows, queryErr error) (_ map[int][]shared.SymbolReference, err error) {
+	if queryErr != nil {
+		return nil, queryErr
+	}
+	defer func() { err = basestore.CloseRows(rows, err) }()
+
+	callSites := map[int][]shared.SymbolReference{}
+	for rows.Next() {
+		var (
+			matchID  int
+			callSite shared.SymbolReference
+		)
+		if err := rows.Scan(
+			&matchID,
+			&callSite.Symbol,
+			&callSite.Path,
+			&callSite.StartLine,
+			&callSite.StartCharacter,
+			&callSite.EndLine,
+			&callSite.EndCharacter,
+		); err != nil {
+			return nil, err
+		}
+
+		callSites[matchID] = append(callSites[matchID], callSite)
+	}
+
+	return callSites, nil
+}
+
+// coarsePackageName mirrors the normalization applied to package names in the coarse filter of
+// scanMatchesQuery (see ecosystem.matchesAffectedPackage for the exact comparison).
+func coarsePackageName(name string) string {
+	return strings.ToLower(strings.NewReplacer("_", "-", ".", "-", "/", "-", ":", "-").Replace(name))
+}
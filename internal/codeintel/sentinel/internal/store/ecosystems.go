@@ -0,0 +1,257 @@
+package store
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/keegancsmith/sqlf"
+	"github.com/lib/pq"
+)
+
+// ecosystem maps the package references of an upload (rows of lsif_references) onto the affected
+// packages of vulnerabilities (rows of vulnerability_affected_packages) for a single package
+// ecosystem, and determines whether a referenced version falls within an affected version range.
+type ecosystem struct {
+	name string
+
+	// schemes and managers are the values of the scheme and manager columns of lsif_references
+	// identifying a package of this ecosystem. References written by older indexers carry only
+	// a scheme, while SCIP uploads also carry the package manager.
+	schemes  []string
+	managers []string
+
+	// languages are the (lower-cased) values of the language column of an affected package of
+	// this ecosystem. Advisories either use the OSV ecosystem name or the language to which the
+	// GitHub advisory database ecosystem was mapped on ingestion.
+	languages []string
+
+	// normalizePackageName returns the canonical name of a package of this ecosystem. Both the
+	// referenced package name and the advisory's package name are normalized before comparison.
+	normalizePackageName func(name string) string
+
+	// matchesPackageName returns true if the (normalized) referenced package name refers to the
+	// (normalized) affected package name. Defaults to equality.
+	matchesPackageName func(referenceName, packageName string) bool
+
+	compareVersions versionComparer
+}
+
+var ecosystems = []ecosystem{
+	{
+		name:                 "Go",
+		schemes:              []string{"gomod", "scip-go", "go"},
+		managers:             []string{"gomod"},
+		languages:            []string{"go"},
+		normalizePackageName: strings.TrimSpace,
+		// Advisories may name a module without its host (e.g., `go-nacelle/config`), so we also
+		// accept a match on a trailing sequence of path segments.
+		matchesPackageName: func(referenceName, packageName string) bool {
+			return referenceName == packageName || strings.HasSuffix(referenceName, "/"+packageName)
+		},
+		compareVersions: compareSemanticVersions,
+	},
+	{
+		name:                 "npm",
+		schemes:              []string{"npm", "scip-typescript"},
+		managers:             []string{"npm"},
+		languages:            []string{"javascript", "npm"},
+		normalizePackageName: normalizeNPMPackageName,
+		compareVersions:      compareSemanticVersions,
+	},
+	{
+		name:                 "PyPI",
+		schemes:              []string{"python", "scip-python", "pip"},
+		managers:             []string{"python", "pip", "pypi"},
+		languages:            []string{"python", "pypi"},
+		normalizePackageName: normalizePythonPackageName,
+		compareVersions:      comparePEP440Versions,
+	},
+	{
+		name:                 "Maven",
+		schemes:              []string{"semanticdb", "maven", "scip-java"},
+		managers:             []string{"maven"},
+		languages:            []string{"java", "maven"},
+		normalizePackageName: normalizeMavenPackageName,
+		compareVersions:      compareMavenVersions,
+	},
+	{
+		name:                 "crates.io",
+		schemes:              []string{"rust-analyzer", "cargo"},
+		managers:             []string{"cargo"},
+		languages:            []string{"rust", "crates.io"},
+		normalizePackageName: normalizeCratePackageName,
+		compareVersions:      compareSemanticVersions,
+	},
+}
+
+// ecosystemForReference returns the ecosystem of a package reference with the given scheme and
+// manager, or nil if the package does not belong to a supported ecosystem. The manager takes
+// precedence over the scheme when both identify an ecosystem.
+func ecosystemForReference(scheme, manager string) *ecosystem {
+	for i, e := range ecosystems {
+		if manager != "" && contains(e.managers, manager) {
+			return &ecosystems[i]
+		}
+	}
+	for i, e := range ecosystems {
+		if contains(e.schemes, scheme) {
+			return &ecosystems[i]
+		}
+	}
+
+	return nil
+}
+
+// matchesAffectedPackage returns true if the referenced package is the given affected package.
+func (e *ecosystem) matchesAffectedPackage(referenceName, language, packageName string) bool {
+	if !contains(e.languages, strings.ToLower(language)) {
+		return false
+	}
+
+	referenceName = e.normalizePackageName(referenceName)
+	packageName = e.normalizePackageName(packageName)
+	if referenceName == "" || packageName == "" {
+		return false
+	}
+	if e.matchesPackageName != nil {
+		return e.matchesPackageName(referenceName, packageName)
+	}
+
+	return referenceName == packageName
+}
+
+// versionMatchesConstraints returns true if the given version satisfies the given constraints,
+// as stored on an affected package. Each constraint is a comparison operator followed by a version
+// (e.g., `>=1.2.0`); a single constraint string may also contain a comma-separated conjunction.
+//
+// Constraints are evaluated as a union of intervals in the order in which they appear: a lower
+// bound (`>` or `>=`) opens an interval which is closed by the following upper bound (`<` or `<=`),
+// which mirrors the introduced/fixed event pairs of OSV ranges. An upper bound without a preceding
+// lower bound is unbounded below (and vice versa). Exact constraints (`=`) each form a distinct
+// interval, and exclusions (`!=`) apply to all intervals.
+//
+// The second return value is false if the version or any of the constraints could not be parsed
+// under the ordering of the ecosystem.
+func (e *ecosystem) versionMatchesConstraints(version string, constraints []string) (matches, valid bool) {
+	var (
+		matched      bool
+		excluded     bool
+		pendingLower bool // a lower bound has been seen but not yet been closed by an upper bound
+		lowerOK      bool // the version satisfies the pending lower bound
+	)
+
+	for _, constraint := range splitConstraints(constraints) {
+		op, bound := parseConstraint(constraint)
+
+		cmp, err := e.compareVersions(version, bound)
+		if err != nil {
+			return false, false
+		}
+
+		switch op {
+		case ">", ">=":
+			if pendingLower {
+				matched = matched || lowerOK
+			}
+			pendingLower, lowerOK = true, cmp > 0 || (op == ">=" && cmp == 0)
+
+		case "<", "<=":
+			ok := cmp < 0 || (op == "<=" && cmp == 0)
+			if pendingLower {
+				ok = ok && lowerOK
+				pendingLower = false
+			}
+			matched = matched || ok
+
+		case "!=":
+			excluded = excluded || cmp == 0
+
+		default:
+			if pendingLower {
+				matched = matched || lowerOK
+				pendingLower = false
+			}
+			matched = matched || cmp == 0
+		}
+	}
+	if pendingLower {
+		matched = matched || lowerOK
+	}
+
+	return matched && !excluded, true
+}
+
+// makeEcosystemConditions returns a condition (over the lsif_references table aliased as r and the
+// vulnerability_affected_packages table aliased as vap) for each supported ecosystem that is true
+// if both the reference and the affected package belong to that ecosystem.
+func makeEcosystemConditions() []*sqlf.Query {
+	conditions := make([]*sqlf.Query, 0, len(ecosystems))
+	for _, e := range ecosystems {
+		conditions = append(conditions, sqlf.Sprintf(
+			"((r.scheme = ANY(%s) OR r.manager = ANY(%s)) AND lower(vap.language) = ANY(%s))",
+			pq.Array(e.schemes),
+			pq.Array(e.managers),
+			pq.Array(e.languages),
+		))
+	}
+
+	return conditions
+}
+
+var constraintOperatorPattern = regexp.MustCompile(`^(>=|<=|!=|==|>|<|=)?\s*(.*)$`)
+
+// parseConstraint splits a constraint into its operator and version. A constraint without an
+// operator is an exact match.
+func parseConstraint(constraint string) (op, version string) {
+	match := constraintOperatorPattern.FindStringSubmatch(strings.TrimSpace(constraint))
+	op, version = match[1], strings.TrimSpace(match[2])
+	if op == "" || op == "==" {
+		op = "="
+	}
+
+	return op, version
+}
+
+func splitConstraints(constraints []string) []string {
+	split := make([]string, 0, len(constraints))
+	for _, constraint := range constraints {
+		for _, part := range strings.Split(constraint, ",") {
+			if part = strings.TrimSpace(part); part != "" {
+				split = append(split, part)
+			}
+		}
+	}
+
+	return split
+}
+
+// normalizeNPMPackageName lower-cases the given npm package name. Legacy package names may contain
+// upper-case characters, but the registry treats names case-insensitively.
+func normalizeNPMPackageName(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
+
+var pythonPackageNameSeparators = regexp.MustCompile(`[-_.]+`)
+
+// normalizePythonPackageName normalizes the given distribution name as described in PEP 503: runs
+// of `-`, `_`, and `.` are equivalent and names are case-insensitive.
+func normalizePythonPackageName(name string) string {
+	return pythonPackageNameSeparators.ReplaceAllString(strings.ToLower(strings.TrimSpace(name)), "-")
+}
+
+// normalizeMavenPackageName returns the given Maven package name in `groupId:artifactId` form, as
+// used by advisories. SCIP indexers name Maven packages `maven/groupId/artifactId`.
+func normalizeMavenPackageName(name string) string {
+	name = strings.TrimPrefix(strings.TrimSpace(name), "maven/")
+	return strings.ToLower(strings.ReplaceAll(name, "/", ":"))
+}
+
+// normalizeCratePackageName normalizes the given crate name. The crates.io registry treats `-` and
+// `_` as equivalent and names case-insensitively.
+func normalizeCratePackageName(name string) string {
+	return strings.ToLower(strings.ReplaceAll(strings.TrimSpace(name), "_", "-"))
+}
+
+func contains(values []string, value string) bool {
+	return indexOf(values, value) >= 0
+}
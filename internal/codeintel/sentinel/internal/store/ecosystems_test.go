@@ -0,0 +1,240 @@
+package store
+
+import (
+	"testing"
+)
+
+func TestEcosystemForReference(t *testing.T) {
+	testCases := []struct {
+		scheme   string
+		manager  string
+		expected string
+	}{
+		{scheme: "gomod", expected: "Go"},
+		{scheme: "scip-go", manager: "gomod", expected: "Go"},
+		{scheme: "scip-typescript", manager: "npm", expected: "npm"},
+		{scheme: "scip-python", manager: "python", expected: "PyPI"},
+		{scheme: "semanticdb", manager: "maven", expected: "Maven"},
+		{scheme: "rust-analyzer", manager: "cargo", expected: "crates.io"},
+		{scheme: "scip-unknown", manager: "npm", expected: "npm"},
+		{scheme: "scip-ruby", manager: "gem", expected: ""},
+	}
+
+	for _, testCase := range testCases {
+		name := ""
+		if e := ecosystemForReference(testCase.scheme, testCase.manager); e != nil {
+			name = e.name
+		}
+		if name != testCase.expected {
+			t.Errorf("unexpected ecosystem for scheme=%q manager=%q. want=%q have=%q", testCase.scheme, testCase.manager, testCase.expected, name)
+		}
+	}
+}
+
+func TestMatchesAffectedPackage(t *testing.T) {
+	testCases := []struct {
+		ecosystem     string
+		referenceName string
+		language      string
+		packageName   string
+		expected      bool
+	}{
+		{ecosystem: "Go", referenceName: "github.com/go-nacelle/config", language: "go", packageName: "github.com/go-nacelle/config", expected: true},
+		{ecosystem: "Go", referenceName: "github.com/go-nacelle/config", language: "go", packageName: "go-nacelle/config", expected: true},
+		{ecosystem: "Go", referenceName: "github.com/go-nacelle/config", language: "go", packageName: "nacelle/config", expected: false},
+		{ecosystem: "Go", referenceName: "github.com/go-nacelle/config", language: "Javascript", packageName: "github.com/go-nacelle/config", expected: false},
+		{ecosystem: "npm", referenceName: "lodash", language: "Javascript", packageName: "lodash", expected: true},
+		{ecosystem: "npm", referenceName: "@types/node", language: "npm", packageName: "@types/node", expected: true},
+		{ecosystem: "npm", referenceName: "lodash.merge", language: "Javascript", packageName: "lodash", expected: false},
+		{ecosystem: "PyPI", referenceName: "Typing_Extensions", language: "python", packageName: "typing-extensions", expected: true},
+		{ecosystem: "PyPI", referenceName: "zope.interface", language: "PyPI", packageName: "zope-interface", expected: true},
+		{ecosystem: "PyPI", referenceName: "django-rest", language: "python", packageName: "django", expected: false},
+		{ecosystem: "Maven", referenceName: "maven/com.fasterxml.jackson.core/jackson-databind", language: "java", packageName: "com.fasterxml.jackson.core:jackson-databind", expected: true},
+		{ecosystem: "Maven", referenceName: "maven/org.apache.logging.log4j/log4j-core", language: "Maven", packageName: "org.apache.logging.log4j:log4j-api", expected: false},
+		{ecosystem: "crates.io", referenceName: "tokio_util", language: "rust", packageName: "tokio-util", expected: true},
+		{ecosystem: "crates.io", referenceName: "tokio", language: "crates.io", packageName: "tokio-util", expected: false},
+	}
+
+	for _, testCase := range testCases {
+		e := ecosystemByName(t, testCase.ecosystem)
+		if matches := e.matchesAffectedPackage(testCase.referenceName, testCase.language, testCase.packageName); matches != testCase.expected {
+			t.Errorf("unexpected match of %q against %s package %q. want=%v have=%v", testCase.referenceName, testCase.language, testCase.packageName, testCase.expected, matches)
+		}
+	}
+}
+
+func TestVersionMatchesConstraints(t *testing.T) {
+	type versionTestCase struct {
+		version  string
+		expected bool
+	}
+
+	testCases := []struct {
+		name        string
+		ecosystem   string
+		constraints []string
+		versions    []versionTestCase
+	}{
+		{
+			name:        "go upper bound",
+			ecosystem:   "Go",
+			constraints: []string{"<= v1.2.5"},
+			versions: []versionTestCase{
+				{version: "v1.2.3", expected: true},
+				{version: "v1.2.5", expected: true},
+				{version: "v1.2.6", expected: false},
+				{version: "v0.0.0-20220101000000-abcdef123456", expected: true},
+			},
+		},
+		{
+			name:        "go introduced and fixed",
+			ecosystem:   "Go",
+			constraints: []string{">=0", "<1.3.4"},
+			versions: []versionTestCase{
+				{version: "v1.3.3", expected: true},
+				{version: "v1.3.4-rc.1", expected: true},
+				{version: "v1.3.4", expected: false},
+				{version: "v2.0.0+incompatible", expected: false},
+			},
+		},
+		{
+			name:        "npm multiple ranges",
+			ecosystem:   "npm",
+			constraints: []string{">=4.0.0", "<4.17.21", ">=5.0.0-beta.1", "<5.0.3"},
+			versions: []versionTestCase{
+				{version: "3.10.1", expected: false},
+				{version: "4.17.20", expected: true},
+				{version: "4.17.21", expected: false},
+				{version: "5.0.0-alpha", expected: false},
+				{version: "5.0.0-beta.2", expected: true},
+				{version: "5.0.2", expected: true},
+				{version: "5.0.3", expected: false},
+			},
+		},
+		{
+			name:        "npm exact versions with exclusions",
+			ecosystem:   "npm",
+			constraints: []string{"=1.0.0", "=1.0.1", "!=1.0.1"},
+			versions: []versionTestCase{
+				{version: "1.0.0", expected: true},
+				{version: "1.0.1", expected: false},
+				{version: "1.0.2", expected: false},
+			},
+		},
+		{
+			name:        "pypi introduced and fixed",
+			ecosystem:   "PyPI",
+			constraints: []string{">=2.0", "<2.2.28"},
+			versions: []versionTestCase{
+				{version: "1.11.29", expected: false},
+				{version: "2.0rc1", expected: false},
+				{version: "2.0", expected: true},
+				{version: "2.2.27.post1", expected: true},
+				{version: "2.2.28rc1", expected: true},
+				{version: "2.2.28", expected: false},
+			},
+		},
+		{
+			name:        "pypi last affected",
+			ecosystem:   "PyPI",
+			constraints: []string{">=0", "<=1.26.4"},
+			versions: []versionTestCase{
+				{version: "1.26.4", expected: true},
+				{version: "1.26.4+local.1", expected: false},
+				{version: "1.26.5.dev0", expected: false},
+				{version: "1!0.1", expected: false},
+			},
+		},
+		{
+			name:        "maven introduced and fixed",
+			ecosystem:   "Maven",
+			constraints: []string{">=2.0-beta9", "<2.15.0"},
+			versions: []versionTestCase{
+				{version: "2.0-alpha1", expected: false},
+				{version: "2.0-beta9", expected: true},
+				{version: "2.0", expected: true},
+				{version: "2.14.1", expected: true},
+				{version: "2.15.0-rc1", expected: true},
+				{version: "2.15.0", expected: false},
+				{version: "2.15.0.1", expected: false},
+			},
+		},
+		{
+			name:        "maven qualified artifacts",
+			ecosystem:   "Maven",
+			constraints: []string{">=0", "<32.0.0-android"},
+			versions: []versionTestCase{
+				{version: "31.1-jre", expected: true},
+				{version: "32.0.0-android", expected: false},
+				{version: "32.0.0-jre", expected: false},
+			},
+		},
+		{
+			name:        "cargo introduced and fixed",
+			ecosystem:   "crates.io",
+			constraints: []string{">=1.8.0", "<1.8.4"},
+			versions: []versionTestCase{
+				{version: "1.7.9", expected: false},
+				{version: "1.8.0", expected: true},
+				{version: "1.8.3", expected: true},
+				{version: "1.8.4-alpha.1", expected: true},
+				{version: "1.8.4", expected: false},
+			},
+		},
+		{
+			name:        "cargo comma-separated constraint",
+			ecosystem:   "crates.io",
+			constraints: []string{">= 0.2.0, < 0.2.3"},
+			versions: []versionTestCase{
+				{version: "0.1.9", expected: false},
+				{version: "0.2.2", expected: true},
+				{version: "0.2.3", expected: false},
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			e := ecosystemByName(t, testCase.ecosystem)
+
+			for _, versionTestCase := range testCase.versions {
+				matches, valid := e.versionMatchesConstraints(versionTestCase.version, testCase.constraints)
+				if !valid {
+					t.Fatalf("unexpected invalid version or constraints: %q %v", versionTestCase.version, testCase.constraints)
+				}
+				if matches != versionTestCase.expected {
+					t.Errorf("unexpected match of %q against %v. want=%v have=%v", versionTestCase.version, testCase.constraints, versionTestCase.expected, matches)
+				}
+			}
+		})
+	}
+}
+
+func TestVersionMatchesConstraintsInvalid(t *testing.T) {
+	testCases := []struct {
+		ecosystem   string
+		version     string
+		constraints []string
+	}{
+		{ecosystem: "Go", version: "latest", constraints: []string{"<1.0.0"}},
+		{ecosystem: "npm", version: "1.0.0", constraints: []string{"<1.x"}},
+		{ecosystem: "PyPI", version: "1.0-foo", constraints: []string{"<2.0"}},
+	}
+
+	for _, testCase := range testCases {
+		if _, valid := ecosystemByName(t, testCase.ecosystem).versionMatchesConstraints(testCase.version, testCase.constraints); valid {
+			t.Errorf("expected %q against %v to be invalid", testCase.version, testCase.constraints)
+		}
+	}
+}
+
+func ecosystemByName(t *testing.T, name string) *ecosystem {
+	for i, e := range ecosystems {
+		if e.name == name {
+			return &ecosystems[i]
+		}
+	}
+
+	t.Fatalf("unknown ecosystem %q", name)
+	return nil
+}
@@ -2,10 +2,7 @@ package store
 
 import (
 	"context"
-	"sort"
-	"strings"
 
-	"github.com/hashicorp/go-version"
 	"github.com/keegancsmith/sqlf"
 	"github.com/lib/pq"
 	"go.opentelemetry.io/otel/attribute"
@@ -27,6 +24,9 @@ func (s *store) VulnerabilityMatchByID(ctx context.Context, id int) (_ shared.Vu
 	if err != nil || len(matches) == 0 {
 		return shared.VulnerabilityMatch{}, false, err
 	}
+	if err := s.attachCallSites(ctx, matches); err != nil {
+		return shared.VulnerabilityMatch{}, false, err
+	}
 
 	return matches[0], true, nil
 }
@@ -36,6 +36,7 @@ SELECT
 	m.id,
 	m.upload_id,
 	vap.vulnerability_id,
+	COALESCE(m.reachable, false),
 	vap.package_name,
 	vap.language,
 	vap.namespace,
@@ -77,7 +78,15 @@ func (s *store) GetVulnerabilityMatches(ctx context.Context, args shared.GetVuln
 		conds = append(conds, sqlf.Sprintf("TRUE"))
 	}
 
-	return scanVulnerabilityMatchesAndCount(s.db.Query(ctx, sqlf.Sprintf(getVulnerabilityMatchesQuery, sqlf.Join(conds, " AND "), args.Limit, args.Offset)))
+	matches, totalCount, err := scanVulnerabilityMatchesAndCount(s.db.Query(ctx, sqlf.Sprintf(getVulnerabilityMatchesQuery, sqlf.Join(conds, " AND "), args.Limit, args.Offset)))
+	if err != nil {
+		return nil, 0, err
+	}
+	if err := s.attachCallSites(ctx, matches); err != nil {
+		return nil, 0, err
+	}
+
+	return matches, totalCount, nil
 }
 
 const getVulnerabilityMatchesQuery = `
@@ -85,7 +94,8 @@ WITH limited_matches AS (
 	SELECT
 		m.id,
 		m.upload_id,
-		m.vulnerability_affected_package_id
+		m.vulnerability_affected_package_id,
+		m.reachable
 	FROM vulnerability_matches m
 	ORDER BY id
 )
@@ -93,6 +103,7 @@ SELECT
 	m.id,
 	m.upload_id,
 	vap.vulnerability_id,
+	COALESCE(m.reachable, false),
 	vap.package_name,
 	vap.language,
 	vap.namespace,
@@ -225,16 +236,35 @@ func (s *store) ScanMatches(ctx context.Context, batchSize int) (numReferencesSc
 		numScanned := 0
 		scanFilteredVulnerabilityMatches := basestore.NewFilteredSliceScanner(func(s dbutil.Scanner) (m vulnerabilityMatch, _ bool, _ error) {
 			var (
+				scheme             string
+				manager            string
+				name               string
 				version            string
+				language           string
+				packageName        string
 				versionConstraints []string
 			)
 
-			if err := s.Scan(&m.UploadID, &m.VulnerabilityAffectedPackageID, &version, pq.Array(&versionConstraints)); err != nil {
+			if err := s.Scan(
+				&m.UploadID,
+				&m.VulnerabilityAffectedPackageID,
+				&scheme,
+				&manager,
+				&name,
+				&version,
+				&language,
+				&packageName,
+				pq.Array(&versionConstraints),
+			); err != nil {
 				return vulnerabilityMatch{}, false, err
 			}
 
 			numScanned++
-			matches, valid := versionMatchesConstraints(version, versionConstraints)
+			ecosystem := ecosystemForReference(scheme, manager)
+			if ecosystem == nil || !ecosystem.matchesAffectedPackage(name, language, packageName) {
+				return m, false, nil
+			}
+			matches, valid := ecosystem.versionMatchesConstraints(version, versionConstraints)
 			_ = valid // TODO - log un-parseable versions
 
 			return m, matches, nil
@@ -243,7 +273,7 @@ func (s *store) ScanMatches(ctx context.Context, batchSize int) (numReferencesSc
 		matches, err := scanFilteredVulnerabilityMatches(tx.Query(ctx, sqlf.Sprintf(
 			scanMatchesQuery,
 			batchSize,
-			sqlf.Join(makeSchemeTtoVulnerabilityLanguageMappingConditions(), " OR "),
+			sqlf.Join(makeEcosystemConditions(), " OR "),
 		)))
 		if err != nil {
 			return err
@@ -322,16 +352,20 @@ locked_candidates AS (
 SELECT
 	r.dump_id,
 	vap.id,
+	r.scheme,
+	r.manager,
+	r.name,
 	r.version,
+	vap.language,
+	vap.package_name,
 	vap.version_constraint
 FROM locked_candidates lc
 JOIN lsif_references r ON r.dump_id = lc.upload_id
 JOIN vulnerability_affected_packages vap ON
-	-- NOTE: This is currently a bit of a hack that works to find some
-	-- good matches with the dataset we have. We should have a better
-	-- way to match on a normalized name here, or have rules per types
-	-- of language ecosystem.
-	r.name LIKE '%%' || vap.package_name || '%%'
+	-- NOTE: This is a coarse filter that ignores the differences in separators and case
+	-- between package names of the same ecosystem. The exact, per-ecosystem comparison of
+	-- the candidate pairs is done in ecosystem.matchesAffectedPackage.
+	lower(translate(r.name, '_./:', '----')) LIKE '%%' || lower(translate(vap.package_name, '_./:', '----')) || '%%'
 WHERE %s
 `
 
@@ -368,6 +402,7 @@ var scanVulnerabilityMatchesAndCount = func(rows basestore.Rows, queryErr error)
 			&match.ID,
 			&match.UploadID,
 			&match.VulnerabilityID,
+			&match.Reachable,
 			// RHS(s) of left join (may be null)
 			&dbutil.NullString{S: &vap.PackageName},
 			&dbutil.NullString{S: &vap.Language},
@@ -376,7 +411,7 @@ var scanVulnerabilityMatchesAndCount = func(rows basestore.Rows, queryErr error)
 			&dbutil.NullBool{B: &vap.Fixed},
 			&dbutil.NullString{S: &fixedIn},
 			&dbutil.NullString{S: &vas.Path},
-			pq.Array(vas.Symbols),
+			pq.Array(&vas.Symbols),
 			&dbutil.NullString{S: &vul.Severity},
 			&count,
 		); err != nil {
@@ -421,38 +456,3 @@ var flattenMatches = func(ms []shared.VulnerabilityMatch) []shared.Vulnerability
 
 	return flattened
 }
-
-func versionMatchesConstraints(versionString string, constraints []string) (matches, valid bool) {
-	v, err := version.NewVersion(versionString)
-	if err != nil {
-		return false, false
-	}
-
-	constraint, err := version.NewConstraint(strings.Join(constraints, ","))
-	if err != nil {
-		return false, false
-	}
-
-	return constraint.Check(v), true
-}
-
-var scipSchemeToVulnerabilityLanguage = map[string]string{
-	"gomod": "go",
-	"npm":   "Javascript",
-	// TODO - java mapping
-}
-
-func makeSchemeTtoVulnerabilityLanguageMappingConditions() []*sqlf.Query {
-	schemes := make([]string, 0, len(scipSchemeToVulnerabilityLanguage))
-	for scheme := range scipSchemeToVulnerabilityLanguage {
-		schemes = append(schemes, scheme)
-	}
-	sort.Strings(schemes)
-
-	mappings := make([]*sqlf.Query, 0, len(schemes))
-	for _, scheme := range schemes {
-		mappings = append(mappings, sqlf.Sprintf("(r.scheme = %s AND vap.language = %s)", scheme, scipSchemeToVulnerabilityLanguage[scheme]))
-	}
-
-	return mappings
-}
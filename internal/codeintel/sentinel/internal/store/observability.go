@@ -17,6 +17,9 @@ type operations struct {
 	getVulnerabilityMatchesSummaryCount      *observation.Operation
 	getVulnerabilityMatchesCountByRepository *observation.Operation
 	scanMatches                              *observation.Operation
+	getUnresolvedVulnerabilityMatches        *observation.Operation
+	updateVulnerabilityMatchCallSites        *observation.Operation
+	markVulnerabilityMatchCallSitesFailed    *observation.Operation
 }
 
 var m = new(metrics.SingletonREDMetrics)
@@ -49,5 +52,8 @@ func newOperations(observationCtx *observation.Context) *operations {
 		getVulnerabilityMatchesSummaryCount:      op("GetVulnerabilityMatchesSummaryCount"),
 		getVulnerabilityMatchesCountByRepository: op("GetVulnerabilityMatchesCountByRepository"),
 		scanMatches:                              op("ScanMatches"),
+		getUnresolvedVulnerabilityMatches:        op("GetUnresolvedVulnerabilityMatches"),
+		updateVulnerabilityMatchCallSites:        op("UpdateVulnerabilityMatchCallSites"),
+		markVulnerabilityMatchCallSitesFailed:    op("MarkVulnerabilityMatchCallSitesFailed"),
 	}
 }
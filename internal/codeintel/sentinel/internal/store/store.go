@@ -24,6 +24,11 @@ type Store interface {
 	GetVulnerabilityMatchesSummaryCount(ctx context.Context) (counts shared.GetVulnerabilityMatchesSummaryCounts, err error)
 	GetVulnerabilityMatchesCountByRepository(ctx context.Context, args shared.GetVulnerabilityMatchesCountByRepositoryArgs) (_ []shared.VulnerabilityMatchesByRepository, _ int, err error)
 	ScanMatches(ctx context.Context, batchSize int) (numReferencesScanned int, numVulnerabilityMatches int, _ error)
+
+	// Vulnerability match call sites
+	GetUnresolvedVulnerabilityMatches(ctx context.Context, limit int) (_ []shared.UnresolvedVulnerabilityMatch, err error)
+	UpdateVulnerabilityMatchCallSites(ctx context.Context, matchID int, callSites []shared.SymbolReference) (err error)
+	MarkVulnerabilityMatchCallSitesFailed(ctx context.Context, matchID int) (err error)
 }
 
 type store struct {
@@ -0,0 +1,550 @@
+package store
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// versionComparer compares two versions of the same ecosystem, returning a negative number if a
+// orders before b, a positive number if a orders after b, and zero if the versions are equivalent.
+// An error is returned if either version cannot be parsed.
+type versionComparer func(a, b string) (int, error)
+
+//
+// Semantic versioning (Go modules, npm, Cargo)
+
+type semanticVersion struct {
+	release    [3]string
+	prerelease []string
+}
+
+var semverPattern = regexp.MustCompile(`^v?(\d+)(?:\.(\d+))?(?:\.(\d+))?(?:-([0-9A-Za-z.-]+))?(?:\+[0-9A-Za-z.-]+)?$`)
+
+// parseSemanticVersion parses a semantic version. Missing minor and patch components are treated
+// as zero so that bounds such as `>=0` (as emitted for an OSV `introduced: 0` event) are accepted.
+// A leading `v` (as used by Go modules) and a leading `=` (as accepted by npm) are ignored, as is
+// build metadata (e.g., `+incompatible`).
+func parseSemanticVersion(raw string) (semanticVersion, error) {
+	match := semverPattern.FindStringSubmatch(strings.TrimPrefix(strings.TrimSpace(raw), "="))
+	if match == nil {
+		return semanticVersion{}, errors.Newf("illegal semantic version %q", raw)
+	}
+
+	v := semanticVersion{release: [3]string{match[1], match[2], match[3]}}
+	for i, part := range v.release {
+		if part == "" {
+			v.release[i] = "0"
+		}
+	}
+	if match[4] != "" {
+		v.prerelease = strings.Split(match[4], ".")
+	}
+
+	return v, nil
+}
+
+// compareSemanticVersions orders versions according to semver 2.0.0: release components compare
+// numerically, a pre-release orders before the associated release, and pre-release identifiers
+// compare numerically if both are numeric and lexically otherwise (numeric identifiers first).
+func compareSemanticVersions(a, b string) (int, error) {
+	va, err := parseSemanticVersion(a)
+	if err != nil {
+		return 0, err
+	}
+	vb, err := parseSemanticVersion(b)
+	if err != nil {
+		return 0, err
+	}
+
+	for i := range va.release {
+		if cmp := compareNumeric(va.release[i], vb.release[i]); cmp != 0 {
+			return cmp, nil
+		}
+	}
+
+	switch {
+	case len(va.prerelease) == 0 && len(vb.prerelease) == 0:
+		return 0, nil
+	case len(va.prerelease) == 0:
+		return 1, nil
+	case len(vb.prerelease) == 0:
+		return -1, nil
+	}
+
+	for i := 0; i < len(va.prerelease) && i < len(vb.prerelease); i++ {
+		x, y := va.prerelease[i], vb.prerelease[i]
+		xNumeric, yNumeric := isNumeric(x), isNumeric(y)
+
+		var cmp int
+		switch {
+		case xNumeric && yNumeric:
+			cmp = compareNumeric(x, y)
+		case xNumeric:
+			cmp = -1
+		case yNumeric:
+			cmp = 1
+		default:
+			cmp = strings.Compare(x, y)
+		}
+		if cmp != 0 {
+			return cmp, nil
+		}
+	}
+
+	return compareInts(len(va.prerelease), len(vb.prerelease)), nil
+}
+
+//
+// PEP 440 (PyPI)
+
+type pep440Version struct {
+	epoch   string
+	release []string
+	// preKind is one of `a`, `b`, or `rc`; empty if there is no pre-release segment.
+	preKind   string
+	pre       string
+	post      string
+	hasPost   bool
+	dev       string
+	hasDev    bool
+	localSegs []string
+}
+
+var pep440Pattern = regexp.MustCompile(`^v?` +
+	`(?:(\d+)!)?` + // epoch
+	`(\d+(?:\.\d+)*)` + // release
+	`(?:[-_.]?(a|b|c|rc|alpha|beta|pre|preview)[-_.]?(\d+)?)?` + // pre-release
+	`(?:-(\d+)|[-_.]?(post|rev|r)[-_.]?(\d+)?)?` + // post-release
+	`(?:[-_.]?(dev)[-_.]?(\d+)?)?` + // development release
+	`(?:\+([a-z0-9]+(?:[-_.][a-z0-9]+)*))?$`, // local version
+)
+
+var pep440LocalSeparators = regexp.MustCompile(`[-_.]`)
+
+// parsePEP440Version parses a version according to PEP 440, normalizing alternate spellings
+// (e.g., `1.0-alpha1`, `1.0.post-1`, and `1.0-1` are equivalent to `1.0a1`, `1.0.post1`, and
+// `1.0.post1`, respectively). Versions are case-insensitive.
+func parsePEP440Version(raw string) (pep440Version, error) {
+	match := pep440Pattern.FindStringSubmatch(strings.ToLower(strings.TrimSpace(raw)))
+	if match == nil {
+		return pep440Version{}, errors.Newf("illegal PEP 440 version %q", raw)
+	}
+
+	v := pep440Version{
+		epoch:   match[1],
+		release: strings.Split(match[2], "."),
+	}
+	if v.epoch == "" {
+		v.epoch = "0"
+	}
+
+	if match[3] != "" {
+		switch match[3] {
+		case "a", "alpha":
+			v.preKind = "a"
+		case "b", "beta":
+			v.preKind = "b"
+		default:
+			v.preKind = "rc"
+		}
+		v.pre = defaultNumber(match[4])
+	}
+
+	if match[5] != "" {
+		v.hasPost, v.post = true, match[5]
+	} else if match[6] != "" {
+		v.hasPost, v.post = true, defaultNumber(match[7])
+	}
+
+	if match[8] != "" {
+		v.hasDev, v.dev = true, defaultNumber(match[9])
+	}
+
+	if match[10] != "" {
+		v.localSegs = pep440LocalSeparators.Split(match[10], -1)
+	}
+
+	return v, nil
+}
+
+// comparePEP440Versions orders versions according to PEP 440: by epoch, then release segment
+// (ignoring trailing zeros), then pre-release, post-release, development release, and finally
+// local version label. Development releases order before pre-releases of the same release, and
+// pre-releases order before the release itself.
+func comparePEP440Versions(a, b string) (int, error) {
+	va, err := parsePEP440Version(a)
+	if err != nil {
+		return 0, err
+	}
+	vb, err := parsePEP440Version(b)
+	if err != nil {
+		return 0, err
+	}
+
+	if cmp := compareNumeric(va.epoch, vb.epoch); cmp != 0 {
+		return cmp, nil
+	}
+	if cmp := compareNumericSegments(va.release, vb.release); cmp != 0 {
+		return cmp, nil
+	}
+	if cmp := comparePEP440Pre(va, vb); cmp != 0 {
+		return cmp, nil
+	}
+	if cmp := compareOptionalNumeric(va.hasPost, va.post, vb.hasPost, vb.post, -1); cmp != 0 {
+		return cmp, nil
+	}
+	if cmp := compareOptionalNumeric(va.hasDev, va.dev, vb.hasDev, vb.dev, 1); cmp != 0 {
+		return cmp, nil
+	}
+
+	return comparePEP440Local(va.localSegs, vb.localSegs), nil
+}
+
+var pep440PreKindOrder = map[string]int{"a": 0, "b": 1, "rc": 2}
+
+func comparePEP440Pre(a, b pep440Version) int {
+	// A version without a pre-release segment orders after all pre-releases, unless it is a
+	// development release with neither pre- nor post-release segments (e.g., `1.0.dev1`), which
+	// orders before all pre-releases.
+	rank := func(v pep440Version) int {
+		switch {
+		case v.preKind != "":
+			return 0
+		case v.hasDev && !v.hasPost:
+			return -1
+		default:
+			return 1
+		}
+	}
+
+	ra, rb := rank(a), rank(b)
+	if ra != rb || ra != 0 {
+		return compareInts(ra, rb)
+	}
+	if cmp := compareInts(pep440PreKindOrder[a.preKind], pep440PreKindOrder[b.preKind]); cmp != 0 {
+		return cmp
+	}
+
+	return compareNumeric(a.pre, b.pre)
+}
+
+func comparePEP440Local(a, b []string) int {
+	for i := 0; i < len(a) && i < len(b); i++ {
+		x, y := a[i], b[i]
+		xNumeric, yNumeric := isNumeric(x), isNumeric(y)
+
+		var cmp int
+		switch {
+		case xNumeric && yNumeric:
+			cmp = compareNumeric(x, y)
+		case xNumeric:
+			cmp = 1
+		case yNumeric:
+			cmp = -1
+		default:
+			cmp = strings.Compare(x, y)
+		}
+		if cmp != 0 {
+			return cmp
+		}
+	}
+
+	return compareInts(len(a), len(b))
+}
+
+//
+// Maven
+
+// mavenItem is a single component of a parsed Maven version: an integer, a qualifier, or a
+// nested list (introduced by a hyphen or a transition between digits and letters).
+type mavenItem struct {
+	kind  mavenItemKind
+	value string
+	items []mavenItem
+}
+
+type mavenItemKind int
+
+const (
+	mavenIntItem mavenItemKind = iota
+	mavenStringItem
+	mavenListItem
+)
+
+// mavenQualifiers lists the well-known qualifiers in ascending order. The empty string denotes
+// a release. Unknown qualifiers order after all well-known qualifiers, lexically.
+var mavenQualifiers = []string{"alpha", "beta", "milestone", "rc", "snapshot", "", "sp"}
+
+var mavenQualifierAliases = map[string]string{
+	"ga":      "",
+	"final":   "",
+	"release": "",
+	"cr":      "rc",
+}
+
+var mavenReleaseQualifierIndex = strconv.Itoa(indexOf(mavenQualifiers, ""))
+
+// parseMavenVersion parses a version in the same way as Maven's ComparableVersion. Parsing is
+// total: every string is a valid Maven version.
+func parseMavenVersion(raw string) mavenItem {
+	version := strings.ToLower(strings.TrimSpace(raw))
+
+	root := mavenItem{kind: mavenListItem}
+	stack := []*mavenItem{&root}
+	list := &root
+
+	pushList := func() {
+		list.items = append(list.items, mavenItem{kind: mavenListItem})
+		list = &list.items[len(list.items)-1]
+		stack = append(stack, list)
+	}
+
+	isDigit, start := false, 0
+	for i := 0; i < len(version); i++ {
+		c := version[i]
+
+		switch {
+		case c == '.' || c == '-':
+			if i == start {
+				list.items = append(list.items, mavenItem{kind: mavenIntItem})
+			} else {
+				list.items = append(list.items, newMavenItem(isDigit, version[start:i], false))
+			}
+			start = i + 1
+			if c == '-' {
+				pushList()
+			}
+
+		case c >= '0' && c <= '9':
+			if !isDigit && i > start {
+				// A qualifier directly followed by a number (e.g., `rc1`) starts a nested list
+				list.items = append(list.items, newMavenItem(false, version[start:i], true))
+				start = i
+				pushList()
+			}
+			isDigit = true
+
+		default:
+			if isDigit && i > start {
+				list.items = append(list.items, newMavenItem(true, version[start:i], false))
+				start = i
+				pushList()
+			}
+			isDigit = false
+		}
+	}
+	if len(version) > start {
+		list.items = append(list.items, newMavenItem(isDigit, version[start:], false))
+	}
+
+	// Nested lists are pointers into their parent's items; normalize the innermost lists first
+	for i := len(stack) - 1; i >= 0; i-- {
+		stack[i].normalize()
+	}
+
+	return root
+}
+
+func newMavenItem(isDigit bool, value string, followedByDigit bool) mavenItem {
+	if isDigit {
+		return mavenItem{kind: mavenIntItem, value: strings.TrimLeft(value, "0")}
+	}
+
+	if followedByDigit && len(value) == 1 {
+		switch value {
+		case "a":
+			value = "alpha"
+		case "b":
+			value = "beta"
+		case "m":
+			value = "milestone"
+		}
+	}
+	if alias, ok := mavenQualifierAliases[value]; ok {
+		value = alias
+	}
+
+	return mavenItem{kind: mavenStringItem, value: value}
+}
+
+// normalize removes trailing null items (zeros, release qualifiers, and empty lists) from the list.
+func (item *mavenItem) normalize() {
+	for i := len(item.items) - 1; i >= 0; i-- {
+		if last := item.items[i]; last.isNull() {
+			item.items = append(item.items[:i], item.items[i+1:]...)
+		} else if last.kind != mavenListItem {
+			break
+		}
+	}
+}
+
+func (item mavenItem) isNull() bool {
+	if item.kind == mavenListItem {
+		return len(item.items) == 0
+	}
+
+	// Integer values are stored without leading zeros, so zero is the empty string
+	return item.value == ""
+}
+
+func mavenComparableQualifier(qualifier string) string {
+	if i := indexOf(mavenQualifiers, qualifier); i >= 0 {
+		return strconv.Itoa(i)
+	}
+
+	return strconv.Itoa(len(mavenQualifiers)) + "-" + qualifier
+}
+
+// compare compares the item against the given item. A nil item denotes the absence of an item
+// (i.e., the shorter of two lists padded with nulls).
+func (item mavenItem) compare(other *mavenItem) int {
+	switch item.kind {
+	case mavenIntItem:
+		if other == nil {
+			return compareInts(len(item.value), 0)
+		}
+		if other.kind == mavenIntItem {
+			return compareNumeric(item.value, other.value)
+		}
+		return 1
+
+	case mavenStringItem:
+		if other == nil {
+			return strings.Compare(mavenComparableQualifier(item.value), mavenReleaseQualifierIndex)
+		}
+		if other.kind == mavenStringItem {
+			return strings.Compare(mavenComparableQualifier(item.value), mavenComparableQualifier(other.value))
+		}
+		return -1
+
+	default:
+		if other == nil {
+			if len(item.items) == 0 {
+				return 0
+			}
+			return item.items[0].compare(nil)
+		}
+		switch other.kind {
+		case mavenIntItem:
+			return -1
+		case mavenStringItem:
+			return 1
+		}
+
+		for i := 0; i < len(item.items) || i < len(other.items); i++ {
+			var cmp int
+			switch {
+			case i >= len(item.items):
+				cmp = -other.items[i].compare(nil)
+			case i >= len(other.items):
+				cmp = item.items[i].compare(nil)
+			default:
+				cmp = item.items[i].compare(&other.items[i])
+			}
+			if cmp != 0 {
+				return cmp
+			}
+		}
+		return 0
+	}
+}
+
+// compareMavenVersions orders versions according to Maven's version ordering specification.
+// Every string is a valid Maven version, so no error is ever returned.
+func compareMavenVersions(a, b string) (int, error) {
+	va, vb := parseMavenVersion(a), parseMavenVersion(b)
+	return va.compare(&vb), nil
+}
+
+//
+// Helpers
+
+func isNumeric(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+
+	return true
+}
+
+// compareNumeric compares two strings of decimal digits without converting them to integers,
+// so that arbitrarily large components (e.g., date-based versions) cannot overflow.
+func compareNumeric(a, b string) int {
+	a, b = strings.TrimLeft(a, "0"), strings.TrimLeft(b, "0")
+	if cmp := compareInts(len(a), len(b)); cmp != 0 {
+		return cmp
+	}
+
+	return strings.Compare(a, b)
+}
+
+// compareNumericSegments compares two dot-separated numeric release segments, padding the
+// shorter of the two with zeros.
+func compareNumericSegments(a, b []string) int {
+	for i := 0; i < len(a) || i < len(b); i++ {
+		x, y := "0", "0"
+		if i < len(a) {
+			x = a[i]
+		}
+		if i < len(b) {
+			y = b[i]
+		}
+		if cmp := compareNumeric(x, y); cmp != 0 {
+			return cmp
+		}
+	}
+
+	return 0
+}
+
+// compareOptionalNumeric compares two optional numeric segments. The absence of a segment orders
+// before all present values if missing is negative, and after all present values otherwise.
+func compareOptionalNumeric(hasA bool, a string, hasB bool, b string, missing int) int {
+	switch {
+	case hasA && hasB:
+		return compareNumeric(a, b)
+	case hasA:
+		return -missing
+	case hasB:
+		return missing
+	default:
+		return 0
+	}
+}
+
+func compareInts(a, b int) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
+
+func defaultNumber(s string) string {
+	if s == "" {
+		return "0"
+	}
+
+	return s
+}
+
+func indexOf(values []string, value string) int {
+	for i, v := range values {
+		if v == value {
+			return i
+		}
+	}
+
+	return -1
+}
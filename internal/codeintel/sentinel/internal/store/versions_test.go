@@ -0,0 +1,165 @@
+package store
+
+import (
+	"testing"
+)
+
+func TestCompareSemanticVersions(t *testing.T) {
+	testCases := []struct {
+		a, b     string
+		expected int
+	}{
+		{a: "1.2.3", b: "1.2.3", expected: 0},
+		{a: "v1.2.3", b: "1.2.3", expected: 0},
+		{a: "=1.2.3", b: "1.2.3", expected: 0},
+		{a: "1.2.3+build.7", b: "1.2.3", expected: 0},
+		{a: "v2.0.0+incompatible", b: "v2.0.0", expected: 0},
+		{a: "1.2", b: "1.2.0", expected: 0},
+		{a: "0", b: "0.0.0", expected: 0},
+		{a: "1.2.3", b: "1.2.10", expected: -1},
+		{a: "1.10.0", b: "1.9.9", expected: 1},
+		{a: "1.0.0-alpha", b: "1.0.0", expected: -1},
+		{a: "1.0.0-alpha", b: "1.0.0-alpha.1", expected: -1},
+		{a: "1.0.0-alpha.1", b: "1.0.0-alpha.beta", expected: -1},
+		{a: "1.0.0-beta.2", b: "1.0.0-beta.11", expected: -1},
+		{a: "1.0.0-rc.1", b: "1.0.0-beta.11", expected: 1},
+		{a: "v0.0.0-20210101000000-abcdef123456", b: "v0.0.1", expected: -1},
+		{a: "20230101.0.0", b: "9999999999999999999999.0.0", expected: -1},
+	}
+
+	for _, testCase := range testCases {
+		cmp, err := compareSemanticVersions(testCase.a, testCase.b)
+		if err != nil {
+			t.Fatalf("unexpected error comparing %q and %q: %s", testCase.a, testCase.b, err)
+		}
+		if cmp != testCase.expected {
+			t.Errorf("unexpected comparison of %q and %q. want=%d have=%d", testCase.a, testCase.b, testCase.expected, cmp)
+		}
+	}
+
+	for _, version := range []string{"", "latest", "1.2.3.4", "1.x", "^1.2.3"} {
+		if _, err := compareSemanticVersions(version, "1.0.0"); err == nil {
+			t.Errorf("expected error parsing %q", version)
+		}
+	}
+}
+
+func TestComparePEP440Versions(t *testing.T) {
+	// Listed in ascending order
+	orderedVersions := []string{
+		"1.0.dev456",
+		"1.0a1",
+		"1.0a2.dev456",
+		"1.0a12.dev456",
+		"1.0a12",
+		"1.0b1.dev456",
+		"1.0b2",
+		"1.0b2.post345.dev456",
+		"1.0b2.post345",
+		"1.0rc1.dev456",
+		"1.0rc1",
+		"1.0",
+		"1.0+abc.5",
+		"1.0+abc.7",
+		"1.0+5",
+		"1.0.post456.dev34",
+		"1.0.post456",
+		"1.0.15",
+		"1.1.dev1",
+		"1!0.5",
+	}
+
+	for i := 0; i < len(orderedVersions)-1; i++ {
+		a, b := orderedVersions[i], orderedVersions[i+1]
+
+		if cmp, err := comparePEP440Versions(a, b); err != nil {
+			t.Fatalf("unexpected error comparing %q and %q: %s", a, b, err)
+		} else if cmp >= 0 {
+			t.Errorf("expected %q to order before %q", a, b)
+		}
+		if cmp, err := comparePEP440Versions(b, a); err != nil {
+			t.Fatalf("unexpected error comparing %q and %q: %s", b, a, err)
+		} else if cmp <= 0 {
+			t.Errorf("expected %q to order after %q", b, a)
+		}
+	}
+
+	equivalentVersions := [][2]string{
+		{"1.0", "1.0.0"},
+		{"1.0-alpha1", "1.0a1"},
+		{"1.0.ALPHA.1", "1.0a1"},
+		{"1.0c1", "1.0rc1"},
+		{"1.0-preview-2", "1.0rc2"},
+		{"1.0-1", "1.0.post1"},
+		{"1.0.rev1", "1.0.post1"},
+		{"1.0-post", "1.0.post0"},
+		{"1.0-dev", "1.0.dev0"},
+		{"v1.0", "1.0"},
+		{"0!1.0", "1.0"},
+	}
+	for _, pair := range equivalentVersions {
+		if cmp, err := comparePEP440Versions(pair[0], pair[1]); err != nil {
+			t.Fatalf("unexpected error comparing %q and %q: %s", pair[0], pair[1], err)
+		} else if cmp != 0 {
+			t.Errorf("expected %q to be equivalent to %q", pair[0], pair[1])
+		}
+	}
+
+	for _, version := range []string{"", "latest", "1.0-foo", "1.0+"} {
+		if _, err := comparePEP440Versions(version, "1.0"); err == nil {
+			t.Errorf("expected error parsing %q", version)
+		}
+	}
+}
+
+func TestCompareMavenVersions(t *testing.T) {
+	// Listed in ascending order
+	orderedVersions := []string{
+		"1-alpha",
+		"1-alpha2",
+		"1-alpha10",
+		"1-beta1",
+		"1-milestone1",
+		"1-rc1",
+		"1-SNAPSHOT",
+		"1",
+		"1-sp1",
+		"1-abc",
+		"1-1",
+		"1.0.1",
+		"1.1",
+		"1.1.0.1",
+		"1.2-jre",
+		"1.10",
+		"31.0-android",
+		"31.0-jre",
+		"31.0.1-jre",
+	}
+
+	for i := 0; i < len(orderedVersions)-1; i++ {
+		a, b := orderedVersions[i], orderedVersions[i+1]
+
+		if cmp, _ := compareMavenVersions(a, b); cmp >= 0 {
+			t.Errorf("expected %q to order before %q", a, b)
+		}
+		if cmp, _ := compareMavenVersions(b, a); cmp <= 0 {
+			t.Errorf("expected %q to order after %q", b, a)
+		}
+	}
+
+	equivalentVersions := [][2]string{
+		{"1", "1.0.0"},
+		{"1.0", "1-ga"},
+		{"1.0", "1.final"},
+		{"1.0", "1-RELEASE"},
+		{"1-a1", "1-alpha-1"},
+		{"1-cr1", "1-rc1"},
+		{"1.0.0-1", "1-1"},
+		{"01.002", "1.2"},
+	}
+	for _, pair := range equivalentVersions {
+		if cmp, _ := compareMavenVersions(pair[0], pair[1]); cmp != 0 {
+			t.Errorf("expected %q to be equivalent to %q", pair[0], pair[1])
+		}
+	}
+}
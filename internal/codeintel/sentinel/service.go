@@ -3,6 +3,7 @@ package sentinel
 import (
 	"context"
 
+	"github.com/sourcegraph/sourcegraph/internal/codeintel/sentinel/internal/lsifstore"
 	"github.com/sourcegraph/sourcegraph/internal/codeintel/sentinel/internal/store"
 	"github.com/sourcegraph/sourcegraph/internal/codeintel/sentinel/shared"
 	"github.com/sourcegraph/sourcegraph/internal/observation"
@@ -10,15 +11,18 @@ import (
 
 type Service struct {
 	store      store.Store
+	lsifstore  lsifstore.Store
 	operations *operations
 }
 
 func newService(
 	observationCtx *observation.Context,
 	store store.Store,
+	lsifstore lsifstore.Store,
 ) *Service {
 	return &Service{
 		store:      store,
+		lsifstore:  lsifstore,
 		operations: newOperations(observationCtx),
 	}
 }
@@ -56,6 +56,41 @@ type VulnerabilityMatch struct {
 	UploadID        int
 	VulnerabilityID int
 	AffectedPackage AffectedPackage
+
+	// Reachable is true if the index references one of the symbols affected by the vulnerability.
+	// If the vulnerability does not list affected symbols, any reference to a symbol of the affected
+	// package is considered a call site.
+	Reachable bool
+	CallSites []SymbolReference
+}
+
+// SymbolReference is the location of a reference to a symbol within an index.
+type SymbolReference struct {
+	Symbol         string
+	Path           string
+	StartLine      int
+	StartCharacter int
+	EndLine        int
+	EndCharacter   int
+}
+
+// UnresolvedVulnerabilityMatch is a vulnerability match for which the call sites of the affected
+// symbols within the index have not yet been resolved.
+type UnresolvedVulnerabilityMatch struct {
+	ID              int
+	UploadID        int
+	AffectedPackage AffectedPackage
+
+	// Packages are the packages referenced by the index that match the affected package.
+	Packages []PackageReference
+}
+
+// PackageReference is a package (with a version) that is referenced by an index.
+type PackageReference struct {
+	Scheme  string
+	Manager string
+	Name    string
+	Version string
 }
 
 type GetVulnerabilitiesArgs struct {
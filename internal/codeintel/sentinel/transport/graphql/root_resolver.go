@@ -352,6 +352,49 @@ func (r *vulnerabilityMatchResolver) PreciseIndex(ctx context.Context) (resolver
 	return r.preciseIndexResolverFactory.Create(ctx, r.uploadLoader, r.indexLoader, r.locationResolver, r.errTracer, &upload, nil)
 }
 
+func (r *vulnerabilityMatchResolver) Reachable() bool {
+	return r.m.Reachable
+}
+
+func (r *vulnerabilityMatchResolver) CallSites() []resolverstubs.VulnerabilityCallSiteResolver {
+	resolvers := make([]resolverstubs.VulnerabilityCallSiteResolver, 0, len(r.m.CallSites))
+	for _, callSite := range r.m.CallSites {
+		resolvers = append(resolvers, &vulnerabilityCallSiteResolver{callSite})
+	}
+
+	return resolvers
+}
+
+type vulnerabilityCallSiteResolver struct {
+	s shared.SymbolReference
+}
+
+func (r *vulnerabilityCallSiteResolver) Symbol() string { return r.s.Symbol }
+func (r *vulnerabilityCallSiteResolver) Path() string   { return r.s.Path }
+func (r *vulnerabilityCallSiteResolver) Range() resolverstubs.RangeResolver {
+	return &callSiteRangeResolver{r.s}
+}
+
+type callSiteRangeResolver struct {
+	s shared.SymbolReference
+}
+
+func (r *callSiteRangeResolver) Start() resolverstubs.PositionResolver {
+	return &callSitePositionResolver{line: r.s.StartLine, character: r.s.StartCharacter}
+}
+
+func (r *callSiteRangeResolver) End() resolverstubs.PositionResolver {
+	return &callSitePositionResolver{line: r.s.EndLine, character: r.s.EndCharacter}
+}
+
+type callSitePositionResolver struct {
+	line      int
+	character int
+}
+
+func (r *callSitePositionResolver) Line() int32      { return int32(r.line) }
+func (r *callSitePositionResolver) Character() int32 { return int32(r.character) }
+
 //
 //
 
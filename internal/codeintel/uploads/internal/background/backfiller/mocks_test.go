@@ -28,6 +28,9 @@ type MockStore struct {
 	// AddUploadPartFunc is an instance of a mock function object
 	// controlling the behavior of the method AddUploadPart.
 	AddUploadPartFunc *StoreAddUploadPartFunc
+	// CopyPackagesFromUploadFunc is an instance of a mock function object
+	// controlling the behavior of the method CopyPackagesFromUpload.
+	CopyPackagesFromUploadFunc *StoreCopyPackagesFromUploadFunc
 	// DeleteIndexByIDFunc is an instance of a mock function object
 	// controlling the behavior of the method DeleteIndexByID.
 	DeleteIndexByIDFunc *StoreDeleteIndexByIDFunc
@@ -120,6 +123,9 @@ type MockStore struct {
 	// GetUploadByIDFunc is an instance of a mock function object
 	// controlling the behavior of the method GetUploadByID.
 	GetUploadByIDFunc *StoreGetUploadByIDFunc
+	// GetUploadDeltaFunc is an instance of a mock function object
+	// controlling the behavior of the method GetUploadDelta.
+	GetUploadDeltaFunc *StoreGetUploadDeltaFunc
 	// GetUploadIDsWithReferencesFunc is an instance of a mock function
 	// object controlling the behavior of the method
 	// GetUploadIDsWithReferences.
@@ -157,6 +163,9 @@ type MockStore struct {
 	// InsertUploadFunc is an instance of a mock function object controlling
 	// the behavior of the method InsertUpload.
 	InsertUploadFunc *StoreInsertUploadFunc
+	// InsertUploadDeltaFunc is an instance of a mock function object
+	// controlling the behavior of the method InsertUploadDelta.
+	InsertUploadDeltaFunc *StoreInsertUploadDeltaFunc
 	// MarkFailedFunc is an instance of a mock function object controlling
 	// the behavior of the method MarkFailed.
 	MarkFailedFunc *StoreMarkFailedFunc
@@ -246,6 +255,11 @@ func NewMockStore() *MockStore {
 				return
 			},
 		},
+		CopyPackagesFromUploadFunc: &StoreCopyPackagesFromUploadFunc{
+			defaultHook: func(context.Context, int, int) (r0 error) {
+				return
+			},
+		},
 		DeleteIndexByIDFunc: &StoreDeleteIndexByIDFunc{
 			defaultHook: func(context.Context, int) (r0 bool, r1 error) {
 				return
@@ -386,6 +400,11 @@ func NewMockStore() *MockStore {
 				return
 			},
 		},
+		GetUploadDeltaFunc: &StoreGetUploadDeltaFunc{
+			defaultHook: func(context.Context, int) (r0 shared.UploadDelta, r1 bool, r2 error) {
+				return
+			},
+		},
 		GetUploadIDsWithReferencesFunc: &StoreGetUploadIDsWithReferencesFunc{
 			defaultHook: func(context.Context, []precise.QualifiedMonikerData, []int, int, string, int, int, observation.TraceLogger) (r0 []int, r1 int, r2 int, r3 error) {
 				return
@@ -441,6 +460,11 @@ func NewMockStore() *MockStore {
 				return
 			},
 		},
+		InsertUploadDeltaFunc: &StoreInsertUploadDeltaFunc{
+			defaultHook: func(context.Context, int, shared.UploadDelta) (r0 error) {
+				return
+			},
+		},
 		MarkFailedFunc: &StoreMarkFailedFunc{
 			defaultHook: func(context.Context, int, string) (r0 error) {
 				return
@@ -573,6 +597,11 @@ func NewStrictMockStore() *MockStore {
 				panic("unexpected invocation of MockStore.AddUploadPart")
 			},
 		},
+		CopyPackagesFromUploadFunc: &StoreCopyPackagesFromUploadFunc{
+			defaultHook: func(context.Context, int, int) error {
+				panic("unexpected invocation of MockStore.CopyPackagesFromUpload")
+			},
+		},
 		DeleteIndexByIDFunc: &StoreDeleteIndexByIDFunc{
 			defaultHook: func(context.Context, int) (bool, error) {
 				panic("unexpected invocation of MockStore.DeleteIndexByID")
@@ -713,6 +742,11 @@ func NewStrictMockStore() *MockStore {
 				panic("unexpected invocation of MockStore.GetUploadByID")
 			},
 		},
+		GetUploadDeltaFunc: &StoreGetUploadDeltaFunc{
+			defaultHook: func(context.Context, int) (shared.UploadDelta, bool, error) {
+				panic("unexpected invocation of MockStore.GetUploadDelta")
+			},
+		},
 		GetUploadIDsWithReferencesFunc: &StoreGetUploadIDsWithReferencesFunc{
 			defaultHook: func(context.Context, []precise.QualifiedMonikerData, []int, int, string, int, int, observation.TraceLogger) ([]int, int, int, error) {
 				panic("unexpected invocation of MockStore.GetUploadIDsWithReferences")
@@ -768,6 +802,11 @@ func NewStrictMockStore() *MockStore {
 				panic("unexpected invocation of MockStore.InsertUpload")
 			},
 		},
+		InsertUploadDeltaFunc: &StoreInsertUploadDeltaFunc{
+			defaultHook: func(context.Context, int, shared.UploadDelta) error {
+				panic("unexpected invocation of MockStore.InsertUploadDelta")
+			},
+		},
 		MarkFailedFunc: &StoreMarkFailedFunc{
 			defaultHook: func(context.Context, int, string) error {
 				panic("unexpected invocation of MockStore.MarkFailed")
@@ -898,6 +937,9 @@ func NewMockStoreFrom(i store.Store) *MockStore {
 		AddUploadPartFunc: &StoreAddUploadPartFunc{
 			defaultHook: i.AddUploadPart,
 		},
+		CopyPackagesFromUploadFunc: &StoreCopyPackagesFromUploadFunc{
+			defaultHook: i.CopyPackagesFromUpload,
+		},
 		DeleteIndexByIDFunc: &StoreDeleteIndexByIDFunc{
 			defaultHook: i.DeleteIndexByID,
 		},
@@ -982,6 +1024,9 @@ func NewMockStoreFrom(i store.Store) *MockStore {
 		GetUploadByIDFunc: &StoreGetUploadByIDFunc{
 			defaultHook: i.GetUploadByID,
 		},
+		GetUploadDeltaFunc: &StoreGetUploadDeltaFunc{
+			defaultHook: i.GetUploadDelta,
+		},
 		GetUploadIDsWithReferencesFunc: &StoreGetUploadIDsWithReferencesFunc{
 			defaultHook: i.GetUploadIDsWithReferences,
 		},
@@ -1015,6 +1060,9 @@ func NewMockStoreFrom(i store.Store) *MockStore {
 		InsertUploadFunc: &StoreInsertUploadFunc{
 			defaultHook: i.InsertUpload,
 		},
+		InsertUploadDeltaFunc: &StoreInsertUploadDeltaFunc{
+			defaultHook: i.InsertUploadDelta,
+		},
 		MarkFailedFunc: &StoreMarkFailedFunc{
 			defaultHook: i.MarkFailed,
 		},
@@ -1197,6 +1245,115 @@ func (c StoreAddUploadPartFuncCall) Results() []interface{} {
 	return []interface{}{c.Result0}
 }
 
+// StoreCopyPackagesFromUploadFunc describes the behavior when the
+// CopyPackagesFromUpload method of the parent MockStore instance is
+// invoked.
+type StoreCopyPackagesFromUploadFunc struct {
+	defaultHook func(context.Context, int, int) error
+	hooks       []func(context.Context, int, int) error
+	history     []StoreCopyPackagesFromUploadFuncCall
+	mutex       sync.Mutex
+}
+
+// CopyPackagesFromUpload delegates to the next hook function in the queue
+// and stores the parameter and result values of this invocation.
+func (m *MockStore) CopyPackagesFromUpload(v0 context.Context, v1 int, v2 int) error {
+	r0 := m.CopyPackagesFromUploadFunc.nextHook()(v0, v1, v2)
+	m.CopyPackagesFromUploadFunc.appendCall(StoreCopyPackagesFromUploadFuncCall{v0, v1, v2, r0})
+	return r0
+}
+
+// SetDefaultHook sets function that is called when the
+// CopyPackagesFromUpload method of the parent MockStore instance is invoked
+// and the hook queue is empty.
+func (f *StoreCopyPackagesFromUploadFunc) SetDefaultHook(hook func(context.Context, int, int) error) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// CopyPackagesFromUpload method of the parent MockStore instance invokes
+// the hook at the front of the queue and discards it. After the queue is
+// empty, the default hook function is invoked for any future action.
+func (f *StoreCopyPackagesFromUploadFunc) PushHook(hook func(context.Context, int, int) error) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultHook with a function that returns the
+// given values.
+func (f *StoreCopyPackagesFromUploadFunc) SetDefaultReturn(r0 error) {
+	f.SetDefaultHook(func(context.Context, int, int) error {
+		return r0
+	})
+}
+
+// PushReturn calls PushHook with a function that returns the given values.
+func (f *StoreCopyPackagesFromUploadFunc) PushReturn(r0 error) {
+	f.PushHook(func(context.Context, int, int) error {
+		return r0
+	})
+}
+
+func (f *StoreCopyPackagesFromUploadFunc) nextHook() func(context.Context, int, int) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *StoreCopyPackagesFromUploadFunc) appendCall(r0 StoreCopyPackagesFromUploadFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of StoreCopyPackagesFromUploadFuncCall objects
+// describing the invocations of this function.
+func (f *StoreCopyPackagesFromUploadFunc) History() []StoreCopyPackagesFromUploadFuncCall {
+	f.mutex.Lock()
+	history := make([]StoreCopyPackagesFromUploadFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// StoreCopyPackagesFromUploadFuncCall is an object that describes an
+// invocation of method CopyPackagesFromUpload on an instance of MockStore.
+type StoreCopyPackagesFromUploadFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 int
+	// Arg2 is the value of the 3rd argument passed to this method
+	// invocation.
+	Arg2 int
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c StoreCopyPackagesFromUploadFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1, c.Arg2}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c StoreCopyPackagesFromUploadFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0}
+}
+
 // StoreDeleteIndexByIDFunc describes the behavior when the DeleteIndexByID
 // method of the parent MockStore instance is invoked.
 type StoreDeleteIndexByIDFunc struct {
@@ -4321,6 +4478,117 @@ func (c StoreGetUploadByIDFuncCall) Results() []interface{} {
 	return []interface{}{c.Result0, c.Result1, c.Result2}
 }
 
+// StoreGetUploadDeltaFunc describes the behavior when the GetUploadDelta
+// method of the parent MockStore instance is invoked.
+type StoreGetUploadDeltaFunc struct {
+	defaultHook func(context.Context, int) (shared.UploadDelta, bool, error)
+	hooks       []func(context.Context, int) (shared.UploadDelta, bool, error)
+	history     []StoreGetUploadDeltaFuncCall
+	mutex       sync.Mutex
+}
+
+// GetUploadDelta delegates to the next hook function in the queue and
+// stores the parameter and result values of this invocation.
+func (m *MockStore) GetUploadDelta(v0 context.Context, v1 int) (shared.UploadDelta, bool, error) {
+	r0, r1, r2 := m.GetUploadDeltaFunc.nextHook()(v0, v1)
+	m.GetUploadDeltaFunc.appendCall(StoreGetUploadDeltaFuncCall{v0, v1, r0, r1, r2})
+	return r0, r1, r2
+}
+
+// SetDefaultHook sets function that is called when the GetUploadDelta
+// method of the parent MockStore instance is invoked and the hook queue is
+// empty.
+func (f *StoreGetUploadDeltaFunc) SetDefaultHook(hook func(context.Context, int) (shared.UploadDelta, bool, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// GetUploadDelta method of the parent MockStore instance invokes the hook
+// at the front of the queue and discards it. After the queue is empty, the
+// default hook function is invoked for any future action.
+func (f *StoreGetUploadDeltaFunc) PushHook(hook func(context.Context, int) (shared.UploadDelta, bool, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultHook with a function that returns the
+// given values.
+func (f *StoreGetUploadDeltaFunc) SetDefaultReturn(r0 shared.UploadDelta, r1 bool, r2 error) {
+	f.SetDefaultHook(func(context.Context, int) (shared.UploadDelta, bool, error) {
+		return r0, r1, r2
+	})
+}
+
+// PushReturn calls PushHook with a function that returns the given values.
+func (f *StoreGetUploadDeltaFunc) PushReturn(r0 shared.UploadDelta, r1 bool, r2 error) {
+	f.PushHook(func(context.Context, int) (shared.UploadDelta, bool, error) {
+		return r0, r1, r2
+	})
+}
+
+func (f *StoreGetUploadDeltaFunc) nextHook() func(context.Context, int) (shared.UploadDelta, bool, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *StoreGetUploadDeltaFunc) appendCall(r0 StoreGetUploadDeltaFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of StoreGetUploadDeltaFuncCall objects
+// describing the invocations of this function.
+func (f *StoreGetUploadDeltaFunc) History() []StoreGetUploadDeltaFuncCall {
+	f.mutex.Lock()
+	history := make([]StoreGetUploadDeltaFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// StoreGetUploadDeltaFuncCall is an object that describes an invocation of
+// method GetUploadDelta on an instance of MockStore.
+type StoreGetUploadDeltaFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 int
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 shared.UploadDelta
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 bool
+	// Result2 is the value of the 3rd result returned from this method
+	// invocation.
+	Result2 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c StoreGetUploadDeltaFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c StoreGetUploadDeltaFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1, c.Result2}
+}
+
 // StoreGetUploadIDsWithReferencesFunc describes the behavior when the
 // GetUploadIDsWithReferences method of the parent MockStore instance is
 // invoked.
@@ -5572,6 +5840,114 @@ func (c StoreInsertUploadFuncCall) Results() []interface{} {
 	return []interface{}{c.Result0, c.Result1}
 }
 
+// StoreInsertUploadDeltaFunc describes the behavior when the
+// InsertUploadDelta method of the parent MockStore instance is invoked.
+type StoreInsertUploadDeltaFunc struct {
+	defaultHook func(context.Context, int, shared.UploadDelta) error
+	hooks       []func(context.Context, int, shared.UploadDelta) error
+	history     []StoreInsertUploadDeltaFuncCall
+	mutex       sync.Mutex
+}
+
+// InsertUploadDelta delegates to the next hook function in the queue and
+// stores the parameter and result values of this invocation.
+func (m *MockStore) InsertUploadDelta(v0 context.Context, v1 int, v2 shared.UploadDelta) error {
+	r0 := m.InsertUploadDeltaFunc.nextHook()(v0, v1, v2)
+	m.InsertUploadDeltaFunc.appendCall(StoreInsertUploadDeltaFuncCall{v0, v1, v2, r0})
+	return r0
+}
+
+// SetDefaultHook sets function that is called when the InsertUploadDelta
+// method of the parent MockStore instance is invoked and the hook queue is
+// empty.
+func (f *StoreInsertUploadDeltaFunc) SetDefaultHook(hook func(context.Context, int, shared.UploadDelta) error) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// InsertUploadDelta method of the parent MockStore instance invokes the
+// hook at the front of the queue and discards it. After the queue is empty,
+// the default hook function is invoked for any future action.
+func (f *StoreInsertUploadDeltaFunc) PushHook(hook func(context.Context, int, shared.UploadDelta) error) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultHook with a function that returns the
+// given values.
+func (f *StoreInsertUploadDeltaFunc) SetDefaultReturn(r0 error) {
+	f.SetDefaultHook(func(context.Context, int, shared.UploadDelta) error {
+		return r0
+	})
+}
+
+// PushReturn calls PushHook with a function that returns the given values.
+func (f *StoreInsertUploadDeltaFunc) PushReturn(r0 error) {
+	f.PushHook(func(context.Context, int, shared.UploadDelta) error {
+		return r0
+	})
+}
+
+func (f *StoreInsertUploadDeltaFunc) nextHook() func(context.Context, int, shared.UploadDelta) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *StoreInsertUploadDeltaFunc) appendCall(r0 StoreInsertUploadDeltaFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of StoreInsertUploadDeltaFuncCall objects
+// describing the invocations of this function.
+func (f *StoreInsertUploadDeltaFunc) History() []StoreInsertUploadDeltaFuncCall {
+	f.mutex.Lock()
+	history := make([]StoreInsertUploadDeltaFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// StoreInsertUploadDeltaFuncCall is an object that describes an invocation
+// of method InsertUploadDelta on an instance of MockStore.
+type StoreInsertUploadDeltaFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 int
+	// Arg2 is the value of the 3rd argument passed to this method
+	// invocation.
+	Arg2 shared.UploadDelta
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c StoreInsertUploadDeltaFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1, c.Arg2}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c StoreInsertUploadDeltaFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0}
+}
+
 // StoreMarkFailedFunc describes the behavior when the MarkFailed method of
 // the parent MockStore instance is invoked.
 type StoreMarkFailedFunc struct {
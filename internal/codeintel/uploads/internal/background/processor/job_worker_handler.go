@@ -225,6 +225,11 @@ func (h *handler) HandleRawUpload(ctx context.Context, logger log.Logger, upload
 		return requeued, err
 	}
 
+	delta, requeued, err := h.resolveUploadDelta(ctx, logger, upload)
+	if err != nil || requeued {
+		return requeued, err
+	}
+
 	// Determine if the upload is for the default Git branch.
 	isDefaultBranch, err := h.defaultBranchContains(ctx, repo.Name, upload.Commit)
 	if err != nil {
@@ -281,7 +286,7 @@ func (h *handler) HandleRawUpload(ctx context.Context, logger log.Logger, upload
 
 		// Note: this is writing to a different database than the block below, so we need to use a
 		// different transaction context (managed by the writeData function).
-		pkgData, err := writeSCIPDocuments(ctx, logger, h.lsifStore, upload, scipDataStream, trace)
+		pkgData, err := writeSCIPDocuments(ctx, logger, h.lsifStore, upload, delta, scipDataStream, trace)
 		if err != nil {
 			if isUniqueConstraintViolation(err) {
 				// If this is a unique constraint violation, then we've previously processed this same
@@ -317,6 +322,14 @@ func (h *handler) HandleRawUpload(ctx context.Context, logger log.Logger, upload
 				return errors.Wrap(err, "store.UpdatePackageReferences")
 			}
 
+			if delta != nil {
+				// Package data is only extracted from the documents of the index, so we also carry over
+				// the packages and package references of the base upload to the materialized upload.
+				if err := tx.CopyPackagesFromUpload(ctx, delta.BaseUploadID, upload.ID); err != nil {
+					return errors.Wrap(err, "store.CopyPackagesFromUpload")
+				}
+			}
+
 			// Insert a companion record to this upload that will asynchronously trigger other workers to
 			// sync/create referenced dependency repositories and queue auto-index records for the monikers
 			// written into the lsif_references table attached by this index processing job.
@@ -345,7 +358,8 @@ func inTransaction(ctx context.Context, dbStore store.Store, fn func(tx store.St
 
 // requeueDelay is the delay between processing attempts to process a record when waiting on
 // gitserver to refresh. We'll requeue a record with this delay while the repo is cloning or
-// while we're waiting for a commit to become available to the remote code host.
+// while we're waiting for a commit to become available to the remote code host, as well as while
+// the base upload of a delta upload is still being processed.
 const requeueDelay = time.Minute
 
 // requeueIfCloningOrCommitUnknown ensures that the repo and revision are resolvable. If the repo is currently
@@ -379,6 +393,50 @@ func requeueIfCloningOrCommitUnknown(ctx context.Context, logger log.Logger, git
 	return true, nil
 }
 
+// resolveUploadDelta returns the delta attached to the given upload, or nil if the upload is a full
+// upload. If the base upload of a delta has not yet finished processing, then the upload will be
+// requeued and this function returns a true valued flag. A delta upload whose base upload cannot be
+// used to materialize it (e.g., as it failed to process or has been deleted) is an error.
+func (h *handler) resolveUploadDelta(ctx context.Context, logger log.Logger, upload uploadsshared.Upload) (_ *uploadsshared.UploadDelta, requeued bool, _ error) {
+	delta, ok, err := h.store.GetUploadDelta(ctx, upload.ID)
+	if err != nil {
+		return nil, false, errors.Wrap(err, "store.GetUploadDelta")
+	}
+	if !ok {
+		return nil, false, nil
+	}
+
+	base, ok, err := h.store.GetUploadByID(ctx, delta.BaseUploadID)
+	if err != nil {
+		return nil, false, errors.Wrap(err, "store.GetUploadByID")
+	}
+	if !ok {
+		return nil, false, errors.Newf("base upload %d does not exist", delta.BaseUploadID)
+	}
+	if base.RepositoryID != upload.RepositoryID || base.Root != upload.Root || base.Indexer != upload.Indexer {
+		return nil, false, errors.Newf("base upload %d does not share the repository, root, and indexer of this upload", delta.BaseUploadID)
+	}
+
+	switch base.State {
+	case "completed":
+		return &delta, false, nil
+
+	case "uploading", "queued", "processing":
+		after := time.Now().UTC().Add(requeueDelay)
+
+		if err := h.workerStore.Requeue(ctx, upload.ID, after); err != nil {
+			return nil, false, errors.Wrap(err, "store.Requeue")
+		}
+		logger.Warn("Requeued LSIF upload record",
+			log.Int("id", upload.ID),
+			log.String("reason", "base upload still processing"))
+		return nil, true, nil
+
+	default:
+		return nil, false, errors.Newf("base upload %d is in state %q", delta.BaseUploadID, base.State)
+	}
+}
+
 // NOTE(scip-index-size-stats) In practice, the following seem to be true:
 //   - The size of an uncompressed index is about 5x-10x the size of
 //     the gzip-compressed index
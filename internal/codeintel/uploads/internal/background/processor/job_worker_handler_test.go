@@ -369,6 +369,133 @@ func TestHandleCloneInProgress(t *testing.T) {
 	}
 }
 
+func TestHandleDelta(t *testing.T) {
+	setupRepoMocks(t)
+
+	upload := shared.Upload{
+		ID:           42,
+		Root:         "",
+		Commit:       "deadbeef",
+		RepositoryID: 50,
+		Indexer:      "lsif-go",
+		ContentType:  "application/x-protobuf+scip",
+	}
+
+	mockWorkerStore := NewMockWorkerStore[shared.Upload]()
+	mockDBStore := NewMockStore()
+	mockRepoStore := defaultMockRepoStore()
+	mockLSIFStore := NewMockLSIFStore()
+	mockUploadStore := uploadstoremocks.NewMockStore()
+	gitserverClient := gitserver.NewMockClient()
+
+	// Set default transaction behavior
+	mockDBStore.WithTransactionFunc.SetDefaultHook(func(ctx context.Context, f func(s store.Store) error) error { return f(mockDBStore) })
+	mockLSIFStore.WithTransactionFunc.SetDefaultHook(func(ctx context.Context, f func(s lsifstore.Store) error) error { return f(mockLSIFStore) })
+
+	// Attach a delta to the upload
+	mockDBStore.GetUploadDeltaFunc.SetDefaultReturn(shared.UploadDelta{BaseUploadID: 41, DeletedPaths: []string{"template/src/removed.ts"}}, true, nil)
+	mockDBStore.GetUploadByIDFunc.SetDefaultReturn(shared.Upload{ID: 41, Root: "", RepositoryID: 50, Indexer: "lsif-go", State: "completed"}, true, nil)
+
+	// Track writes to symbols table
+	scipWriter := NewMockLSIFSCIPWriter()
+	mockLSIFStore.NewSCIPDeltaWriterFunc.SetDefaultReturn(scipWriter, nil)
+
+	// Give correlation package a valid input dump
+	mockUploadStore.GetFunc.SetDefaultHook(copyTestDumpScip)
+
+	// Allowlist all files in dump
+	gitserverClient.ListDirectoryChildrenFunc.SetDefaultReturn(scipDirectoryChildren, nil)
+	gitserverClient.CommitDateFunc.SetDefaultReturn("deadbeef", time.Now(), true, nil)
+
+	svc := &handler{
+		store:           mockDBStore,
+		lsifStore:       mockLSIFStore,
+		gitserverClient: gitserverClient,
+		repoStore:       mockRepoStore,
+		workerStore:     mockWorkerStore,
+	}
+
+	requeued, err := svc.HandleRawUpload(context.Background(), logtest.Scoped(t), upload, mockUploadStore, observation.TestTraceLogger(logtest.Scoped(t)))
+	if err != nil {
+		t.Fatalf("unexpected error handling upload: %s", err)
+	} else if requeued {
+		t.Errorf("unexpected requeue")
+	}
+
+	if len(mockLSIFStore.NewSCIPWriterFunc.History()) != 0 {
+		t.Errorf("unexpected number of NewSCIPWriter calls. want=%d have=%d", 0, len(mockLSIFStore.NewSCIPWriterFunc.History()))
+	}
+	if calls := mockLSIFStore.NewSCIPDeltaWriterFunc.History(); len(calls) != 1 {
+		t.Errorf("unexpected number of NewSCIPDeltaWriter calls. want=%d have=%d", 1, len(calls))
+	} else {
+		if calls[0].Arg1 != 42 || calls[0].Arg2 != 41 {
+			t.Errorf("unexpected NewSCIPDeltaWriter upload ids. want=%d,%d have=%d,%d", 42, 41, calls[0].Arg1, calls[0].Arg2)
+		}
+
+		excludedPaths := calls[0].Arg3
+		for _, path := range []string{"template/src/extension.ts", "template/src/removed.ts"} {
+			found := false
+			for _, excludedPath := range excludedPaths {
+				if excludedPath == path {
+					found = true
+				}
+			}
+			if !found {
+				t.Errorf("expected %q to be excluded from the base upload", path)
+			}
+		}
+	}
+
+	if calls := mockDBStore.CopyPackagesFromUploadFunc.History(); len(calls) != 1 {
+		t.Errorf("unexpected number of CopyPackagesFromUpload calls. want=%d have=%d", 1, len(calls))
+	} else if calls[0].Arg1 != 41 || calls[0].Arg2 != 42 {
+		t.Errorf("unexpected CopyPackagesFromUpload upload ids. want=%d,%d have=%d,%d", 41, 42, calls[0].Arg1, calls[0].Arg2)
+	}
+}
+
+func TestHandleDeltaBaseProcessing(t *testing.T) {
+	setupRepoMocks(t)
+
+	upload := shared.Upload{
+		ID:           42,
+		Root:         "",
+		Commit:       "deadbeef",
+		RepositoryID: 50,
+		Indexer:      "lsif-go",
+		ContentType:  "application/x-protobuf+scip",
+	}
+
+	mockWorkerStore := NewMockWorkerStore[shared.Upload]()
+	mockDBStore := NewMockStore()
+	mockRepoStore := defaultMockRepoStore()
+	mockUploadStore := uploadstoremocks.NewMockStore()
+	gitserverClient := gitserver.NewMockClient()
+
+	mockDBStore.GetUploadDeltaFunc.SetDefaultReturn(shared.UploadDelta{BaseUploadID: 41}, true, nil)
+	mockDBStore.GetUploadByIDFunc.SetDefaultReturn(shared.Upload{ID: 41, Root: "", RepositoryID: 50, Indexer: "lsif-go", State: "processing"}, true, nil)
+
+	svc := &handler{
+		store:           mockDBStore,
+		gitserverClient: gitserverClient,
+		repoStore:       mockRepoStore,
+		workerStore:     mockWorkerStore,
+	}
+
+	requeued, err := svc.HandleRawUpload(context.Background(), logtest.Scoped(t), upload, mockUploadStore, observation.TestTraceLogger(logtest.Scoped(t)))
+	if err != nil {
+		t.Fatalf("unexpected error handling upload: %s", err)
+	} else if !requeued {
+		t.Errorf("expected upload to be requeued")
+	}
+
+	if len(mockWorkerStore.RequeueFunc.History()) != 1 {
+		t.Errorf("unexpected number of Requeue calls. want=%d have=%d", 1, len(mockWorkerStore.RequeueFunc.History()))
+	}
+	if len(mockUploadStore.GetFunc.History()) != 0 {
+		t.Errorf("unexpected number of Get calls. want=%d have=%d", 0, len(mockUploadStore.GetFunc.History()))
+	}
+}
+
 //
 //
 
@@ -202,6 +202,7 @@ func prepareSCIPDataStream(
 	return lsifstore.SCIPDataStream{
 		Metadata:         metadata,
 		DocumentIterator: &documentOneShotIterator{ignorePaths, indexSummary, indexReader},
+		DocumentPaths:    indexSummary.relativePaths,
 	}, nil
 }
 
@@ -352,11 +353,15 @@ func packageFromSymbol(symbolName string) (precise.Package, bool) {
 // writeSCIPDocuments iterates over the documents in the index and:
 // - Assembles package information
 // - Writes processed documents into the given store targeting codeintel-db
+//
+// If delta is non-nil, the documents of the delta's base upload which are neither part of the
+// index nor deleted are carried over to the given upload before the index's documents are written.
 func writeSCIPDocuments(
 	ctx context.Context,
 	logger log.Logger,
 	lsifStore lsifstore.Store,
 	upload shared.Upload,
+	delta *shared.UploadDelta,
 	scipDataStream lsifstore.SCIPDataStream,
 	trace observation.TraceLogger,
 ) (pkgData lsifstore.ProcessedPackageData, err error) {
@@ -365,7 +370,18 @@ func writeSCIPDocuments(
 			return err
 		}
 
-		scipWriter, err := tx.NewSCIPWriter(ctx, upload.ID)
+		var (
+			scipWriter lsifstore.SCIPWriter
+			err        error
+		)
+		if delta == nil {
+			scipWriter, err = tx.NewSCIPWriter(ctx, upload.ID)
+		} else {
+			excludedPaths := make([]string, 0, len(scipDataStream.DocumentPaths)+len(delta.DeletedPaths))
+			excludedPaths = append(excludedPaths, scipDataStream.DocumentPaths...)
+			excludedPaths = append(excludedPaths, delta.DeletedPaths...)
+			scipWriter, err = tx.NewSCIPDeltaWriter(ctx, upload.ID, delta.BaseUploadID, excludedPaths)
+		}
 		if err != nil {
 			return err
 		}
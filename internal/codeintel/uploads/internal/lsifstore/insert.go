@@ -31,6 +31,7 @@ import (
 type SCIPDataStream struct {
 	Metadata         ProcessedMetadata
 	DocumentIterator SCIPDocumentVisitor
+	DocumentPaths    []string
 }
 
 type SCIPDocumentVisitor interface {
@@ -101,6 +102,10 @@ VALUES (%s, %s, %s, %s, %s, %s)
 `
 
 func (s *store) NewSCIPWriter(ctx context.Context, uploadID int) (SCIPWriter, error) {
+	return s.newSCIPWriter(ctx, uploadID)
+}
+
+func (s *store) newSCIPWriter(ctx context.Context, uploadID int) (*scipWriter, error) {
 	if !s.db.InTransaction() {
 		return nil, errors.New("WriteSCIPSymbols must be called in a transaction")
 	}
@@ -0,0 +1,132 @@
+package lsifstore
+
+import (
+	"context"
+
+	"github.com/keegancsmith/sqlf"
+	"github.com/lib/pq"
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+)
+
+// NewSCIPDeltaWriter returns a SCIPWriter for a delta upload. Before any document is inserted,
+// the data of the base upload is copied to the given upload: all of its symbol names, and the
+// document references and symbol ranges of every document which is not excluded. The excluded
+// paths should include the paths of all documents of the delta upload as well as the paths of
+// deleted documents.
+//
+// Documents are content-addressed and shared between uploads, so copying a document does not
+// duplicate its payload. The copied symbol names retain their identifiers, and symbol names
+// of documents inserted via the returned writer are assigned identifiers after them. A symbol
+// name may therefore occur twice within the prefix tree of an upload, which lookups tolerate.
+func (s *store) NewSCIPDeltaWriter(ctx context.Context, uploadID, baseUploadID int, excludedPaths []string) (_ SCIPWriter, err error) {
+	ctx, trace, endObservation := s.operations.newSCIPDeltaWriter.With(ctx, &err, observation.Args{Attrs: []attribute.KeyValue{
+		attribute.Int("uploadID", uploadID),
+		attribute.Int("baseUploadID", baseUploadID),
+		attribute.Int("numExcludedPaths", len(excludedPaths)),
+	}})
+	defer endObservation(1, observation.Args{})
+
+	writer, err := s.newSCIPWriter(ctx, uploadID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.db.Exec(ctx, sqlf.Sprintf(copySymbolNamesQuery, uploadID, baseUploadID)); err != nil {
+		return nil, err
+	}
+
+	nextID, _, err := basestore.ScanFirstInt(s.db.Query(ctx, sqlf.Sprintf(nextSymbolNameIDQuery, baseUploadID)))
+	if err != nil {
+		return nil, err
+	}
+	writer.nextID = nextID
+
+	if excludedPaths == nil {
+		excludedPaths = []string{}
+	}
+
+	counts, _, err := scanCopiedDocumentCounts(s.db.Query(ctx, sqlf.Sprintf(
+		copyDocumentsQuery,
+		baseUploadID,
+		pq.Array(excludedPaths),
+		uploadID,
+		uploadID,
+		baseUploadID,
+	)))
+	if err != nil {
+		return nil, err
+	}
+	trace.AddEvent("Copy",
+		attribute.Int("numDocuments", counts[0]),
+		attribute.Int("numSymbols", counts[1]))
+
+	writer.count = uint32(counts[1])
+	return writer, nil
+}
+
+const copySymbolNamesQuery = `
+INSERT INTO codeintel_scip_symbol_names (upload_id, id, name_segment, prefix_id)
+SELECT %s, ssn.id, ssn.name_segment, ssn.prefix_id
+FROM codeintel_scip_symbol_names ssn
+WHERE ssn.upload_id = %s
+`
+
+const nextSymbolNameIDQuery = `
+SELECT COALESCE(MAX(ssn.id) + 1, 0)
+FROM codeintel_scip_symbol_names ssn
+WHERE ssn.upload_id = %s
+`
+
+const copyDocumentsQuery = `
+WITH
+base_lookups AS (
+	SELECT sid.id, sid.document_path, sid.document_id
+	FROM codeintel_scip_document_lookup sid
+	WHERE
+		sid.upload_id = %s AND
+		NOT (sid.document_path = ANY(%s))
+),
+copied_lookups AS (
+	INSERT INTO codeintel_scip_document_lookup (upload_id, document_path, document_id)
+	SELECT %s, bl.document_path, bl.document_id
+	FROM base_lookups bl
+	RETURNING id, document_path
+),
+copied_symbols AS (
+	INSERT INTO codeintel_scip_symbols (
+		upload_id,
+		symbol_id,
+		document_lookup_id,
+		schema_version,
+		definition_ranges,
+		reference_ranges,
+		implementation_ranges,
+		type_definition_ranges
+	)
+	SELECT
+		%s,
+		ss.symbol_id,
+		cl.id,
+		ss.schema_version,
+		ss.definition_ranges,
+		ss.reference_ranges,
+		ss.implementation_ranges,
+		ss.type_definition_ranges
+	FROM copied_lookups cl
+	JOIN base_lookups bl ON bl.document_path = cl.document_path
+	JOIN codeintel_scip_symbols ss ON ss.upload_id = %s AND ss.document_lookup_id = bl.id
+	RETURNING 1
+)
+SELECT
+	(SELECT COUNT(*) FROM copied_lookups),
+	(SELECT COUNT(*) FROM copied_symbols)
+`
+
+var scanCopiedDocumentCounts = basestore.NewFirstScanner(func(s dbutil.Scanner) (counts [2]int, _ error) {
+	err := s.Scan(&counts[0], &counts[1])
+	return counts, err
+})
@@ -4,6 +4,7 @@ import (
 	"context"
 	"testing"
 
+	"github.com/google/go-cmp/cmp"
 	"github.com/sourcegraph/log/logtest"
 	"github.com/sourcegraph/scip/bindings/go/scip"
 
@@ -175,3 +176,79 @@ func TestInsertDocumentWithSymbols(t *testing.T) {
 		t.Fatalf("unexpected number of symbols inserted. want=%d have=%d", expected, n)
 	}
 }
+
+func TestInsertDelta(t *testing.T) {
+	logger := logtest.Scoped(t)
+	codeIntelDB := codeintelshared.NewCodeIntelDB(logger, dbtest.NewDB(t))
+	store := New(&observation.TestContext, codeIntelDB)
+	ctx := context.Background()
+
+	document := func(symbol string) *scip.Document {
+		return &scip.Document{
+			Symbols: []*scip.SymbolInformation{
+				{Symbol: symbol},
+			},
+			Occurrences: []*scip.Occurrence{
+				{
+					Range:       []int32{3, 25, 3, 30},
+					Symbol:      symbol,
+					SymbolRoles: int32(scip.SymbolRole_Definition),
+				},
+			},
+		}
+	}
+
+	if err := store.WithTransaction(ctx, func(tx Store) error {
+		scipWriter24, err := tx.NewSCIPWriter(ctx, 24)
+		if err != nil {
+			t.Fatalf("failed to create SCIP writer: %s", err)
+		}
+		if err := scipWriter24.InsertDocument(ctx, "internal/util.go", document("foo.bar.ident")); err != nil {
+			t.Fatalf("failed to write SCIP document: %s", err)
+		}
+		if err := scipWriter24.InsertDocument(ctx, "internal/util_test.go", document("foo.bar.test")); err != nil {
+			t.Fatalf("failed to write SCIP document: %s", err)
+		}
+		if err := scipWriter24.InsertDocument(ctx, "internal/old.go", document("foo.bar.old")); err != nil {
+			t.Fatalf("failed to write SCIP document: %s", err)
+		}
+		if _, err := scipWriter24.Flush(ctx); err != nil {
+			t.Fatalf("failed to flush SCIP data: %s", err)
+		}
+
+		return nil
+	}); err != nil {
+		t.Fatalf("failed to commit transaction: %s", err)
+	}
+
+	var n uint32
+	if err := store.WithTransaction(ctx, func(tx Store) error {
+		// util_test.go is re-indexed by the delta and old.go has been deleted
+		scipWriter25, err := tx.NewSCIPDeltaWriter(ctx, 25, 24, []string{"internal/util_test.go", "internal/old.go"})
+		if err != nil {
+			t.Fatalf("failed to create SCIP delta writer: %s", err)
+		}
+		if err := scipWriter25.InsertDocument(ctx, "internal/util_test.go", document("foo.bar.newtest")); err != nil {
+			t.Fatalf("failed to write SCIP document: %s", err)
+		}
+		if n, err = scipWriter25.Flush(ctx); err != nil {
+			t.Fatalf("failed to flush SCIP data: %s", err)
+		}
+
+		return nil
+	}); err != nil {
+		t.Fatalf("failed to commit transaction: %s", err)
+	}
+
+	if expected := uint32(2); n != expected {
+		t.Fatalf("unexpected number of symbols inserted. want=%d have=%d", expected, n)
+	}
+
+	paths, err := basestore.ScanStrings(codeIntelDB.Handle().QueryContext(ctx, `SELECT document_path FROM codeintel_scip_document_lookup WHERE upload_id = 25 ORDER BY document_path`))
+	if err != nil {
+		t.Fatalf("failed to query document paths: %s", err)
+	}
+	if diff := cmp.Diff([]string{"internal/util.go", "internal/util_test.go"}, paths); diff != "" {
+		t.Errorf("unexpected document paths (-want +got):\n%s", diff)
+	}
+}
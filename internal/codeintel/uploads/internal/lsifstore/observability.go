@@ -10,6 +10,7 @@ import (
 type operations struct {
 	insertMetadata                            *observation.Operation
 	newSCIPWriter                             *observation.Operation
+	newSCIPDeltaWriter                        *observation.Operation
 	idsWithMeta                               *observation.Operation
 	reconcileCandidates                       *observation.Operation
 	deleteLsifDataByUploadIds                 *observation.Operation
@@ -40,6 +41,7 @@ func newOperations(observationCtx *observation.Context) *operations {
 	return &operations{
 		insertMetadata:                            op("InsertMetadata"),
 		newSCIPWriter:                             op("NewSCIPWriter"),
+		newSCIPDeltaWriter:                        op("NewSCIPDeltaWriter"),
 		idsWithMeta:                               op("IDsWithMeta"),
 		reconcileCandidates:                       op("ReconcileCandidates"),
 		deleteLsifDataByUploadIds:                 op("DeleteLsifDataByUploadIds"),
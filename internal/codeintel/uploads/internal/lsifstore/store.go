@@ -18,6 +18,7 @@ type Store interface {
 	// Insert
 	InsertMetadata(ctx context.Context, uploadID int, meta ProcessedMetadata) error
 	NewSCIPWriter(ctx context.Context, uploadID int) (SCIPWriter, error)
+	NewSCIPDeltaWriter(ctx context.Context, uploadID, baseUploadID int, excludedPaths []string) (SCIPWriter, error)
 
 	// Reconciliation and cleanup
 	IDsWithMeta(ctx context.Context, ids []int) ([]int, error)
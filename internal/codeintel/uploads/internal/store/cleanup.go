@@ -13,7 +13,9 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/observation"
 )
 
-// HardDeleteUploadsByIDs deletes the upload record with the given identifier.
+// HardDeleteUploadsByIDs deletes the upload record with the given identifier. Delta uploads based on
+// a deleted upload which have not yet been processed are marked as errored, as they can no longer be
+// materialized.
 func (s *store) HardDeleteUploadsByIDs(ctx context.Context, ids ...int) (err error) {
 	ctx, _, endObservation := s.operations.hardDeleteUploadsByIDs.With(ctx, &err, observation.Args{Attrs: []attribute.KeyValue{
 		attribute.Int("numIDs", len(ids)),
@@ -41,6 +43,19 @@ locked_uploads AS (
 	WHERE u.id IN (%s)
 	ORDER BY u.id FOR UPDATE
 ),
+errored_delta_uploads AS (
+	UPDATE lsif_uploads u
+	SET
+		state = 'errored',
+		finished_at = NOW(),
+		failure_message = 'base upload ' || d.base_upload_id || ' was deleted'
+	FROM codeintel_upload_deltas d
+	WHERE
+		d.upload_id = u.id AND
+		d.base_upload_id IN (SELECT id FROM locked_uploads) AND
+		u.id NOT IN (SELECT id FROM locked_uploads) AND
+		u.state IN ('uploading', 'queued', 'processing')
+),
 delete_uploads AS (
 	DELETE FROM lsif_uploads WHERE id IN (SELECT id FROM locked_uploads)
 ),
@@ -0,0 +1,112 @@
+package store
+
+import (
+	"context"
+
+	"github.com/keegancsmith/sqlf"
+	"github.com/lib/pq"
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/sourcegraph/sourcegraph/internal/codeintel/uploads/shared"
+	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+)
+
+// InsertUploadDelta marks the given upload as a delta of the given base upload.
+func (s *store) InsertUploadDelta(ctx context.Context, uploadID int, delta shared.UploadDelta) (err error) {
+	ctx, _, endObservation := s.operations.insertUploadDelta.With(ctx, &err, observation.Args{Attrs: []attribute.KeyValue{
+		attribute.Int("uploadID", uploadID),
+		attribute.Int("baseUploadID", delta.BaseUploadID),
+		attribute.Int("numDeletedPaths", len(delta.DeletedPaths)),
+	}})
+	defer endObservation(1, observation.Args{})
+
+	deletedPaths := delta.DeletedPaths
+	if deletedPaths == nil {
+		deletedPaths = []string{}
+	}
+
+	return s.db.Exec(ctx, sqlf.Sprintf(insertUploadDeltaQuery, uploadID, delta.BaseUploadID, pq.Array(deletedPaths)))
+}
+
+const insertUploadDeltaQuery = `
+INSERT INTO codeintel_upload_deltas (upload_id, base_upload_id, deleted_paths)
+VALUES (%s, %s, %s)
+`
+
+// GetUploadDelta returns the base of the given upload. The boolean flag indicates whether
+// the given upload is a delta upload.
+func (s *store) GetUploadDelta(ctx context.Context, uploadID int) (_ shared.UploadDelta, _ bool, err error) {
+	ctx, _, endObservation := s.operations.getUploadDelta.With(ctx, &err, observation.Args{Attrs: []attribute.KeyValue{
+		attribute.Int("uploadID", uploadID),
+	}})
+	defer endObservation(1, observation.Args{})
+
+	return scanFirstUploadDelta(s.db.Query(ctx, sqlf.Sprintf(getUploadDeltaQuery, uploadID)))
+}
+
+const getUploadDeltaQuery = `
+SELECT base_upload_id, deleted_paths
+FROM codeintel_upload_deltas
+WHERE upload_id = %s
+`
+
+var scanFirstUploadDelta = basestore.NewFirstScanner(func(s dbutil.Scanner) (delta shared.UploadDelta, _ error) {
+	err := s.Scan(&delta.BaseUploadID, pq.Array(&delta.DeletedPaths))
+	return delta, err
+})
+
+// CopyPackagesFromUpload attaches the packages defined and referenced by the source upload to
+// the target upload, skipping those which are already attached to the target upload.
+func (s *store) CopyPackagesFromUpload(ctx context.Context, sourceUploadID, targetUploadID int) (err error) {
+	ctx, _, endObservation := s.operations.copyPackagesFromUpload.With(ctx, &err, observation.Args{Attrs: []attribute.KeyValue{
+		attribute.Int("sourceUploadID", sourceUploadID),
+		attribute.Int("targetUploadID", targetUploadID),
+	}})
+	defer endObservation(1, observation.Args{})
+
+	return s.withTransaction(ctx, func(tx *store) error {
+		if err := tx.db.Exec(ctx, sqlf.Sprintf(copyPackagesQuery, targetUploadID, sourceUploadID, targetUploadID)); err != nil {
+			return err
+		}
+
+		return tx.db.Exec(ctx, sqlf.Sprintf(copyPackageReferencesQuery, targetUploadID, sourceUploadID, targetUploadID))
+	})
+}
+
+const copyPackagesQuery = `
+INSERT INTO lsif_packages (dump_id, scheme, manager, name, version)
+SELECT %s, p.scheme, p.manager, p.name, p.version
+FROM lsif_packages p
+WHERE
+	p.dump_id = %s AND
+	NOT EXISTS (
+		SELECT 1
+		FROM lsif_packages e
+		WHERE
+			e.dump_id = %s AND
+			e.scheme = p.scheme AND
+			e.manager = p.manager AND
+			e.name = p.name AND
+			e.version = p.version
+	)
+`
+
+const copyPackageReferencesQuery = `
+INSERT INTO lsif_references (dump_id, scheme, manager, name, version)
+SELECT %s, r.scheme, r.manager, r.name, r.version
+FROM lsif_references r
+WHERE
+	r.dump_id = %s AND
+	NOT EXISTS (
+		SELECT 1
+		FROM lsif_references e
+		WHERE
+			e.dump_id = %s AND
+			e.scheme = r.scheme AND
+			e.manager = r.manager AND
+			e.name = r.name AND
+			e.version = r.version
+	)
+`
@@ -0,0 +1,74 @@
+package store
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/sourcegraph/log/logtest"
+
+	"github.com/sourcegraph/sourcegraph/internal/codeintel/uploads/shared"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbtest"
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+)
+
+func TestUploadDeltas(t *testing.T) {
+	logger := logtest.Scoped(t)
+	db := database.NewDB(logger, dbtest.NewDB(t))
+	store := New(&observation.TestContext, db)
+	ctx := context.Background()
+
+	insertUploads(t, db,
+		shared.Upload{ID: 50, State: "deleting"},
+		shared.Upload{ID: 51, State: "queued"},
+		shared.Upload{ID: 52, State: "completed"},
+	)
+
+	expected := shared.UploadDelta{BaseUploadID: 50, DeletedPaths: []string{"deleted.go"}}
+	if err := store.InsertUploadDelta(ctx, 51, expected); err != nil {
+		t.Fatalf("unexpected error inserting upload delta: %s", err)
+	}
+
+	if delta, ok, err := store.GetUploadDelta(ctx, 51); err != nil {
+		t.Fatalf("unexpected error getting upload delta: %s", err)
+	} else if !ok {
+		t.Fatalf("expected upload 51 to be a delta upload")
+	} else if diff := cmp.Diff(expected, delta); diff != "" {
+		t.Errorf("unexpected upload delta (-want +got):\n%s", diff)
+	}
+
+	if err := store.InsertUploadDelta(ctx, 52, shared.UploadDelta{BaseUploadID: 50}); err != nil {
+		t.Fatalf("unexpected error inserting upload delta: %s", err)
+	}
+
+	if _, ok, err := store.GetUploadDelta(ctx, 50); err != nil {
+		t.Fatalf("unexpected error getting upload delta: %s", err)
+	} else if ok {
+		t.Fatalf("expected upload 50 not to be a delta upload")
+	}
+
+	// Deleting the base upload fails its unprocessed delta uploads, as they can no longer be
+	// materialized, and deletes the deltas of its dependent uploads
+	if err := store.HardDeleteUploadsByIDs(ctx, 50); err != nil {
+		t.Fatalf("unexpected error deleting upload: %s", err)
+	}
+	for _, id := range []int{51, 52} {
+		if _, ok, err := store.GetUploadDelta(ctx, id); err != nil {
+			t.Fatalf("unexpected error getting upload delta: %s", err)
+		} else if ok {
+			t.Fatalf("expected the delta of upload %d to be deleted with its base upload", id)
+		}
+	}
+
+	if upload, _, err := store.GetUploadByID(ctx, 51); err != nil {
+		t.Fatalf("unexpected error getting upload: %s", err)
+	} else if upload.State != "errored" || upload.FailureMessage == nil || *upload.FailureMessage != "base upload 50 was deleted" {
+		t.Errorf("unexpected state of upload 51: state=%q failureMessage=%v", upload.State, upload.FailureMessage)
+	}
+	if upload, _, err := store.GetUploadByID(ctx, 52); err != nil {
+		t.Fatalf("unexpected error getting upload: %s", err)
+	} else if upload.State != "completed" {
+		t.Errorf("unexpected state of upload 52: %q", upload.State)
+	}
+}
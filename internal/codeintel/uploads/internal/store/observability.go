@@ -61,6 +61,11 @@ type operations struct {
 	markFailed                           *observation.Operation
 	deleteUploads                        *observation.Operation
 
+	// Deltas
+	insertUploadDelta      *observation.Operation
+	getUploadDelta         *observation.Operation
+	copyPackagesFromUpload *observation.Operation
+
 	// Dumps
 	findClosestDumps                   *observation.Operation
 	findClosestDumpsFromGraphFragment  *observation.Operation
@@ -163,6 +168,11 @@ func newOperations(observationCtx *observation.Context) *operations {
 		markFailed:                           op("MarkFailed"),
 		deleteUploads:                        op("DeleteUploads"),
 
+		// Deltas
+		insertUploadDelta:      op("InsertUploadDelta"),
+		getUploadDelta:         op("GetUploadDelta"),
+		copyPackagesFromUpload: op("CopyPackagesFromUpload"),
+
 		writeVisibleUploads:        op("writeVisibleUploads"),
 		persistNearestUploads:      op("persistNearestUploads"),
 		persistNearestUploadsLinks: op("persistNearestUploadsLinks"),
@@ -51,6 +51,11 @@ type Store interface {
 	DeleteOverlappingDumps(ctx context.Context, repositoryID int, commit, root, indexer string) error
 	WorkerutilStore(observationCtx *observation.Context) dbworkerstore.Store[shared.Upload]
 
+	// Delta uploads
+	InsertUploadDelta(ctx context.Context, uploadID int, delta shared.UploadDelta) error
+	GetUploadDelta(ctx context.Context, uploadID int) (shared.UploadDelta, bool, error)
+	CopyPackagesFromUpload(ctx context.Context, sourceUploadID, targetUploadID int) error
+
 	// Dependencies
 	ReferencesForUpload(ctx context.Context, uploadID int) (shared.PackageReferenceScanner, error)
 	UpdatePackages(ctx context.Context, dumpID int, packages []precise.Package) error
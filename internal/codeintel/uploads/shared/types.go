@@ -37,6 +37,14 @@ type Upload struct {
 	ShouldReindex     bool
 }
 
+// UploadDelta describes an upload which contains only the documents that changed since
+// its base upload. The remaining documents of the base upload, except for the deleted
+// paths, are copied when the delta upload is processed.
+type UploadDelta struct {
+	BaseUploadID int
+	DeletedPaths []string
+}
+
 func (u Upload) RecordID() int {
 	return u.ID
 }
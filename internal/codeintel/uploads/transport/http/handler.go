@@ -2,6 +2,8 @@ package http
 
 import (
 	"context"
+	"encoding/json"
+	"io"
 	"net/http"
 
 	"github.com/sourcegraph/log"
@@ -40,6 +42,12 @@ func newHandler(
 			return uploads.UploadMetadata{}, statusCode, err
 		}
 
+		baseUploadID := getQueryInt(r, "baseUploadId")
+		deletedPaths, err := deletedPathsFromRequest(r, baseUploadID)
+		if err != nil {
+			return uploads.UploadMetadata{}, http.StatusBadRequest, err
+		}
+
 		contentType := r.Header.Get("Content-Type")
 		if contentType == "" {
 			contentType = "application/x-ndjson+lsif"
@@ -54,6 +62,8 @@ func newHandler(
 			IndexerVersion:    getQuery(r, "indexerVersion"),
 			AssociatedIndexID: getQueryInt(r, "associatedIndexId"),
 			ContentType:       contentType,
+			BaseUploadID:      baseUploadID,
+			DeletedPaths:      deletedPaths,
 		}, 0, nil
 	}
 
@@ -68,6 +78,27 @@ func newHandler(
 	return handler
 }
 
+// maxMultipartSetupPayloadSize is the maximum size of the body of a request preparing a multipart upload.
+const maxMultipartSetupPayloadSize = 64 * 1024 * 1024
+
+// deletedPathsFromRequest returns the paths deleted since the base upload of a delta upload. These are
+// sent in the body of the request preparing a multipart upload, as the list may be too large to fit
+// into the query string. Delta uploads sent in a single request cannot delete paths.
+func deletedPathsFromRequest(r *http.Request, baseUploadID int) ([]string, error) {
+	if baseUploadID == 0 || getQuery(r, "multiPart") == "" {
+		return nil, nil
+	}
+
+	var payload struct {
+		DeletedPaths []string `json:"deletedPaths"`
+	}
+	if err := json.NewDecoder(io.LimitReader(r.Body, maxMultipartSetupPayloadSize)).Decode(&payload); err != nil && err != io.EOF {
+		return nil, errors.Wrap(err, "decoding multipart upload payload")
+	}
+
+	return payload.DeletedPaths, nil
+}
+
 func ensureRepoAndCommitExist(ctx context.Context, repoStore RepoStore, repoName, commit string, logger log.Logger) (int, int, error) {
 	// 🚨 SECURITY: Bypass authz here; we've already determined that the current request is
 	// authorized to view the target repository; they are either a site admin or the code
@@ -8,6 +8,7 @@ import (
 	"net/url"
 	"testing"
 
+	"github.com/google/go-cmp/cmp"
 	"github.com/keegancsmith/sqlf"
 	"github.com/sourcegraph/log/logtest"
 
@@ -164,3 +165,62 @@ func insertTestUser(t *testing.T, db database.DB, name string, isAdmin bool) (us
 	}
 	return userID
 }
+
+func TestHandleEnqueueDeltaDeletedPaths(t *testing.T) {
+	mockDBStore := NewMockDBStore[uploads.UploadMetadata]()
+	mockDBStore.WithTransactionFunc.SetDefaultHook(func(ctx context.Context, f func(tx uploadhandler.DBStore[uploads.UploadMetadata]) error) error {
+		return f(mockDBStore)
+	})
+	mockDBStore.InsertUploadFunc.SetDefaultReturn(42, nil)
+
+	handler := newHandler(
+		testRepoStore{},
+		uploadstoremocks.NewMockStore(),
+		mockDBStore,
+		uploadhandler.NewOperations(&observation.TestContext, "test"),
+	)
+
+	testURL, err := url.Parse("http://test.com/upload")
+	if err != nil {
+		t.Fatalf("unexpected error constructing url: %s", err)
+	}
+	testURL.RawQuery = (url.Values{
+		"commit":       []string{testCommit},
+		"repository":   []string{"github.com/test/test"},
+		"indexerName":  []string{"scip-go"},
+		"baseUploadId": []string{"41"},
+		"multiPart":    []string{"true"},
+		"numParts":     []string{"1"},
+	}).Encode()
+
+	w := httptest.NewRecorder()
+	r, err := http.NewRequest("POST", testURL.String(), bytes.NewReader([]byte(`{"deletedPaths":["a.go","b/c.go"]}`)))
+	if err != nil {
+		t.Fatalf("unexpected error constructing request: %s", err)
+	}
+	handler.ServeHTTP(w, r)
+
+	if w.Code != http.StatusAccepted {
+		t.Fatalf("unexpected status code. want=%d have=%d (%s)", http.StatusAccepted, w.Code, w.Body.String())
+	}
+	if len(mockDBStore.InsertUploadFunc.History()) != 1 {
+		t.Fatalf("unexpected number of InsertUpload calls. want=%d have=%d", 1, len(mockDBStore.InsertUploadFunc.History()))
+	}
+	metadata := mockDBStore.InsertUploadFunc.History()[0].Arg1.Metadata
+	if metadata.BaseUploadID != 41 {
+		t.Errorf("unexpected base upload id. want=%d have=%d", 41, metadata.BaseUploadID)
+	}
+	if diff := cmp.Diff([]string{"a.go", "b/c.go"}, metadata.DeletedPaths); diff != "" {
+		t.Errorf("unexpected deleted paths (-want +got):\n%s", diff)
+	}
+}
+
+type testRepoStore struct{}
+
+func (testRepoStore) GetByName(ctx context.Context, name api.RepoName) (*types.Repo, error) {
+	return &types.Repo{ID: 50, Name: name}, nil
+}
+
+func (testRepoStore) ResolveRev(ctx context.Context, repo *types.Repo, rev string) (api.CommitID, error) {
+	return api.CommitID(rev), nil
+}
@@ -16,6 +16,8 @@ type UploadMetadata struct {
 	IndexerVersion    string
 	AssociatedIndexID int
 	ContentType       string
+	BaseUploadID      int
+	DeletedPaths      []string
 }
 
 type uploadHandlerShim struct {
@@ -36,7 +38,7 @@ func (s *uploadHandlerShim) InsertUpload(ctx context.Context, upload uploadhandl
 		associatedIndexID = &upload.Metadata.AssociatedIndexID
 	}
 
-	return s.Store.InsertUpload(ctx, shared.Upload{
+	record := shared.Upload{
 		ID:                upload.ID,
 		State:             upload.State,
 		NumParts:          upload.NumParts,
@@ -50,7 +52,25 @@ func (s *uploadHandlerShim) InsertUpload(ctx context.Context, upload uploadhandl
 		IndexerVersion:    upload.Metadata.IndexerVersion,
 		AssociatedIndexID: associatedIndexID,
 		ContentType:       upload.Metadata.ContentType,
+	}
+
+	if upload.Metadata.BaseUploadID == 0 {
+		return s.Store.InsertUpload(ctx, record)
+	}
+
+	var id int
+	err := s.Store.WithTransaction(ctx, func(tx store.Store) (err error) {
+		if id, err = tx.InsertUpload(ctx, record); err != nil {
+			return err
+		}
+
+		return tx.InsertUploadDelta(ctx, id, shared.UploadDelta{
+			BaseUploadID: upload.Metadata.BaseUploadID,
+			DeletedPaths: upload.Metadata.DeletedPaths,
+		})
 	})
+
+	return id, err
 }
 
 func (s *uploadHandlerShim) GetUploadByID(ctx context.Context, uploadID int) (uploadhandler.Upload[UploadMetadata], bool, error) {
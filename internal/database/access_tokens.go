@@ -33,6 +33,9 @@ type AccessToken struct {
 	Internal   bool
 	CreatedAt  time.Time
 	LastUsedAt *time.Time
+	// ExpiresAt is the time after which the access token is no longer valid. Tokens
+	// without an expiration time never expire.
+	ExpiresAt *time.Time
 }
 
 // ErrAccessTokenNotFound occurs when a database operation expects a specific access token to exist
@@ -73,6 +76,15 @@ type AccessTokenStore interface {
 	// specified user (i.e., that the actor is either the user or a site admin).
 	Create(ctx context.Context, subjectUserID int32, scopes []string, note string, creatorUserID int32) (id int64, token string, err error)
 
+	// CreateWithExpiration creates an access token for the specified user that is no longer valid
+	// after the given expiration time.
+	//
+	// See the documentation for Create for more details.
+	//
+	// 🚨 SECURITY: The caller must ensure that the actor is permitted to create tokens for the
+	// specified user (i.e., that the actor is either the user or a site admin).
+	CreateWithExpiration(ctx context.Context, subjectUserID int32, scopes []string, note string, creatorUserID int32, expiresAt time.Time) (id int64, token string, err error)
+
 	// CreateInternal creates an *internal* access token for the specified user. An
 	// internal access token will be used by Sourcegraph to talk to its API from
 	// other services, i.e. executor jobs. Internal tokens do not show up in the UI.
@@ -88,6 +100,10 @@ type AccessTokenStore interface {
 	// 🚨 SECURITY: The caller must ensure that the actor is permitted to delete the token.
 	DeleteByID(context.Context, int64) error
 
+	// DeleteExpired deletes (and therefore revokes) all access tokens that expired before the
+	// given time, and returns the deleted access tokens.
+	DeleteExpired(ctx context.Context, now time.Time) ([]*AccessToken, error)
+
 	// DeleteByToken deletes an access token given the secret token value itself (i.e., the same value
 	// that an API client would use to authenticate). The token prefix "sgp_", if present, is stripped.
 	DeleteByToken(ctx context.Context, token string) error
@@ -124,6 +140,34 @@ type AccessTokenStore interface {
 	// non-deleted access token.
 	Lookup(ctx context.Context, token, requiredScope string) (subjectUserID int32, err error)
 
+	// LookupScopes looks up the access token. If it's valid, it returns the subject's user ID and
+	// the token's scopes. Otherwise ErrAccessTokenNotFound is returned. It is the responsibility
+	// of the caller to restrict the subject's privileges to the returned scopes.
+	//
+	// The token prefix "sgp_", if present, is stripped.
+	//
+	// Calling LookupScopes also updates the access token's last-used-at date.
+	//
+	// 🚨 SECURITY: This returns a user ID if and only if the token corresponds to a valid,
+	// non-deleted, and unexpired access token.
+	LookupScopes(ctx context.Context, token string) (subjectUserID int32, scopes []string, err error)
+
+	// ListExpiring lists all access tokens that expire before the given time, and whose subject has
+	// not yet been notified of the upcoming expiration.
+	ListExpiring(ctx context.Context, expiresBefore time.Time) ([]*AccessToken, error)
+
+	// MarkExpirationNotified marks the access token as notified of its upcoming expiration, so that
+	// it is no longer returned by ListExpiring.
+	MarkExpirationNotified(ctx context.Context, id int64) error
+
+	// ListRevokedExpired lists all access tokens revoked by DeleteExpired after the given time, and
+	// whose subject has not yet been notified of the revocation.
+	ListRevokedExpired(ctx context.Context, revokedAfter time.Time) ([]*AccessToken, error)
+
+	// MarkRevocationNotified marks the access token as notified of its revocation, so that it is no
+	// longer returned by ListRevokedExpired.
+	MarkRevocationNotified(ctx context.Context, id int64) error
+
 	WithTransact(context.Context, func(AccessTokenStore) error) error
 	With(basestore.ShareableStore) AccessTokenStore
 	basestore.ShareableStore
@@ -152,11 +196,15 @@ func (s *accessTokenStore) WithTransact(ctx context.Context, f func(AccessTokenS
 }
 
 func (s *accessTokenStore) Create(ctx context.Context, subjectUserID int32, scopes []string, note string, creatorUserID int32) (id int64, token string, err error) {
-	return s.createToken(ctx, subjectUserID, scopes, note, creatorUserID, false)
+	return s.createToken(ctx, subjectUserID, scopes, note, creatorUserID, nil, false)
+}
+
+func (s *accessTokenStore) CreateWithExpiration(ctx context.Context, subjectUserID int32, scopes []string, note string, creatorUserID int32, expiresAt time.Time) (id int64, token string, err error) {
+	return s.createToken(ctx, subjectUserID, scopes, note, creatorUserID, &expiresAt, false)
 }
 
 func (s *accessTokenStore) CreateInternal(ctx context.Context, subjectUserID int32, scopes []string, note string, creatorUserID int32) (id int64, token string, err error) {
-	return s.createToken(ctx, subjectUserID, scopes, note, creatorUserID, true)
+	return s.createToken(ctx, subjectUserID, scopes, note, creatorUserID, nil, true)
 }
 
 // personalAccessTokenPrefix is the token prefix for Sourcegraph personal access tokens. Its purpose
@@ -164,7 +212,7 @@ func (s *accessTokenStore) CreateInternal(ctx context.Context, subjectUserID int
 // Sourcegraph personal access token (vs. some arbitrary high-entropy hex-encoded value).
 const personalAccessTokenPrefix = "sgp_"
 
-func (s *accessTokenStore) createToken(ctx context.Context, subjectUserID int32, scopes []string, note string, creatorUserID int32, internal bool) (id int64, token string, err error) {
+func (s *accessTokenStore) createToken(ctx context.Context, subjectUserID int32, scopes []string, note string, creatorUserID int32, expiresAt *time.Time, internal bool) (id int64, token string, err error) {
 	var b [20]byte
 	if _, err := rand.Read(b[:]); err != nil {
 		return 0, "", err
@@ -176,6 +224,9 @@ func (s *accessTokenStore) createToken(ctx context.Context, subjectUserID int32,
 		// GraphQL API wouldn't let you do so anyway.
 		return 0, "", errors.New("access tokens without scopes are not supported")
 	}
+	if expiresAt != nil && !expiresAt.After(time.Now()) {
+		return 0, "", errors.New("access token expiration time must be in the future")
+	}
 
 	if err := s.Handle().QueryRowContext(ctx,
 		// Include users table query (with "FOR UPDATE") to ensure that subject/creator users have
@@ -188,12 +239,12 @@ creator_user AS (
   SELECT id FROM users WHERE id=$5 AND deleted_at IS NULL FOR UPDATE
 ),
 insert_values AS (
-  SELECT subject_user.id AS subject_user_id, $2::text[] AS scopes, $3::bytea AS value_sha256, $4::text AS note, creator_user.id AS creator_user_id, $6::boolean AS internal
+  SELECT subject_user.id AS subject_user_id, $2::text[] AS scopes, $3::bytea AS value_sha256, $4::text AS note, creator_user.id AS creator_user_id, $6::boolean AS internal, $7::timestamptz AS expires_at
   FROM subject_user, creator_user
 )
-INSERT INTO access_tokens(subject_user_id, scopes, value_sha256, note, creator_user_id, internal) SELECT * FROM insert_values RETURNING id
+INSERT INTO access_tokens(subject_user_id, scopes, value_sha256, note, creator_user_id, internal, expires_at) SELECT * FROM insert_values RETURNING id
 `,
-		subjectUserID, pq.Array(scopes), hashutil.ToSHA256Bytes(b[:]), note, creatorUserID, internal,
+		subjectUserID, pq.Array(scopes), hashutil.ToSHA256Bytes(b[:]), note, creatorUserID, internal, expiresAt,
 	).Scan(&id); err != nil {
 		return 0, "", err
 	}
@@ -201,15 +252,17 @@ INSERT INTO access_tokens(subject_user_id, scopes, value_sha256, note, creator_u
 	// only log access tokens created by users
 	if !internal {
 		arg, err := json.Marshal(struct {
-			SubjectUserId int32    `json:"subject_user_id"`
-			CreatorUserId int32    `json:"creator_user_id"`
-			Scopes        []string `json:"scopes"`
-			Note          string   `json:"note"`
+			SubjectUserId int32      `json:"subject_user_id"`
+			CreatorUserId int32      `json:"creator_user_id"`
+			Scopes        []string   `json:"scopes"`
+			Note          string     `json:"note"`
+			ExpiresAt     *time.Time `json:"expires_at,omitempty"`
 		}{
 			SubjectUserId: subjectUserID,
 			CreatorUserId: creatorUserID,
 			Scopes:        scopes,
 			Note:          note,
+			ExpiresAt:     expiresAt,
 		})
 		if err != nil {
 			s.logger.Error("failed to marshall the access token log argument")
@@ -247,6 +300,7 @@ WHERE t.id IN (
 	JOIN users subject_user ON t2.subject_user_id=subject_user.id AND subject_user.deleted_at IS NULL
 	JOIN users creator_user ON t2.creator_user_id=creator_user.id AND creator_user.deleted_at IS NULL
 	WHERE t2.value_sha256=$1 AND t2.deleted_at IS NULL AND
+	(t2.expires_at IS NULL OR t2.expires_at > now()) AND
 	$2 = ANY (t2.scopes)
 )
 RETURNING t.subject_user_id
@@ -261,6 +315,35 @@ RETURNING t.subject_user_id
 	return subjectUserID, nil
 }
 
+func (s *accessTokenStore) LookupScopes(ctx context.Context, token string) (subjectUserID int32, scopes []string, err error) {
+	tokenHash, err := tokenSHA256Hash(token)
+	if err != nil {
+		return 0, nil, errors.Wrap(err, "AccessTokens.LookupScopes")
+	}
+
+	if err := s.Handle().QueryRowContext(ctx,
+		// Ensure that subject and creator users still exist.
+		`
+UPDATE access_tokens t SET last_used_at=now()
+WHERE t.id IN (
+	SELECT t2.id FROM access_tokens t2
+	JOIN users subject_user ON t2.subject_user_id=subject_user.id AND subject_user.deleted_at IS NULL
+	JOIN users creator_user ON t2.creator_user_id=creator_user.id AND creator_user.deleted_at IS NULL
+	WHERE t2.value_sha256=$1 AND t2.deleted_at IS NULL AND
+	(t2.expires_at IS NULL OR t2.expires_at > now())
+)
+RETURNING t.subject_user_id, t.scopes
+`,
+		tokenHash,
+	).Scan(&subjectUserID, pq.Array(&scopes)); err != nil {
+		if err == sql.ErrNoRows {
+			return 0, nil, ErrAccessTokenNotFound
+		}
+		return 0, nil, err
+	}
+	return subjectUserID, scopes, nil
+}
+
 func (s *accessTokenStore) GetByID(ctx context.Context, id int64) (*AccessToken, error) {
 	return s.get(ctx, []*sqlf.Query{sqlf.Sprintf("id=%d", id)})
 }
@@ -317,7 +400,7 @@ func (s *accessTokenStore) List(ctx context.Context, opt AccessTokensListOptions
 
 func (s *accessTokenStore) list(ctx context.Context, conds []*sqlf.Query, limitOffset *LimitOffset) ([]*AccessToken, error) {
 	q := sqlf.Sprintf(`
-SELECT id, subject_user_id, scopes, note, creator_user_id, internal, created_at, last_used_at FROM access_tokens
+SELECT id, subject_user_id, scopes, note, creator_user_id, internal, created_at, last_used_at, expires_at FROM access_tokens
 WHERE (%s)
 ORDER BY now() - created_at < interval '5 minutes' DESC, -- show recently created tokens first
 last_used_at DESC NULLS FIRST, -- ensure newly created tokens show first
@@ -333,10 +416,14 @@ created_at DESC
 	}
 	defer rows.Close()
 
+	return scanAccessTokens(rows)
+}
+
+func scanAccessTokens(rows *sql.Rows) ([]*AccessToken, error) {
 	var results []*AccessToken
 	for rows.Next() {
 		var t AccessToken
-		if err := rows.Scan(&t.ID, &t.SubjectUserID, pq.Array(&t.Scopes), &t.Note, &t.CreatorUserID, &t.Internal, &t.CreatedAt, &t.LastUsedAt); err != nil {
+		if err := rows.Scan(&t.ID, &t.SubjectUserID, pq.Array(&t.Scopes), &t.Note, &t.CreatorUserID, &t.Internal, &t.CreatedAt, &t.LastUsedAt, &t.ExpiresAt); err != nil {
 			return nil, err
 		}
 		results = append(results, &t)
@@ -375,6 +462,92 @@ func (s *accessTokenStore) DeleteByID(ctx context.Context, id int64) error {
 	return nil
 }
 
+func (s *accessTokenStore) DeleteExpired(ctx context.Context, now time.Time) ([]*AccessToken, error) {
+	rows, err := s.Query(ctx, sqlf.Sprintf(`
+UPDATE access_tokens SET deleted_at=%s
+WHERE deleted_at IS NULL AND expires_at IS NOT NULL AND expires_at <= %s
+RETURNING id, subject_user_id, scopes, note, creator_user_id, internal, created_at, last_used_at, expires_at
+`,
+		now, now,
+	))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tokens, err := scanAccessTokens(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, token := range tokens {
+		arg, err := json.Marshal(struct {
+			AccessTokenId int64     `json:"access_token_id"`
+			ExpiresAt     time.Time `json:"expires_at"`
+		}{AccessTokenId: token.ID, ExpiresAt: *token.ExpiresAt})
+		if err != nil {
+			s.logger.Error("failed to marshall the access token log argument")
+		}
+
+		s.logAccessTokenDeleted(ctx, SecurityEventAccessTokenExpired, arg)
+	}
+
+	return tokens, nil
+}
+
+func (s *accessTokenStore) ListExpiring(ctx context.Context, expiresBefore time.Time) ([]*AccessToken, error) {
+	rows, err := s.Query(ctx, sqlf.Sprintf(`
+SELECT id, subject_user_id, scopes, note, creator_user_id, internal, created_at, last_used_at, expires_at
+FROM access_tokens
+WHERE
+	deleted_at IS NULL AND
+	internal IS FALSE AND
+	expiration_notified_at IS NULL AND
+	expires_at IS NOT NULL AND
+	expires_at <= %s
+ORDER BY id ASC
+`,
+		expiresBefore,
+	))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanAccessTokens(rows)
+}
+
+func (s *accessTokenStore) MarkExpirationNotified(ctx context.Context, id int64) error {
+	return s.Exec(ctx, sqlf.Sprintf("UPDATE access_tokens SET expiration_notified_at=now() WHERE id = %s", id))
+}
+
+func (s *accessTokenStore) ListRevokedExpired(ctx context.Context, revokedAfter time.Time) ([]*AccessToken, error) {
+	rows, err := s.Query(ctx, sqlf.Sprintf(`
+SELECT id, subject_user_id, scopes, note, creator_user_id, internal, created_at, last_used_at, expires_at
+FROM access_tokens
+WHERE
+	deleted_at IS NOT NULL AND
+	deleted_at > %s AND
+	internal IS FALSE AND
+	revocation_notified_at IS NULL AND
+	expires_at IS NOT NULL AND
+	expires_at <= deleted_at
+ORDER BY id ASC
+`,
+		revokedAfter,
+	))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanAccessTokens(rows)
+}
+
+func (s *accessTokenStore) MarkRevocationNotified(ctx context.Context, id int64) error {
+	return s.Exec(ctx, sqlf.Sprintf("UPDATE access_tokens SET revocation_notified_at=now() WHERE id = %s", id))
+}
+
 func (s *accessTokenStore) HardDeleteByID(ctx context.Context, id int64) error {
 	res, err := s.ExecResult(ctx, sqlf.Sprintf("DELETE FROM access_tokens WHERE id = %s", id))
 	if err != nil {
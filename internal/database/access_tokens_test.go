@@ -5,6 +5,7 @@ import (
 	"reflect"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/sourcegraph/log/logtest"
 	"github.com/stretchr/testify/assert"
@@ -34,6 +35,7 @@ func TestAccessTokens(t *testing.T) {
 		t.Run("testAccessTokens_List", testAccessTokens_List)
 		t.Run("testAccessTokens_Lookup", testAccessTokens_Lookup)
 		t.Run("testAccessToken_Lookup_deletedUser", testAccessTokens_Lookup_deletedUser)
+		t.Run("testAccessTokens_Expiration", testAccessTokens_Expiration)
 		t.Run("testAccessTokens_tokenSHA256Hash", testAccessTokens_tokenSHA256Hash)
 	})
 
@@ -386,6 +388,146 @@ func testAccessTokens_Lookup(t *testing.T) {
 	}
 }
 
+// 🚨 SECURITY: This tests that expired access tokens are invalid, and that expiring access tokens
+// are revoked and reported for notification.
+// This test is run in TestAccessTokens
+func testAccessTokens_Expiration(t *testing.T) {
+	t.Parallel()
+	logger := logtest.Scoped(t)
+	db := NewDB(logger, dbtest.NewDB(t))
+	ctx := context.Background()
+
+	subject, err := db.Users().Create(ctx, NewUser{
+		Email:                 "a@example.com",
+		Username:              "u1",
+		Password:              "p1",
+		EmailVerificationCode: "c1",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, _, err := db.AccessTokens().CreateWithExpiration(ctx, subject.ID, []string{"a"}, "n0", subject.ID, time.Now().Add(-time.Hour)); err == nil {
+		t.Fatal("expected error creating access token that has already expired")
+	}
+
+	expiresAt := time.Now().Add(time.Hour)
+	tid0, tv0, err := db.AccessTokens().CreateWithExpiration(ctx, subject.ID, []string{"a", "b"}, "n0", subject.ID, expiresAt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tid1, tv1, err := db.AccessTokens().Create(ctx, subject.ID, []string{"a"}, "n1", subject.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	gotSubjectUserID, gotScopes, err := db.AccessTokens().LookupScopes(ctx, tv0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if gotSubjectUserID != subject.ID {
+		t.Errorf("got %v, want %v", gotSubjectUserID, subject.ID)
+	}
+	if want := []string{"a", "b"}; !reflect.DeepEqual(gotScopes, want) {
+		t.Errorf("got %q, want %q", gotScopes, want)
+	}
+
+	token, err := db.AccessTokens().GetByID(ctx, tid0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if token.ExpiresAt == nil || !token.ExpiresAt.Equal(expiresAt.Truncate(time.Microsecond)) {
+		t.Errorf("got expiration %v, want %v", token.ExpiresAt, expiresAt)
+	}
+
+	// Tokens expiring within the window are listed until they are marked as notified.
+	for i := 0; i < 2; i++ {
+		expiring, err := db.AccessTokens().ListExpiring(ctx, time.Now().Add(2*time.Hour))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(expiring) != 1 || expiring[0].ID != tid0 {
+			t.Errorf("unexpected expiring tokens %v", expiring)
+		}
+	}
+	if err := db.AccessTokens().MarkExpirationNotified(ctx, tid0); err != nil {
+		t.Fatal(err)
+	}
+	expiring, err := db.AccessTokens().ListExpiring(ctx, time.Now().Add(2*time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(expiring) != 0 {
+		t.Errorf("unexpected expiring tokens %v", expiring)
+	}
+
+	// Nothing has expired yet.
+	deleted, err := db.AccessTokens().DeleteExpired(ctx, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(deleted) != 0 {
+		t.Errorf("unexpected deleted tokens %v", deleted)
+	}
+
+	// Simulate the passage of time past the expiration of the first token.
+	if _, err := db.Handle().ExecContext(ctx, "UPDATE access_tokens SET expires_at = now() - interval '1 minute' WHERE id = $1", tid0); err != nil {
+		t.Fatal(err)
+	}
+
+	// Expired tokens are invalid even before they are revoked.
+	if _, _, err := db.AccessTokens().LookupScopes(ctx, tv0); err != ErrAccessTokenNotFound {
+		t.Errorf("got err %v, want %v", err, ErrAccessTokenNotFound)
+	}
+	if _, err := db.AccessTokens().Lookup(ctx, tv0, "a"); err != ErrAccessTokenNotFound {
+		t.Errorf("got err %v, want %v", err, ErrAccessTokenNotFound)
+	}
+
+	deleted, err = db.AccessTokens().DeleteExpired(ctx, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(deleted) != 1 || deleted[0].ID != tid0 {
+		t.Errorf("unexpected deleted tokens %v", deleted)
+	}
+
+	// Revoked tokens are listed until they are marked as notified.
+	for i := 0; i < 2; i++ {
+		revoked, err := db.AccessTokens().ListRevokedExpired(ctx, time.Now().Add(-time.Hour))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(revoked) != 1 || revoked[0].ID != tid0 {
+			t.Errorf("unexpected revoked tokens %v", revoked)
+		}
+	}
+	if revoked, err := db.AccessTokens().ListRevokedExpired(ctx, time.Now().Add(time.Hour)); err != nil {
+		t.Fatal(err)
+	} else if len(revoked) != 0 {
+		t.Errorf("unexpected tokens revoked in the future %v", revoked)
+	}
+	if err := db.AccessTokens().MarkRevocationNotified(ctx, tid0); err != nil {
+		t.Fatal(err)
+	}
+	if revoked, err := db.AccessTokens().ListRevokedExpired(ctx, time.Now().Add(-time.Hour)); err != nil {
+		t.Fatal(err)
+	} else if len(revoked) != 0 {
+		t.Errorf("unexpected revoked tokens %v", revoked)
+	}
+	tokens, err := db.AccessTokens().List(ctx, AccessTokensListOptions{SubjectUserID: subject.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tokens) != 1 || tokens[0].ID != tid1 {
+		t.Errorf("unexpected remaining tokens %v", tokens)
+	}
+
+	// Tokens without expiration remain valid.
+	if _, _, err := db.AccessTokens().LookupScopes(ctx, tv1); err != nil {
+		t.Fatal(err)
+	}
+}
+
 // 🚨 SECURITY: This tests that deleting the subject or creator user of an access token invalidates
 // the token, and that no new access tokens may be created for deleted users.
 // This test is run in TestAccessTokens
@@ -0,0 +1,193 @@
+package database
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/keegancsmith/sqlf"
+	"github.com/lib/pq"
+
+	"github.com/sourcegraph/sourcegraph/internal/audit"
+	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
+	"github.com/sourcegraph/sourcegraph/internal/database/batch"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// AuditLogOutboxEntry is an audit log entry queued for delivery to a sink.
+type AuditLogOutboxEntry struct {
+	ID        int64
+	Sink      string
+	Timestamp time.Time
+	Action    string
+	// Payload is the JSON encoded audit.Entry.
+	Payload  json.RawMessage
+	Attempts int
+}
+
+// AuditLogOutboxStore queues audit log entries in the database until they are
+// delivered to the sinks configured in log.auditLog.sinks, which guarantees that
+// every entry is delivered at least once.
+type AuditLogOutboxStore interface {
+	basestore.ShareableStore
+
+	// Enqueue queues the entries for delivery to each of the sinks with the given
+	// keys, as returned by audit.SinkKeys.
+	Enqueue(ctx context.Context, sinks []string, entries []audit.Entry) error
+
+	// ListUndelivered returns the oldest entries that have not been delivered to
+	// the sink yet, in the order they were queued.
+	ListUndelivered(ctx context.Context, sink string, limit int) ([]*AuditLogOutboxEntry, error)
+
+	// MarkDelivered marks the entries with the given IDs as delivered.
+	MarkDelivered(ctx context.Context, ids []int64) error
+
+	// MarkFailed records a failed delivery attempt of the entries with the given
+	// IDs. The entries are delivered again on the next attempt.
+	MarkFailed(ctx context.Context, ids []int64, message string) error
+
+	// DeleteDelivered deletes all entries delivered before the given time,
+	// returning the number of deleted entries.
+	DeleteDelivered(ctx context.Context, before time.Time) (int64, error)
+
+	// DeleteUndeliveredForRemovedSinks deletes all undelivered entries queued
+	// before the given time for sinks other than the ones with the given keys,
+	// returning the number of deleted entries.
+	DeleteUndeliveredForRemovedSinks(ctx context.Context, sinks []string, before time.Time) (int64, error)
+
+	// CountUndelivered returns the number of entries that have not been delivered
+	// yet by sink.
+	CountUndelivered(ctx context.Context) (map[string]int64, error)
+}
+
+func AuditLogOutboxWith(other basestore.ShareableStore) AuditLogOutboxStore {
+	return &auditLogOutboxStore{Store: basestore.NewWithHandle(other.Handle())}
+}
+
+type auditLogOutboxStore struct {
+	*basestore.Store
+}
+
+var _ AuditLogOutboxStore = &auditLogOutboxStore{}
+
+func (s *auditLogOutboxStore) Enqueue(ctx context.Context, sinks []string, entries []audit.Entry) error {
+	if len(sinks) == 0 || len(entries) == 0 {
+		return nil
+	}
+
+	payloads := make([][]byte, len(entries))
+	for i, entry := range entries {
+		payload, err := json.Marshal(entry)
+		if err != nil {
+			return errors.Wrapf(err, "marshalling audit log entry %q", entry.AuditID)
+		}
+		payloads[i] = payload
+	}
+
+	ch := make(chan []any, len(sinks)*len(entries))
+	for _, sink := range sinks {
+		for i, entry := range entries {
+			ch <- []any{sink, entry.Timestamp, entry.Action, payloads[i]}
+		}
+	}
+	close(ch)
+
+	return batch.InsertValues(
+		ctx,
+		s.Handle(),
+		"audit_log_outbox",
+		batch.MaxNumPostgresParameters,
+		[]string{"sink", "timestamp", "action", "payload"},
+		ch,
+	)
+}
+
+const listUndeliveredAuditLogEntriesFmtstr = `
+SELECT id, sink, timestamp, action, payload, attempts
+FROM audit_log_outbox
+WHERE sink = %s AND delivered_at IS NULL
+ORDER BY id
+LIMIT %s
+`
+
+func (s *auditLogOutboxStore) ListUndelivered(ctx context.Context, sink string, limit int) ([]*AuditLogOutboxEntry, error) {
+	q := sqlf.Sprintf(listUndeliveredAuditLogEntriesFmtstr, sink, limit)
+	return scanAuditLogOutboxEntries(s.Query(ctx, q))
+}
+
+var scanAuditLogOutboxEntries = basestore.NewSliceScanner(func(s dbutil.Scanner) (*AuditLogOutboxEntry, error) {
+	var e AuditLogOutboxEntry
+	if err := s.Scan(&e.ID, &e.Sink, &e.Timestamp, &e.Action, &e.Payload, &e.Attempts); err != nil {
+		return nil, err
+	}
+	return &e, nil
+})
+
+func (s *auditLogOutboxStore) MarkDelivered(ctx context.Context, ids []int64) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	q := sqlf.Sprintf(`
+UPDATE audit_log_outbox
+SET delivered_at = NOW(), attempts = attempts + 1, last_error = NULL
+WHERE id = ANY(%s)
+`, pq.Array(ids))
+	return errors.Wrap(s.Exec(ctx, q), "marking audit log entries as delivered")
+}
+
+func (s *auditLogOutboxStore) MarkFailed(ctx context.Context, ids []int64, message string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	q := sqlf.Sprintf(`
+UPDATE audit_log_outbox
+SET attempts = attempts + 1, last_error = %s
+WHERE id = ANY(%s) AND delivered_at IS NULL
+`, message, pq.Array(ids))
+	return errors.Wrap(s.Exec(ctx, q), "marking audit log entries as failed")
+}
+
+func (s *auditLogOutboxStore) DeleteDelivered(ctx context.Context, before time.Time) (int64, error) {
+	q := sqlf.Sprintf(`
+DELETE FROM audit_log_outbox
+WHERE delivered_at IS NOT NULL AND delivered_at < %s
+`, before)
+	res, err := s.ExecResult(ctx, q)
+	if err != nil {
+		return 0, errors.Wrap(err, "deleting delivered audit log entries")
+	}
+	return res.RowsAffected()
+}
+
+func (s *auditLogOutboxStore) DeleteUndeliveredForRemovedSinks(ctx context.Context, sinks []string, before time.Time) (int64, error) {
+	if sinks == nil {
+		// ANY(NULL) is never false, so no entries would be deleted.
+		sinks = []string{}
+	}
+	q := sqlf.Sprintf(`
+DELETE FROM audit_log_outbox
+WHERE delivered_at IS NULL AND NOT sink = ANY(%s) AND created_at < %s
+`, pq.Array(sinks), before)
+	res, err := s.ExecResult(ctx, q)
+	if err != nil {
+		return 0, errors.Wrap(err, "deleting undelivered audit log entries of removed sinks")
+	}
+	return res.RowsAffected()
+}
+
+func (s *auditLogOutboxStore) CountUndelivered(ctx context.Context) (map[string]int64, error) {
+	q := sqlf.Sprintf(`
+SELECT sink, COUNT(*)
+FROM audit_log_outbox
+WHERE delivered_at IS NULL
+GROUP BY sink
+`)
+	counts, err := scanAuditLogOutboxCounts(s.Query(ctx, q))
+	return counts, errors.Wrap(err, "counting undelivered audit log entries")
+}
+
+var scanAuditLogOutboxCounts = basestore.NewMapScanner(func(s dbutil.Scanner) (sink string, count int64, _ error) {
+	err := s.Scan(&sink, &count)
+	return sink, count, err
+})
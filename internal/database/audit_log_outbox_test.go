@@ -0,0 +1,154 @@
+package database
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/sourcegraph/log/logtest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/audit"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbtest"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+func TestAuditLogOutbox(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	ctx := context.Background()
+	logger := logtest.Scoped(t)
+	db := NewDB(logger, dbtest.NewDB(t))
+	store := db.AuditLogOutbox()
+
+	timestamp := time.Date(2023, 10, 20, 12, 0, 0, 0, time.UTC)
+	entries := []audit.Entry{
+		audit.NewEntry(ctx, "security events", "SignInSucceeded", timestamp, nil),
+		audit.NewEntry(ctx, "security events", "AccessTokenCreated", timestamp.Add(time.Minute), nil),
+	}
+	require.NoError(t, store.Enqueue(ctx, []string{"syslog:a", "file:b"}, entries))
+
+	counts, err := store.CountUndelivered(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int64{"syslog:a": 2, "file:b": 2}, counts)
+
+	syslogEntries, err := store.ListUndelivered(ctx, "syslog:a", 10)
+	require.NoError(t, err)
+	require.Len(t, syslogEntries, 2)
+	assert.Equal(t, "SignInSucceeded", syslogEntries[0].Action)
+	assert.Equal(t, "AccessTokenCreated", syslogEntries[1].Action)
+	assert.True(t, timestamp.Equal(syslogEntries[0].Timestamp))
+
+	var payload audit.Entry
+	require.NoError(t, json.Unmarshal(syslogEntries[0].Payload, &payload))
+	assert.Equal(t, entries[0].AuditID, payload.AuditID)
+
+	limited, err := store.ListUndelivered(ctx, "syslog:a", 1)
+	require.NoError(t, err)
+	require.Len(t, limited, 1)
+	assert.Equal(t, syslogEntries[0].ID, limited[0].ID)
+
+	// A failed delivery leaves the entries queued.
+	require.NoError(t, store.MarkFailed(ctx, []int64{syslogEntries[0].ID}, "connection refused"))
+	syslogEntries, err = store.ListUndelivered(ctx, "syslog:a", 10)
+	require.NoError(t, err)
+	require.Len(t, syslogEntries, 2)
+	assert.Equal(t, 1, syslogEntries[0].Attempts)
+
+	// Delivering entries to one sink does not affect the others.
+	require.NoError(t, store.MarkDelivered(ctx, []int64{syslogEntries[0].ID, syslogEntries[1].ID}))
+	syslogEntries, err = store.ListUndelivered(ctx, "syslog:a", 10)
+	require.NoError(t, err)
+	assert.Empty(t, syslogEntries)
+
+	counts, err = store.CountUndelivered(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int64{"file:b": 2}, counts)
+
+	deleted, err := store.DeleteDelivered(ctx, time.Now().Add(-time.Hour))
+	require.NoError(t, err)
+	assert.Zero(t, deleted)
+
+	deleted, err = store.DeleteDelivered(ctx, time.Now().Add(time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), deleted)
+
+	// Undelivered entries of sinks that were removed are deleted after the
+	// retention period.
+	require.NoError(t, store.Enqueue(ctx, []string{"s3:c"}, entries))
+
+	deleted, err = store.DeleteUndeliveredForRemovedSinks(ctx, []string{"file:b"}, time.Now().Add(-time.Hour))
+	require.NoError(t, err)
+	assert.Zero(t, deleted)
+
+	deleted, err = store.DeleteUndeliveredForRemovedSinks(ctx, []string{"file:b"}, time.Now().Add(time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), deleted)
+
+	counts, err = store.CountUndelivered(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int64{"file:b": 2}, counts)
+}
+
+func TestSecurityEventLogs_AuditLogSinks(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	prevConf := conf.Get()
+	t.Cleanup(func() {
+		conf.Mock(prevConf)
+	})
+	conf.Mock(&conf.Unified{SiteConfiguration: schema.SiteConfiguration{
+		Log: &schema.Log{
+			SecurityEventLog: &schema.SecurityEventLog{Location: "auditlog"},
+			AuditLog: &schema.AuditLog{Sinks: []*schema.AuditLogSink{
+				{File: &schema.FileAuditLogSink{Type: "file", Path: "/var/log/audit.jsonl"}},
+			}},
+		},
+	}})
+
+	logger := logtest.Scoped(t)
+	db := NewDB(logger, dbtest.NewDB(t))
+	ctx := actor.WithActor(context.Background(), &actor.Actor{UID: 1})
+
+	require.NoError(t, db.SecurityEventLogs().Insert(ctx, &SecurityEvent{
+		Name:     SecurityEventAccessTokenCreated,
+		URL:      "http://sourcegraph.com",
+		UserID:   1,
+		Source:   "BACKEND",
+		Argument: json.RawMessage(`{"scopes":["user:all"]}`),
+	}))
+
+	entries, err := db.AuditLogOutbox().ListUndelivered(ctx, "file:/var/log/audit.jsonl", 10)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, string(SecurityEventAccessTokenCreated), entries[0].Action)
+
+	var payload struct {
+		Entity string `json:"entity"`
+		Actor  struct {
+			ActorUID string `json:"actorUID"`
+		} `json:"actor"`
+		Event struct {
+			UserID   uint32          `json:"UserID"`
+			Argument json.RawMessage `json:"argument"`
+		} `json:"event"`
+	}
+	require.NoError(t, json.Unmarshal(entries[0].Payload, &payload))
+	assert.Equal(t, "security events", payload.Entity)
+	assert.Equal(t, "1", payload.Actor.ActorUID)
+	assert.Equal(t, uint32(1), payload.Event.UserID)
+	assert.JSONEq(t, `{"scopes":["user:all"]}`, string(payload.Event.Argument))
+
+	// Events are not stored in the database when only the audit log is configured.
+	var count int
+	require.NoError(t, db.QueryRowContext(ctx, "SELECT COUNT(*) FROM security_event_logs").Scan(&count))
+	assert.Zero(t, count)
+}
@@ -24,6 +24,7 @@ type DB interface {
 
 	AccessRequests() AccessRequestStore
 	AccessTokens() AccessTokenStore
+	AuditLogOutbox() AuditLogOutboxStore
 	Authz() AuthzStore
 	BitbucketProjectPermissions() BitbucketProjectPermissionsStore
 	CodeMonitors() CodeMonitorStore
@@ -60,6 +61,8 @@ type DB interface {
 	RolePermissions() RolePermissionStore
 	Roles() RoleStore
 	SavedSearches() SavedSearchStore
+	ScopedUserRoles() ScopedUserRoleStore
+	SCIMGroups() SCIMGroupStore
 	SearchContexts() SearchContextsStore
 	Settings() SettingsStore
 	SubRepoPerms() SubRepoPermsStore
@@ -69,6 +72,7 @@ type DB interface {
 	UserEmails() UserEmailsStore
 	UserExternalAccounts() UserExternalAccountsStore
 	UserRoles() UserRoleStore
+	UserSecondFactors(encryption.Key) UserSecondFactorsStore
 	Users() UserStore
 	WebhookLogs(encryption.Key) WebhookLogStore
 	Webhooks(encryption.Key) WebhookStore
@@ -138,6 +142,10 @@ func (d *db) AccessTokens() AccessTokenStore {
 	return AccessTokensWith(d.Store, d.logger.Scoped("AccessTokenStore", ""))
 }
 
+func (d *db) AuditLogOutbox() AuditLogOutboxStore {
+	return AuditLogOutboxWith(d.Store)
+}
+
 func (d *db) AccessRequests() AccessRequestStore {
 	return AccessRequestsWith(d.Store, d.logger.Scoped("AccessRequestStore", ""))
 }
@@ -321,10 +329,22 @@ func (d *db) UserExternalAccounts() UserExternalAccountsStore {
 	return ExternalAccountsWith(d.logger, d.Store)
 }
 
+func (d *db) ScopedUserRoles() ScopedUserRoleStore {
+	return ScopedUserRolesWith(d.Store)
+}
+
+func (d *db) SCIMGroups() SCIMGroupStore {
+	return SCIMGroupsWith(d.Store)
+}
+
 func (d *db) UserRoles() UserRoleStore {
 	return UserRolesWith(d.Store)
 }
 
+func (d *db) UserSecondFactors(key encryption.Key) UserSecondFactorsStore {
+	return UserSecondFactorsWith(d.Store, key)
+}
+
 func (d *db) Users() UserStore {
 	return UsersWith(d.logger, d.Store)
 }
@@ -129,6 +129,107 @@ func (s *RecordEncrypter) DecryptBatch(ctx context.Context, config EncryptionCon
 	return len(decryptedValues), nil
 }
 
+// CountWithInactiveKey returns the number of records encrypted with a key or key
+// version other than the active key of the given config.
+func (s *RecordEncrypter) CountWithInactiveKey(ctx context.Context, config EncryptionConfig) (int, error) {
+	activeKeyID, err := config.ActiveKeyID(ctx)
+	if err != nil || activeKeyID == "" {
+		return 0, err
+	}
+
+	count, _, err := basestore.ScanFirstInt(s.Query(ctx, sqlf.Sprintf(
+		"SELECT COUNT(*) FROM %s WHERE %s NOT IN ('', %s, %s)",
+		quote(config.TableName),
+		quote(config.KeyIDFieldName),
+		encryption.UnmigratedEncryptionKeyID,
+		activeKeyID,
+	)))
+	return count, err
+}
+
+// ReencryptBatchResult is the result of re-encrypting a batch of records.
+type ReencryptBatchResult struct {
+	// LastID is the ID of the last record of the batch, or zero if there are no
+	// more records to re-encrypt after the given ID.
+	LastID int
+	// Reencrypted is the number of records re-encrypted with the active key.
+	Reencrypted int
+	// Failed is the number of records that could not be decrypted with the active
+	// key, e.g. because they were encrypted with a different key altogether.
+	Failed int
+}
+
+// ReencryptBatch re-encrypts a batch of records with an ID greater than afterID
+// that are encrypted with a key or key version other than the active key of the
+// given config. Records that cannot be decrypted are skipped, so that walking the
+// table by ID always makes progress.
+func (s *RecordEncrypter) ReencryptBatch(ctx context.Context, config EncryptionConfig, afterID int) (result ReencryptBatchResult, err error) {
+	key := config.Key()
+	if key == nil {
+		return result, nil
+	}
+	activeKeyID, err := config.ActiveKeyID(ctx)
+	if err != nil {
+		return result, err
+	}
+
+	tx, err := s.Transact(ctx)
+	if err != nil {
+		return result, err
+	}
+	defer func() { err = tx.Done(err) }()
+
+	values, err := config.Scan(tx.Query(ctx, sqlf.Sprintf(
+		"SELECT %s FROM %s WHERE %s NOT IN ('', %s, %s) AND %s > %s ORDER BY %s ASC LIMIT %s FOR UPDATE SKIP LOCKED",
+		fields(config),
+		quote(config.TableName),
+		quote(config.KeyIDFieldName),
+		encryption.UnmigratedEncryptionKeyID,
+		activeKeyID,
+		quote(config.IDFieldName),
+		afterID,
+		quote(config.IDFieldName),
+		config.Limit,
+	)))
+	if err != nil {
+		return result, err
+	}
+
+	decrypted := make(map[int][]string, len(values))
+	for id, ev := range values {
+		if id > result.LastID {
+			result.LastID = id
+		}
+
+		vs, err := decryptValues(ctx, key, map[int]Encrypted{id: ev})
+		if err != nil {
+			result.Failed++
+			continue
+		}
+		decrypted[id] = vs[id]
+	}
+
+	encryptedValues, err := encryptValues(ctx, key, decrypted)
+	if err != nil {
+		return result, err
+	}
+
+	for id, ev := range encryptedValues {
+		if err := tx.Exec(ctx, sqlf.Sprintf(
+			"UPDATE %s SET %s WHERE %s = %s",
+			quote(config.TableName),
+			updatePairs(config, ev),
+			quote(config.IDFieldName),
+			id,
+		)); err != nil {
+			return result, err
+		}
+	}
+
+	result.Reencrypted = len(encryptedValues)
+	return result, nil
+}
+
 func fields(c EncryptionConfig) *sqlf.Query {
 	names := make([]*sqlf.Query, 0, len(c.EncryptedFieldNames)+2)
 	names = append(names, quote(c.IDFieldName), quote(c.KeyIDFieldName))
@@ -1,10 +1,13 @@
 package database
 
 import (
+	"context"
+
 	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
 	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
 	"github.com/sourcegraph/sourcegraph/internal/encryption"
 	"github.com/sourcegraph/sourcegraph/internal/encryption/keyring"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
 )
 
 type EncryptionConfig struct {
@@ -19,6 +22,21 @@ type EncryptionConfig struct {
 	Limit               int
 }
 
+// ActiveKeyID returns the identifier of the key and key version new values of the
+// table are encrypted with, or an empty string if the table is not encrypted.
+func (c EncryptionConfig) ActiveKeyID(ctx context.Context) (string, error) {
+	key := c.Key()
+	if key == nil {
+		return "", nil
+	}
+
+	version, err := key.Version(ctx)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to get encryption key version")
+	}
+	return version.JSON(), nil
+}
+
 var EncryptionConfigs = []EncryptionConfig{
 	externalServicesEncryptionConfig,
 	userExternalAccountsEncryptionConfig,
@@ -27,6 +45,8 @@ var EncryptionConfigs = []EncryptionConfig{
 	webhooklogsEncryptionConfig,
 	executorSecretsEncryptionConfig,
 	outboundWebhooksEncryptionConfig,
+	userTOTPCredentialsEncryptionConfig,
+	userRecoveryCodesEncryptionConfig,
 }
 
 var externalServicesEncryptionConfig = EncryptionConfig{
@@ -103,6 +123,28 @@ var outboundWebhooksEncryptionConfig = EncryptionConfig{
 	Limit:               5,
 }
 
+var userTOTPCredentialsEncryptionConfig = EncryptionConfig{
+	TableName:           "user_totp_credentials",
+	IDFieldName:         "id",
+	KeyIDFieldName:      "encryption_key_id",
+	EncryptedFieldNames: []string{"secret"},
+	UpdateAsBytes:       true,
+	Scan:                basestore.NewMapScanner(scanEncryptedBytea),
+	Key:                 func() encryption.Key { return keyring.Default().UserSecondFactorKey },
+	Limit:               100,
+}
+
+var userRecoveryCodesEncryptionConfig = EncryptionConfig{
+	TableName:           "user_recovery_codes",
+	IDFieldName:         "id",
+	KeyIDFieldName:      "encryption_key_id",
+	EncryptedFieldNames: []string{"code"},
+	UpdateAsBytes:       true,
+	Scan:                basestore.NewMapScanner(scanEncryptedBytea),
+	Key:                 func() encryption.Key { return keyring.Default().UserSecondFactorKey },
+	Limit:               100,
+}
+
 func scanEncryptedString(scanner dbutil.Scanner) (id int, e Encrypted, err error) {
 	e.Values = make([]string, 1)
 	err = scanner.Scan(&id, &e.KeyID, &e.Values[0])
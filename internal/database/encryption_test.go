@@ -144,16 +144,131 @@ func TestRecordEncrypter(t *testing.T) {
 	}
 }
 
-type base64Key struct{}
+func TestRecordEncrypter_Reencrypt(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	ctx := context.Background()
+	logger := logtest.Scoped(t)
+	db := NewDB(logger, dbtest.NewDB(t))
+	key := &base64Key{}
+	encrypter := NewRecordEncrypter(db)
+
+	if err := encrypter.Exec(ctx, sqlf.Sprintf("CREATE TABLE test_encryptable (id int, encryption_key_id text, data text)")); err != nil {
+		t.Fatalf("failed to create test table: %s", err)
+	}
+
+	config := EncryptionConfig{
+		TableName:           "test_encryptable",
+		IDFieldName:         "id",
+		KeyIDFieldName:      "encryption_key_id",
+		EncryptedFieldNames: []string{"data"},
+		Scan:                basestore.NewMapScanner(scanNullableEncryptedString),
+		TreatEmptyAsNull:    true,
+		Key:                 func() encryption.Key { return key },
+		Limit:               5,
+	}
+
+	// Records 1-8 are encrypted with the current version of the key, record 9 with an
+	// unknown key and record 10 is not encrypted.
+	for i := 1; i <= 8; i++ {
+		data := base64.StdEncoding.EncodeToString([]byte(fmt.Sprintf("data-%02d", i)))
+		if err := encrypter.Exec(ctx, sqlf.Sprintf("INSERT INTO test_encryptable VALUES (%s, %s, %s)", i, testEncryptionKeyID(key), data)); err != nil {
+			t.Fatalf("failed to insert test data: %s", err)
+		}
+	}
+	if err := encrypter.Exec(ctx, sqlf.Sprintf("INSERT INTO test_encryptable VALUES (9, 'unknown', '!not base64!'), (10, '', 'data-10')")); err != nil {
+		t.Fatalf("failed to insert test data: %s", err)
+	}
+
+	// Nothing to do while the key is not rotated.
+	result, err := encrypter.ReencryptBatch(ctx, config, 0)
+	if err != nil {
+		t.Fatalf("unexpected error re-encrypting batch: %s", err)
+	}
+	if diff := cmp.Diff(ReencryptBatchResult{}, result); diff != "" {
+		t.Errorf("unexpected result (-want +got):\n%s", diff)
+	}
+
+	oldKeyID := testEncryptionKeyID(key)
+	key.version = "1-test"
+
+	count, err := encrypter.CountWithInactiveKey(ctx, config)
+	if err != nil {
+		t.Fatalf("unexpected error counting records: %s", err)
+	}
+	if count != 9 {
+		t.Errorf("unexpected count. want=%d have=%d", 9, count)
+	}
+
+	var results []ReencryptBatchResult
+	for afterID := 0; ; {
+		result, err := encrypter.ReencryptBatch(ctx, config, afterID)
+		if err != nil {
+			t.Fatalf("unexpected error re-encrypting batch: %s", err)
+		}
+		results = append(results, result)
+		if result.LastID == 0 {
+			break
+		}
+		afterID = result.LastID
+	}
+	if diff := cmp.Diff([]ReencryptBatchResult{
+		{LastID: 5, Reencrypted: 5},
+		{LastID: 9, Reencrypted: 3, Failed: 1},
+		{},
+	}, results); diff != "" {
+		t.Errorf("unexpected results (-want +got):\n%s", diff)
+	}
+
+	// Only the record that cannot be decrypted is left with an inactive key.
+	count, err = encrypter.CountWithInactiveKey(ctx, config)
+	if err != nil {
+		t.Fatalf("unexpected error counting records: %s", err)
+	}
+	if count != 1 {
+		t.Errorf("unexpected count. want=%d have=%d", 1, count)
+	}
+
+	keyIDs, err := basestore.ScanStrings(encrypter.Query(ctx, sqlf.Sprintf("SELECT encryption_key_id FROM test_encryptable WHERE id <= 8")))
+	if err != nil {
+		t.Fatalf("failed to query encryption keys: %s", err)
+	}
+	for _, keyID := range keyIDs {
+		if keyID == oldKeyID {
+			t.Errorf("unexpected key identifier. want=%q have=%q", testEncryptionKeyID(key), keyID)
+		}
+	}
+
+	data, err := basestore.ScanStrings(encrypter.Query(ctx, sqlf.Sprintf("SELECT data FROM test_encryptable WHERE id = 1")))
+	if err != nil {
+		t.Fatalf("failed to query data: %s", err)
+	}
+	if want := base64.StdEncoding.EncodeToString([]byte("data-01")); len(data) != 1 || data[0] != want {
+		t.Errorf("unexpected data. want=%q have=%v", want, data)
+	}
+}
+
+type base64Key struct {
+	version string
+}
 
 func (k *base64Key) Version(ctx context.Context) (encryption.KeyVersion, error) {
 	return encryption.KeyVersion{
 		Type:    "base64",
 		Name:    "base64",
-		Version: "0-test",
+		Version: k.versionOrDefault(),
 	}, nil
 }
 
+func (k *base64Key) versionOrDefault() string {
+	if k.version == "" {
+		return "0-test"
+	}
+	return k.version
+}
+
 func (k *base64Key) Encrypt(ctx context.Context, value []byte) ([]byte, error) {
 	return []byte(base64.StdEncoding.EncodeToString(value)), nil
 }
@@ -7,6 +7,7 @@ import (
 
 	"github.com/keegancsmith/sqlf"
 
+	"github.com/sourcegraph/sourcegraph/internal/api"
 	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
 	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
 	rtypes "github.com/sourcegraph/sourcegraph/internal/rbac/types"
@@ -56,6 +57,18 @@ type GetPermissionForUserOpts struct {
 
 	Namespace rtypes.PermissionNamespace
 	Action    rtypes.NamespaceAction
+
+	// Resource, if set, is the resource the permission is needed for. In addition to
+	// roles assigned to the user globally, roles assigned to the user for a scope that
+	// contains the resource are considered.
+	Resource *PermissionResource
+}
+
+// PermissionResource describes a resource guarded by a permission by the organization and
+// repository it belongs to. Zero values mean the resource doesn't belong to one.
+type PermissionResource struct {
+	OrgID  int32
+	RepoID api.RepoID
 }
 
 type CreatePermissionOpts struct {
@@ -115,6 +128,43 @@ WHERE permissions.action = %s AND permissions.namespace = %s AND user_roles.user
 LIMIT 1
 `
 
+// getScopedPermissionForUserQuery additionally considers the roles assigned to the user for a
+// scope that contains the resource: the organization the resource belongs to, the repository
+// itself, or a search context whose repositories included the resource's repository when the
+// role was assigned.
+//
+// 🚨 SECURITY: The repositories of search contexts are not read from the search contexts
+// themselves, as any user owning a search context can change its repositories.
+const getScopedPermissionForUserQuery = `
+SELECT %s FROM permissions
+INNER JOIN role_permissions ON role_permissions.permission_id = permissions.id
+WHERE permissions.action = %s AND permissions.namespace = %s AND (
+	EXISTS (
+		SELECT 1 FROM user_roles
+		WHERE user_roles.role_id = role_permissions.role_id AND user_roles.user_id = %s
+	)
+	OR EXISTS (
+		SELECT 1 FROM scoped_user_roles
+		WHERE
+			scoped_user_roles.role_id = role_permissions.role_id
+			AND scoped_user_roles.user_id = %s
+			AND (
+				scoped_user_roles.org_id = %s
+				OR scoped_user_roles.repo_id = %s
+				OR EXISTS (
+					SELECT 1 FROM scoped_user_role_repos
+					JOIN search_contexts ON search_contexts.id = scoped_user_roles.search_context_id
+					WHERE
+						scoped_user_role_repos.scoped_user_role_id = scoped_user_roles.id
+						AND scoped_user_role_repos.repo_id = %s
+						AND search_contexts.deleted_at IS NULL
+				)
+			)
+	)
+)
+LIMIT 1
+`
+
 func (p *permissionStore) GetPermissionForUser(ctx context.Context, opts GetPermissionForUserOpts) (*types.Permission, error) {
 	if opts.UserID == 0 {
 		return nil, errors.New("missing user id")
@@ -135,6 +185,19 @@ func (p *permissionStore) GetPermissionForUser(ctx context.Context, opts GetPerm
 		opts.Namespace,
 		opts.UserID,
 	)
+	if opts.Resource != nil {
+		q = sqlf.Sprintf(
+			getScopedPermissionForUserQuery,
+			sqlf.Join(permissionColumns, ", "),
+			opts.Action,
+			opts.Namespace,
+			opts.UserID,
+			opts.UserID,
+			opts.Resource.OrgID,
+			opts.Resource.RepoID,
+			opts.Resource.RepoID,
+		)
+	}
 
 	permission, err := scanPermission(p.QueryRow(ctx, q))
 	if err != nil {
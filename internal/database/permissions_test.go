@@ -409,6 +409,137 @@ func TestGetPermissionForUser(t *testing.T) {
 	})
 }
 
+func TestGetPermissionForUserWithResource(t *testing.T) {
+	ctx := context.Background()
+	logger := logtest.Scoped(t)
+	db := NewDB(logger, dbtest.NewDB(t))
+	store := db.Permissions()
+
+	user, role := createUserAndRole(ctx, t, db)
+
+	p, err := db.Permissions().Create(ctx, CreatePermissionOpts{
+		Namespace: rtypes.BatchChangesNamespace,
+		Action:    rtypes.BatchChangesWriteAction,
+	})
+	require.NoError(t, err)
+
+	err = db.RolePermissions().Assign(ctx, AssignRolePermissionOpts{
+		RoleID:       role.ID,
+		PermissionID: p.ID,
+	})
+	require.NoError(t, err)
+
+	org1, err := db.Orgs().Create(ctx, "org-1", nil)
+	require.NoError(t, err)
+	org2, err := db.Orgs().Create(ctx, "org-2", nil)
+	require.NoError(t, err)
+
+	repo1, _ := createTestRepo(ctx, t, db, &createTestRepoPayload{Name: "github.com/sourcegraph/repo-1"})
+	repo2, _ := createTestRepo(ctx, t, db, &createTestRepoPayload{Name: "github.com/sourcegraph/repo-2"})
+	repo3, _ := createTestRepo(ctx, t, db, &createTestRepoPayload{Name: "github.com/sourcegraph/repo-3"})
+	repo4, _ := createTestRepo(ctx, t, db, &createTestRepoPayload{Name: "github.com/sourcegraph/repo-4"})
+	repo5, _ := createTestRepo(ctx, t, db, &createTestRepoPayload{Name: "github.com/sourcegraph/repo-5"})
+
+	sc, err := db.SearchContexts().CreateSearchContextWithRepositoryRevisions(ctx, &types.SearchContext{Name: "ctx"}, []*types.SearchContextRepositoryRevisions{
+		{Repo: types.MinimalRepo{ID: repo2.ID, Name: repo2.Name}, Revisions: []string{"HEAD"}},
+	})
+	require.NoError(t, err)
+
+	querySC, err := db.SearchContexts().CreateSearchContextWithRepositoryRevisions(ctx, &types.SearchContext{Name: "query-ctx", Query: "repo:repo-4$"}, nil)
+	require.NoError(t, err)
+	err = db.SearchContexts().SetMaterializedRepositoryRevisions(ctx, querySC, 1, []*types.SearchContextRepositoryRevisions{
+		{Repo: types.MinimalRepo{ID: repo4.ID, Name: repo4.Name}, Revisions: []string{"HEAD"}},
+	})
+	require.NoError(t, err)
+
+	// Any member of an organization can create a search context owned by the organization
+	_, err = db.SearchContexts().CreateSearchContextWithRepositoryRevisions(ctx, &types.SearchContext{Name: "org-ctx", NamespaceOrgID: org1.ID}, []*types.SearchContextRepositoryRevisions{
+		{Repo: types.MinimalRepo{ID: repo3.ID, Name: repo3.Name}, Revisions: []string{"HEAD"}},
+	})
+	require.NoError(t, err)
+
+	for _, scope := range []types.RoleScope{{OrgID: org1.ID}, {SearchContextID: sc.ID}, {SearchContextID: querySC.ID}, {RepoID: repo1.ID}} {
+		err = db.ScopedUserRoles().Assign(ctx, AssignScopedUserRoleOpts{
+			UserID: user.ID,
+			RoleID: role.ID,
+			Scope:  scope,
+		})
+		require.NoError(t, err)
+	}
+
+	// The owners of the search contexts add repositories after the roles have been assigned
+	err = db.SearchContexts().SetSearchContextRepositoryRevisions(ctx, sc.ID, []*types.SearchContextRepositoryRevisions{
+		{Repo: types.MinimalRepo{ID: repo2.ID, Name: repo2.Name}, Revisions: []string{"HEAD"}},
+		{Repo: types.MinimalRepo{ID: repo5.ID, Name: repo5.Name}, Revisions: []string{"HEAD"}},
+	})
+	require.NoError(t, err)
+	err = db.SearchContexts().SetMaterializedRepositoryRevisions(ctx, querySC, 2, []*types.SearchContextRepositoryRevisions{
+		{Repo: types.MinimalRepo{ID: repo4.ID, Name: repo4.Name}, Revisions: []string{"HEAD"}},
+		{Repo: types.MinimalRepo{ID: repo5.ID, Name: repo5.Name}, Revisions: []string{"HEAD"}},
+	})
+	require.NoError(t, err)
+
+	getPermission := func(resource PermissionResource) (*types.Permission, error) {
+		return store.GetPermissionForUser(ctx, GetPermissionForUserOpts{
+			UserID:    user.ID,
+			Namespace: rtypes.BatchChangesNamespace,
+			Action:    rtypes.BatchChangesWriteAction,
+			Resource:  &resource,
+		})
+	}
+
+	testCases := []struct {
+		name     string
+		resource PermissionResource
+		wantErr  bool
+	}{
+		{name: "organization in scope", resource: PermissionResource{OrgID: org1.ID}},
+		{name: "organization out of scope", resource: PermissionResource{OrgID: org2.ID}, wantErr: true},
+		{name: "repository in scope", resource: PermissionResource{RepoID: repo1.ID}},
+		{name: "repository in search context scope", resource: PermissionResource{RepoID: repo2.ID}},
+		{name: "repository in query-based search context scope", resource: PermissionResource{RepoID: repo4.ID}},
+		{name: "repository in search context owned by organization in scope", resource: PermissionResource{RepoID: repo3.ID}, wantErr: true},
+		{name: "repository added to search context after assignment", resource: PermissionResource{RepoID: repo5.ID}, wantErr: true},
+		{name: "no resource", resource: PermissionResource{}, wantErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			perm, err := getPermission(tc.resource)
+			if tc.wantErr {
+				require.Nil(t, perm)
+				require.ErrorContains(t, err, (&PermissionNotFoundErr{Namespace: p.Namespace, Action: p.Action}).Error())
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, p.ID, perm.ID)
+		})
+	}
+
+	t.Run("assigning again records the current repositories of the search context", func(t *testing.T) {
+		err := db.ScopedUserRoles().Assign(ctx, AssignScopedUserRoleOpts{
+			UserID: user.ID,
+			RoleID: role.ID,
+			Scope:  types.RoleScope{SearchContextID: sc.ID},
+		})
+		require.NoError(t, err)
+
+		perm, err := getPermission(PermissionResource{RepoID: repo5.ID})
+		require.NoError(t, err)
+		require.Equal(t, p.ID, perm.ID)
+	})
+
+	t.Run("without resource only considers global roles", func(t *testing.T) {
+		perm, err := store.GetPermissionForUser(ctx, GetPermissionForUserOpts{
+			UserID:    user.ID,
+			Namespace: rtypes.BatchChangesNamespace,
+			Action:    rtypes.BatchChangesWriteAction,
+		})
+		require.Nil(t, perm)
+		require.Error(t, err)
+	})
+}
+
 func seedPermissionDataForList(ctx context.Context, t *testing.T, store PermissionStore, db DB) (*types.Role, *types.User, int) {
 	t.Helper()
 
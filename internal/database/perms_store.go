@@ -480,9 +480,11 @@ WHERE
 	AND %s
 	RETURNING id
 `
-	whereSource := sqlf.Sprintf("source != %s", authz.SourceAPI)
-	if source == authz.SourceAPI {
-		whereSource = sqlf.Sprintf("source = %s", authz.SourceAPI)
+	// Permissions set explicitly via the API or granted by group mappings are only
+	// ever replaced by permissions from the same source.
+	whereSource := sqlf.Sprintf("source NOT IN (%s, %s)", authz.SourceAPI, authz.SourceGroupSync)
+	if source == authz.SourceAPI || source == authz.SourceGroupSync {
+		whereSource = sqlf.Sprintf("source = %s", source)
 	}
 
 	var where *sqlf.Query
@@ -1981,6 +1983,10 @@ var scanRepoPermissionsInfo = func(authzParams *AuthzQueryParameters) func(bases
 			if *source == authz.SourceRepoSync || *source == authz.SourceUserSync {
 				reason = UserRepoPermissionReasonPermissionsSync
 			}
+			// if source is group sync, set reason to group mapping
+			if *source == authz.SourceGroupSync {
+				reason = UserRepoPermissionReasonGroupMapping
+			}
 		} else if !repo.Private || unrestricted {
 			reason = UserRepoPermissionReasonUnrestricted
 		} else if repo.Private && !unrestricted && authzParams.BypassAuthzReasons.SiteAdmin {
@@ -2186,4 +2192,5 @@ const (
 	UserRepoPermissionReasonUnrestricted    UserRepoPermissionReason = "Unrestricted"
 	UserRepoPermissionReasonPermissionsSync UserRepoPermissionReason = "Permissions Sync"
 	UserRepoPermissionReasonExplicitPerms   UserRepoPermissionReason = "Explicit API"
+	UserRepoPermissionReasonGroupMapping    UserRepoPermissionReason = "Group Mapping"
 )
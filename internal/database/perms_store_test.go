@@ -676,6 +676,22 @@ func TestPermsStore_SetUserRepoPermissions(t *testing.T) {
 			entity:         authz.PermissionEntity{UserID: 1, ExternalAccountID: 1},
 			expectedResult: &SetPermissionsResult{Added: 1, Removed: 2, Found: 1},
 		},
+		{
+			name: "does not touch group permissions when source is sync",
+			origPermissions: []authz.Permission{
+				{UserID: 1, ExternalAccountID: 1, RepoID: 1},
+				{UserID: 1, ExternalAccountID: 1, RepoID: 2, Source: authz.SourceGroupSync},
+			},
+			permissions: []authz.Permission{
+				{UserID: 1, ExternalAccountID: 1, RepoID: 3},
+			},
+			expectedPermissions: []authz.Permission{
+				{UserID: 1, ExternalAccountID: 1, RepoID: 2, Source: authz.SourceGroupSync},
+				{UserID: 1, ExternalAccountID: 1, RepoID: 3, Source: source},
+			},
+			entity:         authz.PermissionEntity{UserID: 1, ExternalAccountID: 1},
+			expectedResult: &SetPermissionsResult{Added: 1, Removed: 1, Found: 1},
+		},
 		{
 			name: "does not delete old permissions when bool is false",
 			origPermissions: []authz.Permission{
@@ -715,10 +731,14 @@ func TestPermsStore_SetUserRepoPermissions(t *testing.T) {
 				setupPermsRelatedEntities(t, s, test.origPermissions)
 				syncedPermissions := []authz.Permission{}
 				explicitPermissions := []authz.Permission{}
+				groupPermissions := []authz.Permission{}
 				for _, p := range test.origPermissions {
-					if p.Source == authz.SourceAPI {
+					switch p.Source {
+					case authz.SourceAPI:
 						explicitPermissions = append(explicitPermissions, p)
-					} else {
+					case authz.SourceGroupSync:
+						groupPermissions = append(groupPermissions, p)
+					default:
 						syncedPermissions = append(syncedPermissions, p)
 					}
 				}
@@ -727,6 +747,8 @@ func TestPermsStore_SetUserRepoPermissions(t *testing.T) {
 				require.NoError(t, err)
 				_, err = s.setUserRepoPermissions(ctx, explicitPermissions, test.entity, authz.SourceAPI, replacePerms)
 				require.NoError(t, err)
+				_, err = s.setUserRepoPermissions(ctx, groupPermissions, test.entity, authz.SourceGroupSync, replacePerms)
+				require.NoError(t, err)
 			}
 
 			if len(test.permissions) > 0 {
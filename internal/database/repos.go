@@ -613,7 +613,7 @@ type ReposListOptions struct {
 	IDs []api.RepoID
 
 	// SearchContextID, if non zero, will limit the set of results to repositories listed in
-	// the search context.
+	// the search context, or materialized for the query of the search context.
 	//
 	// Mutually exclusive with ExternalServiceIDs
 	SearchContextID int64
@@ -1143,7 +1143,7 @@ func (s *repoStore) listSQL(ctx context.Context, tr trace.Trace, opt ReposListOp
 		where = append(where, sqlf.Sprintf("EXISTS (SELECT 1 FROM external_service_repos esr WHERE repo.id = esr.repo_id AND esr.external_service_id = ANY (%s))", pq.Array(opt.ExternalServiceIDs)))
 	} else if opt.SearchContextID != 0 {
 		// Joining on distinct search context repos to avoid returning duplicates
-		joins = append(joins, sqlf.Sprintf(`JOIN (SELECT DISTINCT repo_id, search_context_id FROM search_context_repos) dscr ON repo.id = dscr.repo_id`))
+		joins = append(joins, sqlf.Sprintf(`JOIN (SELECT DISTINCT repo_id, search_context_id FROM `+searchContextAllReposTable+`) dscr ON repo.id = dscr.repo_id`))
 		where = append(where, sqlf.Sprintf("dscr.search_context_id = %d", opt.SearchContextID))
 	}
 
@@ -21,8 +21,11 @@ type AuthzQueryParameters struct {
 	BypassAuthz               bool
 	BypassAuthzReasons        BypassAuthzReasonsMap
 	UsePermissionsUserMapping bool
-	AuthenticatedUserID       int32
-	AuthzEnforceForSiteAdmins bool
+	// UsePermissionsGroupMappings is true when repository permissions are granted
+	// based on the groups asserted by authentication providers.
+	UsePermissionsGroupMappings bool
+	AuthenticatedUserID         int32
+	AuthzEnforceForSiteAdmins   bool
 }
 
 func (p *AuthzQueryParameters) ToAuthzQuery() *sqlf.Query {
@@ -33,6 +36,7 @@ func GetAuthzQueryParameters(ctx context.Context, db DB) (params *AuthzQueryPara
 	params = &AuthzQueryParameters{}
 	authzAllowByDefault, authzProviders := authz.GetProviders()
 	params.UsePermissionsUserMapping = globals.PermissionsUserMapping().Enabled
+	params.UsePermissionsGroupMappings = len(conf.Get().PermissionsGroupMappings) > 0
 	params.AuthzEnforceForSiteAdmins = conf.Get().AuthzEnforceForSiteAdmins
 
 	a := actor.FromContext(ctx)
@@ -49,10 +53,10 @@ func GetAuthzQueryParameters(ctx context.Context, db DB) (params *AuthzQueryPara
 		params.BypassAuthzReasons.IsInternal = true
 	}
 
-	// 🚨 SECURITY: If explicit permissions API is ON, we want to enforce authz
-	// even if there are no authz providers configured.
+	// 🚨 SECURITY: If explicit permissions API or group mappings are ON, we want
+	// to enforce authz even if there are no authz providers configured.
 	// Otherwise bypass authorization with no authz providers.
-	if !params.UsePermissionsUserMapping && authzAllowByDefault && len(authzProviders) == 0 {
+	if !params.UsePermissionsUserMapping && !params.UsePermissionsGroupMappings && authzAllowByDefault && len(authzProviders) == 0 {
 		params.BypassAuthz = true
 		params.BypassAuthzReasons.NoAuthzProvider = true
 	}
@@ -0,0 +1,158 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/keegancsmith/sqlf"
+
+	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
+)
+
+// SCIMGroup records that an organization or a team was provisioned as a group
+// by a SCIM client. Exactly one of OrgID and TeamID is set.
+type SCIMGroup struct {
+	ID         int32
+	OrgID      int32
+	TeamID     int32
+	ExternalID string
+	CreatedAt  time.Time
+}
+
+// SCIMGroupNotFoundErr is returned when an organization or team was not
+// provisioned by a SCIM client.
+type SCIMGroupNotFoundErr struct {
+	args []any
+}
+
+func (err SCIMGroupNotFoundErr) Error() string {
+	return fmt.Sprintf("SCIM group not found: %v", err.args)
+}
+
+func (SCIMGroupNotFoundErr) NotFound() bool {
+	return true
+}
+
+// SCIMGroupsListOptions specifies the options for listing SCIM groups.
+type SCIMGroupsListOptions struct {
+	// Teams lists the groups provisioned as teams instead of organizations.
+	Teams bool
+	*LimitOffset
+}
+
+// SCIMGroupStore records which organizations and teams were provisioned as
+// groups by a SCIM client, so that SCIM clients cannot see or change the other
+// organizations and teams of the instance.
+type SCIMGroupStore interface {
+	basestore.ShareableStore
+	With(basestore.ShareableStore) SCIMGroupStore
+
+	// Create records the SCIM group and sets its ID and CreatedAt.
+	Create(ctx context.Context, group *SCIMGroup) error
+	// GetByOrgID returns the SCIM group of the organization, or SCIMGroupNotFoundErr.
+	GetByOrgID(ctx context.Context, orgID int32) (*SCIMGroup, error)
+	// GetByTeamID returns the SCIM group of the team, or SCIMGroupNotFoundErr.
+	GetByTeamID(ctx context.Context, teamID int32) (*SCIMGroup, error)
+	// List returns the SCIM groups of existing organizations or teams, ordered
+	// by organization or team ID.
+	List(ctx context.Context, opts SCIMGroupsListOptions) ([]*SCIMGroup, error)
+	// Count returns the number of SCIM groups of existing organizations or teams.
+	Count(ctx context.Context, opts SCIMGroupsListOptions) (int, error)
+	// UpdateExternalID sets the external ID of the SCIM group.
+	UpdateExternalID(ctx context.Context, id int32, externalID string) error
+	// Delete deletes the record of the SCIM group. It does not delete the
+	// organization or team.
+	Delete(ctx context.Context, id int32) error
+}
+
+type scimGroupStore struct {
+	*basestore.Store
+}
+
+var _ SCIMGroupStore = (*scimGroupStore)(nil)
+
+// SCIMGroupsWith instantiates and returns a new SCIMGroupStore using the other store handle.
+func SCIMGroupsWith(other basestore.ShareableStore) SCIMGroupStore {
+	return &scimGroupStore{Store: basestore.NewWithHandle(other.Handle())}
+}
+
+func (s *scimGroupStore) With(other basestore.ShareableStore) SCIMGroupStore {
+	return &scimGroupStore{Store: s.Store.With(other)}
+}
+
+const scimGroupColumns = "scim_groups.id, scim_groups.org_id, scim_groups.team_id, scim_groups.external_id, scim_groups.created_at"
+
+func (s *scimGroupStore) Create(ctx context.Context, group *SCIMGroup) error {
+	q := sqlf.Sprintf(`
+INSERT INTO scim_groups (org_id, team_id, external_id)
+VALUES (%s, %s, %s)
+RETURNING id, created_at
+`, dbutil.NullInt32Column(group.OrgID), dbutil.NullInt32Column(group.TeamID), dbutil.NullStringColumn(group.ExternalID))
+	return s.QueryRow(ctx, q).Scan(&group.ID, &group.CreatedAt)
+}
+
+func (s *scimGroupStore) GetByOrgID(ctx context.Context, orgID int32) (*SCIMGroup, error) {
+	return s.get(ctx, sqlf.Sprintf("org_id = %s", orgID), "orgID", orgID)
+}
+
+func (s *scimGroupStore) GetByTeamID(ctx context.Context, teamID int32) (*SCIMGroup, error) {
+	return s.get(ctx, sqlf.Sprintf("team_id = %s", teamID), "teamID", teamID)
+}
+
+func (s *scimGroupStore) get(ctx context.Context, cond *sqlf.Query, args ...any) (*SCIMGroup, error) {
+	q := sqlf.Sprintf("SELECT "+scimGroupColumns+" FROM scim_groups WHERE %s", cond)
+	groups, err := s.list(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+	if len(groups) == 0 {
+		return nil, SCIMGroupNotFoundErr{args: args}
+	}
+	return groups[0], nil
+}
+
+// existingGroupsQuery returns the query of the SCIM groups whose organization
+// or team exists. Deleted organizations are soft-deleted, so their SCIM groups
+// are not deleted with them.
+func existingGroupsQuery(opts SCIMGroupsListOptions, columns string) *sqlf.Query {
+	if opts.Teams {
+		return sqlf.Sprintf("SELECT " + columns + " FROM scim_groups JOIN teams ON teams.id = scim_groups.team_id")
+	}
+	return sqlf.Sprintf("SELECT " + columns + " FROM scim_groups JOIN orgs ON orgs.id = scim_groups.org_id AND orgs.deleted_at IS NULL")
+}
+
+func (s *scimGroupStore) List(ctx context.Context, opts SCIMGroupsListOptions) ([]*SCIMGroup, error) {
+	q := sqlf.Sprintf("%s ORDER BY COALESCE(scim_groups.org_id, scim_groups.team_id) ASC %s", existingGroupsQuery(opts, scimGroupColumns), opts.LimitOffset.SQL())
+	return s.list(ctx, q)
+}
+
+func (s *scimGroupStore) Count(ctx context.Context, opts SCIMGroupsListOptions) (int, error) {
+	count, _, err := basestore.ScanFirstInt(s.Query(ctx, existingGroupsQuery(opts, "COUNT(*)")))
+	return count, err
+}
+
+func (s *scimGroupStore) UpdateExternalID(ctx context.Context, id int32, externalID string) error {
+	return s.Exec(ctx, sqlf.Sprintf("UPDATE scim_groups SET external_id = %s WHERE id = %s", dbutil.NullStringColumn(externalID), id))
+}
+
+func (s *scimGroupStore) Delete(ctx context.Context, id int32) error {
+	return s.Exec(ctx, sqlf.Sprintf("DELETE FROM scim_groups WHERE id = %s", id))
+}
+
+func (s *scimGroupStore) list(ctx context.Context, q *sqlf.Query) ([]*SCIMGroup, error) {
+	return scanSCIMGroups(s.Query(ctx, q))
+}
+
+var scanSCIMGroups = basestore.NewSliceScanner(func(sc dbutil.Scanner) (*SCIMGroup, error) {
+	var group SCIMGroup
+	err := sc.Scan(
+		&group.ID,
+		dbutil.NullInt32{N: &group.OrgID},
+		dbutil.NullInt32{N: &group.TeamID},
+		dbutil.NullString{S: &group.ExternalID},
+		&group.CreatedAt,
+	)
+	return &group, err
+})
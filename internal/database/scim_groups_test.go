@@ -0,0 +1,80 @@
+package database
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sourcegraph/log/logtest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcegraph/sourcegraph/internal/database/dbtest"
+	"github.com/sourcegraph/sourcegraph/internal/errcode"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+)
+
+func TestSCIMGroups(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	ctx := context.Background()
+	logger := logtest.Scoped(t)
+	db := NewDB(logger, dbtest.NewDB(t))
+	store := db.SCIMGroups()
+
+	provisioned, err := db.Orgs().Create(ctx, "provisioned", nil)
+	require.NoError(t, err)
+	_, err = db.Orgs().Create(ctx, "manual", nil)
+	require.NoError(t, err)
+	deleted, err := db.Orgs().Create(ctx, "deleted", nil)
+	require.NoError(t, err)
+	team, err := db.Teams().CreateTeam(ctx, &types.Team{Name: "team"})
+	require.NoError(t, err)
+
+	orgGroup := &SCIMGroup{OrgID: provisioned.ID, ExternalID: "ext-1"}
+	require.NoError(t, store.Create(ctx, orgGroup))
+	assert.NotZero(t, orgGroup.ID)
+	require.NoError(t, store.Create(ctx, &SCIMGroup{OrgID: deleted.ID}))
+	require.NoError(t, store.Create(ctx, &SCIMGroup{TeamID: team.ID}))
+	require.NoError(t, db.Orgs().Delete(ctx, deleted.ID))
+
+	// An organization or team can only be provisioned once.
+	assert.Error(t, store.Create(ctx, &SCIMGroup{OrgID: provisioned.ID}))
+
+	got, err := store.GetByOrgID(ctx, provisioned.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "ext-1", got.ExternalID)
+	assert.Zero(t, got.TeamID)
+
+	_, err = store.GetByTeamID(ctx, provisioned.ID)
+	assert.True(t, errcode.IsNotFound(err))
+
+	orgGroups, err := store.List(ctx, SCIMGroupsListOptions{})
+	require.NoError(t, err)
+	require.Len(t, orgGroups, 1)
+	assert.Equal(t, provisioned.ID, orgGroups[0].OrgID)
+	count, err := store.Count(ctx, SCIMGroupsListOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+
+	teamGroups, err := store.List(ctx, SCIMGroupsListOptions{Teams: true})
+	require.NoError(t, err)
+	require.Len(t, teamGroups, 1)
+	assert.Equal(t, team.ID, teamGroups[0].TeamID)
+	assert.Empty(t, teamGroups[0].ExternalID)
+
+	require.NoError(t, store.UpdateExternalID(ctx, orgGroup.ID, "ext-2"))
+	got, err = store.GetByOrgID(ctx, provisioned.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "ext-2", got.ExternalID)
+
+	require.NoError(t, store.Delete(ctx, orgGroup.ID))
+	_, err = store.GetByOrgID(ctx, provisioned.ID)
+	assert.True(t, errcode.IsNotFound(err))
+
+	// Deleting a team deletes its SCIM group.
+	require.NoError(t, db.Teams().DeleteTeam(ctx, team.ID))
+	_, err = store.GetByTeamID(ctx, team.ID)
+	assert.True(t, errcode.IsNotFound(err))
+}
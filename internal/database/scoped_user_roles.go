@@ -0,0 +1,301 @@
+package database
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/keegancsmith/sqlf"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+var scopedUserRoleInsertColumns = []*sqlf.Query{
+	sqlf.Sprintf("user_id"),
+	sqlf.Sprintf("role_id"),
+	sqlf.Sprintf("org_id"),
+	sqlf.Sprintf("search_context_id"),
+	sqlf.Sprintf("repo_id"),
+}
+
+var scopedUserRoleColumns = []*sqlf.Query{
+	sqlf.Sprintf("scoped_user_roles.id"),
+	sqlf.Sprintf("scoped_user_roles.user_id"),
+	sqlf.Sprintf("scoped_user_roles.role_id"),
+	sqlf.Sprintf("scoped_user_roles.org_id"),
+	sqlf.Sprintf("scoped_user_roles.search_context_id"),
+	sqlf.Sprintf("scoped_user_roles.repo_id"),
+	sqlf.Sprintf("scoped_user_roles.created_at"),
+}
+
+type ScopedUserRoleOpts struct {
+	UserID int32
+	RoleID int32
+	Scope  types.RoleScope
+}
+
+type (
+	AssignScopedUserRoleOpts ScopedUserRoleOpts
+	RevokeScopedUserRoleOpts ScopedUserRoleOpts
+)
+
+type ScopedUserRoleListOpts struct {
+	UserID int32
+	RoleID int32
+	OrgID  int32
+}
+
+type ScopedUserRoleStore interface {
+	basestore.ShareableStore
+
+	// Assign assigns a role to a user for the resources within the given scope only. For a
+	// search context scope, the repositories the search context contains are recorded, so that
+	// later changes to the search context don't change the scope of the role. Assigning the role
+	// again records the current repositories of the search context.
+	Assign(ctx context.Context, opts AssignScopedUserRoleOpts) error
+	// List returns the scoped roles assigned to users that match the options.
+	List(ctx context.Context, opts ScopedUserRoleListOpts) ([]*types.ScopedUserRole, error)
+	// Revoke revokes a role that has previously been assigned to a user for the given scope.
+	Revoke(ctx context.Context, opts RevokeScopedUserRoleOpts) error
+	// WithTransact creates a transaction for the ScopedUserRoleStore.
+	WithTransact(context.Context, func(ScopedUserRoleStore) error) error
+	// With is used to merge the store with another to pull data via other stores.
+	With(basestore.ShareableStore) ScopedUserRoleStore
+}
+
+type scopedUserRoleStore struct {
+	*basestore.Store
+}
+
+var _ ScopedUserRoleStore = &scopedUserRoleStore{}
+
+func ScopedUserRolesWith(other basestore.ShareableStore) ScopedUserRoleStore {
+	return &scopedUserRoleStore{Store: basestore.NewWithHandle(other.Handle())}
+}
+
+func (r *scopedUserRoleStore) With(other basestore.ShareableStore) ScopedUserRoleStore {
+	return &scopedUserRoleStore{Store: r.Store.With(other)}
+}
+
+func (r *scopedUserRoleStore) WithTransact(ctx context.Context, f func(ScopedUserRoleStore) error) error {
+	return r.Store.WithTransact(ctx, func(tx *basestore.Store) error {
+		return f(&scopedUserRoleStore{Store: tx})
+	})
+}
+
+// validateRoleScope returns an error unless exactly one of the fields of the given scope is set.
+func validateRoleScope(scope types.RoleScope) error {
+	var n int
+	if scope.OrgID != 0 {
+		n++
+	}
+	if scope.SearchContextID != 0 {
+		n++
+	}
+	if scope.RepoID != 0 {
+		n++
+	}
+	if n != 1 {
+		return errors.New("exactly one of org, search context or repository scope is required")
+	}
+	return nil
+}
+
+const scopedUserRoleAssignQueryFmtStr = `
+INSERT INTO
+	scoped_user_roles (%s)
+VALUES (%s, %s, %s, %s, %s)
+ON CONFLICT DO NOTHING
+`
+
+const scopedUserRoleIDQueryFmtStr = `
+SELECT id FROM scoped_user_roles
+WHERE
+	user_id = %s
+	AND role_id = %s
+	AND COALESCE(org_id, 0) = %s
+	AND COALESCE(search_context_id, 0) = %s
+	AND COALESCE(repo_id, 0) = %s
+`
+
+const scopedUserRoleDeleteReposQueryFmtStr = `
+DELETE FROM scoped_user_role_repos WHERE scoped_user_role_id = %s
+`
+
+const scopedUserRoleInsertReposQueryFmtStr = `
+INSERT INTO scoped_user_role_repos (scoped_user_role_id, repo_id)
+SELECT DISTINCT %s, scr.repo_id FROM ` + searchContextAllReposTable + ` scr
+WHERE scr.search_context_id = %s
+`
+
+func (r *scopedUserRoleStore) Assign(ctx context.Context, opts AssignScopedUserRoleOpts) error {
+	if opts.UserID == 0 {
+		return errors.New("missing user id")
+	}
+
+	if opts.RoleID == 0 {
+		return errors.New("missing role id")
+	}
+
+	if err := validateRoleScope(opts.Scope); err != nil {
+		return err
+	}
+
+	return r.Store.WithTransact(ctx, func(tx *basestore.Store) error {
+		if err := tx.Exec(ctx, sqlf.Sprintf(
+			scopedUserRoleAssignQueryFmtStr,
+			sqlf.Join(scopedUserRoleInsertColumns, ", "),
+			opts.UserID,
+			opts.RoleID,
+			dbutil.NewNullInt32(opts.Scope.OrgID),
+			dbutil.NewNullInt64(opts.Scope.SearchContextID),
+			dbutil.NewNullInt32(int32(opts.Scope.RepoID)),
+		)); err != nil {
+			return err
+		}
+
+		if opts.Scope.SearchContextID == 0 {
+			return nil
+		}
+
+		// 🚨 SECURITY: The owner of a search context can change its repositories, so the
+		// repositories are recorded now rather than read from the search context when the
+		// permission is checked.
+		id, _, err := basestore.ScanFirstInt(tx.Query(ctx, sqlf.Sprintf(
+			scopedUserRoleIDQueryFmtStr,
+			opts.UserID,
+			opts.RoleID,
+			opts.Scope.OrgID,
+			opts.Scope.SearchContextID,
+			opts.Scope.RepoID,
+		)))
+		if err != nil {
+			return errors.Wrap(err, "getting scoped user role")
+		}
+		if err := tx.Exec(ctx, sqlf.Sprintf(scopedUserRoleDeleteReposQueryFmtStr, id)); err != nil {
+			return errors.Wrap(err, "deleting scoped user role repositories")
+		}
+		if err := tx.Exec(ctx, sqlf.Sprintf(scopedUserRoleInsertReposQueryFmtStr, id, opts.Scope.SearchContextID)); err != nil {
+			return errors.Wrap(err, "recording scoped user role repositories")
+		}
+
+		return nil
+	})
+}
+
+const scopedUserRoleListQueryFmtStr = `
+SELECT
+	%s
+FROM scoped_user_roles
+WHERE %s
+ORDER BY scoped_user_roles.id ASC
+`
+
+func (r *scopedUserRoleStore) List(ctx context.Context, opts ScopedUserRoleListOpts) ([]*types.ScopedUserRole, error) {
+	conds := []*sqlf.Query{sqlf.Sprintf("TRUE")}
+	if opts.UserID != 0 {
+		conds = append(conds, sqlf.Sprintf("scoped_user_roles.user_id = %s", opts.UserID))
+	}
+	if opts.RoleID != 0 {
+		conds = append(conds, sqlf.Sprintf("scoped_user_roles.role_id = %s", opts.RoleID))
+	}
+	if opts.OrgID != 0 {
+		conds = append(conds, sqlf.Sprintf("scoped_user_roles.org_id = %s", opts.OrgID))
+	}
+
+	q := sqlf.Sprintf(
+		scopedUserRoleListQueryFmtStr,
+		sqlf.Join(scopedUserRoleColumns, ", "),
+		sqlf.Join(conds, " AND "),
+	)
+
+	return scanScopedUserRoles(r.Query(ctx, q))
+}
+
+const scopedUserRoleRevokeQueryFmtStr = `
+DELETE FROM scoped_user_roles
+WHERE
+	user_id = %s
+	AND role_id = %s
+	AND COALESCE(org_id, 0) = %s
+	AND COALESCE(search_context_id, 0) = %s
+	AND COALESCE(repo_id, 0) = %s
+`
+
+func (r *scopedUserRoleStore) Revoke(ctx context.Context, opts RevokeScopedUserRoleOpts) error {
+	if opts.UserID == 0 {
+		return errors.New("missing user id")
+	}
+
+	if opts.RoleID == 0 {
+		return errors.New("missing role id")
+	}
+
+	if err := validateRoleScope(opts.Scope); err != nil {
+		return err
+	}
+
+	q := sqlf.Sprintf(
+		scopedUserRoleRevokeQueryFmtStr,
+		opts.UserID,
+		opts.RoleID,
+		opts.Scope.OrgID,
+		opts.Scope.SearchContextID,
+		opts.Scope.RepoID,
+	)
+
+	result, err := r.ExecResult(ctx, q)
+	if err != nil {
+		return errors.Wrap(err, "running delete query")
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return errors.Wrap(err, "checking deleted rows")
+	}
+
+	if rowsAffected == 0 {
+		return errors.Wrap(&ScopedUserRoleNotFoundErr{UserID: opts.UserID, RoleID: opts.RoleID}, "failed to revoke scoped user role")
+	}
+
+	return nil
+}
+
+type ScopedUserRoleNotFoundErr struct {
+	UserID int32
+	RoleID int32
+}
+
+func (e *ScopedUserRoleNotFoundErr) Error() string {
+	return fmt.Sprintf("scoped user role for user %d and role %d not found", e.UserID, e.RoleID)
+}
+
+func (e *ScopedUserRoleNotFoundErr) NotFound() bool {
+	return true
+}
+
+var scanScopedUserRoles = basestore.NewSliceScanner(scanScopedUserRole)
+
+func scanScopedUserRole(sc dbutil.Scanner) (*types.ScopedUserRole, error) {
+	var (
+		ur     types.ScopedUserRole
+		repoID int32
+	)
+	if err := sc.Scan(
+		&ur.ID,
+		&ur.UserID,
+		&ur.RoleID,
+		dbutil.NullInt32{N: &ur.Scope.OrgID},
+		dbutil.NullInt64{N: &ur.Scope.SearchContextID},
+		dbutil.NullInt32{N: &repoID},
+		&ur.CreatedAt,
+	); err != nil {
+		return nil, err
+	}
+	ur.Scope.RepoID = api.RepoID(repoID)
+
+	return &ur, nil
+}
@@ -0,0 +1,144 @@
+package database
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sourcegraph/log/logtest"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcegraph/sourcegraph/internal/database/dbtest"
+	"github.com/sourcegraph/sourcegraph/internal/errcode"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+)
+
+func TestScopedUserRoleAssign(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	logger := logtest.Scoped(t)
+	db := NewDB(logger, dbtest.NewDB(t))
+	store := db.ScopedUserRoles()
+
+	user, role := createUserAndRole(ctx, t, db)
+	org, err := db.Orgs().Create(ctx, "the-org", nil)
+	require.NoError(t, err)
+	repo, _ := createTestRepo(ctx, t, db, &createTestRepoPayload{Name: "github.com/sourcegraph/sourcegraph"})
+
+	t.Run("without user id", func(t *testing.T) {
+		err := store.Assign(ctx, AssignScopedUserRoleOpts{
+			RoleID: role.ID,
+			Scope:  types.RoleScope{OrgID: org.ID},
+		})
+		require.Error(t, err)
+		require.Equal(t, err.Error(), "missing user id")
+	})
+
+	t.Run("without role id", func(t *testing.T) {
+		err := store.Assign(ctx, AssignScopedUserRoleOpts{
+			UserID: user.ID,
+			Scope:  types.RoleScope{OrgID: org.ID},
+		})
+		require.Error(t, err)
+		require.Equal(t, err.Error(), "missing role id")
+	})
+
+	t.Run("without scope", func(t *testing.T) {
+		err := store.Assign(ctx, AssignScopedUserRoleOpts{
+			UserID: user.ID,
+			RoleID: role.ID,
+		})
+		require.ErrorContains(t, err, "exactly one of org, search context or repository scope is required")
+	})
+
+	t.Run("with multiple scopes", func(t *testing.T) {
+		err := store.Assign(ctx, AssignScopedUserRoleOpts{
+			UserID: user.ID,
+			RoleID: role.ID,
+			Scope:  types.RoleScope{OrgID: org.ID, RepoID: repo.ID},
+		})
+		require.ErrorContains(t, err, "exactly one of org, search context or repository scope is required")
+	})
+
+	t.Run("success", func(t *testing.T) {
+		for _, scope := range []types.RoleScope{{OrgID: org.ID}, {RepoID: repo.ID}} {
+			err := store.Assign(ctx, AssignScopedUserRoleOpts{
+				UserID: user.ID,
+				RoleID: role.ID,
+				Scope:  scope,
+			})
+			require.NoError(t, err)
+
+			// shouldn't fail the second time, since we are "upsert"-ing here
+			err = store.Assign(ctx, AssignScopedUserRoleOpts{
+				UserID: user.ID,
+				RoleID: role.ID,
+				Scope:  scope,
+			})
+			require.NoError(t, err)
+		}
+
+		urs, err := store.List(ctx, ScopedUserRoleListOpts{UserID: user.ID})
+		require.NoError(t, err)
+		require.Len(t, urs, 2)
+		require.Equal(t, types.RoleScope{OrgID: org.ID}, urs[0].Scope)
+		require.Equal(t, types.RoleScope{RepoID: repo.ID}, urs[1].Scope)
+
+		urs, err = store.List(ctx, ScopedUserRoleListOpts{OrgID: org.ID})
+		require.NoError(t, err)
+		require.Len(t, urs, 1)
+		require.Equal(t, role.ID, urs[0].RoleID)
+		require.Equal(t, user.ID, urs[0].UserID)
+	})
+}
+
+func TestScopedUserRoleRevoke(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	logger := logtest.Scoped(t)
+	db := NewDB(logger, dbtest.NewDB(t))
+	store := db.ScopedUserRoles()
+
+	user, role := createUserAndRole(ctx, t, db)
+	org, err := db.Orgs().Create(ctx, "the-org", nil)
+	require.NoError(t, err)
+
+	err = store.Assign(ctx, AssignScopedUserRoleOpts{
+		UserID: user.ID,
+		RoleID: role.ID,
+		Scope:  types.RoleScope{OrgID: org.ID},
+	})
+	require.NoError(t, err)
+
+	t.Run("without scope", func(t *testing.T) {
+		err := store.Revoke(ctx, RevokeScopedUserRoleOpts{
+			UserID: user.ID,
+			RoleID: role.ID,
+		})
+		require.ErrorContains(t, err, "exactly one of org, search context or repository scope is required")
+	})
+
+	t.Run("with non-existent scope", func(t *testing.T) {
+		err := store.Revoke(ctx, RevokeScopedUserRoleOpts{
+			UserID: user.ID,
+			RoleID: role.ID,
+			Scope:  types.RoleScope{OrgID: org.ID + 1},
+		})
+		require.Error(t, err)
+		require.True(t, errcode.IsNotFound(err))
+	})
+
+	t.Run("success", func(t *testing.T) {
+		err := store.Revoke(ctx, RevokeScopedUserRoleOpts{
+			UserID: user.ID,
+			RoleID: role.ID,
+			Scope:  types.RoleScope{OrgID: org.ID},
+		})
+		require.NoError(t, err)
+
+		urs, err := store.List(ctx, ScopedUserRoleListOpts{UserID: user.ID})
+		require.NoError(t, err)
+		require.Empty(t, urs)
+	})
+}
@@ -5,6 +5,7 @@ import (
 	"database/sql"
 	"fmt"
 	"sort"
+	"time"
 
 	"github.com/keegancsmith/sqlf"
 	"github.com/lib/pq"
@@ -14,6 +15,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/actor"
 	"github.com/sourcegraph/sourcegraph/internal/api"
 	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
+	"github.com/sourcegraph/sourcegraph/internal/database/batch"
 	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
 	"github.com/sourcegraph/sourcegraph/internal/types"
 	"github.com/sourcegraph/sourcegraph/lib/errors"
@@ -37,6 +39,9 @@ type SearchContextsStore interface {
 	GetSearchContextRepositoryRevisions(context.Context, int64) ([]*types.SearchContextRepositoryRevisions, error)
 	ListSearchContexts(context.Context, ListSearchContextsPageOptions, ListSearchContextsOptions) ([]*types.SearchContext, error)
 	GetAllQueries(context.Context) ([]string, error)
+	ListSearchContextsToMaterialize(ctx context.Context, maxAge time.Duration) ([]*types.SearchContext, int64, error)
+	SetMaterializedRepositoryRevisions(ctx context.Context, searchContext *types.SearchContext, changeID int64, repositoryRevisions []*types.SearchContextRepositoryRevisions) error
+	MarkMaterializationFailed(ctx context.Context, searchContextID int64, failureMessage string, maxBackoff time.Duration) error
 	SetSearchContextRepositoryRevisions(context.Context, int64, []*types.SearchContextRepositoryRevisions) error
 	Transact(context.Context) (SearchContextsStore, error)
 	UpdateSearchContextWithRepositoryRevisions(context.Context, *types.SearchContext, []*types.SearchContextRepositoryRevisions) (*types.SearchContext, error)
@@ -91,6 +96,7 @@ const searchContextQueryFmtStr = `
 		NULL as namespace_org_id,
 		TIMESTAMP WITH TIME ZONE 'epoch' as updated_at, -- Timestamp is not used for global context, but we need to return something.
 		NULL as query,
+		NULL as repos_materialized_at,
 		NULL as namespace_name,
 		NULL as namespace_username,
 		NULL as namespace_org_name,
@@ -107,6 +113,7 @@ const searchContextQueryFmtStr = `
 		sc.namespace_org_id as namespace_org_id,
 		sc.updated_at as updated_at,
 		sc.query as query,
+		sc.repos_materialized_at as repos_materialized_at,
 		COALESCE(u.username, o.name) as namespace_name,
 		u.username as namespace_username,
 		o.name as namespace_org_name,
@@ -132,6 +139,7 @@ SELECT
 	namespace_org_id,
 	updated_at,
 	query,
+	repos_materialized_at,
 	namespace_username,
 	namespace_org_name,
 	user_default,
@@ -386,10 +394,23 @@ SET
 	description = %s,
 	public = %s,
 	query = %s,
+	-- Materialized repositories are only valid for the query they were computed from.
+	repos_materialized_at = CASE WHEN query IS DISTINCT FROM %s THEN NULL ELSE repos_materialized_at END,
+	repos_materialization_failures = CASE WHEN query IS DISTINCT FROM %s THEN 0 ELSE repos_materialization_failures END,
+	repos_materialization_retry_after = CASE WHEN query IS DISTINCT FROM %s THEN NULL ELSE repos_materialization_retry_after END,
 	updated_at = now()
 WHERE id = %d
 `
 
+const deleteStaleMaterializedReposFmtStr = `
+DELETE FROM search_context_materialized_repos scmr
+USING search_contexts sc
+WHERE
+	scmr.search_context_id = sc.id
+	AND sc.id = %d
+	AND sc.repos_materialized_at IS NULL
+`
+
 // 🚨 SECURITY: The caller must ensure that the actor is a site admin or has permission to update the search context.
 func (s *searchContextsStore) UpdateSearchContextWithRepositoryRevisions(ctx context.Context, searchContext *types.SearchContext, repositoryRevisions []*types.SearchContextRepositoryRevisions) (_ *types.SearchContext, err error) {
 	tx, err := s.Transact(ctx)
@@ -470,12 +491,18 @@ func updateSearchContext(ctx context.Context, s SearchContextsStore, searchConte
 		searchContext.Description,
 		searchContext.Public,
 		dbutil.NullStringColumn(searchContext.Query),
+		dbutil.NullStringColumn(searchContext.Query),
+		dbutil.NullStringColumn(searchContext.Query),
+		dbutil.NullStringColumn(searchContext.Query),
 		searchContext.ID,
 	)
 	_, err := s.Handle().ExecContext(ctx, q.Query(sqlf.PostgresBindVar), q.Args()...)
 	if err != nil {
 		return nil, err
 	}
+	if err := s.Exec(ctx, sqlf.Sprintf(deleteStaleMaterializedReposFmtStr, searchContext.ID)); err != nil {
+		return nil, err
+	}
 	return s.GetSearchContext(ctx, GetSearchContextOptions{
 		Name:            searchContext.Name,
 		NamespaceUserID: searchContext.NamespaceUserID,
@@ -508,6 +535,7 @@ func scanSearchContexts(rows *sql.Rows) ([]*types.SearchContext, error) {
 			&dbutil.NullInt32{N: &sc.NamespaceOrgID},
 			&sc.UpdatedAt,
 			&dbutil.NullString{S: &sc.Query},
+			&dbutil.NullTime{Time: &sc.ReposMaterializedAt},
 			&dbutil.NullString{S: &sc.NamespaceUserName},
 			&dbutil.NullString{S: &sc.NamespaceOrgName},
 			&sc.Default,
@@ -521,13 +549,22 @@ func scanSearchContexts(rows *sql.Rows) ([]*types.SearchContext, error) {
 	return out, nil
 }
 
+// searchContextAllReposTable combines the repositories explicitly listed for
+// search contexts with the materialized repositories of query-based search
+// contexts. A search context only ever has rows in one of the two tables.
+const searchContextAllReposTable = `(
+	SELECT search_context_id, repo_id, revision FROM search_context_repos
+	UNION ALL
+	SELECT search_context_id, repo_id, revision FROM search_context_materialized_repos
+)`
+
 var getSearchContextRepositoryRevisionsFmtStr = `
 SELECT
 	sc.repo_id,
 	sc.revision,
 	r.name
 FROM
-	search_context_repos sc
+	` + searchContextAllReposTable + ` sc
 JOIN
 	(
 		SELECT
@@ -653,6 +690,124 @@ func (s *searchContextsStore) GetAllQueries(ctx context.Context) (qs []string, _
 	return qs, s.QueryRow(ctx, q).Scan(pq.Array(&qs))
 }
 
+const listSearchContextsToMaterializeFmtStr = `
+SELECT
+	id,
+	query,
+	repos_materialized_at
+FROM search_contexts
+WHERE
+	query IS NOT NULL
+	AND query != ''
+	AND (
+		repos_materialized_at IS NULL
+		OR repos_materialized_at < now() - %s::interval
+		OR repos_materialized_change_id IS NULL
+		OR repos_materialized_change_id < %s
+	)
+	AND (repos_materialization_retry_after IS NULL OR repos_materialization_retry_after <= now())
+ORDER BY repos_materialized_at ASC NULLS FIRST, id
+`
+
+// ListSearchContextsToMaterialize returns the query-based search contexts
+// whose materialized repositories are missing, older than maxAge, or outdated
+// because repositories changed since they were materialized. Search contexts
+// whose materialization failed are skipped until their retry time has passed,
+// see MarkMaterializationFailed. Only the ID, Query and ReposMaterializedAt
+// fields of the returned search contexts are set. The returned change ID must
+// be passed to SetMaterializedRepositoryRevisions once the repositories are
+// resolved.
+func (s *searchContextsStore) ListSearchContextsToMaterialize(ctx context.Context, maxAge time.Duration) (_ []*types.SearchContext, changeID int64, err error) {
+	if a := actor.FromContext(ctx); !a.IsInternal() {
+		return nil, 0, errors.New("ListSearchContextsToMaterialize can only be accessed by an internal actor")
+	}
+
+	// The change ID is read before the repositories are resolved, so that
+	// changes happening in the meantime are picked up by the next run.
+	if err := s.QueryRow(ctx, sqlf.Sprintf(`SELECT CASE WHEN is_called THEN last_value ELSE 0 END FROM search_context_repo_changes_seq`)).Scan(&changeID); err != nil {
+		return nil, 0, err
+	}
+
+	rows, err := s.Query(ctx, sqlf.Sprintf(listSearchContextsToMaterializeFmtStr, fmt.Sprintf("%d seconds", int64(maxAge/time.Second)), changeID))
+	if err != nil {
+		return nil, 0, err
+	}
+	defer func() { err = basestore.CloseRows(rows, err) }()
+
+	var out []*types.SearchContext
+	for rows.Next() {
+		var sc types.SearchContext
+		if err := rows.Scan(&sc.ID, &sc.Query, &dbutil.NullTime{Time: &sc.ReposMaterializedAt}); err != nil {
+			return nil, 0, err
+		}
+		out = append(out, &sc)
+	}
+	return out, changeID, nil
+}
+
+const markSearchContextMaterializedFmtStr = `
+UPDATE search_contexts
+SET
+	repos_materialized_at = now(),
+	repos_materialized_change_id = %s,
+	repos_materialization_failures = 0,
+	repos_materialization_retry_after = NULL,
+	repos_materialization_failure_message = NULL
+WHERE id = %d AND query = %s
+RETURNING id
+`
+
+// SetMaterializedRepositoryRevisions replaces the materialized repositories of
+// the given query-based search context. The repositories are discarded if the
+// query of the search context changed since they were resolved.
+func (s *searchContextsStore) SetMaterializedRepositoryRevisions(ctx context.Context, searchContext *types.SearchContext, changeID int64, repositoryRevisions []*types.SearchContextRepositoryRevisions) (err error) {
+	tx, err := s.Store.Transact(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { err = tx.Done(err) }()
+
+	_, ok, err := basestore.ScanFirstInt(tx.Query(ctx, sqlf.Sprintf(markSearchContextMaterializedFmtStr, changeID, searchContext.ID, searchContext.Query)))
+	if err != nil || !ok {
+		// The search context was deleted or its query changed.
+		return err
+	}
+
+	if err := tx.Exec(ctx, sqlf.Sprintf("DELETE FROM search_context_materialized_repos WHERE search_context_id = %d", searchContext.ID)); err != nil {
+		return err
+	}
+
+	inserter := batch.NewInserter(ctx, tx.Handle(), "search_context_materialized_repos", batch.MaxNumPostgresParameters, "search_context_id", "repo_id", "revision")
+	for _, repoRev := range repositoryRevisions {
+		for _, revision := range repoRev.Revisions {
+			if err := inserter.Insert(ctx, searchContext.ID, repoRev.Repo.ID, revision); err != nil {
+				return err
+			}
+		}
+	}
+	return inserter.Flush(ctx)
+}
+
+const markSearchContextMaterializationFailedFmtStr = `
+UPDATE search_contexts
+SET
+	repos_materialization_failures = repos_materialization_failures + 1,
+	-- Back off exponentially, starting at one minute.
+	repos_materialization_retry_after = now() + LEAST(
+		interval '1 minute' * power(2, LEAST(repos_materialization_failures, 20)),
+		%s::interval
+	),
+	repos_materialization_failure_message = %s
+WHERE id = %d
+`
+
+// MarkMaterializationFailed records that the repositories of the given
+// query-based search context could not be materialized. The materialization
+// is retried with an exponential backoff of at most maxBackoff.
+func (s *searchContextsStore) MarkMaterializationFailed(ctx context.Context, searchContextID int64, failureMessage string, maxBackoff time.Duration) error {
+	return s.Exec(ctx, sqlf.Sprintf(markSearchContextMaterializationFailedFmtStr, fmt.Sprintf("%d seconds", int64(maxBackoff/time.Second)), failureMessage, searchContextID))
+}
+
 // 🚨 SECURITY: The caller must ensure that the actor is the user setting the context as their default.
 func (s *searchContextsStore) SetUserDefaultSearchContextID(ctx context.Context, userID int32, searchContextID int64) error {
 	if searchContextID == 0 {
@@ -5,8 +5,10 @@ import (
 	"reflect"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/keegancsmith/sqlf"
 
 	"github.com/sourcegraph/log/logtest"
 
@@ -427,6 +429,132 @@ func TestSearchContexts_CreateAndSetRepositoryRevisions(t *testing.T) {
 	}
 }
 
+func TestSearchContexts_MaterializeRepositoryRevisions(t *testing.T) {
+	logger := logtest.Scoped(t)
+	db := NewDB(logger, dbtest.NewDB(t))
+	t.Parallel()
+	ctx := actor.WithInternalActor(context.Background())
+	sc := db.SearchContexts()
+	r := db.Repos()
+
+	err := r.Create(ctx, &types.Repo{Name: "testA", URI: "https://example.com/a"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %s", err)
+	}
+	repoA, err := r.GetByName(ctx, "testA")
+	if err != nil {
+		t.Fatalf("Expected no error, got %s", err)
+	}
+
+	searchContext, err := sc.CreateSearchContextWithRepositoryRevisions(
+		ctx,
+		&types.SearchContext{Name: "sc", Public: true, Query: "repo:^test"},
+		nil,
+	)
+	if err != nil {
+		t.Fatalf("Expected no error, got %s", err)
+	}
+
+	toMaterialize, changeID, err := sc.ListSearchContextsToMaterialize(ctx, time.Hour)
+	if err != nil {
+		t.Fatalf("Expected no error, got %s", err)
+	}
+	if len(toMaterialize) != 1 || toMaterialize[0].ID != searchContext.ID {
+		t.Fatalf("wanted search context %d to be materialized, got %v", searchContext.ID, toMaterialize)
+	}
+
+	materialized := []*types.SearchContextRepositoryRevisions{
+		{Repo: types.MinimalRepo{ID: repoA.ID, Name: repoA.Name}, Revisions: []string{"HEAD"}},
+	}
+	if err := sc.SetMaterializedRepositoryRevisions(ctx, toMaterialize[0], changeID, materialized); err != nil {
+		t.Fatalf("Expected no error, got %s", err)
+	}
+
+	gotRepositoryRevisions, err := sc.GetSearchContextRepositoryRevisions(ctx, searchContext.ID)
+	if err != nil {
+		t.Fatalf("Expected no error, got %s", err)
+	}
+	if !reflect.DeepEqual(materialized, gotRepositoryRevisions) {
+		t.Fatalf("wanted %v repository revisions, got %v", materialized, gotRepositoryRevisions)
+	}
+
+	toMaterialize, _, err = sc.ListSearchContextsToMaterialize(ctx, time.Hour)
+	if err != nil {
+		t.Fatalf("Expected no error, got %s", err)
+	}
+	if len(toMaterialize) != 0 {
+		t.Fatalf("wanted no search contexts to be materialized, got %v", toMaterialize)
+	}
+
+	// Adding a repository invalidates the materialized repositories.
+	if err := r.Create(ctx, &types.Repo{Name: "testB", URI: "https://example.com/b"}); err != nil {
+		t.Fatalf("Expected no error, got %s", err)
+	}
+	toMaterialize, _, err = sc.ListSearchContextsToMaterialize(ctx, time.Hour)
+	if err != nil {
+		t.Fatalf("Expected no error, got %s", err)
+	}
+	if len(toMaterialize) != 1 {
+		t.Fatalf("wanted search context %d to be materialized, got %v", searchContext.ID, toMaterialize)
+	}
+
+	// A failed materialization is not retried before the backoff passed.
+	if err := sc.MarkMaterializationFailed(ctx, searchContext.ID, "oops", time.Hour); err != nil {
+		t.Fatalf("Expected no error, got %s", err)
+	}
+	toMaterialize, changeID, err = sc.ListSearchContextsToMaterialize(ctx, time.Hour)
+	if err != nil {
+		t.Fatalf("Expected no error, got %s", err)
+	}
+	if len(toMaterialize) != 0 {
+		t.Fatalf("wanted no search contexts to be materialized, got %v", toMaterialize)
+	}
+
+	// A successful materialization resets the backoff.
+	if err := sc.SetMaterializedRepositoryRevisions(ctx, searchContext, changeID, materialized); err != nil {
+		t.Fatalf("Expected no error, got %s", err)
+	}
+	if err := r.Create(ctx, &types.Repo{Name: "testC", URI: "https://example.com/c"}); err != nil {
+		t.Fatalf("Expected no error, got %s", err)
+	}
+	toMaterialize, changeID, err = sc.ListSearchContextsToMaterialize(ctx, time.Hour)
+	if err != nil {
+		t.Fatalf("Expected no error, got %s", err)
+	}
+	if len(toMaterialize) != 1 {
+		t.Fatalf("wanted search context %d to be materialized, got %v", searchContext.ID, toMaterialize)
+	}
+
+	// Changes to repository attributes that are not tracked do not invalidate
+	// the materialized repositories.
+	if err := sc.SetMaterializedRepositoryRevisions(ctx, searchContext, changeID, materialized); err != nil {
+		t.Fatalf("Expected no error, got %s", err)
+	}
+	if err := sc.Exec(ctx, sqlf.Sprintf("UPDATE repo SET description = 'changed', name = name WHERE id = %s", repoA.ID)); err != nil {
+		t.Fatalf("Expected no error, got %s", err)
+	}
+	toMaterialize, _, err = sc.ListSearchContextsToMaterialize(ctx, time.Hour)
+	if err != nil {
+		t.Fatalf("Expected no error, got %s", err)
+	}
+	if len(toMaterialize) != 0 {
+		t.Fatalf("wanted no search contexts to be materialized, got %v", toMaterialize)
+	}
+
+	// Changing the query discards the materialized repositories.
+	searchContext.Query = "repo:^other"
+	if _, err := sc.UpdateSearchContextWithRepositoryRevisions(ctx, searchContext, nil); err != nil {
+		t.Fatalf("Expected no error, got %s", err)
+	}
+	gotRepositoryRevisions, err = sc.GetSearchContextRepositoryRevisions(ctx, searchContext.ID)
+	if err != nil {
+		t.Fatalf("Expected no error, got %s", err)
+	}
+	if len(gotRepositoryRevisions) != 0 {
+		t.Fatalf("wanted no repository revisions, got %v", gotRepositoryRevisions)
+	}
+}
+
 func TestSearchContexts_Permissions(t *testing.T) {
 	logger := logtest.Scoped(t)
 	db := NewDB(logger, dbtest.NewDB(t))
@@ -48,6 +48,7 @@ const (
 
 	SecurityEventAccessTokenCreated             SecurityEventName = "AccessTokenCreated"
 	SecurityEventAccessTokenDeleted             SecurityEventName = "AccessTokenDeleted"
+	SecurityEventAccessTokenExpired             SecurityEventName = "AccessTokenExpired"
 	SecurityEventAccessTokenHardDeleted         SecurityEventName = "AccessTokenHardDeleted"
 	SecurityEventAccessTokenImpersonated        SecurityEventName = "AccessTokenImpersonated"
 	SecurityEventAccessTokenInvalid             SecurityEventName = "AccessTokenInvalid"
@@ -67,6 +68,16 @@ const (
 
 	SecurityEventOIDCLoginSucceeded SecurityEventName = "SecurityEventOIDCLoginSucceeded"
 	SecurityEventOIDCLoginFailed    SecurityEventName = "SecurityEventOIDCLoginFailed"
+
+	SecurityEventNameSearchQueryExecuted SecurityEventName = "SearchQueryExecuted"
+	SecurityEventNameFileViewed          SecurityEventName = "FileViewed"
+
+	SecurityEventNameSecondFactorRequired  SecurityEventName = "SecondFactorRequired"
+	SecurityEventNameSecondFactorEnrolled  SecurityEventName = "SecondFactorEnrolled"
+	SecurityEventNameSecondFactorRemoved   SecurityEventName = "SecondFactorRemoved"
+	SecurityEventNameSecondFactorSucceeded SecurityEventName = "SecondFactorSucceeded"
+	SecurityEventNameSecondFactorFailed    SecurityEventName = "SecondFactorFailed"
+	SecurityEventNameRecoveryCodesCreated  SecurityEventName = "RecoveryCodesCreated"
 )
 
 // SecurityEvent contains information needed for logging a security-relevant event.
@@ -101,6 +112,9 @@ type SecurityEventLogsStore interface {
 	LogEvent(ctx context.Context, e *SecurityEvent)
 	// Bulk "LogEvent" action.
 	LogEventList(ctx context.Context, events []*SecurityEvent)
+	// DeleteOlderThan deletes the security events that happened before the
+	// given time and returns the number of deleted events.
+	DeleteOlderThan(ctx context.Context, before time.Time) (int64, error)
 }
 
 type securityEventLogsStore struct {
@@ -119,7 +133,7 @@ func (s *securityEventLogsStore) Insert(ctx context.Context, event *SecurityEven
 	return s.InsertList(ctx, []*SecurityEvent{event})
 }
 
-func (s *securityEventLogsStore) InsertList(ctx context.Context, events []*SecurityEvent) error {
+func (s *securityEventLogsStore) InsertList(ctx context.Context, events []*SecurityEvent) (err error) {
 	cfg := conf.SiteConfig()
 	loc := audit.SecurityEventLocation(cfg)
 	if loc == audit.None {
@@ -169,11 +183,43 @@ func (s *securityEventLogsStore) InsertList(ctx context.Context, events []*Secur
 		)
 	}
 
-	if loc == audit.Database || loc == audit.All {
-		query := sqlf.Sprintf("INSERT INTO security_event_logs(name, url, user_id, anonymous_user_id, source, argument, version, timestamp) VALUES %s", sqlf.Join(vals, ","))
+	sinks := audit.SinkKeys(cfg)
+	insert := loc == audit.Database || loc == audit.All
+	if insert || len(sinks) > 0 {
+		// Events are queued for the audit log sinks in the same transaction, such
+		// that every stored event is also delivered to the sinks.
+		tx, err := s.Transact(ctx)
+		if err != nil {
+			return err
+		}
+		defer func() { err = tx.Done(err) }()
+
+		if insert {
+			query := sqlf.Sprintf("INSERT INTO security_event_logs(name, url, user_id, anonymous_user_id, source, argument, version, timestamp) VALUES %s", sqlf.Join(vals, ","))
 
-		if _, err := s.Handle().ExecContext(ctx, query.Query(sqlf.PostgresBindVar), query.Args()...); err != nil {
-			return errors.Wrap(err, "INSERT")
+			if err := tx.Exec(ctx, query); err != nil {
+				return errors.Wrap(err, "INSERT")
+			}
+		}
+		if len(sinks) > 0 {
+			entries := make([]audit.Entry, 0, len(events))
+			for _, event := range events {
+				argument := json.RawMessage(event.marshalArgumentAsJSON())
+				if !json.Valid(argument) {
+					argument, _ = json.Marshal(string(argument))
+				}
+				entries = append(entries, audit.NewEntry(ctx, "security events", string(event.Name), event.Timestamp, securityEventAuditFields{
+					URL:             event.URL,
+					UserID:          event.UserID,
+					AnonymousUserID: event.AnonymousUserID,
+					Source:          event.Source,
+					Argument:        argument,
+					Version:         version.Version(),
+				}))
+			}
+			if err := AuditLogOutboxWith(tx).Enqueue(ctx, sinks, entries); err != nil {
+				return errors.Wrap(err, "queueing events for audit log sinks")
+			}
 		}
 	}
 	if loc == audit.AuditLog || loc == audit.All {
@@ -181,6 +227,8 @@ func (s *securityEventLogsStore) InsertList(ctx context.Context, events []*Secur
 			audit.Log(ctx, s.logger, audit.Record{
 				Entity: "security events",
 				Action: string(event.Name),
+				// The event was queued for the sinks above.
+				ExcludeFromSinks: true,
 				Fields: []log.Field{
 					log.Object("event",
 						log.String("URL", event.URL),
@@ -198,6 +246,17 @@ func (s *securityEventLogsStore) InsertList(ctx context.Context, events []*Secur
 	return nil
 }
 
+// securityEventAuditFields are the fields of a security event in the entries
+// delivered to audit log sinks.
+type securityEventAuditFields struct {
+	URL             string          `json:"URL"`
+	UserID          uint32          `json:"UserID"`
+	AnonymousUserID string          `json:"AnonymousUserID"`
+	Source          string          `json:"source"`
+	Argument        json.RawMessage `json:"argument"`
+	Version         string          `json:"version"`
+}
+
 func (s *securityEventLogsStore) LogEvent(ctx context.Context, e *SecurityEvent) {
 	s.LogEventList(ctx, []*SecurityEvent{e})
 }
@@ -216,3 +275,11 @@ func (s *securityEventLogsStore) LogEventList(ctx context.Context, events []*Sec
 		}
 	}
 }
+
+func (s *securityEventLogsStore) DeleteOlderThan(ctx context.Context, before time.Time) (int64, error) {
+	res, err := s.ExecResult(ctx, sqlf.Sprintf(`DELETE FROM security_event_logs WHERE "timestamp" < %s`, before.UTC()))
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
@@ -4,8 +4,10 @@ import (
 	"context"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/sourcegraph/log/logtest"
 
@@ -109,6 +111,47 @@ func TestSecurityEventLogs_ValidInfo(t *testing.T) {
 	}
 }
 
+func TestSecurityEventLogs_DeleteOlderThan(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+	prevConf := conf.Get()
+	t.Cleanup(func() {
+		conf.Mock(prevConf)
+	})
+	conf.Mock(&conf.Unified{SiteConfiguration: schema.SiteConfiguration{
+		Log: &schema.Log{
+			SecurityEventLog: &schema.SecurityEventLog{Location: "database"},
+		},
+	}})
+
+	logger := logtest.Scoped(t)
+	db := NewDB(logger, dbtest.NewDB(t))
+	ctx := actor.WithActor(context.Background(), &actor.Actor{UID: 1})
+
+	now := time.Now()
+	require.NoError(t, db.SecurityEventLogs().InsertList(ctx, []*SecurityEvent{
+		{Name: "old_event", UserID: 1, Source: "BACKEND", Timestamp: now.Add(-48 * time.Hour)},
+		{Name: "new_event", UserID: 1, Source: "BACKEND", Timestamp: now},
+	}))
+
+	deleted, err := db.SecurityEventLogs().DeleteOlderThan(ctx, now.Add(-24*time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), deleted)
+
+	var names []string
+	rows, err := db.QueryContext(ctx, "SELECT name FROM security_event_logs")
+	require.NoError(t, err)
+	defer rows.Close()
+	for rows.Next() {
+		var name string
+		require.NoError(t, rows.Scan(&name))
+		names = append(names, name)
+	}
+	require.NoError(t, rows.Err())
+	assert.Equal(t, []string{"new_event"}, names)
+}
+
 func filterAudit(logs []logtest.CapturedLog) []logtest.CapturedLog {
 	var filtered []logtest.CapturedLog
 	for _, log := range logs {
@@ -0,0 +1,403 @@
+package database
+
+import (
+	"context"
+	"crypto/subtle"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/keegancsmith/sqlf"
+
+	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
+	"github.com/sourcegraph/sourcegraph/internal/encryption"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// UserTOTPCredential is the TOTP (authenticator app) second factor of a user.
+type UserTOTPCredential struct {
+	ID     int32
+	UserID int32
+	// Secret is the decrypted, base32-encoded TOTP secret.
+	Secret string
+	// LastUsedStep is the time step of the last accepted code.
+	LastUsedStep int64
+	// ConfirmedAt is nil until the user verified a code of the secret.
+	ConfirmedAt *time.Time
+	CreatedAt   time.Time
+}
+
+// UserWebAuthnCredential is a WebAuthn security key registered by a user.
+type UserWebAuthnCredential struct {
+	ID           int32
+	UserID       int32
+	Name         string
+	CredentialID []byte
+	// PublicKey is the COSE-encoded public key of the credential.
+	PublicKey  []byte
+	SignCount  uint32
+	CreatedAt  time.Time
+	LastUsedAt *time.Time
+}
+
+// SecondFactorNotFoundErr is returned when a user has no such second factor.
+type SecondFactorNotFoundErr struct {
+	userID int32
+}
+
+func (err SecondFactorNotFoundErr) Error() string {
+	return fmt.Sprintf("second factor not found: userID=%d", err.userID)
+}
+
+func (SecondFactorNotFoundErr) NotFound() bool {
+	return true
+}
+
+// ErrTOTPAlreadyConfirmed is returned when a TOTP secret is enrolled for a user
+// who already confirmed one. The confirmed secret must be deleted first.
+var ErrTOTPAlreadyConfirmed = errors.New("TOTP is already enrolled")
+
+// UserSecondFactorsStore provides access to the second factors of users signing
+// in with the builtin auth provider: TOTP secrets, WebAuthn credentials and
+// recovery codes. TOTP secrets and recovery codes are encrypted with the key of
+// the store.
+type UserSecondFactorsStore interface {
+	basestore.ShareableStore
+	With(basestore.ShareableStore) UserSecondFactorsStore
+	WithTransact(context.Context, func(UserSecondFactorsStore) error) error
+
+	// HasSecondFactor reports whether the user has a confirmed TOTP secret or a
+	// WebAuthn credential.
+	HasSecondFactor(ctx context.Context, userID int32) (bool, error)
+
+	// GetTOTP returns the TOTP secret of the user, or SecondFactorNotFoundErr.
+	GetTOTP(ctx context.Context, userID int32) (*UserTOTPCredential, error)
+	// EnrollTOTP stores a new unconfirmed TOTP secret for the user, replacing an
+	// unconfirmed one. It returns ErrTOTPAlreadyConfirmed if the user already
+	// confirmed a secret.
+	EnrollTOTP(ctx context.Context, userID int32, secret string) error
+	// ConfirmTOTP confirms the TOTP secret of the user with a verified code of
+	// the given time step. It reports false if there is no unconfirmed secret.
+	ConfirmTOTP(ctx context.Context, userID int32, step int64) (bool, error)
+	// UseTOTPStep records that a code of the given time step of the confirmed
+	// secret was used. It reports false if a code of the same or a later step was
+	// already used, so that codes cannot be replayed.
+	UseTOTPStep(ctx context.Context, userID int32, step int64) (bool, error)
+	// DeleteTOTP deletes the TOTP secret of the user.
+	DeleteTOTP(ctx context.Context, userID int32) error
+
+	// ListWebAuthnCredentials returns the WebAuthn credentials of the user.
+	ListWebAuthnCredentials(ctx context.Context, userID int32) ([]*UserWebAuthnCredential, error)
+	// GetWebAuthnCredential returns the WebAuthn credential of the user with the
+	// given credential ID, or SecondFactorNotFoundErr.
+	GetWebAuthnCredential(ctx context.Context, userID int32, credentialID []byte) (*UserWebAuthnCredential, error)
+	// CreateWebAuthnCredential inserts the credential and sets its ID and
+	// CreatedAt.
+	CreateWebAuthnCredential(ctx context.Context, cred *UserWebAuthnCredential) error
+	// UpdateWebAuthnSignCount records a use of the credential with the new
+	// signature counter.
+	UpdateWebAuthnSignCount(ctx context.Context, id int32, signCount uint32) error
+	// DeleteWebAuthnCredential deletes the credential of the user with the given
+	// ID, or returns SecondFactorNotFoundErr.
+	DeleteWebAuthnCredential(ctx context.Context, userID, id int32) error
+
+	// ReplaceRecoveryCodes replaces the recovery codes of the user.
+	ReplaceRecoveryCodes(ctx context.Context, userID int32, codes []string) error
+	// UseRecoveryCode marks the given unused recovery code of the user as used.
+	// It reports false if the user has no such unused code.
+	UseRecoveryCode(ctx context.Context, userID int32, code string) (bool, error)
+	// CountRecoveryCodes returns the number of unused recovery codes of the user.
+	CountRecoveryCodes(ctx context.Context, userID int32) (int, error)
+	// DeleteRecoveryCodes deletes the recovery codes of the user.
+	DeleteRecoveryCodes(ctx context.Context, userID int32) error
+}
+
+type userSecondFactorsStore struct {
+	*basestore.Store
+	key encryption.Key
+}
+
+var _ UserSecondFactorsStore = &userSecondFactorsStore{}
+
+// UserSecondFactorsWith instantiates and returns a new UserSecondFactorsStore
+// using the other store handle.
+func UserSecondFactorsWith(other basestore.ShareableStore, key encryption.Key) UserSecondFactorsStore {
+	return &userSecondFactorsStore{Store: basestore.NewWithHandle(other.Handle()), key: key}
+}
+
+func (s *userSecondFactorsStore) With(other basestore.ShareableStore) UserSecondFactorsStore {
+	return &userSecondFactorsStore{Store: s.Store.With(other), key: s.key}
+}
+
+func (s *userSecondFactorsStore) WithTransact(ctx context.Context, f func(UserSecondFactorsStore) error) error {
+	return s.Store.WithTransact(ctx, func(tx *basestore.Store) error {
+		return f(&userSecondFactorsStore{Store: tx, key: s.key})
+	})
+}
+
+func (s *userSecondFactorsStore) HasSecondFactor(ctx context.Context, userID int32) (bool, error) {
+	q := sqlf.Sprintf(`
+SELECT
+	EXISTS (SELECT 1 FROM user_totp_credentials WHERE user_id = %s AND confirmed_at IS NOT NULL)
+	OR EXISTS (SELECT 1 FROM user_webauthn_credentials WHERE user_id = %s)
+`, userID, userID)
+	has, _, err := basestore.ScanFirstBool(s.Query(ctx, q))
+	return has, err
+}
+
+func (s *userSecondFactorsStore) GetTOTP(ctx context.Context, userID int32) (*UserTOTPCredential, error) {
+	q := sqlf.Sprintf(`
+SELECT id, user_id, secret, encryption_key_id, last_used_step, confirmed_at, created_at
+FROM user_totp_credentials
+WHERE user_id = %s
+`, userID)
+
+	var (
+		cred   UserTOTPCredential
+		secret []byte
+		keyID  string
+	)
+	err := s.QueryRow(ctx, q).Scan(
+		&cred.ID,
+		&cred.UserID,
+		&secret,
+		&keyID,
+		&cred.LastUsedStep,
+		&cred.ConfirmedAt,
+		&cred.CreatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, SecondFactorNotFoundErr{userID: userID}
+		}
+		return nil, err
+	}
+
+	cred.Secret, err = encryption.MaybeDecrypt(ctx, s.key, string(secret), keyID)
+	if err != nil {
+		return nil, errors.Wrap(err, "decrypting TOTP secret")
+	}
+	return &cred, nil
+}
+
+func (s *userSecondFactorsStore) EnrollTOTP(ctx context.Context, userID int32, secret string) error {
+	encrypted, keyID, err := encryption.MaybeEncrypt(ctx, s.key, secret)
+	if err != nil {
+		return errors.Wrap(err, "encrypting TOTP secret")
+	}
+
+	q := sqlf.Sprintf(`
+INSERT INTO user_totp_credentials (user_id, secret, encryption_key_id)
+VALUES (%s, %s, %s)
+ON CONFLICT (user_id) DO UPDATE
+SET secret = EXCLUDED.secret, encryption_key_id = EXCLUDED.encryption_key_id, last_used_step = 0, created_at = NOW()
+WHERE user_totp_credentials.confirmed_at IS NULL
+`, userID, []byte(encrypted), keyID)
+	res, err := s.ExecResult(ctx, q)
+	if err != nil {
+		return err
+	}
+	if rows, err := res.RowsAffected(); err != nil {
+		return err
+	} else if rows == 0 {
+		return ErrTOTPAlreadyConfirmed
+	}
+	return nil
+}
+
+func (s *userSecondFactorsStore) ConfirmTOTP(ctx context.Context, userID int32, step int64) (bool, error) {
+	q := sqlf.Sprintf(`
+UPDATE user_totp_credentials
+SET confirmed_at = NOW(), last_used_step = %s
+WHERE user_id = %s AND confirmed_at IS NULL
+`, step, userID)
+	return s.execAffectsRow(ctx, q)
+}
+
+func (s *userSecondFactorsStore) UseTOTPStep(ctx context.Context, userID int32, step int64) (bool, error) {
+	q := sqlf.Sprintf(`
+UPDATE user_totp_credentials
+SET last_used_step = %s
+WHERE user_id = %s AND confirmed_at IS NOT NULL AND last_used_step < %s
+`, step, userID, step)
+	return s.execAffectsRow(ctx, q)
+}
+
+func (s *userSecondFactorsStore) DeleteTOTP(ctx context.Context, userID int32) error {
+	return s.Exec(ctx, sqlf.Sprintf("DELETE FROM user_totp_credentials WHERE user_id = %s", userID))
+}
+
+func (s *userSecondFactorsStore) execAffectsRow(ctx context.Context, q *sqlf.Query) (bool, error) {
+	res, err := s.ExecResult(ctx, q)
+	if err != nil {
+		return false, err
+	}
+	rows, err := res.RowsAffected()
+	return rows > 0, err
+}
+
+const userWebAuthnCredentialColumns = `id, user_id, name, credential_id, public_key, sign_count, created_at, last_used_at`
+
+func (s *userSecondFactorsStore) ListWebAuthnCredentials(ctx context.Context, userID int32) ([]*UserWebAuthnCredential, error) {
+	q := sqlf.Sprintf(`
+SELECT `+userWebAuthnCredentialColumns+`
+FROM user_webauthn_credentials
+WHERE user_id = %s
+ORDER BY id
+`, userID)
+	return scanUserWebAuthnCredentials(s.Query(ctx, q))
+}
+
+func (s *userSecondFactorsStore) GetWebAuthnCredential(ctx context.Context, userID int32, credentialID []byte) (*UserWebAuthnCredential, error) {
+	q := sqlf.Sprintf(`
+SELECT `+userWebAuthnCredentialColumns+`
+FROM user_webauthn_credentials
+WHERE user_id = %s AND credential_id = %s
+`, userID, credentialID)
+	cred, ok, err := scanFirstUserWebAuthnCredential(s.Query(ctx, q))
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, SecondFactorNotFoundErr{userID: userID}
+	}
+	return cred, nil
+}
+
+func (s *userSecondFactorsStore) CreateWebAuthnCredential(ctx context.Context, cred *UserWebAuthnCredential) error {
+	q := sqlf.Sprintf(`
+INSERT INTO user_webauthn_credentials (user_id, name, credential_id, public_key, sign_count)
+VALUES (%s, %s, %s, %s, %s)
+RETURNING id, created_at
+`, cred.UserID, cred.Name, cred.CredentialID, cred.PublicKey, int64(cred.SignCount))
+	return s.QueryRow(ctx, q).Scan(&cred.ID, &cred.CreatedAt)
+}
+
+func (s *userSecondFactorsStore) UpdateWebAuthnSignCount(ctx context.Context, id int32, signCount uint32) error {
+	q := sqlf.Sprintf(`
+UPDATE user_webauthn_credentials
+SET sign_count = %s, last_used_at = NOW()
+WHERE id = %s
+`, int64(signCount), id)
+	return s.Exec(ctx, q)
+}
+
+func (s *userSecondFactorsStore) DeleteWebAuthnCredential(ctx context.Context, userID, id int32) error {
+	q := sqlf.Sprintf("DELETE FROM user_webauthn_credentials WHERE user_id = %s AND id = %s", userID, id)
+	deleted, err := s.execAffectsRow(ctx, q)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return SecondFactorNotFoundErr{userID: userID}
+	}
+	return nil
+}
+
+func scanUserWebAuthnCredential(s dbutil.Scanner) (*UserWebAuthnCredential, error) {
+	var (
+		cred      UserWebAuthnCredential
+		signCount int64
+	)
+	if err := s.Scan(
+		&cred.ID,
+		&cred.UserID,
+		&cred.Name,
+		&cred.CredentialID,
+		&cred.PublicKey,
+		&signCount,
+		&cred.CreatedAt,
+		&cred.LastUsedAt,
+	); err != nil {
+		return nil, err
+	}
+	cred.SignCount = uint32(signCount)
+	return &cred, nil
+}
+
+var (
+	scanUserWebAuthnCredentials     = basestore.NewSliceScanner(scanUserWebAuthnCredential)
+	scanFirstUserWebAuthnCredential = basestore.NewFirstScanner(scanUserWebAuthnCredential)
+)
+
+func (s *userSecondFactorsStore) ReplaceRecoveryCodes(ctx context.Context, userID int32, codes []string) error {
+	return s.Store.WithTransact(ctx, func(tx *basestore.Store) error {
+		if err := tx.Exec(ctx, sqlf.Sprintf("DELETE FROM user_recovery_codes WHERE user_id = %s", userID)); err != nil {
+			return err
+		}
+
+		for _, code := range codes {
+			encrypted, keyID, err := encryption.MaybeEncrypt(ctx, s.key, code)
+			if err != nil {
+				return errors.Wrap(err, "encrypting recovery code")
+			}
+			q := sqlf.Sprintf(
+				"INSERT INTO user_recovery_codes (user_id, code, encryption_key_id) VALUES (%s, %s, %s)",
+				userID, []byte(encrypted), keyID,
+			)
+			if err := tx.Exec(ctx, q); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+type encryptedRecoveryCode struct {
+	id    int32
+	code  []byte
+	keyID string
+}
+
+var scanEncryptedRecoveryCodes = basestore.NewSliceScanner(func(s dbutil.Scanner) (c encryptedRecoveryCode, err error) {
+	err = s.Scan(&c.id, &c.code, &c.keyID)
+	return c, err
+})
+
+func (s *userSecondFactorsStore) UseRecoveryCode(ctx context.Context, userID int32, code string) (used bool, err error) {
+	if code == "" {
+		return false, nil
+	}
+
+	err = s.Store.WithTransact(ctx, func(tx *basestore.Store) error {
+		// The codes are locked so that concurrent requests cannot use the same
+		// code twice.
+		q := sqlf.Sprintf(`
+SELECT id, code, encryption_key_id
+FROM user_recovery_codes
+WHERE user_id = %s AND used_at IS NULL
+ORDER BY id
+FOR UPDATE
+`, userID)
+		candidates, err := scanEncryptedRecoveryCodes(tx.Query(ctx, q))
+		if err != nil {
+			return err
+		}
+
+		for _, c := range candidates {
+			decrypted, err := encryption.MaybeDecrypt(ctx, s.key, string(c.code), c.keyID)
+			if err != nil {
+				return errors.Wrap(err, "decrypting recovery code")
+			}
+			if subtle.ConstantTimeCompare([]byte(decrypted), []byte(code)) != 1 {
+				continue
+			}
+
+			used = true
+			return tx.Exec(ctx, sqlf.Sprintf("UPDATE user_recovery_codes SET used_at = NOW() WHERE id = %s", c.id))
+		}
+		return nil
+	})
+	return used, err
+}
+
+func (s *userSecondFactorsStore) CountRecoveryCodes(ctx context.Context, userID int32) (int, error) {
+	q := sqlf.Sprintf("SELECT COUNT(*) FROM user_recovery_codes WHERE user_id = %s AND used_at IS NULL", userID)
+	count, _, err := basestore.ScanFirstInt(s.Query(ctx, q))
+	return count, err
+}
+
+func (s *userSecondFactorsStore) DeleteRecoveryCodes(ctx context.Context, userID int32) error {
+	return s.Exec(ctx, sqlf.Sprintf("DELETE FROM user_recovery_codes WHERE user_id = %s", userID))
+}
@@ -0,0 +1,152 @@
+package database
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sourcegraph/log/logtest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcegraph/sourcegraph/internal/database/dbtest"
+	et "github.com/sourcegraph/sourcegraph/internal/encryption/testing"
+	"github.com/sourcegraph/sourcegraph/internal/errcode"
+)
+
+func TestUserSecondFactors(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	ctx := context.Background()
+	logger := logtest.Scoped(t)
+	db := NewDB(logger, dbtest.NewDB(t))
+	store := db.UserSecondFactors(et.TestKey{})
+
+	user, err := db.Users().Create(ctx, NewUser{Username: "alice"})
+	require.NoError(t, err)
+
+	has, err := store.HasSecondFactor(ctx, user.ID)
+	require.NoError(t, err)
+	assert.False(t, has)
+
+	t.Run("TOTP", func(t *testing.T) {
+		_, err := store.GetTOTP(ctx, user.ID)
+		assert.True(t, errcode.IsNotFound(err))
+
+		require.NoError(t, store.EnrollTOTP(ctx, user.ID, "first"))
+		require.NoError(t, store.EnrollTOTP(ctx, user.ID, "JBSWY3DPEHPK3PXP"))
+
+		// The secret is encrypted at rest.
+		var stored []byte
+		require.NoError(t, db.QueryRowContext(ctx, "SELECT secret FROM user_totp_credentials WHERE user_id = $1", user.ID).Scan(&stored))
+		assert.NotEqual(t, "JBSWY3DPEHPK3PXP", string(stored))
+
+		cred, err := store.GetTOTP(ctx, user.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "JBSWY3DPEHPK3PXP", cred.Secret)
+		assert.Nil(t, cred.ConfirmedAt)
+
+		// Unconfirmed secrets are not a second factor and cannot be used.
+		has, err := store.HasSecondFactor(ctx, user.ID)
+		require.NoError(t, err)
+		assert.False(t, has)
+		used, err := store.UseTOTPStep(ctx, user.ID, 100)
+		require.NoError(t, err)
+		assert.False(t, used)
+
+		confirmed, err := store.ConfirmTOTP(ctx, user.ID, 100)
+		require.NoError(t, err)
+		assert.True(t, confirmed)
+		confirmed, err = store.ConfirmTOTP(ctx, user.ID, 101)
+		require.NoError(t, err)
+		assert.False(t, confirmed)
+
+		has, err = store.HasSecondFactor(ctx, user.ID)
+		require.NoError(t, err)
+		assert.True(t, has)
+
+		assert.Equal(t, ErrTOTPAlreadyConfirmed, store.EnrollTOTP(ctx, user.ID, "other"))
+
+		// Codes of the step used to confirm, or of earlier steps, are rejected.
+		for step, want := range map[int64]bool{99: false, 100: false} {
+			used, err := store.UseTOTPStep(ctx, user.ID, step)
+			require.NoError(t, err)
+			assert.Equal(t, want, used, "step %d", step)
+		}
+		used, err = store.UseTOTPStep(ctx, user.ID, 101)
+		require.NoError(t, err)
+		assert.True(t, used)
+
+		require.NoError(t, store.DeleteTOTP(ctx, user.ID))
+		has, err = store.HasSecondFactor(ctx, user.ID)
+		require.NoError(t, err)
+		assert.False(t, has)
+	})
+
+	t.Run("WebAuthn", func(t *testing.T) {
+		cred := &UserWebAuthnCredential{
+			UserID:       user.ID,
+			Name:         "YubiKey",
+			CredentialID: []byte("credential"),
+			PublicKey:    []byte("public key"),
+			SignCount:    1,
+		}
+		require.NoError(t, store.CreateWebAuthnCredential(ctx, cred))
+		assert.NotZero(t, cred.ID)
+
+		has, err := store.HasSecondFactor(ctx, user.ID)
+		require.NoError(t, err)
+		assert.True(t, has)
+
+		require.NoError(t, store.UpdateWebAuthnSignCount(ctx, cred.ID, 5))
+		got, err := store.GetWebAuthnCredential(ctx, user.ID, []byte("credential"))
+		require.NoError(t, err)
+		assert.Equal(t, "YubiKey", got.Name)
+		assert.Equal(t, []byte("public key"), got.PublicKey)
+		assert.Equal(t, uint32(5), got.SignCount)
+		assert.NotNil(t, got.LastUsedAt)
+
+		_, err = store.GetWebAuthnCredential(ctx, user.ID, []byte("other"))
+		assert.True(t, errcode.IsNotFound(err))
+
+		creds, err := store.ListWebAuthnCredentials(ctx, user.ID)
+		require.NoError(t, err)
+		require.Len(t, creds, 1)
+		assert.Equal(t, cred.ID, creds[0].ID)
+
+		require.NoError(t, store.DeleteWebAuthnCredential(ctx, user.ID, cred.ID))
+		assert.True(t, errcode.IsNotFound(store.DeleteWebAuthnCredential(ctx, user.ID, cred.ID)))
+	})
+
+	t.Run("recovery codes", func(t *testing.T) {
+		require.NoError(t, store.ReplaceRecoveryCodes(ctx, user.ID, []string{"old"}))
+		require.NoError(t, store.ReplaceRecoveryCodes(ctx, user.ID, []string{"aaaaa-aaaaa", "bbbbb-bbbbb"}))
+
+		count, err := store.CountRecoveryCodes(ctx, user.ID)
+		require.NoError(t, err)
+		assert.Equal(t, 2, count)
+
+		for code, want := range map[string]bool{"old": false, "": false, "aaaaa-aaaab": false} {
+			used, err := store.UseRecoveryCode(ctx, user.ID, code)
+			require.NoError(t, err)
+			assert.Equal(t, want, used, code)
+		}
+
+		used, err := store.UseRecoveryCode(ctx, user.ID, "bbbbb-bbbbb")
+		require.NoError(t, err)
+		assert.True(t, used)
+		used, err = store.UseRecoveryCode(ctx, user.ID, "bbbbb-bbbbb")
+		require.NoError(t, err)
+		assert.False(t, used)
+
+		count, err = store.CountRecoveryCodes(ctx, user.ID)
+		require.NoError(t, err)
+		assert.Equal(t, 1, count)
+
+		require.NoError(t, store.DeleteRecoveryCodes(ctx, user.ID))
+		count, err = store.CountRecoveryCodes(ctx, user.ID)
+		require.NoError(t, err)
+		assert.Equal(t, 0, count)
+	})
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/encryption/cache"
 	"github.com/sourcegraph/sourcegraph/internal/encryption/cloudkms"
 	"github.com/sourcegraph/sourcegraph/internal/encryption/mounted"
+	"github.com/sourcegraph/sourcegraph/internal/encryption/vaulttransit"
 	"github.com/sourcegraph/sourcegraph/lib/errors"
 	"github.com/sourcegraph/sourcegraph/schema"
 )
@@ -113,6 +114,13 @@ func NewRing(ctx context.Context, keyConfig *schema.EncryptionKeys) (*Ring, erro
 		}
 	}
 
+	if keyConfig.UserSecondFactorKey != nil {
+		r.UserSecondFactorKey, err = NewKey(ctx, keyConfig.UserSecondFactorKey, keyConfig)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	if keyConfig.WebhookKey != nil {
 		r.WebhookKey, err = NewKey(ctx, keyConfig.WebhookKey, keyConfig)
 		if err != nil {
@@ -143,6 +151,7 @@ type Ring struct {
 	GitHubAppKey              encryption.Key
 	OutboundWebhookKey        encryption.Key
 	UserExternalAccountKey    encryption.Key
+	UserSecondFactorKey       encryption.Key
 	WebhookKey                encryption.Key
 	WebhookLogKey             encryption.Key
 	ExecutorSecretKey         encryption.Key
@@ -163,6 +172,8 @@ func NewKey(ctx context.Context, k *schema.EncryptionKey, config *schema.Encrypt
 		key, err = awskms.NewKey(ctx, *k.Awskms)
 	case k.Mounted != nil:
 		key, err = mounted.NewKey(ctx, *k.Mounted)
+	case k.Vaulttransit != nil:
+		key, err = vaulttransit.NewKey(ctx, *k.Vaulttransit)
 	case k.Noop != nil:
 		key = &encryption.NoopKey{}
 	default:
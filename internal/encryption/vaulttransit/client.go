@@ -0,0 +1,248 @@
+package vaulttransit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/internal/httpcli"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+// client is a minimal client of the Vault HTTP API that authenticates lazily and
+// authenticates again when its token expires or is rejected.
+type client struct {
+	doer      httpcli.Doer
+	address   *url.URL
+	namespace string
+	auth      authMethod
+
+	mu        sync.Mutex
+	token     string
+	expiresAt time.Time
+}
+
+// authMethod obtains a Vault token.
+type authMethod interface {
+	// login returns a token and its time to live, which is zero if it is unknown.
+	login(ctx context.Context, c *client) (token string, ttl time.Duration, err error)
+}
+
+// tokenRenewalMargin is how long before its expiry a token is replaced.
+const tokenRenewalMargin = 30 * time.Second
+
+func newAuthMethod(keyConfig schema.VaultTransitEncryptionKey) (authMethod, error) {
+	token := keyConfig.TokenEnvVarName != "" || keyConfig.TokenFile != ""
+	appRole := keyConfig.RoleId != "" || keyConfig.SecretIdEnvVarName != "" || keyConfig.SecretIdFile != ""
+
+	switch {
+	case token && appRole:
+		return nil, errors.New("must use only one of token and AppRole authentication")
+	case token:
+		src, err := newSecretSource("token", keyConfig.TokenEnvVarName, keyConfig.TokenFile)
+		if err != nil {
+			return nil, err
+		}
+		return &tokenAuth{token: src}, nil
+	case appRole:
+		if keyConfig.RoleId == "" {
+			return nil, errors.New("roleId is required for AppRole authentication")
+		}
+		src, err := newSecretSource("secretId", keyConfig.SecretIdEnvVarName, keyConfig.SecretIdFile)
+		if err != nil {
+			return nil, err
+		}
+		mountPath := strings.Trim(keyConfig.AppRoleMountPath, "/")
+		if mountPath == "" {
+			mountPath = "approle"
+		}
+		return &appRoleAuth{mountPath: mountPath, roleID: keyConfig.RoleId, secretID: src}, nil
+	default:
+		return nil, errors.New("must configure either token or AppRole authentication")
+	}
+}
+
+// secretSource reads a secret from an environment variable or a file.
+type secretSource struct {
+	envVarName string
+	filepath   string
+}
+
+func newSecretSource(name, envVarName, filepath string) (*secretSource, error) {
+	if (envVarName == "") == (filepath == "") {
+		return nil, errors.Errorf(
+			"must use only one of %sEnvVarName and %sFile, %sEnvVarName: %q, %sFile: %q",
+			name, name, name, envVarName, name, filepath,
+		)
+	}
+	return &secretSource{envVarName: envVarName, filepath: filepath}, nil
+}
+
+func (s *secretSource) read() (string, error) {
+	if s.envVarName != "" {
+		v := os.Getenv(s.envVarName)
+		if v == "" {
+			return "", errors.Errorf("environment variable %q is empty", s.envVarName)
+		}
+		return v, nil
+	}
+
+	b, err := os.ReadFile(s.filepath)
+	if err != nil {
+		return "", errors.Wrapf(err, "reading secret file %q", s.filepath)
+	}
+	return strings.TrimSpace(string(b)), nil
+}
+
+// tokenAuth uses a token issued outside of Sourcegraph. It is read again on every
+// login so that tokens renewed by e.g. a Vault Agent are picked up.
+type tokenAuth struct {
+	token *secretSource
+}
+
+func (a *tokenAuth) login(context.Context, *client) (string, time.Duration, error) {
+	token, err := a.token.read()
+	return token, 0, err
+}
+
+// appRoleAuth logs in with the AppRole auth method.
+type appRoleAuth struct {
+	mountPath string
+	roleID    string
+	secretID  *secretSource
+}
+
+func (a *appRoleAuth) login(ctx context.Context, c *client) (string, time.Duration, error) {
+	secretID, err := a.secretID.read()
+	if err != nil {
+		return "", 0, err
+	}
+
+	var res struct {
+		Auth struct {
+			ClientToken   string `json:"client_token"`
+			LeaseDuration int    `json:"lease_duration"`
+		} `json:"auth"`
+	}
+	body := map[string]string{"role_id": a.roleID, "secret_id": secretID}
+	if err := c.send(ctx, "POST", "/v1/auth/"+a.mountPath+"/login", "", body, &res); err != nil {
+		return "", 0, errors.Wrap(err, "logging in with AppRole")
+	}
+	if res.Auth.ClientToken == "" {
+		return "", 0, errors.New("logging in with AppRole: no token returned")
+	}
+	return res.Auth.ClientToken, time.Duration(res.Auth.LeaseDuration) * time.Second, nil
+}
+
+// currentToken returns the current token, logging in if there is none or it is
+// about to expire.
+func (c *client) currentToken(ctx context.Context) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.token != "" && (c.expiresAt.IsZero() || time.Now().Before(c.expiresAt.Add(-tokenRenewalMargin))) {
+		return c.token, nil
+	}
+
+	token, ttl, err := c.auth.login(ctx, c)
+	if err != nil {
+		return "", err
+	}
+	c.token = token
+	c.expiresAt = time.Time{}
+	if ttl > 0 {
+		c.expiresAt = time.Now().Add(ttl)
+	}
+	return c.token, nil
+}
+
+// resetToken discards the given token if it is still the current one, so that the
+// next request logs in again.
+func (c *client) resetToken(token string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.token == token {
+		c.token = ""
+	}
+}
+
+// do sends an authenticated request. If the token is rejected, it logs in again
+// and retries the request once.
+func (c *client) do(ctx context.Context, method, path string, body, out any) error {
+	for attempt := 0; ; attempt++ {
+		token, err := c.currentToken(ctx)
+		if err != nil {
+			return err
+		}
+
+		err = c.send(ctx, method, path, token, body, out)
+		var apiErr *apiError
+		if attempt == 0 && errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusForbidden {
+			c.resetToken(token)
+			continue
+		}
+		return err
+	}
+}
+
+// apiError is an error response of the Vault API.
+type apiError struct {
+	StatusCode int      `json:"-"`
+	Errors     []string `json:"errors"`
+}
+
+func (e *apiError) Error() string {
+	if len(e.Errors) == 0 {
+		return "Vault API returned status " + http.StatusText(e.StatusCode)
+	}
+	return "Vault API returned status " + http.StatusText(e.StatusCode) + ": " + strings.Join(e.Errors, "; ")
+}
+
+func (c *client) send(ctx context.Context, method, path, token string, body, out any) error {
+	var reqBody io.Reader
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reqBody = bytes.NewReader(b)
+	}
+
+	u := *c.address
+	u.Path = strings.TrimSuffix(u.Path, "/") + path
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), reqBody)
+	if err != nil {
+		return err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if token != "" {
+		req.Header.Set("X-Vault-Token", token)
+	}
+	if c.namespace != "" {
+		req.Header.Set("X-Vault-Namespace", c.namespace)
+	}
+
+	resp, err := c.doer.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		apiErr := &apiError{StatusCode: resp.StatusCode}
+		// The body is only used for a more descriptive error.
+		_ = json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(apiErr)
+		return apiErr
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
@@ -0,0 +1,174 @@
+package vaulttransit
+
+import (
+	"context"
+	"encoding/base64"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/sourcegraph/sourcegraph/internal/encryption"
+	"github.com/sourcegraph/sourcegraph/internal/encryption/envelope"
+	"github.com/sourcegraph/sourcegraph/internal/encryption/wrapper"
+	"github.com/sourcegraph/sourcegraph/internal/httpcli"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+// mechanismEnvelope is the only mechanism used by this key: values are encrypted
+// with a random data key, which is in turn encrypted by Vault.
+const mechanismEnvelope = "envelope"
+
+func NewKey(ctx context.Context, keyConfig schema.VaultTransitEncryptionKey) (encryption.Key, error) {
+	return newKey(ctx, keyConfig, httpcli.ExternalDoer)
+}
+
+func newKey(ctx context.Context, keyConfig schema.VaultTransitEncryptionKey, doer httpcli.Doer) (*Key, error) {
+	if keyConfig.Keyname == "" {
+		return nil, errors.New("keyname is required")
+	}
+	address, err := url.Parse(keyConfig.Address)
+	if err != nil {
+		return nil, errors.Wrap(err, "parsing Vault address")
+	}
+	if address.Scheme == "" || address.Host == "" {
+		return nil, errors.Errorf("invalid Vault address %q", keyConfig.Address)
+	}
+
+	auth, err := newAuthMethod(keyConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	mountPath := strings.Trim(keyConfig.MountPath, "/")
+	if mountPath == "" {
+		mountPath = "transit"
+	}
+
+	k := &Key{
+		keyname:   keyConfig.Keyname,
+		mountPath: mountPath,
+		client: &client{
+			doer:      doer,
+			address:   address,
+			namespace: keyConfig.Namespace,
+			auth:      auth,
+		},
+	}
+	// Test client connection.
+	_, err = k.Version(ctx)
+	return k, err
+}
+
+// Key is an encryption.Key implementation that uses a key of the HashiCorp Vault
+// transit secrets engine to encrypt the data keys of envelope encrypted values.
+//
+// Vault keeps all versions of a key after it is rotated, so values encrypted with
+// an older version can still be decrypted, while new values are always encrypted
+// with the latest version.
+type Key struct {
+	keyname   string
+	mountPath string
+	client    *client
+}
+
+// Version returns the latest version of the key, which changes whenever the key
+// is rotated in Vault.
+func (k *Key) Version(ctx context.Context) (encryption.KeyVersion, error) {
+	var res struct {
+		Data struct {
+			LatestVersion      int  `json:"latest_version"`
+			SupportsEncryption bool `json:"supports_encryption"`
+			SupportsDecryption bool `json:"supports_decryption"`
+		} `json:"data"`
+	}
+	if err := k.client.do(ctx, "GET", k.path("keys"), nil, &res); err != nil {
+		return encryption.KeyVersion{}, errors.Wrap(err, "getting key version")
+	}
+	if !res.Data.SupportsEncryption || !res.Data.SupportsDecryption {
+		return encryption.KeyVersion{}, errors.Errorf("key %q does not support encryption and decryption", k.keyname)
+	}
+	return encryption.KeyVersion{
+		Type:    "vaulttransit",
+		Name:    k.keyname,
+		Version: strconv.Itoa(res.Data.LatestVersion),
+	}, nil
+}
+
+// Encrypt encrypts the value with a data key encrypted by the latest version of
+// the Vault key.
+func (k *Key) Encrypt(ctx context.Context, plaintext []byte) ([]byte, error) {
+	ev, err := envelope.Encrypt(plaintext)
+	if err != nil {
+		return nil, errors.Wrap(err, "envelope encrypting payload")
+	}
+
+	var res struct {
+		Data struct {
+			Ciphertext string `json:"ciphertext"`
+		} `json:"data"`
+	}
+	body := map[string]string{"plaintext": base64.StdEncoding.EncodeToString(ev.Key)}
+	if err := k.client.do(ctx, "POST", k.path("encrypt"), body, &res); err != nil {
+		return nil, errors.Wrap(err, "encrypting envelope key")
+	}
+
+	ek := wrapper.StorableEncryptedKey{
+		Mechanism: mechanismEnvelope,
+		KeyName:   k.keyname,
+		// The Vault ciphertext is prefixed with the version of the key it was
+		// encrypted with, e.g. "vault:v2:...".
+		WrappedKey: []byte(res.Data.Ciphertext),
+		Ciphertext: ev.Ciphertext,
+		Nonce:      ev.Nonce,
+	}
+
+	return ek.Serialize()
+}
+
+// Decrypt decrypts a value encrypted by any version of the Vault key that has
+// not been trimmed or excluded by its minimum decryption version.
+func (k *Key) Decrypt(ctx context.Context, cipherText []byte) (*encryption.Secret, error) {
+	wr, err := wrapper.FromCiphertext(cipherText)
+	if err != nil {
+		return nil, err
+	}
+
+	if wr.Mechanism != mechanismEnvelope {
+		return nil, errors.Newf("invalid mechanism %q", wr.Mechanism)
+	}
+	if wr.KeyName != k.keyname {
+		return nil, errors.New("invalid key name, are you trying to decrypt something with the wrong key?")
+	}
+
+	var res struct {
+		Data struct {
+			Plaintext string `json:"plaintext"`
+		} `json:"data"`
+	}
+	body := map[string]string{"ciphertext": string(wr.WrappedKey)}
+	if err := k.client.do(ctx, "POST", k.path("decrypt"), body, &res); err != nil {
+		return nil, errors.Wrap(err, "decrypting envelope key")
+	}
+	key, err := base64.StdEncoding.DecodeString(res.Data.Plaintext)
+	if err != nil {
+		return nil, errors.Wrap(err, "decoding envelope key")
+	}
+
+	plaintext, err := envelope.Decrypt(&envelope.Envelope{
+		Key:        key,
+		Nonce:      wr.Nonce,
+		Ciphertext: wr.Ciphertext,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s := encryption.NewSecret(string(plaintext))
+	return &s, nil
+}
+
+// path returns the API path of the given transit endpoint for the key.
+func (k *Key) path(endpoint string) string {
+	return "/v1/" + k.mountPath + "/" + endpoint + "/" + k.keyname
+}
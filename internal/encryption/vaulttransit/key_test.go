@@ -0,0 +1,330 @@
+package vaulttransit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcegraph/sourcegraph/internal/encryption"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+// fakeVault is a stand-in for a Vault dev server with the transit secrets engine
+// mounted at "transit" and the AppRole auth method mounted at "approle".
+type fakeVault struct {
+	mu sync.Mutex
+	// tokens are the valid tokens.
+	tokens map[string]bool
+	// keys maps key names to their latest version.
+	keys map[string]int
+	// minDecryptionVersion is the minimum version of all keys that can decrypt.
+	minDecryptionVersion int
+	roleID, secretID     string
+	logins               int
+}
+
+func newFakeVault(t *testing.T) (*fakeVault, *httptest.Server) {
+	v := &fakeVault{
+		tokens:               map[string]bool{"root": true},
+		keys:                 map[string]int{"sourcegraph": 1},
+		minDecryptionVersion: 1,
+		roleID:               "role",
+		secretID:             "secret",
+	}
+	srv := httptest.NewServer(v)
+	t.Cleanup(srv.Close)
+	return v, srv
+}
+
+func (v *fakeVault) rotate(name string) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.keys[name]++
+}
+
+func (v *fakeVault) setMinDecryptionVersion(version int) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.minDecryptionVersion = version
+}
+
+func (v *fakeVault) addKey(name string) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.keys[name] = 1
+}
+
+func (v *fakeVault) loginCount() int {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	return v.logins
+}
+
+func (v *fakeVault) revokeTokens() {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.tokens = map[string]bool{}
+}
+
+func (v *fakeVault) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	writeError := func(status int, msg string) {
+		w.WriteHeader(status)
+		_ = json.NewEncoder(w).Encode(map[string]any{"errors": []string{msg}})
+	}
+	var body map[string]string
+	if r.Body != nil {
+		_ = json.NewDecoder(r.Body).Decode(&body)
+	}
+
+	if r.URL.Path == "/v1/auth/approle/login" {
+		if body["role_id"] != v.roleID || body["secret_id"] != v.secretID {
+			writeError(http.StatusBadRequest, "invalid role or secret ID")
+			return
+		}
+		v.logins++
+		token := fmt.Sprintf("approle-%d", v.logins)
+		v.tokens[token] = true
+		_ = json.NewEncoder(w).Encode(map[string]any{"auth": map[string]any{"client_token": token, "lease_duration": 3600}})
+		return
+	}
+
+	if !v.tokens[r.Header.Get("X-Vault-Token")] {
+		writeError(http.StatusForbidden, "permission denied")
+		return
+	}
+
+	parts := strings.Split(strings.TrimPrefix(r.URL.Path, "/v1/transit/"), "/")
+	if len(parts) != 2 {
+		writeError(http.StatusNotFound, "no handler for route")
+		return
+	}
+	endpoint, name := parts[0], parts[1]
+	latest, ok := v.keys[name]
+	if !ok {
+		writeError(http.StatusBadRequest, "encryption key not found")
+		return
+	}
+
+	switch endpoint {
+	case "keys":
+		_ = json.NewEncoder(w).Encode(map[string]any{"data": map[string]any{
+			"latest_version":      latest,
+			"supports_encryption": true,
+			"supports_decryption": true,
+		}})
+	case "encrypt":
+		// The ciphertext is not encrypted, which is good enough to test the client.
+		ciphertext := fmt.Sprintf("vault:v%d:%s:%s", latest, name, body["plaintext"])
+		_ = json.NewEncoder(w).Encode(map[string]any{"data": map[string]any{"ciphertext": ciphertext}})
+	case "decrypt":
+		fields := strings.SplitN(body["ciphertext"], ":", 4)
+		if len(fields) != 4 || fields[0] != "vault" || fields[2] != name {
+			writeError(http.StatusBadRequest, "invalid ciphertext")
+			return
+		}
+		version, _ := strconv.Atoi(strings.TrimPrefix(fields[1], "v"))
+		if version < v.minDecryptionVersion {
+			writeError(http.StatusBadRequest, "ciphertext version is disallowed by policy (too old)")
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"data": map[string]any{"plaintext": fields[3]}})
+	default:
+		writeError(http.StatusNotFound, "no handler for route")
+	}
+}
+
+func TestRoundtrip(t *testing.T) {
+	ctx := context.Background()
+	vault, srv := newFakeVault(t)
+	t.Setenv("TEST_VAULT_TOKEN", "root")
+
+	k, err := newKey(ctx, schema.VaultTransitEncryptionKey{
+		Type:            "vaulttransit",
+		Address:         srv.URL,
+		Keyname:         "sourcegraph",
+		TokenEnvVarName: "TEST_VAULT_TOKEN",
+	}, srv.Client())
+	require.NoError(t, err)
+
+	testString := strings.Repeat("test1234", 4096)
+	ciphertext, err := k.Encrypt(ctx, []byte(testString))
+	require.NoError(t, err)
+	assert.NotContains(t, string(ciphertext), testString)
+
+	plaintext, err := k.Decrypt(ctx, ciphertext)
+	require.NoError(t, err)
+	assert.Equal(t, testString, plaintext.Secret())
+
+	version, err := k.Version(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, encryption.KeyVersion{Type: "vaulttransit", Name: "sourcegraph", Version: "1"}, version)
+
+	t.Run("key rotation", func(t *testing.T) {
+		vault.rotate("sourcegraph")
+
+		version, err := k.Version(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, "2", version.Version)
+
+		// Values encrypted with the previous version can still be decrypted.
+		plaintext, err := k.Decrypt(ctx, ciphertext)
+		require.NoError(t, err)
+		assert.Equal(t, testString, plaintext.Secret())
+
+		rotated, err := k.Encrypt(ctx, []byte(testString))
+		require.NoError(t, err)
+		vault.setMinDecryptionVersion(2)
+
+		plaintext, err = k.Decrypt(ctx, rotated)
+		require.NoError(t, err)
+		assert.Equal(t, testString, plaintext.Secret())
+
+		_, err = k.Decrypt(ctx, ciphertext)
+		assert.ErrorContains(t, err, "too old")
+	})
+
+	t.Run("wrong key", func(t *testing.T) {
+		vault.addKey("other")
+		other, err := newKey(ctx, schema.VaultTransitEncryptionKey{
+			Address:         srv.URL,
+			Keyname:         "other",
+			TokenEnvVarName: "TEST_VAULT_TOKEN",
+		}, srv.Client())
+		require.NoError(t, err)
+
+		_, err = other.Decrypt(ctx, ciphertext)
+		assert.ErrorContains(t, err, "invalid key name")
+	})
+}
+
+func TestAppRole(t *testing.T) {
+	ctx := context.Background()
+	vault, srv := newFakeVault(t)
+
+	secretIDFile := filepath.Join(t.TempDir(), "secret-id")
+	require.NoError(t, os.WriteFile(secretIDFile, []byte("secret\n"), 0600))
+
+	k, err := newKey(ctx, schema.VaultTransitEncryptionKey{
+		Address:      srv.URL,
+		Keyname:      "sourcegraph",
+		RoleId:       "role",
+		SecretIdFile: secretIDFile,
+	}, srv.Client())
+	require.NoError(t, err)
+	assert.Equal(t, 1, vault.loginCount())
+
+	ciphertext, err := k.Encrypt(ctx, []byte("secret value"))
+	require.NoError(t, err)
+	assert.Equal(t, 1, vault.loginCount())
+
+	// A rejected token is replaced by logging in again.
+	vault.revokeTokens()
+	plaintext, err := k.Decrypt(ctx, ciphertext)
+	require.NoError(t, err)
+	assert.Equal(t, "secret value", plaintext.Secret())
+	assert.Equal(t, 2, vault.loginCount())
+
+	t.Run("invalid secret ID", func(t *testing.T) {
+		require.NoError(t, os.WriteFile(secretIDFile, []byte("wrong"), 0600))
+		vault.revokeTokens()
+
+		_, err := k.Encrypt(ctx, []byte("secret value"))
+		assert.ErrorContains(t, err, "invalid role or secret ID")
+	})
+}
+
+func TestNewKeyConfig(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		config schema.VaultTransitEncryptionKey
+		err    string
+	}{
+		{
+			name:   "no auth",
+			config: schema.VaultTransitEncryptionKey{Address: "https://vault.example.com", Keyname: "sourcegraph"},
+			err:    "must configure either token or AppRole authentication",
+		},
+		{
+			name: "token and AppRole",
+			config: schema.VaultTransitEncryptionKey{
+				Address:         "https://vault.example.com",
+				Keyname:         "sourcegraph",
+				TokenEnvVarName: "VAULT_TOKEN",
+				RoleId:          "role",
+			},
+			err: "must use only one of token and AppRole authentication",
+		},
+		{
+			name: "token env var and file",
+			config: schema.VaultTransitEncryptionKey{
+				Address:         "https://vault.example.com",
+				Keyname:         "sourcegraph",
+				TokenEnvVarName: "VAULT_TOKEN",
+				TokenFile:       "/vault/token",
+			},
+			err: "must use only one of tokenEnvVarName and tokenFile",
+		},
+		{
+			name: "AppRole without secret ID",
+			config: schema.VaultTransitEncryptionKey{
+				Address: "https://vault.example.com",
+				Keyname: "sourcegraph",
+				RoleId:  "role",
+			},
+			err: "must use only one of secretIdEnvVarName and secretIdFile",
+		},
+		{
+			name:   "invalid address",
+			config: schema.VaultTransitEncryptionKey{Address: "vault", Keyname: "sourcegraph", TokenFile: "/vault/token"},
+			err:    `invalid Vault address "vault"`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := newKey(context.Background(), tc.config, http.DefaultClient)
+			assert.ErrorContains(t, err, tc.err)
+		})
+	}
+}
+
+func TestVersionWithNamespace(t *testing.T) {
+	var namespace string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		namespace = r.Header.Get("X-Vault-Namespace")
+		assert.Equal(t, "/v1/secrets/transit/keys/sourcegraph", r.URL.Path)
+		_ = json.NewEncoder(w).Encode(map[string]any{"data": map[string]any{
+			"latest_version":      3,
+			"supports_encryption": true,
+			"supports_decryption": true,
+		}})
+	}))
+	t.Cleanup(srv.Close)
+	t.Setenv("TEST_VAULT_TOKEN", "root")
+
+	k, err := newKey(context.Background(), schema.VaultTransitEncryptionKey{
+		Address:         srv.URL,
+		Keyname:         "sourcegraph",
+		MountPath:       "/secrets/transit/",
+		Namespace:       "engineering",
+		TokenEnvVarName: "TEST_VAULT_TOKEN",
+	}, srv.Client())
+	require.NoError(t, err)
+	assert.Equal(t, "engineering", namespace)
+
+	version, err := k.Version(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "3", version.Version)
+}
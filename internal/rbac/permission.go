@@ -37,15 +37,43 @@ func CheckCurrentUserHasPermission(ctx context.Context, db database.DB, permissi
 	if user == nil {
 		return auth.ErrNotAuthenticated
 	}
-	return checkUserHasPermission(ctx, db, user, permission)
+	return checkUserHasPermission(ctx, db, user, permission, nil)
 }
 
 // CheckGivenUserHasPermission returns an error if the given user doesn't have a permission assigned to them.
 func CheckGivenUserHasPermission(ctx context.Context, db database.DB, user *types.User, permission string) error {
-	return checkUserHasPermission(ctx, db, user, permission)
+	return checkUserHasPermission(ctx, db, user, permission, nil)
 }
 
-func checkUserHasPermission(ctx context.Context, db database.DB, user *types.User, permission string) error {
+// Resource identifies the resource a permission is checked for. Roles that are assigned to a
+// user for an organization, search context or repository only grant their permissions on
+// resources within that scope.
+type Resource = database.PermissionResource
+
+// CheckCurrentUserHasPermissionForResource returns an error if the current user doesn't have a
+// permission assigned to them, either globally or for a scope that contains the given resource.
+func CheckCurrentUserHasPermissionForResource(ctx context.Context, db database.DB, permission string, resource Resource) error {
+	if actor.FromContext(ctx).IsInternal() {
+		return nil
+	}
+	// We check the current user exists and is authenticated.
+	user, err := auth.CurrentUser(ctx, db)
+	if err != nil {
+		return err
+	}
+	if user == nil {
+		return auth.ErrNotAuthenticated
+	}
+	return checkUserHasPermission(ctx, db, user, permission, &resource)
+}
+
+// CheckGivenUserHasPermissionForResource returns an error if the given user doesn't have a
+// permission assigned to them, either globally or for a scope that contains the given resource.
+func CheckGivenUserHasPermissionForResource(ctx context.Context, db database.DB, user *types.User, permission string, resource Resource) error {
+	return checkUserHasPermission(ctx, db, user, permission, &resource)
+}
+
+func checkUserHasPermission(ctx context.Context, db database.DB, user *types.User, permission string, resource *Resource) error {
 	namespace, action, err := ParsePermissionDisplayName(permission)
 	if err != nil {
 		return err
@@ -55,6 +83,7 @@ func checkUserHasPermission(ctx context.Context, db database.DB, user *types.Use
 		UserID:    user.ID,
 		Namespace: namespace,
 		Action:    action,
+		Resource:  resource,
 	})
 	if err != nil {
 		if errors.Is(err, &database.PermissionNotFoundErr{
@@ -113,6 +113,67 @@ func TestCheckGivenUserHasPermission(t *testing.T) {
 	}
 }
 
+func TestCheckGivenUserHasPermissionForResource(t *testing.T) {
+	ctx := context.Background()
+	db, u1, u2, p := setup(t, ctx)
+
+	r, err := db.Roles().Get(ctx, database.GetRoleOpts{Name: "TEST-ROLE"})
+	require.NoError(t, err)
+
+	org1, err := db.Orgs().Create(ctx, "org-1", nil)
+	require.NoError(t, err)
+	org2, err := db.Orgs().Create(ctx, "org-2", nil)
+	require.NoError(t, err)
+
+	err = db.ScopedUserRoles().Assign(ctx, database.AssignScopedUserRoleOpts{
+		UserID: u1.ID,
+		RoleID: r.ID,
+		Scope:  types.RoleScope{OrgID: org1.ID},
+	})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		user     *types.User
+		resource Resource
+
+		expectedErr error
+	}{
+		{
+			name:     "user with scoped role in scope",
+			user:     u1,
+			resource: Resource{OrgID: org1.ID},
+		},
+		{
+			name:        "user with scoped role out of scope",
+			user:        u1,
+			resource:    Resource{OrgID: org2.ID},
+			expectedErr: &ErrNotAuthorized{Permission: p.DisplayName()},
+		},
+		{
+			name:     "user with global role",
+			user:     u2,
+			resource: Resource{OrgID: org2.ID},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := CheckGivenUserHasPermissionForResource(ctx, db, tc.user, p.DisplayName(), tc.resource)
+			if tc.expectedErr != nil {
+				require.ErrorContains(t, err, tc.expectedErr.Error())
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+
+	t.Run("scoped role is not considered without a resource", func(t *testing.T) {
+		err := CheckGivenUserHasPermission(ctx, db, u1, p.DisplayName())
+		require.ErrorContains(t, err, (&ErrNotAuthorized{Permission: p.DisplayName()}).Error())
+	})
+}
+
 func setup(t *testing.T, ctx context.Context) (database.DB, *types.User, *types.User, *types.Permission) {
 	t.Helper()
 	logger := logtest.Scoped(t)
@@ -0,0 +1,402 @@
+package scim
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/elimity-com/scim"
+	scimerrors "github.com/elimity-com/scim/errors"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/auth"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/errcode"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+	"github.com/sourcegraph/sourcegraph/lib/pointers"
+)
+
+const (
+	AttrMembers     = "members"
+	AttrMemberValue = "value"
+)
+
+// GroupMapping is the Sourcegraph entity that SCIM groups are provisioned as.
+type GroupMapping string
+
+const (
+	GroupMappingOrganization GroupMapping = "organization"
+	GroupMappingTeam         GroupMapping = "team"
+)
+
+func getConfiguredGroupMapping() GroupMapping {
+	switch conf.Get().ScimGroupMapping {
+	case string(GroupMappingTeam):
+		return GroupMappingTeam
+	default:
+		return GroupMappingOrganization
+	}
+}
+
+// Group is a SCIM group, backed by either an organization or a team.
+type Group struct {
+	ID          int32
+	Name        string
+	DisplayName string
+	ExternalID  string
+	MemberIDs   []int32
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+
+	// scimGroupID is the ID of the record of the provisioned organization or team.
+	scimGroupID int32
+}
+
+func (g *Group) ToResource() scim.Resource {
+	members := make([]interface{}, 0, len(g.MemberIDs))
+	for _, id := range g.MemberIDs {
+		members = append(members, map[string]interface{}{
+			AttrMemberValue: strconv.Itoa(int(id)),
+		})
+	}
+
+	displayName := g.DisplayName
+	if displayName == "" {
+		displayName = g.Name
+	}
+
+	attributes := scim.ResourceAttributes{
+		AttrDisplayName: displayName,
+		AttrMembers:     members,
+	}
+	if g.ExternalID != "" {
+		attributes[AttrExternalId] = g.ExternalID
+	}
+
+	return scim.Resource{
+		ID:         strconv.Itoa(int(g.ID)),
+		ExternalID: getOptionalExternalID(attributes),
+		Attributes: attributes,
+		Meta: scim.Meta{
+			Created:      &g.CreatedAt,
+			LastModified: &g.UpdatedAt,
+		},
+	}
+}
+
+// groupStore stores SCIM groups as Sourcegraph organizations or teams. Only the
+// organizations or teams that were created by the SCIM client are groups; the
+// others are neither listed nor changed.
+type groupStore interface {
+	// Get returns the group with the given ID. It returns an error for which
+	// errcode.IsNotFound is true if the group does not exist.
+	Get(ctx context.Context, id int32) (*Group, error)
+	// NameExists reports whether an organization or team with the given name
+	// exists, whether it is a group or not.
+	NameExists(ctx context.Context, name string) (bool, error)
+	List(ctx context.Context, limitOffset *database.LimitOffset) ([]*Group, error)
+	Count(ctx context.Context) (int, error)
+	Create(ctx context.Context, name, displayName, externalID string) (*Group, error)
+	UpdateDisplayName(ctx context.Context, id int32, displayName string) error
+	UpdateExternalID(ctx context.Context, group *Group, externalID string) error
+	Delete(ctx context.Context, group *Group) error
+	AddMember(ctx context.Context, id, userID int32) error
+	RemoveMember(ctx context.Context, id, userID int32) error
+}
+
+// newGroupStore returns the groupStore for the configured group mapping.
+func newGroupStore(db database.DB) groupStore {
+	if getConfiguredGroupMapping() == GroupMappingTeam {
+		return &teamGroupStore{db: db}
+	}
+	return &orgGroupStore{db: db}
+}
+
+type orgGroupStore struct {
+	db database.DB
+}
+
+func (s *orgGroupStore) toGroup(ctx context.Context, org *types.Org, scimGroup *database.SCIMGroup) (*Group, error) {
+	memberships, err := s.db.OrgMembers().GetByOrgID(ctx, org.ID)
+	if err != nil {
+		return nil, errors.Wrap(err, "list organization members")
+	}
+	group := &Group{
+		ID:          org.ID,
+		Name:        org.Name,
+		DisplayName: pointers.Deref(org.DisplayName, ""),
+		ExternalID:  scimGroup.ExternalID,
+		CreatedAt:   org.CreatedAt,
+		UpdatedAt:   org.UpdatedAt,
+		scimGroupID: scimGroup.ID,
+	}
+	for _, m := range memberships {
+		group.MemberIDs = append(group.MemberIDs, m.UserID)
+	}
+	return group, nil
+}
+
+func (s *orgGroupStore) Get(ctx context.Context, id int32) (*Group, error) {
+	scimGroup, err := s.db.SCIMGroups().GetByOrgID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	org, err := s.db.Orgs().GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return s.toGroup(ctx, org, scimGroup)
+}
+
+func (s *orgGroupStore) NameExists(ctx context.Context, name string) (bool, error) {
+	_, err := s.db.Orgs().GetByName(ctx, name)
+	if err != nil {
+		if errcode.IsNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+func (s *orgGroupStore) List(ctx context.Context, limitOffset *database.LimitOffset) ([]*Group, error) {
+	scimGroups, err := s.db.SCIMGroups().List(ctx, database.SCIMGroupsListOptions{LimitOffset: limitOffset})
+	if err != nil {
+		return nil, err
+	}
+	groups := make([]*Group, 0, len(scimGroups))
+	for _, scimGroup := range scimGroups {
+		org, err := s.db.Orgs().GetByID(ctx, scimGroup.OrgID)
+		if err != nil {
+			return nil, err
+		}
+		group, err := s.toGroup(ctx, org, scimGroup)
+		if err != nil {
+			return nil, err
+		}
+		groups = append(groups, group)
+	}
+	return groups, nil
+}
+
+func (s *orgGroupStore) Count(ctx context.Context) (int, error) {
+	return s.db.SCIMGroups().Count(ctx, database.SCIMGroupsListOptions{})
+}
+
+func (s *orgGroupStore) Create(ctx context.Context, name, displayName, externalID string) (*Group, error) {
+	org, err := s.db.Orgs().Create(ctx, name, &displayName)
+	if err != nil {
+		return nil, err
+	}
+	scimGroup := &database.SCIMGroup{OrgID: org.ID, ExternalID: externalID}
+	if err := s.db.SCIMGroups().Create(ctx, scimGroup); err != nil {
+		return nil, err
+	}
+	return &Group{ID: org.ID, Name: org.Name, DisplayName: displayName, ExternalID: externalID, CreatedAt: org.CreatedAt, UpdatedAt: org.UpdatedAt, scimGroupID: scimGroup.ID}, nil
+}
+
+func (s *orgGroupStore) UpdateDisplayName(ctx context.Context, id int32, displayName string) error {
+	_, err := s.db.Orgs().Update(ctx, id, &displayName)
+	return err
+}
+
+func (s *orgGroupStore) UpdateExternalID(ctx context.Context, group *Group, externalID string) error {
+	return s.db.SCIMGroups().UpdateExternalID(ctx, group.scimGroupID, externalID)
+}
+
+func (s *orgGroupStore) Delete(ctx context.Context, group *Group) error {
+	// Organizations are soft-deleted, so the record of the group is deleted explicitly.
+	if err := s.db.SCIMGroups().Delete(ctx, group.scimGroupID); err != nil {
+		return err
+	}
+	return s.db.Orgs().Delete(ctx, group.ID)
+}
+
+func (s *orgGroupStore) AddMember(ctx context.Context, id, userID int32) error {
+	_, err := s.db.OrgMembers().Create(ctx, id, userID)
+	return err
+}
+
+func (s *orgGroupStore) RemoveMember(ctx context.Context, id, userID int32) error {
+	return s.db.OrgMembers().Remove(ctx, id, userID)
+}
+
+type teamGroupStore struct {
+	db database.DB
+}
+
+func (s *teamGroupStore) toGroup(ctx context.Context, team *types.Team, scimGroup *database.SCIMGroup) (*Group, error) {
+	members, _, err := s.db.Teams().ListTeamMembers(ctx, database.ListTeamMembersOpts{TeamID: team.ID})
+	if err != nil {
+		return nil, errors.Wrap(err, "list team members")
+	}
+	group := &Group{
+		ID:          team.ID,
+		Name:        team.Name,
+		DisplayName: team.DisplayName,
+		ExternalID:  scimGroup.ExternalID,
+		CreatedAt:   team.CreatedAt,
+		UpdatedAt:   team.UpdatedAt,
+		scimGroupID: scimGroup.ID,
+	}
+	for _, m := range members {
+		group.MemberIDs = append(group.MemberIDs, m.UserID)
+	}
+	return group, nil
+}
+
+func (s *teamGroupStore) Get(ctx context.Context, id int32) (*Group, error) {
+	scimGroup, err := s.db.SCIMGroups().GetByTeamID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	team, err := s.db.Teams().GetTeamByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return s.toGroup(ctx, team, scimGroup)
+}
+
+func (s *teamGroupStore) NameExists(ctx context.Context, name string) (bool, error) {
+	_, err := s.db.Teams().GetTeamByName(ctx, name)
+	if err != nil {
+		if errcode.IsNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+func (s *teamGroupStore) List(ctx context.Context, limitOffset *database.LimitOffset) ([]*Group, error) {
+	scimGroups, err := s.db.SCIMGroups().List(ctx, database.SCIMGroupsListOptions{Teams: true, LimitOffset: limitOffset})
+	if err != nil {
+		return nil, err
+	}
+	groups := make([]*Group, 0, len(scimGroups))
+	for _, scimGroup := range scimGroups {
+		team, err := s.db.Teams().GetTeamByID(ctx, scimGroup.TeamID)
+		if err != nil {
+			return nil, err
+		}
+		group, err := s.toGroup(ctx, team, scimGroup)
+		if err != nil {
+			return nil, err
+		}
+		groups = append(groups, group)
+	}
+	return groups, nil
+}
+
+func (s *teamGroupStore) Count(ctx context.Context) (int, error) {
+	return s.db.SCIMGroups().Count(ctx, database.SCIMGroupsListOptions{Teams: true})
+}
+
+func (s *teamGroupStore) Create(ctx context.Context, name, displayName, externalID string) (*Group, error) {
+	// Teams provisioned via SCIM are read-only so that their membership is
+	// only ever managed by the identity provider.
+	team, err := s.db.Teams().CreateTeam(ctx, &types.Team{Name: name, DisplayName: displayName, ReadOnly: true})
+	if err != nil {
+		return nil, err
+	}
+	scimGroup := &database.SCIMGroup{TeamID: team.ID, ExternalID: externalID}
+	if err := s.db.SCIMGroups().Create(ctx, scimGroup); err != nil {
+		return nil, err
+	}
+	return &Group{ID: team.ID, Name: team.Name, DisplayName: team.DisplayName, ExternalID: externalID, CreatedAt: team.CreatedAt, UpdatedAt: team.UpdatedAt, scimGroupID: scimGroup.ID}, nil
+}
+
+func (s *teamGroupStore) UpdateDisplayName(ctx context.Context, id int32, displayName string) error {
+	team, err := s.db.Teams().GetTeamByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	team.DisplayName = displayName
+	return s.db.Teams().UpdateTeam(ctx, team)
+}
+
+func (s *teamGroupStore) UpdateExternalID(ctx context.Context, group *Group, externalID string) error {
+	return s.db.SCIMGroups().UpdateExternalID(ctx, group.scimGroupID, externalID)
+}
+
+func (s *teamGroupStore) Delete(ctx context.Context, group *Group) error {
+	// The record of the group is deleted with the team.
+	return s.db.Teams().DeleteTeam(ctx, group.ID)
+}
+
+func (s *teamGroupStore) AddMember(ctx context.Context, id, userID int32) error {
+	return s.db.Teams().CreateTeamMember(ctx, &types.TeamMember{TeamID: id, UserID: userID})
+}
+
+func (s *teamGroupStore) RemoveMember(ctx context.Context, id, userID int32) error {
+	return s.db.Teams().DeleteTeamMember(ctx, &types.TeamMember{TeamID: id, UserID: userID})
+}
+
+// extractMemberIDs extracts the deduplicated and sorted user IDs of the members in the given attributes.
+func extractMemberIDs(attributes scim.ResourceAttributes) ([]int32, error) {
+	members, _ := attributes[AttrMembers].([]interface{})
+	seen := make(map[int32]struct{}, len(members))
+	ids := make([]int32, 0, len(members))
+	for _, memberRaw := range members {
+		member, ok := memberRaw.(map[string]interface{})
+		if !ok {
+			return nil, scimerrors.ScimErrorBadParams([]string{"invalid member"})
+		}
+		value, _ := member[AttrMemberValue].(string)
+		id, err := strconv.ParseInt(value, 10, 32)
+		if err != nil {
+			return nil, scimerrors.ScimErrorBadParams([]string{"invalid member value " + strconv.Quote(value)})
+		}
+		if _, ok := seen[int32(id)]; ok {
+			continue
+		}
+		seen[int32(id)] = struct{}{}
+		ids = append(ids, int32(id))
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids, nil
+}
+
+// diffMemberIDs returns the user IDs that are in after but not in before, and the
+// user IDs that are in before but not in after.
+func diffMemberIDs(before, after []int32) (toAdd, toRemove []int32) {
+	toSet := func(ids []int32) map[int32]struct{} {
+		m := make(map[int32]struct{}, len(ids))
+		for _, id := range ids {
+			m[id] = struct{}{}
+		}
+		return m
+	}
+	beforeSet, afterSet := toSet(before), toSet(after)
+	for _, id := range after {
+		if _, ok := beforeSet[id]; !ok {
+			toAdd = append(toAdd, id)
+		}
+	}
+	for _, id := range before {
+		if _, ok := afterSet[id]; !ok {
+			toRemove = append(toRemove, id)
+		}
+	}
+	return toAdd, toRemove
+}
+
+// getUniqueGroupName returns a normalized group name based on the given display name.
+// It returns a SCIM conflict error if a group with the name already exists.
+func getUniqueGroupName(ctx context.Context, store groupStore, displayName string) (string, error) {
+	name, err := auth.NormalizeUsername(displayName)
+	if err != nil {
+		return "", scimerrors.ScimErrorBadParams([]string{"invalid displayName"})
+	}
+	exists, err := store.NameExists(ctx, name)
+	if err != nil {
+		return "", scimerrors.ScimError{Status: http.StatusInternalServerError, Detail: errors.Wrap(err, "could not check if group exists").Error()}
+	}
+	if exists {
+		return "", scimerrors.ScimError{Status: http.StatusConflict, Detail: "Group already exists based on displayName"}
+	}
+	return name, nil
+}
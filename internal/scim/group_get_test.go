@@ -0,0 +1,114 @@
+package scim
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"testing"
+
+	"github.com/elimity-com/scim"
+	"github.com/scim2/filter-parser/v2"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbmocks"
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/lib/pointers"
+)
+
+// createMockGroupDB returns a mock database with three users and four organizations,
+// along with the members of each organization. The first three organizations were
+// provisioned as groups, the fourth was created manually.
+func createMockGroupDB() (map[int32][]int32, *dbmocks.MockDB) {
+	db := getMockDB([]*types.UserForSCIM{
+		{User: types.User{ID: 1, Username: "user1"}},
+		{User: types.User{ID: 2, Username: "user2"}},
+		{User: types.User{ID: 3, Username: "user3"}},
+	}, map[int32][]*database.UserEmail{})
+	orgMembers := map[int32][]int32{1: {1, 2}, 2: {3}}
+	addMockOrgs(db, []*types.Org{
+		{ID: 1, Name: "engineering", DisplayName: pointers.Ptr("Engineering")},
+		{ID: 2, Name: "sales", DisplayName: pointers.Ptr("Sales")},
+		{ID: 3, Name: "support"},
+		{ID: 4, Name: "manual"},
+	}, orgMembers)
+	addMockSCIMGroups(db,
+		&database.SCIMGroup{ID: 1, OrgID: 1, ExternalID: "ext-engineering"},
+		&database.SCIMGroup{ID: 2, OrgID: 2},
+		&database.SCIMGroup{ID: 3, OrgID: 3},
+	)
+	return orgMembers, db
+}
+
+func TestGroupResourceHandler_Get(t *testing.T) {
+	_, db := createMockGroupDB()
+	groupResourceHandler := NewGroupResourceHandler(context.Background(), &observation.TestContext, db)
+
+	group1, err := groupResourceHandler.Get(&http.Request{}, "1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	group3, err := groupResourceHandler.Get(&http.Request{}, "3")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, "1", group1.ID)
+	assert.Equal(t, "ext-engineering", group1.ExternalID.Value())
+	assert.Equal(t, "Engineering", group1.Attributes[AttrDisplayName])
+	assert.Equal(t, toInterfaceSlice(
+		map[string]interface{}{AttrMemberValue: "1"},
+		map[string]interface{}{AttrMemberValue: "2"},
+	), group1.Attributes[AttrMembers])
+	// Falls back to the name if there is no display name
+	assert.Equal(t, "support", group3.Attributes[AttrDisplayName])
+	assert.Empty(t, group3.Attributes[AttrMembers])
+
+	// Organizations that were not provisioned are not groups.
+	_, err = groupResourceHandler.Get(&http.Request{}, "4")
+	assert.Error(t, err)
+	_, err = groupResourceHandler.Get(&http.Request{}, "5")
+	assert.Error(t, err)
+}
+
+func TestGroupResourceHandler_GetAll(t *testing.T) {
+	_, db := createMockGroupDB()
+
+	cases := []struct {
+		name             string
+		count            int
+		startIndex       int
+		filter           string
+		wantTotalResults int
+		wantResults      int
+		wantFirstID      int
+	}{
+		{name: "no filter, count=2", count: 2, startIndex: 1, wantTotalResults: 3, wantResults: 2, wantFirstID: 1},
+		{name: "no filter, offset=2", count: 999, startIndex: 3, wantTotalResults: 3, wantResults: 1, wantFirstID: 3},
+		{name: "filter: displayName", count: 999, startIndex: 1, filter: "displayName eq \"Sales\"", wantTotalResults: 1, wantResults: 1, wantFirstID: 2},
+		{name: "filter: member", count: 999, startIndex: 1, filter: "members[value eq \"3\"]", wantTotalResults: 1, wantResults: 1, wantFirstID: 2},
+		{name: "filter: no match", count: 999, startIndex: 1, filter: "displayName eq \"Marketing\"", wantTotalResults: 0, wantResults: 0},
+	}
+
+	groupResourceHandler := NewGroupResourceHandler(context.Background(), &observation.TestContext, db)
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			params := scim.ListRequestParams{Count: c.count, StartIndex: c.startIndex}
+			if c.filter != "" {
+				filterExpr, err := filter.ParseFilter([]byte(c.filter))
+				if err != nil {
+					t.Fatal(err)
+				}
+				params.Filter = filterExpr
+			}
+			page, err := groupResourceHandler.GetAll(&http.Request{}, params)
+			assert.NoError(t, err)
+			assert.Equal(t, c.wantTotalResults, page.TotalResults)
+			assert.Equal(t, c.wantResults, len(page.Resources))
+			if c.wantResults > 0 {
+				assert.Equal(t, strconv.Itoa(c.wantFirstID), page.Resources[0].ID)
+			}
+		})
+	}
+}
@@ -0,0 +1,232 @@
+package scim
+
+import (
+	"context"
+	"testing"
+
+	"github.com/elimity-com/scim"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbmocks"
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+func TestGroupResourceHandler_Create(t *testing.T) {
+	orgMembers, db := createMockGroupDB()
+	groupResourceHandler := NewGroupResourceHandler(context.Background(), &observation.TestContext, db)
+
+	group, err := groupResourceHandler.Create(createDummyRequest(), scim.ResourceAttributes{
+		AttrExternalId:  "ext-1",
+		AttrDisplayName: "Product Design",
+		AttrMembers:     toInterfaceSlice(map[string]interface{}{"value": "2"}, map[string]interface{}{"value": "3"}, map[string]interface{}{"value": "2"}),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "5", group.ID)
+	assert.Equal(t, "ext-1", group.ExternalID.Value())
+	assert.Equal(t, "Product Design", group.Attributes[AttrDisplayName])
+	assert.Equal(t, []int32{2, 3}, orgMembers[5])
+	org, err := db.Orgs().GetByID(context.Background(), 5)
+	require.NoError(t, err)
+	assert.Equal(t, "Product-Design", org.Name)
+	scimGroup, err := db.SCIMGroups().GetByOrgID(context.Background(), 5)
+	require.NoError(t, err)
+	assert.Equal(t, "ext-1", scimGroup.ExternalID)
+
+	// The created group is listed.
+	fetched, err := groupResourceHandler.Get(createDummyRequest(), "5")
+	require.NoError(t, err)
+	assert.Equal(t, "ext-1", fetched.ExternalID.Value())
+
+	t.Run("existing display name", func(t *testing.T) {
+		_, err := groupResourceHandler.Create(createDummyRequest(), scim.ResourceAttributes{AttrDisplayName: "engineering"})
+		assert.Error(t, err)
+	})
+	t.Run("existing display name of organization that is not a group", func(t *testing.T) {
+		_, err := groupResourceHandler.Create(createDummyRequest(), scim.ResourceAttributes{AttrDisplayName: "manual"})
+		assert.Error(t, err)
+	})
+	t.Run("unknown member", func(t *testing.T) {
+		_, err := groupResourceHandler.Create(createDummyRequest(), scim.ResourceAttributes{
+			AttrDisplayName: "Marketing",
+			AttrMembers:     toInterfaceSlice(map[string]interface{}{"value": "42"}),
+		})
+		assert.Error(t, err)
+	})
+	t.Run("missing display name", func(t *testing.T) {
+		_, err := groupResourceHandler.Create(createDummyRequest(), scim.ResourceAttributes{AttrExternalId: "ext-2"})
+		assert.Error(t, err)
+	})
+}
+
+func TestGroupResourceHandler_Patch(t *testing.T) {
+	testCases := []struct {
+		name           string
+		operations     []scim.PatchOperation
+		wantName       string
+		wantMembers    []int32
+		wantExternalID string
+	}{
+		{
+			name:        "replace display name",
+			operations:  []scim.PatchOperation{{Op: "replace", Path: createPath(AttrDisplayName, nil), Value: "Platform"}},
+			wantName:    "Platform",
+			wantMembers: []int32{1, 2},
+		},
+		{
+			name:        "replace display name without path",
+			operations:  []scim.PatchOperation{{Op: "replace", Value: map[string]interface{}{AttrDisplayName: "Platform"}}},
+			wantName:    "Platform",
+			wantMembers: []int32{1, 2},
+		},
+		{
+			name:        "add members",
+			operations:  []scim.PatchOperation{{Op: "add", Path: createPath(AttrMembers, nil), Value: toInterfaceSlice(map[string]interface{}{"value": "2"}, map[string]interface{}{"value": "3"})}},
+			wantName:    "Engineering",
+			wantMembers: []int32{1, 2, 3},
+		},
+		{
+			name:        "remove member with filter",
+			operations:  []scim.PatchOperation{{Op: "remove", Path: parseStringPath("members[value eq \"1\"]")}},
+			wantName:    "Engineering",
+			wantMembers: []int32{2},
+		},
+		{
+			name:        "remove member with value",
+			operations:  []scim.PatchOperation{{Op: "remove", Path: createPath(AttrMembers, nil), Value: toInterfaceSlice(map[string]interface{}{"value": "2"})}},
+			wantName:    "Engineering",
+			wantMembers: []int32{1},
+		},
+		{
+			name:        "remove all members",
+			operations:  []scim.PatchOperation{{Op: "remove", Path: createPath(AttrMembers, nil)}},
+			wantName:    "Engineering",
+			wantMembers: []int32{},
+		},
+		{
+			name:           "replace external ID",
+			operations:     []scim.PatchOperation{{Op: "replace", Path: createPath(AttrExternalId, nil), Value: "ext-platform"}},
+			wantName:       "Engineering",
+			wantMembers:    []int32{1, 2},
+			wantExternalID: "ext-platform",
+		},
+		{
+			name:        "replace members",
+			operations:  []scim.PatchOperation{{Op: "replace", Path: createPath(AttrMembers, nil), Value: toInterfaceSlice(map[string]interface{}{"value": "3"})}},
+			wantName:    "Engineering",
+			wantMembers: []int32{3},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			orgMembers, db := createMockGroupDB()
+			groupResourceHandler := NewGroupResourceHandler(context.Background(), &observation.TestContext, db)
+
+			group, err := groupResourceHandler.Patch(createDummyRequest(), "1", tc.operations)
+			require.NoError(t, err)
+			assert.Equal(t, tc.wantName, group.Attributes[AttrDisplayName])
+			assert.ElementsMatch(t, tc.wantMembers, orgMembers[1])
+			assert.Len(t, group.Attributes[AttrMembers], len(tc.wantMembers))
+			wantExternalID := tc.wantExternalID
+			if wantExternalID == "" {
+				wantExternalID = "ext-engineering"
+			}
+			assert.Equal(t, wantExternalID, group.ExternalID.Value())
+		})
+	}
+}
+
+func TestGroupResourceHandler_Replace(t *testing.T) {
+	orgMembers, db := createMockGroupDB()
+	groupResourceHandler := NewGroupResourceHandler(context.Background(), &observation.TestContext, db)
+
+	group, err := groupResourceHandler.Replace(createDummyRequest(), "2", scim.ResourceAttributes{
+		AttrDisplayName: "Sales EMEA",
+		AttrMembers:     toInterfaceSlice(map[string]interface{}{"value": "1"}),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "Sales EMEA", group.Attributes[AttrDisplayName])
+	assert.Equal(t, []int32{1}, orgMembers[2])
+}
+
+func TestGroupResourceHandler_Delete(t *testing.T) {
+	orgMembers, db := createMockGroupDB()
+	groupResourceHandler := NewGroupResourceHandler(context.Background(), &observation.TestContext, db)
+
+	require.NoError(t, groupResourceHandler.Delete(createDummyRequest(), "1"))
+	_, err := groupResourceHandler.Get(createDummyRequest(), "1")
+	assert.Error(t, err)
+	assert.NotContains(t, orgMembers, int32(1))
+	_, err = db.SCIMGroups().GetByOrgID(context.Background(), 1)
+	assert.Error(t, err)
+
+	// Organizations that were not provisioned cannot be deleted.
+	assert.Error(t, groupResourceHandler.Delete(createDummyRequest(), "4"))
+	_, err = db.Orgs().GetByID(context.Background(), 4)
+	assert.NoError(t, err)
+}
+
+func TestGroupResourceHandler_PatchNotProvisioned(t *testing.T) {
+	orgMembers, db := createMockGroupDB()
+	groupResourceHandler := NewGroupResourceHandler(context.Background(), &observation.TestContext, db)
+
+	_, err := groupResourceHandler.Patch(createDummyRequest(), "4", []scim.PatchOperation{
+		{Op: "add", Path: createPath(AttrMembers, nil), Value: toInterfaceSlice(map[string]interface{}{"value": "1"})},
+	})
+	assert.Error(t, err)
+	assert.Empty(t, orgMembers[4])
+}
+
+func TestGroupResourceHandler_TeamMapping(t *testing.T) {
+	conf.Mock(&conf.Unified{SiteConfiguration: schema.SiteConfiguration{ScimGroupMapping: string(GroupMappingTeam)}})
+	t.Cleanup(func() { conf.Mock(nil) })
+
+	db := getMockDB([]*types.UserForSCIM{
+		{User: types.User{ID: 1, Username: "user1"}},
+		{User: types.User{ID: 2, Username: "user2"}},
+	}, map[int32][]*database.UserEmail{})
+	team := &types.Team{ID: 7, Name: "owners", DisplayName: "Owners"}
+	teamMembers := []*types.TeamMember{{TeamID: 7, UserID: 1}}
+	teamStore := dbmocks.NewMockTeamStore()
+	teamStore.GetTeamByIDFunc.SetDefaultReturn(team, nil)
+	teamStore.GetTeamByNameFunc.SetDefaultReturn(nil, database.TeamNotFoundError{})
+	teamStore.CreateTeamFunc.SetDefaultHook(func(ctx context.Context, t *types.Team) (*types.Team, error) {
+		t.ID = 8
+		return t, nil
+	})
+	teamStore.ListTeamMembersFunc.SetDefaultHook(func(ctx context.Context, opts database.ListTeamMembersOpts) ([]*types.TeamMember, *database.TeamMemberListCursor, error) {
+		var members []*types.TeamMember
+		for _, m := range teamMembers {
+			if m.TeamID == opts.TeamID {
+				members = append(members, m)
+			}
+		}
+		return members, nil, nil
+	})
+	teamStore.CreateTeamMemberFunc.SetDefaultHook(func(ctx context.Context, members ...*types.TeamMember) error {
+		teamMembers = append(teamMembers, members...)
+		return nil
+	})
+	db.TeamsFunc.SetDefaultReturn(teamStore)
+	addMockSCIMGroups(db, &database.SCIMGroup{ID: 1, TeamID: 7})
+	groupResourceHandler := NewGroupResourceHandler(context.Background(), &observation.TestContext, db)
+
+	group, err := groupResourceHandler.Patch(createDummyRequest(), "7", []scim.PatchOperation{
+		{Op: "add", Path: createPath(AttrMembers, nil), Value: toInterfaceSlice(map[string]interface{}{"value": "2"})},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "Owners", group.Attributes[AttrDisplayName])
+	assert.Equal(t, []*types.TeamMember{{TeamID: 7, UserID: 1}, {TeamID: 7, UserID: 2}}, teamMembers)
+
+	created, err := groupResourceHandler.Create(createDummyRequest(), scim.ResourceAttributes{AttrDisplayName: "Code Owners"})
+	require.NoError(t, err)
+	assert.Equal(t, "8", created.ID)
+	createdTeam := teamStore.CreateTeamFunc.History()[0].Arg1
+	assert.Equal(t, "Code-Owners", createdTeam.Name)
+	assert.True(t, createdTeam.ReadOnly)
+}
@@ -0,0 +1,230 @@
+package scim
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/elimity-com/scim"
+	scimerrors "github.com/elimity-com/scim/errors"
+
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/errcode"
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// NewGroupResourceHandler returns a new ResourceHandler for groups.
+func NewGroupResourceHandler(ctx context.Context, observationCtx *observation.Context, db database.DB) *ResourceHandler {
+	groupSCIMService := &GroupSCIMService{
+		db: db,
+	}
+	return &ResourceHandler{
+		ctx:              ctx,
+		observationCtx:   observationCtx,
+		coreSchema:       groupSCIMService.Schema(),
+		schemaExtensions: groupSCIMService.SchemaExtensions(),
+		service:          groupSCIMService,
+	}
+}
+
+// GroupSCIMService provisions SCIM groups as Sourcegraph organizations or teams,
+// depending on the "scim.groupMapping" site configuration.
+type GroupSCIMService struct {
+	db database.DB
+}
+
+func (g *GroupSCIMService) Get(ctx context.Context, id string) (scim.Resource, error) {
+	group, err := getGroupFromDB(ctx, newGroupStore(g.db), id)
+	if err != nil {
+		return scim.Resource{}, err
+	}
+	return group.ToResource(), nil
+}
+
+func (g *GroupSCIMService) GetAll(ctx context.Context, start int, count *int) (totalCount int, entities []scim.Resource, err error) {
+	store := newGroupStore(g.db)
+
+	// Calculate offset
+	var offset int
+	if start > 0 {
+		offset = start - 1
+	}
+
+	var limitOffset *database.LimitOffset
+	if count != nil {
+		limitOffset = &database.LimitOffset{Limit: *count, Offset: offset}
+	}
+	groups, err := store.List(ctx, limitOffset)
+	if err != nil {
+		return 0, nil, err
+	}
+	entities = make([]scim.Resource, 0, len(groups))
+	for _, group := range groups {
+		entities = append(entities, group.ToResource())
+	}
+
+	// Get total count
+	if count == nil {
+		return len(groups), entities, nil
+	}
+	totalCount, err = store.Count(ctx)
+	return totalCount, entities, err
+}
+
+func (g *GroupSCIMService) Update(ctx context.Context, id string, applySCIMUpdates func(getResource func() scim.Resource) (updated scim.Resource, _ error)) (finalResource scim.Resource, _ error) {
+	var groupAfterUpdate *Group
+	err := g.db.WithTransact(ctx, func(tx database.DB) error {
+		store := newGroupStore(tx)
+		group, err := getGroupFromDB(ctx, store, id)
+		if err != nil {
+			return err
+		}
+
+		// Capture a copy of the resource before applying updates so it can be compared to determine which
+		// database updates are necessary
+		resourceBeforeUpdate := group.ToResource()
+		resourceAfterUpdate, err := applySCIMUpdates(group.ToResource)
+		if err != nil {
+			return err
+		}
+
+		// Update display name
+		displayName := extractStringAttribute(resourceAfterUpdate.Attributes, AttrDisplayName)
+		if displayName == "" {
+			return scimerrors.ScimErrorBadParams([]string{"displayName missing"})
+		}
+		if displayName != resourceBeforeUpdate.Attributes[AttrDisplayName] {
+			if err := store.UpdateDisplayName(ctx, group.ID, displayName); err != nil {
+				return scimerrors.ScimError{Status: http.StatusInternalServerError, Detail: errors.Wrap(err, "could not update displayName").Error()}
+			}
+		}
+
+		// Update external ID
+		if externalID := getOptionalExternalID(resourceAfterUpdate.Attributes); externalID.Present() && externalID.Value() != group.ExternalID {
+			if err := store.UpdateExternalID(ctx, group, externalID.Value()); err != nil {
+				return scimerrors.ScimError{Status: http.StatusInternalServerError, Detail: errors.Wrap(err, "could not update externalId").Error()}
+			}
+		}
+
+		// Update members
+		memberIDs, err := extractMemberIDs(resourceAfterUpdate.Attributes)
+		if err != nil {
+			return err
+		}
+		if err := updateGroupMembers(ctx, tx, store, group, memberIDs); err != nil {
+			return err
+		}
+
+		groupAfterUpdate, err = store.Get(ctx, group.ID)
+		if err != nil {
+			return scimerrors.ScimError{Status: http.StatusInternalServerError, Detail: err.Error()}
+		}
+		return nil
+	})
+	if err != nil {
+		multiErr, ok := err.(errors.MultiError)
+		if !ok || len(multiErr.Errors()) == 0 {
+			return scim.Resource{}, err
+		}
+		return scim.Resource{}, multiErr.Errors()[len(multiErr.Errors())-1]
+	}
+	return groupAfterUpdate.ToResource(), nil
+}
+
+func (g *GroupSCIMService) Create(ctx context.Context, attributes scim.ResourceAttributes) (scim.Resource, error) {
+	displayName := extractStringAttribute(attributes, AttrDisplayName)
+	if displayName == "" {
+		return scim.Resource{}, scimerrors.ScimErrorBadParams([]string{"displayName missing"})
+	}
+	memberIDs, err := extractMemberIDs(attributes)
+	if err != nil {
+		return scim.Resource{}, err
+	}
+
+	var group *Group
+	err = g.db.WithTransact(ctx, func(tx database.DB) error {
+		store := newGroupStore(tx)
+		name, err := getUniqueGroupName(ctx, store, displayName)
+		if err != nil {
+			return err
+		}
+
+		group, err = store.Create(ctx, name, displayName, getOptionalExternalID(attributes).Value())
+		if err != nil {
+			if errors.Is(err, database.ErrTeamNameAlreadyExists) {
+				return scimerrors.ScimError{Status: http.StatusConflict, Detail: err.Error()}
+			}
+			return scimerrors.ScimError{Status: http.StatusInternalServerError, Detail: err.Error()}
+		}
+
+		if err := updateGroupMembers(ctx, tx, store, group, memberIDs); err != nil {
+			return err
+		}
+		group.MemberIDs = memberIDs
+		return nil
+	})
+	if err != nil {
+		multiErr, ok := err.(errors.MultiError)
+		if !ok || len(multiErr.Errors()) == 0 {
+			return scim.Resource{}, err
+		}
+		return scim.Resource{}, multiErr.Errors()[len(multiErr.Errors())-1]
+	}
+
+	return group.ToResource(), nil
+}
+
+func (g *GroupSCIMService) Delete(ctx context.Context, id string) error {
+	return g.db.WithTransact(ctx, func(tx database.DB) error {
+		store := newGroupStore(tx)
+		group, err := getGroupFromDB(ctx, store, id)
+		if err != nil {
+			return err
+		}
+		return store.Delete(ctx, group)
+	})
+}
+
+// Helper functions used for Groups
+
+// getGroupFromDB returns the group with the given ID.
+// When it fails, it returns an error that's safe to return to the client as a SCIM error.
+func getGroupFromDB(ctx context.Context, store groupStore, idStr string) (*Group, error) {
+	id, err := strconv.ParseInt(idStr, 10, 32)
+	if err != nil {
+		return nil, scimerrors.ScimErrorResourceNotFound(idStr)
+	}
+
+	group, err := store.Get(ctx, int32(id))
+	if err != nil {
+		if errcode.IsNotFound(err) {
+			return nil, scimerrors.ScimErrorResourceNotFound(idStr)
+		}
+		return nil, scimerrors.ScimError{Status: http.StatusInternalServerError, Detail: err.Error()}
+	}
+	return group, nil
+}
+
+// updateGroupMembers adds and removes members of the given group so that its members
+// match the given user IDs. Users that are added must exist.
+func updateGroupMembers(ctx context.Context, tx database.DB, store groupStore, group *Group, memberIDs []int32) error {
+	toAdd, toRemove := diffMemberIDs(group.MemberIDs, memberIDs)
+	for _, userID := range toAdd {
+		if _, err := tx.Users().GetByID(ctx, userID); err != nil {
+			if database.IsUserNotFoundErr(err) {
+				return scimerrors.ScimErrorBadParams([]string{"member " + strconv.Itoa(int(userID)) + " not found"})
+			}
+			return scimerrors.ScimError{Status: http.StatusInternalServerError, Detail: err.Error()}
+		}
+		if err := store.AddMember(ctx, group.ID, userID); err != nil {
+			return scimerrors.ScimError{Status: http.StatusInternalServerError, Detail: errors.Wrap(err, "could not add member").Error()}
+		}
+	}
+	for _, userID := range toRemove {
+		if err := store.RemoveMember(ctx, group.ID, userID); err != nil {
+			return scimerrors.ScimError{Status: http.StatusInternalServerError, Detail: errors.Wrap(err, "could not remove member").Error()}
+		}
+	}
+	return nil
+}
@@ -52,9 +52,11 @@ func NewHandler(ctx context.Context, db database.DB, observationCtx *observation
 	}
 
 	userResourceHandler := NewUserResourceHandler(ctx, observationCtx, db)
+	groupResourceHandler := NewGroupResourceHandler(ctx, observationCtx, db)
 
 	resourceTypes := []scim.ResourceType{
 		createResourceType("User", "/Users", "User Account", userResourceHandler),
+		createResourceType("Group", "/Groups", "Group", groupResourceHandler),
 	}
 
 	server := scim.Server{
@@ -236,3 +236,169 @@ func applyLimitOffset(users []*types.UserForSCIM, limitOffset *database.LimitOff
 	}
 	return users[start:end], nil
 }
+
+// addMockOrgs adds mock org stores containing the given organizations and org memberships to the given database.
+// Note: IDs of organizations must be ascending.
+func addMockOrgs(db *dbmocks.MockDB, orgs []*types.Org, orgMembers map[int32][]int32) {
+	orgStore := dbmocks.NewMockOrgStore()
+	orgStore.GetByIDFunc.SetDefaultHook(func(ctx context.Context, id int32) (*types.Org, error) {
+		for _, org := range orgs {
+			if org.ID == id {
+				return org, nil
+			}
+		}
+		return nil, &database.OrgNotFoundError{Message: "not found"}
+	})
+	orgStore.GetByNameFunc.SetDefaultHook(func(ctx context.Context, name string) (*types.Org, error) {
+		for _, org := range orgs {
+			if org.Name == name {
+				return org, nil
+			}
+		}
+		return nil, &database.OrgNotFoundError{Message: "not found"}
+	})
+	orgStore.ListFunc.SetDefaultHook(func(ctx context.Context, opt *database.OrgsListOptions) ([]*types.Org, error) {
+		if opt == nil || opt.LimitOffset == nil {
+			return orgs, nil
+		}
+		start := opt.Offset
+		if start > len(orgs) {
+			start = len(orgs)
+		}
+		end := start + opt.Limit
+		if end > len(orgs) {
+			end = len(orgs)
+		}
+		return orgs[start:end], nil
+	})
+	orgStore.CountFunc.SetDefaultHook(func(ctx context.Context, opt database.OrgsListOptions) (int, error) {
+		return len(orgs), nil
+	})
+	orgStore.CreateFunc.SetDefaultHook(func(ctx context.Context, name string, displayName *string) (*types.Org, error) {
+		nextID := int32(1)
+		if len(orgs) > 0 {
+			nextID = orgs[len(orgs)-1].ID + 1
+		}
+		org := &types.Org{ID: nextID, Name: name, DisplayName: displayName}
+		orgs = append(orgs, org)
+		return org, nil
+	})
+	orgStore.UpdateFunc.SetDefaultHook(func(ctx context.Context, id int32, displayName *string) (*types.Org, error) {
+		for _, org := range orgs {
+			if org.ID == id {
+				org.DisplayName = displayName
+				return org, nil
+			}
+		}
+		return nil, &database.OrgNotFoundError{Message: "not found"}
+	})
+	orgStore.DeleteFunc.SetDefaultHook(func(ctx context.Context, id int32) error {
+		for i, org := range orgs {
+			if org.ID == id {
+				orgs = append(orgs[:i], orgs[i+1:]...)
+				delete(orgMembers, id)
+				return nil
+			}
+		}
+		return &database.OrgNotFoundError{Message: "not found"}
+	})
+
+	orgMemberStore := dbmocks.NewMockOrgMemberStore()
+	orgMemberStore.GetByOrgIDFunc.SetDefaultHook(func(ctx context.Context, orgID int32) ([]*types.OrgMembership, error) {
+		memberships := make([]*types.OrgMembership, 0, len(orgMembers[orgID]))
+		for _, userID := range orgMembers[orgID] {
+			memberships = append(memberships, &types.OrgMembership{OrgID: orgID, UserID: userID})
+		}
+		return memberships, nil
+	})
+	orgMemberStore.CreateFunc.SetDefaultHook(func(ctx context.Context, orgID, userID int32) (*types.OrgMembership, error) {
+		orgMembers[orgID] = append(orgMembers[orgID], userID)
+		return &types.OrgMembership{OrgID: orgID, UserID: userID}, nil
+	})
+	orgMemberStore.RemoveFunc.SetDefaultHook(func(ctx context.Context, orgID, userID int32) error {
+		for i, id := range orgMembers[orgID] {
+			if id == userID {
+				orgMembers[orgID] = append(orgMembers[orgID][:i], orgMembers[orgID][i+1:]...)
+				return nil
+			}
+		}
+		return errors.New("org membership not found")
+	})
+
+	db.OrgsFunc.SetDefaultReturn(orgStore)
+	db.OrgMembersFunc.SetDefaultReturn(orgMemberStore)
+}
+
+// addMockSCIMGroups adds a mock SCIM group store containing the given provisioned organizations and teams
+// to the given database.
+func addMockSCIMGroups(db *dbmocks.MockDB, groups ...*database.SCIMGroup) {
+	nextID := int32(len(groups) + 1)
+	get := func(matches func(*database.SCIMGroup) bool) (*database.SCIMGroup, error) {
+		for _, group := range groups {
+			if matches(group) {
+				return group, nil
+			}
+		}
+		return nil, database.SCIMGroupNotFoundErr{}
+	}
+
+	scimGroupStore := dbmocks.NewMockSCIMGroupStore()
+	scimGroupStore.GetByOrgIDFunc.SetDefaultHook(func(ctx context.Context, orgID int32) (*database.SCIMGroup, error) {
+		return get(func(group *database.SCIMGroup) bool { return group.OrgID == orgID })
+	})
+	scimGroupStore.GetByTeamIDFunc.SetDefaultHook(func(ctx context.Context, teamID int32) (*database.SCIMGroup, error) {
+		return get(func(group *database.SCIMGroup) bool { return group.TeamID == teamID })
+	})
+	list := func(opts database.SCIMGroupsListOptions) []*database.SCIMGroup {
+		var matching []*database.SCIMGroup
+		for _, group := range groups {
+			if (group.TeamID != 0) == opts.Teams {
+				matching = append(matching, group)
+			}
+		}
+		return matching
+	}
+	scimGroupStore.ListFunc.SetDefaultHook(func(ctx context.Context, opts database.SCIMGroupsListOptions) ([]*database.SCIMGroup, error) {
+		matching := list(opts)
+		if opts.LimitOffset == nil {
+			return matching, nil
+		}
+		start := opts.Offset
+		if start > len(matching) {
+			start = len(matching)
+		}
+		end := start + opts.Limit
+		if end > len(matching) {
+			end = len(matching)
+		}
+		return matching[start:end], nil
+	})
+	scimGroupStore.CountFunc.SetDefaultHook(func(ctx context.Context, opts database.SCIMGroupsListOptions) (int, error) {
+		return len(list(opts)), nil
+	})
+	scimGroupStore.CreateFunc.SetDefaultHook(func(ctx context.Context, group *database.SCIMGroup) error {
+		group.ID = nextID
+		nextID++
+		groups = append(groups, group)
+		return nil
+	})
+	scimGroupStore.UpdateExternalIDFunc.SetDefaultHook(func(ctx context.Context, id int32, externalID string) error {
+		group, err := get(func(group *database.SCIMGroup) bool { return group.ID == id })
+		if err != nil {
+			return err
+		}
+		group.ExternalID = externalID
+		return nil
+	})
+	scimGroupStore.DeleteFunc.SetDefaultHook(func(ctx context.Context, id int32) error {
+		for i, group := range groups {
+			if group.ID == id {
+				groups = append(groups[:i], groups[i+1:]...)
+				return nil
+			}
+		}
+		return nil
+	})
+
+	db.SCIMGroupsFunc.SetDefaultReturn(scimGroupStore)
+}
@@ -20,7 +20,7 @@ func (h *ResourceHandler) Delete(r *http.Request, id string) error {
 
 	err = h.service.Delete(r.Context(), entity.ID)
 	if err != nil {
-		return errors.Wrap(err, "delete user")
+		return errors.Wrap(err, "delete resource")
 	}
 	return nil
 }
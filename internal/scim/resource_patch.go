@@ -89,7 +89,14 @@ func (h *ResourceHandler) applyOperation(op scim.PatchOperation, resource *scim.
 
 		switch v := currentValue.(type) {
 		case []interface{}: // this value has multiple items
-			if valueExpr == nil { // this applies to whole attribute remove it
+			if valueExpr == nil {
+				// Some IdPs (for example, Azure AD when removing group members) list the members to remove
+				// in the value instead of using a filter → only remove those members
+				if itemsToRemove, ok := op.Value.([]interface{}); ok && len(itemsToRemove) > 0 && attrName == AttrMembers {
+					applyAttributeChange(resource.Attributes, attrName, removeMatchingItems(v, itemsToRemove), "replace")
+					return
+				}
+				// this applies to whole attribute remove it
 				applyAttributeChange(resource.Attributes, attrName, nil, op.Op)
 				return
 			}
@@ -204,6 +211,30 @@ func ensureSinglePrimaryItem(changedItems []interface{}, attributes scim.Resourc
 	}
 }
 
+// removeMatchingItems returns the items that don't have the same "value" as any of the items to remove.
+func removeMatchingItems(items []interface{}, itemsToRemove []interface{}) []interface{} {
+	valuesToRemove := make(map[string]struct{}, len(itemsToRemove))
+	for _, item := range itemsToRemove {
+		if mapItem, ok := item.(map[string]interface{}); ok {
+			if value, ok := mapItem["value"].(string); ok {
+				valuesToRemove[value] = struct{}{}
+			}
+		}
+	}
+	remainingItems := []interface{}{}
+	for _, item := range items {
+		if mapItem, ok := item.(map[string]interface{}); ok {
+			if value, ok := mapItem["value"].(string); ok {
+				if _, remove := valuesToRemove[value]; remove {
+					continue
+				}
+			}
+		}
+		remainingItems = append(remainingItems, item)
+	}
+	return remainingItems
+}
+
 // applyChangeToAttributes applies a change to a resource (for example, sets its userName).
 func applyChangeToAttributes(attributes scim.ResourceAttributes, rawPath string, value interface{}) {
 	// Ignore nil values
@@ -150,6 +150,26 @@ func Test_UserResourceHandler_PatchRemoveWithFilter(t *testing.T) {
 	assert.True(t, containsEmail(dbEmails, "primary@work.com", true, true))
 }
 
+func Test_UserResourceHandler_PatchRemoveArrayFieldWithValue(t *testing.T) {
+	userResourceHandler := NewUserResourceHandler(context.Background(), &observation.TestContext, createMockDB())
+	resource := scim.Resource{Attributes: scim.ResourceAttributes{
+		AttrEmails: toInterfaceSlice(
+			map[string]interface{}{"value": "primary@work.com", "primary": true},
+			map[string]interface{}{"value": "secondary@work.com", "primary": false},
+		),
+	}}
+
+	// Only group members are removed by value; for users, the value of a remove
+	// operation without a filter is ignored and the whole attribute is removed.
+	err := userResourceHandler.applyOperation(scim.PatchOperation{
+		Op:    "remove",
+		Path:  createPath(AttrEmails, nil),
+		Value: toInterfaceSlice(map[string]interface{}{"value": "secondary@work.com"}),
+	}, &resource)
+	assert.NoError(t, err)
+	assert.NotContains(t, resource.Attributes, AttrEmails)
+}
+
 func Test_UserResourceHandler_PatchReplaceWholeArrayField(t *testing.T) {
 	db := createMockDB()
 	userResourceHandler := NewUserResourceHandler(context.Background(), &observation.TestContext, db)
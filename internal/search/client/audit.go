@@ -0,0 +1,114 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/audit"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/goroutine"
+	"github.com/sourcegraph/sourcegraph/internal/search"
+	"github.com/sourcegraph/sourcegraph/internal/search/query"
+)
+
+// searchQueryEventArgument is the argument of the security event recorded for
+// an executed search query.
+type searchQueryEventArgument struct {
+	Query       string   `json:"query"`
+	PatternType string   `json:"patternType"`
+	Protocol    string   `json:"protocol"`
+	RepoFilters []string `json:"repoFilters,omitempty"`
+	FileFilters []string `json:"fileFilters,omitempty"`
+}
+
+// logSearchQueryEvent records a security event for the search query about to
+// be executed if log.auditLog.searchQueries is enabled. The query and filters
+// are redacted according to log.auditLog.redactionPatterns. The event is
+// written in the background, so that searches never wait for the database.
+func logSearchQueryEvent(ctx context.Context, db database.DB, inputs *search.Inputs) {
+	cfg := conf.SiteConfig()
+	if !audit.IsEnabled(cfg, audit.SearchQueries) || !audit.SampleDataAccess(cfg) {
+		return
+	}
+
+	filterValues := func(field string) []string {
+		values, negated := inputs.Query.StringValues(field)
+		var filters []string
+		for _, v := range values {
+			filters = append(filters, audit.RedactDataAccess(cfg, v))
+		}
+		for _, v := range negated {
+			filters = append(filters, "-"+audit.RedactDataAccess(cfg, v))
+		}
+		return filters
+	}
+
+	argument, err := json.Marshal(searchQueryEventArgument{
+		Query:       audit.RedactDataAccess(cfg, inputs.OriginalQuery),
+		PatternType: inputs.PatternType.String(),
+		Protocol:    inputs.Protocol.String(),
+		RepoFilters: filterValues(query.FieldRepo),
+		FileFilters: filterValues(query.FieldFile),
+	})
+	if err != nil {
+		return
+	}
+
+	a := actor.FromContext(ctx)
+	enqueueSearchQueryEvent(queuedSearchQueryEvent{
+		// The event outlives the search, so it is written with a context that
+		// is not canceled with the request but still carries the actor.
+		ctx: actor.WithActor(context.Background(), a),
+		db:  db,
+		event: &database.SecurityEvent{
+			Name:            database.SecurityEventNameSearchQueryExecuted,
+			UserID:          uint32(a.UID),
+			AnonymousUserID: a.AnonymousUID,
+			Argument:        argument,
+			Source:          "BACKEND",
+			Timestamp:       time.Now(),
+		},
+	})
+}
+
+// searchQueryEventBufferSize is the number of search query events buffered in
+// memory. Events are written synchronously while the buffer is full, so that
+// no event is dropped.
+const searchQueryEventBufferSize = 1000
+
+var (
+	searchQueryEvents               = make(chan queuedSearchQueryEvent, searchQueryEventBufferSize)
+	startSearchQueryEventWriterOnce sync.Once
+)
+
+type queuedSearchQueryEvent struct {
+	ctx   context.Context
+	db    database.DB
+	event *database.SecurityEvent
+}
+
+func (e queuedSearchQueryEvent) write() {
+	e.db.SecurityEventLogs().LogEvent(e.ctx, e.event)
+}
+
+// enqueueSearchQueryEvent buffers the event for the background writer, or
+// writes it immediately if the buffer is full.
+func enqueueSearchQueryEvent(e queuedSearchQueryEvent) {
+	startSearchQueryEventWriterOnce.Do(func() {
+		goroutine.Go(func() {
+			for e := range searchQueryEvents {
+				e.write()
+			}
+		})
+	})
+
+	select {
+	case searchQueryEvents <- e:
+	default:
+		e.write()
+	}
+}
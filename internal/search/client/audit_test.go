@@ -0,0 +1,89 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbmocks"
+	"github.com/sourcegraph/sourcegraph/internal/search"
+	"github.com/sourcegraph/sourcegraph/internal/search/query"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+func TestLogSearchQueryEvent(t *testing.T) {
+	securityEventLogs := dbmocks.NewMockSecurityEventLogsStore()
+	db := dbmocks.NewMockDB()
+	db.SecurityEventLogsFunc.SetDefaultReturn(securityEventLogs)
+
+	searchQuery := "repo:^github.com/sourcegraph/secret-project$ -file:secret-notes.md token"
+	plan, err := query.Pipeline(query.Init(searchQuery, query.SearchTypeStandard))
+	require.NoError(t, err)
+	inputs := &search.Inputs{
+		Plan:          plan,
+		Query:         plan.ToQ(),
+		OriginalQuery: searchQuery,
+		PatternType:   query.SearchTypeStandard,
+		Protocol:      search.Streaming,
+	}
+	ctx := actor.WithActor(context.Background(), &actor.Actor{UID: 42})
+
+	mockAuditLog := func(auditLog *schema.AuditLog) {
+		conf.Mock(&conf.Unified{SiteConfiguration: schema.SiteConfiguration{Log: &schema.Log{AuditLog: auditLog}}})
+	}
+	t.Cleanup(func() { conf.Mock(nil) })
+
+	t.Run("disabled", func(t *testing.T) {
+		mockAuditLog(&schema.AuditLog{FileViews: true})
+		logSearchQueryEvent(ctx, db, inputs)
+		require.Empty(t, securityEventLogs.LogEventFunc.History())
+	})
+
+	t.Run("enabled", func(t *testing.T) {
+		mockAuditLog(&schema.AuditLog{SearchQueries: true, RedactionPatterns: []string{`secret-\w+`}})
+		logSearchQueryEvent(ctx, db, inputs)
+
+		require.Eventually(t, func() bool {
+			return len(securityEventLogs.LogEventFunc.History()) == 1
+		}, 5*time.Second, 10*time.Millisecond)
+		call := securityEventLogs.LogEventFunc.History()[0]
+		require.Equal(t, int32(42), actor.FromContext(call.Arg0).UID)
+		event := call.Arg1
+		require.Equal(t, database.SecurityEventNameSearchQueryExecuted, event.Name)
+		require.Equal(t, uint32(42), event.UserID)
+		require.Equal(t, "BACKEND", event.Source)
+
+		var argument searchQueryEventArgument
+		require.NoError(t, json.Unmarshal(event.Argument, &argument))
+		require.Equal(t, searchQueryEventArgument{
+			Query:       "repo:^github.com/sourcegraph/[REDACTED]$ -file:[REDACTED].md token",
+			PatternType: "standard",
+			Protocol:    "Streaming",
+			RepoFilters: []string{"^github.com/sourcegraph/[REDACTED]$"},
+			FileFilters: []string{"-[REDACTED].md"},
+		}, argument)
+	})
+
+	t.Run("does not wait for the database", func(t *testing.T) {
+		mockAuditLog(&schema.AuditLog{SearchQueries: true})
+
+		release := make(chan struct{})
+		securityEventLogs := dbmocks.NewMockSecurityEventLogsStore()
+		securityEventLogs.LogEventFunc.SetDefaultHook(func(context.Context, *database.SecurityEvent) { <-release })
+		db := dbmocks.NewMockDB()
+		db.SecurityEventLogsFunc.SetDefaultReturn(securityEventLogs)
+
+		logSearchQueryEvent(ctx, db, inputs)
+		close(release)
+
+		require.Eventually(t, func() bool {
+			return len(securityEventLogs.LogEventFunc.History()) == 1
+		}, 5*time.Second, 10*time.Millisecond)
+	})
+}
@@ -112,6 +112,12 @@ func (s *searchClient) Plan(
 		if err != nil {
 			return "", err
 		}
+		if searchcontexts.HasMaterializedRepositories(sc) {
+			// The repositories matched by the query are resolved from the
+			// database like those of a context with explicit repositories.
+			tr.AddEvent("using materialized repositories of context", attribute.String("context", context))
+			return "", nil
+		}
 		tr.AddEvent("substituted context filter with query", attribute.String("query", sc.Query), attribute.String("context", context))
 		return sc.Query, nil
 	})
@@ -157,6 +163,8 @@ func (s *searchClient) Execute(
 		return nil, err
 	}
 
+	logSearchQueryEvent(ctx, s.runtimeClients.DB, inputs)
+
 	return planJob.Run(ctx, s.JobClients(), stream)
 }
 
@@ -126,6 +126,10 @@ func (j SearchJob) Name() string {
 	return "CommitSearchJob"
 }
 
+func (j *SearchJob) Backend() string {
+	return job.BackendGitserver
+}
+
 func (j *SearchJob) Attributes(v job.Verbosity) (res []attribute.KeyValue) {
 	switch v {
 	case job.VerbosityMax:
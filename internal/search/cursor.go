@@ -0,0 +1,86 @@
+package search
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"hash/fnv"
+	"strconv"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// Cursor is the position at which a search stopped because it hit its result
+// or display limit. A search that is started with a cursor skips the
+// repositories and matches that were already returned.
+//
+// Clients only see the opaque string returned by Encode.
+type Cursor struct {
+	// Query identifies the query the cursor was created for. A cursor can only
+	// be used to resume the same query.
+	Query string `json:"q"`
+
+	// RepoCursors is the position of the repository pager at the start of the
+	// page of repositories in which the search stopped.
+	RepoCursors types.MultiCursor `json:"c,omitempty"`
+
+	// Returned are the sorted keys of the matches in that page which were
+	// already returned, as computed by streaming.CursorMatchKey. Matches are
+	// identified by key rather than by their position, since the order in
+	// which matches are found differs between executions of a search.
+	Returned []uint64 `json:"r,omitempty"`
+}
+
+// NewCursor returns an empty cursor for the given query.
+func NewCursor(query string) *Cursor {
+	return &Cursor{Query: cursorQueryHash(query)}
+}
+
+// Matches returns true if the cursor was created for the given query.
+func (c *Cursor) Matches(query string) bool {
+	return c.Query == cursorQueryHash(query)
+}
+
+// Encode returns the opaque representation of the cursor.
+func (c *Cursor) Encode() string {
+	// Marshalling cannot fail, all fields are plain data.
+	raw, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+// DecodeCursor parses a cursor returned by Encode.
+func DecodeCursor(encoded string) (*Cursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid cursor")
+	}
+
+	var c Cursor
+	if err := json.Unmarshal(raw, &c); err != nil {
+		return nil, errors.Wrap(err, "invalid cursor")
+	}
+	return &c, nil
+}
+
+func cursorQueryHash(query string) string {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(query))
+	return strconv.FormatUint(h.Sum64(), 36)
+}
+
+// RepoPage describes a page of repositories resolved by the repository pager.
+// Pages are reported in the stats of a search, so that consumers can compute
+// the cursor at which to resume the search.
+type RepoPage struct {
+	// Index is the position of the page among the pages of its pager,
+	// starting at 0.
+	Index int
+
+	// Cursors is the position of the repository pager at the start of the
+	// page.
+	Cursors types.MultiCursor
+
+	// Repos are the repositories of the page.
+	Repos []api.RepoID
+}
@@ -0,0 +1,135 @@
+package job
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/search"
+	"github.com/sourcegraph/sourcegraph/internal/search/streaming"
+)
+
+// Names of the backends which serve searches, as returned by Backender.
+const (
+	BackendZoekt     = "zoekt"
+	BackendSearcher  = "searcher"
+	BackendGitserver = "gitserver"
+)
+
+// Backender is implemented by jobs which send requests to a search backend.
+// It is used to attribute the time spent in a job when a search is explained.
+type Backender interface {
+	Backend() string
+}
+
+// ExplainNode describes how a job ran in a search with explain mode enabled.
+// Nodes are only recorded for jobs which call StartSpan.
+type ExplainNode struct {
+	Name       string
+	Attributes []attribute.KeyValue
+	Backend    string
+
+	// Duration is the wall time spent in Run of the job, including the time
+	// spent in its children.
+	Duration time.Duration
+
+	// Results is the number of results the job sent.
+	Results int
+
+	// Repos is the number of distinct repositories the job sent results or
+	// statuses for.
+	Repos int
+
+	// Shards is the number of Zoekt index shards searched by the job.
+	Shards int
+
+	Children []*ExplainNode
+
+	mu    sync.Mutex
+	repos map[api.RepoID]struct{}
+}
+
+// Explain collects the ExplainNodes of the jobs which run with a context
+// returned by WithExplain.
+type Explain struct {
+	mu    sync.Mutex
+	roots []*ExplainNode
+}
+
+type explainKey struct{}
+
+// WithExplain returns a context which records how every job run with it
+// performs.
+func WithExplain(ctx context.Context) (context.Context, *Explain) {
+	e := &Explain{}
+	return context.WithValue(ctx, explainKey{}, e), e
+}
+
+// Root returns the node of the first job that ran with the context of e. A
+// search runs a single job tree, so this is the root of the tree. Root must
+// only be called after the job finished.
+func (e *Explain) Root() *ExplainNode {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if len(e.roots) == 0 {
+		return nil
+	}
+	return e.roots[0]
+}
+
+// startExplain records a node for j if explain mode is enabled in ctx. The
+// returned context records jobs run with it as children of the node.
+func startExplain(ctx context.Context, j Job) (context.Context, *ExplainNode) {
+	node := &ExplainNode{
+		Name:       j.Name(),
+		Attributes: j.Attributes(VerbosityBasic),
+		repos:      map[api.RepoID]struct{}{},
+	}
+	if b, ok := j.(Backender); ok {
+		node.Backend = b.Backend()
+	}
+
+	switch parent := ctx.Value(explainKey{}).(type) {
+	case *Explain:
+		parent.mu.Lock()
+		parent.roots = append(parent.roots, node)
+		parent.mu.Unlock()
+	case *ExplainNode:
+		parent.mu.Lock()
+		parent.Children = append(parent.Children, node)
+		parent.mu.Unlock()
+	default:
+		return ctx, nil
+	}
+
+	return context.WithValue(ctx, explainKey{}, node), node
+}
+
+func (n *ExplainNode) observe(event streaming.SearchEvent) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	n.Results += len(event.Results)
+	n.Shards += event.Stats.ShardsScanned
+	for _, match := range event.Results {
+		n.repos[match.RepoName().ID] = struct{}{}
+	}
+	for id := range event.Stats.Repos {
+		n.repos[id] = struct{}{}
+	}
+	event.Stats.Status.Iterate(func(id api.RepoID, _ search.RepoStatus) {
+		n.repos[id] = struct{}{}
+	})
+	n.Repos = len(n.repos)
+}
+
+func (n *ExplainNode) finish(start time.Time) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	n.Duration = time.Since(start)
+}
@@ -62,6 +62,14 @@ func NewPlanJob(inputs *search.Inputs, plan query.Plan) (job.Job, error) {
 		jobTree = smartsearch.NewSmartSearchJob(jobTree, newJob, plan)
 	}
 
+	if inputs.Cursor != nil {
+		var err error
+		jobTree, err = withRepoPagerCursor(jobTree, inputs.Cursor)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	alertJob := NewAlertJob(inputs, jobTree)
 	logJob := NewLogJob(inputs, alertJob)
 	return logJob, nil
@@ -414,6 +422,7 @@ func NewFlatJob(searchInputs *search.Inputs, f query.Flat) (job.Job, error) {
 					query.FieldRepoHasCommitAfter: {},
 					query.FieldPatternType:        {},
 					query.FieldSelect:             {},
+					query.FieldExplain:            {},
 				}
 
 				// Don't run a repo search if the search contains fields that aren't on the allowlist.
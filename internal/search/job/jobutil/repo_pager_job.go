@@ -2,22 +2,27 @@ package jobutil
 
 import (
 	"context"
+	"sort"
 
 	"go.opentelemetry.io/otel/attribute"
 
+	"github.com/sourcegraph/sourcegraph/internal/api"
 	"github.com/sourcegraph/sourcegraph/internal/search"
 	"github.com/sourcegraph/sourcegraph/internal/search/job"
 	"github.com/sourcegraph/sourcegraph/internal/search/repos"
+	"github.com/sourcegraph/sourcegraph/internal/search/result"
 	"github.com/sourcegraph/sourcegraph/internal/search/searcher"
 	"github.com/sourcegraph/sourcegraph/internal/search/streaming"
 	"github.com/sourcegraph/sourcegraph/internal/search/zoekt"
 	"github.com/sourcegraph/sourcegraph/internal/trace"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
 )
 
 type repoPagerJob struct {
 	repoOpts         search.RepoOptions
 	containsRefGlobs bool                          // whether to include repositories with refs
 	child            job.PartialJob[resolvedRepos] // child job tree that need populating a repos field to run
+	cursor           *search.Cursor                // optional position to resume from
 }
 
 // resolvedRepos is the set of information to complete the partial
@@ -83,12 +88,20 @@ func (p *repoPagerJob) Run(ctx context.Context, clients job.RuntimeClients, stre
 
 	var maxAlerter search.MaxAlerter
 
+	repoOpts := p.repoOpts
+	var returned []uint64
+	if p.cursor != nil {
+		repoOpts.Cursors = p.cursor.RepoCursors
+		returned = p.cursor.Returned
+	}
+
 	repoResolver := repos.NewResolver(clients.Logger, clients.DB, clients.Gitserver, clients.SearcherURLs, clients.Zoekt)
-	it := repoResolver.Iterator(ctx, p.repoOpts)
+	it := repoResolver.Iterator(ctx, repoOpts)
 
-	for it.Next() {
+	for pageIndex := 0; it.Next(); pageIndex++ {
 		page := it.Current()
 		page.MaybeSendStats(stream)
+		sendRepoPage(stream, pageIndex, page)
 		indexed, unindexed, err := zoekt.PartitionRepos(
 			ctx,
 			clients.Logger,
@@ -103,8 +116,13 @@ func (p *repoPagerJob) Run(ctx context.Context, clients job.RuntimeClients, stre
 			return maxAlerter.Alert, err
 		}
 
+		pageStream := stream
+		if pageIndex == 0 && len(returned) > 0 {
+			pageStream = newReturnedMatchesStream(returned, stream)
+		}
+
 		job := p.child.Resolve(resolvedRepos{indexed, unindexed})
-		alert, err := job.Run(ctx, clients, stream)
+		alert, err := job.Run(ctx, clients, pageStream)
 		maxAlerter.Add(alert)
 
 		if err != nil {
@@ -115,6 +133,69 @@ func (p *repoPagerJob) Run(ctx context.Context, clients job.RuntimeClients, stre
 	return maxAlerter.Alert, it.Err()
 }
 
+// sendRepoPage reports the repositories of a page, so that consumers can
+// compute the cursor at which to resume the search.
+func sendRepoPage(stream streaming.Sender, index int, page repos.Resolved) {
+	ids := make([]api.RepoID, 0, len(page.RepoRevs))
+	for _, rr := range page.RepoRevs {
+		ids = append(ids, rr.Repo.ID)
+	}
+	stream.Send(streaming.SearchEvent{
+		Stats: streaming.Stats{
+			RepoPages: []search.RepoPage{{
+				Index:   index,
+				Cursors: page.Cursors,
+				Repos:   ids,
+			}},
+		},
+	})
+}
+
+// returnedMatchesStream drops the matches which were already returned by the
+// search the cursor was created for.
+type returnedMatchesStream struct {
+	parent   streaming.Sender
+	returned []uint64 // sorted
+}
+
+func newReturnedMatchesStream(returned []uint64, parent streaming.Sender) *returnedMatchesStream {
+	sorted := append([]uint64(nil), returned...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	return &returnedMatchesStream{parent: parent, returned: sorted}
+}
+
+func (s *returnedMatchesStream) Send(event streaming.SearchEvent) {
+	filtered := make(result.Matches, 0, len(event.Results))
+	for _, match := range event.Results {
+		key := streaming.CursorMatchKey(match)
+		i := sort.Search(len(s.returned), func(i int) bool { return s.returned[i] >= key })
+		if i < len(s.returned) && s.returned[i] == key {
+			continue
+		}
+		filtered = append(filtered, match)
+	}
+
+	event.Results = filtered
+	s.parent.Send(event)
+}
+
+// withRepoPagerCursor resumes the repository pager of j at cursor. Cursors
+// are only supported for job trees with a single repository pager, since a
+// cursor describes the position of one pager.
+func withRepoPagerCursor(j job.Job, cursor *search.Cursor) (job.Job, error) {
+	pagers := 0
+	job.VisitType(j, func(*repoPagerJob) { pagers++ })
+	if pagers != 1 {
+		return nil, errors.New("a cursor can only be used to resume searches over a single set of repositories")
+	}
+
+	return job.MapType(j, func(p *repoPagerJob) job.Job {
+		cp := *p
+		cp.cursor = cursor
+		return &cp
+	}), nil
+}
+
 func (p *repoPagerJob) Name() string {
 	return "RepoPagerJob"
 }
@@ -7,7 +7,9 @@ import (
 
 	"github.com/sourcegraph/sourcegraph/internal/api"
 	"github.com/sourcegraph/sourcegraph/internal/search"
+	"github.com/sourcegraph/sourcegraph/internal/search/result"
 	"github.com/sourcegraph/sourcegraph/internal/search/searcher"
+	"github.com/sourcegraph/sourcegraph/internal/search/streaming"
 	"github.com/sourcegraph/sourcegraph/internal/search/zoekt"
 	"github.com/sourcegraph/sourcegraph/internal/types"
 )
@@ -32,3 +34,43 @@ func Test_setRepos(t *testing.T) {
 	require.Len(t, j.(*ParallelJob).children[0].(*zoekt.RepoSubsetTextSearchJob).Repos.RepoRevs, 1)
 	require.Len(t, j.(*ParallelJob).children[1].(*searcher.TextSearchJob).Repos, 2)
 }
+
+func TestReturnedMatchesStream(t *testing.T) {
+	match := func(repo api.RepoID, path string) result.Match {
+		return &result.FileMatch{File: result.File{Repo: types.MinimalRepo{ID: repo}, Path: path}}
+	}
+
+	// The first execution of the search returned a and b before the limit
+	// was hit.
+	tracker := streaming.NewCursorTracker("foo", 10)
+	tracker.Update(streaming.SearchEvent{Stats: streaming.Stats{RepoPages: []search.RepoPage{{Repos: []api.RepoID{1, 2}}}}})
+	tracker.Update(streaming.SearchEvent{Results: result.Matches{match(1, "a"), match(1, "b"), match(1, "c")}})
+	tracker.Update(streaming.SearchEvent{Stats: streaming.Stats{IsLimitHit: true}})
+	cursor := tracker.Cursor()
+	require.NotNil(t, cursor)
+
+	// The resumed search finds the matches in a different order, so only the
+	// matches which were not returned before are kept.
+	var got result.Matches
+	s := newReturnedMatchesStream(cursor.Returned, streaming.StreamFunc(func(event streaming.SearchEvent) {
+		got = append(got, event.Results...)
+	}))
+	s.Send(streaming.SearchEvent{Results: result.Matches{match(1, "c"), match(2, "a")}})
+	s.Send(streaming.SearchEvent{Results: result.Matches{match(1, "b"), match(1, "a")}})
+
+	require.Equal(t, result.Matches{match(1, "c"), match(2, "a")}, got)
+}
+
+func Test_withRepoPagerCursor(t *testing.T) {
+	cursor := search.NewCursor("foo")
+
+	j, err := withRepoPagerCursor(NewLimitJob(10, &repoPagerJob{child: &reposPartialJob{&searcher.TextSearchJob{}}}), cursor)
+	require.NoError(t, err)
+	require.Equal(t, cursor, j.(*LimitJob).child.(*repoPagerJob).cursor)
+
+	_, err = withRepoPagerCursor(NewParallelJob(
+		&repoPagerJob{child: &reposPartialJob{&searcher.TextSearchJob{}}},
+		&repoPagerJob{child: &reposPartialJob{&searcher.SymbolSearchJob{}}},
+	), cursor)
+	require.Error(t, err)
+}
@@ -2,6 +2,7 @@ package job
 
 import (
 	"context"
+	"time"
 
 	"go.opentelemetry.io/otel/attribute"
 	"go.uber.org/atomic"
@@ -14,10 +15,12 @@ import (
 type finishSpanFunc func(*search.Alert, error)
 
 func StartSpan(ctx context.Context, stream streaming.Sender, job Job) (trace.Trace, context.Context, streaming.Sender, finishSpanFunc) {
+	start := time.Now()
 	tr, ctx := trace.New(ctx, job.Name())
 	tr.SetAttributes(job.Attributes(VerbosityMax)...)
 
-	observingStream := newObservingStream(tr, stream)
+	ctx, node := startExplain(ctx, job)
+	observingStream := newObservingStream(tr, node, stream)
 
 	return tr, ctx, observingStream, func(alert *search.Alert, err error) {
 		tr.SetError(err)
@@ -26,15 +29,19 @@ func StartSpan(ctx context.Context, stream streaming.Sender, job Job) (trace.Tra
 		}
 		tr.SetAttributes(attribute.Int64("total_results", observingStream.totalEvents.Load()))
 		tr.End()
+		if node != nil {
+			node.finish(start)
+		}
 	}
 }
 
-func newObservingStream(tr trace.Trace, parent streaming.Sender) *observingStream {
-	return &observingStream{tr: tr, parent: parent}
+func newObservingStream(tr trace.Trace, node *ExplainNode, parent streaming.Sender) *observingStream {
+	return &observingStream{tr: tr, node: node, parent: parent}
 }
 
 type observingStream struct {
 	tr          trace.Trace
+	node        *ExplainNode // nil unless the search is explained
 	parent      streaming.Sender
 	totalEvents atomic.Int64
 }
@@ -48,5 +55,8 @@ func (o *observingStream) Send(event streaming.SearchEvent) {
 			o.tr.AddEvent("first results")
 		}
 	}
+	if o.node != nil {
+		o.node.observe(event)
+	}
 	o.parent.Send(event)
 }
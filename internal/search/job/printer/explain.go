@@ -0,0 +1,55 @@
+package printer
+
+import (
+	"encoding/json"
+
+	"github.com/sourcegraph/sourcegraph/internal/search/job"
+)
+
+// ExplainedJob is the representation of a job that ran with explain mode
+// enabled, as returned to users.
+type ExplainedJob struct {
+	Name       string          `json:"name"`
+	Attributes map[string]any  `json:"attributes,omitempty"`
+	Backend    string          `json:"backend,omitempty"`
+	DurationMs float64         `json:"durationMs"`
+	Results    int             `json:"results"`
+	Repos      int             `json:"repos"`
+	Shards     int             `json:"shards,omitempty"`
+	Children   []*ExplainedJob `json:"children,omitempty"`
+}
+
+// Explain converts the tree of an explained job.
+func Explain(n *job.ExplainNode) *ExplainedJob {
+	if n == nil {
+		return nil
+	}
+
+	res := &ExplainedJob{
+		Name:       n.Name,
+		Backend:    n.Backend,
+		DurationMs: float64(n.Duration.Microseconds()) / 1000,
+		Results:    n.Results,
+		Repos:      n.Repos,
+		Shards:     n.Shards,
+	}
+	if len(n.Attributes) > 0 {
+		res.Attributes = make(map[string]any, len(n.Attributes))
+		for _, field := range n.Attributes {
+			res.Attributes[string(field.Key)] = field.Value.AsInterface()
+		}
+	}
+	for _, child := range n.Children {
+		res.Children = append(res.Children, Explain(child))
+	}
+	return res
+}
+
+// ExplainJSON returns the tree of an explained job in formatted JSON.
+func ExplainJSON(n *job.ExplainNode) string {
+	result, err := json.MarshalIndent(Explain(n), "", "  ")
+	if err != nil {
+		panic(err)
+	}
+	return string(result)
+}
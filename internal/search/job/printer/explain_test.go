@@ -0,0 +1,49 @@
+package printer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hexops/autogold/v2"
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/sourcegraph/sourcegraph/internal/search/job"
+)
+
+func TestExplainJSON(t *testing.T) {
+	node := &job.ExplainNode{
+		Name:       "LimitJob",
+		Attributes: []attribute.KeyValue{attribute.Int("limit", 100)},
+		Duration:   12500 * time.Microsecond,
+		Results:    3,
+		Repos:      2,
+		Children: []*job.ExplainNode{{
+			Name:     "ZoektGlobalTextSearchJob",
+			Backend:  job.BackendZoekt,
+			Duration: 10 * time.Millisecond,
+			Results:  3,
+			Repos:    2,
+			Shards:   5,
+		}},
+	}
+
+	autogold.Expect(autogold.Raw(`{
+  "name": "LimitJob",
+  "attributes": {
+    "limit": 100
+  },
+  "durationMs": 12.5,
+  "results": 3,
+  "repos": 2,
+  "children": [
+    {
+      "name": "ZoektGlobalTextSearchJob",
+      "backend": "zoekt",
+      "durationMs": 10,
+      "results": 3,
+      "repos": 2,
+      "shards": 5
+    }
+  ]
+}`)).Equal(t, autogold.Raw(ExplainJSON(node)))
+}
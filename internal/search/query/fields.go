@@ -33,6 +33,7 @@ const (
 	FieldTimeout   = "timeout"
 	FieldCombyRule = "rule"
 	FieldSelect    = "select"
+	FieldExplain   = "explain" // Searches that specify `explain:yes` return how each job of the search performed
 )
 
 var allFields = map[string]struct{}{
@@ -71,6 +72,7 @@ var allFields = map[string]struct{}{
 	FieldRev:                empty,
 	"revision":              empty,
 	FieldSelect:             empty,
+	FieldExplain:            empty,
 }
 
 var aliases = map[string]string{
@@ -138,6 +138,12 @@ func (q Q) IsCaseSensitive() bool {
 	return q.BoolValue("case")
 }
 
+// IsExplain returns true if the query asks for an explanation of how the
+// search performed.
+func (q Q) IsExplain() bool {
+	return q.BoolValue(FieldExplain)
+}
+
 func (q Q) Repositories() (repos []ParsedRepoFilter, negatedRepos []string) {
 	VisitField(q, FieldRepo, func(value string, negated bool, a Annotation) {
 		if a.Labels.IsSet(IsPredicate) {
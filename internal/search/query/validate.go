@@ -241,7 +241,8 @@ func validateField(field, value string, negated bool, seen map[string]struct{})
 		FieldDefault:
 		// Search patterns are not validated here, as it depends on the search type.
 	case
-		FieldCase:
+		FieldCase,
+		FieldExplain:
 		return satisfies(isSingular, isBoolean, isNotNegated)
 	case
 		FieldRepo:
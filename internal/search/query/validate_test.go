@@ -21,6 +21,14 @@ func TestValidation(t *testing.T) {
 			input: "case:yes case:no",
 			want:  `field "case" may not be used more than once`,
 		},
+		{
+			input: "explain:maybe",
+			want:  `invalid boolean "maybe"`,
+		},
+		{
+			input: "-explain:yes",
+			want:  `field "explain" does not support negation`,
+		},
 		{
 			input: "repo:[",
 			want:  "error parsing regexp: missing closing ]: `[`",
@@ -46,6 +46,10 @@ import (
 type Resolved struct {
 	RepoRevs []*search.RepositoryRevisions
 
+	// Cursors is the position of the pager at the start of this page. It is
+	// only set for pages returned by Resolver.Iterator.
+	Cursors types.MultiCursor
+
 	// BackendsMissing is the number of search backends that failed to be
 	// searched. This is due to it being unreachable. The most common reason
 	// for this is during zoekt rollout.
@@ -112,6 +116,7 @@ func (r *Resolver) Iterator(ctx context.Context, opts search.RepoOptions) *itera
 			}
 		}
 
+		page.Cursors = opts.Cursors
 		done = next == nil
 		opts.Cursors = next
 		return []Resolved{page}, nil
@@ -279,8 +284,9 @@ func (r *Resolver) doQueryDB(ctx context.Context, tr trace.Trace, op search.Repo
 	}
 
 	// Filter by search context repository revisions only if this search context doesn't have
-	// a query, which replaces the context:foo term at query parsing time.
-	if searchContext.Query == "" {
+	// a query, which replaces the context:foo term at query parsing time, or if the
+	// repositories matched by its query have been materialized.
+	if searchContext.Query == "" || searchcontexts.HasMaterializedRepositories(searchContext) {
 		options.SearchContextID = searchContext.ID
 	}
 
@@ -321,7 +327,7 @@ func (r *Resolver) doQueryDB(ctx context.Context, tr trace.Trace, op search.Repo
 	}
 
 	var searchContextRepositoryRevisions map[api.RepoID]RepoRevSpecs
-	if !searchcontexts.IsAutoDefinedSearchContext(searchContext) && searchContext.Query == "" {
+	if !searchcontexts.IsAutoDefinedSearchContext(searchContext) && (searchContext.Query == "" || searchcontexts.HasMaterializedRepositories(searchContext)) {
 		scRepoRevs, err := searchcontexts.GetRepositoryRevisions(ctx, r.db, searchContext.ID)
 		if err != nil {
 			return dbResolved{}, nil, err
@@ -303,12 +303,17 @@ func TestResolverIterator(t *testing.T) {
 		t.Fatal(err)
 	}
 
+	// cursorAt returns the cursor of a page starting at all.RepoRevs[i].
+	cursorAt := func(i int) types.MultiCursor {
+		return types.MultiCursor{
+			{Column: "stars", Direction: "prev", Value: fmt.Sprint(all.RepoRevs[i].Repo.Stars)},
+			{Column: "id", Direction: "prev", Value: fmt.Sprint(all.RepoRevs[i].Repo.ID)},
+		}
+	}
+
 	// Assertation that we get the cursor we expect
 	{
-		want := types.MultiCursor{
-			{Column: "stars", Direction: "prev", Value: fmt.Sprint(all.RepoRevs[3].Repo.Stars)},
-			{Column: "id", Direction: "prev", Value: fmt.Sprint(all.RepoRevs[3].Repo.ID)},
-		}
+		want := cursorAt(3)
 		_, next, err := resolver.resolve(ctx, search.RepoOptions{
 			Limit: 3,
 		})
@@ -347,6 +352,7 @@ func TestResolverIterator(t *testing.T) {
 				},
 				{
 					RepoRevs: all.RepoRevs[3:],
+					Cursors:  cursorAt(3),
 				},
 			},
 		},
@@ -381,21 +387,20 @@ func TestResolverIterator(t *testing.T) {
 				},
 				{
 					RepoRevs: allAtRev.RepoRevs[2:],
+					Cursors:  cursorAt(3),
 				},
 			},
 		},
 		{
 			name: "with limit 3 and cursor",
 			opts: search.RepoOptions{
-				Limit: 3,
-				Cursors: types.MultiCursor{
-					{Column: "stars", Direction: "prev", Value: fmt.Sprint(all.RepoRevs[3].Repo.Stars)},
-					{Column: "id", Direction: "prev", Value: fmt.Sprint(all.RepoRevs[3].Repo.ID)},
-				},
+				Limit:   3,
+				Cursors: cursorAt(3),
 			},
 			pages: []Resolved{
 				{
 					RepoRevs: all.RepoRevs[3:],
+					Cursors:  cursorAt(3),
 				},
 			},
 		},
@@ -3,6 +3,7 @@ package searchcontexts
 import (
 	"context"
 	"fmt"
+	"sort"
 	"strings"
 	"sync"
 
@@ -458,49 +459,196 @@ func ParseRepoOpts(contextQuery string) ([]RepoOpts, error) {
 
 	qs := make([]RepoOpts, 0, len(plan))
 	for _, p := range plan {
-		q := p.ToParseTree()
+		qs = append(qs, toRepoOpts(p))
+	}
+
+	return qs, nil
+}
+
+func toRepoOpts(p query.Basic) RepoOpts {
+	q := p.ToParseTree()
+
+	repoFilters, minusRepoFilters := q.Repositories()
+
+	fork := query.No
+	if setFork := q.Fork(); setFork != nil {
+		fork = *setFork
+	}
 
-		repoFilters, minusRepoFilters := q.Repositories()
+	archived := query.No
+	if setArchived := q.Archived(); setArchived != nil {
+		archived = *setArchived
+	}
+
+	visibilityStr, _ := q.StringValue(query.FieldVisibility)
+	visibility := query.ParseVisibility(visibilityStr)
+
+	rq := RepoOpts{
+		ReposListOptions: database.ReposListOptions{
+			CaseSensitivePatterns: q.IsCaseSensitive(),
+			ExcludePattern:        query.UnionRegExps(minusRepoFilters),
+			DescriptionPatterns:   p.RepoHasDescription(),
+			OnlyForks:             fork == query.Only,
+			NoForks:               fork == query.No,
+			OnlyArchived:          archived == query.Only,
+			NoArchived:            archived == query.No,
+			NoPrivate:             visibility == query.Public,
+			OnlyPrivate:           visibility == query.Private,
+		},
+	}
+
+	for _, filter := range p.RepoHasKVPs() {
+		rq.KVPFilters = append(rq.KVPFilters, database.RepoKVPFilter{
+			Key:     filter.Key,
+			Value:   filter.Value,
+			Negated: filter.Negated,
+			KeyOnly: filter.KeyOnly,
+		})
+	}
 
-		fork := query.No
-		if setFork := q.Fork(); setFork != nil {
-			fork = *setFork
+	for _, filter := range p.RepoHasTopics() {
+		rq.TopicFilters = append(rq.TopicFilters, database.RepoTopicFilter{
+			Topic:   filter.Topic,
+			Negated: filter.Negated,
+		})
+	}
+
+	for _, r := range repoFilters {
+		for _, rev := range r.Revs {
+			if !rev.HasRefGlob() {
+				rq.RevSpecs = append(rq.RevSpecs, rev.RevSpec)
+			}
 		}
+		rq.IncludePatterns = append(rq.IncludePatterns, r.Repo)
+	}
+
+	return rq
+}
+
+// CanMaterializeQuery returns true if the repositories matched by the given
+// search context query can be materialized ahead of search time. This is the
+// case when the query only consists of repository filters whose predicates, if
+// any, match repository metadata: file: and lang: filters, as well as repo:
+// predicates matching the contents or history of repositories, have to be
+// applied by the search itself.
+func CanMaterializeQuery(contextQuery string) bool {
+	if contextQuery == "" {
+		return false
+	}
+
+	plan, err := query.Pipeline(query.Init(contextQuery, query.SearchTypeRegex))
+	if err != nil {
+		return false
+	}
 
-		archived := query.No
-		if setArchived := q.Archived(); setArchived != nil {
-			archived = *setArchived
+	ok := true
+	query.VisitParameter(plan.ToQ(), func(field, value string, _ bool, annotation query.Annotation) {
+		switch field {
+		case query.FieldRepo:
+			if annotation.Labels.IsSet(query.IsPredicate) && !isMaterializableRepoPredicate(value) {
+				ok = false
+			}
+		case query.FieldFork, query.FieldArchived, query.FieldVisibility, query.FieldCase:
+		default:
+			ok = false
 		}
+	})
+	return ok
+}
+
+// isMaterializableRepoPredicate returns true if the given repo: predicate is
+// applied by the repository store when resolving the repositories of a search
+// context query. Other predicates, such as has.file or has.commit.after, are
+// dropped by toRepoOpts and have to be evaluated by the search itself.
+func isMaterializableRepoPredicate(value string) bool {
+	name, _ := query.ParseAsPredicate(value)
+	newPredicate, ok := query.DefaultPredicateRegistry[query.FieldRepo][name]
+	if !ok {
+		return false
+	}
+
+	switch newPredicate().(type) {
+	case *query.RepoHasDescriptionPredicate,
+		*query.RepoHasTopicPredicate,
+		*query.RepoHasKVPPredicate,
+		*query.RepoHasMetaPredicate,
+		*query.RepoHasTagPredicate,
+		*query.RepoHasKeyPredicate:
+		return true
+	default:
+		return false
+	}
+}
+
+// HasMaterializedRepositories returns true if the repositories matched by the
+// query of the search context are materialized in the database, so that the
+// search context can be resolved like a search context with an explicit list
+// of repositories.
+func HasMaterializedRepositories(searchContext *types.SearchContext) bool {
+	return searchContext.Query != "" && !searchContext.ReposMaterializedAt.IsZero()
+}
+
+// ResolveQueryRepositoryRevisions returns the repositories and revisions
+// matched by the given search context query. Repositories matched by a repo:
+// filter without revisions are searched at HEAD.
+//
+// 🚨 SECURITY: The result is filtered by the permissions of the actor in the
+// context. Callers materializing the result for all users must use an
+// internal actor.
+func ResolveQueryRepositoryRevisions(ctx context.Context, db database.DB, contextQuery string) ([]*types.SearchContextRepositoryRevisions, error) {
+	plan, err := query.Pipeline(query.Init(contextQuery, query.SearchTypeRegex))
+	if err != nil {
+		return nil, err
+	}
 
-		visibilityStr, _ := q.StringValue(query.FieldVisibility)
-		visibility := query.ParseVisibility(visibilityStr)
-
-		rq := RepoOpts{
-			ReposListOptions: database.ReposListOptions{
-				CaseSensitivePatterns: q.IsCaseSensitive(),
-				ExcludePattern:        query.UnionRegExps(minusRepoFilters),
-				OnlyForks:             fork == query.Only,
-				NoForks:               fork == query.No,
-				OnlyArchived:          archived == query.Only,
-				NoArchived:            archived == query.No,
-				NoPrivate:             visibility == query.Public,
-				OnlyPrivate:           visibility == query.Private,
-			},
+	repos := map[api.RepoID]types.MinimalRepo{}
+	revisions := map[api.RepoID]map[string]struct{}{}
+
+	for _, p := range plan {
+		opts := toRepoOpts(p)
+		repoFilters, _ := p.ToParseTree().Repositories()
+
+		rs, err := db.Repos().ListMinimalRepos(ctx, opts.ReposListOptions)
+		if err != nil {
+			return nil, err
 		}
 
-		for _, r := range repoFilters {
-			for _, rev := range r.Revs {
-				if !rev.HasRefGlob() {
-					rq.RevSpecs = append(rq.RevSpecs, rev.RevSpec)
+		for _, r := range rs {
+			repos[r.ID] = r
+			if revisions[r.ID] == nil {
+				revisions[r.ID] = map[string]struct{}{}
+			}
+
+			var matched bool
+			for _, f := range repoFilters {
+				if len(f.Revs) == 0 || !f.RepoRegex.MatchString(string(r.Name)) {
+					continue
 				}
+				for _, rev := range f.Revs {
+					if !rev.HasRefGlob() && rev.RevSpec != "" {
+						revisions[r.ID][rev.RevSpec] = struct{}{}
+						matched = true
+					}
+				}
+			}
+			if !matched {
+				revisions[r.ID]["HEAD"] = struct{}{}
 			}
-			rq.IncludePatterns = append(rq.IncludePatterns, r.Repo)
 		}
+	}
 
-		qs = append(qs, rq)
+	out := make([]*types.SearchContextRepositoryRevisions, 0, len(repos))
+	for id, r := range repos {
+		revs := make([]string, 0, len(revisions[id]))
+		for rev := range revisions[id] {
+			revs = append(revs, rev)
+		}
+		sort.Strings(revs)
+		out = append(out, &types.SearchContextRepositoryRevisions{Repo: r, Revisions: revs})
 	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Repo.ID < out[j].Repo.ID })
 
-	return qs, nil
+	return out, nil
 }
 
 func GetRepositoryRevisions(ctx context.Context, db database.DB, searchContextID int64) ([]search.RepositoryRevisions, error) {
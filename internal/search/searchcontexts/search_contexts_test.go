@@ -723,6 +723,38 @@ func TestParseRepoOpts(t *testing.T) {
 	}
 }
 
+func TestCanMaterializeQuery(t *testing.T) {
+	cases := []struct {
+		query string
+		want  bool
+	}{
+		{query: "", want: false},
+		{query: "repo:^github\\.com/sourcegraph/", want: true},
+		{query: "(repo:foo or repo:bar) archived:yes fork:no visibility:public", want: true},
+		{query: "repo:has.topic(go) case:yes", want: true},
+		{query: "repo:foo@main:dev", want: true},
+		{query: "repo:foo lang:go", want: false},
+		{query: "repo:foo file:^docs/", want: false},
+		{query: "repo:foo rev:main", want: false},
+		{query: "repo:has.description(indexer) repo:has(owner:code-intel)", want: true},
+		{query: "repo:has.key(owner) -repo:has.tag(deprecated)", want: true},
+		{query: "repo:foo repo:has.file(path:go.mod)", want: false},
+		{query: "repo:contains.file(path:package.json)", want: false},
+		{query: "repo:has.path(docs/)", want: false},
+		{query: "repo:has.content(TODO)", want: false},
+		{query: "repo:has.commit.after(1 month ago)", want: false},
+		{query: "repo:foo or repo:contains.commit.after(yesterday)", want: false},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.query, func(t *testing.T) {
+			if have := CanMaterializeQuery(tc.query); have != tc.want {
+				t.Errorf("CanMaterializeQuery(%q) = %t, want %t", tc.query, have, tc.want)
+			}
+		})
+	}
+}
+
 func Test_validateSearchContextQuery(t *testing.T) {
 	cases := []struct {
 		query   string
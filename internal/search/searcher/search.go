@@ -142,6 +142,10 @@ func (s *TextSearchJob) Name() string {
 	return "SearcherTextSearchJob"
 }
 
+func (s *TextSearchJob) Backend() string {
+	return job.BackendSearcher
+}
+
 func (s *TextSearchJob) Attributes(v job.Verbosity) (res []attribute.KeyValue) {
 	switch v {
 	case job.VerbosityMax:
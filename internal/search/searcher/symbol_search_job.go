@@ -69,6 +69,10 @@ func (s *SymbolSearchJob) Name() string {
 	return "SearcherSymbolSearchJob"
 }
 
+func (s *SymbolSearchJob) Backend() string {
+	return job.BackendSearcher
+}
+
 func (s *SymbolSearchJob) Attributes(v job.Verbosity) (res []attribute.KeyValue) {
 	switch v {
 	case job.VerbosityMax:
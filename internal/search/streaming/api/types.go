@@ -25,6 +25,12 @@ type Progress struct {
 
 	// Trace is the URL of an associated trace if the query is logging one.
 	Trace string `json:"trace,omitempty"`
+
+	// Cursor is an opaque cursor which can be passed to a subsequent search
+	// for the same query to return the results after the ones returned by
+	// this search. It is only set on the final progress event of a search
+	// that stopped at its limit.
+	Cursor string `json:"cursor,omitempty"`
 }
 
 // Skipped is a description of shards or documents that were skipped.
@@ -0,0 +1,148 @@
+package streaming
+
+import (
+	"encoding/binary"
+	"hash/fnv"
+	"sort"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/search"
+	"github.com/sourcegraph/sourcegraph/internal/search/result"
+)
+
+// CursorTracker observes the events of a search to compute the cursor at which
+// the search can be resumed after it stopped at its result or display limit.
+//
+// A cursor can only be computed if all results come from the repositories of
+// a single repository pager. This is not the case for global searches which
+// do not page through repositories, or for queries with several independent
+// parts.
+type CursorTracker struct {
+	query            string
+	displayRemaining int
+
+	pages    []search.RepoPage
+	repoPage map[api.RepoID]int      // index into pages
+	sent     map[api.RepoID][]uint64 // keys of the matches returned per repository
+
+	// firstIncomplete is the index of the first page with a match that was
+	// not returned, or -1.
+	firstIncomplete int
+
+	lastRepo    api.RepoID
+	hasLast     bool
+	limitHit    bool
+	unsupported bool
+}
+
+// NewCursorTracker returns a CursorTracker for a search of query whose
+// results are truncated after displayLimit results.
+func NewCursorTracker(query string, displayLimit int) *CursorTracker {
+	return &CursorTracker{
+		query:            query,
+		displayRemaining: displayLimit,
+		repoPage:         map[api.RepoID]int{},
+		sent:             map[api.RepoID][]uint64{},
+		firstIncomplete:  -1,
+	}
+}
+
+// Update records an event. It must be called with every event of the search
+// in order and before the results of the event are truncated to the display
+// limit.
+func (t *CursorTracker) Update(event SearchEvent) {
+	for _, page := range event.Stats.RepoPages {
+		if page.Index == 0 && len(t.pages) > 0 {
+			// A second pager started.
+			t.unsupported = true
+		}
+		for _, id := range page.Repos {
+			t.repoPage[id] = len(t.pages)
+		}
+		t.pages = append(t.pages, page)
+	}
+
+	for _, match := range event.Results {
+		id := match.RepoName().ID
+		page, ok := t.repoPage[id]
+		if !ok {
+			t.unsupported = true
+			continue
+		}
+
+		if count := match.ResultCount(); count <= t.displayRemaining {
+			t.displayRemaining -= count
+			t.sent[id] = append(t.sent[id], CursorMatchKey(match))
+			t.lastRepo, t.hasLast = id, true
+		} else {
+			t.displayRemaining = 0
+			t.markIncomplete(page)
+		}
+	}
+
+	if event.Stats.IsLimitHit && !t.limitHit {
+		t.limitHit = true
+
+		// The match that made the search hit its limit may have been
+		// truncated. We rather return it again than lose its remaining
+		// results.
+		if t.hasLast {
+			sent := t.sent[t.lastRepo]
+			t.sent[t.lastRepo] = sent[:len(sent)-1]
+			t.markIncomplete(t.repoPage[t.lastRepo])
+		}
+	}
+}
+
+func (t *CursorTracker) markIncomplete(page int) {
+	if t.firstIncomplete < 0 || page < t.firstIncomplete {
+		t.firstIncomplete = page
+	}
+}
+
+// Cursor returns the cursor at which the search can be resumed. It returns
+// nil if all results were returned or if the search cannot be resumed.
+func (t *CursorTracker) Cursor() *search.Cursor {
+	if t.unsupported || len(t.pages) == 0 {
+		return nil
+	}
+
+	page := t.firstIncomplete
+	if page < 0 {
+		if !t.limitHit {
+			return nil
+		}
+		// The search was canceled while searching the last page.
+		page = len(t.pages) - 1
+	}
+
+	c := search.NewCursor(t.query)
+	c.RepoCursors = t.pages[page].Cursors
+	for _, id := range t.pages[page].Repos {
+		c.Returned = append(c.Returned, t.sent[id]...)
+	}
+	sort.Slice(c.Returned, func(i, j int) bool { return c.Returned[i] < c.Returned[j] })
+	return c
+}
+
+// CursorMatchKey returns the key that identifies a match in a cursor. It
+// does not depend on the order in which the match was found, and file
+// matches in the same file are considered equal even if the commit they were
+// found in changed between the executions of the search.
+func CursorMatchKey(match result.Match) uint64 {
+	k := match.Key()
+	h := fnv.New64a()
+	write := func(s string) {
+		_, _ = h.Write([]byte(s))
+		_, _ = h.Write([]byte{0})
+	}
+	_ = binary.Write(h, binary.LittleEndian, int64(match.RepoName().ID))
+	write(k.Rev)
+	write(k.Path)
+	write(k.OwnerMetadata)
+	if k.Path == "" {
+		write(string(k.Commit))
+	}
+	_ = binary.Write(h, binary.LittleEndian, int64(k.TypeRank))
+	return h.Sum64()
+}
@@ -0,0 +1,118 @@
+package streaming
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver/gitdomain"
+	"github.com/sourcegraph/sourcegraph/internal/search"
+	"github.com/sourcegraph/sourcegraph/internal/search/result"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+)
+
+func TestCursorTracker(t *testing.T) {
+	page0 := search.RepoPage{Index: 0, Repos: []api.RepoID{1, 2}}
+	page1 := search.RepoPage{
+		Index:   1,
+		Cursors: types.MultiCursor{{Column: "id", Value: "2", Direction: "prev"}},
+		Repos:   []api.RepoID{3, 4},
+	}
+
+	match := func(repo api.RepoID, path string) result.Match {
+		return &result.FileMatch{File: result.File{Repo: types.MinimalRepo{ID: repo}, Path: path}}
+	}
+	keys := func(matches ...result.Match) []uint64 {
+		var ks []uint64
+		for _, m := range matches {
+			ks = append(ks, CursorMatchKey(m))
+		}
+		sort.Slice(ks, func(i, j int) bool { return ks[i] < ks[j] })
+		return ks
+	}
+
+	t.Run("complete search", func(t *testing.T) {
+		tracker := NewCursorTracker("foo", 10)
+		tracker.Update(SearchEvent{Stats: Stats{RepoPages: []search.RepoPage{page0}}})
+		tracker.Update(SearchEvent{Results: result.Matches{match(1, "a"), match(2, "a")}})
+		require.Nil(t, tracker.Cursor())
+	})
+
+	t.Run("limit hit", func(t *testing.T) {
+		tracker := NewCursorTracker("foo", 10)
+		tracker.Update(SearchEvent{Stats: Stats{RepoPages: []search.RepoPage{page0}}})
+		tracker.Update(SearchEvent{Results: result.Matches{match(1, "a"), match(2, "a")}})
+		tracker.Update(SearchEvent{Stats: Stats{RepoPages: []search.RepoPage{page1}}})
+		tracker.Update(SearchEvent{Results: result.Matches{match(3, "a"), match(3, "b"), match(4, "a")}})
+		tracker.Update(SearchEvent{Stats: Stats{IsLimitHit: true}})
+
+		cursor := tracker.Cursor()
+		require.NotNil(t, cursor)
+		require.True(t, cursor.Matches("foo"))
+		require.Equal(t, page1.Cursors, cursor.RepoCursors)
+		// The last match may have been truncated, so it is returned again.
+		require.Equal(t, keys(match(3, "a"), match(3, "b")), cursor.Returned)
+	})
+
+	t.Run("display limit", func(t *testing.T) {
+		tracker := NewCursorTracker("foo", 1)
+		tracker.Update(SearchEvent{Stats: Stats{RepoPages: []search.RepoPage{page0}}})
+		tracker.Update(SearchEvent{Results: result.Matches{match(1, "a"), match(2, "a")}})
+		tracker.Update(SearchEvent{Stats: Stats{RepoPages: []search.RepoPage{page1}}})
+		tracker.Update(SearchEvent{Results: result.Matches{match(3, "a")}})
+
+		cursor := tracker.Cursor()
+		require.NotNil(t, cursor)
+		require.Nil(t, cursor.RepoCursors)
+		require.Equal(t, keys(match(1, "a")), cursor.Returned)
+	})
+
+	t.Run("results outside of pages", func(t *testing.T) {
+		tracker := NewCursorTracker("foo", 10)
+		tracker.Update(SearchEvent{Stats: Stats{RepoPages: []search.RepoPage{page0}}})
+		tracker.Update(SearchEvent{Results: result.Matches{match(1, "a"), match(5, "a")}})
+		tracker.Update(SearchEvent{Stats: Stats{IsLimitHit: true}})
+		require.Nil(t, tracker.Cursor())
+	})
+
+	t.Run("multiple pagers", func(t *testing.T) {
+		tracker := NewCursorTracker("foo", 10)
+		tracker.Update(SearchEvent{Stats: Stats{RepoPages: []search.RepoPage{page0, page0}}})
+		tracker.Update(SearchEvent{Stats: Stats{IsLimitHit: true}})
+		require.Nil(t, tracker.Cursor())
+	})
+
+	t.Run("encoding", func(t *testing.T) {
+		cursor := search.NewCursor("foo")
+		cursor.RepoCursors = page1.Cursors
+		cursor.Returned = keys(match(3, "a"), match(3, "b"))
+
+		decoded, err := search.DecodeCursor(cursor.Encode())
+		require.NoError(t, err)
+		require.Equal(t, cursor, decoded)
+		require.False(t, decoded.Matches("bar"))
+
+		_, err = search.DecodeCursor("not a cursor")
+		require.Error(t, err)
+	})
+}
+
+func TestCursorMatchKey(t *testing.T) {
+	repo := types.MinimalRepo{ID: 1, Name: "foo"}
+	fm := func(path, commit string) result.Match {
+		return &result.FileMatch{File: result.File{Repo: repo, Path: path, CommitID: api.CommitID(commit)}}
+	}
+
+	// File matches are identified by their path, even if the commit changed.
+	require.Equal(t, CursorMatchKey(fm("a", "c1")), CursorMatchKey(fm("a", "c2")))
+	require.NotEqual(t, CursorMatchKey(fm("a", "c1")), CursorMatchKey(fm("b", "c1")))
+	require.NotEqual(t, CursorMatchKey(fm("a", "c1")), CursorMatchKey(&result.RepoMatch{ID: 1, Name: "foo"}))
+
+	// Commit matches are identified by their commit.
+	cm := func(commit string) result.Match {
+		return &result.CommitMatch{Repo: repo, Commit: gitdomain.Commit{ID: api.CommitID(commit)}}
+	}
+	require.NotEqual(t, CursorMatchKey(cm("c1")), CursorMatchKey(cm("c2")))
+}
@@ -0,0 +1,293 @@
+package http
+
+import (
+	"encoding/json"
+	"io"
+	"sync"
+
+	"github.com/sourcegraph/sourcegraph/internal/search/result"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// SARIF (Static Analysis Results Interchange Format) is the format consumed by
+// code scanning tools such as GitHub code scanning and DefectDojo. The types
+// below implement the subset of SARIF 2.1.0 required to describe search
+// results. See https://docs.oasis-open.org/sarif/sarif/v2.1.0/sarif-v2.1.0.html.
+const (
+	SARIFVersion = "2.1.0"
+	SARIFSchema  = "https://json.schemastore.org/sarif-2.1.0.json"
+
+	// SARIFContentType is the media type registered for SARIF logs.
+	SARIFContentType = "application/sarif+json"
+)
+
+type SARIFLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []SARIFRun `json:"runs"`
+}
+
+type SARIFRun struct {
+	Tool        SARIFTool         `json:"tool"`
+	ColumnKind  string            `json:"columnKind,omitempty"`
+	Results     []SARIFResult     `json:"results"`
+	Invocations []SARIFInvocation `json:"invocations,omitempty"`
+}
+
+type SARIFTool struct {
+	Driver SARIFToolComponent `json:"driver"`
+}
+
+type SARIFToolComponent struct {
+	Name           string      `json:"name"`
+	InformationURI string      `json:"informationUri,omitempty"`
+	Rules          []SARIFRule `json:"rules,omitempty"`
+}
+
+// SARIFRule is a reportingDescriptor describing the search query that
+// produced the results.
+type SARIFRule struct {
+	ID               string         `json:"id"`
+	Name             string         `json:"name,omitempty"`
+	ShortDescription *SARIFMessage  `json:"shortDescription,omitempty"`
+	HelpURI          string         `json:"helpUri,omitempty"`
+	Properties       map[string]any `json:"properties,omitempty"`
+}
+
+type SARIFMessage struct {
+	Text string `json:"text"`
+}
+
+type SARIFResult struct {
+	RuleID     string          `json:"ruleId"`
+	RuleIndex  int             `json:"ruleIndex"`
+	Level      string          `json:"level,omitempty"`
+	Message    SARIFMessage    `json:"message"`
+	Locations  []SARIFLocation `json:"locations"`
+	Properties map[string]any  `json:"properties,omitempty"`
+}
+
+type SARIFLocation struct {
+	PhysicalLocation SARIFPhysicalLocation `json:"physicalLocation"`
+}
+
+type SARIFPhysicalLocation struct {
+	ArtifactLocation SARIFArtifactLocation `json:"artifactLocation"`
+	Region           *SARIFRegion          `json:"region,omitempty"`
+	ContextRegion    *SARIFRegion          `json:"contextRegion,omitempty"`
+}
+
+type SARIFArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+// SARIFRegion is a range in a file. Lines and columns are 1-based and the end
+// column is exclusive.
+type SARIFRegion struct {
+	StartLine   int                   `json:"startLine"`
+	StartColumn int                   `json:"startColumn,omitempty"`
+	EndLine     int                   `json:"endLine,omitempty"`
+	EndColumn   int                   `json:"endColumn,omitempty"`
+	Snippet     *SARIFArtifactContent `json:"snippet,omitempty"`
+}
+
+type SARIFArtifactContent struct {
+	Text string `json:"text"`
+}
+
+type SARIFInvocation struct {
+	ExecutionSuccessful        bool                `json:"executionSuccessful"`
+	ToolExecutionNotifications []SARIFNotification `json:"toolExecutionNotifications,omitempty"`
+}
+
+type SARIFNotification struct {
+	Level   string       `json:"level"`
+	Message SARIFMessage `json:"message"`
+}
+
+// SARIFResultsFromFileMatch converts a file match to SARIF results reported
+// for ruleID. Every range of a chunk match and every symbol becomes a result
+// of its own. Path matches become a single result without a region.
+//
+// Artifact URIs are relative to the root of the repository, which is what
+// code scanning tools expect. The repository and commit are recorded in the
+// properties of each result.
+func SARIFResultsFromFileMatch(fm *result.FileMatch, ruleID string) []SARIFResult {
+	newResult := func(message string, region, contextRegion *SARIFRegion) SARIFResult {
+		return SARIFResult{
+			RuleID:  ruleID,
+			Level:   "warning",
+			Message: SARIFMessage{Text: message},
+			Locations: []SARIFLocation{{
+				PhysicalLocation: SARIFPhysicalLocation{
+					ArtifactLocation: SARIFArtifactLocation{URI: fm.Path},
+					Region:           region,
+					ContextRegion:    contextRegion,
+				},
+			}},
+			Properties: map[string]any{
+				"repository": string(fm.Repo.Name),
+				"commit":     string(fm.CommitID),
+			},
+		}
+	}
+
+	var results []SARIFResult
+	for _, cm := range fm.ChunkMatches {
+		contextRegion := &SARIFRegion{
+			StartLine: cm.ContentStart.Line + 1,
+			EndLine:   cm.ContentStart.Line + countLines(cm.Content),
+			Snippet:   &SARIFArtifactContent{Text: cm.Content},
+		}
+		matched := cm.MatchedContent()
+		for i, rr := range cm.Ranges {
+			region := &SARIFRegion{
+				StartLine:   rr.Start.Line + 1,
+				StartColumn: rr.Start.Column + 1,
+				EndLine:     rr.End.Line + 1,
+				EndColumn:   rr.End.Column + 1,
+				Snippet:     &SARIFArtifactContent{Text: matched[i]},
+			}
+			results = append(results, newResult("Search query matched "+fm.Path, region, contextRegion))
+		}
+	}
+
+	for _, sym := range fm.Symbols {
+		r := sym.Symbol.Range()
+		region := &SARIFRegion{
+			StartLine:   r.Start.Line + 1,
+			StartColumn: r.Start.Character + 1,
+			EndLine:     r.End.Line + 1,
+			EndColumn:   r.End.Character + 1,
+		}
+		results = append(results, newResult("Search query matched symbol "+sym.Symbol.Name, region, nil))
+	}
+
+	if len(results) == 0 {
+		results = append(results, newResult("Search query matched path "+fm.Path, nil, nil))
+	}
+
+	return results
+}
+
+// countLines returns the number of lines in s, not counting a trailing
+// newline.
+func countLines(s string) int {
+	n := 1
+	for i := 0; i < len(s)-1; i++ {
+		if s[i] == '\n' {
+			n++
+		}
+	}
+	return n
+}
+
+// SARIFWriter writes a SARIF log with a single run. A SARIF log is a single
+// JSON document, but results are written as soon as they are received so that
+// large result sets do not need to be buffered. Close must be called to
+// complete the document.
+type SARIFWriter struct {
+	mu sync.Mutex
+
+	w             io.Writer
+	tool          SARIFTool
+	wroteHeader   bool
+	wroteResult   bool
+	notifications []SARIFNotification
+	successful    bool
+	err           error
+}
+
+// NewSARIFWriter returns a SARIFWriter whose results are reported by rule.
+func NewSARIFWriter(w io.Writer, rule SARIFRule) *SARIFWriter {
+	return &SARIFWriter{
+		w: w,
+		tool: SARIFTool{Driver: SARIFToolComponent{
+			Name:           "Sourcegraph",
+			InformationURI: "https://sourcegraph.com",
+			Rules:          []SARIFRule{rule},
+		}},
+		successful: true,
+	}
+}
+
+// Write writes results to the run. Results always refer to the only rule of
+// the run, so RuleIndex is overwritten.
+func (s *SARIFWriter) Write(results ...SARIFResult) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.writeHeader()
+	for _, r := range results {
+		r.RuleIndex = 0
+		encoded, err := json.Marshal(r)
+		if err != nil {
+			return err
+		}
+		if s.wroteResult {
+			s.write([]byte{','})
+		}
+		s.write(encoded)
+		s.wroteResult = true
+	}
+	return s.err
+}
+
+// Notify records a notification which is included in the invocation of the
+// run. Notifications with level "error" mark the invocation as unsuccessful.
+func (s *SARIFWriter) Notify(level, message string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if level == "error" {
+		s.successful = false
+	}
+	s.notifications = append(s.notifications, SARIFNotification{
+		Level:   level,
+		Message: SARIFMessage{Text: message},
+	})
+}
+
+// Close completes the SARIF log. No results can be written afterwards.
+func (s *SARIFWriter) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.writeHeader()
+	invocations, err := json.Marshal([]SARIFInvocation{{
+		ExecutionSuccessful:        s.successful,
+		ToolExecutionNotifications: s.notifications,
+	}})
+	if err != nil {
+		return err
+	}
+	s.write([]byte(`],"invocations":`))
+	s.write(invocations)
+	s.write([]byte("}]}\n"))
+	return s.err
+}
+
+func (s *SARIFWriter) writeHeader() {
+	if s.wroteHeader {
+		return
+	}
+	s.wroteHeader = true
+
+	tool, err := json.Marshal(s.tool)
+	if err != nil {
+		s.err = errors.Wrap(err, "marshal SARIF tool")
+		return
+	}
+	s.write([]byte(`{"$schema":"` + SARIFSchema + `","version":"` + SARIFVersion + `","runs":[{"tool":`))
+	s.write(tool)
+	s.write([]byte(`,"columnKind":"unicodeCodePoints","results":[`))
+}
+
+// write is a helper to avoid error handling. Once a write failed, all further
+// writes are skipped.
+func (s *SARIFWriter) write(b []byte) {
+	if s.err != nil {
+		return
+	}
+	_, s.err = s.w.Write(b)
+}
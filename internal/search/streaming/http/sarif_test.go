@@ -0,0 +1,132 @@
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/search/result"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+)
+
+func TestSARIFResultsFromFileMatch(t *testing.T) {
+	t.Parallel()
+
+	file := result.File{
+		Repo:     types.MinimalRepo{Name: "github.com/sourcegraph/sourcegraph"},
+		CommitID: api.CommitID("deadbeef"),
+		Path:     "cmd/main.go",
+	}
+
+	t.Run("chunk matches", func(t *testing.T) {
+		fm := &result.FileMatch{
+			File: file,
+			ChunkMatches: result.ChunkMatches{{
+				Content:      "foo := bar()\nreturn foo",
+				ContentStart: result.Location{Offset: 100, Line: 9, Column: 0},
+				Ranges: result.Ranges{{
+					Start: result.Location{Offset: 107, Line: 9, Column: 7},
+					End:   result.Location{Offset: 110, Line: 9, Column: 10},
+				}, {
+					Start: result.Location{Offset: 120, Line: 10, Column: 7},
+					End:   result.Location{Offset: 123, Line: 10, Column: 10},
+				}},
+			}},
+		}
+
+		results := SARIFResultsFromFileMatch(fm, "my-rule")
+		require.Len(t, results, 2)
+
+		require.Equal(t, "my-rule", results[0].RuleID)
+		loc := results[0].Locations[0].PhysicalLocation
+		require.Equal(t, "cmd/main.go", loc.ArtifactLocation.URI)
+		require.Equal(t, &SARIFRegion{
+			StartLine:   10,
+			StartColumn: 8,
+			EndLine:     10,
+			EndColumn:   11,
+			Snippet:     &SARIFArtifactContent{Text: "bar"},
+		}, loc.Region)
+		require.Equal(t, 10, loc.ContextRegion.StartLine)
+		require.Equal(t, 11, loc.ContextRegion.EndLine)
+		require.Equal(t, "github.com/sourcegraph/sourcegraph", results[0].Properties["repository"])
+		require.Equal(t, "deadbeef", results[0].Properties["commit"])
+
+		require.Equal(t, 11, results[1].Locations[0].PhysicalLocation.Region.StartLine)
+		require.Equal(t, "foo", results[1].Locations[0].PhysicalLocation.Region.Snippet.Text)
+	})
+
+	t.Run("symbol matches", func(t *testing.T) {
+		fm := &result.FileMatch{
+			File: file,
+			Symbols: []*result.SymbolMatch{{
+				Symbol: result.Symbol{Name: "main", Line: 3, Character: 5},
+			}},
+		}
+
+		results := SARIFResultsFromFileMatch(fm, "my-rule")
+		require.Len(t, results, 1)
+		require.Equal(t, &SARIFRegion{
+			StartLine:   3,
+			StartColumn: 6,
+			EndLine:     3,
+			EndColumn:   10,
+		}, results[0].Locations[0].PhysicalLocation.Region)
+	})
+
+	t.Run("path matches", func(t *testing.T) {
+		results := SARIFResultsFromFileMatch(&result.FileMatch{File: file}, "my-rule")
+		require.Len(t, results, 1)
+		require.Nil(t, results[0].Locations[0].PhysicalLocation.Region)
+	})
+}
+
+func TestSARIFWriter(t *testing.T) {
+	t.Parallel()
+
+	decode := func(t *testing.T, b []byte) SARIFLog {
+		t.Helper()
+		var log SARIFLog
+		require.NoError(t, json.Unmarshal(b, &log))
+		require.Equal(t, SARIFVersion, log.Version)
+		require.Len(t, log.Runs, 1)
+		return log
+	}
+
+	rule := SARIFRule{ID: "my-rule", ShortDescription: &SARIFMessage{Text: "repo:foo bar"}}
+
+	t.Run("empty", func(t *testing.T) {
+		var buf bytes.Buffer
+		w := NewSARIFWriter(&buf, rule)
+		require.NoError(t, w.Close())
+
+		log := decode(t, buf.Bytes())
+		require.Empty(t, log.Runs[0].Results)
+		require.Equal(t, []SARIFRule{rule}, log.Runs[0].Tool.Driver.Rules)
+		require.True(t, log.Runs[0].Invocations[0].ExecutionSuccessful)
+	})
+
+	t.Run("results and notifications", func(t *testing.T) {
+		var buf bytes.Buffer
+		w := NewSARIFWriter(&buf, rule)
+		require.NoError(t, w.Write(SARIFResult{RuleID: "my-rule", Message: SARIFMessage{Text: "a"}}))
+		require.NoError(t, w.Write(
+			SARIFResult{RuleID: "my-rule", Message: SARIFMessage{Text: "b"}},
+			SARIFResult{RuleID: "my-rule", Message: SARIFMessage{Text: "c"}},
+		))
+		w.Notify("warning", "timed out")
+		w.Notify("error", "boom")
+		require.NoError(t, w.Close())
+
+		log := decode(t, buf.Bytes())
+		require.Len(t, log.Runs[0].Results, 3)
+		require.Equal(t, "c", log.Runs[0].Results[2].Message.Text)
+
+		invocation := log.Runs[0].Invocations[0]
+		require.False(t, invocation.ExecutionSuccessful)
+		require.Len(t, invocation.ToolExecutionNotifications, 2)
+	})
+}
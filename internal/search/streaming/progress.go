@@ -33,6 +33,14 @@ type Stats struct {
 	// ExcludedArchived is the count of excluded archived repos because the
 	// search query doesn't apply to them, but that we want to know about.
 	ExcludedArchived int
+
+	// RepoPages are the pages of repositories the repository pager started
+	// to search, in order. They are used to compute the cursor at which a
+	// search that hit its limit can be resumed.
+	RepoPages []search.RepoPage
+
+	// ShardsScanned is the number of Zoekt index shards that were searched.
+	ShardsScanned int
 }
 
 // Update updates c with the other data, deduping as necessary. It modifies c but
@@ -58,6 +66,8 @@ func (c *Stats) Update(other *Stats) {
 	c.BackendsMissing += other.BackendsMissing
 	c.ExcludedForks += other.ExcludedForks
 	c.ExcludedArchived += other.ExcludedArchived
+	c.RepoPages = append(c.RepoPages, other.RepoPages...)
+	c.ShardsScanned += other.ShardsScanned
 }
 
 // Zero returns true if stats is empty. IE calling Update will result in no
@@ -72,7 +82,9 @@ func (c *Stats) Zero() bool {
 		c.Status.Len() > 0 ||
 		c.BackendsMissing > 0 ||
 		c.ExcludedForks > 0 ||
-		c.ExcludedArchived > 0)
+		c.ExcludedArchived > 0 ||
+		len(c.RepoPages) > 0 ||
+		c.ShardsScanned > 0)
 }
 
 func (c *Stats) String() string {
@@ -91,6 +103,8 @@ func (c *Stats) String() string {
 		{"backendsMissing", c.BackendsMissing},
 		{"excludedForks", c.ExcludedForks},
 		{"excludedArchived", c.ExcludedArchived},
+		{"repoPages", len(c.RepoPages)},
+		{"shardsScanned", c.ShardsScanned},
 	}
 	for _, p := range nums {
 		if p.n != 0 {
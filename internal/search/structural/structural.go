@@ -207,6 +207,10 @@ func (*SearchJob) Name() string {
 	return "StructuralSearchJob"
 }
 
+func (*SearchJob) Backend() string {
+	return job.BackendSearcher
+}
+
 func (s *SearchJob) Attributes(v job.Verbosity) (res []attribute.KeyValue) {
 	switch v {
 	case job.VerbosityMax:
@@ -38,6 +38,7 @@ type Inputs struct {
 	Features               *Features
 	Protocol               Protocol
 	SanitizeSearchPatterns []*regexp.Regexp
+	Cursor                 *Cursor // optional position to resume a search which hit its limit
 }
 
 // MaxResults computes the limit for the query.
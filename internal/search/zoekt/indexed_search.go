@@ -363,6 +363,7 @@ func sendMatches(event *zoekt.SearchResult, pathRegexps []*regexp.Regexp, getRep
 		// practice is when a backend is missing.
 		BackendsMissing: event.Crashes,
 		IsLimitHit:      event.FilesSkipped+event.ShardsSkipped > 0,
+		ShardsScanned:   event.ShardsScanned,
 	}
 
 	if selector.Root() == filter.Repository {
@@ -676,6 +677,10 @@ func (*RepoSubsetTextSearchJob) Name() string {
 	return "ZoektRepoSubsetTextSearchJob"
 }
 
+func (*RepoSubsetTextSearchJob) Backend() string {
+	return job.BackendZoekt
+}
+
 func (z *RepoSubsetTextSearchJob) Attributes(v job.Verbosity) (res []attribute.KeyValue) {
 	switch v {
 	case job.VerbosityMax:
@@ -726,6 +731,10 @@ func (*GlobalTextSearchJob) Name() string {
 	return "ZoektGlobalTextSearchJob"
 }
 
+func (*GlobalTextSearchJob) Backend() string {
+	return job.BackendZoekt
+}
+
 func (t *GlobalTextSearchJob) Attributes(v job.Verbosity) (res []attribute.KeyValue) {
 	switch v {
 	case job.VerbosityMax:
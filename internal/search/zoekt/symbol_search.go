@@ -56,6 +56,10 @@ func (z *SymbolSearchJob) Name() string {
 	return "ZoektSymbolSearchJob"
 }
 
+func (z *SymbolSearchJob) Backend() string {
+	return job.BackendZoekt
+}
+
 func (z *SymbolSearchJob) Attributes(v job.Verbosity) (res []attribute.KeyValue) {
 	switch v {
 	case job.VerbosityMax:
@@ -113,6 +117,10 @@ func (*GlobalSymbolSearchJob) Name() string {
 	return "ZoektGlobalSymbolSearchJob"
 }
 
+func (*GlobalSymbolSearchJob) Backend() string {
+	return job.BackendZoekt
+}
+
 func (s *GlobalSymbolSearchJob) Attributes(v job.Verbosity) (res []attribute.KeyValue) {
 	switch v {
 	case job.VerbosityMax:
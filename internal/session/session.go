@@ -67,8 +67,20 @@ type sessionInfo struct {
 	LastActive    time.Time     `json:"lastActive"`
 	ExpiryPeriod  time.Duration `json:"expiryPeriod"`
 	UserCreatedAt time.Time     `json:"userCreatedAt"`
+
+	// SecondFactorPending is set after the user entered a correct password but
+	// has not verified their second factor yet. The session does not
+	// authenticate the user while it is set.
+	SecondFactorPending bool `json:"secondFactorPending,omitempty"`
+	// SecondFactorVerifiedAt is when the user last verified a second factor in
+	// this session.
+	SecondFactorVerifiedAt time.Time `json:"secondFactorVerifiedAt"`
 }
 
+// secondFactorPendingExpiry is how long users have to verify their second factor
+// after entering their password.
+const secondFactorPendingExpiry = 10 * time.Minute
+
 // SetSessionStore sets the backing store used for storing sessions on the server. It should be called exactly once.
 func SetSessionStore(s sessions.Store) {
 	sessionStore = s
@@ -249,11 +261,7 @@ func SetActor(w http.ResponseWriter, r *http.Request, actor *actor.Actor, expiry
 	var value *sessionInfo
 	if actor != nil {
 		if expiryPeriod == 0 {
-			if cfgExpiry, err := time.ParseDuration(conf.Get().AuthSessionExpiry); err == nil {
-				expiryPeriod = cfgExpiry
-			} else { // if there is no valid session duration, fall back to the default one
-				expiryPeriod = defaultExpiryPeriod
-			}
+			expiryPeriod = configuredExpiryPeriod()
 		}
 		RemoveSignOutCookieIfSet(r, w)
 
@@ -262,6 +270,74 @@ func SetActor(w http.ResponseWriter, r *http.Request, actor *actor.Actor, expiry
 	return SetData(w, r, "actor", value)
 }
 
+func configuredExpiryPeriod() time.Duration {
+	if cfgExpiry, err := time.ParseDuration(conf.Get().AuthSessionExpiry); err == nil {
+		return cfgExpiry
+	}
+	// if there is no valid session duration, fall back to the default one
+	return defaultExpiryPeriod
+}
+
+// SetActorPendingSecondFactor sets the actor in the session like SetActor, but
+// marks that the user still has to verify a second factor. The session does not
+// authenticate the user until CompleteSecondFactor is called, and expires after
+// 10 minutes.
+func SetActorPendingSecondFactor(w http.ResponseWriter, r *http.Request, actor *actor.Actor, userCreatedAt time.Time) error {
+	RemoveSignOutCookieIfSet(r, w)
+	return SetData(w, r, "actor", &sessionInfo{
+		Actor:               actor,
+		ExpiryPeriod:        secondFactorPendingExpiry,
+		LastActive:          time.Now(),
+		UserCreatedAt:       userCreatedAt,
+		SecondFactorPending: true,
+	})
+}
+
+// PendingSecondFactorActor returns the actor of the session if it is waiting
+// for the user to verify a second factor, or nil otherwise.
+func PendingSecondFactorActor(r *http.Request) *actor.Actor {
+	var info *sessionInfo
+	if err := GetData(r, "actor", &info); err != nil || info == nil || !info.SecondFactorPending {
+		return nil
+	}
+	if info.LastActive.Add(info.ExpiryPeriod).Before(time.Now()) {
+		return nil
+	}
+	return info.Actor
+}
+
+// CompleteSecondFactor records that the user verified a second factor in the
+// session. A session set by SetActorPendingSecondFactor now authenticates the
+// user, with the configured expiry period.
+func CompleteSecondFactor(w http.ResponseWriter, r *http.Request) error {
+	var info *sessionInfo
+	if err := GetData(r, "actor", &info); err != nil {
+		return err
+	}
+	if info == nil {
+		return errors.New("no session to complete the second factor of")
+	}
+
+	if info.SecondFactorPending {
+		info.SecondFactorPending = false
+		info.ExpiryPeriod = configuredExpiryPeriod()
+	}
+	info.LastActive = time.Now()
+	info.SecondFactorVerifiedAt = info.LastActive
+	return SetData(w, r, "actor", info)
+}
+
+// SecondFactorVerifiedWithin reports whether the user verified a second factor
+// in the current session within the given duration. It is used to require a
+// recent verification before sensitive changes to the second factors.
+func SecondFactorVerifiedWithin(r *http.Request, d time.Duration) bool {
+	var info *sessionInfo
+	if err := GetData(r, "actor", &info); err != nil || info == nil || info.SecondFactorPending {
+		return false
+	}
+	return time.Since(info.SecondFactorVerifiedAt) <= d
+}
+
 // RemoveSignOutCookieIfSet removes the sign-out cookie if it is set.
 func RemoveSignOutCookieIfSet(r *http.Request, w http.ResponseWriter) {
 	if HasSignOutCookie(r) {
@@ -425,6 +501,13 @@ func authenticateByCookie(logger log.Logger, db database.DB, r *http.Request, w
 			return actor.WithActor(ctx, &actor.Actor{})
 		}
 
+		// The user has not verified their second factor yet. The session is
+		// kept so that they can do so.
+		if info.SecondFactorPending {
+			span.SetAttributes(attribute.Bool("secondFactorPending", true))
+			return ctx // not authenticated
+		}
+
 		// Check that user still exists.
 		usr, err := db.Users().GetByID(ctx, info.Actor.UID)
 		if err != nil {
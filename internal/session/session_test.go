@@ -13,11 +13,13 @@ import (
 	"github.com/sourcegraph/log/logtest"
 
 	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
 	"github.com/sourcegraph/sourcegraph/internal/database"
 	"github.com/sourcegraph/sourcegraph/internal/database/dbmocks"
 	"github.com/sourcegraph/sourcegraph/internal/errcode"
 	"github.com/sourcegraph/sourcegraph/internal/types"
 	"github.com/sourcegraph/sourcegraph/lib/errors"
+	"github.com/sourcegraph/sourcegraph/schema"
 )
 
 func TestSetActorDeleteSession(t *testing.T) {
@@ -475,3 +477,67 @@ func TestOldUserSessionSucceeds(t *testing.T) {
 		t.Fatal("user creation date was not set")
 	}
 }
+
+func TestSecondFactorPending(t *testing.T) {
+	logger := logtest.Scoped(t)
+
+	cleanup := ResetMockSessionStore(t)
+	defer cleanup()
+
+	conf.Mock(&conf.Unified{SiteConfiguration: schema.SiteConfiguration{AuthSessionExpiry: "1h"}})
+	defer conf.Mock(nil)
+
+	userCreatedAt := time.Now()
+
+	users := dbmocks.NewStrictMockUserStore()
+	users.GetByIDFunc.SetDefaultHook(func(ctx context.Context, id int32) (*types.User, error) {
+		return &types.User{ID: id, CreatedAt: userCreatedAt}, nil
+	})
+
+	db := dbmocks.NewStrictMockDB()
+	db.UsersFunc.SetDefaultReturn(users)
+
+	// Start new session pending the second factor
+	w := httptest.NewRecorder()
+	actr := &actor.Actor{UID: 123, FromSessionCookie: true}
+	if err := SetActorPendingSecondFactor(w, httptest.NewRequest("GET", "/", nil), actr, userCreatedAt); err != nil {
+		t.Fatal(err)
+	}
+
+	authedReq := httptest.NewRequest("GET", "/", nil)
+	for _, cookie := range w.Result().Cookies() {
+		authedReq.AddCookie(cookie)
+	}
+
+	if gotActor := actor.FromContext(authenticateByCookie(logger, db, authedReq, httptest.NewRecorder())); gotActor.IsAuthenticated() {
+		t.Errorf("pending session should not authenticate, got actor %+v", gotActor)
+	}
+	if gotActor := PendingSecondFactorActor(authedReq); gotActor == nil || gotActor.UID != actr.UID {
+		t.Errorf("expected pending actor %+v, got %+v", actr, gotActor)
+	}
+	if SecondFactorVerifiedWithin(authedReq, time.Minute) {
+		t.Error("second factor should not be verified")
+	}
+
+	if err := CompleteSecondFactor(httptest.NewRecorder(), authedReq); err != nil {
+		t.Fatal(err)
+	}
+
+	if gotActor := actor.FromContext(authenticateByCookie(logger, db, authedReq, httptest.NewRecorder())); !reflect.DeepEqual(gotActor, actr) {
+		t.Errorf("didn't find actor %v != %v", gotActor, actr)
+	}
+	if gotActor := PendingSecondFactorActor(authedReq); gotActor != nil {
+		t.Errorf("expected no pending actor, got %+v", gotActor)
+	}
+	if !SecondFactorVerifiedWithin(authedReq, time.Minute) {
+		t.Error("second factor should be verified")
+	}
+
+	var info *sessionInfo
+	if err := GetData(authedReq, "actor", &info); err != nil {
+		t.Fatal(err)
+	}
+	if info.ExpiryPeriod != time.Hour {
+		t.Errorf("expected the configured expiry period after completing the second factor, got %s", info.ExpiryPeriod)
+	}
+}
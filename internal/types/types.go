@@ -918,6 +918,27 @@ type UserRole struct {
 	CreatedAt time.Time
 }
 
+// RoleScope restricts a role assigned to a user to the resources within the scope.
+// Exactly one of its fields is set.
+type RoleScope struct {
+	// OrgID scopes the role to an organization, the resources in its namespace, and
+	// the repositories of the search contexts it owns.
+	OrgID int32
+	// SearchContextID scopes the role to the repositories of a search context.
+	SearchContextID int64
+	// RepoID scopes the role to a single repository.
+	RepoID api.RepoID
+}
+
+// ScopedUserRole is a role assigned to a user for the resources within a scope only.
+type ScopedUserRole struct {
+	ID        int32
+	RoleID    int32
+	UserID    int32
+	Scope     RoleScope
+	CreatedAt time.Time
+}
+
 type NamespacePermission struct {
 	ID         int64
 	Namespace  rtypes.PermissionNamespace
@@ -1950,6 +1971,12 @@ type SearchContext struct {
 	// e.g. repo:^github\.com/org rev:bar archive:no f:sub/dir
 	Query string
 
+	// ReposMaterializedAt is when the repositories matched by Query were last
+	// materialized in the database by a background job. It is zero if they have
+	// not been materialized for the current Query, in which case Query is
+	// evaluated at search time instead.
+	ReposMaterializedAt time.Time
+
 	// Whether the search context is auto-defined by Sourcegraph. Auto-defined search contexts are not editable by users.
 	AutoDefined bool
 
@@ -27,6 +27,11 @@ type uploadRequestOptions struct {
 	Done             bool      // Whether the request is a multipart finalize
 }
 
+// multipartSetupPayload is the body of the request preparing a multipart upload of a delta index.
+type multipartSetupPayload struct {
+	DeletedPaths []string `json:"deletedPaths"`
+}
+
 // ErrUnauthorized occurs when the upload endpoint returns a 401 response.
 var ErrUnauthorized = errors.New("unauthorized upload")
 
@@ -168,6 +173,9 @@ func makeUploadURL(opts uploadRequestOptions) (*url.URL, error) {
 	if opts.UploadRecordOptions.AssociatedIndexID != nil {
 		qs.Add("associatedIndexId", formatInt(*opts.UploadRecordOptions.AssociatedIndexID))
 	}
+	if opts.UploadRecordOptions.BaseUploadID != nil {
+		qs.Add("baseUploadId", formatInt(*opts.UploadRecordOptions.BaseUploadID))
+	}
 	if opts.MultiPart {
 		qs.Add("multiPart", "true")
 	}
@@ -1,7 +1,9 @@
 package upload
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
 	"os"
@@ -64,7 +66,9 @@ func UploadIndex(ctx context.Context, filename string, httpClient Client, opts U
 		))
 	}
 
-	if compressedSize <= opts.MaxPayloadSizeBytes {
+	// Delta uploads are always split into multiple requests, as the paths deleted since
+	// the base upload are sent in the body of the initial request
+	if compressedSize <= opts.MaxPayloadSizeBytes && opts.UploadRecordOptions.BaseUploadID == nil {
 		return uploadIndex(ctx, httpClient, opts, compressedReader, compressedSize, originalSize)
 	}
 
@@ -178,18 +182,30 @@ func uploadMultipartIndexInit(ctx context.Context, httpClient Client, opts Uploa
 	)
 	defer func() { complete(err) }()
 
+	var payload []byte
+	if opts.UploadRecordOptions.BaseUploadID != nil {
+		if payload, err = json.Marshal(multipartSetupPayload{DeletedPaths: opts.UploadRecordOptions.DeletedPaths}); err != nil {
+			return 0, err
+		}
+	}
+
 	err = makeRetry(opts.MaxRetries, opts.RetryInterval)(func(attempt int) (bool, error) {
 		if attempt != 0 {
 			retry(fmt.Sprintf("Failed to prepare multipart upload (will retry; attempt #%d)", attempt))
 		}
 
-		return performUploadRequest(ctx, httpClient, uploadRequestOptions{
+		requestOptions := uploadRequestOptions{
 			UploadOptions:    opts,
 			Target:           &id,
 			MultiPart:        true,
 			NumParts:         numParts,
 			UncompressedSize: uncompressedSize,
-		})
+		}
+		if payload != nil {
+			requestOptions.Payload = bytes.NewReader(payload)
+		}
+
+		return performUploadRequest(ctx, httpClient, requestOptions)
 	})
 
 	return id, err
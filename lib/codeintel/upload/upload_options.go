@@ -44,4 +44,10 @@ type UploadRecordOptions struct {
 	Indexer           string
 	IndexerVersion    string
 	AssociatedIndexID *int
+
+	// BaseUploadID marks the index as a delta of the given (completed) upload. A delta
+	// index contains only the documents which changed since the base upload; all other
+	// documents of the base upload are retained except those listed in DeletedPaths.
+	BaseUploadID *int
+	DeletedPaths []string // Paths relative to the upload root (only used for delta uploads)
 }
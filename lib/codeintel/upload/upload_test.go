@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"compress/gzip"
 	"context"
+	"encoding/json"
 	"io"
 	"net/http"
 	"net/http/httptest"
@@ -85,6 +86,72 @@ func TestUploadIndex(t *testing.T) {
 	}
 }
 
+func TestUploadIndexDelta(t *testing.T) {
+	var numParts int
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Delta uploads are always sent as multipart uploads
+		if r.URL.Query().Get("multiPart") != "" {
+			if baseUploadID := r.URL.Query().Get("baseUploadId"); baseUploadID != "41" {
+				t.Errorf("unexpected baseUploadId. want=%q have=%q", "41", baseUploadID)
+			}
+
+			var payload struct {
+				DeletedPaths []string `json:"deletedPaths"`
+			}
+			if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+				t.Fatalf("unexpected error decoding request body: %s", err)
+			}
+			if diff := cmp.Diff([]string{"a.go", "b/c.go"}, payload.DeletedPaths); diff != "" {
+				t.Errorf("unexpected deleted paths (-want +got):\n%s", diff)
+			}
+
+			w.WriteHeader(http.StatusOK)
+			_, _ = w.Write([]byte(`{"id":"42"}`))
+			return
+		}
+
+		if r.URL.Query().Get("index") != "" {
+			numParts++
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer ts.Close()
+
+	f, err := os.CreateTemp("", "")
+	if err != nil {
+		t.Fatalf("unexpected error creating temp file: %s", err)
+	}
+	defer func() { os.Remove(f.Name()) }()
+	_, _ = f.Write([]byte("payload"))
+	_ = f.Close()
+
+	baseUploadID := 41
+	id, err := UploadIndex(context.Background(), f.Name(), http.DefaultClient, UploadOptions{
+		UploadRecordOptions: UploadRecordOptions{
+			Repo:         "foo/bar",
+			Commit:       "deadbeef",
+			Indexer:      "scip-go",
+			BaseUploadID: &baseUploadID,
+			DeletedPaths: []string{"a.go", "b/c.go"},
+		},
+		SourcegraphInstanceOptions: SourcegraphInstanceOptions{
+			SourcegraphURL:      ts.URL,
+			MaxPayloadSizeBytes: 1000,
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error uploading index: %s", err)
+	}
+
+	if id != 42 {
+		t.Errorf("unexpected id. want=%d have=%d", 42, id)
+	}
+	if numParts != 1 {
+		t.Errorf("unexpected number of parts. want=%d have=%d", 1, numParts)
+	}
+}
+
 func TestUploadIndexMultipart(t *testing.T) {
 	var expectedPayload []byte
 	for i := 0; i < 20000; i++ {
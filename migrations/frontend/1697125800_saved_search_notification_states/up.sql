@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS saved_search_notification_states (
+    saved_search_id integer NOT NULL REFERENCES saved_searches(id) ON DELETE CASCADE,
+    user_id integer REFERENCES users(id) ON DELETE CASCADE,
+    result_count integer NOT NULL DEFAULT 0,
+    fingerprint bigint[] NOT NULL DEFAULT '{}'::bigint[],
+    last_run_at timestamp with time zone NOT NULL DEFAULT now()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS saved_search_notification_states_saved_search_id_user_id ON saved_search_notification_states(saved_search_id, COALESCE(user_id, 0));
+
+COMMENT ON TABLE saved_search_notification_states IS 'Stores the result of the last execution of a saved search with notifications enabled, so that subsequent executions can detect changes.';
+COMMENT ON COLUMN saved_search_notification_states.user_id IS 'The user the saved search was executed as. NULL for the Slack notifications of organization saved searches, which only consider the results visible to every member.';
+COMMENT ON COLUMN saved_search_notification_states.fingerprint IS 'Sorted hashes of the result keys seen by the last execution, capped to a maximum size.';
@@ -0,0 +1,14 @@
+DROP TRIGGER IF EXISTS trig_search_context_repo_kvps_updates ON repo_kvps;
+DROP TRIGGER IF EXISTS trig_search_context_repo_kvps_changes ON repo_kvps;
+DROP TRIGGER IF EXISTS trig_search_context_repo_updates ON repo;
+DROP TRIGGER IF EXISTS trig_search_context_repo_changes ON repo;
+DROP FUNCTION IF EXISTS func_search_context_repo_changes();
+DROP SEQUENCE IF EXISTS search_context_repo_changes_seq;
+
+ALTER TABLE search_contexts DROP COLUMN IF EXISTS repos_materialization_failure_message;
+ALTER TABLE search_contexts DROP COLUMN IF EXISTS repos_materialization_retry_after;
+ALTER TABLE search_contexts DROP COLUMN IF EXISTS repos_materialization_failures;
+ALTER TABLE search_contexts DROP COLUMN IF EXISTS repos_materialized_change_id;
+ALTER TABLE search_contexts DROP COLUMN IF EXISTS repos_materialized_at;
+
+DROP TABLE IF EXISTS search_context_materialized_repos;
@@ -0,0 +1,66 @@
+CREATE TABLE IF NOT EXISTS search_context_materialized_repos (
+    search_context_id bigint NOT NULL REFERENCES search_contexts(id) ON DELETE CASCADE,
+    repo_id integer NOT NULL REFERENCES repo(id) ON DELETE CASCADE,
+    revision text NOT NULL,
+    PRIMARY KEY (search_context_id, repo_id, revision)
+);
+
+CREATE INDEX IF NOT EXISTS search_context_materialized_repos_repo_id_idx ON search_context_materialized_repos(repo_id);
+
+COMMENT ON TABLE search_context_materialized_repos IS 'The repositories and revisions matched by the query of a query-based search context, materialized by a background job.';
+
+ALTER TABLE search_contexts ADD COLUMN IF NOT EXISTS repos_materialized_at timestamp with time zone;
+ALTER TABLE search_contexts ADD COLUMN IF NOT EXISTS repos_materialized_change_id bigint;
+ALTER TABLE search_contexts ADD COLUMN IF NOT EXISTS repos_materialization_failures integer NOT NULL DEFAULT 0;
+ALTER TABLE search_contexts ADD COLUMN IF NOT EXISTS repos_materialization_retry_after timestamp with time zone;
+ALTER TABLE search_contexts ADD COLUMN IF NOT EXISTS repos_materialization_failure_message text;
+
+COMMENT ON COLUMN search_contexts.repos_materialized_at IS 'When the repositories matched by the query were last written to search_context_materialized_repos. NULL if they have not been materialized for the current query.';
+COMMENT ON COLUMN search_contexts.repos_materialized_change_id IS 'The value of search_context_repo_changes_seq observed before the repositories were last materialized.';
+COMMENT ON COLUMN search_contexts.repos_materialization_failures IS 'The number of consecutive failures to materialize the repositories matched by the query.';
+COMMENT ON COLUMN search_contexts.repos_materialization_retry_after IS 'The time before which the materialization of the repositories is not retried after a failure.';
+COMMENT ON COLUMN search_contexts.repos_materialization_failure_message IS 'The error of the last failure to materialize the repositories matched by the query.';
+
+-- search_context_repo_changes_seq is advanced whenever repositories are
+-- created, deleted or renamed, their visibility changes, or their key-value
+-- pairs change. We use a sequence rather than updating search_contexts
+-- directly so that writes to the repo table never contend on row locks.
+--
+-- Only the attributes commonly used by search context queries are
+-- considered, and the no-op updates of the repository syncer are ignored.
+-- Changes to other attributes are picked up when the materialized
+-- repositories are refreshed periodically.
+CREATE SEQUENCE IF NOT EXISTS search_context_repo_changes_seq;
+
+CREATE OR REPLACE FUNCTION func_search_context_repo_changes() RETURNS trigger
+    LANGUAGE plpgsql
+    AS $$
+    BEGIN
+        PERFORM nextval('search_context_repo_changes_seq');
+        RETURN NULL;
+    END;
+$$;
+
+DROP TRIGGER IF EXISTS trig_search_context_repo_changes ON repo;
+CREATE TRIGGER trig_search_context_repo_changes
+AFTER INSERT OR DELETE ON repo
+FOR EACH ROW EXECUTE FUNCTION func_search_context_repo_changes();
+
+DROP TRIGGER IF EXISTS trig_search_context_repo_updates ON repo;
+CREATE TRIGGER trig_search_context_repo_updates
+AFTER UPDATE OF name, private ON repo
+FOR EACH ROW
+WHEN (OLD.name IS DISTINCT FROM NEW.name OR OLD.private IS DISTINCT FROM NEW.private)
+EXECUTE FUNCTION func_search_context_repo_changes();
+
+DROP TRIGGER IF EXISTS trig_search_context_repo_kvps_changes ON repo_kvps;
+CREATE TRIGGER trig_search_context_repo_kvps_changes
+AFTER INSERT OR DELETE ON repo_kvps
+FOR EACH ROW EXECUTE FUNCTION func_search_context_repo_changes();
+
+DROP TRIGGER IF EXISTS trig_search_context_repo_kvps_updates ON repo_kvps;
+CREATE TRIGGER trig_search_context_repo_kvps_updates
+AFTER UPDATE ON repo_kvps
+FOR EACH ROW
+WHEN (OLD.* IS DISTINCT FROM NEW.*)
+EXECUTE FUNCTION func_search_context_repo_changes();
@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS codeintel_upload_deltas (
+    upload_id integer NOT NULL PRIMARY KEY REFERENCES lsif_uploads(id) ON DELETE CASCADE,
+    base_upload_id integer NOT NULL REFERENCES lsif_uploads(id) ON DELETE CASCADE,
+    deleted_paths text[] NOT NULL DEFAULT '{}'::text[]
+);
+
+COMMENT ON TABLE codeintel_upload_deltas IS 'Stores the base of delta uploads, which contain only the documents that changed since the base upload.';
+COMMENT ON COLUMN codeintel_upload_deltas.base_upload_id IS 'The upload providing the documents which are not part of the delta upload. Unchanged documents are copied from this upload when the delta upload is processed.';
+COMMENT ON COLUMN codeintel_upload_deltas.deleted_paths IS 'The paths (relative to the upload root) of documents in the base upload which no longer exist.';
@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS vulnerability_match_call_sites;
+
+ALTER TABLE vulnerability_matches DROP COLUMN IF EXISTS call_sites_retry_after;
+ALTER TABLE vulnerability_matches DROP COLUMN IF EXISTS call_sites_failures;
+ALTER TABLE vulnerability_matches DROP COLUMN IF EXISTS reachable;
@@ -0,0 +1,24 @@
+ALTER TABLE vulnerability_matches ADD COLUMN IF NOT EXISTS reachable boolean;
+ALTER TABLE vulnerability_matches ADD COLUMN IF NOT EXISTS call_sites_failures integer NOT NULL DEFAULT 0;
+ALTER TABLE vulnerability_matches ADD COLUMN IF NOT EXISTS call_sites_retry_after timestamp with time zone;
+
+COMMENT ON COLUMN vulnerability_matches.reachable IS 'Whether the index references one of the symbols affected by the vulnerability. Null if call sites have not yet been resolved for this match.';
+COMMENT ON COLUMN vulnerability_matches.call_sites_failures IS 'The number of consecutive failures to resolve the call sites of this match.';
+COMMENT ON COLUMN vulnerability_matches.call_sites_retry_after IS 'The time before which the resolution of the call sites is not retried after a failure.';
+
+CREATE TABLE IF NOT EXISTS vulnerability_match_call_sites (
+    id SERIAL PRIMARY KEY,
+    vulnerability_match_id integer NOT NULL REFERENCES vulnerability_matches(id) ON DELETE CASCADE,
+    symbol text NOT NULL,
+    path text NOT NULL,
+    start_line integer NOT NULL,
+    start_character integer NOT NULL,
+    end_line integer NOT NULL,
+    end_character integer NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS vulnerability_match_call_sites_vulnerability_match_id ON vulnerability_match_call_sites(vulnerability_match_id);
+
+COMMENT ON TABLE vulnerability_match_call_sites IS 'Stores the locations within an index that reference a symbol affected by the matched vulnerability.';
+COMMENT ON COLUMN vulnerability_match_call_sites.symbol IS 'The SCIP symbol name of the referenced affected symbol.';
+COMMENT ON COLUMN vulnerability_match_call_sites.path IS 'The path of the referencing document, relative to the index root.';
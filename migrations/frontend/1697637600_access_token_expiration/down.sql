@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS access_tokens_expires_at;
+
+ALTER TABLE access_tokens DROP COLUMN IF EXISTS revocation_notified_at;
+ALTER TABLE access_tokens DROP COLUMN IF EXISTS expiration_notified_at;
+ALTER TABLE access_tokens DROP COLUMN IF EXISTS expires_at;
@@ -0,0 +1,9 @@
+ALTER TABLE access_tokens ADD COLUMN IF NOT EXISTS expires_at timestamp with time zone;
+ALTER TABLE access_tokens ADD COLUMN IF NOT EXISTS expiration_notified_at timestamp with time zone;
+ALTER TABLE access_tokens ADD COLUMN IF NOT EXISTS revocation_notified_at timestamp with time zone;
+
+CREATE INDEX IF NOT EXISTS access_tokens_expires_at ON access_tokens(expires_at) WHERE deleted_at IS NULL AND expires_at IS NOT NULL;
+
+COMMENT ON COLUMN access_tokens.expires_at IS 'The time after which the access token is no longer valid. Null if the access token never expires.';
+COMMENT ON COLUMN access_tokens.expiration_notified_at IS 'The time at which the subject user was notified of the upcoming expiration of the access token.';
+COMMENT ON COLUMN access_tokens.revocation_notified_at IS 'The time at which the subject user was notified that the access token was revoked because it expired.';
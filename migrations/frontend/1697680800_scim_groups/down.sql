@@ -0,0 +1 @@
+DROP TABLE IF EXISTS scim_groups;
@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS scim_groups (
+    id SERIAL PRIMARY KEY,
+    org_id integer REFERENCES orgs(id) ON DELETE CASCADE,
+    team_id integer REFERENCES teams(id) ON DELETE CASCADE,
+    external_id text,
+    created_at timestamp with time zone NOT NULL DEFAULT now(),
+    CONSTRAINT scim_groups_org_or_team CHECK ((org_id IS NULL) <> (team_id IS NULL))
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS scim_groups_org_id ON scim_groups(org_id) WHERE org_id IS NOT NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS scim_groups_team_id ON scim_groups(team_id) WHERE team_id IS NOT NULL;
+
+COMMENT ON TABLE scim_groups IS 'The organizations and teams that were provisioned as groups by a SCIM client. The SCIM Groups resource only exposes these.';
+COMMENT ON COLUMN scim_groups.external_id IS 'The externalId of the group set by the SCIM client.';
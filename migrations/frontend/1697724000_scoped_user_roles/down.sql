@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS scoped_user_role_repos;
+DROP TABLE IF EXISTS scoped_user_roles;
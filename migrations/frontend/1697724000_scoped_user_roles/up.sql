@@ -0,0 +1,28 @@
+CREATE TABLE IF NOT EXISTS scoped_user_roles (
+    id SERIAL PRIMARY KEY,
+    user_id integer NOT NULL REFERENCES users(id) ON DELETE CASCADE DEFERRABLE,
+    role_id integer NOT NULL REFERENCES roles(id) ON DELETE CASCADE DEFERRABLE,
+    org_id integer REFERENCES orgs(id) ON DELETE CASCADE DEFERRABLE,
+    search_context_id bigint REFERENCES search_contexts(id) ON DELETE CASCADE DEFERRABLE,
+    repo_id integer REFERENCES repo(id) ON DELETE CASCADE DEFERRABLE,
+    created_at timestamp with time zone NOT NULL DEFAULT now(),
+    CONSTRAINT scoped_user_roles_has_one_scope CHECK (num_nonnulls(org_id, search_context_id, repo_id) = 1)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS scoped_user_roles_unique ON scoped_user_roles(user_id, role_id, COALESCE(org_id, 0), COALESCE(search_context_id, 0), COALESCE(repo_id, 0));
+CREATE INDEX IF NOT EXISTS scoped_user_roles_role_id ON scoped_user_roles(role_id);
+
+CREATE TABLE IF NOT EXISTS scoped_user_role_repos (
+    scoped_user_role_id integer NOT NULL REFERENCES scoped_user_roles(id) ON DELETE CASCADE DEFERRABLE,
+    repo_id integer NOT NULL REFERENCES repo(id) ON DELETE CASCADE DEFERRABLE,
+    PRIMARY KEY (scoped_user_role_id, repo_id)
+);
+
+CREATE INDEX IF NOT EXISTS scoped_user_role_repos_repo_id ON scoped_user_role_repos(repo_id);
+
+COMMENT ON TABLE scoped_user_roles IS 'Assigns a role to a user for the resources within a single scope only: an organization, the repositories of a search context, or a single repository.';
+COMMENT ON COLUMN scoped_user_roles.org_id IS 'Scopes the role to the organization and the resources in its namespace.';
+COMMENT ON COLUMN scoped_user_roles.search_context_id IS 'Scopes the role to the repositories the search context contained when the role was assigned, as recorded in scoped_user_role_repos.';
+COMMENT ON COLUMN scoped_user_roles.repo_id IS 'Scopes the role to the repository.';
+
+COMMENT ON TABLE scoped_user_role_repos IS 'The repositories of the search context of a scoped role, recorded when the role is assigned. Later changes to the search context do not change the scope of the role.';
@@ -0,0 +1,18 @@
+CREATE TABLE IF NOT EXISTS audit_log_outbox (
+    id BIGSERIAL PRIMARY KEY,
+    sink text NOT NULL,
+    "timestamp" timestamp with time zone NOT NULL,
+    action text NOT NULL,
+    payload jsonb NOT NULL,
+    created_at timestamp with time zone NOT NULL DEFAULT now(),
+    attempts integer NOT NULL DEFAULT 0,
+    last_error text,
+    delivered_at timestamp with time zone
+);
+
+CREATE INDEX IF NOT EXISTS audit_log_outbox_undelivered ON audit_log_outbox(sink, id) WHERE delivered_at IS NULL;
+CREATE INDEX IF NOT EXISTS audit_log_outbox_delivered_at ON audit_log_outbox(delivered_at) WHERE delivered_at IS NOT NULL;
+
+COMMENT ON TABLE audit_log_outbox IS 'Audit log entries waiting to be delivered to the sinks configured in log.auditLog.sinks. Each entry is queued once per sink.';
+COMMENT ON COLUMN audit_log_outbox.sink IS 'The key of the sink the entry is delivered to, which identifies its destination.';
+COMMENT ON COLUMN audit_log_outbox.delivered_at IS 'When the entry was delivered. Delivered entries are deleted after a retention period.';
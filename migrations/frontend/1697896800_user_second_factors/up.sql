@@ -0,0 +1,45 @@
+CREATE TABLE IF NOT EXISTS user_totp_credentials (
+    id SERIAL PRIMARY KEY,
+    user_id integer NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    secret bytea NOT NULL,
+    encryption_key_id text NOT NULL DEFAULT '',
+    last_used_step bigint NOT NULL DEFAULT 0,
+    confirmed_at timestamp with time zone,
+    created_at timestamp with time zone NOT NULL DEFAULT now()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS user_totp_credentials_user_id ON user_totp_credentials(user_id);
+
+COMMENT ON TABLE user_totp_credentials IS 'The TOTP (authenticator app) second factor of users signing in with the builtin auth provider.';
+COMMENT ON COLUMN user_totp_credentials.last_used_step IS 'The last accepted TOTP time step, so that a code cannot be used twice.';
+COMMENT ON COLUMN user_totp_credentials.confirmed_at IS 'When the user confirmed the enrollment with a valid code. Unconfirmed secrets are not used to sign in.';
+
+CREATE TABLE IF NOT EXISTS user_webauthn_credentials (
+    id SERIAL PRIMARY KEY,
+    user_id integer NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name text NOT NULL,
+    credential_id bytea NOT NULL,
+    public_key bytea NOT NULL,
+    sign_count bigint NOT NULL DEFAULT 0,
+    created_at timestamp with time zone NOT NULL DEFAULT now(),
+    last_used_at timestamp with time zone
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS user_webauthn_credentials_credential_id ON user_webauthn_credentials(credential_id);
+CREATE INDEX IF NOT EXISTS user_webauthn_credentials_user_id ON user_webauthn_credentials(user_id);
+
+COMMENT ON TABLE user_webauthn_credentials IS 'The WebAuthn security keys registered by users signing in with the builtin auth provider.';
+COMMENT ON COLUMN user_webauthn_credentials.public_key IS 'The COSE-encoded public key of the credential.';
+
+CREATE TABLE IF NOT EXISTS user_recovery_codes (
+    id SERIAL PRIMARY KEY,
+    user_id integer NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    code bytea NOT NULL,
+    encryption_key_id text NOT NULL DEFAULT '',
+    used_at timestamp with time zone,
+    created_at timestamp with time zone NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS user_recovery_codes_user_id ON user_recovery_codes(user_id);
+
+COMMENT ON TABLE user_recovery_codes IS 'Single-use codes that users can verify instead of a second factor they lost.';
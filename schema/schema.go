@@ -72,14 +72,61 @@ type AppNotifications struct {
 
 // AuditLog description: EXPERIMENTAL: Configuration for audit logging (specially formatted log entries for tracking sensitive events)
 type AuditLog struct {
+	// DataAccessSampleRate description: The fraction of search query and file view events to record, between 0 (exclusive) and 1. Defaults to recording every event.
+	DataAccessSampleRate float64 `json:"dataAccessSampleRate,omitempty"`
+	// FileViews description: Record a security event for every file viewed or downloaded through the blob and raw endpoints, with the actor, the repository, the revision and the path. Events are stored according to log.securityEventLog.location.
+	FileViews bool `json:"fileViews,omitempty"`
 	// GitserverAccess description: Capture gitserver access logs as part of the audit log.
 	GitserverAccess bool `json:"gitserverAccess"`
 	// GraphQL description: Capture GraphQL requests and responses as part of the audit log.
 	GraphQL bool `json:"graphQL"`
 	// InternalTraffic description: Capture security events performed by the internal traffic (adds significant noise).
 	InternalTraffic bool `json:"internalTraffic"`
+	// RedactionPatterns description: Regular expressions matched against the queries and paths of search query and file view events. Matches are replaced with [REDACTED] before the event is recorded.
+	RedactionPatterns []string `json:"redactionPatterns,omitempty"`
+	// SearchQueries description: Record a security event for every search query executed, with the actor, the query and its repository and file filters. Events are stored according to log.securityEventLog.location.
+	SearchQueries bool `json:"searchQueries,omitempty"`
 	// SeverityLevel description: DEPRECATED: No effect, audit logs are always set to SRC_LOG_LEVEL
 	SeverityLevel string `json:"severityLevel,omitempty"`
+	// Sinks description: External destinations that security events are exported to, in addition to the service logs and the database. Events are queued in the database and delivered at least once by the worker service.
+	Sinks []*AuditLogSink `json:"sinks,omitempty"`
+}
+
+// AuditLogSink description: An external destination for audit log entries
+type AuditLogSink struct {
+	Syslog *SyslogAuditLogSink
+	File   *FileAuditLogSink
+	S3     *S3AuditLogSink
+}
+
+func (v AuditLogSink) MarshalJSON() ([]byte, error) {
+	if v.Syslog != nil {
+		return json.Marshal(v.Syslog)
+	}
+	if v.File != nil {
+		return json.Marshal(v.File)
+	}
+	if v.S3 != nil {
+		return json.Marshal(v.S3)
+	}
+	return nil, errors.New("tagged union type must have exactly 1 non-nil field value")
+}
+func (v *AuditLogSink) UnmarshalJSON(data []byte) error {
+	var d struct {
+		DiscriminantProperty string `json:"type"`
+	}
+	if err := json.Unmarshal(data, &d); err != nil {
+		return err
+	}
+	switch d.DiscriminantProperty {
+	case "file":
+		return json.Unmarshal(data, &v.File)
+	case "s3":
+		return json.Unmarshal(data, &v.S3)
+	case "syslog":
+		return json.Unmarshal(data, &v.Syslog)
+	}
+	return fmt.Errorf("tagged union type must have a %q property whose value is one of %s", "type", []string{"syslog", "file", "s3"})
 }
 
 // AuthAccessRequest description: The config options for access requests
@@ -515,8 +562,10 @@ type BuiltinAuthProvider struct {
 	// AllowSignup description: Allows new visitors to sign up for accounts. The sign-up page will be enabled and accessible to all visitors.
 	//
 	// SECURITY: If the site has no users (i.e., during initial setup), it will always allow the first user to sign up and become site admin **without any approval** (first user to sign up becomes the admin).
-	AllowSignup bool   `json:"allowSignup,omitempty"`
-	Type        string `json:"type"`
+	AllowSignup bool `json:"allowSignup,omitempty"`
+	// RequireSecondFactorForSiteAdmins description: Requires site admins to verify a second factor (an authenticator app code or a security key) when signing in with a username and password. Site admins without a second factor must enroll one before their sign-in completes. Users who enrolled a second factor always have to verify it.
+	RequireSecondFactorForSiteAdmins bool   `json:"requireSecondFactorForSiteAdmins,omitempty"`
+	Type                             string `json:"type"`
 }
 
 // ChangesetTemplate description: A template describing how to create (and update) changesets with the file changes produced by the command steps.
@@ -690,10 +739,11 @@ type Embeddings struct {
 
 // EncryptionKey description: Config for a key
 type EncryptionKey struct {
-	Cloudkms *CloudKMSEncryptionKey
-	Awskms   *AWSKMSEncryptionKey
-	Mounted  *MountedEncryptionKey
-	Noop     *NoOpEncryptionKey
+	Cloudkms     *CloudKMSEncryptionKey
+	Awskms       *AWSKMSEncryptionKey
+	Mounted      *MountedEncryptionKey
+	Vaulttransit *VaultTransitEncryptionKey
+	Noop         *NoOpEncryptionKey
 }
 
 func (v EncryptionKey) MarshalJSON() ([]byte, error) {
@@ -706,6 +756,9 @@ func (v EncryptionKey) MarshalJSON() ([]byte, error) {
 	if v.Mounted != nil {
 		return json.Marshal(v.Mounted)
 	}
+	if v.Vaulttransit != nil {
+		return json.Marshal(v.Vaulttransit)
+	}
 	if v.Noop != nil {
 		return json.Marshal(v.Noop)
 	}
@@ -727,8 +780,10 @@ func (v *EncryptionKey) UnmarshalJSON(data []byte) error {
 		return json.Unmarshal(data, &v.Mounted)
 	case "noop":
 		return json.Unmarshal(data, &v.Noop)
+	case "vaulttransit":
+		return json.Unmarshal(data, &v.Vaulttransit)
 	}
-	return fmt.Errorf("tagged union type must have a %q property whose value is one of %s", "type", []string{"cloudkms", "awskms", "mounted", "noop"})
+	return fmt.Errorf("tagged union type must have a %q property whose value is one of %s", "type", []string{"cloudkms", "awskms", "mounted", "vaulttransit", "noop"})
 }
 
 // EncryptionKeys description: Configuration for encryption keys used to encrypt data at rest in the database.
@@ -743,6 +798,7 @@ type EncryptionKeys struct {
 	GitHubAppKey           *EncryptionKey `json:"gitHubAppKey,omitempty"`
 	OutboundWebhookKey     *EncryptionKey `json:"outboundWebhookKey,omitempty"`
 	UserExternalAccountKey *EncryptionKey `json:"userExternalAccountKey,omitempty"`
+	UserSecondFactorKey    *EncryptionKey `json:"userSecondFactorKey,omitempty"`
 	WebhookKey             *EncryptionKey `json:"webhookKey,omitempty"`
 	WebhookLogKey          *EncryptionKey `json:"webhookLogKey,omitempty"`
 }
@@ -990,6 +1046,21 @@ type ExternalIdentity struct {
 	Type           string `json:"type"`
 }
 
+// FileAuditLogSink description: Writes audit log entries as JSON lines to a file on the worker service, which is rotated when it reaches a maximum size.
+type FileAuditLogSink struct {
+	// Compress description: Compress rotated files with gzip.
+	Compress bool `json:"compress,omitempty"`
+	// MaxAgeDays description: The maximum number of days to keep rotated files. 0 keeps rotated files regardless of their age.
+	MaxAgeDays int `json:"maxAgeDays,omitempty"`
+	// MaxBackups description: The maximum number of rotated files to keep. 0 keeps all rotated files.
+	MaxBackups int `json:"maxBackups,omitempty"`
+	// MaxSizeMB description: The size in megabytes at which the file is rotated.
+	MaxSizeMB int `json:"maxSizeMB,omitempty"`
+	// Path description: The path of the file. Rotated files are stored in the same directory.
+	Path string `json:"path"`
+	Type string `json:"type"`
+}
+
 // FileFilters description: Filters that allow you to specify which files in a repository should get embedded.
 type FileFilters struct {
 	// ExcludedFilePathPatterns description: A list of glob patterns that match file paths you want to exclude from embeddings. This is useful to exclude files with low information value (e.g., SVG files, test fixtures, mocks, auto-generated files, etc.).
@@ -1348,6 +1419,8 @@ type Github struct {
 type GitoliteConnection struct {
 	// Exclude description: A list of repositories to never mirror from this Gitolite instance. Supports excluding by exact name ({"name": "foo"}).
 	Exclude []*ExcludedGitoliteRepo `json:"exclude,omitempty"`
+	// EnforcePermissions description: If true, repositories from this code host are private and users can only access them if they have been granted permissions, for example through "permissions.groupMappings" in site configuration.
+	EnforcePermissions bool `json:"enforcePermissions,omitempty"`
 	// Host description: Gitolite host that stores the repositories (e.g., git@gitolite.example.com, ssh://git@gitolite.example.com:2222/).
 	Host string `json:"host"`
 	// Phabricator description: This is DEPRECATED
@@ -1749,7 +1822,9 @@ type OpenIDConnectAuthProvider struct {
 	ConfigID      string  `json:"configID,omitempty"`
 	DisplayName   string  `json:"displayName,omitempty"`
 	DisplayPrefix *string `json:"displayPrefix,omitempty"`
-	Hidden        bool    `json:"hidden,omitempty"`
+	// GroupsClaimName description: Name of the ID token or userinfo claim that holds the groups of the user, used by the "permissions.groupMappings" setting.
+	GroupsClaimName string `json:"groupsClaimName,omitempty"`
+	Hidden          bool   `json:"hidden,omitempty"`
 	// Issuer description: The URL of the OpenID Connect issuer.
 	//
 	// For Google Apps: https://accounts.google.com
@@ -1789,6 +1864,8 @@ type OrganizationInvitations struct {
 type OtherExternalServiceConnection struct {
 	// Exclude description: A list of repositories to never mirror by name after applying repositoryPathPattern. Supports excluding by exact name ({"name": "myrepo"}) or regular expression ({"pattern": ".*secret.*"}).
 	Exclude []*ExcludedOtherRepo `json:"exclude,omitempty"`
+	// EnforcePermissions description: If true, repositories from this code host are private and users can only access them if they have been granted permissions, for example through "permissions.groupMappings" in site configuration.
+	EnforcePermissions bool `json:"enforcePermissions,omitempty"`
 	// MakeReposPublicOnDotCom description: Whether or not these repositories should be marked as public on Sourcegraph.com. Defaults to false.
 	MakeReposPublicOnDotCom bool     `json:"makeReposPublicOnDotCom,omitempty"`
 	Repos                   []string `json:"repos"`
@@ -1900,6 +1977,14 @@ type PerforceRateLimit struct {
 	RequestsPerHour float64 `json:"requestsPerHour"`
 }
 
+// PermissionsGroupMapping description: Grants users access to repositories based on the groups asserted by the OpenID Connect or SAML authentication provider they signed in with. Each mapping grants the members of a group read access to the repositories whose names match any of the patterns. Only repositories that are restricted, such as those of code hosts with "enforcePermissions" enabled, are affected.
+type PermissionsGroupMapping struct {
+	// Group description: The name of the group, as asserted in the groups claim (OpenID Connect) or attribute (SAML).
+	Group string `json:"group"`
+	// Repos description: Regular expressions matched against repository names. Members of the group can read every repository matching any of the patterns.
+	Repos []string `json:"repos"`
+}
+
 // PermissionsUserMapping description: Settings for Sourcegraph explicit permissions, which allow the site admin to explicitly manage repository permissions via the GraphQL API. This will mark repositories as restricted by default.
 type PermissionsUserMapping struct {
 	// BindID description: The type of identifier to identify a user. The default is "email", which uses the email address to identify a user. Use "username" to identify a user by their username. Changing this setting will erase any permissions created for users that do not yet exist.
@@ -2060,6 +2145,23 @@ type RustRateLimit struct {
 	RequestsPerHour float64 `json:"requestsPerHour"`
 }
 
+// S3AuditLogSink description: Uploads batches of audit log entries as gzip-compressed JSON lines objects to an S3-compatible object storage bucket.
+type S3AuditLogSink struct {
+	// Bucket description: The name of the bucket.
+	Bucket string `json:"bucket"`
+	// CredentialsFile description: The path to an AWS shared credentials file. If empty, the default AWS credential chain of the worker service is used.
+	CredentialsFile string `json:"credentialsFile,omitempty"`
+	// Endpoint description: The endpoint of an S3-compatible object storage service, e.g. MinIO. Defaults to AWS S3.
+	Endpoint string `json:"endpoint,omitempty"`
+	// Prefix description: The prefix of the object keys. Objects are stored under <prefix>YYYY/MM/DD/.
+	Prefix string `json:"prefix,omitempty"`
+	// Region description: The region of the bucket. Defaults to the region of the AWS configuration of the worker service.
+	Region string `json:"region,omitempty"`
+	Type   string `json:"type"`
+	// UsePathStyle description: Use path-style instead of virtual-hosted-style URLs, as required by most S3-compatible object storage services.
+	UsePathStyle bool `json:"usePathStyle,omitempty"`
+}
+
 // SAMLAuthProvider description: Configures the SAML authentication provider for SSO.
 //
 // Note: if you are using IdP-initiated login, you must have *at most one* SAMLAuthProvider in the `auth.providers` array.
@@ -2173,6 +2275,8 @@ type SearchStep struct {
 type SecurityEventLog struct {
 	// Location description: Where to output the security event log [none, auditlog, database, all] where auditlog is the default logging to stdout with the specified audit log format
 	Location string `json:"location,omitempty"`
+	// RetentionDays description: Number of days to retain security events stored in the database. Older events are deleted periodically.
+	RetentionDays int `json:"retentionDays,omitempty"`
 }
 
 // Sentry description: Configuration for Sentry
@@ -2715,6 +2819,8 @@ type SiteConfiguration struct {
 	OwnBestEffortTeamMatching *bool `json:"own.bestEffortTeamMatching,omitempty"`
 	// ParentSourcegraph description: URL to fetch unreachable repository details from. Defaults to "https://sourcegraph.com"
 	ParentSourcegraph *ParentSourcegraph `json:"parentSourcegraph,omitempty"`
+	// PermissionsGroupMappings description: Grants users access to repositories based on the groups asserted by the OpenID Connect or SAML authentication provider they signed in with. Each mapping grants the members of a group read access to the repositories whose names match any of the patterns. Only repositories that are restricted, such as those of code hosts with "enforcePermissions" enabled, are affected.
+	PermissionsGroupMappings []*PermissionsGroupMapping `json:"permissions.groupMappings,omitempty"`
 	// PermissionsSyncJobCleanupInterval description: Time interval (in seconds) of how often cleanup worker should remove old jobs from permissions sync jobs table.
 	PermissionsSyncJobCleanupInterval int `json:"permissions.syncJobCleanupInterval,omitempty"`
 	// PermissionsSyncJobsHistorySize description: The number of last repo/user permission jobs to keep for history.
@@ -2745,6 +2851,8 @@ type SiteConfiguration struct {
 	RepoPurgeWorker *RepoPurgeWorker `json:"repoPurgeWorker,omitempty"`
 	// ScimAuthToken description: The SCIM auth token is used to authenticate SCIM requests. If not set, SCIM is disabled.
 	ScimAuthToken string `json:"scim.authToken,omitempty"`
+	// ScimGroupMapping description: The Sourcegraph entity that SCIM groups are provisioned as. Group members are added to and removed from the organization or team.
+	ScimGroupMapping string `json:"scim.groupMapping,omitempty"`
 	// ScimIdentityProvider description: Identity provider used for SCIM support.  "STANDARD" should be used unless a more specific value is available
 	ScimIdentityProvider string `json:"scim.identityProvider,omitempty"`
 	// SearchIndexSymbolsEnabled description: Whether indexed symbol search is enabled. This is contingent on the indexed search configuration, and is true by default for instances with indexed search enabled. Enabling this will cause every repository to re-index, which is a time consuming (several hours) operation. Additionally, it requires more storage and ram to accommodate the added symbols information in the search index.
@@ -2900,6 +3008,7 @@ func (v *SiteConfiguration) UnmarshalJSON(data []byte) error {
 	delete(m, "own.background.repoIndexRateLimit")
 	delete(m, "own.bestEffortTeamMatching")
 	delete(m, "parentSourcegraph")
+	delete(m, "permissions.groupMappings")
 	delete(m, "permissions.syncJobCleanupInterval")
 	delete(m, "permissions.syncJobsHistorySize")
 	delete(m, "permissions.syncOldestRepos")
@@ -2915,6 +3024,7 @@ func (v *SiteConfiguration) UnmarshalJSON(data []byte) error {
 	delete(m, "repoListUpdateInterval")
 	delete(m, "repoPurgeWorker")
 	delete(m, "scim.authToken")
+	delete(m, "scim.groupMapping")
 	delete(m, "scim.identityProvider")
 	delete(m, "search.index.symbols.enabled")
 	delete(m, "search.largeFiles")
@@ -2999,6 +3109,23 @@ type SyntaxHighlightingLanguagePatterns struct {
 	Pattern string `json:"pattern"`
 }
 
+// SyslogAuditLogSink description: Sends audit log entries as RFC 5424 syslog messages over TCP or TLS, using octet-counting framing.
+type SyslogAuditLogSink struct {
+	// Address description: The host and port of the syslog server.
+	Address string `json:"address"`
+	// AppName description: The APP-NAME field of the messages.
+	AppName string `json:"appName,omitempty"`
+	// CaCertificate description: A PEM-encoded certificate of the certificate authority that signed the certificate of the syslog server. If empty, the system certificate pool is used.
+	CaCertificate string `json:"caCertificate,omitempty"`
+	// Facility description: The syslog facility of the messages. Defaults to 13 (log audit).
+	Facility int `json:"facility,omitempty"`
+	// Hostname description: The HOSTNAME field of the messages. Defaults to the hostname of the worker service.
+	Hostname string `json:"hostname,omitempty"`
+	// Tls description: Connect to the syslog server with TLS (RFC 5425).
+	Tls  bool   `json:"tls,omitempty"`
+	Type string `json:"type"`
+}
+
 // TlsExternal description: Global TLS/SSL settings for Sourcegraph to use when communicating with code hosts.
 type TlsExternal struct {
 	// Certificates description: TLS certificates to accept. This is only necessary if you are using self-signed certificates or an internal CA. Can be an internal CA certificate or a self-signed certificate. To get the certificate of a webserver run `openssl s_client -connect HOST:443 -showcerts < /dev/null 2> /dev/null | openssl x509 -outform PEM`. To escape the value into a JSON string, you may want to use a tool like https://json-escape-text.now.sh. NOTE: System Certificate Authorities are automatically included.
@@ -3031,6 +3158,31 @@ type UsernameIdentity struct {
 	Type string `json:"type"`
 }
 
+// VaultTransitEncryptionKey description: HashiCorp Vault Transit Encryption Key, used to encrypt data with a key of the Vault transit secrets engine
+type VaultTransitEncryptionKey struct {
+	// Address description: The URL of the Vault server.
+	Address string `json:"address"`
+	// AppRoleMountPath description: The path the AppRole auth method is mounted at.
+	AppRoleMountPath string `json:"appRoleMountPath,omitempty"`
+	// Keyname description: The name of the key in the transit secrets engine.
+	Keyname string `json:"keyname"`
+	// MountPath description: The path the transit secrets engine is mounted at.
+	MountPath string `json:"mountPath,omitempty"`
+	// Namespace description: The Vault Enterprise namespace of the transit secrets engine.
+	Namespace string `json:"namespace,omitempty"`
+	// RoleId description: The role ID used to authenticate with the AppRole auth method.
+	RoleId string `json:"roleId,omitempty"`
+	// SecretIdEnvVarName description: The name of the environment variable containing the secret ID used to authenticate with the AppRole auth method.
+	SecretIdEnvVarName string `json:"secretIdEnvVarName,omitempty"`
+	// SecretIdFile description: The path to a file containing the secret ID used to authenticate with the AppRole auth method.
+	SecretIdFile string `json:"secretIdFile,omitempty"`
+	// TokenEnvVarName description: The name of the environment variable containing the Vault token used to authenticate. Use either token or AppRole authentication.
+	TokenEnvVarName string `json:"tokenEnvVarName,omitempty"`
+	// TokenFile description: The path to a file containing the Vault token used to authenticate, e.g. the sink file of a Vault Agent. The file is read again when the token is rejected. Use either token or AppRole authentication.
+	TokenFile string `json:"tokenFile,omitempty"`
+	Type      string `json:"type"`
+}
+
 // VideoStep description: Video step
 type VideoStep struct {
 	Type  any    `json:"type"`